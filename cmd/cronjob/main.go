@@ -5,8 +5,10 @@ import (
 	"kelarin/internal/config"
 	"kelarin/internal/queue"
 	"kelarin/internal/types"
+	"kelarin/internal/utils"
 	awsUtil "kelarin/internal/utils/aws"
 	dbUtil "kelarin/internal/utils/dbutil"
+	emailUtil "kelarin/internal/utils/email_util"
 	firebaseUtil "kelarin/internal/utils/firebase_util"
 	ws "kelarin/internal/utils/websocket"
 	"kelarin/pkg/cron"
@@ -59,12 +61,16 @@ func main() {
 	firebaseApp := firebaseUtil.NewApp(cfg)
 	firebaseMessagingClient := firebaseUtil.NewMessagingClient(firebaseApp)
 
+	emailSender := emailUtil.NewSender(cfg)
+
+	midtransSnapClient := utils.NewMidtransSnapClient(cfg.Midtrans.ServerKey, cfg.Midtrans.Env(), cfg.Midtrans.NotificationURL)
+
 	wsUpgrader := ws.NewWsUpgrader(cfg)
-	wsHub := ws.NewWsHub()
+	wsHub := ws.NewWsHub(redis)
 
 	mainDBTx := dbUtil.NewSqlxTx(db)
 
-	cronApp := newCronjob(db, mainDBTx, es, cfg, redis, queueClient, s3Client, s3Uploader, s3PresignClient, firebaseMessagingClient, wsUpgrader, wsHub)
+	cronApp := newCronjob(db, mainDBTx, es, cfg, redis, queueClient, s3Client, s3Uploader, s3PresignClient, firebaseMessagingClient, emailSender, midtransSnapClient, wsUpgrader, wsHub)
 
 	ctx := context.Background()
 
@@ -80,6 +86,16 @@ func main() {
 			if err != nil {
 				log.Fatal().Err(err).Send()
 			}
+		case types.CronjobLiftExpiredSuspension:
+			err = cron.RegisterJob(ctx, job, cronApp.UserService.TaskLiftExpiredSuspensions)
+			if err != nil {
+				log.Fatal().Err(err).Send()
+			}
+		case types.CronjobSweepOutboxEvents:
+			err = cron.RegisterJob(ctx, job, cronApp.OutboxService.TaskSweepPending)
+			if err != nil {
+				log.Fatal().Err(err).Send()
+			}
 		default:
 			log.Fatal().Msgf("Unknown job name: %s", job.Name)
 		}
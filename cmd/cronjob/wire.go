@@ -7,8 +7,9 @@ import (
 	"kelarin/internal/config"
 	"kelarin/internal/provider"
 	"kelarin/internal/queue/task"
-	"kelarin/internal/types"
 	dbUtil "kelarin/internal/utils/dbutil"
+	emailUtil "kelarin/internal/utils/email_util"
+	ws "kelarin/internal/utils/websocket"
 
 	"firebase.google.com/go/messaging"
 	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
@@ -18,6 +19,7 @@ import (
 	"github.com/gorilla/websocket"
 	"github.com/hibiken/asynq"
 	"github.com/jmoiron/sqlx"
+	"github.com/midtrans/midtrans-go/snap"
 	"github.com/redis/go-redis/v9"
 )
 
@@ -32,11 +34,15 @@ func newCronjob(
 	s3UploadManager *manager.Uploader,
 	s3PresignClient *s3.PresignClient,
 	firebaseMessagingClient *messaging.Client,
+	emailSender emailUtil.Sender,
+	midtransSnapClient *snap.Client,
 	wsUpgrader *websocket.Upgrader,
-	wsHub *types.WsHub,
+	wsHub ws.Hub,
 ) *provider.Cronjob {
 	wire.Build(
 		task.NewTempFile,
+		task.NewOutbox,
+		task.NewNotification,
 		provider.TaskRepositorySet,
 		provider.TaskServiceSet,
 		provider.NewCronjob,
@@ -14,19 +14,21 @@ import (
 	"github.com/gorilla/websocket"
 	"github.com/hibiken/asynq"
 	"github.com/jmoiron/sqlx"
+	"github.com/midtrans/midtrans-go/snap"
 	"github.com/redis/go-redis/v9"
 	"kelarin/internal/config"
 	"kelarin/internal/provider"
 	"kelarin/internal/queue/task"
 	"kelarin/internal/repository"
 	"kelarin/internal/service"
-	"kelarin/internal/types"
 	"kelarin/internal/utils/dbutil"
+	"kelarin/internal/utils/email_util"
+	websocket2 "kelarin/internal/utils/websocket"
 )
 
 // Injectors from wire.go:
 
-func newCronjob(db *sqlx.DB, mainDBTx dbUtil.SqlxTx, esDB *elasticsearch.TypedClient, config2 *config.Config, redis2 *redis.Client, queueClient *asynq.Client, s3Client *s3.Client, s3UploadManager *manager.Uploader, s3PresignClient *s3.PresignClient, firebaseMessagingClient *messaging.Client, wsUpgrader *websocket.Upgrader, wsHub *types.WsHub) *provider.Cronjob {
+func newCronjob(db *sqlx.DB, mainDBTx dbUtil.SqlxTx, esDB *elasticsearch.TypedClient, config2 *config.Config, redis2 *redis.Client, queueClient *asynq.Client, s3Client *s3.Client, s3UploadManager *manager.Uploader, s3PresignClient *s3.PresignClient, firebaseMessagingClient *messaging.Client, emailSender emailUtil.Sender, midtransSnapClient *snap.Client, wsUpgrader *websocket.Upgrader, wsHub websocket2.Hub) *provider.Cronjob {
 	offer := repository.NewOffer(db)
 	userAddress := repository.NewUserAddress(db)
 	repositoryService := repository.NewService(db)
@@ -36,22 +38,45 @@ func newCronjob(db *sqlx.DB, mainDBTx dbUtil.SqlxTx, esDB *elasticsearch.TypedCl
 	serviceProvider := repository.NewServiceProvider(db)
 	offerNegotiation := repository.NewOfferNegotiation(db)
 	serviceProviderNotification := repository.NewServiceProviderNotification(db)
-	fcmToken := repository.NewFCMToken(redis2)
-	notification := service.NewNotification(db, firebaseMessagingClient, fcmToken)
 	user := repository.NewUser(db)
 	consumerNotification := repository.NewConsumerNotification(db)
 	chatRoom := repository.NewChatRoom(db)
 	chatRoomUser := repository.NewChatRoomUser(db)
 	chatMessage := repository.NewChatMessage(db)
+	userPresence := repository.NewUserPresence(redis2)
 	order := repository.NewOrder(db)
 	util := service.NewUtil()
-	chat := service.NewChat(mainDBTx, repositoryService, user, chatRoom, chatRoomUser, chatMessage, wsHub, offer, serviceProvider, serviceFile, order, util)
+	chat := service.NewChat(mainDBTx, repositoryService, user, chatRoom, chatRoomUser, chatMessage, userPresence, wsHub, offer, serviceProvider, serviceFile, order, util)
 	orderOfferSnapshot := repository.NewOrderOfferSnapshot(db)
 	payment := repository.NewPayment(db)
 	paymentMethod := repository.NewPaymentMethod(db)
+	outboxEvent := repository.NewOutboxEvent(db)
+	outbox := task.NewOutbox(queueClient)
+	fcmToken := repository.NewFCMToken(redis2)
+	serviceProviderArea := repository.NewServiceProviderArea(db)
+	notificationPreference := repository.NewNotificationPreference(db)
+	notification := task.NewNotification(queueClient)
+	serviceNotification := service.NewNotification(config2, db, firebaseMessagingClient, emailSender, user, fcmToken, serviceProvider, serviceProviderArea, consumerNotification, serviceProviderNotification, notificationPreference, notification, wsHub)
+	serviceIndex := repository.NewServiceIndex(esDB)
+	serviceCategory := repository.NewServiceCategory(db)
+	serviceOutbox := service.NewOutbox(config2, mainDBTx, outboxEvent, outbox, serviceNotification, serviceIndex, repositoryService, serviceCategory, serviceProvider, serviceProviderArea)
 	serviceFeedback := repository.NewServiceFeedback(db)
-	serviceOrder := service.NewOrder(mainDBTx, user, order, orderOfferSnapshot, serviceFile, util, offer, payment, paymentMethod, config2, serviceProvider, consumerNotification, serviceProviderNotification, fcmToken, notification, repositoryService, serviceFeedback)
-	serviceOffer := service.NewOffer(mainDBTx, offer, userAddress, repositoryService, serviceFile, serviceProvider, offerNegotiation, serviceProviderNotification, fcmToken, notification, user, consumerNotification, chat, serviceOrder, util)
-	cronjob := provider.NewCronjob(db, redis2, queueClient, serviceOffer, serviceOrder)
+	serviceProviderCreditLedger := repository.NewServiceProviderCreditLedger(db)
+	serviceProviderBankAccount := repository.NewServiceProviderBankAccount(db)
+	payout := repository.NewPayout(db)
+	serviceProviderCredit := service.NewServiceProviderCredit(mainDBTx, serviceProvider, serviceProviderCreditLedger, serviceProviderBankAccount, payout)
+	refund := repository.NewRefund(db)
+	midtrans := service.NewMidtrans(midtransSnapClient)
+	serviceRefund := service.NewRefund(mainDBTx, refund, order, midtrans, consumerNotification, serviceOutbox)
+	orderSession := repository.NewOrderSession(db)
+	serviceOrder := service.NewOrder(mainDBTx, user, order, orderOfferSnapshot, serviceFile, util, offer, payment, paymentMethod, config2, serviceProvider, consumerNotification, serviceProviderNotification, serviceOutbox, repositoryService, serviceFeedback, serviceProviderCredit, refund, serviceRefund, orderSession)
+	serviceProviderAvailability := repository.NewServiceProviderAvailability(db)
+	serviceServiceProviderAvailability := service.NewServiceProviderAvailability(mainDBTx, serviceProviderAvailability, serviceProvider, repositoryService, util)
+	serviceOffer := service.NewOffer(mainDBTx, offer, userAddress, repositoryService, serviceFile, serviceProvider, offerNegotiation, serviceProviderNotification, user, consumerNotification, chat, serviceOrder, util, serviceServiceProviderAvailability, serviceOutbox)
+	userModerationLog := repository.NewUserModerationLog(db)
+	session := repository.NewSession(redis2)
+	userBlocklist := repository.NewUserBlocklist(redis2)
+	serviceUser := service.NewUser(mainDBTx, user, userModerationLog, session, userBlocklist, serviceNotification)
+	cronjob := provider.NewCronjob(db, redis2, queueClient, serviceOffer, serviceOrder, serviceUser, serviceOutbox)
 	return cronjob
 }
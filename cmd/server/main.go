@@ -5,10 +5,12 @@ import (
 	"kelarin/internal/config"
 	"kelarin/internal/middleware"
 	"kelarin/internal/queue"
+	"kelarin/internal/repository"
 	"kelarin/internal/routes"
 	"kelarin/internal/utils"
 	awsUtil "kelarin/internal/utils/aws"
 	dbUtil "kelarin/internal/utils/dbutil"
+	emailUtil "kelarin/internal/utils/email_util"
 	fileSystemUtil "kelarin/internal/utils/file_system"
 	firebaseUtil "kelarin/internal/utils/firebase_util"
 	ws "kelarin/internal/utils/websocket"
@@ -70,6 +72,10 @@ func main() {
 		log.Fatal().Stack().Msg("elasticsearch is not available")
 	}
 
+	if err := repository.NewServiceIndex(es).PutMapping(context.Background()); err != nil {
+		log.Fatal().Stack().Err(err).Msg("failed to put services index mapping")
+	}
+
 	queueClient, err := queue.NewAsynq(&cfg.Redis)
 	if err != nil {
 		log.Fatal().Err(errors.New(err)).Msg("Failed to connect to queue")
@@ -85,13 +91,15 @@ func main() {
 	firebaseApp := firebaseUtil.NewApp(cfg)
 	firebaseMessagingClient := firebaseUtil.NewMessagingClient(firebaseApp)
 
+	emailSender := emailUtil.NewSender(cfg)
+
 	midtransSnapClient := utils.NewMidtransSnapClient(cfg.Midtrans.ServerKey, cfg.Midtrans.Env(), cfg.Midtrans.NotificationURL)
 
 	wsUpgrader := ws.NewWsUpgrader(cfg)
-	wsHub := ws.NewWsHub()
+	wsHub := ws.NewWsHub(redis)
 
 	mainDBTx := dbUtil.NewSqlxTx(db)
-	server, err := newServer(db, es, cfg, redis, s3Uploader, queueClient, s3Client, s3PresignClient, openCageClient, firebaseMessagingClient, midtransSnapClient, wsUpgrader, wsHub, mainDBTx)
+	server, err := newServer(db, es, cfg, redis, s3Uploader, queueClient, s3Client, s3PresignClient, openCageClient, firebaseMessagingClient, emailSender, midtransSnapClient, wsUpgrader, wsHub, mainDBTx)
 	if err != nil {
 		log.Fatal().Err(errors.New(err)).Send()
 	}
@@ -135,13 +143,18 @@ func main() {
 	paymentMethodRoutes := routes.NewPaymentMethod(g, server.PaymentMethodHandler)
 	reportRoutes := routes.NewReport(g, server.ReportHandler)
 	chatRoutes := routes.NewChat(g, server.ChatHandler)
+	serviceProviderCreditRoutes := routes.NewServiceProviderCredit(g, server.ServiceProviderCreditHandler)
+	refundRoutes := routes.NewRefund(g, server.RefundHandler)
+	disputeRoutes := routes.NewDispute(g, server.DisputeHandler)
+	serviceProviderAvailabilityRoutes := routes.NewServiceProviderAvailability(g, server.ServiceProviderAvailabilityHandler)
+	adminRoleRoutes := routes.NewAdminRole(g, server.AdminRoleHandler)
 
 	// End init routes region
 
 	// Register routes
 
 	authRoutes.Register(authMiddleware)
-	userRoutes.Register()
+	userRoutes.Register(authMiddleware)
 	fileRoutes.Register(authMiddleware)
 	serviceProviderRoutes.Register(authMiddleware)
 	serviceRoutes.Register(authMiddleware)
@@ -157,13 +170,18 @@ func main() {
 	paymentMethodRoutes.Register()
 	reportRoutes.Register(authMiddleware)
 	chatRoutes.Register(authMiddleware)
+	serviceProviderCreditRoutes.Register(authMiddleware)
+	refundRoutes.Register(authMiddleware)
+	disputeRoutes.Register(authMiddleware)
+	serviceProviderAvailabilityRoutes.Register(authMiddleware)
+	adminRoleRoutes.Register(authMiddleware)
 
 	// End routes registration
 
-	startServer(g, db, cfg)
+	startServer(g, db, wsHub, cfg)
 }
 
-func startServer(g *gin.Engine, db *sqlx.DB, cfg *config.Config) {
+func startServer(g *gin.Engine, db *sqlx.DB, wsHub ws.Hub, cfg *config.Config) {
 	srv := &http.Server{
 		Addr:    cfg.Address(),
 		Handler: g,
@@ -187,6 +205,10 @@ func startServer(g *gin.Engine, db *sqlx.DB, cfg *config.Config) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
+	if err := wsHub.Close(); err != nil {
+		log.Error().Err(err).Msg("Failed to close websocket hub")
+	}
+
 	log.Info().Msg("Closing database connection...")
 	err := dbUtil.ClosePostgresConnection(db)
 	if err != nil {
@@ -8,8 +8,9 @@ import (
 	"kelarin/internal/middleware"
 	"kelarin/internal/provider"
 	"kelarin/internal/queue/task"
-	"kelarin/internal/types"
 	dbUtil "kelarin/internal/utils/dbutil"
+	emailUtil "kelarin/internal/utils/email_util"
+	ws "kelarin/internal/utils/websocket"
 
 	"firebase.google.com/go/messaging"
 	"github.com/alexliesenfeld/opencage"
@@ -24,10 +25,12 @@ import (
 	"github.com/redis/go-redis/v9"
 )
 
-func newServer(db *sqlx.DB, esDB *elasticsearch.TypedClient, config *config.Config, redis *redis.Client, s3UploadManager *manager.Uploader, queueClient *asynq.Client, s3Client *s3.Client, s3PresignClient *s3.PresignClient, opencageClient *opencage.Client, firebaseMessagingClient *messaging.Client, midtransSnapClient *snap.Client, wsUpgrader *websocket.Upgrader, wsHub *types.WsHub, mainDBTx dbUtil.SqlxTx) (*provider.Server, error) {
+func newServer(db *sqlx.DB, esDB *elasticsearch.TypedClient, config *config.Config, redis *redis.Client, s3UploadManager *manager.Uploader, queueClient *asynq.Client, s3Client *s3.Client, s3PresignClient *s3.PresignClient, opencageClient *opencage.Client, firebaseMessagingClient *messaging.Client, emailSender emailUtil.Sender, midtransSnapClient *snap.Client, wsUpgrader *websocket.Upgrader, wsHub ws.Hub, mainDBTx dbUtil.SqlxTx) (*provider.Server, error) {
 	wire.Build(
 		middleware.NewAuth,
 		task.NewTempFile,
+		task.NewOutbox,
+		task.NewNotification,
 		provider.RepositorySet,
 		provider.ServiceSet,
 		provider.HandlerSet,
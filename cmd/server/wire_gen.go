@@ -24,82 +24,114 @@ import (
 	"kelarin/internal/queue/task"
 	"kelarin/internal/repository"
 	"kelarin/internal/service"
-	"kelarin/internal/types"
 	"kelarin/internal/utils/dbutil"
+	"kelarin/internal/utils/email_util"
+	websocket2 "kelarin/internal/utils/websocket"
 )
 
 // Injectors from wire.go:
 
-func newServer(db *sqlx.DB, esDB *elasticsearch.TypedClient, config2 *config.Config, redis2 *redis.Client, s3UploadManager *manager.Uploader, queueClient *asynq.Client, s3Client *s3.Client, s3PresignClient *s3.PresignClient, opencageClient *opencage.Client, firebaseMessagingClient *messaging.Client, midtransSnapClient *snap.Client, wsUpgrader *websocket.Upgrader, wsHub *types.WsHub, mainDBTx dbUtil.SqlxTx) (*provider.Server, error) {
+func newServer(db *sqlx.DB, esDB *elasticsearch.TypedClient, config2 *config.Config, redis2 *redis.Client, s3UploadManager *manager.Uploader, queueClient *asynq.Client, s3Client *s3.Client, s3PresignClient *s3.PresignClient, opencageClient *opencage.Client, firebaseMessagingClient *messaging.Client, emailSender emailUtil.Sender, midtransSnapClient *snap.Client, wsUpgrader *websocket.Upgrader, wsHub websocket2.Hub, mainDBTx dbUtil.SqlxTx) (*provider.Server, error) {
 	user := repository.NewUser(db)
-	serviceUser := service.NewUser(user)
-	handlerUser := handler.NewUser(serviceUser)
+	userModerationLog := repository.NewUserModerationLog(db)
 	session := repository.NewSession(redis2)
+	userBlocklist := repository.NewUserBlocklist(redis2)
+	fcmToken := repository.NewFCMToken(redis2)
+	serviceProvider := repository.NewServiceProvider(db)
+	serviceProviderArea := repository.NewServiceProviderArea(db)
+	consumerNotification := repository.NewConsumerNotification(db)
+	serviceProviderNotification := repository.NewServiceProviderNotification(db)
+	notificationPreference := repository.NewNotificationPreference(db)
+	notification := task.NewNotification(queueClient)
+	serviceNotification := service.NewNotification(config2, db, firebaseMessagingClient, emailSender, user, fcmToken, serviceProvider, serviceProviderArea, consumerNotification, serviceProviderNotification, notificationPreference, notification, wsHub)
+	serviceUser := service.NewUser(mainDBTx, user, userModerationLog, session, userBlocklist, serviceNotification)
+	adminRole := repository.NewAdminRole(db)
+	adminPermissionCache := repository.NewAdminPermissionCache(redis2)
+	auth := middleware.NewAuth(config2, session, userBlocklist, adminRole, adminPermissionCache)
+	handlerUser := handler.NewUser(serviceUser, auth)
 	pendingRegistration := repository.NewPendingRegistration(redis2)
-	auth := service.NewAuth(config2, mainDBTx, session, user, pendingRegistration)
-	middlewareAuth := middleware.NewAuth(config2, session)
-	handlerAuth := handler.NewAuth(auth, middlewareAuth)
+	authToken := repository.NewAuthToken(redis2)
+	userTOTP := repository.NewUserTOTP(db)
+	twoFactor := service.NewTwoFactor(config2, mainDBTx, user, userTOTP)
+	serviceAuth := service.NewAuth(config2, mainDBTx, session, user, pendingRegistration, authToken, serviceNotification, twoFactor)
+	handlerAuth := handler.NewAuth(serviceAuth, twoFactor, auth)
 	file := repository.NewFile(redis2)
 	tempFile := task.NewTempFile(queueClient)
 	serviceFile := service.NewFile(redis2, config2, file, tempFile, s3PresignClient, s3UploadManager, s3Client)
 	handlerFile := handler.NewFile(serviceFile)
-	serviceProvider := repository.NewServiceProvider(db)
 	province := repository.NewProvince(db)
 	city := repository.NewCity(db)
-	serviceProviderArea := repository.NewServiceProviderArea(db)
 	geocoding := service.NewGeocoding(opencageClient)
 	serviceServiceProvider := service.NewServiceProvider(db, serviceProvider, user, province, city, serviceProviderArea, pendingRegistration, serviceFile, geocoding)
-	handlerServiceProvider := handler.NewServiceProvider(serviceServiceProvider, middlewareAuth)
-	serviceIndex := repository.NewServiceIndex(esDB)
+	handlerServiceProvider := handler.NewServiceProvider(serviceServiceProvider, auth)
 	repositoryService := repository.NewService(db)
 	serviceCategory := repository.NewServiceCategory(db)
 	serviceServiceCategory := repository.NewServiceServiceCategory(db)
-	serviceService := service.NewService(mainDBTx, serviceIndex, serviceProvider, repositoryService, serviceCategory, serviceServiceCategory, serviceProviderArea, serviceFile)
+	outboxEvent := repository.NewOutboxEvent(db)
+	outbox := task.NewOutbox(queueClient)
+	serviceIndex := repository.NewServiceIndex(esDB)
+	serviceOutbox := service.NewOutbox(config2, mainDBTx, outboxEvent, outbox, serviceNotification, serviceIndex, repositoryService, serviceCategory, serviceProvider, serviceProviderArea)
+	serviceService := service.NewService(mainDBTx, serviceProvider, repositoryService, serviceCategory, serviceServiceCategory, serviceFile, serviceOutbox)
 	order := repository.NewOrder(db)
 	serviceFeedback := repository.NewServiceFeedback(db)
-	consumerService := service.NewConsumerService(mainDBTx, serviceIndex, repositoryService, serviceProviderArea, serviceProvider, serviceFile, order, serviceFeedback)
+	userAddress := repository.NewUserAddress(db)
+	consumerService := service.NewConsumerService(mainDBTx, serviceIndex, repositoryService, serviceProviderArea, serviceProvider, serviceFile, order, serviceFeedback, userAddress, serviceOutbox)
 	serviceServiceFeedback := service.NewServiceFeedback(serviceFeedback, repositoryService)
-	handlerService := handler.NewService(serviceService, consumerService, serviceServiceFeedback, middlewareAuth)
+	handlerService := handler.NewService(serviceService, consumerService, serviceServiceFeedback, auth)
 	serviceProvince := service.NewProvince(province)
 	handlerProvince := handler.NewProvince(serviceProvince)
 	serviceCity := service.NewCity(city)
 	handlerCity := handler.NewCity(serviceCity)
 	serviceCategory2 := service.NewServiceCategory(serviceCategory)
 	handlerServiceCategory := handler.NewServiceCategory(serviceCategory2)
-	userAddress := repository.NewUserAddress(db)
 	serviceUserAddress := service.NewUserAddress(userAddress, geocoding)
-	handlerUserAddress := handler.NewUserAddress(serviceUserAddress, middlewareAuth)
+	handlerUserAddress := handler.NewUserAddress(serviceUserAddress, auth)
 	offer := repository.NewOffer(db)
 	offerNegotiation := repository.NewOfferNegotiation(db)
-	serviceProviderNotification := repository.NewServiceProviderNotification(db)
-	fcmToken := repository.NewFCMToken(redis2)
-	notification := service.NewNotification(db, firebaseMessagingClient, fcmToken)
-	consumerNotification := repository.NewConsumerNotification(db)
 	chatRoom := repository.NewChatRoom(db)
 	chatRoomUser := repository.NewChatRoomUser(db)
 	chatMessage := repository.NewChatMessage(db)
+	userPresence := repository.NewUserPresence(redis2)
 	util := service.NewUtil()
-	chat := service.NewChat(mainDBTx, repositoryService, user, chatRoom, chatRoomUser, chatMessage, wsHub, offer, serviceProvider, serviceFile, order, util)
+	chat := service.NewChat(mainDBTx, repositoryService, user, chatRoom, chatRoomUser, chatMessage, userPresence, wsHub, offer, serviceProvider, serviceFile, order, util)
 	orderOfferSnapshot := repository.NewOrderOfferSnapshot(db)
 	payment := repository.NewPayment(db)
 	paymentMethod := repository.NewPaymentMethod(db)
-	serviceOrder := service.NewOrder(mainDBTx, user, order, orderOfferSnapshot, serviceFile, util, offer, payment, paymentMethod, config2, serviceProvider, consumerNotification, serviceProviderNotification, fcmToken, notification, repositoryService, serviceFeedback)
-	serviceOffer := service.NewOffer(mainDBTx, offer, userAddress, repositoryService, serviceFile, serviceProvider, offerNegotiation, serviceProviderNotification, fcmToken, notification, user, consumerNotification, chat, serviceOrder, util)
-	handlerOffer := handler.NewOffer(serviceOffer, middlewareAuth)
-	serviceOfferNegotiation := service.NewOfferNegotiation(mainDBTx, serviceProvider, offerNegotiation, offer, repositoryService, notification, fcmToken, serviceFile, consumerNotification, serviceProviderNotification, user)
-	handlerOfferNegotiation := handler.NewOfferNegotiation(middlewareAuth, serviceOfferNegotiation)
-	serviceConsumerNotification := service.NewConsumerNotification(mainDBTx, user, consumerNotification, util, serviceFile)
-	serviceServiceProviderNotification := service.NewServiceProviderNotification(serviceProvider, serviceProviderNotification, util)
-	handlerNotification := handler.NewNotification(middlewareAuth, notification, serviceConsumerNotification, serviceServiceProviderNotification)
+	serviceProviderCreditLedger := repository.NewServiceProviderCreditLedger(db)
+	serviceProviderBankAccount := repository.NewServiceProviderBankAccount(db)
+	payout := repository.NewPayout(db)
+	serviceProviderCredit := service.NewServiceProviderCredit(mainDBTx, serviceProvider, serviceProviderCreditLedger, serviceProviderBankAccount, payout)
+	refund := repository.NewRefund(db)
 	midtrans := service.NewMidtrans(midtransSnapClient)
-	servicePayment := service.NewPayment(config2, mainDBTx, payment, paymentMethod, order, midtrans, notification, fcmToken, consumerNotification, serviceProviderNotification)
-	handlerPayment := handler.NewPayment(servicePayment, middlewareAuth)
-	handlerOrder := handler.NewOrder(serviceOrder, middlewareAuth)
+	serviceRefund := service.NewRefund(mainDBTx, refund, order, midtrans, consumerNotification, serviceOutbox)
+	orderSession := repository.NewOrderSession(db)
+	serviceOrder := service.NewOrder(mainDBTx, user, order, orderOfferSnapshot, serviceFile, util, offer, payment, paymentMethod, config2, serviceProvider, consumerNotification, serviceProviderNotification, serviceOutbox, repositoryService, serviceFeedback, serviceProviderCredit, refund, serviceRefund, orderSession)
+	serviceProviderAvailability := repository.NewServiceProviderAvailability(db)
+	serviceServiceProviderAvailability := service.NewServiceProviderAvailability(mainDBTx, serviceProviderAvailability, serviceProvider, repositoryService, util)
+	serviceOffer := service.NewOffer(mainDBTx, offer, userAddress, repositoryService, serviceFile, serviceProvider, offerNegotiation, serviceProviderNotification, user, consumerNotification, chat, serviceOrder, util, serviceServiceProviderAvailability, serviceOutbox)
+	handlerOffer := handler.NewOffer(serviceOffer, auth)
+	serviceOfferNegotiation := service.NewOfferNegotiation(mainDBTx, serviceProvider, offerNegotiation, offer, repositoryService, serviceOutbox, serviceFile, consumerNotification, serviceProviderNotification, user)
+	handlerOfferNegotiation := handler.NewOfferNegotiation(auth, serviceOfferNegotiation)
+	serviceConsumerNotification := service.NewConsumerNotification(mainDBTx, user, consumerNotification, util, serviceFile, serviceNotification)
+	serviceServiceProviderNotification := service.NewServiceProviderNotification(serviceProvider, serviceProviderNotification, util, serviceNotification)
+	serviceNotificationPreference := service.NewNotificationPreference(mainDBTx, notificationPreference, user, util)
+	handlerNotification := handler.NewNotification(auth, serviceNotification, serviceConsumerNotification, serviceServiceProviderNotification, serviceNotificationPreference)
+	servicePayment := service.NewPayment(config2, mainDBTx, payment, paymentMethod, order, midtrans, consumerNotification, serviceProviderNotification, refund, serviceRefund, serviceOutbox)
+	handlerPayment := handler.NewPayment(servicePayment, auth)
+	handlerOrder := handler.NewOrder(serviceOrder, auth)
 	servicePaymentMethod := service.NewPaymentMethod(paymentMethod)
 	handlerPaymentMethod := handler.NewPaymentMethod(servicePaymentMethod)
 	report := service.NewReport(serviceProvider, offer, order, util)
-	handlerReport := handler.NewReport(report, middlewareAuth)
-	handlerChat := handler.NewChat(wsUpgrader, chat, wsHub, middlewareAuth)
-	server := provider.NewServer(handlerUser, handlerAuth, handlerFile, handlerServiceProvider, handlerService, handlerProvince, handlerCity, handlerServiceCategory, handlerUserAddress, handlerOffer, handlerOfferNegotiation, handlerNotification, handlerPayment, handlerOrder, handlerPaymentMethod, handlerReport, handlerChat, middlewareAuth)
+	handlerReport := handler.NewReport(report, auth)
+	handlerChat := handler.NewChat(wsUpgrader, chat, wsHub, auth)
+	handlerServiceProviderCredit := handler.NewServiceProviderCredit(serviceProviderCredit, auth)
+	handlerRefund := handler.NewRefund(serviceRefund, auth)
+	dispute := repository.NewDispute(db)
+	serviceDispute := service.NewDispute(mainDBTx, dispute, order, serviceProvider, refund, serviceRefund, serviceProviderCredit, serviceFile, consumerNotification, serviceProviderNotification, serviceOutbox)
+	handlerDispute := handler.NewDispute(serviceDispute, auth)
+	handlerServiceProviderAvailability := handler.NewServiceProviderAvailability(serviceServiceProviderAvailability, auth)
+	serviceAdminRole := service.NewAdminRole(mainDBTx, adminRole, adminPermissionCache, user)
+	handlerAdminRole := handler.NewAdminRole(serviceAdminRole, auth)
+	server := provider.NewServer(handlerUser, handlerAuth, handlerFile, handlerServiceProvider, handlerService, handlerProvince, handlerCity, handlerServiceCategory, handlerUserAddress, handlerOffer, handlerOfferNegotiation, handlerNotification, handlerPayment, handlerOrder, handlerPaymentMethod, handlerReport, handlerChat, handlerServiceProviderCredit, handlerRefund, handlerDispute, handlerServiceProviderAvailability, handlerAdminRole, auth)
 	return server, nil
 }
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"kelarin/internal/config"
+	"kelarin/internal/provider"
+	"kelarin/internal/queue"
+	"kelarin/internal/types"
+	"kelarin/internal/utils"
+	awsUtil "kelarin/internal/utils/aws"
+	dbUtil "kelarin/internal/utils/dbutil"
+	emailUtil "kelarin/internal/utils/email_util"
+	fileSystemUtil "kelarin/internal/utils/file_system"
+	firebaseUtil "kelarin/internal/utils/firebase_util"
+	ws "kelarin/internal/utils/websocket"
+	workerUtil "kelarin/internal/utils/worker_util"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
+	"github.com/hibiken/asynq"
+	"github.com/rs/zerolog/log"
+)
+
+func main() {
+	cfg := config.NewApp("config/config.yaml")
+	config.NewLogger(cfg)
+	if err := fileSystemUtil.InitTempDir(); err != nil {
+		log.Fatal().Stack().Err(err).Send()
+	}
+
+	db, err := dbUtil.NewPostgres(&cfg.DataBase)
+	if err != nil {
+		log.Fatal().Caller().Err(err).Send()
+	}
+
+	redis, err := dbUtil.NewRedisClient(cfg)
+	if err != nil {
+		log.Fatal().Stack().Err(err).Send()
+	}
+
+	es, err := dbUtil.NewElasticsearchClient(cfg.Elasticsearch)
+	if err != nil {
+		log.Fatal().Stack().Caller().Err(err).Send()
+	}
+
+	queueClient, err := queue.NewAsynq(&cfg.Redis)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to connect to queue")
+	}
+
+	awsCfg := awsUtil.NewConfig()
+	s3Client := awsUtil.NewS3ClientFromConfig(awsCfg)
+	s3Uploader := manager.NewUploader(s3Client)
+	s3PresignClient := awsUtil.NewS3PresignClient(s3Client)
+
+	firebaseApp := firebaseUtil.NewApp(cfg)
+	firebaseMessagingClient := firebaseUtil.NewMessagingClient(firebaseApp)
+
+	emailSender := emailUtil.NewSender(cfg)
+
+	midtransSnapClient := utils.NewMidtransSnapClient(cfg.Midtrans.ServerKey, cfg.Midtrans.Env(), cfg.Midtrans.NotificationURL)
+
+	wsUpgrader := ws.NewWsUpgrader(cfg)
+	wsHub := ws.NewWsHub(redis)
+
+	mainDBTx := dbUtil.NewSqlxTx(db)
+
+	workerApp := newWorker(db, mainDBTx, es, cfg, redis, queueClient, s3Client, s3Uploader, s3PresignClient, firebaseMessagingClient, emailSender, midtransSnapClient, wsUpgrader, wsHub)
+
+	mux := asynq.NewServeMux()
+	registerTaskHandlers(mux, workerApp)
+
+	server := queue.NewServer(cfg, workerUtil.NewWorkerLogger())
+
+	// Start runs the processors in background goroutines, so signals are handled here
+	// instead of letting asynq trap them through Run
+	if err := server.Start(mux); err != nil {
+		log.Fatal().Err(err).Msg("Failed to start worker")
+	}
+
+	log.Info().Int("concurrency", cfg.Worker.Concurrency).Msg("worker started")
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+
+	<-quit
+
+	log.Info().Msg("Shutting down worker...")
+
+	// Shutdown waits up to cfg.Worker.ShutdownTimeout for in-flight tasks, unfinished tasks are requeued
+	server.Shutdown()
+
+	err = workerApp.Close()
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to close worker application")
+	}
+
+	log.Info().Msg("Worker shuted down gracefully")
+}
+
+func registerTaskHandlers(mux *asynq.ServeMux, w *provider.Worker) {
+	mux.HandleFunc(types.TaskDeleteTempFile, w.TempFileHandler.DeleteTempFile)
+	mux.HandleFunc(types.TaskProcessOutboxEvent, w.OutboxHandler.ProcessEvent)
+	mux.HandleFunc(types.TaskSendPushNotification, w.NotificationHandler.SendPush)
+	mux.HandleFunc(types.TaskSendEmailNotification, w.NotificationHandler.SendEmail)
+	mux.HandleFunc(types.TaskSendAuthEmail, w.NotificationHandler.SendAuthEmail)
+}
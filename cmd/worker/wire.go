@@ -0,0 +1,52 @@
+//go:build wireinject
+// +build wireinject
+
+package main
+
+import (
+	"kelarin/internal/config"
+	"kelarin/internal/provider"
+	"kelarin/internal/queue/task"
+	dbUtil "kelarin/internal/utils/dbutil"
+	emailUtil "kelarin/internal/utils/email_util"
+	ws "kelarin/internal/utils/websocket"
+
+	"firebase.google.com/go/messaging"
+	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/elastic/go-elasticsearch/v8"
+	"github.com/google/wire"
+	"github.com/gorilla/websocket"
+	"github.com/hibiken/asynq"
+	"github.com/jmoiron/sqlx"
+	"github.com/midtrans/midtrans-go/snap"
+	"github.com/redis/go-redis/v9"
+)
+
+func newWorker(
+	db *sqlx.DB,
+	mainDBTx dbUtil.SqlxTx,
+	esDB *elasticsearch.TypedClient,
+	config *config.Config,
+	redis *redis.Client,
+	queueClient *asynq.Client,
+	s3Client *s3.Client,
+	s3UploadManager *manager.Uploader,
+	s3PresignClient *s3.PresignClient,
+	firebaseMessagingClient *messaging.Client,
+	emailSender emailUtil.Sender,
+	midtransSnapClient *snap.Client,
+	wsUpgrader *websocket.Upgrader,
+	wsHub ws.Hub,
+) *provider.Worker {
+	wire.Build(
+		task.NewOutbox,
+		task.NewNotification,
+		provider.TaskRepositorySet,
+		provider.TaskServiceSet,
+		provider.WorkerHandlerSet,
+		provider.NewWorker,
+	)
+
+	return &provider.Worker{}
+}
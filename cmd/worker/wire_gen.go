@@ -0,0 +1,53 @@
+// Code generated by Wire. DO NOT EDIT.
+
+//go:generate go run -mod=mod github.com/google/wire/cmd/wire
+//go:build !wireinject
+// +build !wireinject
+
+package main
+
+import (
+	"firebase.google.com/go/messaging"
+	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/elastic/go-elasticsearch/v8"
+	"github.com/gorilla/websocket"
+	"github.com/hibiken/asynq"
+	"github.com/jmoiron/sqlx"
+	"github.com/midtrans/midtrans-go/snap"
+	"github.com/redis/go-redis/v9"
+	"kelarin/internal/config"
+	"kelarin/internal/provider"
+	"kelarin/internal/queue/handler"
+	"kelarin/internal/queue/task"
+	"kelarin/internal/repository"
+	"kelarin/internal/service"
+	"kelarin/internal/utils/dbutil"
+	"kelarin/internal/utils/email_util"
+	websocket2 "kelarin/internal/utils/websocket"
+)
+
+// Injectors from wire.go:
+
+func newWorker(db *sqlx.DB, mainDBTx dbUtil.SqlxTx, esDB *elasticsearch.TypedClient, config2 *config.Config, redis2 *redis.Client, queueClient *asynq.Client, s3Client *s3.Client, s3UploadManager *manager.Uploader, s3PresignClient *s3.PresignClient, firebaseMessagingClient *messaging.Client, emailSender emailUtil.Sender, midtransSnapClient *snap.Client, wsUpgrader *websocket.Upgrader, wsHub websocket2.Hub) *provider.Worker {
+	queueTempFile := taskHandler.NewQueueTempFile()
+	outboxEvent := repository.NewOutboxEvent(db)
+	outbox := task.NewOutbox(queueClient)
+	user := repository.NewUser(db)
+	fcmToken := repository.NewFCMToken(redis2)
+	serviceProvider := repository.NewServiceProvider(db)
+	serviceProviderArea := repository.NewServiceProviderArea(db)
+	consumerNotification := repository.NewConsumerNotification(db)
+	serviceProviderNotification := repository.NewServiceProviderNotification(db)
+	notificationPreference := repository.NewNotificationPreference(db)
+	notification := task.NewNotification(queueClient)
+	serviceNotification := service.NewNotification(config2, db, firebaseMessagingClient, emailSender, user, fcmToken, serviceProvider, serviceProviderArea, consumerNotification, serviceProviderNotification, notificationPreference, notification, wsHub)
+	serviceIndex := repository.NewServiceIndex(esDB)
+	repositoryService := repository.NewService(db)
+	serviceCategory := repository.NewServiceCategory(db)
+	serviceOutbox := service.NewOutbox(config2, mainDBTx, outboxEvent, outbox, serviceNotification, serviceIndex, repositoryService, serviceCategory, serviceProvider, serviceProviderArea)
+	queueOutbox := taskHandler.NewQueueOutbox(serviceOutbox)
+	queueNotification := taskHandler.NewQueueNotification(serviceNotification)
+	worker := provider.NewWorker(db, redis2, queueClient, queueTempFile, queueOutbox, queueNotification)
+	return worker
+}
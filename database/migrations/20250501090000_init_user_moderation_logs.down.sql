@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS user_moderation_logs;
+DROP TYPE IF EXISTS user_moderation_action;
\ No newline at end of file
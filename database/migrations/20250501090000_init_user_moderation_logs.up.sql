@@ -0,0 +1,26 @@
+DO $$
+BEGIN
+    CREATE TYPE user_moderation_action AS ENUM (
+        'suspend',
+        'unsuspend',
+        'ban',
+        'unban'
+    );
+    EXCEPTION WHEN duplicate_object THEN 
+        RAISE NOTICE 'user_moderation_action type already exists';
+END $$;
+
+CREATE TABLE IF NOT EXISTS user_moderation_logs (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL,
+    admin_user_id UUID NOT NULL,
+    action user_moderation_action NOT NULL,
+    reason TEXT NOT NULL,
+    suspended_from TIMESTAMPTZ,
+    suspended_to TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id),
+    FOREIGN KEY (admin_user_id) REFERENCES users(id)
+);
+
+CREATE INDEX IF NOT EXISTS user_moderation_logs_user_id_idx ON user_moderation_logs (user_id);
\ No newline at end of file
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS users_suspended_to_idx;
+
+DELETE FROM user_moderation_logs WHERE admin_user_id IS NULL;
+ALTER TABLE user_moderation_logs ALTER COLUMN admin_user_id SET NOT NULL;
\ No newline at end of file
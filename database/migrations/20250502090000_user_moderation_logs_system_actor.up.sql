@@ -0,0 +1,4 @@
+-- automated actions (e.g. lifting an expired suspension) have no admin actor
+ALTER TABLE user_moderation_logs ALTER COLUMN admin_user_id DROP NOT NULL;
+
+CREATE INDEX IF NOT EXISTS users_suspended_to_idx ON users (suspended_to) WHERE is_suspended = TRUE;
\ No newline at end of file
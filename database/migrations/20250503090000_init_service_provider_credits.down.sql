@@ -0,0 +1,8 @@
+ALTER TABLE service_providers DROP CONSTRAINT IF EXISTS service_providers_credit_non_negative;
+
+DROP TABLE IF EXISTS service_provider_credit_ledgers;
+DROP TABLE IF EXISTS payouts;
+DROP TABLE IF EXISTS service_provider_bank_accounts;
+
+DROP TYPE IF EXISTS payout_status;
+DROP TYPE IF EXISTS service_provider_credit_ledger_type;
\ No newline at end of file
@@ -0,0 +1,75 @@
+DO $$
+BEGIN
+    CREATE TYPE service_provider_credit_ledger_type AS ENUM (
+        'order_finished',
+        'payout',
+        'payout_rejected',
+        'adjustment'
+    );
+    EXCEPTION WHEN duplicate_object THEN 
+        RAISE NOTICE 'service_provider_credit_ledger_type type already exists';
+END $$;
+
+DO $$
+BEGIN
+    CREATE TYPE payout_status AS ENUM (
+        'pending',
+        'approved',
+        'rejected',
+        'paid'
+    );
+    EXCEPTION WHEN duplicate_object THEN 
+        RAISE NOTICE 'payout_status type already exists';
+END $$;
+
+CREATE TABLE IF NOT EXISTS service_provider_bank_accounts (
+    id UUID PRIMARY KEY,
+    service_provider_id UUID NOT NULL,
+    bank_name VARCHAR(100) NOT NULL,
+    account_number VARCHAR(50) NOT NULL,
+    account_holder_name VARCHAR(255) NOT NULL,
+    is_deleted BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMPTZ,
+    FOREIGN KEY (service_provider_id) REFERENCES service_providers(id)
+);
+
+CREATE INDEX IF NOT EXISTS service_provider_bank_accounts_service_provider_id_idx ON service_provider_bank_accounts (service_provider_id);
+
+CREATE TABLE IF NOT EXISTS payouts (
+    id UUID PRIMARY KEY,
+    service_provider_id UUID NOT NULL,
+    service_provider_bank_account_id UUID NOT NULL,
+    amount NUMERIC(15, 2) NOT NULL CHECK (amount > 0),
+    status payout_status NOT NULL DEFAULT 'pending',
+    admin_user_id UUID,
+    note TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ,
+    paid_at TIMESTAMPTZ,
+    FOREIGN KEY (service_provider_id) REFERENCES service_providers(id),
+    FOREIGN KEY (service_provider_bank_account_id) REFERENCES service_provider_bank_accounts(id),
+    FOREIGN KEY (admin_user_id) REFERENCES users(id)
+);
+
+CREATE INDEX IF NOT EXISTS payouts_service_provider_id_idx ON payouts (service_provider_id);
+CREATE INDEX IF NOT EXISTS payouts_status_idx ON payouts (status);
+
+CREATE TABLE IF NOT EXISTS service_provider_credit_ledgers (
+    id UUID PRIMARY KEY,
+    service_provider_id UUID NOT NULL,
+    type service_provider_credit_ledger_type NOT NULL,
+    amount NUMERIC(15, 2) NOT NULL,
+    balance NUMERIC(15, 2) NOT NULL CHECK (balance >= 0),
+    order_id UUID,
+    payout_id UUID,
+    note TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (service_provider_id) REFERENCES service_providers(id),
+    FOREIGN KEY (order_id) REFERENCES orders(id),
+    FOREIGN KEY (payout_id) REFERENCES payouts(id)
+);
+
+CREATE INDEX IF NOT EXISTS service_provider_credit_ledgers_service_provider_id_idx ON service_provider_credit_ledgers (service_provider_id);
+
+ALTER TABLE service_providers ADD CONSTRAINT service_providers_credit_non_negative CHECK (credit >= 0);
\ No newline at end of file
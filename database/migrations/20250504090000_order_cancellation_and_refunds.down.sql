@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS refunds;
+DROP TYPE IF EXISTS refund_status;
+
+ALTER TABLE orders
+    DROP COLUMN IF EXISTS canceled_by,
+    DROP COLUMN IF EXISTS cancellation_reason,
+    DROP COLUMN IF EXISTS canceled_at;
+
+-- the values added to order_status are kept since postgres can not drop a value from an enum
\ No newline at end of file
@@ -0,0 +1,34 @@
+ALTER TYPE order_status ADD VALUE IF NOT EXISTS 'expired';
+ALTER TYPE order_status ADD VALUE IF NOT EXISTS 'canceled';
+
+ALTER TABLE orders
+    ADD COLUMN IF NOT EXISTS canceled_by SMALLINT,
+    ADD COLUMN IF NOT EXISTS cancellation_reason TEXT,
+    ADD COLUMN IF NOT EXISTS canceled_at TIMESTAMPTZ;
+
+DO $$
+BEGIN
+    CREATE TYPE refund_status AS ENUM (
+        'pending',
+        'succeeded',
+        'failed'
+    );
+    EXCEPTION WHEN duplicate_object THEN 
+        RAISE NOTICE 'refund_status type already exists';
+END $$;
+
+CREATE TABLE IF NOT EXISTS refunds (
+    id UUID PRIMARY KEY,
+    order_id UUID NOT NULL UNIQUE,
+    payment_id UUID NOT NULL,
+    amount NUMERIC(15, 2) NOT NULL CHECK (amount > 0),
+    reason TEXT NOT NULL,
+    status refund_status NOT NULL DEFAULT 'pending',
+    failure_message TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ,
+    FOREIGN KEY (order_id) REFERENCES orders(id),
+    FOREIGN KEY (payment_id) REFERENCES payments(id)
+);
+
+CREATE INDEX IF NOT EXISTS refunds_status_idx ON refunds (status);
\ No newline at end of file
@@ -0,0 +1,7 @@
+ALTER TABLE service_provider_notifications DROP COLUMN IF EXISTS dispute_id;
+ALTER TABLE consumer_notifications DROP COLUMN IF EXISTS dispute_id;
+
+DROP TABLE IF EXISTS disputes;
+
+DROP TYPE IF EXISTS dispute_outcome;
+DROP TYPE IF EXISTS dispute_status;
\ No newline at end of file
@@ -0,0 +1,54 @@
+DO $$
+BEGIN
+    CREATE TYPE dispute_status AS ENUM (
+        'open',
+        'responded',
+        'resolved'
+    );
+    EXCEPTION WHEN duplicate_object THEN 
+        RAISE NOTICE 'dispute_status type already exists';
+END $$;
+
+DO $$
+BEGIN
+    CREATE TYPE dispute_outcome AS ENUM (
+        'refund',
+        'partial_refund',
+        'dismiss'
+    );
+    EXCEPTION WHEN duplicate_object THEN 
+        RAISE NOTICE 'dispute_outcome type already exists';
+END $$;
+
+CREATE TABLE IF NOT EXISTS disputes (
+    id UUID PRIMARY KEY,
+    order_id UUID NOT NULL UNIQUE,
+    user_id UUID NOT NULL,
+    service_provider_id UUID NOT NULL,
+    reason TEXT NOT NULL,
+    evidence_images VARCHAR(255)[] NOT NULL DEFAULT '{}',
+    status dispute_status NOT NULL DEFAULT 'open',
+    provider_response TEXT,
+    provider_responded_at TIMESTAMPTZ,
+    outcome dispute_outcome,
+    refund_amount NUMERIC(15, 2),
+    admin_user_id UUID,
+    admin_note TEXT,
+    resolved_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ,
+    FOREIGN KEY (order_id) REFERENCES orders(id),
+    FOREIGN KEY (user_id) REFERENCES users(id),
+    FOREIGN KEY (service_provider_id) REFERENCES service_providers(id),
+    FOREIGN KEY (admin_user_id) REFERENCES users(id)
+);
+
+CREATE INDEX IF NOT EXISTS disputes_user_id_idx ON disputes (user_id);
+CREATE INDEX IF NOT EXISTS disputes_service_provider_id_idx ON disputes (service_provider_id);
+CREATE INDEX IF NOT EXISTS disputes_status_idx ON disputes (status);
+
+ALTER TABLE consumer_notifications
+    ADD COLUMN IF NOT EXISTS dispute_id UUID REFERENCES disputes(id);
+
+ALTER TABLE service_provider_notifications
+    ADD COLUMN IF NOT EXISTS dispute_id UUID REFERENCES disputes(id);
\ No newline at end of file
@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS order_sessions;
+DROP TYPE IF EXISTS order_session_status;
+
+ALTER TABLE offers
+    DROP COLUMN IF EXISTS service_schedule;
+
+DROP TYPE IF EXISTS offer_service_schedule;
\ No newline at end of file
@@ -0,0 +1,61 @@
+DO $$
+BEGIN
+    CREATE TYPE offer_service_schedule AS ENUM (
+        'single',
+        'daily',
+        'weekly'
+    );
+    EXCEPTION WHEN duplicate_object THEN 
+        RAISE NOTICE 'offer_service_schedule type already exists';
+END $$;
+
+ALTER TABLE offers
+    ADD COLUMN IF NOT EXISTS service_schedule offer_service_schedule NOT NULL DEFAULT 'single';
+
+DO $$
+BEGIN
+    CREATE TYPE order_session_status AS ENUM (
+        'pending',
+        'ongoing',
+        'finished',
+        'canceled'
+    );
+    EXCEPTION WHEN duplicate_object THEN 
+        RAISE NOTICE 'order_session_status type already exists';
+END $$;
+
+CREATE TABLE IF NOT EXISTS order_sessions (
+    id UUID PRIMARY KEY,
+    order_id UUID NOT NULL,
+    sequence SMALLINT NOT NULL,
+    service_date DATE NOT NULL,
+    service_time TIMETZ NOT NULL,
+    status order_session_status NOT NULL DEFAULT 'pending',
+    finished_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ,
+    UNIQUE (order_id, sequence),
+    FOREIGN KEY (order_id) REFERENCES orders(id)
+);
+
+CREATE INDEX IF NOT EXISTS order_sessions_service_date_status_idx ON order_sessions (service_date, status);
+
+-- every order created before this migration becomes a single session order
+INSERT INTO order_sessions (id, order_id, sequence, service_date, service_time, status, finished_at, created_at, updated_at)
+SELECT
+    gen_random_uuid(),
+    id,
+    1,
+    service_date,
+    service_time,
+    (CASE status
+        WHEN 'pending' THEN 'pending'
+        WHEN 'ongoing' THEN 'ongoing'
+        WHEN 'finished' THEN 'finished'
+        ELSE 'canceled'
+    END)::order_session_status,
+    CASE WHEN status = 'finished' THEN updated_at END,
+    created_at,
+    updated_at
+FROM orders
+WHERE NOT EXISTS (SELECT 1 FROM order_sessions WHERE order_sessions.order_id = orders.id);
\ No newline at end of file
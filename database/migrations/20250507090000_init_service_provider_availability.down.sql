@@ -0,0 +1,5 @@
+ALTER TABLE services
+    DROP COLUMN IF EXISTS max_concurrent_jobs;
+
+DROP TABLE IF EXISTS service_provider_days_off;
+DROP TABLE IF EXISTS service_provider_working_hours;
\ No newline at end of file
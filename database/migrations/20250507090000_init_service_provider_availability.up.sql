@@ -0,0 +1,21 @@
+CREATE TABLE IF NOT EXISTS service_provider_working_hours (
+    service_provider_id UUID NOT NULL,
+    day_of_week SMALLINT NOT NULL CHECK (day_of_week BETWEEN 0 AND 6),
+    start_time TIMETZ NOT NULL,
+    end_time TIMETZ NOT NULL,
+    PRIMARY KEY (service_provider_id, day_of_week),
+    FOREIGN KEY (service_provider_id) REFERENCES service_providers(id)
+);
+
+CREATE TABLE IF NOT EXISTS service_provider_days_off (
+    id UUID PRIMARY KEY,
+    service_provider_id UUID NOT NULL,
+    date DATE NOT NULL,
+    reason TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (service_provider_id, date),
+    FOREIGN KEY (service_provider_id) REFERENCES service_providers(id)
+);
+
+ALTER TABLE services
+    ADD COLUMN IF NOT EXISTS max_concurrent_jobs SMALLINT NOT NULL DEFAULT 1 CHECK (max_concurrent_jobs > 0);
\ No newline at end of file
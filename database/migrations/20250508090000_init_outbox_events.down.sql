@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS outbox_events;
+
+DROP TYPE IF EXISTS outbox_event_status;
+DROP TYPE IF EXISTS outbox_event_type;
\ No newline at end of file
@@ -0,0 +1,33 @@
+DO $$
+BEGIN
+    CREATE TYPE outbox_event_type AS ENUM (
+        'push_notification',
+        'service_index_sync'
+    );
+    EXCEPTION WHEN duplicate_object THEN 
+        RAISE NOTICE 'outbox_event_type type already exists';
+END $$;
+
+DO $$
+BEGIN
+    CREATE TYPE outbox_event_status AS ENUM (
+        'pending',
+        'processed',
+        'dead'
+    );
+    EXCEPTION WHEN duplicate_object THEN 
+        RAISE NOTICE 'outbox_event_status type already exists';
+END $$;
+
+CREATE TABLE IF NOT EXISTS outbox_events (
+    id UUID PRIMARY KEY,
+    type outbox_event_type NOT NULL,
+    payload JSONB NOT NULL,
+    status outbox_event_status NOT NULL DEFAULT 'pending',
+    attempts SMALLINT NOT NULL DEFAULT 0,
+    last_error TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    processed_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS outbox_events_pending_idx ON outbox_events(created_at) WHERE status = 'pending';
\ No newline at end of file
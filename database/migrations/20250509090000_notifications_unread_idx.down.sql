@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS service_provider_notifications_unread_idx;
+DROP INDEX IF EXISTS service_provider_notifications_service_provider_id_id_idx;
+
+DROP INDEX IF EXISTS consumer_notifications_unread_idx;
+DROP INDEX IF EXISTS consumer_notifications_user_id_id_idx;
\ No newline at end of file
@@ -0,0 +1,5 @@
+CREATE INDEX IF NOT EXISTS consumer_notifications_user_id_id_idx ON consumer_notifications(user_id, id DESC);
+CREATE INDEX IF NOT EXISTS consumer_notifications_unread_idx ON consumer_notifications(user_id) WHERE read = FALSE;
+
+CREATE INDEX IF NOT EXISTS service_provider_notifications_service_provider_id_id_idx ON service_provider_notifications(service_provider_id, id DESC);
+CREATE INDEX IF NOT EXISTS service_provider_notifications_unread_idx ON service_provider_notifications(service_provider_id) WHERE read = FALSE;
\ No newline at end of file
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS notification_quiet_hours;
+DROP TABLE IF EXISTS notification_preferences;
+
+DROP TYPE IF EXISTS notification_channel;
\ No newline at end of file
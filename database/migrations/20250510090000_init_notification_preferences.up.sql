@@ -0,0 +1,30 @@
+DO $$
+BEGIN
+    CREATE TYPE notification_channel AS ENUM (
+        'push',
+        'in_app',
+        'email'
+    );
+    EXCEPTION WHEN duplicate_object THEN 
+        RAISE NOTICE 'notification_channel type already exists';
+END $$;
+
+CREATE TABLE IF NOT EXISTS notification_preferences (
+    user_id UUID NOT NULL,
+    type SMALLINT NOT NULL,
+    channel notification_channel NOT NULL,
+    enabled BOOLEAN NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, type, channel),
+    FOREIGN KEY (user_id) REFERENCES users(id)
+);
+
+CREATE TABLE IF NOT EXISTS notification_quiet_hours (
+    user_id UUID PRIMARY KEY,
+    enabled BOOLEAN NOT NULL,
+    start_time TIME NOT NULL,
+    end_time TIME NOT NULL,
+    time_zone TEXT NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id)
+);
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS language VARCHAR(2) NOT NULL DEFAULT 'id';
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE chat_messages DROP COLUMN IF EXISTS delivered_at;
\ No newline at end of file
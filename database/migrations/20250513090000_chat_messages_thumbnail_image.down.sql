@@ -0,0 +1 @@
+ALTER TABLE chat_messages DROP COLUMN IF EXISTS thumbnail_image;
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE chat_messages ADD COLUMN IF NOT EXISTS thumbnail_image TEXT;
\ No newline at end of file
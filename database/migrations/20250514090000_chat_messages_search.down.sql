@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS chat_messages_chat_room_id_id_idx;
+
+DROP INDEX IF EXISTS chat_messages_search_vector_idx;
+
+ALTER TABLE chat_messages DROP COLUMN IF EXISTS search_vector;
\ No newline at end of file
@@ -0,0 +1,7 @@
+-- the simple configuration is used because the messages are written in indonesian and english
+ALTER TABLE chat_messages ADD COLUMN IF NOT EXISTS search_vector TSVECTOR
+    GENERATED ALWAYS AS (to_tsvector('simple', CASE WHEN content_type = 'text' THEN content ELSE '' END)) STORED;
+
+CREATE INDEX IF NOT EXISTS chat_messages_search_vector_idx ON chat_messages USING GIN (search_vector);
+
+CREATE INDEX IF NOT EXISTS chat_messages_chat_room_id_id_idx ON chat_messages (chat_room_id, id);
\ No newline at end of file
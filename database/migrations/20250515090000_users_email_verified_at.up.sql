@@ -0,0 +1,4 @@
+-- accounts created before local registration were verified by google or seeded
+ALTER TABLE users ADD COLUMN IF NOT EXISTS email_verified_at TIMESTAMPTZ;
+
+UPDATE users SET email_verified_at = created_at WHERE email_verified_at IS NULL;
\ No newline at end of file
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS user_totp_recovery_codes;
+DROP TABLE IF EXISTS user_totps;
\ No newline at end of file
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS user_admin_roles;
+DROP TABLE IF EXISTS admin_role_permissions;
+DROP TABLE IF EXISTS admin_roles;
\ No newline at end of file
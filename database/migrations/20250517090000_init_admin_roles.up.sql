@@ -0,0 +1,43 @@
+-- the permissions are defined by the api, a role only stores the names it grants
+CREATE TABLE IF NOT EXISTS admin_roles (
+    id UUID PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    description VARCHAR(255) NOT NULL DEFAULT '',
+    is_system BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ,
+    UNIQUE (name)
+);
+
+CREATE TABLE IF NOT EXISTS admin_role_permissions (
+    role_id UUID NOT NULL,
+    permission VARCHAR(100) NOT NULL,
+    PRIMARY KEY (role_id, permission),
+    FOREIGN KEY (role_id) REFERENCES admin_roles(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS user_admin_roles (
+    user_id UUID NOT NULL,
+    role_id UUID NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, role_id),
+    FOREIGN KEY (user_id) REFERENCES users(id),
+    FOREIGN KEY (role_id) REFERENCES admin_roles(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS user_admin_roles_role_id_idx ON user_admin_roles (role_id);
+
+-- super admin grants every permission with the wildcard, the admins (role 1) created before the roles keep their access
+INSERT INTO admin_roles (id, name, description, is_system)
+VALUES ('0196d4a0-0000-7000-8000-000000000001', 'super-admin', 'Every permission, including the management of the roles', TRUE)
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO admin_role_permissions (role_id, permission)
+VALUES ('0196d4a0-0000-7000-8000-000000000001', '*')
+ON CONFLICT DO NOTHING;
+
+INSERT INTO user_admin_roles (user_id, role_id)
+SELECT id, '0196d4a0-0000-7000-8000-000000000001'
+FROM users
+WHERE role = 1
+ON CONFLICT DO NOTHING;
\ No newline at end of file
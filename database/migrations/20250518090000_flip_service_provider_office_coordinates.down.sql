@@ -0,0 +1,10 @@
+UPDATE service_providers SET office_coordinates = ST_FlipCoordinates(office_coordinates::geometry)::geography WHERE office_coordinates IS NOT NULL;
+
+INSERT INTO outbox_events (id, type, payload)
+SELECT
+    gen_random_uuid(),
+    'service_index_sync',
+    jsonb_build_object('service_id', s.id)
+FROM services s
+JOIN service_providers sp ON sp.id = s.service_provider_id
+WHERE s.is_deleted = FALSE AND sp.office_coordinates IS NOT NULL;
\ No newline at end of file
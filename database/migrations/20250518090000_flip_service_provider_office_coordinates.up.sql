@@ -0,0 +1,12 @@
+-- office coordinates were written as POINT(lat long) before the geo-distance search, postgis expects POINT(long lat)
+UPDATE service_providers SET office_coordinates = ST_FlipCoordinates(office_coordinates::geometry)::geography WHERE office_coordinates IS NOT NULL;
+
+-- the outbox sweeper rebuilds the search documents so the location of every existing service is backfilled
+INSERT INTO outbox_events (id, type, payload)
+SELECT
+    gen_random_uuid(),
+    'service_index_sync',
+    jsonb_build_object('service_id', s.id)
+FROM services s
+JOIN service_providers sp ON sp.id = s.service_provider_id
+WHERE s.is_deleted = FALSE AND sp.office_coordinates IS NOT NULL;
\ No newline at end of file
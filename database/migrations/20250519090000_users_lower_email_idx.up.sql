@@ -0,0 +1,2 @@
+-- emails are looked up case insensitively, the accounts registered before they were lowercased keep their case
+CREATE INDEX IF NOT EXISTS users_lower_email_idx ON users (LOWER(email));
\ No newline at end of file
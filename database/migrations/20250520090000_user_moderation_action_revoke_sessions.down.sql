@@ -0,0 +1 @@
+-- the value added to user_moderation_action is kept since postgres can not drop a value from an enum
\ No newline at end of file
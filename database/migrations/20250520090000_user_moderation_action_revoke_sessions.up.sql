@@ -0,0 +1 @@
+ALTER TYPE user_moderation_action ADD VALUE IF NOT EXISTS 'revoke_sessions';
\ No newline at end of file
@@ -0,0 +1 @@
+-- the value added to refund_status is kept since postgres can not drop a value from an enum
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TYPE refund_status ADD VALUE IF NOT EXISTS 'processing';
\ No newline at end of file
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"encoding/base64"
 	"fmt"
 	pkg "kelarin/pkg/validator"
 	"os"
@@ -74,6 +75,29 @@ func (j JWTConfig) Validate() error {
 	)
 }
 
+// AuthConfig is used by local registration and two-factor authentication, the tokens sent by email and the
+// two-factor challenge tokens are signed with TokenSigningKey
+type AuthConfig struct {
+	TokenSigningKey              string        `yaml:"token_signing_key"`
+	EmailVerificationExpiration  time.Duration `yaml:"email_verification_expiration"`
+	PasswordResetExpiration      time.Duration `yaml:"password_reset_expiration"`
+	EmailResendInterval          time.Duration `yaml:"email_resend_interval"`
+	TwoFactorChallengeExpiration time.Duration `yaml:"two_factor_challenge_expiration"`
+	TOTPEncryptionKey            string        `yaml:"totp_encryption_key"` // base64 of a 32 bytes key
+	TOTPEncryptionKeyBytes       []byte
+}
+
+func (a AuthConfig) Validate() error {
+	return validation.ValidateStruct(&a,
+		validation.Field(&a.TokenSigningKey, validation.Required),
+		validation.Field(&a.EmailVerificationExpiration, validation.Required),
+		validation.Field(&a.PasswordResetExpiration, validation.Required),
+		validation.Field(&a.EmailResendInterval, validation.Required),
+		validation.Field(&a.TwoFactorChallengeExpiration, validation.Required),
+		validation.Field(&a.TOTPEncryptionKey, validation.Required, validation.By(pkg.ValidateBase64)),
+	)
+}
+
 type OAuthConfig struct {
 	Google GoogleOAuth `yaml:"google"`
 }
@@ -97,16 +121,21 @@ func (g GoogleOAuth) Validate() error {
 type File struct {
 	MaxUploadedImageFileSize   string        `yaml:"max_uploaded_image_file_size"`
 	AllowedImageTypes          []string      `yaml:"allowed_image_types"`
+	MaxUploadedVideoFileSize   string        `yaml:"max_uploaded_video_file_size"`
+	AllowedVideoTypes          []string      `yaml:"allowed_video_types"`
 	TempFileExpiration         time.Duration `yaml:"temp_file_expiration"`
 	PresignedURLExpiration     time.Duration `yaml:"presigned_url_expiration"`
 	AwsS3Bucket                string        `yaml:"aws_s3_bucket"`
 	UploadedImageFileSizeLimit int64
+	UploadedVideoFileSizeLimit int64
 }
 
 func (f File) Validate() error {
 	return validation.ValidateStruct(&f,
 		validation.Field(&f.MaxUploadedImageFileSize, validation.Required),
 		validation.Field(&f.AllowedImageTypes, validation.Required),
+		validation.Field(&f.MaxUploadedVideoFileSize, validation.Required),
+		validation.Field(&f.AllowedVideoTypes, validation.Required),
 		validation.Field(&f.TempFileExpiration, validation.Required),
 		validation.Field(&f.PresignedURLExpiration, validation.Required),
 		validation.Field(&f.AwsS3Bucket, validation.Required),
@@ -183,12 +212,68 @@ func (j Job) Validate() error {
 	)
 }
 
+type WorkerConfig struct {
+	Concurrency     int           `yaml:"concurrency"`
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout"`
+}
+
+func (w WorkerConfig) Validate() error {
+	return validation.ValidateStruct(&w,
+		validation.Field(&w.Concurrency, validation.Required, validation.Min(1)),
+		validation.Field(&w.ShutdownTimeout, validation.Required),
+	)
+}
+
+type EmailDriver string
+
+const (
+	EmailDriverSMTP EmailDriver = "smtp"
+	EmailDriverLog  EmailDriver = "log" // for local development, emails are logged and written to LogDir instead of being sent
+)
+
+type EmailConfig struct {
+	Driver EmailDriver `yaml:"driver"`
+	From   string      `yaml:"from"`
+	AppURL string      `yaml:"app_url"`
+	LogDir string      `yaml:"log_dir"`
+	SMTP   SMTPConfig  `yaml:"smtp"`
+}
+
+func (e EmailConfig) Validate() error {
+	err := validation.ValidateStruct(&e,
+		validation.Field(&e.Driver, validation.Required, validation.In(EmailDriverSMTP, EmailDriverLog)),
+		validation.Field(&e.From, validation.Required),
+		validation.Field(&e.AppURL, validation.Required),
+	)
+	if err != nil || e.Driver != EmailDriverSMTP {
+		return err
+	}
+
+	// the smtp config is only required by the smtp driver
+	return validation.ValidateStruct(&e.SMTP,
+		validation.Field(&e.SMTP.Host, validation.Required),
+		validation.Field(&e.SMTP.Port, validation.Required),
+	)
+}
+
+type SMTPConfig struct {
+	Host     string `yaml:"host"`
+	Port     int    `yaml:"port"`
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+}
+
+func (s SMTPConfig) Address() string {
+	return fmt.Sprintf("%s:%d", s.Host, s.Port)
+}
+
 type Config struct {
 	Environment            string              `yaml:"environment"`
 	Server                 Server              `yaml:"server"`
 	DataBase               PostgresConfig      `yaml:"database"`
 	Redis                  RedisConfig         `yaml:"redis"`
 	JWT                    JWTConfig           `yaml:"jwt"`
+	Auth                   AuthConfig          `yaml:"auth"`
 	Oauth                  OAuthConfig         `yaml:"oauth"`
 	File                   File                `yaml:"file"`
 	OpenCageApiKey         string              `yaml:"opencage_api_key"`
@@ -197,6 +282,8 @@ type Config struct {
 	Midtrans               MidtransConfig      `yaml:"midtrans"`
 	OrderQRCodeSigningKey  string              `yaml:"order_qr_code_signing_key"`
 	Jobs                   []Job               `yaml:"jobs"`
+	Worker                 WorkerConfig        `yaml:"worker"`
+	Email                  EmailConfig         `yaml:"email"`
 }
 
 func (c Config) Validate() error {
@@ -205,6 +292,7 @@ func (c Config) Validate() error {
 		validation.Field(&c.DataBase, validation.Required),
 		validation.Field(&c.Redis, validation.Required),
 		validation.Field(&c.JWT, validation.Required),
+		validation.Field(&c.Auth, validation.Required),
 		validation.Field(&c.Oauth, validation.Required),
 		validation.Field(&c.OpenCageApiKey, validation.Required),
 		validation.Field(&c.Elasticsearch, validation.Required),
@@ -212,6 +300,8 @@ func (c Config) Validate() error {
 		validation.Field(&c.Midtrans, validation.Required),
 		validation.Field(&c.OrderQRCodeSigningKey, validation.Required),
 		validation.Field(&c.Jobs, validation.Required),
+		validation.Field(&c.Worker, validation.Required),
+		validation.Field(&c.Email, validation.Required),
 	)
 }
 
@@ -250,5 +340,15 @@ func NewApp(filePath string) *Config {
 		log.Fatal().Err(err).Msg("Failed to parse max_uploaded_image_file_size")
 	}
 
+	cfg.File.UploadedVideoFileSizeLimit, err = units.FromHumanSize(cfg.File.MaxUploadedVideoFileSize)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to parse max_uploaded_video_file_size")
+	}
+
+	cfg.Auth.TOTPEncryptionKeyBytes, err = base64.StdEncoding.DecodeString(cfg.Auth.TOTPEncryptionKey)
+	if err != nil || len(cfg.Auth.TOTPEncryptionKeyBytes) != 32 {
+		log.Fatal().Err(err).Msg("totp_encryption_key must be the base64 of a 32 bytes key")
+	}
+
 	return cfg
 }
@@ -0,0 +1,165 @@
+package handler
+
+import (
+	"kelarin/internal/middleware"
+	"kelarin/internal/service"
+	"kelarin/internal/types"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AdminRole interface {
+	GetAllPermissions(c *gin.Context)
+	GetAll(c *gin.Context)
+	Create(c *gin.Context)
+	Update(c *gin.Context)
+	Delete(c *gin.Context)
+	GetByUserID(c *gin.Context)
+	AssignToUser(c *gin.Context)
+}
+
+type adminRoleImpl struct {
+	adminRoleSvc service.AdminRole
+	authMw       middleware.Auth
+}
+
+func NewAdminRole(adminRoleSvc service.AdminRole, authMw middleware.Auth) AdminRole {
+	return &adminRoleImpl{
+		adminRoleSvc: adminRoleSvc,
+		authMw:       authMw,
+	}
+}
+
+func (h *adminRoleImpl) GetAllPermissions(c *gin.Context) {
+	c.JSON(http.StatusOK, types.ApiResponse{
+		StatusCode: http.StatusOK,
+		Data:       h.adminRoleSvc.GetAllPermissions(c.Request.Context()),
+	})
+}
+
+func (h *adminRoleImpl) GetAll(c *gin.Context) {
+	var req types.AdminRoleGetAllReq
+	if err := h.authMw.BindWithRequest(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	res, err := h.adminRoleSvc.GetAll(c.Request.Context(), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, types.ApiResponse{
+		StatusCode: http.StatusOK,
+		Data:       res,
+	})
+}
+
+func (h *adminRoleImpl) Create(c *gin.Context) {
+	var req types.AdminRoleCreateReq
+	if err := h.authMw.BindWithRequest(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	res, err := h.adminRoleSvc.Create(c.Request.Context(), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, types.ApiResponse{
+		StatusCode: http.StatusCreated,
+		Data:       res,
+	})
+}
+
+func (h *adminRoleImpl) Update(c *gin.Context) {
+	var req types.AdminRoleUpdateReq
+	if err := req.ID.UnmarshalText([]byte(c.Param("id"))); err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := h.authMw.BindWithRequest(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := h.adminRoleSvc.Update(c.Request.Context(), req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, types.ApiResponse{
+		StatusCode: http.StatusOK,
+	})
+}
+
+func (h *adminRoleImpl) Delete(c *gin.Context) {
+	var req types.AdminRoleDeleteReq
+	if err := req.ID.UnmarshalText([]byte(c.Param("id"))); err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := h.authMw.BindWithRequest(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := h.adminRoleSvc.Delete(c.Request.Context(), req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
+
+func (h *adminRoleImpl) GetByUserID(c *gin.Context) {
+	var req types.AdminRoleGetByUserIDReq
+	if err := req.UserID.UnmarshalText([]byte(c.Param("id"))); err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := h.authMw.BindWithRequest(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	res, err := h.adminRoleSvc.GetByUserID(c.Request.Context(), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, types.ApiResponse{
+		StatusCode: http.StatusOK,
+		Data:       res,
+	})
+}
+
+func (h *adminRoleImpl) AssignToUser(c *gin.Context) {
+	var req types.AdminRoleAssignReq
+	if err := req.UserID.UnmarshalText([]byte(c.Param("id"))); err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := h.authMw.BindWithRequest(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := h.adminRoleSvc.AssignToUser(c.Request.Context(), req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, types.ApiResponse{
+		StatusCode: http.StatusOK,
+	})
+}
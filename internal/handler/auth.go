@@ -10,12 +10,13 @@ import (
 )
 
 type Auth struct {
-	authService service.Auth
-	authMw      middleware.Auth
+	authService      service.Auth
+	twoFactorService service.TwoFactor
+	authMw           middleware.Auth
 }
 
-func NewAuth(authService service.Auth, authMw middleware.Auth) *Auth {
-	return &Auth{authService: authService, authMw: authMw}
+func NewAuth(authService service.Auth, twoFactorService service.TwoFactor, authMw middleware.Auth) *Auth {
+	return &Auth{authService: authService, twoFactorService: twoFactorService, authMw: authMw}
 }
 
 func (h *Auth) Login(c *gin.Context) {
@@ -26,6 +27,11 @@ func (h *Auth) Login(c *gin.Context) {
 		return
 	}
 
+	if err := bindSessionMetadata(c, &req.SessionMetadataReq); err != nil {
+		c.Error(err)
+		return
+	}
+
 	res, err := h.authService.LocalCreateSession(c.Request.Context(), req)
 	if err != nil {
 		c.Error(err)
@@ -46,6 +52,11 @@ func (h *Auth) ConsumerGoogleLogin(c *gin.Context) {
 		return
 	}
 
+	if err := bindSessionMetadata(c, &req.SessionMetadataReq); err != nil {
+		c.Error(err)
+		return
+	}
+
 	res, err := h.authService.ConsumerCreateSession(c.Request.Context(), req)
 	if err != nil {
 		c.Error(err)
@@ -66,6 +77,11 @@ func (h *Auth) ProviderGoogleLogin(c *gin.Context) {
 		return
 	}
 
+	if err := bindSessionMetadata(c, &req.SessionMetadataReq); err != nil {
+		c.Error(err)
+		return
+	}
+
 	res, err := h.authService.ProviderCreateSession(c.Request.Context(), req)
 	if err != nil {
 		c.Error(err)
@@ -86,6 +102,11 @@ func (h *Auth) RenewSession(c *gin.Context) {
 		return
 	}
 
+	if err := bindSessionMetadata(c, &req.SessionMetadataReq); err != nil {
+		c.Error(err)
+		return
+	}
+
 	res, err := h.authService.RenewSession(c.Request.Context(), req)
 	if err != nil {
 		c.Error(err)
@@ -115,3 +136,307 @@ func (h *Auth) RevokeSession(c *gin.Context) {
 
 	c.JSON(http.StatusNoContent, nil)
 }
+
+func (h *Auth) ConsumerRegister(c *gin.Context) {
+	var req types.AuthRegisterReq
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := h.authService.ConsumerRegister(c.Request.Context(), req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, types.ApiResponse{
+		StatusCode: http.StatusCreated,
+	})
+}
+
+func (h *Auth) ProviderRegister(c *gin.Context) {
+	var req types.AuthRegisterReq
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := h.authService.ProviderRegister(c.Request.Context(), req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, types.ApiResponse{
+		StatusCode: http.StatusCreated,
+	})
+}
+
+func (h *Auth) VerifyEmail(c *gin.Context) {
+	var req types.AuthVerifyEmailReq
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := h.authService.VerifyEmail(c.Request.Context(), req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
+
+func (h *Auth) ResendVerificationEmail(c *gin.Context) {
+	var req types.AuthEmailReq
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := h.authService.ResendVerificationEmail(c.Request.Context(), req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
+
+func (h *Auth) ForgotPassword(c *gin.Context) {
+	var req types.AuthEmailReq
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := h.authService.ForgotPassword(c.Request.Context(), req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
+
+func (h *Auth) ResetPassword(c *gin.Context) {
+	var req types.AuthResetPasswordReq
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := h.authService.ResetPassword(c.Request.Context(), req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
+
+func (h *Auth) ChangePassword(c *gin.Context) {
+	var req types.AuthChangePasswordReq
+
+	if err := h.authMw.BindWithRequest(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := h.authService.ChangePassword(c.Request.Context(), req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
+
+func (h *Auth) GetSessions(c *gin.Context) {
+	var req types.SessionGetAllReq
+
+	if err := h.authMw.BindWithRequest(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	res, err := h.authService.GetSessions(c.Request.Context(), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, types.ApiResponse{
+		StatusCode: http.StatusOK,
+		Data:       res,
+	})
+}
+
+func (h *Auth) RevokeSessionByID(c *gin.Context) {
+	var req types.SessionRevokeReq
+	if err := req.ID.UnmarshalText([]byte(c.Param("id"))); err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := h.authMw.BindWithRequest(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := h.authService.RevokeSessionByID(c.Request.Context(), req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
+
+func (h *Auth) RevokeOtherSessions(c *gin.Context) {
+	var req types.SessionRevokeOthersReq
+
+	if err := h.authMw.BindWithRequest(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := h.authService.RevokeOtherSessions(c.Request.Context(), req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
+
+func (h *Auth) VerifyTwoFactor(c *gin.Context) {
+	var req types.AuthVerifyTwoFactorReq
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := bindSessionMetadata(c, &req.SessionMetadataReq); err != nil {
+		c.Error(err)
+		return
+	}
+
+	res, err := h.authService.VerifyTwoFactor(c.Request.Context(), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, types.ApiResponse{
+		StatusCode: http.StatusCreated,
+		Data:       res,
+	})
+}
+
+func (h *Auth) EnrollTwoFactor(c *gin.Context) {
+	var req types.AuthEnrollTwoFactorReq
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	res, err := h.authService.EnrollTwoFactor(c.Request.Context(), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, types.ApiResponse{
+		StatusCode: http.StatusOK,
+		Data:       res,
+	})
+}
+
+func (h *Auth) SetupTwoFactor(c *gin.Context) {
+	var req types.TwoFactorSetupReq
+
+	if err := h.authMw.BindWithRequest(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	res, err := h.twoFactorService.Setup(c.Request.Context(), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, types.ApiResponse{
+		StatusCode: http.StatusOK,
+		Data:       res,
+	})
+}
+
+func (h *Auth) EnableTwoFactor(c *gin.Context) {
+	var req types.TwoFactorCodeReq
+
+	if err := h.authMw.BindWithRequest(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	res, err := h.twoFactorService.Enable(c.Request.Context(), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, types.ApiResponse{
+		StatusCode: http.StatusOK,
+		Data:       res,
+	})
+}
+
+func (h *Auth) DisableTwoFactor(c *gin.Context) {
+	var req types.TwoFactorCodeReq
+
+	if err := h.authMw.BindWithRequest(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := h.twoFactorService.Disable(c.Request.Context(), req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
+
+func (h *Auth) RegenerateTwoFactorRecoveryCodes(c *gin.Context) {
+	var req types.TwoFactorCodeReq
+
+	if err := h.authMw.BindWithRequest(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	res, err := h.twoFactorService.RegenerateRecoveryCodes(c.Request.Context(), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, types.ApiResponse{
+		StatusCode: http.StatusOK,
+		Data:       res,
+	})
+}
+
+// bindSessionMetadata reads the device that creates or renews the session, it is shown in the session list
+func bindSessionMetadata(c *gin.Context, req *types.SessionMetadataReq) error {
+	if err := c.ShouldBindHeader(req); err != nil {
+		return err
+	}
+
+	req.IPAddress = c.ClientIP()
+
+	return nil
+}
@@ -5,6 +5,7 @@ import (
 	"kelarin/internal/middleware"
 	"kelarin/internal/service"
 	"kelarin/internal/types"
+	ws "kelarin/internal/utils/websocket"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -23,16 +24,17 @@ type Chat interface {
 	ProviderGetByRoomID(c *gin.Context)
 
 	MarkReceivedAsSeen(c *gin.Context)
+	Search(c *gin.Context)
 }
 
 type chatImpl struct {
 	wsUpgrader     *websocket.Upgrader
-	hub            *types.WsHub
+	hub            ws.Hub
 	chatService    service.Chat
 	authMiddleware middleware.Auth
 }
 
-func NewChat(upgrader *websocket.Upgrader, chatService service.Chat, hub *types.WsHub, authMw middleware.Auth) Chat {
+func NewChat(upgrader *websocket.Upgrader, chatService service.Chat, hub ws.Hub, authMw middleware.Auth) Chat {
 	return &chatImpl{
 		wsUpgrader:     upgrader,
 		hub:            hub,
@@ -48,19 +50,40 @@ func (h *chatImpl) HandleInboundMessage(ctx *gin.Context) {
 		return
 	}
 
-	client := types.WsClient{
-		Ctx: context.Background(),
-		Con: con,
+	client := types.NewWsClient(context.Background(), con)
+
+	if err = h.authMiddleware.BindWithRequest(ctx, client); err != nil {
+		log.Error().Stack().Err(err).Send()
+		con.Close()
+		return
 	}
 
-	if err = h.authMiddleware.BindWithRequest(ctx, &client); err != nil {
+	if err = h.hub.Register(client); err != nil {
 		log.Error().Stack().Err(err).Send()
+		con.Close()
 		return
 	}
 
-	h.hub.Clients[client.AuthUser.ID.String()] = &client
+	ws.Start(client)
+
+	if err = h.chatService.PublishPresence(client.Ctx, client.AuthUser.ID, true); err != nil {
+		log.Error().Stack().Err(err).Send()
+	}
+
+	// the read loop returns once the connection is closed or misses its pongs
+	go func() {
+		h.chatService.HandleInboundMessage(client)
 
-	go h.chatService.HandleInboundMessage(&client)
+		if err := h.hub.Unregister(client); err != nil {
+			log.Error().Stack().Err(err).Send()
+		}
+
+		client.Close()
+
+		if err := h.chatService.PublishPresence(client.Ctx, client.AuthUser.ID, false); err != nil {
+			log.Error().Stack().Err(err).Send()
+		}
+	}()
 }
 
 func (h *chatImpl) ConsumerGetAll(c *gin.Context) {
@@ -94,7 +117,7 @@ func (h *chatImpl) ConsumerGetByRoomID(c *gin.Context) {
 		return
 	}
 
-	res, err := h.chatService.ConsumerGetByRoomID(c.Request.Context(), req)
+	res, paginationRes, err := h.chatService.ConsumerGetByRoomID(c.Request.Context(), req)
 	if err != nil {
 		c.Error(err)
 		return
@@ -103,6 +126,7 @@ func (h *chatImpl) ConsumerGetByRoomID(c *gin.Context) {
 	c.JSON(http.StatusOK, types.ApiResponse{
 		StatusCode: http.StatusOK,
 		Data:       res,
+		Pagination: &paginationRes,
 	})
 }
 
@@ -137,7 +161,7 @@ func (h *chatImpl) ProviderGetByRoomID(c *gin.Context) {
 		return
 	}
 
-	res, err := h.chatService.ProviderGetByRoomID(c.Request.Context(), req)
+	res, paginationRes, err := h.chatService.ProviderGetByRoomID(c.Request.Context(), req)
 	if err != nil {
 		c.Error(err)
 		return
@@ -146,6 +170,7 @@ func (h *chatImpl) ProviderGetByRoomID(c *gin.Context) {
 	c.JSON(http.StatusOK, types.ApiResponse{
 		StatusCode: http.StatusOK,
 		Data:       res,
+		Pagination: &paginationRes,
 	})
 }
 
@@ -192,3 +217,23 @@ func (h *chatImpl) MarkReceivedAsSeen(c *gin.Context) {
 		StatusCode: http.StatusOK,
 	})
 }
+
+func (h *chatImpl) Search(c *gin.Context) {
+	var req types.ChatSearchReq
+	if err := h.authMiddleware.BindWithRequest(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	res, paginationRes, err := h.chatService.Search(c.Request.Context(), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, types.ApiResponse{
+		StatusCode: http.StatusOK,
+		Data:       res,
+		Pagination: &paginationRes,
+	})
+}
@@ -0,0 +1,229 @@
+package handler
+
+import (
+	"kelarin/internal/middleware"
+	"kelarin/internal/service"
+	"kelarin/internal/types"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Dispute interface {
+	ConsumerCreate(c *gin.Context)
+	ConsumerGetAll(c *gin.Context)
+	ConsumerGetByID(c *gin.Context)
+
+	ProviderGetAll(c *gin.Context)
+	ProviderGetByID(c *gin.Context)
+	ProviderRespond(c *gin.Context)
+
+	AdminGetAll(c *gin.Context)
+	AdminGetByID(c *gin.Context)
+	AdminResolve(c *gin.Context)
+}
+
+type disputeImpl struct {
+	disputeSvc service.Dispute
+	authMw     middleware.Auth
+}
+
+func NewDispute(disputeSvc service.Dispute, authMw middleware.Auth) Dispute {
+	return &disputeImpl{
+		disputeSvc: disputeSvc,
+		authMw:     authMw,
+	}
+}
+
+func (h *disputeImpl) ConsumerCreate(c *gin.Context) {
+	var req types.DisputeConsumerCreateReq
+	if err := h.authMw.BindWithRequest(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := h.disputeSvc.ConsumerCreate(c.Request.Context(), req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, types.ApiResponse{
+		StatusCode: http.StatusCreated,
+	})
+}
+
+func (h *disputeImpl) ConsumerGetAll(c *gin.Context) {
+	var req types.DisputeConsumerGetAllReq
+	if err := h.authMw.BindWithRequest(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	res, paginationRes, err := h.disputeSvc.ConsumerGetAll(c.Request.Context(), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, types.ApiResponse{
+		StatusCode: http.StatusOK,
+		Data:       res,
+		Pagination: &paginationRes,
+	})
+}
+
+func (h *disputeImpl) ConsumerGetByID(c *gin.Context) {
+	var req types.DisputeGetByIDReq
+	if err := req.ID.UnmarshalText([]byte(c.Param("id"))); err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := h.authMw.BindWithRequest(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	res, err := h.disputeSvc.ConsumerGetByID(c.Request.Context(), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, types.ApiResponse{
+		StatusCode: http.StatusOK,
+		Data:       res,
+	})
+}
+
+func (h *disputeImpl) ProviderGetAll(c *gin.Context) {
+	var req types.DisputeProviderGetAllReq
+	if err := h.authMw.BindWithRequest(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	res, paginationRes, err := h.disputeSvc.ProviderGetAll(c.Request.Context(), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, types.ApiResponse{
+		StatusCode: http.StatusOK,
+		Data:       res,
+		Pagination: &paginationRes,
+	})
+}
+
+func (h *disputeImpl) ProviderGetByID(c *gin.Context) {
+	var req types.DisputeGetByIDReq
+	if err := req.ID.UnmarshalText([]byte(c.Param("id"))); err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := h.authMw.BindWithRequest(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	res, err := h.disputeSvc.ProviderGetByID(c.Request.Context(), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, types.ApiResponse{
+		StatusCode: http.StatusOK,
+		Data:       res,
+	})
+}
+
+func (h *disputeImpl) ProviderRespond(c *gin.Context) {
+	var req types.DisputeProviderRespondReq
+	if err := req.ID.UnmarshalText([]byte(c.Param("id"))); err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := h.authMw.BindWithRequest(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := h.disputeSvc.ProviderRespond(c.Request.Context(), req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, types.ApiResponse{
+		StatusCode: http.StatusOK,
+	})
+}
+
+func (h *disputeImpl) AdminGetAll(c *gin.Context) {
+	var req types.DisputeAdminGetAllReq
+	if err := h.authMw.BindWithRequest(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	res, paginationRes, err := h.disputeSvc.AdminGetAll(c.Request.Context(), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, types.ApiResponse{
+		StatusCode: http.StatusOK,
+		Data:       res,
+		Pagination: &paginationRes,
+	})
+}
+
+func (h *disputeImpl) AdminGetByID(c *gin.Context) {
+	var req types.DisputeGetByIDReq
+	if err := req.ID.UnmarshalText([]byte(c.Param("id"))); err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := h.authMw.BindWithRequest(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	res, err := h.disputeSvc.AdminGetByID(c.Request.Context(), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, types.ApiResponse{
+		StatusCode: http.StatusOK,
+		Data:       res,
+	})
+}
+
+func (h *disputeImpl) AdminResolve(c *gin.Context) {
+	var req types.DisputeAdminResolveReq
+	if err := req.ID.UnmarshalText([]byte(c.Param("id"))); err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := h.authMw.BindWithRequest(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := h.disputeSvc.AdminResolve(c.Request.Context(), req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, types.ApiResponse{
+		StatusCode: http.StatusOK,
+	})
+}
@@ -11,6 +11,7 @@ import (
 
 type File interface {
 	UploadImages(c *gin.Context)
+	UploadChatAttachment(c *gin.Context)
 }
 
 type fileImpl struct {
@@ -64,3 +65,38 @@ func (h fileImpl) UploadImages(c *gin.Context) {
 		Data:       res,
 	})
 }
+
+func (h fileImpl) UploadChatAttachment(c *gin.Context) {
+	var req types.FileUploadChatAttachmentReq
+
+	file, err := c.FormFile("file")
+	if errors.Is(err, http.ErrNotMultipart) {
+		c.Error(errors.New(types.AppErr{
+			Code:    http.StatusBadRequest,
+			Message: http.ErrNotMultipart.Error(),
+		}))
+		return
+	} else if errors.Is(err, http.ErrMissingFile) {
+		c.Error(errors.New(types.AppErr{
+			Code:    http.StatusBadRequest,
+			Message: "file is required",
+		}))
+		return
+	} else if err != nil {
+		c.Error(errors.New(err))
+		return
+	}
+
+	req.File = file
+
+	res, err := h.fileUploadSvc.StoreChatAttachmentTemp(c.Request.Context(), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, types.ApiResponse{
+		StatusCode: http.StatusOK,
+		Data:       res,
+	})
+}
@@ -7,29 +7,46 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
 type Notification interface {
 	SaveToken(c *gin.Context)
+	GetPreferences(c *gin.Context)
+	UpdatePreferences(c *gin.Context)
+	UpdateQuietHours(c *gin.Context)
+	UpdateLanguage(c *gin.Context)
 
 	ConsumerGetAll(c *gin.Context)
+	ConsumerMarkAsRead(c *gin.Context)
+	ConsumerMarkManyAsRead(c *gin.Context)
+	ConsumerMarkAllAsRead(c *gin.Context)
+	ConsumerGetUnreadCount(c *gin.Context)
 
 	ProviderGetAll(c *gin.Context)
+	ProviderMarkAsRead(c *gin.Context)
+	ProviderMarkManyAsRead(c *gin.Context)
+	ProviderMarkAllAsRead(c *gin.Context)
+	ProviderGetUnreadCount(c *gin.Context)
+
+	AdminBroadcast(c *gin.Context)
 }
 
 type notificationImpl struct {
-	authMw                  middleware.Auth
-	notificationSvc         service.Notification
-	consumerNotificationSvc service.ConsumerNotification
-	providerNotification    service.ServiceProviderNotification
+	authMw                    middleware.Auth
+	notificationSvc           service.Notification
+	consumerNotificationSvc   service.ConsumerNotification
+	providerNotification      service.ServiceProviderNotification
+	notificationPreferenceSvc service.NotificationPreference
 }
 
-func NewNotification(authMw middleware.Auth, notificationSvc service.Notification, consumerNotificationSvc service.ConsumerNotification, providerNotification service.ServiceProviderNotification) Notification {
+func NewNotification(authMw middleware.Auth, notificationSvc service.Notification, consumerNotificationSvc service.ConsumerNotification, providerNotification service.ServiceProviderNotification, notificationPreferenceSvc service.NotificationPreference) Notification {
 	return &notificationImpl{
-		authMw:                  authMw,
-		notificationSvc:         notificationSvc,
-		consumerNotificationSvc: consumerNotificationSvc,
-		providerNotification:    providerNotification,
+		authMw:                    authMw,
+		notificationSvc:           notificationSvc,
+		consumerNotificationSvc:   consumerNotificationSvc,
+		providerNotification:      providerNotification,
+		notificationPreferenceSvc: notificationPreferenceSvc,
 	}
 }
 
@@ -50,6 +67,76 @@ func (h *notificationImpl) SaveToken(c *gin.Context) {
 	})
 }
 
+func (h *notificationImpl) GetPreferences(c *gin.Context) {
+	var req types.NotificationPreferenceGetReq
+	if err := h.authMw.BindWithRequest(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	res, err := h.notificationPreferenceSvc.Get(c.Request.Context(), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, types.ApiResponse{
+		StatusCode: http.StatusOK,
+		Data:       res,
+	})
+}
+
+func (h *notificationImpl) UpdatePreferences(c *gin.Context) {
+	var req types.NotificationPreferenceUpdateReq
+	if err := h.authMw.BindWithRequest(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := h.notificationPreferenceSvc.Update(c.Request.Context(), req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, types.ApiResponse{
+		StatusCode: http.StatusOK,
+	})
+}
+
+func (h *notificationImpl) UpdateQuietHours(c *gin.Context) {
+	var req types.NotificationQuietHoursUpdateReq
+	if err := h.authMw.BindWithRequest(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := h.notificationPreferenceSvc.UpdateQuietHours(c.Request.Context(), req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, types.ApiResponse{
+		StatusCode: http.StatusOK,
+	})
+}
+
+func (h *notificationImpl) UpdateLanguage(c *gin.Context) {
+	var req types.NotificationLanguageUpdateReq
+	if err := h.authMw.BindWithRequest(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := h.notificationPreferenceSvc.UpdateLanguage(c.Request.Context(), req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, types.ApiResponse{
+		StatusCode: http.StatusOK,
+	})
+}
+
 func (h *notificationImpl) ConsumerGetAll(c *gin.Context) {
 	var req types.ConsumerNotificationGetAllReq
 	if err := h.authMw.BindWithRequest(c, &req); err != nil {
@@ -57,7 +144,77 @@ func (h *notificationImpl) ConsumerGetAll(c *gin.Context) {
 		return
 	}
 
-	res, err := h.consumerNotificationSvc.GetAll(c.Request.Context(), req)
+	res, paginationRes, err := h.consumerNotificationSvc.GetAll(c.Request.Context(), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, types.ApiResponse{
+		StatusCode: http.StatusOK,
+		Data:       res,
+		Pagination: &paginationRes,
+	})
+}
+
+func (h *notificationImpl) ConsumerMarkAsRead(c *gin.Context) {
+	req, ok := h.bindMarkAsReadByParam(c)
+	if !ok {
+		return
+	}
+
+	if err := h.consumerNotificationSvc.MarkAsRead(c.Request.Context(), req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, types.ApiResponse{
+		StatusCode: http.StatusOK,
+	})
+}
+
+func (h *notificationImpl) ConsumerMarkManyAsRead(c *gin.Context) {
+	var req types.NotificationMarkAsReadReq
+	if err := h.authMw.BindWithRequest(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := h.consumerNotificationSvc.MarkAsRead(c.Request.Context(), req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, types.ApiResponse{
+		StatusCode: http.StatusOK,
+	})
+}
+
+func (h *notificationImpl) ConsumerMarkAllAsRead(c *gin.Context) {
+	var req types.NotificationMarkAllAsReadReq
+	if err := h.authMw.BindWithRequest(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := h.consumerNotificationSvc.MarkAllAsRead(c.Request.Context(), req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, types.ApiResponse{
+		StatusCode: http.StatusOK,
+	})
+}
+
+func (h *notificationImpl) ConsumerGetUnreadCount(c *gin.Context) {
+	var req types.NotificationUnreadCountReq
+	if err := h.authMw.BindWithRequest(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	res, err := h.consumerNotificationSvc.GetUnreadCount(c.Request.Context(), req)
 	if err != nil {
 		c.Error(err)
 		return
@@ -76,7 +233,77 @@ func (h *notificationImpl) ProviderGetAll(c *gin.Context) {
 		return
 	}
 
-	res, err := h.providerNotification.GetAll(c.Request.Context(), req)
+	res, paginationRes, err := h.providerNotification.GetAll(c.Request.Context(), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, types.ApiResponse{
+		StatusCode: http.StatusOK,
+		Data:       res,
+		Pagination: &paginationRes,
+	})
+}
+
+func (h *notificationImpl) ProviderMarkAsRead(c *gin.Context) {
+	req, ok := h.bindMarkAsReadByParam(c)
+	if !ok {
+		return
+	}
+
+	if err := h.providerNotification.MarkAsRead(c.Request.Context(), req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, types.ApiResponse{
+		StatusCode: http.StatusOK,
+	})
+}
+
+func (h *notificationImpl) ProviderMarkManyAsRead(c *gin.Context) {
+	var req types.NotificationMarkAsReadReq
+	if err := h.authMw.BindWithRequest(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := h.providerNotification.MarkAsRead(c.Request.Context(), req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, types.ApiResponse{
+		StatusCode: http.StatusOK,
+	})
+}
+
+func (h *notificationImpl) ProviderMarkAllAsRead(c *gin.Context) {
+	var req types.NotificationMarkAllAsReadReq
+	if err := h.authMw.BindWithRequest(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := h.providerNotification.MarkAllAsRead(c.Request.Context(), req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, types.ApiResponse{
+		StatusCode: http.StatusOK,
+	})
+}
+
+func (h *notificationImpl) ProviderGetUnreadCount(c *gin.Context) {
+	var req types.NotificationUnreadCountReq
+	if err := h.authMw.BindWithRequest(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	res, err := h.providerNotification.GetUnreadCount(c.Request.Context(), req)
 	if err != nil {
 		c.Error(err)
 		return
@@ -87,3 +314,40 @@ func (h *notificationImpl) ProviderGetAll(c *gin.Context) {
 		Data:       res,
 	})
 }
+
+// bindMarkAsReadByParam binds a mark as read request of the notification in the id path param, the error is already set when it fails
+func (h *notificationImpl) bindMarkAsReadByParam(c *gin.Context) (types.NotificationMarkAsReadReq, bool) {
+	var req types.NotificationMarkAsReadReq
+
+	var ID uuid.UUID
+	if err := ID.UnmarshalText([]byte(c.Param("id"))); err != nil {
+		c.Error(err)
+		return req, false
+	}
+
+	if err := h.authMw.BindWithRequest(c, &req); err != nil {
+		c.Error(err)
+		return req, false
+	}
+
+	req.IDs = uuid.UUIDs{ID}
+
+	return req, true
+}
+
+func (h *notificationImpl) AdminBroadcast(c *gin.Context) {
+	var req types.NotificationAdminBroadcastReq
+	if err := h.authMw.BindWithRequest(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := h.notificationSvc.AdminBroadcast(c.Request.Context(), req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, types.ApiResponse{
+		StatusCode: http.StatusOK,
+	})
+}
@@ -13,10 +13,12 @@ type Order interface {
 	ConsumerGetAll(c *gin.Context)
 	ConsumerGetByID(c *gin.Context)
 	ConsumerGenerateQRCode(c *gin.Context)
+	ConsumerCancel(c *gin.Context)
 
 	ProviderGetAll(c *gin.Context)
 	ProviderGetByID(c *gin.Context)
 	ProviderFinish(c *gin.Context)
+	ProviderCancel(c *gin.Context)
 }
 
 type orderImpl struct {
@@ -98,6 +100,30 @@ func (h *orderImpl) ConsumerGenerateQRCode(c *gin.Context) {
 	})
 }
 
+func (h *orderImpl) ConsumerCancel(c *gin.Context) {
+	var req types.OrderCancelReq
+	if err := req.ID.UnmarshalText([]byte(c.Param("id"))); err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := h.authMw.BindWithRequest(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	res, err := h.orderSvc.ConsumerCancel(c.Request.Context(), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, types.ApiResponse{
+		StatusCode: http.StatusOK,
+		Data:       res,
+	})
+}
+
 func (h *orderImpl) ProviderGetAll(c *gin.Context) {
 	var req types.OrderProviderGetAllReq
 	if err := h.authMw.BindWithRequest(c, &req); err != nil {
@@ -157,3 +183,27 @@ func (h *orderImpl) ProviderFinish(c *gin.Context) {
 		StatusCode: http.StatusOK,
 	})
 }
+
+func (h *orderImpl) ProviderCancel(c *gin.Context) {
+	var req types.OrderCancelReq
+	if err := req.ID.UnmarshalText([]byte(c.Param("id"))); err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := h.authMw.BindWithRequest(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	res, err := h.orderSvc.ProviderCancel(c.Request.Context(), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, types.ApiResponse{
+		StatusCode: http.StatusOK,
+		Data:       res,
+	})
+}
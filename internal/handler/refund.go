@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"kelarin/internal/middleware"
+	"kelarin/internal/service"
+	"kelarin/internal/types"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Refund interface {
+	AdminGetAll(c *gin.Context)
+	AdminRetry(c *gin.Context)
+	AdminMarkAsSucceeded(c *gin.Context)
+}
+
+type refundImpl struct {
+	refundSvc service.Refund
+	authMw    middleware.Auth
+}
+
+func NewRefund(refundSvc service.Refund, authMw middleware.Auth) Refund {
+	return &refundImpl{
+		refundSvc: refundSvc,
+		authMw:    authMw,
+	}
+}
+
+func (h *refundImpl) AdminGetAll(c *gin.Context) {
+	var req types.RefundAdminGetAllReq
+	if err := h.authMw.BindWithRequest(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	res, paginationRes, err := h.refundSvc.AdminGetAll(c.Request.Context(), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, types.ApiResponse{
+		StatusCode: http.StatusOK,
+		Data:       res,
+		Pagination: &paginationRes,
+	})
+}
+
+func (h *refundImpl) AdminRetry(c *gin.Context) {
+	var req types.RefundAdminActionReq
+	if err := req.ID.UnmarshalText([]byte(c.Param("id"))); err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := h.authMw.BindWithRequest(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := h.refundSvc.AdminRetry(c.Request.Context(), req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, types.ApiResponse{
+		StatusCode: http.StatusOK,
+	})
+}
+
+func (h *refundImpl) AdminMarkAsSucceeded(c *gin.Context) {
+	var req types.RefundAdminActionReq
+	if err := req.ID.UnmarshalText([]byte(c.Param("id"))); err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := h.authMw.BindWithRequest(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := h.refundSvc.AdminMarkAsSucceeded(c.Request.Context(), req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, types.ApiResponse{
+		StatusCode: http.StatusOK,
+	})
+}
@@ -1,15 +1,19 @@
 package handler
 
 import (
+	"fmt"
 	"kelarin/internal/middleware"
 	"kelarin/internal/service"
 	"kelarin/internal/types"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-errors/errors"
 	"github.com/google/uuid"
 	"github.com/samber/lo"
+	"github.com/shopspring/decimal"
+	"github.com/volatiletech/null/v9"
 )
 
 type Service interface {
@@ -212,7 +216,62 @@ func (h *serviceImpl) ConsumerGetAll(c *gin.Context) {
 
 	req.Categories = categories
 
-	res, paginationRes, err := h.consumerSvc.GetAll(c.Request.Context(), req)
+	if authUser, exists := c.Get(types.AuthUserContextKey); exists {
+		req.AuthUser, _ = authUser.(types.AuthUser)
+	}
+
+	for query, dest := range map[string]*null.Float64{"lat": &req.Lat, "lng": &req.Lng, "radius": &req.Radius, "min_rating": &req.MinRating} {
+		if val := c.Query(query); val != "" {
+			floatVal, err := strconv.ParseFloat(val, 64)
+			if err != nil {
+				c.Error(errors.New(types.AppErr{Code: http.StatusBadRequest, Message: fmt.Sprintf("invalid %s query", query)}))
+				return
+			}
+
+			*dest = null.Float64From(floatVal)
+		}
+	}
+
+	if addressID := c.Query("address_id"); addressID != "" {
+		if err := req.AddressID.UUID.UnmarshalText([]byte(addressID)); err != nil {
+			c.Error(errors.New(types.AppErr{Code: http.StatusBadRequest, Message: "invalid address_id query"}))
+			return
+		}
+
+		req.AddressID.Valid = true
+	}
+
+	for query, dest := range map[string]*decimal.NullDecimal{"fee_min": &req.FeeMin, "fee_max": &req.FeeMax} {
+		if val := c.Query(query); val != "" {
+			decimalVal, err := decimal.NewFromString(val)
+			if err != nil {
+				c.Error(errors.New(types.AppErr{Code: http.StatusBadRequest, Message: fmt.Sprintf("invalid %s query", query)}))
+				return
+			}
+
+			*dest = decimal.NewNullDecimal(decimalVal)
+		}
+	}
+
+	if available := c.Query("available"); available != "" {
+		isAvailable, err := strconv.ParseBool(available)
+		if err != nil {
+			c.Error(errors.New(types.AppErr{Code: http.StatusBadRequest, Message: "invalid available query"}))
+			return
+		}
+
+		req.IsAvailable = null.BoolFrom(isAvailable)
+	}
+
+	for _, deliveryMethod := range c.QueryArray("delivery_methods") {
+		if deliveryMethod != "" {
+			req.DeliveryMethods = append(req.DeliveryMethods, types.ServiceDeliveryMethod(deliveryMethod))
+		}
+	}
+
+	req.Sort = types.ServiceIndexSort(c.Query("sort"))
+
+	res, paginationRes, metadata, err := h.consumerSvc.GetAll(c.Request.Context(), req)
 	if err != nil {
 		c.Error(err)
 		return
@@ -222,6 +281,7 @@ func (h *serviceImpl) ConsumerGetAll(c *gin.Context) {
 		StatusCode: http.StatusOK,
 		Data:       res,
 		Pagination: &paginationRes,
+		Metadata:   metadata,
 	})
 }
 
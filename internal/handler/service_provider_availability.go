@@ -0,0 +1,173 @@
+package handler
+
+import (
+	"kelarin/internal/middleware"
+	"kelarin/internal/service"
+	"kelarin/internal/types"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ServiceProviderAvailability interface {
+	GetSlots(c *gin.Context)
+
+	ProviderGetWorkingHours(c *gin.Context)
+	ProviderUpdateWorkingHours(c *gin.Context)
+	ProviderGetAllDaysOff(c *gin.Context)
+	ProviderCreateDayOff(c *gin.Context)
+	ProviderDeleteDayOff(c *gin.Context)
+	ProviderUpdateService(c *gin.Context)
+}
+
+type serviceProviderAvailabilityImpl struct {
+	serviceProviderAvailabilitySvc service.ServiceProviderAvailability
+	authMw                         middleware.Auth
+}
+
+func NewServiceProviderAvailability(serviceProviderAvailabilitySvc service.ServiceProviderAvailability, authMw middleware.Auth) ServiceProviderAvailability {
+	return &serviceProviderAvailabilityImpl{
+		serviceProviderAvailabilitySvc: serviceProviderAvailabilitySvc,
+		authMw:                         authMw,
+	}
+}
+
+func (h *serviceProviderAvailabilityImpl) GetSlots(c *gin.Context) {
+	var req types.ServiceAvailabilityGetSlotsReq
+	if err := req.ID.UnmarshalText([]byte(c.Param("id"))); err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := h.authMw.BindWithRequest(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	res, err := h.serviceProviderAvailabilitySvc.GetSlots(c.Request.Context(), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, types.ApiResponse{
+		StatusCode: http.StatusOK,
+		Data:       res,
+	})
+}
+
+func (h *serviceProviderAvailabilityImpl) ProviderGetWorkingHours(c *gin.Context) {
+	var req types.ServiceProviderWorkingHourProviderGetAllReq
+	if err := h.authMw.BindWithRequest(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	res, err := h.serviceProviderAvailabilitySvc.ProviderGetWorkingHours(c.Request.Context(), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, types.ApiResponse{
+		StatusCode: http.StatusOK,
+		Data:       res,
+	})
+}
+
+func (h *serviceProviderAvailabilityImpl) ProviderUpdateWorkingHours(c *gin.Context) {
+	var req types.ServiceProviderWorkingHourProviderUpdateReq
+	if err := h.authMw.BindWithRequest(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := h.serviceProviderAvailabilitySvc.ProviderUpdateWorkingHours(c.Request.Context(), req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, types.ApiResponse{
+		StatusCode: http.StatusOK,
+	})
+}
+
+func (h *serviceProviderAvailabilityImpl) ProviderGetAllDaysOff(c *gin.Context) {
+	var req types.ServiceProviderDayOffProviderGetAllReq
+	if err := h.authMw.BindWithRequest(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	res, err := h.serviceProviderAvailabilitySvc.ProviderGetAllDaysOff(c.Request.Context(), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, types.ApiResponse{
+		StatusCode: http.StatusOK,
+		Data:       res,
+	})
+}
+
+func (h *serviceProviderAvailabilityImpl) ProviderCreateDayOff(c *gin.Context) {
+	var req types.ServiceProviderDayOffProviderCreateReq
+	if err := h.authMw.BindWithRequest(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := h.serviceProviderAvailabilitySvc.ProviderCreateDayOff(c.Request.Context(), req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, types.ApiResponse{
+		StatusCode: http.StatusCreated,
+	})
+}
+
+func (h *serviceProviderAvailabilityImpl) ProviderDeleteDayOff(c *gin.Context) {
+	var req types.ServiceProviderDayOffProviderDeleteReq
+	if err := req.ID.UnmarshalText([]byte(c.Param("id"))); err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := h.authMw.BindWithRequest(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := h.serviceProviderAvailabilitySvc.ProviderDeleteDayOff(c.Request.Context(), req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, types.ApiResponse{
+		StatusCode: http.StatusOK,
+	})
+}
+
+func (h *serviceProviderAvailabilityImpl) ProviderUpdateService(c *gin.Context) {
+	var req types.ServiceAvailabilityProviderUpdateReq
+	if err := req.ID.UnmarshalText([]byte(c.Param("id"))); err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := h.authMw.BindWithRequest(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := h.serviceProviderAvailabilitySvc.ProviderUpdateService(c.Request.Context(), req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, types.ApiResponse{
+		StatusCode: http.StatusOK,
+	})
+}
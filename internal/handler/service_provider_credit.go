@@ -0,0 +1,257 @@
+package handler
+
+import (
+	"kelarin/internal/middleware"
+	"kelarin/internal/service"
+	"kelarin/internal/types"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ServiceProviderCredit interface {
+	ProviderGet(c *gin.Context)
+	ProviderGetLedgers(c *gin.Context)
+	ProviderGetAllBankAccounts(c *gin.Context)
+	ProviderCreateBankAccount(c *gin.Context)
+	ProviderDeleteBankAccount(c *gin.Context)
+	ProviderGetAllPayouts(c *gin.Context)
+	ProviderCreatePayout(c *gin.Context)
+
+	AdminGetAllPayouts(c *gin.Context)
+	AdminApprovePayout(c *gin.Context)
+	AdminRejectPayout(c *gin.Context)
+	AdminMarkPayoutAsPaid(c *gin.Context)
+}
+
+type serviceProviderCreditImpl struct {
+	serviceProviderCreditSvc service.ServiceProviderCredit
+	authMw                   middleware.Auth
+}
+
+func NewServiceProviderCredit(serviceProviderCreditSvc service.ServiceProviderCredit, authMw middleware.Auth) ServiceProviderCredit {
+	return &serviceProviderCreditImpl{
+		serviceProviderCreditSvc: serviceProviderCreditSvc,
+		authMw:                   authMw,
+	}
+}
+
+func (h *serviceProviderCreditImpl) ProviderGet(c *gin.Context) {
+	var req types.ServiceProviderCreditProviderGetReq
+	if err := h.authMw.BindWithRequest(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	res, err := h.serviceProviderCreditSvc.ProviderGet(c.Request.Context(), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, types.ApiResponse{
+		StatusCode: http.StatusOK,
+		Data:       res,
+	})
+}
+
+func (h *serviceProviderCreditImpl) ProviderGetLedgers(c *gin.Context) {
+	var req types.ServiceProviderCreditProviderGetLedgersReq
+	if err := h.authMw.BindWithRequest(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	res, paginationRes, err := h.serviceProviderCreditSvc.ProviderGetLedgers(c.Request.Context(), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, types.ApiResponse{
+		StatusCode: http.StatusOK,
+		Data:       res,
+		Pagination: &paginationRes,
+	})
+}
+
+func (h *serviceProviderCreditImpl) ProviderGetAllBankAccounts(c *gin.Context) {
+	var req types.ServiceProviderBankAccountProviderGetAllReq
+	if err := h.authMw.BindWithRequest(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	res, err := h.serviceProviderCreditSvc.ProviderGetAllBankAccounts(c.Request.Context(), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, types.ApiResponse{
+		StatusCode: http.StatusOK,
+		Data:       res,
+	})
+}
+
+func (h *serviceProviderCreditImpl) ProviderCreateBankAccount(c *gin.Context) {
+	var req types.ServiceProviderBankAccountProviderCreateReq
+	if err := h.authMw.BindWithRequest(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := h.serviceProviderCreditSvc.ProviderCreateBankAccount(c.Request.Context(), req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, types.ApiResponse{
+		StatusCode: http.StatusCreated,
+	})
+}
+
+func (h *serviceProviderCreditImpl) ProviderDeleteBankAccount(c *gin.Context) {
+	var req types.ServiceProviderBankAccountProviderDeleteReq
+	if err := req.ID.UnmarshalText([]byte(c.Param("id"))); err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := h.authMw.BindWithRequest(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := h.serviceProviderCreditSvc.ProviderDeleteBankAccount(c.Request.Context(), req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, types.ApiResponse{
+		StatusCode: http.StatusOK,
+	})
+}
+
+func (h *serviceProviderCreditImpl) ProviderGetAllPayouts(c *gin.Context) {
+	var req types.PayoutProviderGetAllReq
+	if err := h.authMw.BindWithRequest(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	res, paginationRes, err := h.serviceProviderCreditSvc.ProviderGetAllPayouts(c.Request.Context(), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, types.ApiResponse{
+		StatusCode: http.StatusOK,
+		Data:       res,
+		Pagination: &paginationRes,
+	})
+}
+
+func (h *serviceProviderCreditImpl) ProviderCreatePayout(c *gin.Context) {
+	var req types.PayoutProviderCreateReq
+	if err := h.authMw.BindWithRequest(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := h.serviceProviderCreditSvc.ProviderCreatePayout(c.Request.Context(), req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, types.ApiResponse{
+		StatusCode: http.StatusCreated,
+	})
+}
+
+func (h *serviceProviderCreditImpl) AdminGetAllPayouts(c *gin.Context) {
+	var req types.PayoutAdminGetAllReq
+	if err := h.authMw.BindWithRequest(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	res, paginationRes, err := h.serviceProviderCreditSvc.AdminGetAllPayouts(c.Request.Context(), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, types.ApiResponse{
+		StatusCode: http.StatusOK,
+		Data:       res,
+		Pagination: &paginationRes,
+	})
+}
+
+func (h *serviceProviderCreditImpl) AdminApprovePayout(c *gin.Context) {
+	var req types.PayoutAdminActionReq
+	if err := req.ID.UnmarshalText([]byte(c.Param("id"))); err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := h.authMw.BindWithRequest(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := h.serviceProviderCreditSvc.AdminApprovePayout(c.Request.Context(), req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, types.ApiResponse{
+		StatusCode: http.StatusOK,
+	})
+}
+
+func (h *serviceProviderCreditImpl) AdminRejectPayout(c *gin.Context) {
+	var req types.PayoutAdminActionReq
+	if err := req.ID.UnmarshalText([]byte(c.Param("id"))); err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := h.authMw.BindWithRequest(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := h.serviceProviderCreditSvc.AdminRejectPayout(c.Request.Context(), req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, types.ApiResponse{
+		StatusCode: http.StatusOK,
+	})
+}
+
+func (h *serviceProviderCreditImpl) AdminMarkPayoutAsPaid(c *gin.Context) {
+	var req types.PayoutAdminActionReq
+	if err := req.ID.UnmarshalText([]byte(c.Param("id"))); err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := h.authMw.BindWithRequest(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := h.serviceProviderCreditSvc.AdminMarkPayoutAsPaid(c.Request.Context(), req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, types.ApiResponse{
+		StatusCode: http.StatusOK,
+	})
+}
@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"kelarin/internal/middleware"
 	"kelarin/internal/service"
 	"kelarin/internal/types"
 	"net/http"
@@ -10,15 +11,25 @@ import (
 
 type User interface {
 	GetOne(c *gin.Context)
+
+	AdminGetAll(c *gin.Context)
+	AdminGetByID(c *gin.Context)
+	AdminSuspend(c *gin.Context)
+	AdminUnsuspend(c *gin.Context)
+	AdminBan(c *gin.Context)
+	AdminUnban(c *gin.Context)
+	AdminRevokeSessions(c *gin.Context)
 }
 
 type userImpl struct {
 	userSvc service.User
+	authMw  middleware.Auth
 }
 
-func NewUser(userSvc service.User) User {
+func NewUser(userSvc service.User, authMw middleware.Auth) User {
 	return &userImpl{
 		userSvc: userSvc,
+		authMw:  authMw,
 	}
 }
 
@@ -34,3 +45,157 @@ func (h *userImpl) GetOne(c *gin.Context) {
 		Message:    http.StatusText(http.StatusOK),
 	})
 }
+
+func (h *userImpl) AdminGetAll(c *gin.Context) {
+	var req types.UserAdminGetAllReq
+	if err := h.authMw.BindWithRequest(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	res, paginationRes, err := h.userSvc.AdminGetAll(c.Request.Context(), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, types.ApiResponse{
+		StatusCode: http.StatusOK,
+		Data:       res,
+		Pagination: &paginationRes,
+	})
+}
+
+func (h *userImpl) AdminGetByID(c *gin.Context) {
+	var req types.UserAdminGetByIDReq
+	if err := req.ID.UnmarshalText([]byte(c.Param("id"))); err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := h.authMw.BindWithRequest(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	res, err := h.userSvc.AdminGetByID(c.Request.Context(), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, types.ApiResponse{
+		StatusCode: http.StatusOK,
+		Data:       res,
+	})
+}
+
+func (h *userImpl) AdminSuspend(c *gin.Context) {
+	var req types.UserAdminSuspendReq
+	if err := req.ID.UnmarshalText([]byte(c.Param("id"))); err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := h.authMw.BindWithRequest(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := h.userSvc.AdminSuspend(c.Request.Context(), req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, types.ApiResponse{
+		StatusCode: http.StatusOK,
+	})
+}
+
+func (h *userImpl) AdminUnsuspend(c *gin.Context) {
+	var req types.UserAdminModerateReq
+	if err := req.ID.UnmarshalText([]byte(c.Param("id"))); err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := h.authMw.BindWithRequest(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := h.userSvc.AdminUnsuspend(c.Request.Context(), req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, types.ApiResponse{
+		StatusCode: http.StatusOK,
+	})
+}
+
+func (h *userImpl) AdminBan(c *gin.Context) {
+	var req types.UserAdminModerateReq
+	if err := req.ID.UnmarshalText([]byte(c.Param("id"))); err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := h.authMw.BindWithRequest(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := h.userSvc.AdminBan(c.Request.Context(), req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, types.ApiResponse{
+		StatusCode: http.StatusOK,
+	})
+}
+
+func (h *userImpl) AdminUnban(c *gin.Context) {
+	var req types.UserAdminModerateReq
+	if err := req.ID.UnmarshalText([]byte(c.Param("id"))); err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := h.authMw.BindWithRequest(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := h.userSvc.AdminUnban(c.Request.Context(), req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, types.ApiResponse{
+		StatusCode: http.StatusOK,
+	})
+}
+
+func (h *userImpl) AdminRevokeSessions(c *gin.Context) {
+	var req types.UserAdminModerateReq
+	if err := req.ID.UnmarshalText([]byte(c.Param("id"))); err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := h.authMw.BindWithRequest(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := h.userSvc.AdminRevokeSessions(c.Request.Context(), req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, types.ApiResponse{
+		StatusCode: http.StatusOK,
+	})
+}
@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"reflect"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-errors/errors"
@@ -17,20 +18,33 @@ import (
 
 type Auth interface {
 	Authenticated(c *gin.Context)
+	Session(c *gin.Context)
+	Admin(c *gin.Context)
 	Consumer(c *gin.Context)
 	ServiceProvider(c *gin.Context)
 	NonAdmin(c *gin.Context)
+	Permission(permission types.AdminPermission) gin.HandlerFunc
+	Optional(c *gin.Context)
 	BindWithRequest(c *gin.Context, req any) error
 	WS(c *gin.Context)
 }
 
 type authImpl struct {
-	config      *config.Config
-	sessionRepo repository.Session
+	config                   *config.Config
+	sessionRepo              repository.Session
+	userBlocklistRepo        repository.UserBlocklist
+	adminRoleRepo            repository.AdminRole
+	adminPermissionCacheRepo repository.AdminPermissionCache
 }
 
-func NewAuth(config *config.Config, sessionRepo repository.Session) Auth {
-	return &authImpl{config: config, sessionRepo: sessionRepo}
+func NewAuth(config *config.Config, sessionRepo repository.Session, userBlocklistRepo repository.UserBlocklist, adminRoleRepo repository.AdminRole, adminPermissionCacheRepo repository.AdminPermissionCache) Auth {
+	return &authImpl{
+		config:                   config,
+		sessionRepo:              sessionRepo,
+		userBlocklistRepo:        userBlocklistRepo,
+		adminRoleRepo:            adminRoleRepo,
+		adminPermissionCacheRepo: adminPermissionCacheRepo,
+	}
 }
 
 func (m *authImpl) Authenticated(c *gin.Context) {
@@ -38,6 +52,20 @@ func (m *authImpl) Authenticated(c *gin.Context) {
 	c.Next()
 }
 
+// Session allows every role like Authenticated, but rejects a session revoked from another device and a blocked user
+func (m *authImpl) Session(c *gin.Context) {
+	m.parseAuthorizationHeader(c)
+	if c.IsAborted() {
+		return
+	}
+
+	m.nextFunc(c, []types.UserRole{
+		types.UserRoleAdmin,
+		types.UserRoleConsumer,
+		types.UserRoleServiceProvider,
+	})
+}
+
 func (m *authImpl) Admin(c *gin.Context) {
 	m.parseAuthorizationHeader(c)
 	m.nextFunc(c, []types.UserRole{types.UserRoleAdmin})
@@ -61,6 +89,56 @@ func (m *authImpl) NonAdmin(c *gin.Context) {
 	})
 }
 
+// Permission allows an admin whose roles grant the permission, the permissions are cached per session
+func (m *authImpl) Permission(permission types.AdminPermission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		m.parseAuthorizationHeader(c)
+		if c.IsAborted() {
+			return
+		}
+
+		authUser, ok := m.authorize(c, []types.UserRole{types.UserRoleAdmin})
+		if !ok {
+			return
+		}
+
+		permissions, err := m.findAdminPermissions(c, authUser)
+		if err != nil {
+			log.Error().Stack().Err(err).Send()
+			c.Error(errors.New(types.AppErr{Code: http.StatusInternalServerError}))
+			c.Abort()
+			return
+		}
+
+		if !types.HasAdminPermission(permissions, permission) {
+			c.Error(types.ErrAdminPermissionDenied)
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// Optional authenticates the user only when the authorization header is sent, used by public routes that have extra behavior for logged in users
+func (m *authImpl) Optional(c *gin.Context) {
+	if c.GetHeader("Authorization") == "" {
+		c.Next()
+		return
+	}
+
+	m.parseAuthorizationHeader(c)
+	if c.IsAborted() {
+		return
+	}
+
+	m.nextFunc(c, []types.UserRole{
+		types.UserRoleAdmin,
+		types.UserRoleConsumer,
+		types.UserRoleServiceProvider,
+	})
+}
+
 func (m *authImpl) parseAuthorizationHeader(c *gin.Context) {
 	accToken, err := getTokenFromHeader(c)
 	if err != nil {
@@ -84,18 +162,28 @@ func (m *authImpl) parseAuthorizationHeader(c *gin.Context) {
 		Role:                   claims.Role,
 		Name:                   claims.Name,
 		IncompleteRegistration: claims.IncompleteRegistration,
+		IssuedAt:               getTokenIssuedAt(claims),
 	}
 
 	c.Set(types.AuthUserContextKey, authUser)
 }
 
 func (m *authImpl) nextFunc(c *gin.Context, roles []types.UserRole) {
+	if _, ok := m.authorize(c, roles); !ok {
+		return
+	}
+
+	c.Next()
+}
+
+// authorize checks the session, the blocklist and the role of the user, the request is aborted when it reports false
+func (m *authImpl) authorize(c *gin.Context, roles []types.UserRole) (types.AuthUser, bool) {
 	userContext, exists := c.Get(types.AuthUserContextKey)
 	if !exists {
 		log.Error().Stack().Err(errors.New("missing user context")).Send()
 		c.Error(errors.New(types.AppErr{Code: http.StatusUnauthorized}))
 		c.Abort()
-		return
+		return types.AuthUser{}, false
 	}
 
 	authUser, ok := userContext.(types.AuthUser)
@@ -103,7 +191,7 @@ func (m *authImpl) nextFunc(c *gin.Context, roles []types.UserRole) {
 		log.Error().Stack().Err(errors.New("invalid user context")).Send()
 		c.Error(errors.New(types.AppErr{Code: http.StatusUnauthorized}))
 		c.Abort()
-		return
+		return types.AuthUser{}, false
 	}
 
 	key := types.GetSessionKey(authUser.SessionID.String())
@@ -111,22 +199,89 @@ func (m *authImpl) nextFunc(c *gin.Context, roles []types.UserRole) {
 	if errors.Is(err, types.ErrNoData) {
 		c.Error(errors.New(types.AppErr{Code: http.StatusUnauthorized, Message: "You have been logged out"}))
 		c.Abort()
-		return
+		return authUser, false
 	} else if err != nil {
 		log.Error().Stack().Err(err).Send()
 		c.Error(errors.New(types.AppErr{Code: http.StatusUnauthorized}))
 		c.Abort()
-		return
+		return authUser, false
+	}
+
+	if err := m.checkBlocklist(c, authUser); err != nil {
+		c.Error(err)
+		c.Abort()
+		return authUser, false
 	}
 
 	if !lo.Contains(roles, authUser.Role) {
 		log.Error().Stack().Err(errors.New("invalid user role")).Send()
 		c.Error(errors.New(types.AppErr{Code: http.StatusForbidden}))
 		c.Abort()
-		return
+		return authUser, false
 	}
 
-	c.Next()
+	return authUser, true
+}
+
+// findAdminPermissions reads the permissions of the session from the cache, a miss loads them from the roles of the admin.
+// The version is read before the roles, a role change committed after that read makes the cache drop the loaded permissions
+func (m *authImpl) findAdminPermissions(c *gin.Context, authUser types.AuthUser) ([]string, error) {
+	permissions, err := m.adminPermissionCacheRepo.Find(c, authUser.ID, authUser.SessionID)
+	if err == nil {
+		return permissions, nil
+	} else if !errors.Is(err, types.ErrNoData) {
+		log.Error().Stack().Err(err).Send()
+	}
+
+	version, err := m.adminPermissionCacheRepo.FindVersion(c, authUser.ID)
+	if err != nil {
+		// the permissions can not be cached without the version, they are only loaded for this request
+		log.Error().Stack().Err(err).Send()
+		return m.adminRoleRepo.FindPermissionsByUserID(c, authUser.ID)
+	}
+
+	permissions, err = m.adminRoleRepo.FindPermissionsByUserID(c, authUser.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = m.adminPermissionCacheRepo.Set(c, authUser.ID, authUser.SessionID, version, permissions, types.AdminPermissionCacheExpiration); err != nil {
+		log.Error().Stack().Err(err).Send()
+	}
+
+	return permissions, nil
+}
+
+// checkBlocklist rejects a suspended or banned user whose access token was issued before the moderation action,
+// a token without an issue time is always rejected
+func (m *authImpl) checkBlocklist(c *gin.Context, authUser types.AuthUser) error {
+	entry, err := m.userBlocklistRepo.Find(c, authUser.ID)
+	if errors.Is(err, types.ErrNoData) {
+		return nil
+	} else if err != nil {
+		log.Error().Stack().Err(err).Send()
+		return errors.New(types.AppErr{Code: http.StatusUnauthorized})
+	}
+
+	// the issue time has a precision of a second, a token issued in the same second as the action is rejected
+	if authUser.IssuedAt.After(entry.BlockedAt) {
+		return nil
+	}
+
+	switch entry.Status {
+	case types.UserStatusBanned:
+		return errors.New(types.AppErr{Code: http.StatusUnauthorized, Message: "your account is banned"})
+	default:
+		return errors.New(types.AppErr{Code: http.StatusUnauthorized, Message: "your account is suspended"})
+	}
+}
+
+func getTokenIssuedAt(claims *types.AuthJwtCustomClaims) time.Time {
+	if claims.IssuedAt == nil {
+		return time.Time{}
+	}
+
+	return claims.IssuedAt.Time
 }
 
 func getTokenFromHeader(c *gin.Context) (string, error) {
@@ -238,6 +393,13 @@ func (m *authImpl) WS(c *gin.Context) {
 		Role:                   claims.Role,
 		Name:                   claims.Name,
 		IncompleteRegistration: claims.IncompleteRegistration,
+		IssuedAt:               getTokenIssuedAt(claims),
+	}
+
+	if err := m.checkBlocklist(c, authUser); err != nil {
+		c.JSON(http.StatusUnauthorized, types.ApiResponse{StatusCode: http.StatusUnauthorized})
+		c.Abort()
+		return
 	}
 
 	c.Set(types.AuthUserContextKey, authUser)
@@ -0,0 +1,217 @@
+package middleware_test
+
+import (
+	"kelarin/internal/config"
+	"kelarin/internal/middleware"
+	repoMock "kelarin/internal/mocks/repository"
+	"kelarin/internal/types"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-errors/errors"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAuthPermission(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	cfg := &config.Config{JWT: config.JWTConfig{SecretKey: "c2VjcmV0"}}
+	authUser := types.AuthUser{
+		ID:        uuid.New(),
+		SessionID: uuid.New(),
+		Role:      types.UserRoleAdmin,
+	}
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, types.AuthJwtCustomClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+		ID:      authUser.SessionID,
+		Subject: authUser.ID,
+		Role:    authUser.Role,
+	}).SignedString([]byte(cfg.JWT.SecretKey))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when signing the token", err)
+	}
+
+	tests := []struct {
+		name       string
+		permission types.AdminPermission
+		setup      func(adminRoleRepo *repoMock.AdminRole, adminPermissionCacheRepo *repoMock.AdminPermissionCache)
+		expectCode int
+	}{
+		{
+			name:       "cached permission is granted without loading the roles",
+			permission: types.AdminPermissionUsersRead,
+			setup: func(adminRoleRepo *repoMock.AdminRole, adminPermissionCacheRepo *repoMock.AdminPermissionCache) {
+				adminPermissionCacheRepo.Mock.On("Find", mock.Anything, authUser.ID, authUser.SessionID).Return([]string{string(types.AdminPermissionUsersRead)}, nil)
+			},
+			expectCode: http.StatusOK,
+		},
+		{
+			name:       "cache miss loads the roles and caches them with the version read before",
+			permission: types.AdminPermissionUsersRead,
+			setup: func(adminRoleRepo *repoMock.AdminRole, adminPermissionCacheRepo *repoMock.AdminPermissionCache) {
+				permissions := []string{string(types.AdminPermissionUsersRead)}
+				adminPermissionCacheRepo.Mock.On("Find", mock.Anything, authUser.ID, authUser.SessionID).Return([]string{}, errors.New(types.ErrNoData))
+				adminPermissionCacheRepo.Mock.On("FindVersion", mock.Anything, authUser.ID).Return(int64(3), nil)
+				adminRoleRepo.Mock.On("FindPermissionsByUserID", mock.Anything, authUser.ID).Return(permissions, nil)
+				adminPermissionCacheRepo.Mock.On("Set", mock.Anything, authUser.ID, authUser.SessionID, int64(3), permissions, types.AdminPermissionCacheExpiration).Return(nil)
+			},
+			expectCode: http.StatusOK,
+		},
+		{
+			name:       "permissions are not cached when the version can not be read",
+			permission: types.AdminPermissionUsersRead,
+			setup: func(adminRoleRepo *repoMock.AdminRole, adminPermissionCacheRepo *repoMock.AdminPermissionCache) {
+				adminPermissionCacheRepo.Mock.On("Find", mock.Anything, authUser.ID, authUser.SessionID).Return([]string{}, errors.New(types.ErrNoData))
+				adminPermissionCacheRepo.Mock.On("FindVersion", mock.Anything, authUser.ID).Return(int64(0), errors.New("redis is down"))
+				adminRoleRepo.Mock.On("FindPermissionsByUserID", mock.Anything, authUser.ID).Return([]string{string(types.AdminPermissionUsersRead)}, nil)
+			},
+			expectCode: http.StatusOK,
+		},
+		{
+			name:       "missing permission is denied",
+			permission: types.AdminPermissionUsersBan,
+			setup: func(adminRoleRepo *repoMock.AdminRole, adminPermissionCacheRepo *repoMock.AdminPermissionCache) {
+				adminPermissionCacheRepo.Mock.On("Find", mock.Anything, authUser.ID, authUser.SessionID).Return([]string{string(types.AdminPermissionUsersRead)}, nil)
+			},
+			expectCode: http.StatusForbidden,
+		},
+		{
+			name:       "wildcard grants every permission",
+			permission: types.AdminPermissionRolesManage,
+			setup: func(adminRoleRepo *repoMock.AdminRole, adminPermissionCacheRepo *repoMock.AdminPermissionCache) {
+				adminPermissionCacheRepo.Mock.On("Find", mock.Anything, authUser.ID, authUser.SessionID).Return([]string{string(types.AdminPermissionAll)}, nil)
+			},
+			expectCode: http.StatusOK,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			sessionRepo := repoMock.NewSession(t)
+			userBlocklistRepo := repoMock.NewUserBlocklist(t)
+			adminRoleRepo := repoMock.NewAdminRole(t)
+			adminPermissionCacheRepo := repoMock.NewAdminPermissionCache(t)
+
+			sessionRepo.Mock.On("Find", mock.Anything, types.GetSessionKey(authUser.SessionID.String())).Return(authUser.ID.String(), nil)
+			userBlocklistRepo.Mock.On("Find", mock.Anything, authUser.ID).Return(types.UserBlocklistEntry{}, errors.New(types.ErrNoData))
+			test.setup(adminRoleRepo, adminPermissionCacheRepo)
+
+			auth := middleware.NewAuth(cfg, sessionRepo, userBlocklistRepo, adminRoleRepo, adminPermissionCacheRepo)
+
+			router := gin.New()
+			router.Use(middleware.HttpErrorHandler)
+			router.GET("/", auth.Permission(test.permission), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set("Authorization", "Bearer "+token)
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, test.expectCode, w.Code)
+		})
+	}
+}
+
+func TestAuthBlocklist(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	cfg := &config.Config{JWT: config.JWTConfig{SecretKey: "c2VjcmV0"}}
+	userID := uuid.New()
+	sessionID := uuid.New()
+	blockedAt := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name       string
+		issuedAt   *jwt.NumericDate
+		entry      types.UserBlocklistEntry
+		findErr    error
+		expectCode int
+	}{
+		{
+			name:       "user that is not blocked is allowed",
+			issuedAt:   jwt.NewNumericDate(blockedAt.Add(-time.Minute)),
+			findErr:    errors.New(types.ErrNoData),
+			expectCode: http.StatusOK,
+		},
+		{
+			name:       "token issued before the suspension is rejected",
+			issuedAt:   jwt.NewNumericDate(blockedAt.Add(-time.Minute)),
+			entry:      types.UserBlocklistEntry{Status: types.UserStatusSuspended, BlockedAt: blockedAt},
+			expectCode: http.StatusUnauthorized,
+		},
+		{
+			name:       "token issued before the ban is rejected",
+			issuedAt:   jwt.NewNumericDate(blockedAt.Add(-time.Minute)),
+			entry:      types.UserBlocklistEntry{Status: types.UserStatusBanned, BlockedAt: blockedAt},
+			expectCode: http.StatusUnauthorized,
+		},
+		{
+			name:       "token issued in the same second as the action is rejected",
+			issuedAt:   jwt.NewNumericDate(blockedAt),
+			entry:      types.UserBlocklistEntry{Status: types.UserStatusSuspended, BlockedAt: blockedAt},
+			expectCode: http.StatusUnauthorized,
+		},
+		{
+			name:       "token without an issue time is rejected",
+			entry:      types.UserBlocklistEntry{Status: types.UserStatusSuspended, BlockedAt: blockedAt},
+			expectCode: http.StatusUnauthorized,
+		},
+		{
+			name:       "token issued after the action is allowed",
+			issuedAt:   jwt.NewNumericDate(blockedAt.Add(time.Minute)),
+			entry:      types.UserBlocklistEntry{Status: types.UserStatusSuspended, BlockedAt: blockedAt},
+			expectCode: http.StatusOK,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			sessionRepo := repoMock.NewSession(t)
+			userBlocklistRepo := repoMock.NewUserBlocklist(t)
+
+			sessionRepo.Mock.On("Find", mock.Anything, types.GetSessionKey(sessionID.String())).Return(userID.String(), nil)
+			userBlocklistRepo.Mock.On("Find", mock.Anything, userID).Return(test.entry, test.findErr)
+
+			auth := middleware.NewAuth(cfg, sessionRepo, userBlocklistRepo, repoMock.NewAdminRole(t), repoMock.NewAdminPermissionCache(t))
+
+			token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, types.AuthJwtCustomClaims{
+				RegisteredClaims: jwt.RegisteredClaims{
+					IssuedAt:  test.issuedAt,
+					ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+				},
+				ID:      sessionID,
+				Subject: userID,
+				Role:    types.UserRoleConsumer,
+			}).SignedString([]byte(cfg.JWT.SecretKey))
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when signing the token", err)
+			}
+
+			router := gin.New()
+			router.Use(middleware.HttpErrorHandler)
+			router.GET("/", auth.Session, func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set("Authorization", "Bearer "+token)
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, test.expectCode, w.Code)
+		})
+	}
+}
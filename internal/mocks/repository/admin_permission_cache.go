@@ -0,0 +1,133 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+
+	uuid "github.com/google/uuid"
+)
+
+// AdminPermissionCache is an autogenerated mock type for the AdminPermissionCache type
+type AdminPermissionCache struct {
+	mock.Mock
+}
+
+// Find provides a mock function with given fields: ctx, userID, sessionID
+func (_m *AdminPermissionCache) Find(ctx context.Context, userID uuid.UUID, sessionID uuid.UUID) ([]string, error) {
+	ret := _m.Called(ctx, userID, sessionID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Find")
+	}
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID) ([]string, error)); ok {
+		return rf(ctx, userID, sessionID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID) []string); ok {
+		r0 = rf(ctx, userID, sessionID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, uuid.UUID) error); ok {
+		r1 = rf(ctx, userID, sessionID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindVersion provides a mock function with given fields: ctx, userID
+func (_m *AdminPermissionCache) FindVersion(ctx context.Context, userID uuid.UUID) (int64, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindVersion")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (int64, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) int64); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Invalidate provides a mock function with given fields: ctx, expiration, userIDs
+func (_m *AdminPermissionCache) Invalidate(ctx context.Context, expiration time.Duration, userIDs ...uuid.UUID) error {
+	_va := make([]interface{}, len(userIDs))
+	for _i := range userIDs {
+		_va[_i] = userIDs[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, expiration)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Invalidate")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Duration, ...uuid.UUID) error); ok {
+		r0 = rf(ctx, expiration, userIDs...)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Set provides a mock function with given fields: ctx, userID, sessionID, version, permissions, expiration
+func (_m *AdminPermissionCache) Set(ctx context.Context, userID uuid.UUID, sessionID uuid.UUID, version int64, permissions []string, expiration time.Duration) error {
+	ret := _m.Called(ctx, userID, sessionID, version, permissions, expiration)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Set")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID, int64, []string, time.Duration) error); ok {
+		r0 = rf(ctx, userID, sessionID, version, permissions, expiration)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewAdminPermissionCache creates a new instance of AdminPermissionCache. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewAdminPermissionCache(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *AdminPermissionCache {
+	mock := &AdminPermissionCache{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
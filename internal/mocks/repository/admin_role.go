@@ -0,0 +1,329 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	dbUtil "kelarin/internal/utils/dbutil"
+
+	mock "github.com/stretchr/testify/mock"
+
+	types "kelarin/internal/types"
+
+	uuid "github.com/google/uuid"
+)
+
+// AdminRole is an autogenerated mock type for the AdminRole type
+type AdminRole struct {
+	mock.Mock
+}
+
+// CreateTx provides a mock function with given fields: ctx, _tx, role
+func (_m *AdminRole) CreateTx(ctx context.Context, _tx dbUtil.Tx, role types.AdminRole) error {
+	ret := _m.Called(ctx, _tx, role)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateTx")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, dbUtil.Tx, types.AdminRole) error); ok {
+		r0 = rf(ctx, _tx, role)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteTx provides a mock function with given fields: ctx, _tx, ID
+func (_m *AdminRole) DeleteTx(ctx context.Context, _tx dbUtil.Tx, ID uuid.UUID) error {
+	ret := _m.Called(ctx, _tx, ID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteTx")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, dbUtil.Tx, uuid.UUID) error); ok {
+		r0 = rf(ctx, _tx, ID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FindAll provides a mock function with given fields: ctx
+func (_m *AdminRole) FindAll(ctx context.Context) ([]types.AdminRole, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindAll")
+	}
+
+	var r0 []types.AdminRole
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]types.AdminRole, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []types.AdminRole); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]types.AdminRole)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindAllByUserID provides a mock function with given fields: ctx, userID
+func (_m *AdminRole) FindAllByUserID(ctx context.Context, userID uuid.UUID) ([]types.AdminRole, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindAllByUserID")
+	}
+
+	var r0 []types.AdminRole
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]types.AdminRole, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) []types.AdminRole); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]types.AdminRole)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindByID provides a mock function with given fields: ctx, ID
+func (_m *AdminRole) FindByID(ctx context.Context, ID uuid.UUID) (types.AdminRole, error) {
+	ret := _m.Called(ctx, ID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindByID")
+	}
+
+	var r0 types.AdminRole
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (types.AdminRole, error)); ok {
+		return rf(ctx, ID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) types.AdminRole); ok {
+		r0 = rf(ctx, ID)
+	} else {
+		r0 = ret.Get(0).(types.AdminRole)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, ID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindByIDs provides a mock function with given fields: ctx, IDs
+func (_m *AdminRole) FindByIDs(ctx context.Context, IDs uuid.UUIDs) ([]types.AdminRole, error) {
+	ret := _m.Called(ctx, IDs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindByIDs")
+	}
+
+	var r0 []types.AdminRole
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUIDs) ([]types.AdminRole, error)); ok {
+		return rf(ctx, IDs)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUIDs) []types.AdminRole); ok {
+		r0 = rf(ctx, IDs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]types.AdminRole)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUIDs) error); ok {
+		r1 = rf(ctx, IDs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindForUpdateByID provides a mock function with given fields: ctx, _tx, ID
+func (_m *AdminRole) FindForUpdateByID(ctx context.Context, _tx dbUtil.Tx, ID uuid.UUID) (types.AdminRole, error) {
+	ret := _m.Called(ctx, _tx, ID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindForUpdateByID")
+	}
+
+	var r0 types.AdminRole
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, dbUtil.Tx, uuid.UUID) (types.AdminRole, error)); ok {
+		return rf(ctx, _tx, ID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, dbUtil.Tx, uuid.UUID) types.AdminRole); ok {
+		r0 = rf(ctx, _tx, ID)
+	} else {
+		r0 = ret.Get(0).(types.AdminRole)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, dbUtil.Tx, uuid.UUID) error); ok {
+		r1 = rf(ctx, _tx, ID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindPermissionsByUserID provides a mock function with given fields: ctx, userID
+func (_m *AdminRole) FindPermissionsByUserID(ctx context.Context, userID uuid.UUID) ([]string, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindPermissionsByUserID")
+	}
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]string, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) []string); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindUserIDsByRoleIDTx provides a mock function with given fields: ctx, _tx, roleID
+func (_m *AdminRole) FindUserIDsByRoleIDTx(ctx context.Context, _tx dbUtil.Tx, roleID uuid.UUID) (uuid.UUIDs, error) {
+	ret := _m.Called(ctx, _tx, roleID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindUserIDsByRoleIDTx")
+	}
+
+	var r0 uuid.UUIDs
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, dbUtil.Tx, uuid.UUID) (uuid.UUIDs, error)); ok {
+		return rf(ctx, _tx, roleID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, dbUtil.Tx, uuid.UUID) uuid.UUIDs); ok {
+		r0 = rf(ctx, _tx, roleID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(uuid.UUIDs)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, dbUtil.Tx, uuid.UUID) error); ok {
+		r1 = rf(ctx, _tx, roleID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReplacePermissionsTx provides a mock function with given fields: ctx, _tx, roleID, permissions
+func (_m *AdminRole) ReplacePermissionsTx(ctx context.Context, _tx dbUtil.Tx, roleID uuid.UUID, permissions []string) error {
+	ret := _m.Called(ctx, _tx, roleID, permissions)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReplacePermissionsTx")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, dbUtil.Tx, uuid.UUID, []string) error); ok {
+		r0 = rf(ctx, _tx, roleID, permissions)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ReplaceUserRolesTx provides a mock function with given fields: ctx, _tx, userID, roleIDs
+func (_m *AdminRole) ReplaceUserRolesTx(ctx context.Context, _tx dbUtil.Tx, userID uuid.UUID, roleIDs uuid.UUIDs) error {
+	ret := _m.Called(ctx, _tx, userID, roleIDs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReplaceUserRolesTx")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, dbUtil.Tx, uuid.UUID, uuid.UUIDs) error); ok {
+		r0 = rf(ctx, _tx, userID, roleIDs)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateTx provides a mock function with given fields: ctx, _tx, role
+func (_m *AdminRole) UpdateTx(ctx context.Context, _tx dbUtil.Tx, role types.AdminRole) error {
+	ret := _m.Called(ctx, _tx, role)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateTx")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, dbUtil.Tx, types.AdminRole) error); ok {
+		r0 = rf(ctx, _tx, role)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewAdminRole creates a new instance of AdminRole. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewAdminRole(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *AdminRole {
+	mock := &AdminRole{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
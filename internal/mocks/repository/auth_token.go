@@ -0,0 +1,190 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+
+	uuid "github.com/google/uuid"
+)
+
+// AuthToken is an autogenerated mock type for the AuthToken type
+type AuthToken struct {
+	mock.Mock
+}
+
+// AcquireCooldown provides a mock function with given fields: ctx, key, interval
+func (_m *AuthToken) AcquireCooldown(ctx context.Context, key string, interval time.Duration) (bool, error) {
+	ret := _m.Called(ctx, key, interval)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AcquireCooldown")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Duration) (bool, error)); ok {
+		return rf(ctx, key, interval)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Duration) bool); ok {
+		r0 = rf(ctx, key, interval)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Duration) error); ok {
+		r1 = rf(ctx, key, interval)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Consume provides a mock function with given fields: ctx, key, tokenID
+func (_m *AuthToken) Consume(ctx context.Context, key string, tokenID uuid.UUID) (bool, error) {
+	ret := _m.Called(ctx, key, tokenID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Consume")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, uuid.UUID) (bool, error)); ok {
+		return rf(ctx, key, tokenID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, uuid.UUID) bool); ok {
+		r0 = rf(ctx, key, tokenID)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, uuid.UUID) error); ok {
+		r1 = rf(ctx, key, tokenID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// IncrAttempts provides a mock function with given fields: ctx, key, expiration
+func (_m *AuthToken) IncrAttempts(ctx context.Context, key string, expiration time.Duration) (int64, error) {
+	ret := _m.Called(ctx, key, expiration)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IncrAttempts")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Duration) (int64, error)); ok {
+		return rf(ctx, key, expiration)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Duration) int64); ok {
+		r0 = rf(ctx, key, expiration)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Duration) error); ok {
+		r1 = rf(ctx, key, expiration)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// IsCurrent provides a mock function with given fields: ctx, key, tokenID
+func (_m *AuthToken) IsCurrent(ctx context.Context, key string, tokenID uuid.UUID) (bool, error) {
+	ret := _m.Called(ctx, key, tokenID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IsCurrent")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, uuid.UUID) (bool, error)); ok {
+		return rf(ctx, key, tokenID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, uuid.UUID) bool); ok {
+		r0 = rf(ctx, key, tokenID)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, uuid.UUID) error); ok {
+		r1 = rf(ctx, key, tokenID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Restore provides a mock function with given fields: ctx, key, tokenID, expiration
+func (_m *AuthToken) Restore(ctx context.Context, key string, tokenID uuid.UUID, expiration time.Duration) (bool, error) {
+	ret := _m.Called(ctx, key, tokenID, expiration)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Restore")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, uuid.UUID, time.Duration) (bool, error)); ok {
+		return rf(ctx, key, tokenID, expiration)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, uuid.UUID, time.Duration) bool); ok {
+		r0 = rf(ctx, key, tokenID, expiration)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, uuid.UUID, time.Duration) error); ok {
+		r1 = rf(ctx, key, tokenID, expiration)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Set provides a mock function with given fields: ctx, key, tokenID, expiration
+func (_m *AuthToken) Set(ctx context.Context, key string, tokenID uuid.UUID, expiration time.Duration) error {
+	ret := _m.Called(ctx, key, tokenID, expiration)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Set")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, uuid.UUID, time.Duration) error); ok {
+		r0 = rf(ctx, key, tokenID, expiration)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewAuthToken creates a new instance of AuthToken. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewAuthToken(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *AuthToken {
+	mock := &AuthToken{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
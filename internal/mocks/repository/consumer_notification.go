@@ -18,6 +18,34 @@ type ConsumerNotification struct {
 	mock.Mock
 }
 
+// CountUnreadByUserID provides a mock function with given fields: ctx, userID
+func (_m *ConsumerNotification) CountUnreadByUserID(ctx context.Context, userID uuid.UUID) (int64, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CountUnreadByUserID")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (int64, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) int64); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // CreateTx provides a mock function with given fields: ctx, tx, req
 func (_m *ConsumerNotification) CreateTx(ctx context.Context, tx dbUtil.Tx, req types.ConsumerNotification) error {
 	ret := _m.Called(ctx, tx, req)
@@ -36,9 +64,9 @@ func (_m *ConsumerNotification) CreateTx(ctx context.Context, tx dbUtil.Tx, req
 	return r0
 }
 
-// FindAllByUserID provides a mock function with given fields: ctx, userID
-func (_m *ConsumerNotification) FindAllByUserID(ctx context.Context, userID uuid.UUID) ([]types.ConsumerNotificationWithServiceProviderAndPayment, error) {
-	ret := _m.Called(ctx, userID)
+// FindAllByUserID provides a mock function with given fields: ctx, userID, filter
+func (_m *ConsumerNotification) FindAllByUserID(ctx context.Context, userID uuid.UUID, filter types.NotificationCursorFilter) ([]types.ConsumerNotificationWithServiceProviderAndPayment, error) {
+	ret := _m.Called(ctx, userID, filter)
 
 	if len(ret) == 0 {
 		panic("no return value specified for FindAllByUserID")
@@ -46,19 +74,65 @@ func (_m *ConsumerNotification) FindAllByUserID(ctx context.Context, userID uuid
 
 	var r0 []types.ConsumerNotificationWithServiceProviderAndPayment
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]types.ConsumerNotificationWithServiceProviderAndPayment, error)); ok {
-		return rf(ctx, userID)
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, types.NotificationCursorFilter) ([]types.ConsumerNotificationWithServiceProviderAndPayment, error)); ok {
+		return rf(ctx, userID, filter)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) []types.ConsumerNotificationWithServiceProviderAndPayment); ok {
-		r0 = rf(ctx, userID)
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, types.NotificationCursorFilter) []types.ConsumerNotificationWithServiceProviderAndPayment); ok {
+		r0 = rf(ctx, userID, filter)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]types.ConsumerNotificationWithServiceProviderAndPayment)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
-		r1 = rf(ctx, userID)
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, types.NotificationCursorFilter) error); ok {
+		r1 = rf(ctx, userID, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateAllAsReadByUserID provides a mock function with given fields: ctx, userID
+func (_m *ConsumerNotification) UpdateAllAsReadByUserID(ctx context.Context, userID uuid.UUID) error {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateAllAsReadByUserID")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) error); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateAsReadByIDs provides a mock function with given fields: ctx, userID, IDs
+func (_m *ConsumerNotification) UpdateAsReadByIDs(ctx context.Context, userID uuid.UUID, IDs uuid.UUIDs) (int64, error) {
+	ret := _m.Called(ctx, userID, IDs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateAsReadByIDs")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUIDs) (int64, error)); ok {
+		return rf(ctx, userID, IDs)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUIDs) int64); ok {
+		r0 = rf(ctx, userID, IDs)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, uuid.UUIDs) error); ok {
+		r1 = rf(ctx, userID, IDs)
 	} else {
 		r1 = ret.Error(1)
 	}
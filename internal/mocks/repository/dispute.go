@@ -0,0 +1,208 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	dbUtil "kelarin/internal/utils/dbutil"
+
+	mock "github.com/stretchr/testify/mock"
+
+	types "kelarin/internal/types"
+
+	uuid "github.com/google/uuid"
+)
+
+// Dispute is an autogenerated mock type for the Dispute type
+type Dispute struct {
+	mock.Mock
+}
+
+// CreateTx provides a mock function with given fields: ctx, _tx, req
+func (_m *Dispute) CreateTx(ctx context.Context, _tx dbUtil.Tx, req types.Dispute) error {
+	ret := _m.Called(ctx, _tx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateTx")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, dbUtil.Tx, types.Dispute) error); ok {
+		r0 = rf(ctx, _tx, req)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FindAllByFilter provides a mock function with given fields: ctx, filter
+func (_m *Dispute) FindAllByFilter(ctx context.Context, filter types.DisputeFilter) ([]types.DisputeWithRelations, int64, error) {
+	ret := _m.Called(ctx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindAllByFilter")
+	}
+
+	var r0 []types.DisputeWithRelations
+	var r1 int64
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, types.DisputeFilter) ([]types.DisputeWithRelations, int64, error)); ok {
+		return rf(ctx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, types.DisputeFilter) []types.DisputeWithRelations); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]types.DisputeWithRelations)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, types.DisputeFilter) int64); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, types.DisputeFilter) error); ok {
+		r2 = rf(ctx, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// FindByID provides a mock function with given fields: ctx, ID
+func (_m *Dispute) FindByID(ctx context.Context, ID uuid.UUID) (types.DisputeWithRelations, error) {
+	ret := _m.Called(ctx, ID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindByID")
+	}
+
+	var r0 types.DisputeWithRelations
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (types.DisputeWithRelations, error)); ok {
+		return rf(ctx, ID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) types.DisputeWithRelations); ok {
+		r0 = rf(ctx, ID)
+	} else {
+		r0 = ret.Get(0).(types.DisputeWithRelations)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, ID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindByOrderID provides a mock function with given fields: ctx, orderID
+func (_m *Dispute) FindByOrderID(ctx context.Context, orderID uuid.UUID) (types.Dispute, error) {
+	ret := _m.Called(ctx, orderID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindByOrderID")
+	}
+
+	var r0 types.Dispute
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (types.Dispute, error)); ok {
+		return rf(ctx, orderID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) types.Dispute); ok {
+		r0 = rf(ctx, orderID)
+	} else {
+		r0 = ret.Get(0).(types.Dispute)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, orderID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindForUpdateByID provides a mock function with given fields: ctx, _tx, ID
+func (_m *Dispute) FindForUpdateByID(ctx context.Context, _tx dbUtil.Tx, ID uuid.UUID) (types.Dispute, error) {
+	ret := _m.Called(ctx, _tx, ID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindForUpdateByID")
+	}
+
+	var r0 types.Dispute
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, dbUtil.Tx, uuid.UUID) (types.Dispute, error)); ok {
+		return rf(ctx, _tx, ID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, dbUtil.Tx, uuid.UUID) types.Dispute); ok {
+		r0 = rf(ctx, _tx, ID)
+	} else {
+		r0 = ret.Get(0).(types.Dispute)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, dbUtil.Tx, uuid.UUID) error); ok {
+		r1 = rf(ctx, _tx, ID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateResolutionTx provides a mock function with given fields: ctx, _tx, req
+func (_m *Dispute) UpdateResolutionTx(ctx context.Context, _tx dbUtil.Tx, req types.Dispute) error {
+	ret := _m.Called(ctx, _tx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateResolutionTx")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, dbUtil.Tx, types.Dispute) error); ok {
+		r0 = rf(ctx, _tx, req)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateResponseTx provides a mock function with given fields: ctx, _tx, req
+func (_m *Dispute) UpdateResponseTx(ctx context.Context, _tx dbUtil.Tx, req types.Dispute) error {
+	ret := _m.Called(ctx, _tx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateResponseTx")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, dbUtil.Tx, types.Dispute) error); ok {
+		r0 = rf(ctx, _tx, req)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewDispute creates a new instance of Dispute. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewDispute(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Dispute {
+	mock := &Dispute{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -8,6 +8,10 @@ import (
 	mock "github.com/stretchr/testify/mock"
 
 	time "time"
+
+	types "kelarin/internal/types"
+
+	uuid "github.com/google/uuid"
 )
 
 // FCMToken is an autogenerated mock type for the FCMToken type
@@ -15,27 +19,82 @@ type FCMToken struct {
 	mock.Mock
 }
 
-// Find provides a mock function with given fields: ctx, key
-func (_m *FCMToken) Find(ctx context.Context, key string) (string, error) {
-	ret := _m.Called(ctx, key)
+// DeleteBySessionIDs provides a mock function with given fields: ctx, userID, sessionIDs
+func (_m *FCMToken) DeleteBySessionIDs(ctx context.Context, userID uuid.UUID, sessionIDs ...uuid.UUID) error {
+	_va := make([]interface{}, len(sessionIDs))
+	for _i := range sessionIDs {
+		_va[_i] = sessionIDs[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, userID)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteBySessionIDs")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, ...uuid.UUID) error); ok {
+		r0 = rf(ctx, userID, sessionIDs...)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FindAllByUserID provides a mock function with given fields: ctx, userID
+func (_m *FCMToken) FindAllByUserID(ctx context.Context, userID uuid.UUID) ([]types.FCMToken, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindAllByUserID")
+	}
+
+	var r0 []types.FCMToken
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]types.FCMToken, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) []types.FCMToken); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]types.FCMToken)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindBySessionID provides a mock function with given fields: ctx, userID, sessionID
+func (_m *FCMToken) FindBySessionID(ctx context.Context, userID uuid.UUID, sessionID uuid.UUID) (types.FCMToken, error) {
+	ret := _m.Called(ctx, userID, sessionID)
 
 	if len(ret) == 0 {
-		panic("no return value specified for Find")
+		panic("no return value specified for FindBySessionID")
 	}
 
-	var r0 string
+	var r0 types.FCMToken
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) (string, error)); ok {
-		return rf(ctx, key)
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID) (types.FCMToken, error)); ok {
+		return rf(ctx, userID, sessionID)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) string); ok {
-		r0 = rf(ctx, key)
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID) types.FCMToken); ok {
+		r0 = rf(ctx, userID, sessionID)
 	} else {
-		r0 = ret.Get(0).(string)
+		r0 = ret.Get(0).(types.FCMToken)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = rf(ctx, key)
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, uuid.UUID) error); ok {
+		r1 = rf(ctx, userID, sessionID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -43,17 +102,17 @@ func (_m *FCMToken) Find(ctx context.Context, key string) (string, error) {
 	return r0, r1
 }
 
-// Save provides a mock function with given fields: ctx, key, token, expiration
-func (_m *FCMToken) Save(ctx context.Context, key string, token string, expiration time.Duration) error {
-	ret := _m.Called(ctx, key, token, expiration)
+// Save provides a mock function with given fields: ctx, userID, req, expiration
+func (_m *FCMToken) Save(ctx context.Context, userID uuid.UUID, req types.FCMToken, expiration time.Duration) error {
+	ret := _m.Called(ctx, userID, req, expiration)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Save")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, string, time.Duration) error); ok {
-		r0 = rf(ctx, key, token, expiration)
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, types.FCMToken, time.Duration) error); ok {
+		r0 = rf(ctx, userID, req, expiration)
 	} else {
 		r0 = ret.Error(0)
 	}
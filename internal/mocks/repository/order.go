@@ -309,6 +309,34 @@ func (_m *Order) FindForReportExportByServiceProviderID(ctx context.Context, ser
 	return r0, r1
 }
 
+// FindForUpdateByID provides a mock function with given fields: ctx, _tx, ID
+func (_m *Order) FindForUpdateByID(ctx context.Context, _tx dbUtil.Tx, ID uuid.UUID) (types.Order, error) {
+	ret := _m.Called(ctx, _tx, ID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindForUpdateByID")
+	}
+
+	var r0 types.Order
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, dbUtil.Tx, uuid.UUID) (types.Order, error)); ok {
+		return rf(ctx, _tx, ID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, dbUtil.Tx, uuid.UUID) types.Order); ok {
+		r0 = rf(ctx, _tx, ID)
+	} else {
+		r0 = ret.Get(0).(types.Order)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, dbUtil.Tx, uuid.UUID) error); ok {
+		r1 = rf(ctx, _tx, ID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // FindIDsWhereExpired provides a mock function with given fields: ctx
 func (_m *Order) FindIDsWhereExpired(ctx context.Context) (uuid.UUIDs, error) {
 	ret := _m.Called(ctx)
@@ -425,6 +453,24 @@ func (_m *Order) SumServiceFeeByServiceProviderIDAndStatusAndMonthAndYear(ctx co
 	return r0, r1
 }
 
+// UpdateAsCanceledTx provides a mock function with given fields: ctx, _tx, req
+func (_m *Order) UpdateAsCanceledTx(ctx context.Context, _tx dbUtil.Tx, req types.Order) error {
+	ret := _m.Called(ctx, _tx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateAsCanceledTx")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, dbUtil.Tx, types.Order) error); ok {
+		r0 = rf(ctx, _tx, req)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UpdateAsPaymentFulfilledTx provides a mock function with given fields: ctx, tx, req
 func (_m *Order) UpdateAsPaymentFulfilledTx(ctx context.Context, tx dbUtil.Tx, req types.Order) error {
 	ret := _m.Called(ctx, tx, req)
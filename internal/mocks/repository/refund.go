@@ -0,0 +1,134 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	dbUtil "kelarin/internal/utils/dbutil"
+
+	mock "github.com/stretchr/testify/mock"
+
+	types "kelarin/internal/types"
+
+	uuid "github.com/google/uuid"
+)
+
+// Refund is an autogenerated mock type for the Refund type
+type Refund struct {
+	mock.Mock
+}
+
+// CreateTx provides a mock function with given fields: ctx, _tx, req
+func (_m *Refund) CreateTx(ctx context.Context, _tx dbUtil.Tx, req types.Refund) error {
+	ret := _m.Called(ctx, _tx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateTx")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, dbUtil.Tx, types.Refund) error); ok {
+		r0 = rf(ctx, _tx, req)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FindAllByFilter provides a mock function with given fields: ctx, filter
+func (_m *Refund) FindAllByFilter(ctx context.Context, filter types.RefundFilter) ([]types.RefundWithOrder, int64, error) {
+	ret := _m.Called(ctx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindAllByFilter")
+	}
+
+	var r0 []types.RefundWithOrder
+	var r1 int64
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, types.RefundFilter) ([]types.RefundWithOrder, int64, error)); ok {
+		return rf(ctx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, types.RefundFilter) []types.RefundWithOrder); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]types.RefundWithOrder)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, types.RefundFilter) int64); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, types.RefundFilter) error); ok {
+		r2 = rf(ctx, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// FindForUpdateByID provides a mock function with given fields: ctx, _tx, ID
+func (_m *Refund) FindForUpdateByID(ctx context.Context, _tx dbUtil.Tx, ID uuid.UUID) (types.Refund, error) {
+	ret := _m.Called(ctx, _tx, ID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindForUpdateByID")
+	}
+
+	var r0 types.Refund
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, dbUtil.Tx, uuid.UUID) (types.Refund, error)); ok {
+		return rf(ctx, _tx, ID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, dbUtil.Tx, uuid.UUID) types.Refund); ok {
+		r0 = rf(ctx, _tx, ID)
+	} else {
+		r0 = ret.Get(0).(types.Refund)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, dbUtil.Tx, uuid.UUID) error); ok {
+		r1 = rf(ctx, _tx, ID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateStatusTx provides a mock function with given fields: ctx, _tx, req
+func (_m *Refund) UpdateStatusTx(ctx context.Context, _tx dbUtil.Tx, req types.Refund) error {
+	ret := _m.Called(ctx, _tx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateStatusTx")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, dbUtil.Tx, types.Refund) error); ok {
+		r0 = rf(ctx, _tx, req)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewRefund creates a new instance of Refund. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewRefund(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Refund {
+	mock := &Refund{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,199 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	typedapitypes "github.com/elastic/go-elasticsearch/v8/typedapi/types"
+
+	types "kelarin/internal/types"
+)
+
+// ServiceIndex is an autogenerated mock type for the ServiceIndex type
+type ServiceIndex struct {
+	mock.Mock
+}
+
+// Create provides a mock function with given fields: ctx, req
+func (_m *ServiceIndex) Create(ctx context.Context, req types.ServiceIndex) error {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, types.ServiceIndex) error); ok {
+		r0 = rf(ctx, req)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Delete provides a mock function with given fields: ctx, req
+func (_m *ServiceIndex) Delete(ctx context.Context, req types.ServiceIndex) error {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, types.ServiceIndex) error); ok {
+		r0 = rf(ctx, req)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FindAllByFilter provides a mock function with given fields: ctx, req
+func (_m *ServiceIndex) FindAllByFilter(ctx context.Context, req types.ServiceIndexFilter) ([]types.ServiceIndex, int64, []typedapitypes.FieldValue, types.ServiceIndexFacets, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindAllByFilter")
+	}
+
+	var r0 []types.ServiceIndex
+	var r1 int64
+	var r2 []typedapitypes.FieldValue
+	var r3 types.ServiceIndexFacets
+	var r4 error
+	if rf, ok := ret.Get(0).(func(context.Context, types.ServiceIndexFilter) ([]types.ServiceIndex, int64, []typedapitypes.FieldValue, types.ServiceIndexFacets, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, types.ServiceIndexFilter) []types.ServiceIndex); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]types.ServiceIndex)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, types.ServiceIndexFilter) int64); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, types.ServiceIndexFilter) []typedapitypes.FieldValue); ok {
+		r2 = rf(ctx, req)
+	} else {
+		if ret.Get(2) != nil {
+			r2 = ret.Get(2).([]typedapitypes.FieldValue)
+		}
+	}
+
+	if rf, ok := ret.Get(3).(func(context.Context, types.ServiceIndexFilter) types.ServiceIndexFacets); ok {
+		r3 = rf(ctx, req)
+	} else {
+		r3 = ret.Get(3).(types.ServiceIndexFacets)
+	}
+
+	if rf, ok := ret.Get(4).(func(context.Context, types.ServiceIndexFilter) error); ok {
+		r4 = rf(ctx, req)
+	} else {
+		r4 = ret.Error(4)
+	}
+
+	return r0, r1, r2, r3, r4
+}
+
+// FindByID provides a mock function with given fields: ctx, ID
+func (_m *ServiceIndex) FindByID(ctx context.Context, ID string) (types.ServiceIndex, int64, int64, error) {
+	ret := _m.Called(ctx, ID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindByID")
+	}
+
+	var r0 types.ServiceIndex
+	var r1 int64
+	var r2 int64
+	var r3 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (types.ServiceIndex, int64, int64, error)); ok {
+		return rf(ctx, ID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) types.ServiceIndex); ok {
+		r0 = rf(ctx, ID)
+	} else {
+		r0 = ret.Get(0).(types.ServiceIndex)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) int64); ok {
+		r1 = rf(ctx, ID)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string) int64); ok {
+		r2 = rf(ctx, ID)
+	} else {
+		r2 = ret.Get(2).(int64)
+	}
+
+	if rf, ok := ret.Get(3).(func(context.Context, string) error); ok {
+		r3 = rf(ctx, ID)
+	} else {
+		r3 = ret.Error(3)
+	}
+
+	return r0, r1, r2, r3
+}
+
+// PutMapping provides a mock function with given fields: ctx
+func (_m *ServiceIndex) PutMapping(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PutMapping")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Update provides a mock function with given fields: ctx, req, seqNo, primaryTerm
+func (_m *ServiceIndex) Update(ctx context.Context, req types.ServiceIndex, seqNo int64, primaryTerm int64) error {
+	ret := _m.Called(ctx, req, seqNo, primaryTerm)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Update")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, types.ServiceIndex, int64, int64) error); ok {
+		r0 = rf(ctx, req, seqNo, primaryTerm)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewServiceIndex creates a new instance of ServiceIndex. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewServiceIndex(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ServiceIndex {
+	mock := &ServiceIndex{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -228,17 +228,17 @@ func (_m *ServiceProvider) UpdateAsFeedbackGiven(ctx context.Context, tx dbUtil.
 	return r0
 }
 
-// UpdateCreditTx provides a mock function with given fields: ctx, req
-func (_m *ServiceProvider) UpdateCreditTx(ctx context.Context, req types.ServiceProvider) error {
-	ret := _m.Called(ctx, req)
+// UpdateCreditTx provides a mock function with given fields: ctx, _tx, req
+func (_m *ServiceProvider) UpdateCreditTx(ctx context.Context, _tx dbUtil.Tx, req types.ServiceProvider) error {
+	ret := _m.Called(ctx, _tx, req)
 
 	if len(ret) == 0 {
 		panic("no return value specified for UpdateCreditTx")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, types.ServiceProvider) error); ok {
-		r0 = rf(ctx, req)
+	if rf, ok := ret.Get(0).(func(context.Context, dbUtil.Tx, types.ServiceProvider) error); ok {
+		r0 = rf(ctx, _tx, req)
 	} else {
 		r0 = ret.Error(0)
 	}
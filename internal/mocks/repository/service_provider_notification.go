@@ -18,6 +18,34 @@ type ServiceProviderNotification struct {
 	mock.Mock
 }
 
+// CountUnreadByUserID provides a mock function with given fields: ctx, userID
+func (_m *ServiceProviderNotification) CountUnreadByUserID(ctx context.Context, userID uuid.UUID) (int64, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CountUnreadByUserID")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (int64, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) int64); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // CreateTx provides a mock function with given fields: ctx, tx, req
 func (_m *ServiceProviderNotification) CreateTx(ctx context.Context, tx dbUtil.Tx, req types.ServiceProviderNotification) error {
 	ret := _m.Called(ctx, tx, req)
@@ -36,9 +64,9 @@ func (_m *ServiceProviderNotification) CreateTx(ctx context.Context, tx dbUtil.T
 	return r0
 }
 
-// FindAllByServiceProviderID provides a mock function with given fields: ctx, serviceProviderID
-func (_m *ServiceProviderNotification) FindAllByServiceProviderID(ctx context.Context, serviceProviderID uuid.UUID) ([]types.ServiceProviderNotificationWithUser, error) {
-	ret := _m.Called(ctx, serviceProviderID)
+// FindAllByServiceProviderID provides a mock function with given fields: ctx, serviceProviderID, filter
+func (_m *ServiceProviderNotification) FindAllByServiceProviderID(ctx context.Context, serviceProviderID uuid.UUID, filter types.NotificationCursorFilter) ([]types.ServiceProviderNotificationWithUser, error) {
+	ret := _m.Called(ctx, serviceProviderID, filter)
 
 	if len(ret) == 0 {
 		panic("no return value specified for FindAllByServiceProviderID")
@@ -46,19 +74,65 @@ func (_m *ServiceProviderNotification) FindAllByServiceProviderID(ctx context.Co
 
 	var r0 []types.ServiceProviderNotificationWithUser
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]types.ServiceProviderNotificationWithUser, error)); ok {
-		return rf(ctx, serviceProviderID)
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, types.NotificationCursorFilter) ([]types.ServiceProviderNotificationWithUser, error)); ok {
+		return rf(ctx, serviceProviderID, filter)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) []types.ServiceProviderNotificationWithUser); ok {
-		r0 = rf(ctx, serviceProviderID)
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, types.NotificationCursorFilter) []types.ServiceProviderNotificationWithUser); ok {
+		r0 = rf(ctx, serviceProviderID, filter)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]types.ServiceProviderNotificationWithUser)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
-		r1 = rf(ctx, serviceProviderID)
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, types.NotificationCursorFilter) error); ok {
+		r1 = rf(ctx, serviceProviderID, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateAllAsReadByServiceProviderID provides a mock function with given fields: ctx, serviceProviderID
+func (_m *ServiceProviderNotification) UpdateAllAsReadByServiceProviderID(ctx context.Context, serviceProviderID uuid.UUID) error {
+	ret := _m.Called(ctx, serviceProviderID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateAllAsReadByServiceProviderID")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) error); ok {
+		r0 = rf(ctx, serviceProviderID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateAsReadByIDs provides a mock function with given fields: ctx, serviceProviderID, IDs
+func (_m *ServiceProviderNotification) UpdateAsReadByIDs(ctx context.Context, serviceProviderID uuid.UUID, IDs uuid.UUIDs) (int64, error) {
+	ret := _m.Called(ctx, serviceProviderID, IDs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateAsReadByIDs")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUIDs) (int64, error)); ok {
+		return rf(ctx, serviceProviderID, IDs)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUIDs) int64); ok {
+		r0 = rf(ctx, serviceProviderID, IDs)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, uuid.UUIDs) error); ok {
+		r1 = rf(ctx, serviceProviderID, IDs)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -0,0 +1,246 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+
+	types "kelarin/internal/types"
+)
+
+// Session is an autogenerated mock type for the Session type
+type Session struct {
+	mock.Mock
+}
+
+// Delete provides a mock function with given fields: ctx, key
+func (_m *Session) Delete(ctx context.Context, key string) error {
+	ret := _m.Called(ctx, key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, key)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteAllByUserID provides a mock function with given fields: ctx, userID
+func (_m *Session) DeleteAllByUserID(ctx context.Context, userID string) error {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteAllByUserID")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteOthersByUserID provides a mock function with given fields: ctx, userID, currentKey
+func (_m *Session) DeleteOthersByUserID(ctx context.Context, userID string, currentKey string) error {
+	ret := _m.Called(ctx, userID, currentKey)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteOthersByUserID")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, userID, currentKey)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Find provides a mock function with given fields: ctx, key
+func (_m *Session) Find(ctx context.Context, key string) (string, error) {
+	ret := _m.Called(ctx, key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Find")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (string, error)); ok {
+		return rf(ctx, key)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) string); ok {
+		r0 = rf(ctx, key)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, key)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindAllByUserID provides a mock function with given fields: ctx, userID
+func (_m *Session) FindAllByUserID(ctx context.Context, userID string) ([]types.Session, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindAllByUserID")
+	}
+
+	var r0 []types.Session
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]types.Session, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []types.Session); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]types.Session)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindMetadata provides a mock function with given fields: ctx, userID, key
+func (_m *Session) FindMetadata(ctx context.Context, userID string, key string) (types.Session, error) {
+	ret := _m.Called(ctx, userID, key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindMetadata")
+	}
+
+	var r0 types.Session
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (types.Session, error)); ok {
+		return rf(ctx, userID, key)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) types.Session); ok {
+		r0 = rf(ctx, userID, key)
+	} else {
+		r0 = ret.Get(0).(types.Session)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, userID, key)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// IsRenewed provides a mock function with given fields: ctx, familyID, sessionID
+func (_m *Session) IsRenewed(ctx context.Context, familyID string, sessionID string) (bool, error) {
+	ret := _m.Called(ctx, familyID, sessionID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IsRenewed")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (bool, error)); ok {
+		return rf(ctx, familyID, sessionID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) bool); ok {
+		r0 = rf(ctx, familyID, sessionID)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, familyID, sessionID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RenewAndDelete provides a mock function with given fields: ctx, oldKey, newKey, session, duration
+func (_m *Session) RenewAndDelete(ctx context.Context, oldKey string, newKey string, session types.Session, duration time.Duration) (bool, error) {
+	ret := _m.Called(ctx, oldKey, newKey, session, duration)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RenewAndDelete")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, types.Session, time.Duration) (bool, error)); ok {
+		return rf(ctx, oldKey, newKey, session, duration)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, types.Session, time.Duration) bool); ok {
+		r0 = rf(ctx, oldKey, newKey, session, duration)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, types.Session, time.Duration) error); ok {
+		r1 = rf(ctx, oldKey, newKey, session, duration)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Set provides a mock function with given fields: ctx, key, session, duration
+func (_m *Session) Set(ctx context.Context, key string, session types.Session, duration time.Duration) error {
+	ret := _m.Called(ctx, key, session, duration)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Set")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, types.Session, time.Duration) error); ok {
+		r0 = rf(ctx, key, session, duration)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewSession creates a new instance of Session. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewSession(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Session {
+	mock := &Session{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
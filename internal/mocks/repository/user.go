@@ -8,6 +8,8 @@ import (
 
 	mock "github.com/stretchr/testify/mock"
 
+	time "time"
+
 	types "kelarin/internal/types"
 
 	uuid "github.com/google/uuid"
@@ -54,6 +56,43 @@ func (_m *User) CreateTx(ctx context.Context, _tx dbUtil.Tx, user types.User) er
 	return r0
 }
 
+// FindAllByFilter provides a mock function with given fields: ctx, filter
+func (_m *User) FindAllByFilter(ctx context.Context, filter types.UserAdminFilter) ([]types.User, int64, error) {
+	ret := _m.Called(ctx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindAllByFilter")
+	}
+
+	var r0 []types.User
+	var r1 int64
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, types.UserAdminFilter) ([]types.User, int64, error)); ok {
+		return rf(ctx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, types.UserAdminFilter) []types.User); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]types.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, types.UserAdminFilter) int64); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, types.UserAdminFilter) error); ok {
+		r2 = rf(ctx, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // FindByEmail provides a mock function with given fields: ctx, email
 func (_m *User) FindByEmail(ctx context.Context, email string) (types.User, error) {
 	ret := _m.Called(ctx, email)
@@ -140,6 +179,154 @@ func (_m *User) FindByIDs(ctx context.Context, IDs uuid.UUIDs) ([]types.User, er
 	return r0, r1
 }
 
+// FindForUpdateByID provides a mock function with given fields: ctx, _tx, ID
+func (_m *User) FindForUpdateByID(ctx context.Context, _tx dbUtil.Tx, ID uuid.UUID) (types.User, error) {
+	ret := _m.Called(ctx, _tx, ID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindForUpdateByID")
+	}
+
+	var r0 types.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, dbUtil.Tx, uuid.UUID) (types.User, error)); ok {
+		return rf(ctx, _tx, ID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, dbUtil.Tx, uuid.UUID) types.User); ok {
+		r0 = rf(ctx, _tx, ID)
+	} else {
+		r0 = ret.Get(0).(types.User)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, dbUtil.Tx, uuid.UUID) error); ok {
+		r1 = rf(ctx, _tx, ID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MarkEmailAsVerified provides a mock function with given fields: ctx, ID, verifiedAt
+func (_m *User) MarkEmailAsVerified(ctx context.Context, ID uuid.UUID, verifiedAt time.Time) error {
+	ret := _m.Called(ctx, ID, verifiedAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MarkEmailAsVerified")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, time.Time) error); ok {
+		r0 = rf(ctx, ID, verifiedAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UnsuspendExpiredTx provides a mock function with given fields: ctx, _tx, now, limit
+func (_m *User) UnsuspendExpiredTx(ctx context.Context, _tx dbUtil.Tx, now time.Time, limit int) ([]types.User, error) {
+	ret := _m.Called(ctx, _tx, now, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UnsuspendExpiredTx")
+	}
+
+	var r0 []types.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, dbUtil.Tx, time.Time, int) ([]types.User, error)); ok {
+		return rf(ctx, _tx, now, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, dbUtil.Tx, time.Time, int) []types.User); ok {
+		r0 = rf(ctx, _tx, now, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]types.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, dbUtil.Tx, time.Time, int) error); ok {
+		r1 = rf(ctx, _tx, now, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateBanTx provides a mock function with given fields: ctx, _tx, user
+func (_m *User) UpdateBanTx(ctx context.Context, _tx dbUtil.Tx, user types.User) error {
+	ret := _m.Called(ctx, _tx, user)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateBanTx")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, dbUtil.Tx, types.User) error); ok {
+		r0 = rf(ctx, _tx, user)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateLanguage provides a mock function with given fields: ctx, ID, language
+func (_m *User) UpdateLanguage(ctx context.Context, ID uuid.UUID, language types.Language) error {
+	ret := _m.Called(ctx, ID, language)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateLanguage")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, types.Language) error); ok {
+		r0 = rf(ctx, ID, language)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdatePassword provides a mock function with given fields: ctx, ID, password
+func (_m *User) UpdatePassword(ctx context.Context, ID uuid.UUID, password string) error {
+	ret := _m.Called(ctx, ID, password)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdatePassword")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, string) error); ok {
+		r0 = rf(ctx, ID, password)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateSuspensionTx provides a mock function with given fields: ctx, _tx, user
+func (_m *User) UpdateSuspensionTx(ctx context.Context, _tx dbUtil.Tx, user types.User) error {
+	ret := _m.Called(ctx, _tx, user)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateSuspensionTx")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, dbUtil.Tx, types.User) error); ok {
+		r0 = rf(ctx, _tx, user)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewUser creates a new instance of User. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewUser(t interface {
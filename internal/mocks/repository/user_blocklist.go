@@ -0,0 +1,105 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+
+	types "kelarin/internal/types"
+
+	uuid "github.com/google/uuid"
+)
+
+// UserBlocklist is an autogenerated mock type for the UserBlocklist type
+type UserBlocklist struct {
+	mock.Mock
+}
+
+// Delete provides a mock function with given fields: ctx, userIDs
+func (_m *UserBlocklist) Delete(ctx context.Context, userIDs ...uuid.UUID) error {
+	_va := make([]interface{}, len(userIDs))
+	for _i := range userIDs {
+		_va[_i] = userIDs[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, ...uuid.UUID) error); ok {
+		r0 = rf(ctx, userIDs...)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Find provides a mock function with given fields: ctx, userID
+func (_m *UserBlocklist) Find(ctx context.Context, userID uuid.UUID) (types.UserBlocklistEntry, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Find")
+	}
+
+	var r0 types.UserBlocklistEntry
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (types.UserBlocklistEntry, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) types.UserBlocklistEntry); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Get(0).(types.UserBlocklistEntry)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Set provides a mock function with given fields: ctx, userID, entry, expiration
+func (_m *UserBlocklist) Set(ctx context.Context, userID uuid.UUID, entry types.UserBlocklistEntry, expiration time.Duration) error {
+	ret := _m.Called(ctx, userID, entry, expiration)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Set")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, types.UserBlocklistEntry, time.Duration) error); ok {
+		r0 = rf(ctx, userID, entry, expiration)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewUserBlocklist creates a new instance of UserBlocklist. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewUserBlocklist(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *UserBlocklist {
+	mock := &UserBlocklist{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
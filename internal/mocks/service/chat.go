@@ -8,6 +8,8 @@ import (
 	mock "github.com/stretchr/testify/mock"
 
 	types "kelarin/internal/types"
+
+	uuid "github.com/google/uuid"
 )
 
 // Chat is an autogenerated mock type for the Chat type
@@ -46,7 +48,7 @@ func (_m *Chat) ConsumerGetAll(ctx context.Context, req types.ChatGetAllReq) ([]
 }
 
 // ConsumerGetByRoomID provides a mock function with given fields: ctx, req
-func (_m *Chat) ConsumerGetByRoomID(ctx context.Context, req types.ChatGetByRoomIDReq) (types.ChatConsumerGetByRoomIDRes, error) {
+func (_m *Chat) ConsumerGetByRoomID(ctx context.Context, req types.ChatGetByRoomIDReq) (types.ChatConsumerGetByRoomIDRes, types.ChatMessageCursorPaginationRes, error) {
 	ret := _m.Called(ctx, req)
 
 	if len(ret) == 0 {
@@ -54,8 +56,9 @@ func (_m *Chat) ConsumerGetByRoomID(ctx context.Context, req types.ChatGetByRoom
 	}
 
 	var r0 types.ChatConsumerGetByRoomIDRes
-	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, types.ChatGetByRoomIDReq) (types.ChatConsumerGetByRoomIDRes, error)); ok {
+	var r1 types.ChatMessageCursorPaginationRes
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, types.ChatGetByRoomIDReq) (types.ChatConsumerGetByRoomIDRes, types.ChatMessageCursorPaginationRes, error)); ok {
 		return rf(ctx, req)
 	}
 	if rf, ok := ret.Get(0).(func(context.Context, types.ChatGetByRoomIDReq) types.ChatConsumerGetByRoomIDRes); ok {
@@ -64,13 +67,19 @@ func (_m *Chat) ConsumerGetByRoomID(ctx context.Context, req types.ChatGetByRoom
 		r0 = ret.Get(0).(types.ChatConsumerGetByRoomIDRes)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, types.ChatGetByRoomIDReq) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, types.ChatGetByRoomIDReq) types.ChatMessageCursorPaginationRes); ok {
 		r1 = rf(ctx, req)
 	} else {
-		r1 = ret.Error(1)
+		r1 = ret.Get(1).(types.ChatMessageCursorPaginationRes)
 	}
 
-	return r0, r1
+	if rf, ok := ret.Get(2).(func(context.Context, types.ChatGetByRoomIDReq) error); ok {
+		r2 = rf(ctx, req)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
 }
 
 // CreateChatRoom provides a mock function with given fields: ctx, req
@@ -155,7 +164,7 @@ func (_m *Chat) ProviderGetAll(ctx context.Context, req types.ChatGetAllReq) ([]
 }
 
 // ProviderGetByRoomID provides a mock function with given fields: ctx, req
-func (_m *Chat) ProviderGetByRoomID(ctx context.Context, req types.ChatGetByRoomIDReq) (types.ChatProviderGetByRoomIDRes, error) {
+func (_m *Chat) ProviderGetByRoomID(ctx context.Context, req types.ChatGetByRoomIDReq) (types.ChatProviderGetByRoomIDRes, types.ChatMessageCursorPaginationRes, error) {
 	ret := _m.Called(ctx, req)
 
 	if len(ret) == 0 {
@@ -163,8 +172,9 @@ func (_m *Chat) ProviderGetByRoomID(ctx context.Context, req types.ChatGetByRoom
 	}
 
 	var r0 types.ChatProviderGetByRoomIDRes
-	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, types.ChatGetByRoomIDReq) (types.ChatProviderGetByRoomIDRes, error)); ok {
+	var r1 types.ChatMessageCursorPaginationRes
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, types.ChatGetByRoomIDReq) (types.ChatProviderGetByRoomIDRes, types.ChatMessageCursorPaginationRes, error)); ok {
 		return rf(ctx, req)
 	}
 	if rf, ok := ret.Get(0).(func(context.Context, types.ChatGetByRoomIDReq) types.ChatProviderGetByRoomIDRes); ok {
@@ -173,13 +183,74 @@ func (_m *Chat) ProviderGetByRoomID(ctx context.Context, req types.ChatGetByRoom
 		r0 = ret.Get(0).(types.ChatProviderGetByRoomIDRes)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, types.ChatGetByRoomIDReq) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, types.ChatGetByRoomIDReq) types.ChatMessageCursorPaginationRes); ok {
 		r1 = rf(ctx, req)
 	} else {
-		r1 = ret.Error(1)
+		r1 = ret.Get(1).(types.ChatMessageCursorPaginationRes)
 	}
 
-	return r0, r1
+	if rf, ok := ret.Get(2).(func(context.Context, types.ChatGetByRoomIDReq) error); ok {
+		r2 = rf(ctx, req)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// PublishPresence provides a mock function with given fields: ctx, userID, online
+func (_m *Chat) PublishPresence(ctx context.Context, userID uuid.UUID, online bool) error {
+	ret := _m.Called(ctx, userID, online)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PublishPresence")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, bool) error); ok {
+		r0 = rf(ctx, userID, online)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Search provides a mock function with given fields: ctx, req
+func (_m *Chat) Search(ctx context.Context, req types.ChatSearchReq) ([]types.ChatSearchRes, types.CursorPaginationRes, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Search")
+	}
+
+	var r0 []types.ChatSearchRes
+	var r1 types.CursorPaginationRes
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, types.ChatSearchReq) ([]types.ChatSearchRes, types.CursorPaginationRes, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, types.ChatSearchReq) []types.ChatSearchRes); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]types.ChatSearchRes)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, types.ChatSearchReq) types.CursorPaginationRes); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Get(1).(types.CursorPaginationRes)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, types.ChatSearchReq) error); ok {
+		r2 = rf(ctx, req)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
 }
 
 // NewChat creates a new instance of Chat. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
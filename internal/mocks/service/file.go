@@ -137,6 +137,34 @@ func (_m *File) GetTemp(ctx context.Context, fileName string) (types.FileGetTemp
 	return r0, r1
 }
 
+// StoreChatAttachmentTemp provides a mock function with given fields: ctx, req
+func (_m *File) StoreChatAttachmentTemp(ctx context.Context, req types.FileUploadChatAttachmentReq) (types.FileUploadChatAttachmentRes, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StoreChatAttachmentTemp")
+	}
+
+	var r0 types.FileUploadChatAttachmentRes
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, types.FileUploadChatAttachmentReq) (types.FileUploadChatAttachmentRes, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, types.FileUploadChatAttachmentReq) types.FileUploadChatAttachmentRes); ok {
+		r0 = rf(ctx, req)
+	} else {
+		r0 = ret.Get(0).(types.FileUploadChatAttachmentRes)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, types.FileUploadChatAttachmentReq) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // StoreTemp provides a mock function with given fields: ctx, req
 func (_m *File) StoreTemp(ctx context.Context, req types.FileUploadImagesReq) (types.FileUploadFilesRes, error) {
 	ret := _m.Called(ctx, req)
@@ -165,6 +193,34 @@ func (_m *File) StoreTemp(ctx context.Context, req types.FileUploadImagesReq) (t
 	return r0, r1
 }
 
+// UploadChatAttachment provides a mock function with given fields: ctx, fileKey, contentType
+func (_m *File) UploadChatAttachment(ctx context.Context, fileKey string, contentType types.ChatMessageContentType) (types.FileChatAttachment, error) {
+	ret := _m.Called(ctx, fileKey, contentType)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UploadChatAttachment")
+	}
+
+	var r0 types.FileChatAttachment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, types.ChatMessageContentType) (types.FileChatAttachment, error)); ok {
+		return rf(ctx, fileKey, contentType)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, types.ChatMessageContentType) types.FileChatAttachment); ok {
+		r0 = rf(ctx, fileKey, contentType)
+	} else {
+		r0 = ret.Get(0).(types.FileChatAttachment)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, types.ChatMessageContentType) error); ok {
+		r1 = rf(ctx, fileKey, contentType)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewFile creates a new instance of File. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewFile(t interface {
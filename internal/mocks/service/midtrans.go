@@ -5,6 +5,7 @@ package mocks
 import (
 	context "context"
 
+	coreapi "github.com/midtrans/midtrans-go/coreapi"
 	mock "github.com/stretchr/testify/mock"
 
 	snap "github.com/midtrans/midtrans-go/snap"
@@ -45,6 +46,36 @@ func (_m *Midtrans) CreateTransaction(ctx context.Context, req *snap.Request) (*
 	return r0, r1
 }
 
+// Refund provides a mock function with given fields: ctx, orderID, req
+func (_m *Midtrans) Refund(ctx context.Context, orderID string, req *coreapi.RefundReq) (*coreapi.RefundResponse, error) {
+	ret := _m.Called(ctx, orderID, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Refund")
+	}
+
+	var r0 *coreapi.RefundResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *coreapi.RefundReq) (*coreapi.RefundResponse, error)); ok {
+		return rf(ctx, orderID, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *coreapi.RefundReq) *coreapi.RefundResponse); ok {
+		r0 = rf(ctx, orderID, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*coreapi.RefundResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *coreapi.RefundReq) error); ok {
+		r1 = rf(ctx, orderID, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewMidtrans creates a new instance of Midtrans. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewMidtrans(t interface {
@@ -8,6 +8,8 @@ import (
 	mock "github.com/stretchr/testify/mock"
 
 	types "kelarin/internal/types"
+
+	uuid "github.com/google/uuid"
 )
 
 // Notification is an autogenerated mock type for the Notification type
@@ -15,6 +17,132 @@ type Notification struct {
 	mock.Mock
 }
 
+// AdminBroadcast provides a mock function with given fields: ctx, req
+func (_m *Notification) AdminBroadcast(ctx context.Context, req types.NotificationAdminBroadcastReq) error {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AdminBroadcast")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, types.NotificationAdminBroadcastReq) error); ok {
+		r0 = rf(ctx, req)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MoveToken provides a mock function with given fields: ctx, userID, oldSessionID, newSessionID
+func (_m *Notification) MoveToken(ctx context.Context, userID uuid.UUID, oldSessionID uuid.UUID, newSessionID uuid.UUID) error {
+	ret := _m.Called(ctx, userID, oldSessionID, newSessionID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MoveToken")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID, uuid.UUID) error); ok {
+		r0 = rf(ctx, userID, oldSessionID, newSessionID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// PublishUnreadCount provides a mock function with given fields: ctx, userID
+func (_m *Notification) PublishUnreadCount(ctx context.Context, userID uuid.UUID) error {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PublishUnreadCount")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) error); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// QueueAuthEmail provides a mock function with given fields: ctx, req
+func (_m *Notification) QueueAuthEmail(ctx context.Context, req types.NotificationSendAuthEmailReq) error {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for QueueAuthEmail")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, types.NotificationSendAuthEmailReq) error); ok {
+		r0 = rf(ctx, req)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// QueueEmailToUser provides a mock function with given fields: ctx, ID, req
+func (_m *Notification) QueueEmailToUser(ctx context.Context, ID uuid.UUID, req types.NotificationSendEmailReq) error {
+	ret := _m.Called(ctx, ID, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for QueueEmailToUser")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, types.NotificationSendEmailReq) error); ok {
+		r0 = rf(ctx, ID, req)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RemoveAllTokens provides a mock function with given fields: ctx, userID
+func (_m *Notification) RemoveAllTokens(ctx context.Context, userID uuid.UUID) error {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RemoveAllTokens")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) error); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RemoveToken provides a mock function with given fields: ctx, userID, sessionID
+func (_m *Notification) RemoveToken(ctx context.Context, userID uuid.UUID, sessionID uuid.UUID) error {
+	ret := _m.Called(ctx, userID, sessionID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RemoveToken")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID) error); ok {
+		r0 = rf(ctx, userID, sessionID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // SaveToken provides a mock function with given fields: ctx, req
 func (_m *Notification) SaveToken(ctx context.Context, req types.NotificationSaveTokenReq) error {
 	ret := _m.Called(ctx, req)
@@ -33,6 +161,42 @@ func (_m *Notification) SaveToken(ctx context.Context, req types.NotificationSav
 	return r0
 }
 
+// SendAuthEmail provides a mock function with given fields: ctx, req
+func (_m *Notification) SendAuthEmail(ctx context.Context, req types.NotificationSendAuthEmailReq) error {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SendAuthEmail")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, types.NotificationSendAuthEmailReq) error); ok {
+		r0 = rf(ctx, req)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SendEmailToUser provides a mock function with given fields: ctx, req
+func (_m *Notification) SendEmailToUser(ctx context.Context, req types.NotificationSendEmailReq) error {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SendEmailToUser")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, types.NotificationSendEmailReq) error); ok {
+		r0 = rf(ctx, req)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // SendPush provides a mock function with given fields: ctx, req
 func (_m *Notification) SendPush(ctx context.Context, req types.NotificationSendReq) error {
 	ret := _m.Called(ctx, req)
@@ -51,6 +215,24 @@ func (_m *Notification) SendPush(ctx context.Context, req types.NotificationSend
 	return r0
 }
 
+// SendPushToUser provides a mock function with given fields: ctx, userID, req
+func (_m *Notification) SendPushToUser(ctx context.Context, userID uuid.UUID, req types.NotificationSendReq) error {
+	ret := _m.Called(ctx, userID, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SendPushToUser")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, types.NotificationSendReq) error); ok {
+		r0 = rf(ctx, userID, req)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewNotification creates a new instance of Notification. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewNotification(t interface {
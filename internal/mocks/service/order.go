@@ -15,6 +15,34 @@ type Order struct {
 	mock.Mock
 }
 
+// ConsumerCancel provides a mock function with given fields: ctx, req
+func (_m *Order) ConsumerCancel(ctx context.Context, req types.OrderCancelReq) (types.OrderCancelRes, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ConsumerCancel")
+	}
+
+	var r0 types.OrderCancelRes
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, types.OrderCancelReq) (types.OrderCancelRes, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, types.OrderCancelReq) types.OrderCancelRes); ok {
+		r0 = rf(ctx, req)
+	} else {
+		r0 = ret.Get(0).(types.OrderCancelRes)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, types.OrderCancelReq) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ConsumerGenerateQRCode provides a mock function with given fields: ctx, req
 func (_m *Order) ConsumerGenerateQRCode(ctx context.Context, req types.OrderConsumerGenerateQRCodeReq) (types.OrderConsumerGenerateQRCodeRes, error) {
 	ret := _m.Called(ctx, req)
@@ -119,6 +147,34 @@ func (_m *Order) Create(ctx context.Context, req types.OrderCreateReq) error {
 	return r0
 }
 
+// ProviderCancel provides a mock function with given fields: ctx, req
+func (_m *Order) ProviderCancel(ctx context.Context, req types.OrderCancelReq) (types.OrderCancelRes, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ProviderCancel")
+	}
+
+	var r0 types.OrderCancelRes
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, types.OrderCancelReq) (types.OrderCancelRes, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, types.OrderCancelReq) types.OrderCancelRes); ok {
+		r0 = rf(ctx, req)
+	} else {
+		r0 = ret.Get(0).(types.OrderCancelRes)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, types.OrderCancelReq) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ProviderFinish provides a mock function with given fields: ctx, req
 func (_m *Order) ProviderFinish(ctx context.Context, req types.OrderProviderValidateQRCodeReq) error {
 	ret := _m.Called(ctx, req)
@@ -0,0 +1,106 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	dbUtil "kelarin/internal/utils/dbutil"
+
+	mock "github.com/stretchr/testify/mock"
+
+	types "kelarin/internal/types"
+
+	uuid "github.com/google/uuid"
+)
+
+// Outbox is an autogenerated mock type for the Outbox type
+type Outbox struct {
+	mock.Mock
+}
+
+// CreateTx provides a mock function with given fields: ctx, tx, events
+func (_m *Outbox) CreateTx(ctx context.Context, tx dbUtil.Tx, events ...types.OutboxEvent) error {
+	_va := make([]interface{}, len(events))
+	for _i := range events {
+		_va[_i] = events[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, tx)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateTx")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, dbUtil.Tx, ...types.OutboxEvent) error); ok {
+		r0 = rf(ctx, tx, events...)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Dispatch provides a mock function with given fields: ctx, events
+func (_m *Outbox) Dispatch(ctx context.Context, events ...types.OutboxEvent) {
+	_va := make([]interface{}, len(events))
+	for _i := range events {
+		_va[_i] = events[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx)
+	_ca = append(_ca, _va...)
+	_m.Called(_ca...)
+}
+
+// Process provides a mock function with given fields: ctx, ID
+func (_m *Outbox) Process(ctx context.Context, ID uuid.UUID) error {
+	ret := _m.Called(ctx, ID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Process")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) error); ok {
+		r0 = rf(ctx, ID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// TaskSweepPending provides a mock function with given fields: ctx
+func (_m *Outbox) TaskSweepPending(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for TaskSweepPending")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewOutbox creates a new instance of Outbox. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewOutbox(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Outbox {
+	mock := &Outbox{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
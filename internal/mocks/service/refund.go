@@ -0,0 +1,123 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	types "kelarin/internal/types"
+
+	uuid "github.com/google/uuid"
+)
+
+// Refund is an autogenerated mock type for the Refund type
+type Refund struct {
+	mock.Mock
+}
+
+// AdminGetAll provides a mock function with given fields: ctx, req
+func (_m *Refund) AdminGetAll(ctx context.Context, req types.RefundAdminGetAllReq) ([]types.RefundAdminGetAllRes, types.PaginationRes, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AdminGetAll")
+	}
+
+	var r0 []types.RefundAdminGetAllRes
+	var r1 types.PaginationRes
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, types.RefundAdminGetAllReq) ([]types.RefundAdminGetAllRes, types.PaginationRes, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, types.RefundAdminGetAllReq) []types.RefundAdminGetAllRes); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]types.RefundAdminGetAllRes)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, types.RefundAdminGetAllReq) types.PaginationRes); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Get(1).(types.PaginationRes)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, types.RefundAdminGetAllReq) error); ok {
+		r2 = rf(ctx, req)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// AdminMarkAsSucceeded provides a mock function with given fields: ctx, req
+func (_m *Refund) AdminMarkAsSucceeded(ctx context.Context, req types.RefundAdminActionReq) error {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AdminMarkAsSucceeded")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, types.RefundAdminActionReq) error); ok {
+		r0 = rf(ctx, req)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// AdminRetry provides a mock function with given fields: ctx, req
+func (_m *Refund) AdminRetry(ctx context.Context, req types.RefundAdminActionReq) error {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AdminRetry")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, types.RefundAdminActionReq) error); ok {
+		r0 = rf(ctx, req)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Process provides a mock function with given fields: ctx, ID
+func (_m *Refund) Process(ctx context.Context, ID uuid.UUID) error {
+	ret := _m.Called(ctx, ID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Process")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) error); ok {
+		r0 = rf(ctx, ID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewRefund creates a new instance of Refund. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewRefund(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Refund {
+	mock := &Refund{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
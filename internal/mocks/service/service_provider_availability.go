@@ -0,0 +1,218 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	types "kelarin/internal/types"
+)
+
+// ServiceProviderAvailability is an autogenerated mock type for the ServiceProviderAvailability type
+type ServiceProviderAvailability struct {
+	mock.Mock
+}
+
+// Find provides a mock function with given fields: ctx, req
+func (_m *ServiceProviderAvailability) Find(ctx context.Context, req types.ServiceAvailabilityFindReq) (types.ServiceAvailability, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Find")
+	}
+
+	var r0 types.ServiceAvailability
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, types.ServiceAvailabilityFindReq) (types.ServiceAvailability, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, types.ServiceAvailabilityFindReq) types.ServiceAvailability); ok {
+		r0 = rf(ctx, req)
+	} else {
+		r0 = ret.Get(0).(types.ServiceAvailability)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, types.ServiceAvailabilityFindReq) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSlots provides a mock function with given fields: ctx, req
+func (_m *ServiceProviderAvailability) GetSlots(ctx context.Context, req types.ServiceAvailabilityGetSlotsReq) (types.ServiceAvailabilityGetSlotsRes, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSlots")
+	}
+
+	var r0 types.ServiceAvailabilityGetSlotsRes
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, types.ServiceAvailabilityGetSlotsReq) (types.ServiceAvailabilityGetSlotsRes, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, types.ServiceAvailabilityGetSlotsReq) types.ServiceAvailabilityGetSlotsRes); ok {
+		r0 = rf(ctx, req)
+	} else {
+		r0 = ret.Get(0).(types.ServiceAvailabilityGetSlotsRes)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, types.ServiceAvailabilityGetSlotsReq) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ProviderCreateDayOff provides a mock function with given fields: ctx, req
+func (_m *ServiceProviderAvailability) ProviderCreateDayOff(ctx context.Context, req types.ServiceProviderDayOffProviderCreateReq) error {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ProviderCreateDayOff")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, types.ServiceProviderDayOffProviderCreateReq) error); ok {
+		r0 = rf(ctx, req)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ProviderDeleteDayOff provides a mock function with given fields: ctx, req
+func (_m *ServiceProviderAvailability) ProviderDeleteDayOff(ctx context.Context, req types.ServiceProviderDayOffProviderDeleteReq) error {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ProviderDeleteDayOff")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, types.ServiceProviderDayOffProviderDeleteReq) error); ok {
+		r0 = rf(ctx, req)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ProviderGetAllDaysOff provides a mock function with given fields: ctx, req
+func (_m *ServiceProviderAvailability) ProviderGetAllDaysOff(ctx context.Context, req types.ServiceProviderDayOffProviderGetAllReq) ([]types.ServiceProviderDayOffRes, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ProviderGetAllDaysOff")
+	}
+
+	var r0 []types.ServiceProviderDayOffRes
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, types.ServiceProviderDayOffProviderGetAllReq) ([]types.ServiceProviderDayOffRes, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, types.ServiceProviderDayOffProviderGetAllReq) []types.ServiceProviderDayOffRes); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]types.ServiceProviderDayOffRes)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, types.ServiceProviderDayOffProviderGetAllReq) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ProviderGetWorkingHours provides a mock function with given fields: ctx, req
+func (_m *ServiceProviderAvailability) ProviderGetWorkingHours(ctx context.Context, req types.ServiceProviderWorkingHourProviderGetAllReq) ([]types.ServiceProviderWorkingHourRes, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ProviderGetWorkingHours")
+	}
+
+	var r0 []types.ServiceProviderWorkingHourRes
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, types.ServiceProviderWorkingHourProviderGetAllReq) ([]types.ServiceProviderWorkingHourRes, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, types.ServiceProviderWorkingHourProviderGetAllReq) []types.ServiceProviderWorkingHourRes); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]types.ServiceProviderWorkingHourRes)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, types.ServiceProviderWorkingHourProviderGetAllReq) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ProviderUpdateService provides a mock function with given fields: ctx, req
+func (_m *ServiceProviderAvailability) ProviderUpdateService(ctx context.Context, req types.ServiceAvailabilityProviderUpdateReq) error {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ProviderUpdateService")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, types.ServiceAvailabilityProviderUpdateReq) error); ok {
+		r0 = rf(ctx, req)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ProviderUpdateWorkingHours provides a mock function with given fields: ctx, req
+func (_m *ServiceProviderAvailability) ProviderUpdateWorkingHours(ctx context.Context, req types.ServiceProviderWorkingHourProviderUpdateReq) error {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ProviderUpdateWorkingHours")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, types.ServiceProviderWorkingHourProviderUpdateReq) error); ok {
+		r0 = rf(ctx, req)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewServiceProviderAvailability creates a new instance of ServiceProviderAvailability. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewServiceProviderAvailability(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ServiceProviderAvailability {
+	mock := &ServiceProviderAvailability{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
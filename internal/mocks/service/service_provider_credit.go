@@ -0,0 +1,325 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	types "kelarin/internal/types"
+)
+
+// ServiceProviderCredit is an autogenerated mock type for the ServiceProviderCredit type
+type ServiceProviderCredit struct {
+	mock.Mock
+}
+
+// AddTx provides a mock function with given fields: ctx, req
+func (_m *ServiceProviderCredit) AddTx(ctx context.Context, req types.ServiceProviderCreditAddReq) error {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddTx")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, types.ServiceProviderCreditAddReq) error); ok {
+		r0 = rf(ctx, req)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// AdminApprovePayout provides a mock function with given fields: ctx, req
+func (_m *ServiceProviderCredit) AdminApprovePayout(ctx context.Context, req types.PayoutAdminActionReq) error {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AdminApprovePayout")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, types.PayoutAdminActionReq) error); ok {
+		r0 = rf(ctx, req)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// AdminGetAllPayouts provides a mock function with given fields: ctx, req
+func (_m *ServiceProviderCredit) AdminGetAllPayouts(ctx context.Context, req types.PayoutAdminGetAllReq) ([]types.PayoutGetAllRes, types.PaginationRes, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AdminGetAllPayouts")
+	}
+
+	var r0 []types.PayoutGetAllRes
+	var r1 types.PaginationRes
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, types.PayoutAdminGetAllReq) ([]types.PayoutGetAllRes, types.PaginationRes, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, types.PayoutAdminGetAllReq) []types.PayoutGetAllRes); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]types.PayoutGetAllRes)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, types.PayoutAdminGetAllReq) types.PaginationRes); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Get(1).(types.PaginationRes)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, types.PayoutAdminGetAllReq) error); ok {
+		r2 = rf(ctx, req)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// AdminMarkPayoutAsPaid provides a mock function with given fields: ctx, req
+func (_m *ServiceProviderCredit) AdminMarkPayoutAsPaid(ctx context.Context, req types.PayoutAdminActionReq) error {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AdminMarkPayoutAsPaid")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, types.PayoutAdminActionReq) error); ok {
+		r0 = rf(ctx, req)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// AdminRejectPayout provides a mock function with given fields: ctx, req
+func (_m *ServiceProviderCredit) AdminRejectPayout(ctx context.Context, req types.PayoutAdminActionReq) error {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AdminRejectPayout")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, types.PayoutAdminActionReq) error); ok {
+		r0 = rf(ctx, req)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ProviderCreateBankAccount provides a mock function with given fields: ctx, req
+func (_m *ServiceProviderCredit) ProviderCreateBankAccount(ctx context.Context, req types.ServiceProviderBankAccountProviderCreateReq) error {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ProviderCreateBankAccount")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, types.ServiceProviderBankAccountProviderCreateReq) error); ok {
+		r0 = rf(ctx, req)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ProviderCreatePayout provides a mock function with given fields: ctx, req
+func (_m *ServiceProviderCredit) ProviderCreatePayout(ctx context.Context, req types.PayoutProviderCreateReq) error {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ProviderCreatePayout")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, types.PayoutProviderCreateReq) error); ok {
+		r0 = rf(ctx, req)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ProviderDeleteBankAccount provides a mock function with given fields: ctx, req
+func (_m *ServiceProviderCredit) ProviderDeleteBankAccount(ctx context.Context, req types.ServiceProviderBankAccountProviderDeleteReq) error {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ProviderDeleteBankAccount")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, types.ServiceProviderBankAccountProviderDeleteReq) error); ok {
+		r0 = rf(ctx, req)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ProviderGet provides a mock function with given fields: ctx, req
+func (_m *ServiceProviderCredit) ProviderGet(ctx context.Context, req types.ServiceProviderCreditProviderGetReq) (types.ServiceProviderCreditProviderGetRes, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ProviderGet")
+	}
+
+	var r0 types.ServiceProviderCreditProviderGetRes
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, types.ServiceProviderCreditProviderGetReq) (types.ServiceProviderCreditProviderGetRes, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, types.ServiceProviderCreditProviderGetReq) types.ServiceProviderCreditProviderGetRes); ok {
+		r0 = rf(ctx, req)
+	} else {
+		r0 = ret.Get(0).(types.ServiceProviderCreditProviderGetRes)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, types.ServiceProviderCreditProviderGetReq) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ProviderGetAllBankAccounts provides a mock function with given fields: ctx, req
+func (_m *ServiceProviderCredit) ProviderGetAllBankAccounts(ctx context.Context, req types.ServiceProviderBankAccountProviderGetAllReq) ([]types.ServiceProviderBankAccountProviderGetAllRes, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ProviderGetAllBankAccounts")
+	}
+
+	var r0 []types.ServiceProviderBankAccountProviderGetAllRes
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, types.ServiceProviderBankAccountProviderGetAllReq) ([]types.ServiceProviderBankAccountProviderGetAllRes, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, types.ServiceProviderBankAccountProviderGetAllReq) []types.ServiceProviderBankAccountProviderGetAllRes); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]types.ServiceProviderBankAccountProviderGetAllRes)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, types.ServiceProviderBankAccountProviderGetAllReq) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ProviderGetAllPayouts provides a mock function with given fields: ctx, req
+func (_m *ServiceProviderCredit) ProviderGetAllPayouts(ctx context.Context, req types.PayoutProviderGetAllReq) ([]types.PayoutGetAllRes, types.PaginationRes, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ProviderGetAllPayouts")
+	}
+
+	var r0 []types.PayoutGetAllRes
+	var r1 types.PaginationRes
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, types.PayoutProviderGetAllReq) ([]types.PayoutGetAllRes, types.PaginationRes, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, types.PayoutProviderGetAllReq) []types.PayoutGetAllRes); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]types.PayoutGetAllRes)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, types.PayoutProviderGetAllReq) types.PaginationRes); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Get(1).(types.PaginationRes)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, types.PayoutProviderGetAllReq) error); ok {
+		r2 = rf(ctx, req)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// ProviderGetLedgers provides a mock function with given fields: ctx, req
+func (_m *ServiceProviderCredit) ProviderGetLedgers(ctx context.Context, req types.ServiceProviderCreditProviderGetLedgersReq) ([]types.ServiceProviderCreditProviderGetLedgersRes, types.PaginationRes, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ProviderGetLedgers")
+	}
+
+	var r0 []types.ServiceProviderCreditProviderGetLedgersRes
+	var r1 types.PaginationRes
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, types.ServiceProviderCreditProviderGetLedgersReq) ([]types.ServiceProviderCreditProviderGetLedgersRes, types.PaginationRes, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, types.ServiceProviderCreditProviderGetLedgersReq) []types.ServiceProviderCreditProviderGetLedgersRes); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]types.ServiceProviderCreditProviderGetLedgersRes)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, types.ServiceProviderCreditProviderGetLedgersReq) types.PaginationRes); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Get(1).(types.PaginationRes)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, types.ServiceProviderCreditProviderGetLedgersReq) error); ok {
+		r2 = rf(ctx, req)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// NewServiceProviderCredit creates a new instance of ServiceProviderCredit. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewServiceProviderCredit(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ServiceProviderCredit {
+	mock := &ServiceProviderCredit{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,220 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	types "kelarin/internal/types"
+
+	uuid "github.com/google/uuid"
+)
+
+// TwoFactor is an autogenerated mock type for the TwoFactor type
+type TwoFactor struct {
+	mock.Mock
+}
+
+// Disable provides a mock function with given fields: ctx, req
+func (_m *TwoFactor) Disable(ctx context.Context, req types.TwoFactorCodeReq) error {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Disable")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, types.TwoFactorCodeReq) error); ok {
+		r0 = rf(ctx, req)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Enable provides a mock function with given fields: ctx, req
+func (_m *TwoFactor) Enable(ctx context.Context, req types.TwoFactorCodeReq) (types.TwoFactorRecoveryCodesRes, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Enable")
+	}
+
+	var r0 types.TwoFactorRecoveryCodesRes
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, types.TwoFactorCodeReq) (types.TwoFactorRecoveryCodesRes, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, types.TwoFactorCodeReq) types.TwoFactorRecoveryCodesRes); ok {
+		r0 = rf(ctx, req)
+	} else {
+		r0 = ret.Get(0).(types.TwoFactorRecoveryCodesRes)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, types.TwoFactorCodeReq) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// IsEnabled provides a mock function with given fields: ctx, userID
+func (_m *TwoFactor) IsEnabled(ctx context.Context, userID uuid.UUID) (bool, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IsEnabled")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (bool, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) bool); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RegenerateRecoveryCodes provides a mock function with given fields: ctx, req
+func (_m *TwoFactor) RegenerateRecoveryCodes(ctx context.Context, req types.TwoFactorCodeReq) (types.TwoFactorRecoveryCodesRes, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RegenerateRecoveryCodes")
+	}
+
+	var r0 types.TwoFactorRecoveryCodesRes
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, types.TwoFactorCodeReq) (types.TwoFactorRecoveryCodesRes, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, types.TwoFactorCodeReq) types.TwoFactorRecoveryCodesRes); ok {
+		r0 = rf(ctx, req)
+	} else {
+		r0 = ret.Get(0).(types.TwoFactorRecoveryCodesRes)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, types.TwoFactorCodeReq) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Setup provides a mock function with given fields: ctx, req
+func (_m *TwoFactor) Setup(ctx context.Context, req types.TwoFactorSetupReq) (types.TwoFactorSetupRes, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Setup")
+	}
+
+	var r0 types.TwoFactorSetupRes
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, types.TwoFactorSetupReq) (types.TwoFactorSetupRes, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, types.TwoFactorSetupReq) types.TwoFactorSetupRes); ok {
+		r0 = rf(ctx, req)
+	} else {
+		r0 = ret.Get(0).(types.TwoFactorSetupRes)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, types.TwoFactorSetupReq) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetupForUser provides a mock function with given fields: ctx, userID
+func (_m *TwoFactor) SetupForUser(ctx context.Context, userID uuid.UUID) (types.TwoFactorSetupRes, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetupForUser")
+	}
+
+	var r0 types.TwoFactorSetupRes
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (types.TwoFactorSetupRes, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) types.TwoFactorSetupRes); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Get(0).(types.TwoFactorSetupRes)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Verify provides a mock function with given fields: ctx, userID, code
+func (_m *TwoFactor) Verify(ctx context.Context, userID uuid.UUID, code string) ([]string, error) {
+	ret := _m.Called(ctx, userID, code)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Verify")
+	}
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, string) ([]string, error)); ok {
+		return rf(ctx, userID, code)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, string) []string); ok {
+		r0 = rf(ctx, userID, code)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, string) error); ok {
+		r1 = rf(ctx, userID, code)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewTwoFactor creates a new instance of TwoFactor. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewTwoFactor(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *TwoFactor {
+	mock := &TwoFactor{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -24,4 +24,9 @@ var HandlerSet = wire.NewSet(
 	handler.NewPaymentMethod,
 	handler.NewReport,
 	handler.NewChat,
+	handler.NewServiceProviderCredit,
+	handler.NewRefund,
+	handler.NewDispute,
+	handler.NewServiceProviderAvailability,
+	handler.NewAdminRole,
 )
@@ -10,6 +10,10 @@ var RepositorySet = wire.NewSet(
 	repository.NewUser,
 	repository.NewSession,
 	repository.NewPendingRegistration,
+	repository.NewAuthToken,
+	repository.NewUserTOTP,
+	repository.NewAdminRole,
+	repository.NewAdminPermissionCache,
 	repository.NewFile,
 	repository.NewProvince,
 	repository.NewCity,
@@ -25,6 +29,7 @@ var RepositorySet = wire.NewSet(
 	repository.NewFCMToken,
 	repository.NewConsumerNotification,
 	repository.NewServiceProviderNotification,
+	repository.NewNotificationPreference,
 	repository.NewChatRoom,
 	repository.NewChatRoomUser,
 	repository.NewChatMessage,
@@ -33,4 +38,15 @@ var RepositorySet = wire.NewSet(
 	repository.NewOrder,
 	repository.NewServiceFeedback,
 	repository.NewOrderOfferSnapshot,
+	repository.NewOrderSession,
+	repository.NewUserModerationLog,
+	repository.NewUserBlocklist,
+	repository.NewUserPresence,
+	repository.NewServiceProviderCreditLedger,
+	repository.NewServiceProviderBankAccount,
+	repository.NewPayout,
+	repository.NewRefund,
+	repository.NewDispute,
+	repository.NewServiceProviderAvailability,
+	repository.NewOutboxEvent,
 )
@@ -6,24 +6,29 @@ import (
 )
 
 type Server struct {
-	UserHandler             handler.User
-	AuthHandler             *handler.Auth
-	FileHandler             handler.File
-	ServiceProviderHandler  handler.ServiceProvider
-	ServiceHandler          handler.Service
-	ProvinceHandler         handler.Province
-	CityHandler             handler.City
-	ServiceCategoryHandler  handler.ServiceCategory
-	UserAddressHandler      handler.UserAddress
-	OfferHandler            handler.Offer
-	OfferNegotiationHandler handler.OfferNegotiation
-	NotificationHandler     handler.Notification
-	PaymentHandler          handler.Payment
-	OrderHandler            handler.Order
-	PaymentMethodHandler    handler.PaymentMethod
-	ReportHandler           handler.Report
-	ChatHandler             handler.Chat
-	AuthMiddleware          middleware.Auth
+	UserHandler                        handler.User
+	AuthHandler                        *handler.Auth
+	FileHandler                        handler.File
+	ServiceProviderHandler             handler.ServiceProvider
+	ServiceHandler                     handler.Service
+	ProvinceHandler                    handler.Province
+	CityHandler                        handler.City
+	ServiceCategoryHandler             handler.ServiceCategory
+	UserAddressHandler                 handler.UserAddress
+	OfferHandler                       handler.Offer
+	OfferNegotiationHandler            handler.OfferNegotiation
+	NotificationHandler                handler.Notification
+	PaymentHandler                     handler.Payment
+	OrderHandler                       handler.Order
+	PaymentMethodHandler               handler.PaymentMethod
+	ReportHandler                      handler.Report
+	ChatHandler                        handler.Chat
+	ServiceProviderCreditHandler       handler.ServiceProviderCredit
+	RefundHandler                      handler.Refund
+	DisputeHandler                     handler.Dispute
+	ServiceProviderAvailabilityHandler handler.ServiceProviderAvailability
+	AdminRoleHandler                   handler.AdminRole
+	AuthMiddleware                     middleware.Auth
 }
 
 func NewServer(
@@ -44,6 +49,11 @@ func NewServer(
 	paymentMethodHandler handler.PaymentMethod,
 	reportHandler handler.Report,
 	chatHandler handler.Chat,
+	serviceProviderCreditHandler handler.ServiceProviderCredit,
+	refundHandler handler.Refund,
+	disputeHandler handler.Dispute,
+	serviceProviderAvailabilityHandler handler.ServiceProviderAvailability,
+	adminRoleHandler handler.AdminRole,
 	authMiddleware middleware.Auth,
 ) *Server {
 	return &Server{
@@ -64,6 +74,11 @@ func NewServer(
 		paymentMethodHandler,
 		reportHandler,
 		chatHandler,
+		serviceProviderCreditHandler,
+		refundHandler,
+		disputeHandler,
+		serviceProviderAvailabilityHandler,
+		adminRoleHandler,
 		authMiddleware,
 	}
 }
@@ -9,6 +9,7 @@ import (
 var ServiceSet = wire.NewSet(
 	service.NewUser,
 	service.NewAuth,
+	service.NewTwoFactor,
 	service.NewFile,
 	service.NewGeocoding,
 	service.NewServiceProvider,
@@ -31,4 +32,11 @@ var ServiceSet = wire.NewSet(
 	service.NewServiceProviderNotification,
 	service.NewReport,
 	service.NewServiceFeedback,
+	service.NewServiceProviderCredit,
+	service.NewRefund,
+	service.NewDispute,
+	service.NewServiceProviderAvailability,
+	service.NewOutbox,
+	service.NewNotificationPreference,
+	service.NewAdminRole,
 )
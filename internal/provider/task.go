@@ -11,11 +11,13 @@ import (
 )
 
 type Cronjob struct {
-	db           *sqlx.DB
-	redisDB      *redis.Client
-	queueClient  *asynq.Client
-	OfferService service.Offer
-	OrderService service.Order
+	db            *sqlx.DB
+	redisDB       *redis.Client
+	queueClient   *asynq.Client
+	OfferService  service.Offer
+	OrderService  service.Order
+	UserService   service.User
+	OutboxService service.Outbox
 }
 
 func NewCronjob(
@@ -24,13 +26,17 @@ func NewCronjob(
 	queueClient *asynq.Client,
 	offerService service.Offer,
 	orderService service.Order,
+	userService service.User,
+	outboxService service.Outbox,
 ) *Cronjob {
 	return &Cronjob{
-		db:           db,
-		redisDB:      redisDB,
-		queueClient:  queueClient,
-		OfferService: offerService,
-		OrderService: orderService,
+		db:            db,
+		redisDB:       redisDB,
+		queueClient:   queueClient,
+		OfferService:  offerService,
+		OrderService:  orderService,
+		UserService:   userService,
+		OutboxService: outboxService,
 	}
 }
 
@@ -59,12 +65,15 @@ var TaskRepositorySet = wire.NewSet(
 	repository.NewServiceProvider,
 	repository.NewService,
 	repository.NewServiceIndex,
+	repository.NewServiceCategory,
+	repository.NewServiceProviderArea,
 	repository.NewUserAddress,
 	repository.NewOffer,
 	repository.NewOfferNegotiation,
 	repository.NewFCMToken,
 	repository.NewConsumerNotification,
 	repository.NewServiceProviderNotification,
+	repository.NewNotificationPreference,
 	repository.NewChatRoom,
 	repository.NewChatRoomUser,
 	repository.NewChatMessage,
@@ -73,6 +82,17 @@ var TaskRepositorySet = wire.NewSet(
 	repository.NewPayment,
 	repository.NewPaymentMethod,
 	repository.NewOrderOfferSnapshot,
+	repository.NewOrderSession,
+	repository.NewSession,
+	repository.NewUserModerationLog,
+	repository.NewUserBlocklist,
+	repository.NewUserPresence,
+	repository.NewServiceProviderCreditLedger,
+	repository.NewServiceProviderBankAccount,
+	repository.NewPayout,
+	repository.NewRefund,
+	repository.NewServiceProviderAvailability,
+	repository.NewOutboxEvent,
 )
 
 var TaskServiceSet = wire.NewSet(
@@ -82,4 +102,10 @@ var TaskServiceSet = wire.NewSet(
 	service.NewNotification,
 	service.NewOffer,
 	service.NewOrder,
+	service.NewUser,
+	service.NewServiceProviderCredit,
+	service.NewMidtrans,
+	service.NewRefund,
+	service.NewServiceProviderAvailability,
+	service.NewOutbox,
 )
@@ -0,0 +1,62 @@
+package provider
+
+import (
+	taskHandler "kelarin/internal/queue/handler"
+
+	"github.com/google/wire"
+	"github.com/hibiken/asynq"
+	"github.com/jmoiron/sqlx"
+	"github.com/redis/go-redis/v9"
+)
+
+type Worker struct {
+	db                  *sqlx.DB
+	redisDB             *redis.Client
+	queueClient         *asynq.Client
+	TempFileHandler     taskHandler.QueueTempFile
+	OutboxHandler       taskHandler.QueueOutbox
+	NotificationHandler taskHandler.QueueNotification
+}
+
+func NewWorker(
+	db *sqlx.DB,
+	redisDB *redis.Client,
+	queueClient *asynq.Client,
+	tempFileHandler taskHandler.QueueTempFile,
+	outboxHandler taskHandler.QueueOutbox,
+	notificationHandler taskHandler.QueueNotification,
+) *Worker {
+	return &Worker{
+		db:                  db,
+		redisDB:             redisDB,
+		queueClient:         queueClient,
+		TempFileHandler:     tempFileHandler,
+		OutboxHandler:       outboxHandler,
+		NotificationHandler: notificationHandler,
+	}
+}
+
+func (p *Worker) Close() error {
+	err := p.db.Close()
+	if err != nil {
+		return err
+	}
+
+	err = p.redisDB.Close()
+	if err != nil {
+		return err
+	}
+
+	err = p.queueClient.Close()
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+var WorkerHandlerSet = wire.NewSet(
+	taskHandler.NewQueueTempFile,
+	taskHandler.NewQueueOutbox,
+	taskHandler.NewQueueNotification,
+)
@@ -0,0 +1,72 @@
+package taskHandler
+
+import (
+	"context"
+	"encoding/json"
+	"kelarin/internal/service"
+	"kelarin/internal/types"
+
+	"github.com/go-errors/errors"
+	"github.com/hibiken/asynq"
+)
+
+type QueueNotification interface {
+	SendPush(ctx context.Context, t *asynq.Task) error
+	SendEmail(ctx context.Context, t *asynq.Task) error
+	SendAuthEmail(ctx context.Context, t *asynq.Task) error
+}
+
+type notificationImpl struct {
+	notificationSvc service.Notification
+}
+
+func NewQueueNotification(notificationSvc service.Notification) QueueNotification {
+	return &notificationImpl{notificationSvc: notificationSvc}
+}
+
+// SendPush goes through the preferences of the user again, they may have changed while the push was held back
+func (h *notificationImpl) SendPush(ctx context.Context, t *asynq.Task) error {
+	payload := types.QueueSendPushNotificationPayload{}
+
+	err := json.Unmarshal(t.Payload(), &payload)
+	if err != nil {
+		return errors.New(err)
+	}
+
+	return h.notificationSvc.SendPushToUser(ctx, payload.UserID, types.NotificationSendReq{
+		NotificationType: payload.NotificationType,
+		Title:            payload.Title,
+		Message:          payload.Message,
+		ImageURL:         payload.ImageURL,
+	})
+}
+
+func (h *notificationImpl) SendEmail(ctx context.Context, t *asynq.Task) error {
+	payload := types.QueueSendEmailNotificationPayload{}
+
+	err := json.Unmarshal(t.Payload(), &payload)
+	if err != nil {
+		return errors.New(err)
+	}
+
+	return h.notificationSvc.SendEmailToUser(ctx, types.NotificationSendEmailReq{
+		UserID:           payload.UserID,
+		NotificationType: payload.NotificationType,
+		Message:          payload.Message,
+	})
+}
+
+func (h *notificationImpl) SendAuthEmail(ctx context.Context, t *asynq.Task) error {
+	payload := types.QueueSendAuthEmailPayload{}
+
+	err := json.Unmarshal(t.Payload(), &payload)
+	if err != nil {
+		return errors.New(err)
+	}
+
+	return h.notificationSvc.SendAuthEmail(ctx, types.NotificationSendAuthEmailReq{
+		UserID:  payload.UserID,
+		Purpose: payload.Purpose,
+		Token:   payload.Token,
+	})
+}
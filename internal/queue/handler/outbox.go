@@ -0,0 +1,34 @@
+package taskHandler
+
+import (
+	"context"
+	"encoding/json"
+	"kelarin/internal/service"
+	"kelarin/internal/types"
+
+	"github.com/go-errors/errors"
+	"github.com/hibiken/asynq"
+)
+
+type QueueOutbox interface {
+	ProcessEvent(ctx context.Context, t *asynq.Task) error
+}
+
+type outboxImpl struct {
+	outboxSvc service.Outbox
+}
+
+func NewQueueOutbox(outboxSvc service.Outbox) QueueOutbox {
+	return &outboxImpl{outboxSvc: outboxSvc}
+}
+
+func (h *outboxImpl) ProcessEvent(ctx context.Context, t *asynq.Task) error {
+	payload := types.QueueProcessOutboxEventPayload{}
+
+	err := json.Unmarshal(t.Payload(), &payload)
+	if err != nil {
+		return errors.New(err)
+	}
+
+	return h.outboxSvc.Process(ctx, payload.ID)
+}
@@ -35,10 +35,11 @@ func NewServer(cfg *config.Config, logger *workerUtil.WorkerLogger) *asynq.Serve
 	queuePriorityMap := types.GetQueuePriorityNameMap(cfg.Environment)
 
 	server := asynq.NewServer(addr, asynq.Config{
-		Concurrency:  2,
-		Queues:       queuePriorityMap,
-		Logger:       logger,
-		ErrorHandler: workerUtil.NewErrorHandler(logger),
+		Concurrency:     cfg.Worker.Concurrency,
+		Queues:          queuePriorityMap,
+		Logger:          logger,
+		ErrorHandler:    workerUtil.NewErrorHandler(logger),
+		ShutdownTimeout: cfg.Worker.ShutdownTimeout,
 	})
 
 	return server
@@ -0,0 +1,82 @@
+package task
+
+import (
+	"context"
+	"encoding/json"
+	"kelarin/internal/types"
+	"time"
+
+	"github.com/go-errors/errors"
+	"github.com/hibiken/asynq"
+)
+
+type Notification interface {
+	SendPush(ctx context.Context, queueName string, req types.QueueSendPushNotificationPayload, processAt time.Time) error
+	SendEmail(ctx context.Context, queueName, taskID string, req types.QueueSendEmailNotificationPayload) error
+	SendAuthEmail(ctx context.Context, queueName string, req types.QueueSendAuthEmailPayload) error
+}
+
+type notificationImpl struct {
+	client *asynq.Client
+}
+
+func NewNotification(client *asynq.Client) Notification {
+	return &notificationImpl{client}
+}
+
+func (r notificationImpl) SendPush(ctx context.Context, queueName string, req types.QueueSendPushNotificationPayload, processAt time.Time) error {
+	payload, err := json.Marshal(req)
+	if err != nil {
+		return errors.New(err)
+	}
+
+	task := asynq.NewTask(types.TaskSendPushNotification, payload, asynq.Queue(queueName))
+
+	_, err = r.client.EnqueueContext(ctx, task, asynq.ProcessAt(processAt))
+	if err != nil {
+		return errors.New(err)
+	}
+
+	return nil
+}
+
+// SendEmail enqueues the email once per task id, the task is retained after it is processed so it can not be enqueued again
+func (r notificationImpl) SendEmail(ctx context.Context, queueName, taskID string, req types.QueueSendEmailNotificationPayload) error {
+	payload, err := json.Marshal(req)
+	if err != nil {
+		return errors.New(err)
+	}
+
+	task := asynq.NewTask(
+		types.TaskSendEmailNotification,
+		payload,
+		asynq.Queue(queueName),
+		asynq.TaskID(taskID),
+		asynq.Retention(types.EmailNotificationTaskRetention),
+	)
+
+	_, err = r.client.EnqueueContext(ctx, task)
+	if errors.Is(err, asynq.ErrTaskIDConflict) {
+		return nil
+	} else if err != nil {
+		return errors.New(err)
+	}
+
+	return nil
+}
+
+// SendAuthEmail is not retained after it is processed, the payload holds a token that is still valid
+func (r notificationImpl) SendAuthEmail(ctx context.Context, queueName string, req types.QueueSendAuthEmailPayload) error {
+	payload, err := json.Marshal(req)
+	if err != nil {
+		return errors.New(err)
+	}
+
+	task := asynq.NewTask(types.TaskSendAuthEmail, payload, asynq.Queue(queueName))
+
+	if _, err = r.client.EnqueueContext(ctx, task); err != nil {
+		return errors.New(err)
+	}
+
+	return nil
+}
@@ -0,0 +1,48 @@
+package task
+
+import (
+	"context"
+	"encoding/json"
+	"kelarin/internal/types"
+
+	"github.com/go-errors/errors"
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+)
+
+type Outbox interface {
+	Process(ctx context.Context, queueName string, ID uuid.UUID) error
+}
+
+type outboxImpl struct {
+	client *asynq.Client
+}
+
+func NewOutbox(client *asynq.Client) Outbox {
+	return &outboxImpl{client}
+}
+
+// Process enqueues the event once, an event that is already waiting in the queue is not enqueued again
+func (r outboxImpl) Process(ctx context.Context, queueName string, ID uuid.UUID) error {
+	payload, err := json.Marshal(types.QueueProcessOutboxEventPayload{ID: ID})
+	if err != nil {
+		return errors.New(err)
+	}
+
+	task := asynq.NewTask(
+		types.TaskProcessOutboxEvent,
+		payload,
+		asynq.Queue(queueName),
+		asynq.TaskID(types.OutboxTaskID(ID)),
+		asynq.MaxRetry(types.OutboxMaxAttempts),
+	)
+
+	_, err = r.client.EnqueueContext(ctx, task)
+	if errors.Is(err, asynq.ErrTaskIDConflict) {
+		return nil
+	} else if err != nil {
+		return errors.New(err)
+	}
+
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"kelarin/internal/types"
+	"path/filepath"
 	"time"
 
 	"github.com/go-errors/errors"
@@ -23,7 +24,10 @@ func NewTempFile(client *asynq.Client) TempFile {
 }
 
 func (r fileUploadImpl) Delete(ctx context.Context, queueName string, req types.FileTemp, delay time.Duration) error {
-	payload, err := json.Marshal(req)
+	payload, err := json.Marshal(types.QueueDeleteTempFilePayload{
+		FileName: req.Name,
+		FilePath: filepath.Join(types.TempFileDir, req.Name),
+	})
 	if err != nil {
 		return errors.New(err)
 	}
@@ -0,0 +1,138 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"kelarin/internal/types"
+	"strconv"
+	"time"
+
+	"github.com/go-errors/errors"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// setAdminPermissionCacheScript stores the permissions only when the version of the admin has not changed since they were
+// loaded, permissions read before a role change can not be cached after the change has been invalidated. The version is kept
+// as long as the permissions cached with it, so it can not expire and be counted up to the same number again
+var setAdminPermissionCacheScript = redis.NewScript(`
+	local version = redis.call("GET", KEYS[1])
+	if tonumber(version or "0") ~= tonumber(ARGV[1]) then
+		return 0
+	end
+
+	redis.call("SET", KEYS[2], ARGV[2], "PX", ARGV[3])
+	if version then
+		redis.call("PEXPIRE", KEYS[1], ARGV[3])
+	end
+
+	return 1
+`)
+
+// AdminPermissionCache holds the permissions of an admin session so the permission middleware does not hit postgres on every request.
+// Every admin has a version that is incremented when their roles change, the cached permissions of an older version are ignored
+type AdminPermissionCache interface {
+	FindVersion(ctx context.Context, userID uuid.UUID) (int64, error)
+	Set(ctx context.Context, userID, sessionID uuid.UUID, version int64, permissions []string, expiration time.Duration) error
+	Find(ctx context.Context, userID, sessionID uuid.UUID) ([]string, error)
+	Invalidate(ctx context.Context, expiration time.Duration, userIDs ...uuid.UUID) error
+}
+
+type adminPermissionCacheImpl struct {
+	redisDB *redis.Client
+}
+
+func NewAdminPermissionCache(redisDB *redis.Client) AdminPermissionCache {
+	return &adminPermissionCacheImpl{redisDB: redisDB}
+}
+
+// FindVersion returns 0 for an admin whose roles have not changed recently
+func (r *adminPermissionCacheImpl) FindVersion(ctx context.Context, userID uuid.UUID) (int64, error) {
+	version, err := r.redisDB.Get(ctx, types.GetAdminPermissionCacheVersionKey(userID.String())).Int64()
+	if errors.Is(err, redis.Nil) {
+		return 0, nil
+	} else if err != nil {
+		return 0, errors.New(err)
+	}
+
+	return version, nil
+}
+
+// Set stores the permissions as json when version is still the version of the admin, otherwise the permissions are dropped
+// and loaded again on the next request. An admin without permissions is cached as an empty list
+func (r *adminPermissionCacheImpl) Set(ctx context.Context, userID, sessionID uuid.UUID, version int64, permissions []string, expiration time.Duration) error {
+	val, err := json.Marshal(types.AdminPermissionCache{Version: version, Permissions: permissions})
+	if err != nil {
+		return errors.New(err)
+	}
+
+	keys := []string{
+		types.GetAdminPermissionCacheVersionKey(userID.String()),
+		types.GetAdminPermissionCacheKey(sessionID.String()),
+	}
+
+	if err = setAdminPermissionCacheScript.Run(ctx, r.redisDB, keys, version, val, expiration.Milliseconds()).Err(); err != nil {
+		return errors.New(err)
+	}
+
+	return nil
+}
+
+// Find reports ErrNoData when the session has no cached permissions or they were cached before the roles of the admin changed
+func (r *adminPermissionCacheImpl) Find(ctx context.Context, userID, sessionID uuid.UUID) ([]string, error) {
+	vals, err := r.redisDB.MGet(ctx,
+		types.GetAdminPermissionCacheVersionKey(userID.String()),
+		types.GetAdminPermissionCacheKey(sessionID.String()),
+	).Result()
+	if err != nil {
+		return []string{}, errors.New(err)
+	}
+
+	val, ok := vals[1].(string)
+	if !ok {
+		return []string{}, errors.New(types.ErrNoData)
+	}
+
+	version := int64(0)
+	if current, ok := vals[0].(string); ok {
+		if version, err = strconv.ParseInt(current, 10, 64); err != nil {
+			return []string{}, errors.New(err)
+		}
+	}
+
+	res := types.AdminPermissionCache{}
+	if err = json.Unmarshal([]byte(val), &res); err != nil {
+		return []string{}, errors.New(err)
+	}
+
+	if res.Version != version {
+		return []string{}, errors.New(types.ErrNoData)
+	}
+
+	if res.Permissions == nil {
+		res.Permissions = []string{}
+	}
+
+	return res.Permissions, nil
+}
+
+// Invalidate increments the version of the admins so the permissions cached for every session are loaded again
+func (r *adminPermissionCacheImpl) Invalidate(ctx context.Context, expiration time.Duration, userIDs ...uuid.UUID) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+
+	pipe := r.redisDB.TxPipeline()
+
+	for _, userID := range userIDs {
+		key := types.GetAdminPermissionCacheVersionKey(userID.String())
+		pipe.Incr(ctx, key)
+		pipe.Expire(ctx, key, expiration)
+	}
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return errors.New(err)
+	}
+
+	return nil
+}
@@ -0,0 +1,332 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"kelarin/internal/types"
+	dbUtil "kelarin/internal/utils/dbutil"
+
+	"github.com/go-errors/errors"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type AdminRole interface {
+	FindAll(ctx context.Context) ([]types.AdminRole, error)
+	FindByID(ctx context.Context, ID uuid.UUID) (types.AdminRole, error)
+	FindByIDs(ctx context.Context, IDs uuid.UUIDs) ([]types.AdminRole, error)
+	FindAllByUserID(ctx context.Context, userID uuid.UUID) ([]types.AdminRole, error)
+	FindPermissionsByUserID(ctx context.Context, userID uuid.UUID) ([]string, error)
+	FindForUpdateByID(ctx context.Context, _tx dbUtil.Tx, ID uuid.UUID) (types.AdminRole, error)
+	FindUserIDsByRoleIDTx(ctx context.Context, _tx dbUtil.Tx, roleID uuid.UUID) (uuid.UUIDs, error)
+	CreateTx(ctx context.Context, _tx dbUtil.Tx, role types.AdminRole) error
+	UpdateTx(ctx context.Context, _tx dbUtil.Tx, role types.AdminRole) error
+	DeleteTx(ctx context.Context, _tx dbUtil.Tx, ID uuid.UUID) error
+	ReplacePermissionsTx(ctx context.Context, _tx dbUtil.Tx, roleID uuid.UUID, permissions []string) error
+	ReplaceUserRolesTx(ctx context.Context, _tx dbUtil.Tx, userID uuid.UUID, roleIDs uuid.UUIDs) error
+}
+
+type adminRoleImpl struct {
+	db *sqlx.DB
+}
+
+func NewAdminRole(db *sqlx.DB) AdminRole {
+	return &adminRoleImpl{db: db}
+}
+
+// adminRoleSelect aggregates the permissions of the role, a role without permissions gets an empty array
+const adminRoleSelect = `
+	SELECT
+		admin_roles.id,
+		admin_roles.name,
+		admin_roles.description,
+		admin_roles.is_system,
+		COALESCE(
+			array_agg(admin_role_permissions.permission ORDER BY admin_role_permissions.permission)
+				FILTER (WHERE admin_role_permissions.permission IS NOT NULL),
+			'{}'
+		) AS permissions,
+		admin_roles.created_at,
+		admin_roles.updated_at
+	FROM admin_roles
+	LEFT JOIN admin_role_permissions ON admin_role_permissions.role_id = admin_roles.id
+`
+
+func (r *adminRoleImpl) FindAll(ctx context.Context) ([]types.AdminRole, error) {
+	res := []types.AdminRole{}
+
+	query := adminRoleSelect + `
+		GROUP BY admin_roles.id
+		ORDER BY admin_roles.is_system DESC, admin_roles.name
+	`
+
+	if err := r.db.SelectContext(ctx, &res, query); err != nil {
+		return res, errors.New(err)
+	}
+
+	return res, nil
+}
+
+func (r *adminRoleImpl) FindByID(ctx context.Context, ID uuid.UUID) (types.AdminRole, error) {
+	res := types.AdminRole{}
+
+	query := adminRoleSelect + `
+		WHERE admin_roles.id = $1
+		GROUP BY admin_roles.id
+	`
+
+	err := r.db.GetContext(ctx, &res, query, ID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return res, errors.New(types.ErrNoData)
+	} else if err != nil {
+		return res, errors.New(err)
+	}
+
+	return res, nil
+}
+
+func (r *adminRoleImpl) FindByIDs(ctx context.Context, IDs uuid.UUIDs) ([]types.AdminRole, error) {
+	res := []types.AdminRole{}
+
+	if len(IDs) == 0 {
+		return res, nil
+	}
+
+	query := adminRoleSelect + `
+		WHERE admin_roles.id = ANY($1)
+		GROUP BY admin_roles.id
+	`
+
+	if err := r.db.SelectContext(ctx, &res, query, pq.Array(IDs)); err != nil {
+		return res, errors.New(err)
+	}
+
+	return res, nil
+}
+
+func (r *adminRoleImpl) FindAllByUserID(ctx context.Context, userID uuid.UUID) ([]types.AdminRole, error) {
+	res := []types.AdminRole{}
+
+	query := adminRoleSelect + `
+		WHERE admin_roles.id IN (SELECT role_id FROM user_admin_roles WHERE user_id = $1)
+		GROUP BY admin_roles.id
+		ORDER BY admin_roles.is_system DESC, admin_roles.name
+	`
+
+	if err := r.db.SelectContext(ctx, &res, query, userID); err != nil {
+		return res, errors.New(err)
+	}
+
+	return res, nil
+}
+
+// FindPermissionsByUserID returns the permissions granted by every role of the user
+func (r *adminRoleImpl) FindPermissionsByUserID(ctx context.Context, userID uuid.UUID) ([]string, error) {
+	res := []string{}
+
+	query := `
+		SELECT DISTINCT admin_role_permissions.permission
+		FROM user_admin_roles
+		JOIN admin_role_permissions ON admin_role_permissions.role_id = user_admin_roles.role_id
+		WHERE user_admin_roles.user_id = $1
+	`
+
+	if err := r.db.SelectContext(ctx, &res, query, userID); err != nil {
+		return res, errors.New(err)
+	}
+
+	return res, nil
+}
+
+// FindForUpdateByID locks the role, the lock waits for the assignments of the role that are not committed yet.
+// The permissions are read after the lock so they are the ones committed by the last change of the role
+func (r *adminRoleImpl) FindForUpdateByID(ctx context.Context, _tx dbUtil.Tx, ID uuid.UUID) (types.AdminRole, error) {
+	res := types.AdminRole{}
+
+	tx, err := dbUtil.CastSqlxTx(_tx)
+	if err != nil {
+		return res, err
+	}
+
+	query := `
+		SELECT
+			id,
+			name,
+			description,
+			is_system,
+			created_at,
+			updated_at
+		FROM admin_roles
+		WHERE id = $1
+		FOR UPDATE
+	`
+
+	err = tx.GetContext(ctx, &res, query, ID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return res, errors.New(types.ErrNoData)
+	} else if err != nil {
+		return res, errors.New(err)
+	}
+
+	query = `
+		SELECT permission
+		FROM admin_role_permissions
+		WHERE role_id = $1
+		ORDER BY permission
+	`
+
+	res.Permissions = pq.StringArray{}
+	if err = tx.SelectContext(ctx, &res.Permissions, query, ID); err != nil {
+		return res, errors.New(err)
+	}
+
+	return res, nil
+}
+
+func (r *adminRoleImpl) FindUserIDsByRoleIDTx(ctx context.Context, _tx dbUtil.Tx, roleID uuid.UUID) (uuid.UUIDs, error) {
+	res := uuid.UUIDs{}
+
+	tx, err := dbUtil.CastSqlxTx(_tx)
+	if err != nil {
+		return res, err
+	}
+
+	query := `
+		SELECT user_id
+		FROM user_admin_roles
+		WHERE role_id = $1
+	`
+
+	if err = tx.SelectContext(ctx, &res, query, roleID); err != nil {
+		return res, errors.New(err)
+	}
+
+	return res, nil
+}
+
+func (r *adminRoleImpl) CreateTx(ctx context.Context, _tx dbUtil.Tx, role types.AdminRole) error {
+	tx, err := dbUtil.CastSqlxTx(_tx)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO admin_roles (
+			id,
+			name,
+			description,
+			created_at
+		)
+		VALUES (
+			:id,
+			:name,
+			:description,
+			:created_at
+		)
+	`
+
+	if _, err = tx.NamedExecContext(ctx, query, role); err != nil {
+		return errors.New(err)
+	}
+
+	return nil
+}
+
+func (r *adminRoleImpl) UpdateTx(ctx context.Context, _tx dbUtil.Tx, role types.AdminRole) error {
+	tx, err := dbUtil.CastSqlxTx(_tx)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE admin_roles
+		SET name = :name,
+			description = :description,
+			updated_at = :updated_at
+		WHERE id = :id
+	`
+
+	if _, err = tx.NamedExecContext(ctx, query, role); err != nil {
+		return errors.New(err)
+	}
+
+	return nil
+}
+
+// DeleteTx also removes the permissions of the role and the assignments to the admins
+func (r *adminRoleImpl) DeleteTx(ctx context.Context, _tx dbUtil.Tx, ID uuid.UUID) error {
+	tx, err := dbUtil.CastSqlxTx(_tx)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		DELETE FROM admin_roles
+		WHERE id = $1
+	`
+
+	if _, err = tx.ExecContext(ctx, query, ID); err != nil {
+		return errors.New(err)
+	}
+
+	return nil
+}
+
+func (r *adminRoleImpl) ReplacePermissionsTx(ctx context.Context, _tx dbUtil.Tx, roleID uuid.UUID, permissions []string) error {
+	tx, err := dbUtil.CastSqlxTx(_tx)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		DELETE FROM admin_role_permissions
+		WHERE role_id = $1
+	`
+
+	if _, err = tx.ExecContext(ctx, query, roleID); err != nil {
+		return errors.New(err)
+	}
+
+	query = `
+		INSERT INTO admin_role_permissions (role_id, permission)
+		SELECT $1, permission
+		FROM UNNEST($2::VARCHAR[]) AS permission
+		ON CONFLICT DO NOTHING
+	`
+
+	if _, err = tx.ExecContext(ctx, query, roleID, pq.Array(permissions)); err != nil {
+		return errors.New(err)
+	}
+
+	return nil
+}
+
+func (r *adminRoleImpl) ReplaceUserRolesTx(ctx context.Context, _tx dbUtil.Tx, userID uuid.UUID, roleIDs uuid.UUIDs) error {
+	tx, err := dbUtil.CastSqlxTx(_tx)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		DELETE FROM user_admin_roles
+		WHERE user_id = $1
+	`
+
+	if _, err = tx.ExecContext(ctx, query, userID); err != nil {
+		return errors.New(err)
+	}
+
+	query = `
+		INSERT INTO user_admin_roles (user_id, role_id)
+		SELECT $1, role_id
+		FROM UNNEST($2::UUID[]) AS role_id
+		ON CONFLICT DO NOTHING
+	`
+
+	if _, err = tx.ExecContext(ctx, query, userID, pq.Array(roleIDs)); err != nil {
+		return errors.New(err)
+	}
+
+	return nil
+}
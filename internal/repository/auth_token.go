@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-errors/errors"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// consumeAuthTokenScript deletes the key only when it still holds the token id, so a token can not be used twice
+var consumeAuthTokenScript = redis.NewScript(`
+	if redis.call("GET", KEYS[1]) == ARGV[1] then
+		return redis.call("DEL", KEYS[1])
+	end
+
+	return 0
+`)
+
+type AuthToken interface {
+	Set(ctx context.Context, key string, tokenID uuid.UUID, expiration time.Duration) error
+	IsCurrent(ctx context.Context, key string, tokenID uuid.UUID) (bool, error)
+	Consume(ctx context.Context, key string, tokenID uuid.UUID) (bool, error)
+	Restore(ctx context.Context, key string, tokenID uuid.UUID, expiration time.Duration) (bool, error)
+	AcquireCooldown(ctx context.Context, key string, interval time.Duration) (bool, error)
+	IncrAttempts(ctx context.Context, key string, expiration time.Duration) (int64, error)
+}
+
+type authTokenImpl struct {
+	redis *redis.Client
+}
+
+func NewAuthToken(redis *redis.Client) AuthToken {
+	return &authTokenImpl{redis: redis}
+}
+
+func (r *authTokenImpl) Set(ctx context.Context, key string, tokenID uuid.UUID, expiration time.Duration) error {
+	if err := r.redis.Set(ctx, key, tokenID.String(), expiration).Err(); err != nil {
+		return errors.New(err)
+	}
+
+	return nil
+}
+
+// IsCurrent reports false when the token has expired, has been used or a newer token has been sent, the token is kept
+func (r *authTokenImpl) IsCurrent(ctx context.Context, key string, tokenID uuid.UUID) (bool, error) {
+	val, err := r.redis.Get(ctx, key).Result()
+	if errors.Is(err, redis.Nil) {
+		return false, nil
+	} else if err != nil {
+		return false, errors.New(err)
+	}
+
+	return val == tokenID.String(), nil
+}
+
+// Consume reports false when the token has expired, has been used or a newer token has been sent
+func (r *authTokenImpl) Consume(ctx context.Context, key string, tokenID uuid.UUID) (bool, error) {
+	deleted, err := consumeAuthTokenScript.Run(ctx, r.redis, []string{key}, tokenID.String()).Int()
+	if err != nil {
+		return false, errors.New(err)
+	}
+
+	return deleted == 1, nil
+}
+
+// Restore puts back a consumed token, it reports false when a newer token has been set since
+func (r *authTokenImpl) Restore(ctx context.Context, key string, tokenID uuid.UUID, expiration time.Duration) (bool, error) {
+	ok, err := r.redis.SetNX(ctx, key, tokenID.String(), expiration).Result()
+	if err != nil {
+		return false, errors.New(err)
+	}
+
+	return ok, nil
+}
+
+// AcquireCooldown reports false when the key is still cooling down from the previous call
+func (r *authTokenImpl) AcquireCooldown(ctx context.Context, key string, interval time.Duration) (bool, error) {
+	ok, err := r.redis.SetNX(ctx, key, 1, interval).Result()
+	if err != nil {
+		return false, errors.New(err)
+	}
+
+	return ok, nil
+}
+
+// IncrAttempts returns the number of attempts including the current one, the counter outlives the token it belongs to
+func (r *authTokenImpl) IncrAttempts(ctx context.Context, key string, expiration time.Duration) (int64, error) {
+	pipe := r.redis.TxPipeline()
+
+	incr := pipe.Incr(ctx, key)
+	pipe.Expire(ctx, key, expiration)
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, errors.New(err)
+	}
+
+	return incr.Val(), nil
+}
@@ -2,8 +2,10 @@ package repository
 
 import (
 	"context"
+	"fmt"
 	"kelarin/internal/types"
 	dbUtil "kelarin/internal/utils/dbutil"
+	"time"
 
 	"github.com/go-errors/errors"
 	"github.com/google/uuid"
@@ -14,10 +16,13 @@ import (
 type ChatMessage interface {
 	CreateTx(ctx context.Context, _tx dbUtil.Tx, req types.ChatMessage) error
 	CountUnreadReceivedByChatRoomIDs(ctx context.Context, userID uuid.UUID, chatRoomIDs uuid.UUIDs) ([]types.ChatMessageCountUnread, error)
-	FindByChatRoomID(ctx context.Context, roomID uuid.UUID) ([]types.ChatMessage, error)
+	FindByChatRoomID(ctx context.Context, roomID uuid.UUID, filter types.ChatMessageCursorFilter) ([]types.ChatMessage, error)
+	Search(ctx context.Context, userID uuid.UUID, query string, filter types.ChatMessageCursorFilter) ([]types.ChatMessageSearchResult, error)
 	FindLatestByChatRoomIDs(ctx context.Context, roomIDs uuid.UUIDs) ([]types.ChatMessage, error)
 	FindReceivedByIDsAndRoomID(ctx context.Context, ids uuid.UUIDs, roomID, userID uuid.UUID) ([]types.ChatMessage, error)
+	FindReceivedAfterID(ctx context.Context, userID, lastID uuid.UUID, limit int) ([]types.ChatMessage, error)
 	MarkAsSeen(ctx context.Context, IDs uuid.UUIDs) error
+	MarkAsDelivered(ctx context.Context, IDs uuid.UUIDs, deliveredAt time.Time) ([]types.ChatMessage, error)
 }
 
 type chatMessageImpl struct {
@@ -41,6 +46,7 @@ func (r *chatMessageImpl) CreateTx(ctx context.Context, _tx dbUtil.Tx, req types
 			user_id,
 			content,
 			content_type,
+			thumbnail_image,
 			created_at
 		)
 		VALUES (
@@ -49,6 +55,7 @@ func (r *chatMessageImpl) CreateTx(ctx context.Context, _tx dbUtil.Tx, req types
 			:user_id,
 			:content,
 			:content_type,
+			:thumbnail_image,
 			:created_at
 		)
 	`
@@ -81,7 +88,8 @@ func (r *chatMessageImpl) CountUnreadReceivedByChatRoomIDs(ctx context.Context,
 	return res, nil
 }
 
-func (r *chatMessageImpl) FindByChatRoomID(ctx context.Context, roomID uuid.UUID) ([]types.ChatMessage, error) {
+// FindByChatRoomID orders the messages from the cursor, ascending when the filter has After and descending otherwise
+func (r *chatMessageImpl) FindByChatRoomID(ctx context.Context, roomID uuid.UUID, filter types.ChatMessageCursorFilter) ([]types.ChatMessage, error) {
 	res := []types.ChatMessage{}
 
 	query := `
@@ -91,14 +99,21 @@ func (r *chatMessageImpl) FindByChatRoomID(ctx context.Context, roomID uuid.UUID
 			user_id,
 			content,
 			content_type,
+			thumbnail_image,
 			read,
+			delivered_at,
 			created_at
 		FROM chat_messages
 		WHERE chat_room_id = $1
-		ORDER BY id ASC
+			AND ($2::UUID IS NULL OR id < $2)
+			AND ($3::UUID IS NULL OR id > $3)
+		ORDER BY
+			CASE WHEN $3::UUID IS NULL THEN id END DESC,
+			id ASC
+		LIMIT $4
 	`
 
-	if err := r.db.SelectContext(ctx, &res, query, roomID); err != nil {
+	if err := r.db.SelectContext(ctx, &res, query, roomID, filter.Before, filter.After, filter.Limit); err != nil {
 		return res, errors.New(err)
 	}
 
@@ -115,7 +130,9 @@ func (r *chatMessageImpl) FindLatestByChatRoomIDs(ctx context.Context, roomIDs u
 			user_id,
 			content,
 			content_type,
+			thumbnail_image,
 			read,
+			delivered_at,
 			created_at
 		FROM chat_messages
 		WHERE chat_room_id = ANY($1)
@@ -139,7 +156,9 @@ func (r *chatMessageImpl) FindReceivedByIDsAndRoomID(ctx context.Context, ids uu
 			user_id,
 			content,
 			content_type,
+			thumbnail_image,
 			read,
+			delivered_at,
 			created_at
 		FROM chat_messages
 		WHERE id = ANY($1)
@@ -155,10 +174,86 @@ func (r *chatMessageImpl) FindReceivedByIDsAndRoomID(ctx context.Context, ids uu
 	return res, nil
 }
 
+// FindReceivedAfterID returns the messages received by the user in all of their rooms after the message, the ids are
+// uuid v7 so they are ordered by the time the messages were sent
+func (r *chatMessageImpl) FindReceivedAfterID(ctx context.Context, userID, lastID uuid.UUID, limit int) ([]types.ChatMessage, error) {
+	res := []types.ChatMessage{}
+
+	query := `
+		SELECT
+			cm.id,
+			cm.chat_room_id,
+			cm.user_id,
+			cm.content,
+			cm.content_type,
+			cm.thumbnail_image,
+			cm.read,
+			cm.delivered_at,
+			cm.created_at
+		FROM chat_messages cm
+		JOIN chat_room_users cru ON cru.chat_room_id = cm.chat_room_id
+			AND cru.user_id = $1
+		WHERE cm.id > $2
+			AND cm.user_id != $1
+		ORDER BY cm.id ASC
+		LIMIT $3
+	`
+
+	if err := r.db.SelectContext(ctx, &res, query, userID, lastID, limit); err != nil {
+		return res, errors.New(err)
+	}
+
+	return res, nil
+}
+
+// Search matches the text messages of every room of the user from the newest one, Before of the filter is the id of
+// the last result of the previous page
+func (r *chatMessageImpl) Search(ctx context.Context, userID uuid.UUID, query string, filter types.ChatMessageCursorFilter) ([]types.ChatMessageSearchResult, error) {
+	res := []types.ChatMessageSearchResult{}
+
+	q := `
+		SELECT
+			cm.id,
+			cm.chat_room_id,
+			cm.user_id,
+			cm.content,
+			cm.content_type,
+			cm.thumbnail_image,
+			cm.read,
+			cm.delivered_at,
+			cm.created_at,
+			ts_headline('simple', cm.content, websearch_to_tsquery('simple', $2), $5) AS headline,
+			recipient.user_id AS recipient_user_id,
+			COALESCE(service_providers.name, users.name) AS recipient_name
+		FROM chat_messages cm
+		JOIN chat_room_users cru ON cru.chat_room_id = cm.chat_room_id
+			AND cru.user_id = $1
+		JOIN chat_room_users recipient ON recipient.chat_room_id = cm.chat_room_id
+			AND recipient.user_id != $1
+		JOIN users ON users.id = recipient.user_id
+		LEFT JOIN service_providers ON service_providers.user_id = recipient.user_id
+		WHERE cm.search_vector @@ websearch_to_tsquery('simple', $2)
+			AND ($3::UUID IS NULL OR cm.id < $3)
+		ORDER BY cm.id DESC
+		LIMIT $4
+	`
+
+	// the matched words are wrapped in control characters, the content is not escaped so it can't carry html tags yet
+	headlineOptions := fmt.Sprintf(`StartSel="%s", StopSel="%s", MaxFragments=1`, types.ChatSearchHeadlineStartSel, types.ChatSearchHeadlineStopSel)
+
+	if err := r.db.SelectContext(ctx, &res, q, userID, query, filter.Before, filter.Limit, headlineOptions); err != nil {
+		return res, errors.New(err)
+	}
+
+	return res, nil
+}
+
 func (r *chatMessageImpl) MarkAsSeen(ctx context.Context, IDs uuid.UUIDs) error {
 	query := `
 		UPDATE chat_messages
-		SET read = true
+		SET
+			read = true,
+			delivered_at = COALESCE(delivered_at, NOW())
 		WHERE id = ANY($1)
 	`
 
@@ -169,3 +264,31 @@ func (r *chatMessageImpl) MarkAsSeen(ctx context.Context, IDs uuid.UUIDs) error
 
 	return nil
 }
+
+// MarkAsDelivered returns only the messages that were not delivered yet, so a receipt is sent once for every message
+func (r *chatMessageImpl) MarkAsDelivered(ctx context.Context, IDs uuid.UUIDs, deliveredAt time.Time) ([]types.ChatMessage, error) {
+	res := []types.ChatMessage{}
+
+	query := `
+		UPDATE chat_messages
+		SET delivered_at = $2
+		WHERE id = ANY($1)
+			AND delivered_at IS NULL
+		RETURNING
+			id,
+			chat_room_id,
+			user_id,
+			content,
+			content_type,
+			thumbnail_image,
+			read,
+			delivered_at,
+			created_at
+	`
+
+	if err := r.db.SelectContext(ctx, &res, query, pq.Array(IDs), deliveredAt); err != nil {
+		return res, errors.New(err)
+	}
+
+	return res, nil
+}
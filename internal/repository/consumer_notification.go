@@ -8,11 +8,15 @@ import (
 	"github.com/go-errors/errors"
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 )
 
 type ConsumerNotification interface {
 	CreateTx(ctx context.Context, tx dbUtil.Tx, req types.ConsumerNotification) error
-	FindAllByUserID(ctx context.Context, userID uuid.UUID) ([]types.ConsumerNotificationWithServiceProviderAndPayment, error)
+	FindAllByUserID(ctx context.Context, userID uuid.UUID, filter types.NotificationCursorFilter) ([]types.ConsumerNotificationWithServiceProviderAndPayment, error)
+	CountUnreadByUserID(ctx context.Context, userID uuid.UUID) (int64, error)
+	UpdateAsReadByIDs(ctx context.Context, userID uuid.UUID, IDs uuid.UUIDs) (int64, error)
+	UpdateAllAsReadByUserID(ctx context.Context, userID uuid.UUID) error
 }
 
 type consumerNotificationImpl struct {
@@ -38,6 +42,7 @@ func (r *consumerNotificationImpl) CreateTx(ctx context.Context, _tx dbUtil.Tx,
 			offer_negotiation_id,
 			payment_id,
 			order_id,
+			dispute_id,
 			type,
 			created_at
 		)
@@ -47,6 +52,7 @@ func (r *consumerNotificationImpl) CreateTx(ctx context.Context, _tx dbUtil.Tx,
 			:offer_negotiation_id,
 			:payment_id,
 			:order_id,
+			:dispute_id,
 			:type,
 			:created_at
 		)
@@ -59,7 +65,8 @@ func (r *consumerNotificationImpl) CreateTx(ctx context.Context, _tx dbUtil.Tx,
 	return nil
 }
 
-func (r *consumerNotificationImpl) FindAllByUserID(ctx context.Context, userID uuid.UUID) ([]types.ConsumerNotificationWithServiceProviderAndPayment, error) {
+// FindAllByUserID leaves out the types the user turned off for the in app channel
+func (r *consumerNotificationImpl) FindAllByUserID(ctx context.Context, userID uuid.UUID, filter types.NotificationCursorFilter) ([]types.ConsumerNotificationWithServiceProviderAndPayment, error) {
 	res := []types.ConsumerNotificationWithServiceProviderAndPayment{}
 
 	query := `
@@ -69,6 +76,7 @@ func (r *consumerNotificationImpl) FindAllByUserID(ctx context.Context, userID u
 			consumer_notifications.offer_negotiation_id,
 			consumer_notifications.payment_id,
 			consumer_notifications.order_id,
+			consumer_notifications.dispute_id,
 			consumer_notifications.type,
 			consumer_notifications.read,
 			consumer_notifications.created_at,
@@ -95,12 +103,82 @@ func (r *consumerNotificationImpl) FindAllByUserID(ctx context.Context, userID u
 			ON service_providers.id = orders.service_provider_id
 				OR service_providers.id = services.service_provider_id
 		WHERE consumer_notifications.user_id = $1
+			AND ($2::UUID IS NULL OR consumer_notifications.id < $2)
+			AND consumer_notifications.type NOT IN (
+				SELECT type
+				FROM notification_preferences
+				WHERE user_id = $1
+					AND channel = 'in_app'
+					AND enabled = FALSE
+			)
 		ORDER BY consumer_notifications.id DESC
+		LIMIT $3
 	`
 
-	if err := r.db.SelectContext(ctx, &res, query, userID); err != nil {
+	if err := r.db.SelectContext(ctx, &res, query, userID, filter.After, filter.Limit); err != nil {
 		return res, errors.New(err)
 	}
 
 	return res, nil
 }
+
+func (r *consumerNotificationImpl) CountUnreadByUserID(ctx context.Context, userID uuid.UUID) (int64, error) {
+	var res int64
+
+	query := `
+		SELECT COUNT(id)
+		FROM consumer_notifications
+		WHERE user_id = $1
+			AND read = FALSE
+			AND type NOT IN (
+				SELECT type
+				FROM notification_preferences
+				WHERE user_id = $1
+					AND channel = 'in_app'
+					AND enabled = FALSE
+			)
+	`
+
+	if err := r.db.GetContext(ctx, &res, query, userID); err != nil {
+		return res, errors.New(err)
+	}
+
+	return res, nil
+}
+
+// UpdateAsReadByIDs returns how many of the notifications belong to the user, a notification of another user is left untouched
+func (r *consumerNotificationImpl) UpdateAsReadByIDs(ctx context.Context, userID uuid.UUID, IDs uuid.UUIDs) (int64, error) {
+	query := `
+		UPDATE consumer_notifications
+		SET read = TRUE
+		WHERE user_id = $1
+			AND id = ANY($2)
+	`
+
+	result, err := r.db.ExecContext(ctx, query, userID, pq.Array(IDs))
+	if err != nil {
+		return 0, errors.New(err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.New(err)
+	}
+
+	return affected, nil
+}
+
+func (r *consumerNotificationImpl) UpdateAllAsReadByUserID(ctx context.Context, userID uuid.UUID) error {
+	query := `
+		UPDATE consumer_notifications
+		SET read = TRUE
+		WHERE user_id = $1
+			AND read = FALSE
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, userID); err != nil {
+		return errors.New(err)
+	}
+
+	return nil
+}
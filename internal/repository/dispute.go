@@ -0,0 +1,292 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"kelarin/internal/types"
+	dbUtil "kelarin/internal/utils/dbutil"
+
+	"github.com/go-errors/errors"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type Dispute interface {
+	CreateTx(ctx context.Context, _tx dbUtil.Tx, req types.Dispute) error
+	FindByID(ctx context.Context, ID uuid.UUID) (types.DisputeWithRelations, error)
+	FindByOrderID(ctx context.Context, orderID uuid.UUID) (types.Dispute, error)
+	FindAllByFilter(ctx context.Context, filter types.DisputeFilter) ([]types.DisputeWithRelations, int64, error)
+	FindForUpdateByID(ctx context.Context, _tx dbUtil.Tx, ID uuid.UUID) (types.Dispute, error)
+	UpdateResponseTx(ctx context.Context, _tx dbUtil.Tx, req types.Dispute) error
+	UpdateResolutionTx(ctx context.Context, _tx dbUtil.Tx, req types.Dispute) error
+}
+
+type disputeImpl struct {
+	db *sqlx.DB
+}
+
+func NewDispute(db *sqlx.DB) Dispute {
+	return &disputeImpl{db: db}
+}
+
+func (r *disputeImpl) CreateTx(ctx context.Context, _tx dbUtil.Tx, req types.Dispute) error {
+	tx, err := dbUtil.CastSqlxTx(_tx)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO disputes (
+			id,
+			order_id,
+			user_id,
+			service_provider_id,
+			reason,
+			evidence_images,
+			status,
+			created_at
+		)
+		VALUES (
+			:id,
+			:order_id,
+			:user_id,
+			:service_provider_id,
+			:reason,
+			:evidence_images,
+			:status,
+			:created_at
+		)
+	`
+
+	if _, err := tx.NamedExecContext(ctx, query, req); err != nil {
+		return errors.New(err)
+	}
+
+	return nil
+}
+
+func (r *disputeImpl) FindByID(ctx context.Context, ID uuid.UUID) (types.DisputeWithRelations, error) {
+	res := types.DisputeWithRelations{}
+
+	query := `
+		SELECT
+			disputes.id,
+			disputes.order_id,
+			disputes.user_id,
+			disputes.service_provider_id,
+			disputes.reason,
+			disputes.evidence_images,
+			disputes.status,
+			disputes.provider_response,
+			disputes.provider_responded_at,
+			disputes.outcome,
+			disputes.refund_amount,
+			disputes.admin_user_id,
+			disputes.admin_note,
+			disputes.resolved_at,
+			disputes.created_at,
+			disputes.updated_at,
+			users.name AS user_name,
+			service_providers.name AS service_provider_name,
+			service_providers.user_id AS service_provider_user_id,
+			orders.service_fee AS order_service_fee
+		FROM disputes
+		INNER JOIN users
+			ON users.id = disputes.user_id
+		INNER JOIN service_providers
+			ON service_providers.id = disputes.service_provider_id
+		INNER JOIN orders
+			ON orders.id = disputes.order_id
+		WHERE disputes.id = $1
+	`
+
+	err := r.db.GetContext(ctx, &res, query, ID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return res, errors.New(types.ErrNoData)
+	} else if err != nil {
+		return res, errors.New(err)
+	}
+
+	return res, nil
+}
+
+func (r *disputeImpl) FindByOrderID(ctx context.Context, orderID uuid.UUID) (types.Dispute, error) {
+	res := types.Dispute{}
+
+	query := `
+		SELECT
+			id,
+			order_id,
+			user_id,
+			service_provider_id,
+			reason,
+			evidence_images,
+			status,
+			provider_response,
+			provider_responded_at,
+			outcome,
+			refund_amount,
+			admin_user_id,
+			admin_note,
+			resolved_at,
+			created_at,
+			updated_at
+		FROM disputes
+		WHERE order_id = $1
+	`
+
+	err := r.db.GetContext(ctx, &res, query, orderID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return res, errors.New(types.ErrNoData)
+	} else if err != nil {
+		return res, errors.New(err)
+	}
+
+	return res, nil
+}
+
+func (r *disputeImpl) FindAllByFilter(ctx context.Context, filter types.DisputeFilter) ([]types.DisputeWithRelations, int64, error) {
+	res := []types.DisputeWithRelations{}
+	var total int64
+
+	where := `
+		WHERE ($1::UUID IS NULL OR disputes.user_id = $1)
+			AND ($2::UUID IS NULL OR disputes.service_provider_id = $2)
+			AND ($3 = '' OR disputes.status::TEXT = $3)
+	`
+
+	query := `
+		SELECT
+			disputes.id,
+			disputes.order_id,
+			disputes.user_id,
+			disputes.service_provider_id,
+			disputes.reason,
+			disputes.evidence_images,
+			disputes.status,
+			disputes.provider_response,
+			disputes.provider_responded_at,
+			disputes.outcome,
+			disputes.refund_amount,
+			disputes.admin_user_id,
+			disputes.admin_note,
+			disputes.resolved_at,
+			disputes.created_at,
+			disputes.updated_at,
+			users.name AS user_name,
+			service_providers.name AS service_provider_name,
+			service_providers.user_id AS service_provider_user_id,
+			orders.service_fee AS order_service_fee
+		FROM disputes
+		INNER JOIN users
+			ON users.id = disputes.user_id
+		INNER JOIN service_providers
+			ON service_providers.id = disputes.service_provider_id
+		INNER JOIN orders
+			ON orders.id = disputes.order_id
+	` + where + `
+		ORDER BY disputes.id DESC
+		LIMIT $4 OFFSET $5
+	`
+
+	err := r.db.SelectContext(ctx, &res, query, filter.UserID, filter.ServiceProviderID, filter.Status, filter.Limit, filter.Offset)
+	if err != nil {
+		return res, total, errors.New(err)
+	}
+
+	query = `SELECT COUNT(disputes.id) FROM disputes ` + where
+
+	if err = r.db.GetContext(ctx, &total, query, filter.UserID, filter.ServiceProviderID, filter.Status); err != nil {
+		return res, total, errors.New(err)
+	}
+
+	return res, total, nil
+}
+
+func (r *disputeImpl) FindForUpdateByID(ctx context.Context, _tx dbUtil.Tx, ID uuid.UUID) (types.Dispute, error) {
+	res := types.Dispute{}
+
+	tx, err := dbUtil.CastSqlxTx(_tx)
+	if err != nil {
+		return res, err
+	}
+
+	query := `
+		SELECT
+			id,
+			order_id,
+			user_id,
+			service_provider_id,
+			reason,
+			evidence_images,
+			status,
+			provider_response,
+			provider_responded_at,
+			outcome,
+			refund_amount,
+			admin_user_id,
+			admin_note,
+			resolved_at,
+			created_at,
+			updated_at
+		FROM disputes
+		WHERE id = $1
+		FOR UPDATE
+	`
+
+	err = tx.GetContext(ctx, &res, query, ID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return res, errors.New(types.ErrNoData)
+	} else if err != nil {
+		return res, errors.New(err)
+	}
+
+	return res, nil
+}
+
+func (r *disputeImpl) UpdateResponseTx(ctx context.Context, _tx dbUtil.Tx, req types.Dispute) error {
+	tx, err := dbUtil.CastSqlxTx(_tx)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE disputes
+		SET status = :status,
+			provider_response = :provider_response,
+			provider_responded_at = :provider_responded_at,
+			updated_at = :updated_at
+		WHERE id = :id
+	`
+
+	if _, err := tx.NamedExecContext(ctx, query, req); err != nil {
+		return errors.New(err)
+	}
+
+	return nil
+}
+
+func (r *disputeImpl) UpdateResolutionTx(ctx context.Context, _tx dbUtil.Tx, req types.Dispute) error {
+	tx, err := dbUtil.CastSqlxTx(_tx)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE disputes
+		SET status = :status,
+			outcome = :outcome,
+			refund_amount = :refund_amount,
+			admin_user_id = :admin_user_id,
+			admin_note = :admin_note,
+			resolved_at = :resolved_at,
+			updated_at = :updated_at
+		WHERE id = :id
+	`
+
+	if _, err := tx.NamedExecContext(ctx, query, req); err != nil {
+		return errors.New(err)
+	}
+
+	return nil
+}
@@ -2,16 +2,20 @@ package repository
 
 import (
 	"context"
+	"encoding/json"
 	"kelarin/internal/types"
 	"time"
 
 	"github.com/go-errors/errors"
+	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
 )
 
 type FCMToken interface {
-	Find(ctx context.Context, key string) (string, error)
-	Save(ctx context.Context, key, token string, expiration time.Duration) error
+	FindAllByUserID(ctx context.Context, userID uuid.UUID) ([]types.FCMToken, error)
+	FindBySessionID(ctx context.Context, userID, sessionID uuid.UUID) (types.FCMToken, error)
+	Save(ctx context.Context, userID uuid.UUID, req types.FCMToken, expiration time.Duration) error
+	DeleteBySessionIDs(ctx context.Context, userID uuid.UUID, sessionIDs ...uuid.UUID) error
 }
 
 type fcmTokenImpl struct {
@@ -24,21 +28,75 @@ func NewFCMToken(redisDB *redis.Client) FCMToken {
 	}
 }
 
-func (r *fcmTokenImpl) Find(ctx context.Context, key string) (string, error) {
-	res := ""
+func (r *fcmTokenImpl) FindAllByUserID(ctx context.Context, userID uuid.UUID) ([]types.FCMToken, error) {
+	res := []types.FCMToken{}
 
-	err := r.redisDB.Get(ctx, key).Scan(&res)
+	vals, err := r.redisDB.HVals(ctx, types.FCMTokensKey(userID)).Result()
+	if err != nil {
+		return res, errors.New(err)
+	}
+
+	for _, val := range vals {
+		token := types.FCMToken{}
+		if err := json.Unmarshal([]byte(val), &token); err != nil {
+			return res, errors.New(err)
+		}
+
+		res = append(res, token)
+	}
+
+	return res, nil
+}
+
+func (r *fcmTokenImpl) FindBySessionID(ctx context.Context, userID, sessionID uuid.UUID) (types.FCMToken, error) {
+	res := types.FCMToken{}
+
+	val, err := r.redisDB.HGet(ctx, types.FCMTokensKey(userID), sessionID.String()).Result()
 	if errors.Is(err, redis.Nil) {
-		return res, types.ErrNoData
+		return res, errors.New(types.ErrNoData)
 	} else if err != nil {
 		return res, errors.New(err)
 	}
 
+	if err := json.Unmarshal([]byte(val), &res); err != nil {
+		return res, errors.New(err)
+	}
+
 	return res, nil
 }
 
-func (r *fcmTokenImpl) Save(ctx context.Context, key, token string, expiration time.Duration) error {
-	if err := r.redisDB.Set(ctx, key, token, expiration).Err(); err != nil {
+// Save stores the token under its session, the expiration is refreshed for every device of the user
+func (r *fcmTokenImpl) Save(ctx context.Context, userID uuid.UUID, req types.FCMToken, expiration time.Duration) error {
+	val, err := json.Marshal(req)
+	if err != nil {
+		return errors.New(err)
+	}
+
+	key := types.FCMTokensKey(userID)
+
+	pipe := r.redisDB.TxPipeline()
+
+	pipe.HSet(ctx, key, req.SessionID.String(), val)
+	pipe.Expire(ctx, key, expiration)
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return errors.New(err)
+	}
+
+	return nil
+}
+
+func (r *fcmTokenImpl) DeleteBySessionIDs(ctx context.Context, userID uuid.UUID, sessionIDs ...uuid.UUID) error {
+	if len(sessionIDs) == 0 {
+		return nil
+	}
+
+	fields := []string{}
+	for _, sessionID := range sessionIDs {
+		fields = append(fields, sessionID.String())
+	}
+
+	if err := r.redisDB.HDel(ctx, types.FCMTokensKey(userID), fields...).Err(); err != nil {
 		return errors.New(err)
 	}
 
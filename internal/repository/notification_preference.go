@@ -0,0 +1,170 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"kelarin/internal/types"
+	dbUtil "kelarin/internal/utils/dbutil"
+
+	"github.com/go-errors/errors"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type NotificationPreference interface {
+	FindAllByUserID(ctx context.Context, userID uuid.UUID) ([]types.NotificationPreference, error)
+	FindByUserIDAndTypeAndChannel(ctx context.Context, userID uuid.UUID, notificationType int16, channel types.NotificationChannel) (types.NotificationPreference, error)
+	UpsertManyTx(ctx context.Context, tx dbUtil.Tx, req []types.NotificationPreference) error
+	FindQuietHoursByUserID(ctx context.Context, userID uuid.UUID) (types.NotificationQuietHours, error)
+	UpsertQuietHours(ctx context.Context, req types.NotificationQuietHours) error
+}
+
+type notificationPreferenceImpl struct {
+	db *sqlx.DB
+}
+
+func NewNotificationPreference(db *sqlx.DB) NotificationPreference {
+	return &notificationPreferenceImpl{db: db}
+}
+
+func (r *notificationPreferenceImpl) FindAllByUserID(ctx context.Context, userID uuid.UUID) ([]types.NotificationPreference, error) {
+	res := []types.NotificationPreference{}
+
+	query := `
+		SELECT
+			user_id,
+			type,
+			channel,
+			enabled,
+			updated_at
+		FROM notification_preferences
+		WHERE user_id = $1
+	`
+
+	if err := r.db.SelectContext(ctx, &res, query, userID); err != nil {
+		return res, errors.New(err)
+	}
+
+	return res, nil
+}
+
+func (r *notificationPreferenceImpl) FindByUserIDAndTypeAndChannel(ctx context.Context, userID uuid.UUID, notificationType int16, channel types.NotificationChannel) (types.NotificationPreference, error) {
+	res := types.NotificationPreference{}
+
+	query := `
+		SELECT
+			user_id,
+			type,
+			channel,
+			enabled,
+			updated_at
+		FROM notification_preferences
+		WHERE user_id = $1
+			AND type = $2
+			AND channel = $3
+	`
+
+	err := r.db.GetContext(ctx, &res, query, userID, notificationType, channel)
+	if errors.Is(err, sql.ErrNoRows) {
+		return res, errors.New(types.ErrNoData)
+	} else if err != nil {
+		return res, errors.New(err)
+	}
+
+	return res, nil
+}
+
+func (r *notificationPreferenceImpl) UpsertManyTx(ctx context.Context, _tx dbUtil.Tx, req []types.NotificationPreference) error {
+	tx, err := dbUtil.CastSqlxTx(_tx)
+	if err != nil {
+		return err
+	}
+
+	if len(req) == 0 {
+		return nil
+	}
+
+	query := `
+		INSERT INTO notification_preferences (
+			user_id,
+			type,
+			channel,
+			enabled,
+			updated_at
+		)
+		VALUES (
+			:user_id,
+			:type,
+			:channel,
+			:enabled,
+			:updated_at
+		)
+		ON CONFLICT (user_id, type, channel) DO UPDATE SET
+			enabled = EXCLUDED.enabled,
+			updated_at = EXCLUDED.updated_at
+	`
+
+	if _, err = tx.NamedExecContext(ctx, query, req); err != nil {
+		return errors.New(err)
+	}
+
+	return nil
+}
+
+func (r *notificationPreferenceImpl) FindQuietHoursByUserID(ctx context.Context, userID uuid.UUID) (types.NotificationQuietHours, error) {
+	res := types.NotificationQuietHours{}
+
+	query := `
+		SELECT
+			user_id,
+			enabled,
+			start_time,
+			end_time,
+			time_zone,
+			updated_at
+		FROM notification_quiet_hours
+		WHERE user_id = $1
+	`
+
+	err := r.db.GetContext(ctx, &res, query, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return res, errors.New(types.ErrNoData)
+	} else if err != nil {
+		return res, errors.New(err)
+	}
+
+	return res, nil
+}
+
+func (r *notificationPreferenceImpl) UpsertQuietHours(ctx context.Context, req types.NotificationQuietHours) error {
+	query := `
+		INSERT INTO notification_quiet_hours (
+			user_id,
+			enabled,
+			start_time,
+			end_time,
+			time_zone,
+			updated_at
+		)
+		VALUES (
+			:user_id,
+			:enabled,
+			:start_time,
+			:end_time,
+			:time_zone,
+			:updated_at
+		)
+		ON CONFLICT (user_id) DO UPDATE SET
+			enabled = EXCLUDED.enabled,
+			start_time = EXCLUDED.start_time,
+			end_time = EXCLUDED.end_time,
+			time_zone = EXCLUDED.time_zone,
+			updated_at = EXCLUDED.updated_at
+	`
+
+	if _, err := r.db.NamedExecContext(ctx, query, req); err != nil {
+		return errors.New(err)
+	}
+
+	return nil
+}
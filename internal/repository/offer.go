@@ -57,6 +57,7 @@ func (r *offerImpl) CreateTx(ctx context.Context, _tx dbUtil.Tx, offer types.Off
 			service_end_date,
 			service_start_time,
 			service_end_time,
+			service_schedule,
 			status,
 			created_at
 		)
@@ -71,6 +72,7 @@ func (r *offerImpl) CreateTx(ctx context.Context, _tx dbUtil.Tx, offer types.Off
 			:service_end_date,
 			:service_start_time,
 			:service_end_time,
+			:service_schedule,
 			:status,
 			:created_at
 		)
@@ -118,6 +120,7 @@ func (r *offerImpl) FindAllByUserID(ctx context.Context, userID uuid.UUID) ([]ty
 			offers.service_end_date,
 			offers.service_start_time,
 			offers.service_end_time,
+			offers.service_schedule,
 			offers.status,
 			offers.created_at,
 			services.name AS service_name,
@@ -156,6 +159,7 @@ func (r *offerImpl) FindByIDAndUserID(ctx context.Context, ID, userID uuid.UUID)
 			service_end_date,
 			service_start_time,
 			service_end_time,
+			service_schedule,
 			status,
 			created_at
 		FROM offers
@@ -188,6 +192,7 @@ func (r *offerImpl) FindByIDAndServiceProviderID(ctx context.Context, ID, servic
 			offers.service_end_date,
 			offers.service_start_time,
 			offers.service_end_time,
+			offers.service_schedule,
 			offers.status,
 			offers.created_at
 		FROM offers
@@ -249,6 +254,7 @@ func (r *offerImpl) FindAllByServiceProviderID(ctx context.Context, serviceProvi
 			offers.service_end_date,
 			offers.service_start_time,
 			offers.service_end_time,
+			offers.service_schedule,
 			offers.status,
 			offers.created_at
 		FROM offers
@@ -415,6 +421,7 @@ func (r *offerImpl) FindByID(ctx context.Context, ID uuid.UUID) (types.Offer, er
 			service_end_date,
 			service_start_time,
 			service_end_time,
+			service_schedule,
 			status,
 			created_at
 		FROM offers
@@ -34,6 +34,8 @@ type Order interface {
 	FindIDsWhereExpired(ctx context.Context) (uuid.UUIDs, error)
 	FindIDsWhereOngoingToday(ctx context.Context, date time.Time) (uuid.UUIDs, error)
 	UpdateStatusByIDs(ctx context.Context, _tx dbUtil.Tx, ids uuid.UUIDs, status types.OrderStatus) error
+	FindForUpdateByID(ctx context.Context, _tx dbUtil.Tx, ID uuid.UUID) (types.Order, error)
+	UpdateAsCanceledTx(ctx context.Context, _tx dbUtil.Tx, req types.Order) error
 }
 
 type orderImpl struct {
@@ -602,3 +604,67 @@ func (r *orderImpl) UpdateStatusByIDs(ctx context.Context, _tx dbUtil.Tx, ids uu
 
 	return nil
 }
+
+func (r *orderImpl) FindForUpdateByID(ctx context.Context, _tx dbUtil.Tx, ID uuid.UUID) (types.Order, error) {
+	res := types.Order{}
+
+	tx, err := dbUtil.CastSqlxTx(_tx)
+	if err != nil {
+		return res, err
+	}
+
+	query := `
+		SELECT
+			id,
+			user_id,
+			service_provider_id,
+			offer_id,
+			payment_id,
+			payment_fulfilled,
+			service_fee,
+			service_date,
+			service_time,
+			status,
+			canceled_by,
+			cancellation_reason,
+			canceled_at,
+			created_at,
+			updated_at
+		FROM orders
+		WHERE id = $1
+		FOR UPDATE
+	`
+
+	err = tx.GetContext(ctx, &res, query, ID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return res, errors.New(types.ErrNoData)
+	} else if err != nil {
+		return res, errors.New(err)
+	}
+
+	return res, nil
+}
+
+func (r *orderImpl) UpdateAsCanceledTx(ctx context.Context, _tx dbUtil.Tx, req types.Order) error {
+	tx, err := dbUtil.CastSqlxTx(_tx)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE orders
+		SET
+			status = :status,
+			canceled_by = :canceled_by,
+			cancellation_reason = :cancellation_reason,
+			canceled_at = :canceled_at,
+			updated_at = :updated_at
+		WHERE id = :id
+	`
+
+	if _, err := tx.NamedExecContext(ctx, query, req); err != nil {
+		return errors.New(err)
+	}
+
+	return nil
+}
@@ -0,0 +1,213 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"kelarin/internal/types"
+	dbUtil "kelarin/internal/utils/dbutil"
+	"time"
+
+	"github.com/go-errors/errors"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type OrderSession interface {
+	BulkCreateTx(ctx context.Context, tx dbUtil.Tx, req []types.OrderSession) error
+	FindAllByOrderID(ctx context.Context, orderID uuid.UUID) (types.OrderSessions, error)
+	FindForUpdateByID(ctx context.Context, tx dbUtil.Tx, ID uuid.UUID) (types.OrderSession, error)
+	UpdateAsFinishedTx(ctx context.Context, tx dbUtil.Tx, req types.OrderSession) error
+	UpdateAsCanceledByOrderIDsTx(ctx context.Context, tx dbUtil.Tx, orderIDs uuid.UUIDs) error
+	FindIDsWhereOngoingToday(ctx context.Context, date time.Time) (uuid.UUIDs, error)
+	UpdateStatusByIDs(ctx context.Context, tx dbUtil.Tx, ids uuid.UUIDs, status types.OrderSessionStatus) error
+}
+
+type orderSessionImpl struct {
+	db *sqlx.DB
+}
+
+func NewOrderSession(db *sqlx.DB) OrderSession {
+	return &orderSessionImpl{db: db}
+}
+
+func (r *orderSessionImpl) BulkCreateTx(ctx context.Context, _tx dbUtil.Tx, req []types.OrderSession) error {
+	tx, err := dbUtil.CastSqlxTx(_tx)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO order_sessions (
+			id,
+			order_id,
+			sequence,
+			service_date,
+			service_time,
+			status,
+			created_at
+		)
+		VALUES (
+			:id,
+			:order_id,
+			:sequence,
+			:service_date,
+			:service_time,
+			:status,
+			:created_at
+		)
+	`
+
+	if _, err = tx.NamedExecContext(ctx, query, req); err != nil {
+		return errors.New(err)
+	}
+
+	return nil
+}
+
+func (r *orderSessionImpl) FindAllByOrderID(ctx context.Context, orderID uuid.UUID) (types.OrderSessions, error) {
+	res := types.OrderSessions{}
+
+	query := `
+		SELECT
+			id,
+			order_id,
+			sequence,
+			service_date,
+			service_time,
+			status,
+			finished_at,
+			created_at,
+			updated_at
+		FROM order_sessions
+		WHERE order_id = $1
+		ORDER BY sequence ASC
+	`
+
+	if err := r.db.SelectContext(ctx, &res, query, orderID); err != nil {
+		return nil, errors.New(err)
+	}
+
+	return res, nil
+}
+
+func (r *orderSessionImpl) FindForUpdateByID(ctx context.Context, _tx dbUtil.Tx, ID uuid.UUID) (types.OrderSession, error) {
+	res := types.OrderSession{}
+
+	tx, err := dbUtil.CastSqlxTx(_tx)
+	if err != nil {
+		return res, err
+	}
+
+	query := `
+		SELECT
+			id,
+			order_id,
+			sequence,
+			service_date,
+			service_time,
+			status,
+			finished_at,
+			created_at,
+			updated_at
+		FROM order_sessions
+		WHERE id = $1
+		FOR UPDATE
+	`
+
+	err = tx.GetContext(ctx, &res, query, ID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return res, errors.New(types.ErrNoData)
+	} else if err != nil {
+		return res, errors.New(err)
+	}
+
+	return res, nil
+}
+
+func (r *orderSessionImpl) UpdateAsFinishedTx(ctx context.Context, _tx dbUtil.Tx, req types.OrderSession) error {
+	tx, err := dbUtil.CastSqlxTx(_tx)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE order_sessions
+		SET
+			status = :status,
+			finished_at = :finished_at,
+			updated_at = :updated_at
+		WHERE id = :id
+	`
+
+	if _, err = tx.NamedExecContext(ctx, query, req); err != nil {
+		return errors.New(err)
+	}
+
+	return nil
+}
+
+func (r *orderSessionImpl) UpdateAsCanceledByOrderIDsTx(ctx context.Context, _tx dbUtil.Tx, orderIDs uuid.UUIDs) error {
+	tx, err := dbUtil.CastSqlxTx(_tx)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE order_sessions
+		SET
+			status = 'canceled',
+			updated_at = NOW()
+		WHERE
+			order_id = ANY($1)
+			AND status IN ('pending', 'ongoing')
+	`
+
+	if _, err = tx.ExecContext(ctx, query, pq.Array(orderIDs)); err != nil {
+		return errors.New(err)
+	}
+
+	return nil
+}
+
+func (r *orderSessionImpl) FindIDsWhereOngoingToday(ctx context.Context, date time.Time) (uuid.UUIDs, error) {
+	res := uuid.UUIDs{}
+
+	query := `
+		SELECT order_sessions.id
+		FROM order_sessions
+		JOIN orders ON orders.id = order_sessions.order_id
+		WHERE
+			order_sessions.service_date = $1::DATE
+			AND order_sessions.status = 'pending'
+			AND orders.status IN ('pending', 'ongoing')
+	`
+
+	if err := r.db.SelectContext(ctx, &res, query, date); err != nil {
+		return nil, errors.New(err)
+	}
+
+	return res, nil
+}
+
+func (r *orderSessionImpl) UpdateStatusByIDs(ctx context.Context, _tx dbUtil.Tx, ids uuid.UUIDs, status types.OrderSessionStatus) error {
+	tx, err := dbUtil.CastSqlxTx(_tx)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE order_sessions
+		SET
+			status = $1,
+			updated_at = NOW()
+		WHERE
+			id = ANY($2)
+	`
+
+	if _, err = tx.ExecContext(ctx, query, status, pq.Array(ids)); err != nil {
+		return errors.New(err)
+	}
+
+	return nil
+}
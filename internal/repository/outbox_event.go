@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"kelarin/internal/types"
+	dbUtil "kelarin/internal/utils/dbutil"
+	"time"
+
+	"github.com/go-errors/errors"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type OutboxEvent interface {
+	BulkCreateTx(ctx context.Context, tx dbUtil.Tx, req []types.OutboxEvent) error
+	FindForUpdateByID(ctx context.Context, tx dbUtil.Tx, ID uuid.UUID) (types.OutboxEvent, error)
+	UpdateTx(ctx context.Context, tx dbUtil.Tx, req types.OutboxEvent) error
+	FindPendingIDs(ctx context.Context, createdBefore time.Time, limit int) (uuid.UUIDs, error)
+}
+
+type outboxEventImpl struct {
+	db *sqlx.DB
+}
+
+func NewOutboxEvent(db *sqlx.DB) OutboxEvent {
+	return &outboxEventImpl{db: db}
+}
+
+func (r *outboxEventImpl) BulkCreateTx(ctx context.Context, _tx dbUtil.Tx, req []types.OutboxEvent) error {
+	tx, err := dbUtil.CastSqlxTx(_tx)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO outbox_events (
+			id,
+			type,
+			payload,
+			status,
+			created_at
+		)
+		VALUES (
+			:id,
+			:type,
+			:payload,
+			:status,
+			:created_at
+		)
+	`
+
+	if _, err = tx.NamedExecContext(ctx, query, req); err != nil {
+		return errors.New(err)
+	}
+
+	return nil
+}
+
+// FindForUpdateByID skips a row locked by another worker, it is reported as no data
+func (r *outboxEventImpl) FindForUpdateByID(ctx context.Context, _tx dbUtil.Tx, ID uuid.UUID) (types.OutboxEvent, error) {
+	res := types.OutboxEvent{}
+
+	tx, err := dbUtil.CastSqlxTx(_tx)
+	if err != nil {
+		return res, err
+	}
+
+	query := `
+		SELECT
+			id,
+			type,
+			payload,
+			status,
+			attempts,
+			last_error,
+			created_at,
+			processed_at
+		FROM outbox_events
+		WHERE id = $1
+		FOR UPDATE SKIP LOCKED
+	`
+
+	err = tx.GetContext(ctx, &res, query, ID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return res, errors.New(types.ErrNoData)
+	} else if err != nil {
+		return res, errors.New(err)
+	}
+
+	return res, nil
+}
+
+func (r *outboxEventImpl) UpdateTx(ctx context.Context, _tx dbUtil.Tx, req types.OutboxEvent) error {
+	tx, err := dbUtil.CastSqlxTx(_tx)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE outbox_events
+		SET
+			status = :status,
+			attempts = :attempts,
+			last_error = :last_error,
+			processed_at = :processed_at
+		WHERE id = :id
+	`
+
+	if _, err = tx.NamedExecContext(ctx, query, req); err != nil {
+		return errors.New(err)
+	}
+
+	return nil
+}
+
+func (r *outboxEventImpl) FindPendingIDs(ctx context.Context, createdBefore time.Time, limit int) (uuid.UUIDs, error) {
+	res := uuid.UUIDs{}
+
+	query := `
+		SELECT id
+		FROM outbox_events
+		WHERE status = 'pending'
+			AND created_at < $1
+		ORDER BY created_at ASC
+		LIMIT $2
+	`
+
+	if err := r.db.SelectContext(ctx, &res, query, createdBefore, limit); err != nil {
+		return res, errors.New(err)
+	}
+
+	return res, nil
+}
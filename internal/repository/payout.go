@@ -0,0 +1,186 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"kelarin/internal/types"
+	dbUtil "kelarin/internal/utils/dbutil"
+
+	"github.com/go-errors/errors"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/shopspring/decimal"
+)
+
+type Payout interface {
+	CreateTx(ctx context.Context, _tx dbUtil.Tx, req types.Payout) error
+	FindAllByFilter(ctx context.Context, filter types.PayoutFilter) ([]types.PayoutWithBankAccount, int64, error)
+	FindForUpdateByID(ctx context.Context, _tx dbUtil.Tx, ID uuid.UUID) (types.Payout, error)
+	UpdateStatusTx(ctx context.Context, _tx dbUtil.Tx, req types.Payout) error
+	SumUnpaidAmountByServiceProviderID(ctx context.Context, serviceProviderID uuid.UUID) (decimal.Decimal, error)
+}
+
+type payoutImpl struct {
+	db *sqlx.DB
+}
+
+func NewPayout(db *sqlx.DB) Payout {
+	return &payoutImpl{db: db}
+}
+
+func (r *payoutImpl) CreateTx(ctx context.Context, _tx dbUtil.Tx, req types.Payout) error {
+	tx, err := dbUtil.CastSqlxTx(_tx)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO payouts (
+			id,
+			service_provider_id,
+			service_provider_bank_account_id,
+			amount,
+			status,
+			created_at
+		)
+		VALUES (
+			:id,
+			:service_provider_id,
+			:service_provider_bank_account_id,
+			:amount,
+			:status,
+			:created_at
+		)
+	`
+
+	if _, err := tx.NamedExecContext(ctx, query, req); err != nil {
+		return errors.New(err)
+	}
+
+	return nil
+}
+
+func (r *payoutImpl) FindAllByFilter(ctx context.Context, filter types.PayoutFilter) ([]types.PayoutWithBankAccount, int64, error) {
+	res := []types.PayoutWithBankAccount{}
+	var total int64
+
+	where := `
+		WHERE ($1::UUID IS NULL OR payouts.service_provider_id = $1)
+			AND ($2 = '' OR payouts.status::TEXT = $2)
+	`
+
+	query := `
+		SELECT
+			payouts.id,
+			payouts.service_provider_id,
+			payouts.service_provider_bank_account_id,
+			payouts.amount,
+			payouts.status,
+			payouts.admin_user_id,
+			payouts.note,
+			payouts.created_at,
+			payouts.updated_at,
+			payouts.paid_at,
+			service_providers.name AS service_provider_name,
+			service_provider_bank_accounts.bank_name,
+			service_provider_bank_accounts.account_number,
+			service_provider_bank_accounts.account_holder_name
+		FROM payouts
+		INNER JOIN service_providers
+			ON service_providers.id = payouts.service_provider_id
+		INNER JOIN service_provider_bank_accounts
+			ON service_provider_bank_accounts.id = payouts.service_provider_bank_account_id
+	` + where + `
+		ORDER BY payouts.id DESC
+		LIMIT $3 OFFSET $4
+	`
+
+	err := r.db.SelectContext(ctx, &res, query, filter.ServiceProviderID, filter.Status, filter.Limit, filter.Offset)
+	if err != nil {
+		return res, total, errors.New(err)
+	}
+
+	query = `SELECT COUNT(payouts.id) FROM payouts ` + where
+
+	if err = r.db.GetContext(ctx, &total, query, filter.ServiceProviderID, filter.Status); err != nil {
+		return res, total, errors.New(err)
+	}
+
+	return res, total, nil
+}
+
+func (r *payoutImpl) FindForUpdateByID(ctx context.Context, _tx dbUtil.Tx, ID uuid.UUID) (types.Payout, error) {
+	res := types.Payout{}
+
+	tx, err := dbUtil.CastSqlxTx(_tx)
+	if err != nil {
+		return res, err
+	}
+
+	query := `
+		SELECT
+			id,
+			service_provider_id,
+			service_provider_bank_account_id,
+			amount,
+			status,
+			admin_user_id,
+			note,
+			created_at,
+			updated_at,
+			paid_at
+		FROM payouts
+		WHERE id = $1
+		FOR UPDATE
+	`
+
+	err = tx.GetContext(ctx, &res, query, ID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return res, types.ErrNoData
+	} else if err != nil {
+		return res, errors.New(err)
+	}
+
+	return res, nil
+}
+
+func (r *payoutImpl) UpdateStatusTx(ctx context.Context, _tx dbUtil.Tx, req types.Payout) error {
+	tx, err := dbUtil.CastSqlxTx(_tx)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE payouts
+		SET status = :status,
+			admin_user_id = :admin_user_id,
+			note = :note,
+			updated_at = :updated_at,
+			paid_at = :paid_at
+		WHERE id = :id
+	`
+
+	if _, err := tx.NamedExecContext(ctx, query, req); err != nil {
+		return errors.New(err)
+	}
+
+	return nil
+}
+
+// SumUnpaidAmountByServiceProviderID sums the payouts that are already deducted from the credit but not transferred yet
+func (r *payoutImpl) SumUnpaidAmountByServiceProviderID(ctx context.Context, serviceProviderID uuid.UUID) (decimal.Decimal, error) {
+	var res decimal.Decimal
+
+	query := `
+		SELECT COALESCE(SUM(amount), 0)
+		FROM payouts
+		WHERE service_provider_id = $1
+			AND status IN ($2, $3)
+	`
+
+	if err := r.db.GetContext(ctx, &res, query, serviceProviderID, types.PayoutStatusPending, types.PayoutStatusApproved); err != nil {
+		return res, errors.New(err)
+	}
+
+	return res, nil
+}
@@ -0,0 +1,168 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"kelarin/internal/types"
+	dbUtil "kelarin/internal/utils/dbutil"
+
+	"github.com/go-errors/errors"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type Refund interface {
+	CreateTx(ctx context.Context, _tx dbUtil.Tx, req types.Refund) error
+	FindAllByFilter(ctx context.Context, filter types.RefundFilter) ([]types.RefundWithOrder, int64, error)
+	FindForUpdateByID(ctx context.Context, _tx dbUtil.Tx, ID uuid.UUID) (types.Refund, error)
+	UpdateStatusTx(ctx context.Context, _tx dbUtil.Tx, req types.Refund) error
+}
+
+type refundImpl struct {
+	db *sqlx.DB
+}
+
+func NewRefund(db *sqlx.DB) Refund {
+	return &refundImpl{db: db}
+}
+
+func (r *refundImpl) CreateTx(ctx context.Context, _tx dbUtil.Tx, req types.Refund) error {
+	tx, err := dbUtil.CastSqlxTx(_tx)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO refunds (
+			id,
+			order_id,
+			payment_id,
+			amount,
+			reason,
+			status,
+			created_at
+		)
+		VALUES (
+			:id,
+			:order_id,
+			:payment_id,
+			:amount,
+			:reason,
+			:status,
+			:created_at
+		)
+	`
+
+	if _, err := tx.NamedExecContext(ctx, query, req); err != nil {
+		return errors.New(err)
+	}
+
+	return nil
+}
+
+func (r *refundImpl) FindAllByFilter(ctx context.Context, filter types.RefundFilter) ([]types.RefundWithOrder, int64, error) {
+	res := []types.RefundWithOrder{}
+	var total int64
+
+	where := `
+		WHERE ($1 = '' OR refunds.status::TEXT = $1)
+	`
+
+	query := `
+		SELECT
+			refunds.id,
+			refunds.order_id,
+			refunds.payment_id,
+			refunds.amount,
+			refunds.reason,
+			refunds.status,
+			refunds.failure_message,
+			refunds.created_at,
+			refunds.updated_at,
+			orders.user_id,
+			users.name AS user_name,
+			orders.service_provider_id,
+			service_providers.name AS service_provider_name,
+			payments.reference AS payment_reference
+		FROM refunds
+		INNER JOIN orders
+			ON orders.id = refunds.order_id
+		INNER JOIN users
+			ON users.id = orders.user_id
+		INNER JOIN service_providers
+			ON service_providers.id = orders.service_provider_id
+		INNER JOIN payments
+			ON payments.id = refunds.payment_id
+	` + where + `
+		ORDER BY refunds.id DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	err := r.db.SelectContext(ctx, &res, query, filter.Status, filter.Limit, filter.Offset)
+	if err != nil {
+		return res, total, errors.New(err)
+	}
+
+	query = `SELECT COUNT(refunds.id) FROM refunds ` + where
+
+	if err = r.db.GetContext(ctx, &total, query, filter.Status); err != nil {
+		return res, total, errors.New(err)
+	}
+
+	return res, total, nil
+}
+
+func (r *refundImpl) FindForUpdateByID(ctx context.Context, _tx dbUtil.Tx, ID uuid.UUID) (types.Refund, error) {
+	res := types.Refund{}
+
+	tx, err := dbUtil.CastSqlxTx(_tx)
+	if err != nil {
+		return res, err
+	}
+
+	query := `
+		SELECT
+			id,
+			order_id,
+			payment_id,
+			amount,
+			reason,
+			status,
+			failure_message,
+			created_at,
+			updated_at
+		FROM refunds
+		WHERE id = $1
+		FOR UPDATE
+	`
+
+	err = tx.GetContext(ctx, &res, query, ID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return res, errors.New(types.ErrNoData)
+	} else if err != nil {
+		return res, errors.New(err)
+	}
+
+	return res, nil
+}
+
+func (r *refundImpl) UpdateStatusTx(ctx context.Context, _tx dbUtil.Tx, req types.Refund) error {
+	tx, err := dbUtil.CastSqlxTx(_tx)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE refunds
+		SET status = :status,
+			failure_message = :failure_message,
+			updated_at = :updated_at
+		WHERE id = :id
+	`
+
+	if _, err := tx.NamedExecContext(ctx, query, req); err != nil {
+		return errors.New(err)
+	}
+
+	return nil
+}
@@ -52,6 +52,7 @@ func (r *serviceImpl) FindByID(ctx context.Context, ID uuid.UUID) (types.Service
 			is_available,
 			received_rating_count,
 			received_rating_average,
+			is_deleted,
 			created_at
 		FROM services
 		WHERE id = $1
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"kelarin/internal/types"
 	"net/http"
 	"strconv"
@@ -10,18 +11,23 @@ import (
 	"github.com/elastic/go-elasticsearch/v8"
 	"github.com/elastic/go-elasticsearch/v8/typedapi/core/search"
 	esTypes "github.com/elastic/go-elasticsearch/v8/typedapi/types"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types/enums/distanceunit"
 	"github.com/elastic/go-elasticsearch/v8/typedapi/types/enums/fieldsortnumerictype"
 	"github.com/elastic/go-elasticsearch/v8/typedapi/types/enums/operator"
 	"github.com/elastic/go-elasticsearch/v8/typedapi/types/enums/sortorder"
 	"github.com/go-errors/errors"
+	"github.com/golang/geo/s2"
+	"github.com/samber/lo"
+	"github.com/volatiletech/null/v9"
 )
 
 type ServiceIndex interface {
+	PutMapping(ctx context.Context) error
 	Create(ctx context.Context, req types.ServiceIndex) error
 	FindByID(ctx context.Context, ID string) (types.ServiceIndex, int64, int64, error)
 	Update(ctx context.Context, req types.ServiceIndex, seqNo int64, primaryTerm int64) error
 	Delete(ctx context.Context, req types.ServiceIndex) error
-	FindAllByFilter(ctx context.Context, req types.ServiceIndexFilter) ([]types.ServiceIndex, int64, []esTypes.FieldValue, error)
+	FindAllByFilter(ctx context.Context, req types.ServiceIndexFilter) ([]types.ServiceIndex, int64, []esTypes.FieldValue, types.ServiceIndexFacets, error)
 }
 
 type serviceIndexImpl struct {
@@ -34,6 +40,105 @@ func NewServiceIndex(esDB *elasticsearch.TypedClient) ServiceIndex {
 	}
 }
 
+// PutMapping creates the versioned services index if it does not exist yet and points the services alias to it,
+// documents of an index created before the alias existed are moved to the versioned index
+func (r *serviceIndexImpl) PutMapping(ctx context.Context) error {
+	mappings := serviceIndexMappings()
+
+	exists, err := r.esDB.Indices.Exists(types.ServiceElasticSearchVersionedIndexName).Do(ctx)
+	if err != nil {
+		return errors.New(err)
+	}
+
+	if !exists {
+		_, err := r.esDB.Indices.Create(types.ServiceElasticSearchVersionedIndexName).Mappings(mappings).Do(ctx)
+		if err != nil {
+			return errors.New(err)
+		}
+	} else {
+		_, err := r.esDB.Indices.PutMapping(types.ServiceElasticSearchVersionedIndexName).Properties(mappings.Properties).Do(ctx)
+		if err != nil {
+			return errors.New(err)
+		}
+	}
+
+	aliasExists, err := r.esDB.Indices.ExistsAlias(types.ServiceElasticSearchIndexName).Do(ctx)
+	if err != nil {
+		return errors.New(err)
+	}
+
+	if aliasExists {
+		return nil
+	}
+
+	legacyExists, err := r.esDB.Indices.Exists(types.ServiceElasticSearchIndexName).Do(ctx)
+	if err != nil {
+		return errors.New(err)
+	}
+
+	if legacyExists {
+		res, err := r.esDB.Reindex().
+			Source(&esTypes.ReindexSource{Index: []string{types.ServiceElasticSearchIndexName}}).
+			Dest(&esTypes.ReindexDestination{Index: types.ServiceElasticSearchVersionedIndexName}).
+			WaitForCompletion(true).
+			Refresh(true).
+			Do(ctx)
+		if err != nil {
+			return errors.New(err)
+		}
+
+		if len(res.Failures) > 0 {
+			return errors.Errorf("failed to reindex %d services: %s", len(res.Failures), lo.FromPtr(res.Failures[0].Cause.Reason))
+		}
+
+		if _, err := r.esDB.Indices.Delete(types.ServiceElasticSearchIndexName).Do(ctx); err != nil {
+			return errors.New(err)
+		}
+	}
+
+	if _, err := r.esDB.Indices.PutAlias(types.ServiceElasticSearchVersionedIndexName, types.ServiceElasticSearchIndexName).Do(ctx); err != nil {
+		return errors.New(err)
+	}
+
+	return nil
+}
+
+func serviceIndexMappings() *esTypes.TypeMapping {
+	feeScalingFactor := esTypes.Float64(100)
+	ignoreAbove := 256
+
+	fee := esTypes.NewScaledFloatNumberProperty()
+	fee.ScalingFactor = &feeScalingFactor
+
+	areaKeyword := esTypes.NewKeywordProperty()
+	areaKeyword.IgnoreAbove = &ignoreAbove
+
+	area := esTypes.NewTextProperty()
+	area.Fields = map[string]esTypes.Property{
+		"keyword": areaKeyword,
+	}
+
+	return &esTypes.TypeMapping{
+		Properties: map[string]esTypes.Property{
+			"id":                      esTypes.NewKeywordProperty(),
+			"service_provider_id":     esTypes.NewKeywordProperty(),
+			"name":                    esTypes.NewTextProperty(),
+			"description":             esTypes.NewTextProperty(),
+			"province":                area,
+			"city":                    area,
+			"delivery_methods":        esTypes.NewKeywordProperty(),
+			"categories":              esTypes.NewKeywordProperty(),
+			"fee_start_at":            fee,
+			"fee_end_at":              fee,
+			"location":                esTypes.NewGeoPointProperty(),
+			"is_available":            esTypes.NewBooleanProperty(),
+			"received_rating_count":   esTypes.NewIntegerNumberProperty(),
+			"received_rating_average": esTypes.NewFloatNumberProperty(),
+			"created_at":              esTypes.NewDateProperty(),
+		},
+	}
+}
+
 func (r *serviceIndexImpl) Create(ctx context.Context, req types.ServiceIndex) error {
 	_, err := r.esDB.Index(types.ServiceElasticSearchIndexName).Request(req).Id(req.ID.String()).Do(ctx)
 	if err != nil {
@@ -105,28 +210,15 @@ func (r *serviceIndexImpl) Delete(ctx context.Context, req types.ServiceIndex) e
 	return nil
 }
 
-func (r *serviceIndexImpl) FindAllByFilter(ctx context.Context, req types.ServiceIndexFilter) ([]types.ServiceIndex, int64, []esTypes.FieldValue, error) {
+func (r *serviceIndexImpl) FindAllByFilter(ctx context.Context, req types.ServiceIndexFilter) ([]types.ServiceIndex, int64, []esTypes.FieldValue, types.ServiceIndexFacets, error) {
 	res := []types.ServiceIndex{}
+	facets := types.ServiceIndexFacets{}
 	var after []esTypes.FieldValue
 
-	dateTimeFormat := "strict_date_optional_time_nanos"
-
 	searchReq := search.Request{
-		Size: &req.Limit,
-		Sort: []esTypes.SortCombinations{
-			esTypes.SortOptions{
-				Score_: &esTypes.ScoreSort{
-					Order: &sortorder.Desc,
-				},
-				SortOptions: map[string]esTypes.FieldSort{
-					"created_at": {
-						NumericType: &fieldsortnumerictype.Date,
-						Format:      &dateTimeFormat,
-						Order:       &sortorder.Desc,
-					},
-				},
-			},
-		},
+		Size:         &req.Limit,
+		Sort:         serviceIndexSort(req),
+		Aggregations: serviceIndexFacetAggregations(),
 	}
 
 	if len(req.After) > 0 {
@@ -177,6 +269,89 @@ func (r *serviceIndexImpl) FindAllByFilter(ctx context.Context, req types.Servic
 		})
 	}
 
+	// a service matches a fee range when its own fee range overlaps it
+	if req.FeeMin.Valid {
+		feeMin := esTypes.Float64(req.FeeMin.Decimal.InexactFloat64())
+		filterQuery = append(filterQuery, esTypes.Query{
+			Range: map[string]esTypes.RangeQuery{
+				"fee_end_at": esTypes.NumberRangeQuery{Gte: &feeMin},
+			},
+		})
+	}
+	if req.FeeMax.Valid {
+		feeMax := esTypes.Float64(req.FeeMax.Decimal.InexactFloat64())
+		filterQuery = append(filterQuery, esTypes.Query{
+			Range: map[string]esTypes.RangeQuery{
+				"fee_start_at": esTypes.NumberRangeQuery{Lte: &feeMax},
+			},
+		})
+	}
+
+	if req.MinRating.Valid {
+		minRating := esTypes.Float64(req.MinRating.Float64)
+		filterQuery = append(filterQuery, esTypes.Query{
+			Range: map[string]esTypes.RangeQuery{
+				"received_rating_average": esTypes.NumberRangeQuery{Gte: &minRating},
+			},
+		})
+	}
+
+	if len(req.DeliveryMethods) > 0 {
+		filterQuery = append(filterQuery, esTypes.Query{
+			Terms: &esTypes.TermsQuery{
+				TermsQuery: map[string]esTypes.TermsQueryField{
+					"delivery_methods": lo.Map(req.DeliveryMethods, func(v types.ServiceDeliveryMethod, _ int) esTypes.FieldValue {
+						return v
+					}),
+				},
+			},
+		})
+	}
+
+	if req.IsAvailable.Valid {
+		filterQuery = append(filterQuery, esTypes.Query{
+			Term: map[string]esTypes.TermQuery{
+				"is_available": {Value: req.IsAvailable.Bool},
+			},
+		})
+	}
+
+	if req.Location != nil {
+		locationQuery := []esTypes.Query{
+			{
+				GeoDistance: &esTypes.GeoDistanceQuery{
+					Distance: fmt.Sprintf("%fkm", req.RadiusKm),
+					GeoDistanceQuery: map[string]esTypes.GeoLocation{
+						"location": esTypes.LatLonGeoLocation{Lat: esTypes.Float64(req.Location.Lat), Lon: esTypes.Float64(req.Location.Lon)},
+					},
+				},
+			},
+		}
+
+		if req.AreaCity != "" {
+			locationQuery = append(locationQuery, esTypes.Query{
+				Bool: &esTypes.BoolQuery{
+					MustNot: []esTypes.Query{{Exists: &esTypes.ExistsQuery{Field: "location"}}},
+					Must: []esTypes.Query{{
+						Match: map[string]esTypes.MatchQuery{
+							"city": {
+								Query:    req.AreaCity,
+								Operator: &operator.And,
+							},
+						},
+					}},
+				},
+			})
+		}
+
+		filterQuery = append(filterQuery, esTypes.Query{
+			Bool: &esTypes.BoolQuery{
+				Should:             locationQuery,
+				MinimumShouldMatch: 1,
+			},
+		})
+	}
+
 	searchReq.Query = &esTypes.Query{
 		Bool: &esTypes.BoolQuery{
 			Must:   mustQuery,
@@ -186,13 +361,19 @@ func (r *serviceIndexImpl) FindAllByFilter(ctx context.Context, req types.Servic
 
 	services, err := r.esDB.Search().Index(types.ServiceElasticSearchIndexName).Request(&searchReq).Do(ctx)
 	if err != nil {
-		return res, 0, nil, errors.New(err)
+		return res, 0, nil, facets, errors.New(err)
 	}
 
 	for _, hit := range services.Hits.Hits {
 		var service types.ServiceIndex
 		if err := json.Unmarshal(hit.Source_, &service); err != nil {
-			return res, 0, nil, errors.New(err)
+			return res, 0, nil, facets, errors.New(err)
+		}
+
+		if req.Location != nil && service.Location != nil {
+			from := s2.LatLngFromDegrees(req.Location.Lat, req.Location.Lon)
+			to := s2.LatLngFromDegrees(service.Location.Lat, service.Location.Lon)
+			service.Distance = null.Float64From(from.Distance(to).Radians() * earthRadiusKm)
 		}
 
 		res = append(res, service)
@@ -202,5 +383,116 @@ func (r *serviceIndexImpl) FindAllByFilter(ctx context.Context, req types.Servic
 		after = services.Hits.Hits[len(services.Hits.Hits)-1].Sort
 	}
 
-	return res, services.Hits.Total.Value, after, nil
+	facets.Categories = serviceIndexFacetBuckets(services.Aggregations["categories"])
+	facets.Cities = serviceIndexFacetBuckets(services.Aggregations["cities"])
+	facets.DeliveryMethods = serviceIndexFacetBuckets(services.Aggregations["delivery_methods"])
+
+	return res, services.Hits.Total.Value, after, facets, nil
+}
+
+const earthRadiusKm = 6371.0088
+
+func serviceIndexSort(req types.ServiceIndexFilter) []esTypes.SortCombinations {
+	dateTimeFormat := "strict_date_optional_time_nanos"
+
+	newest := esTypes.SortOptions{
+		SortOptions: map[string]esTypes.FieldSort{
+			"created_at": {
+				NumericType: &fieldsortnumerictype.Date,
+				Format:      &dateTimeFormat,
+				Order:       &sortorder.Desc,
+			},
+		},
+	}
+
+	fieldSort := func(field string, order sortorder.SortOrder) esTypes.SortOptions {
+		return esTypes.SortOptions{
+			SortOptions: map[string]esTypes.FieldSort{
+				field: {Order: &order},
+			},
+		}
+	}
+
+	switch req.Sort {
+	case types.ServiceIndexSortNewest:
+		return []esTypes.SortCombinations{newest}
+	case types.ServiceIndexSortPriceAsc:
+		return []esTypes.SortCombinations{fieldSort("fee_start_at", sortorder.Asc), newest}
+	case types.ServiceIndexSortPriceDesc:
+		return []esTypes.SortCombinations{fieldSort("fee_start_at", sortorder.Desc), newest}
+	case types.ServiceIndexSortRating:
+		return []esTypes.SortCombinations{
+			fieldSort("received_rating_average", sortorder.Desc),
+			fieldSort("received_rating_count", sortorder.Desc),
+			newest,
+		}
+	case types.ServiceIndexSortDistance:
+		if req.Location != nil {
+			// services matched by their service area have no location and are placed last
+			return []esTypes.SortCombinations{
+				esTypes.SortOptions{
+					GeoDistance_: &esTypes.GeoDistanceSort{
+						GeoDistanceSort: map[string][]esTypes.GeoLocation{
+							"location": {esTypes.LatLonGeoLocation{Lat: esTypes.Float64(req.Location.Lat), Lon: esTypes.Float64(req.Location.Lon)}},
+						},
+						Order: &sortorder.Asc,
+						Unit:  &distanceunit.Kilometers,
+					},
+				},
+				newest,
+			}
+		}
+	}
+
+	return []esTypes.SortCombinations{
+		esTypes.SortOptions{
+			Score_: &esTypes.ScoreSort{
+				Order: &sortorder.Desc,
+			},
+		},
+		newest,
+	}
+}
+
+const serviceIndexFacetSize = 50
+
+func serviceIndexFacetAggregations() map[string]esTypes.Aggregations {
+	size := serviceIndexFacetSize
+	terms := func(field string) esTypes.Aggregations {
+		return esTypes.Aggregations{
+			Terms: &esTypes.TermsAggregation{
+				Field: &field,
+				Size:  &size,
+			},
+		}
+	}
+
+	return map[string]esTypes.Aggregations{
+		"categories":       terms("categories"),
+		"cities":           terms("city.keyword"),
+		"delivery_methods": terms("delivery_methods"),
+	}
+}
+
+func serviceIndexFacetBuckets(aggregate esTypes.Aggregate) []types.ServiceIndexFacetBucket {
+	res := []types.ServiceIndexFacetBucket{}
+
+	terms, ok := aggregate.(*esTypes.StringTermsAggregate)
+	if !ok {
+		return res
+	}
+
+	buckets, ok := terms.Buckets.([]esTypes.StringTermsBucket)
+	if !ok {
+		return res
+	}
+
+	for _, bucket := range buckets {
+		res = append(res, types.ServiceIndexFacetBucket{
+			Value: fmt.Sprint(bucket.Key),
+			Count: bucket.DocCount,
+		})
+	}
+
+	return res
 }
@@ -17,7 +17,7 @@ type ServiceProvider interface {
 	FindByUserID(ctx context.Context, userID uuid.UUID) (types.ServiceProvider, error)
 	FindByIDs(ctx context.Context, IDs []uuid.UUID) ([]types.ServiceProvider, error)
 	FindByID(ctx context.Context, ID uuid.UUID) (types.ServiceProvider, error)
-	UpdateCreditTx(ctx context.Context, req types.ServiceProvider) error
+	UpdateCreditTx(ctx context.Context, _tx dbUtil.Tx, req types.ServiceProvider) error
 	FindByUserIDs(ctx context.Context, IDs []uuid.UUID) ([]types.ServiceProvider, error)
 	FindByServiceID(ctx context.Context, serviceID uuid.UUID) (types.ServiceProvider, error)
 	FindForUpdateByID(ctx context.Context, tx dbUtil.Tx, ID uuid.UUID) (types.ServiceProvider, error)
@@ -169,14 +169,19 @@ func (r *serviceProviderImpl) FindByID(ctx context.Context, ID uuid.UUID) (types
 	return res, nil
 }
 
-func (r *serviceProviderImpl) UpdateCreditTx(ctx context.Context, req types.ServiceProvider) error {
+func (r *serviceProviderImpl) UpdateCreditTx(ctx context.Context, _tx dbUtil.Tx, req types.ServiceProvider) error {
+	tx, err := dbUtil.CastSqlxTx(_tx)
+	if err != nil {
+		return err
+	}
+
 	query := `
 		UPDATE service_providers
 		SET credit = $1
 		WHERE id = $2
 	`
 
-	if _, err := r.db.ExecContext(ctx, query, req.Credit, req.ID); err != nil {
+	if _, err := tx.ExecContext(ctx, query, req.Credit, req.ID); err != nil {
 		return errors.New(err)
 	}
 
@@ -0,0 +1,251 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"kelarin/internal/types"
+	dbUtil "kelarin/internal/utils/dbutil"
+	"time"
+
+	"github.com/go-errors/errors"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type ServiceProviderAvailability interface {
+	FindWorkingHoursByServiceProviderID(ctx context.Context, serviceProviderID uuid.UUID) ([]types.ServiceProviderWorkingHour, error)
+	ReplaceWorkingHoursTx(ctx context.Context, tx dbUtil.Tx, serviceProviderID uuid.UUID, req []types.ServiceProviderWorkingHour) error
+	FindDaysOffByServiceProviderIDAndDateRange(ctx context.Context, serviceProviderID uuid.UUID, startDate, endDate time.Time) ([]types.ServiceProviderDayOff, error)
+	CreateDayOff(ctx context.Context, req types.ServiceProviderDayOff) error
+	DeleteDayOffByIDAndServiceProviderID(ctx context.Context, ID, serviceProviderID uuid.UUID) error
+	FindMaxConcurrentJobsByServiceID(ctx context.Context, serviceID uuid.UUID) (int16, error)
+	UpdateMaxConcurrentJobsByServiceID(ctx context.Context, serviceID uuid.UUID, maxConcurrentJobs int16) error
+	LockServiceTx(ctx context.Context, tx dbUtil.Tx, serviceID uuid.UUID) error
+	FindBookedSessionsByServiceIDAndDateRange(ctx context.Context, serviceID uuid.UUID, startDate, endDate time.Time) ([]types.ServiceBookedSession, error)
+}
+
+type serviceProviderAvailabilityImpl struct {
+	db *sqlx.DB
+}
+
+func NewServiceProviderAvailability(db *sqlx.DB) ServiceProviderAvailability {
+	return &serviceProviderAvailabilityImpl{db: db}
+}
+
+func (r *serviceProviderAvailabilityImpl) FindWorkingHoursByServiceProviderID(ctx context.Context, serviceProviderID uuid.UUID) ([]types.ServiceProviderWorkingHour, error) {
+	res := []types.ServiceProviderWorkingHour{}
+
+	query := `
+		SELECT
+			service_provider_id,
+			day_of_week,
+			start_time,
+			end_time
+		FROM service_provider_working_hours
+		WHERE service_provider_id = $1
+		ORDER BY day_of_week ASC
+	`
+
+	if err := r.db.SelectContext(ctx, &res, query, serviceProviderID); err != nil {
+		return nil, errors.New(err)
+	}
+
+	return res, nil
+}
+
+func (r *serviceProviderAvailabilityImpl) ReplaceWorkingHoursTx(ctx context.Context, _tx dbUtil.Tx, serviceProviderID uuid.UUID, req []types.ServiceProviderWorkingHour) error {
+	tx, err := dbUtil.CastSqlxTx(_tx)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		DELETE FROM service_provider_working_hours
+		WHERE service_provider_id = $1
+	`
+
+	if _, err = tx.ExecContext(ctx, query, serviceProviderID); err != nil {
+		return errors.New(err)
+	}
+
+	if len(req) == 0 {
+		return nil
+	}
+
+	query = `
+		INSERT INTO service_provider_working_hours (
+			service_provider_id,
+			day_of_week,
+			start_time,
+			end_time
+		)
+		VALUES (
+			:service_provider_id,
+			:day_of_week,
+			:start_time,
+			:end_time
+		)
+	`
+
+	if _, err = tx.NamedExecContext(ctx, query, req); err != nil {
+		return errors.New(err)
+	}
+
+	return nil
+}
+
+func (r *serviceProviderAvailabilityImpl) FindDaysOffByServiceProviderIDAndDateRange(ctx context.Context, serviceProviderID uuid.UUID, startDate, endDate time.Time) ([]types.ServiceProviderDayOff, error) {
+	res := []types.ServiceProviderDayOff{}
+
+	query := `
+		SELECT
+			id,
+			service_provider_id,
+			date,
+			reason,
+			created_at
+		FROM service_provider_days_off
+		WHERE
+			service_provider_id = $1
+			AND date BETWEEN $2::DATE AND $3::DATE
+		ORDER BY date ASC
+	`
+
+	if err := r.db.SelectContext(ctx, &res, query, serviceProviderID, startDate, endDate); err != nil {
+		return nil, errors.New(err)
+	}
+
+	return res, nil
+}
+
+func (r *serviceProviderAvailabilityImpl) CreateDayOff(ctx context.Context, req types.ServiceProviderDayOff) error {
+	query := `
+		INSERT INTO service_provider_days_off (
+			id,
+			service_provider_id,
+			date,
+			reason,
+			created_at
+		)
+		VALUES (
+			:id,
+			:service_provider_id,
+			:date,
+			:reason,
+			:created_at
+		)
+		ON CONFLICT (service_provider_id, date) DO UPDATE SET reason = EXCLUDED.reason
+	`
+
+	if _, err := r.db.NamedExecContext(ctx, query, req); err != nil {
+		return errors.New(err)
+	}
+
+	return nil
+}
+
+func (r *serviceProviderAvailabilityImpl) DeleteDayOffByIDAndServiceProviderID(ctx context.Context, ID, serviceProviderID uuid.UUID) error {
+	query := `
+		DELETE FROM service_provider_days_off
+		WHERE
+			id = $1
+			AND service_provider_id = $2
+	`
+
+	res, err := r.db.ExecContext(ctx, query, ID, serviceProviderID)
+	if err != nil {
+		return errors.New(err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return errors.New(err)
+	}
+
+	if affected == 0 {
+		return errors.New(types.ErrNoData)
+	}
+
+	return nil
+}
+
+func (r *serviceProviderAvailabilityImpl) FindMaxConcurrentJobsByServiceID(ctx context.Context, serviceID uuid.UUID) (int16, error) {
+	var res int16
+
+	query := `
+		SELECT max_concurrent_jobs
+		FROM services
+		WHERE id = $1
+	`
+
+	err := r.db.GetContext(ctx, &res, query, serviceID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return res, errors.New(types.ErrNoData)
+	} else if err != nil {
+		return res, errors.New(err)
+	}
+
+	return res, nil
+}
+
+func (r *serviceProviderAvailabilityImpl) UpdateMaxConcurrentJobsByServiceID(ctx context.Context, serviceID uuid.UUID, maxConcurrentJobs int16) error {
+	query := `
+		UPDATE services
+		SET max_concurrent_jobs = $1
+		WHERE id = $2
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, maxConcurrentJobs, serviceID); err != nil {
+		return errors.New(err)
+	}
+
+	return nil
+}
+
+func (r *serviceProviderAvailabilityImpl) LockServiceTx(ctx context.Context, _tx dbUtil.Tx, serviceID uuid.UUID) error {
+	tx, err := dbUtil.CastSqlxTx(_tx)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		SELECT id
+		FROM services
+		WHERE id = $1
+		FOR UPDATE
+	`
+
+	var id uuid.UUID
+	err = tx.GetContext(ctx, &id, query, serviceID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return errors.New(types.ErrNoData)
+	} else if err != nil {
+		return errors.New(err)
+	}
+
+	return nil
+}
+
+func (r *serviceProviderAvailabilityImpl) FindBookedSessionsByServiceIDAndDateRange(ctx context.Context, serviceID uuid.UUID, startDate, endDate time.Time) ([]types.ServiceBookedSession, error) {
+	res := []types.ServiceBookedSession{}
+
+	query := `
+		SELECT
+			order_sessions.service_date,
+			order_sessions.service_time
+		FROM order_sessions
+		JOIN orders ON orders.id = order_sessions.order_id
+		JOIN offers ON offers.id = orders.offer_id
+		WHERE
+			offers.service_id = $1
+			AND order_sessions.service_date BETWEEN $2::DATE AND $3::DATE
+			AND order_sessions.status IN ('pending', 'ongoing')
+			AND orders.status IN ('pending', 'ongoing')
+	`
+
+	if err := r.db.SelectContext(ctx, &res, query, serviceID, startDate, endDate); err != nil {
+		return nil, errors.New(err)
+	}
+
+	return res, nil
+}
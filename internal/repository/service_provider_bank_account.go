@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"kelarin/internal/types"
+
+	"github.com/go-errors/errors"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type ServiceProviderBankAccount interface {
+	Create(ctx context.Context, req types.ServiceProviderBankAccount) error
+	FindAllByServiceProviderID(ctx context.Context, serviceProviderID uuid.UUID) ([]types.ServiceProviderBankAccount, error)
+	FindByIDAndServiceProviderID(ctx context.Context, ID, serviceProviderID uuid.UUID) (types.ServiceProviderBankAccount, error)
+	Delete(ctx context.Context, req types.ServiceProviderBankAccount) error
+}
+
+type serviceProviderBankAccountImpl struct {
+	db *sqlx.DB
+}
+
+func NewServiceProviderBankAccount(db *sqlx.DB) ServiceProviderBankAccount {
+	return &serviceProviderBankAccountImpl{db: db}
+}
+
+func (r *serviceProviderBankAccountImpl) Create(ctx context.Context, req types.ServiceProviderBankAccount) error {
+	query := `
+		INSERT INTO service_provider_bank_accounts (
+			id,
+			service_provider_id,
+			bank_name,
+			account_number,
+			account_holder_name,
+			created_at
+		)
+		VALUES (
+			:id,
+			:service_provider_id,
+			:bank_name,
+			:account_number,
+			:account_holder_name,
+			:created_at
+		)
+	`
+
+	if _, err := r.db.NamedExecContext(ctx, query, req); err != nil {
+		return errors.New(err)
+	}
+
+	return nil
+}
+
+func (r *serviceProviderBankAccountImpl) FindAllByServiceProviderID(ctx context.Context, serviceProviderID uuid.UUID) ([]types.ServiceProviderBankAccount, error) {
+	res := []types.ServiceProviderBankAccount{}
+
+	query := `
+		SELECT
+			id,
+			service_provider_id,
+			bank_name,
+			account_number,
+			account_holder_name,
+			is_deleted,
+			created_at,
+			deleted_at
+		FROM service_provider_bank_accounts
+		WHERE service_provider_id = $1
+			AND is_deleted = FALSE
+		ORDER BY id DESC
+	`
+
+	if err := r.db.SelectContext(ctx, &res, query, serviceProviderID); err != nil {
+		return res, errors.New(err)
+	}
+
+	return res, nil
+}
+
+func (r *serviceProviderBankAccountImpl) FindByIDAndServiceProviderID(ctx context.Context, ID, serviceProviderID uuid.UUID) (types.ServiceProviderBankAccount, error) {
+	res := types.ServiceProviderBankAccount{}
+
+	query := `
+		SELECT
+			id,
+			service_provider_id,
+			bank_name,
+			account_number,
+			account_holder_name,
+			is_deleted,
+			created_at,
+			deleted_at
+		FROM service_provider_bank_accounts
+		WHERE id = $1
+			AND service_provider_id = $2
+			AND is_deleted = FALSE
+	`
+
+	err := r.db.GetContext(ctx, &res, query, ID, serviceProviderID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return res, types.ErrNoData
+	} else if err != nil {
+		return res, errors.New(err)
+	}
+
+	return res, nil
+}
+
+func (r *serviceProviderBankAccountImpl) Delete(ctx context.Context, req types.ServiceProviderBankAccount) error {
+	query := `
+		UPDATE service_provider_bank_accounts
+		SET is_deleted = TRUE,
+			deleted_at = :deleted_at
+		WHERE id = :id
+	`
+
+	if _, err := r.db.NamedExecContext(ctx, query, req); err != nil {
+		return errors.New(err)
+	}
+
+	return nil
+}
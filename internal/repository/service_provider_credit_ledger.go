@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+	"kelarin/internal/types"
+	dbUtil "kelarin/internal/utils/dbutil"
+
+	"github.com/go-errors/errors"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type ServiceProviderCreditLedger interface {
+	CreateTx(ctx context.Context, _tx dbUtil.Tx, req types.ServiceProviderCreditLedger) error
+	FindAllByServiceProviderID(ctx context.Context, serviceProviderID uuid.UUID, limit, offset int) ([]types.ServiceProviderCreditLedger, int64, error)
+}
+
+type serviceProviderCreditLedgerImpl struct {
+	db *sqlx.DB
+}
+
+func NewServiceProviderCreditLedger(db *sqlx.DB) ServiceProviderCreditLedger {
+	return &serviceProviderCreditLedgerImpl{db: db}
+}
+
+func (r *serviceProviderCreditLedgerImpl) CreateTx(ctx context.Context, _tx dbUtil.Tx, req types.ServiceProviderCreditLedger) error {
+	tx, err := dbUtil.CastSqlxTx(_tx)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO service_provider_credit_ledgers (
+			id,
+			service_provider_id,
+			type,
+			amount,
+			balance,
+			order_id,
+			payout_id,
+			note,
+			created_at
+		)
+		VALUES (
+			:id,
+			:service_provider_id,
+			:type,
+			:amount,
+			:balance,
+			:order_id,
+			:payout_id,
+			:note,
+			:created_at
+		)
+	`
+
+	if _, err := tx.NamedExecContext(ctx, query, req); err != nil {
+		return errors.New(err)
+	}
+
+	return nil
+}
+
+func (r *serviceProviderCreditLedgerImpl) FindAllByServiceProviderID(ctx context.Context, serviceProviderID uuid.UUID, limit, offset int) ([]types.ServiceProviderCreditLedger, int64, error) {
+	res := []types.ServiceProviderCreditLedger{}
+	var total int64
+
+	query := `
+		SELECT
+			id,
+			service_provider_id,
+			type,
+			amount,
+			balance,
+			order_id,
+			payout_id,
+			note,
+			created_at
+		FROM service_provider_credit_ledgers
+		WHERE service_provider_id = $1
+		ORDER BY id DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	if err := r.db.SelectContext(ctx, &res, query, serviceProviderID, limit, offset); err != nil {
+		return res, total, errors.New(err)
+	}
+
+	query = `
+		SELECT COUNT(id)
+		FROM service_provider_credit_ledgers
+		WHERE service_provider_id = $1
+	`
+
+	if err := r.db.GetContext(ctx, &total, query, serviceProviderID); err != nil {
+		return res, total, errors.New(err)
+	}
+
+	return res, total, nil
+}
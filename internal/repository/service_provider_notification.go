@@ -8,11 +8,15 @@ import (
 	"github.com/go-errors/errors"
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 )
 
 type ServiceProviderNotification interface {
 	CreateTx(ctx context.Context, tx dbUtil.Tx, req types.ServiceProviderNotification) error
-	FindAllByServiceProviderID(ctx context.Context, serviceProviderID uuid.UUID) ([]types.ServiceProviderNotificationWithUser, error)
+	FindAllByServiceProviderID(ctx context.Context, serviceProviderID uuid.UUID, filter types.NotificationCursorFilter) ([]types.ServiceProviderNotificationWithUser, error)
+	CountUnreadByUserID(ctx context.Context, userID uuid.UUID) (int64, error)
+	UpdateAsReadByIDs(ctx context.Context, serviceProviderID uuid.UUID, IDs uuid.UUIDs) (int64, error)
+	UpdateAllAsReadByServiceProviderID(ctx context.Context, serviceProviderID uuid.UUID) error
 }
 
 type serviceProviderNotificationImpl struct {
@@ -38,6 +42,7 @@ func (r *serviceProviderNotificationImpl) CreateTx(ctx context.Context, _tx dbUt
 			offer_id,
 			offer_negotiation_id,
 			order_id,
+			dispute_id,
 			type,
 			created_at
 		)
@@ -47,6 +52,7 @@ func (r *serviceProviderNotificationImpl) CreateTx(ctx context.Context, _tx dbUt
 			:offer_id,
 			:offer_negotiation_id,
 			:order_id,
+			:dispute_id,
 			:type,
 			:created_at
 		)
@@ -59,7 +65,8 @@ func (r *serviceProviderNotificationImpl) CreateTx(ctx context.Context, _tx dbUt
 	return nil
 }
 
-func (r *serviceProviderNotificationImpl) FindAllByServiceProviderID(ctx context.Context, serviceProviderID uuid.UUID) ([]types.ServiceProviderNotificationWithUser, error) {
+// FindAllByServiceProviderID leaves out the types the owner of the service provider turned off for the in app channel
+func (r *serviceProviderNotificationImpl) FindAllByServiceProviderID(ctx context.Context, serviceProviderID uuid.UUID, filter types.NotificationCursorFilter) ([]types.ServiceProviderNotificationWithUser, error) {
 	res := []types.ServiceProviderNotificationWithUser{}
 
 	query := `
@@ -69,6 +76,7 @@ func (r *serviceProviderNotificationImpl) FindAllByServiceProviderID(ctx context
 			service_provider_notifications.offer_id,
 			service_provider_notifications.offer_negotiation_id,
 			service_provider_notifications.order_id,
+			service_provider_notifications.dispute_id,
 			service_provider_notifications.type,
 			service_provider_notifications.read,
 			service_provider_notifications.created_at,
@@ -85,12 +93,87 @@ func (r *serviceProviderNotificationImpl) FindAllByServiceProviderID(ctx context
 			ON users.id = offers.user_id
 				OR	users.id = orders.user_id
 		WHERE service_provider_notifications.service_provider_id = $1
+			AND ($2::UUID IS NULL OR service_provider_notifications.id < $2)
+			AND service_provider_notifications.type NOT IN (
+				SELECT notification_preferences.type
+				FROM notification_preferences
+				JOIN service_providers
+					ON service_providers.user_id = notification_preferences.user_id
+				WHERE service_providers.id = $1
+					AND notification_preferences.channel = 'in_app'
+					AND notification_preferences.enabled = FALSE
+			)
 		ORDER BY service_provider_notifications.id DESC
+		LIMIT $3
 	`
 
-	if err := r.db.SelectContext(ctx, &res, query, serviceProviderID); err != nil {
+	if err := r.db.SelectContext(ctx, &res, query, serviceProviderID, filter.After, filter.Limit); err != nil {
 		return res, errors.New(err)
 	}
 
 	return res, nil
 }
+
+// CountUnreadByUserID counts the unread notifications of the service provider owned by the user
+func (r *serviceProviderNotificationImpl) CountUnreadByUserID(ctx context.Context, userID uuid.UUID) (int64, error) {
+	var res int64
+
+	query := `
+		SELECT COUNT(service_provider_notifications.id)
+		FROM service_provider_notifications
+		JOIN service_providers
+			ON service_providers.id = service_provider_notifications.service_provider_id
+		WHERE service_providers.user_id = $1
+			AND service_provider_notifications.read = FALSE
+			AND service_provider_notifications.type NOT IN (
+				SELECT type
+				FROM notification_preferences
+				WHERE user_id = $1
+					AND channel = 'in_app'
+					AND enabled = FALSE
+			)
+	`
+
+	if err := r.db.GetContext(ctx, &res, query, userID); err != nil {
+		return res, errors.New(err)
+	}
+
+	return res, nil
+}
+
+// UpdateAsReadByIDs returns how many of the notifications belong to the service provider, a notification of another service provider is left untouched
+func (r *serviceProviderNotificationImpl) UpdateAsReadByIDs(ctx context.Context, serviceProviderID uuid.UUID, IDs uuid.UUIDs) (int64, error) {
+	query := `
+		UPDATE service_provider_notifications
+		SET read = TRUE
+		WHERE service_provider_id = $1
+			AND id = ANY($2)
+	`
+
+	result, err := r.db.ExecContext(ctx, query, serviceProviderID, pq.Array(IDs))
+	if err != nil {
+		return 0, errors.New(err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.New(err)
+	}
+
+	return affected, nil
+}
+
+func (r *serviceProviderNotificationImpl) UpdateAllAsReadByServiceProviderID(ctx context.Context, serviceProviderID uuid.UUID) error {
+	query := `
+		UPDATE service_provider_notifications
+		SET read = TRUE
+		WHERE service_provider_id = $1
+			AND read = FALSE
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, serviceProviderID); err != nil {
+		return errors.New(err)
+	}
+
+	return nil
+}
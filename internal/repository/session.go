@@ -2,18 +2,47 @@ package repository
 
 import (
 	"context"
+	"encoding/json"
 	"kelarin/internal/types"
+	"slices"
 	"time"
 
 	"github.com/go-errors/errors"
 	"github.com/redis/go-redis/v9"
 )
 
+// renewSessionScript claims the old session by deleting it, only one of the renewals that use the same refresh token
+// at the same time gets the new session. The id of the old session is added to its family in the same step
+var renewSessionScript = redis.NewScript(`
+	if redis.call("DEL", KEYS[1]) == 0 then
+		return 0
+	end
+
+	redis.call("SREM", KEYS[3], KEYS[1])
+	redis.call("HDEL", KEYS[4], KEYS[1])
+
+	redis.call("SET", KEYS[2], ARGV[1], "PX", ARGV[2])
+	redis.call("SADD", KEYS[3], KEYS[2])
+	redis.call("PEXPIRE", KEYS[3], ARGV[2])
+	redis.call("HSET", KEYS[4], KEYS[2], ARGV[3])
+	redis.call("PEXPIRE", KEYS[4], ARGV[2])
+
+	redis.call("SADD", KEYS[5], ARGV[4])
+	redis.call("PEXPIRE", KEYS[5], ARGV[2])
+
+	return 1
+`)
+
 type Session interface {
-	Set(ctx context.Context, key string, val string, duration time.Duration) error
+	Set(ctx context.Context, key string, session types.Session, duration time.Duration) error
 	Find(ctx context.Context, key string) (string, error)
-	RenewAndDelete(ctx context.Context, oldKey, newKey, newVal string, duration time.Duration) error
+	FindAllByUserID(ctx context.Context, userID string) ([]types.Session, error)
+	FindMetadata(ctx context.Context, userID, key string) (types.Session, error)
+	RenewAndDelete(ctx context.Context, oldKey, newKey string, session types.Session, duration time.Duration) (bool, error)
+	IsRenewed(ctx context.Context, familyID, sessionID string) (bool, error)
 	Delete(ctx context.Context, key string) error
+	DeleteAllByUserID(ctx context.Context, userID string) error
+	DeleteOthersByUserID(ctx context.Context, userID, currentKey string) error
 }
 
 type sessionImpl struct {
@@ -24,8 +53,26 @@ func NewSession(redis *redis.Client) Session {
 	return &sessionImpl{redis: redis}
 }
 
-func (s *sessionImpl) Set(ctx context.Context, key string, val string, duration time.Duration) error {
-	if err := s.redis.Set(ctx, key, val, duration).Err(); err != nil {
+// Set stores the session and indexes its key and its metadata under the owner user so it can be listed and revoked in bulk
+func (s *sessionImpl) Set(ctx context.Context, key string, session types.Session, duration time.Duration) error {
+	userID := session.UserID.String()
+	userSessionsKey := types.GetUserSessionsKey(userID)
+	metadataKey := types.GetUserSessionMetadataKey(userID)
+
+	metadata, err := json.Marshal(session)
+	if err != nil {
+		return errors.New(err)
+	}
+
+	pipe := s.redis.TxPipeline()
+
+	pipe.Set(ctx, key, userID, duration)
+	pipe.SAdd(ctx, userSessionsKey, key)
+	pipe.Expire(ctx, userSessionsKey, duration)
+	pipe.HSet(ctx, metadataKey, key, metadata)
+	pipe.Expire(ctx, metadataKey, duration)
+
+	if _, err := pipe.Exec(ctx); err != nil {
 		return errors.New(err)
 	}
 
@@ -43,11 +90,136 @@ func (s *sessionImpl) Find(ctx context.Context, key string) (string, error) {
 	return val, nil
 }
 
-func (s *sessionImpl) RenewAndDelete(ctx context.Context, oldKey, newKey, newVal string, duration time.Duration) error {
+// FindAllByUserID returns the sessions that have not expired ordered by the last use, the index entries of the expired sessions are removed
+func (s *sessionImpl) FindAllByUserID(ctx context.Context, userID string) ([]types.Session, error) {
+	res := []types.Session{}
+	userSessionsKey := types.GetUserSessionsKey(userID)
+	metadataKey := types.GetUserSessionMetadataKey(userID)
+
+	keys, err := s.redis.SMembers(ctx, userSessionsKey).Result()
+	if err != nil {
+		return res, errors.New(err)
+	} else if len(keys) == 0 {
+		return res, nil
+	}
+
 	pipe := s.redis.Pipeline()
 
-	pipe.Del(ctx, oldKey)
-	pipe.Set(ctx, newKey, newVal, duration)
+	existsCmds := make([]*redis.IntCmd, len(keys))
+	for i, key := range keys {
+		existsCmds[i] = pipe.Exists(ctx, key)
+	}
+	metadataCmd := pipe.HMGet(ctx, metadataKey, keys...)
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return res, errors.New(err)
+	}
+
+	expiredKeys := []string{}
+	for i, key := range keys {
+		if existsCmds[i].Val() == 0 {
+			expiredKeys = append(expiredKeys, key)
+			continue
+		}
+
+		session := types.Session{}
+		if metadata, ok := metadataCmd.Val()[i].(string); ok {
+			if err := json.Unmarshal([]byte(metadata), &session); err != nil {
+				return res, errors.New(err)
+			}
+		} else {
+			session.ID, err = types.GetSessionIDFromKey(key)
+			if err != nil {
+				return res, errors.New(err)
+			}
+		}
+
+		res = append(res, session)
+	}
+
+	if err := s.deleteIndexes(ctx, userID, expiredKeys); err != nil {
+		return res, err
+	}
+
+	slices.SortFunc(res, func(a, b types.Session) int {
+		return b.LastUsedAt.Compare(a.LastUsedAt)
+	})
+
+	return res, nil
+}
+
+// FindMetadata returns the metadata of the session, a session created before the metadata was stored returns ErrNoData
+func (s *sessionImpl) FindMetadata(ctx context.Context, userID, key string) (types.Session, error) {
+	res := types.Session{}
+
+	metadata, err := s.redis.HGet(ctx, types.GetUserSessionMetadataKey(userID), key).Result()
+	if errors.Is(err, redis.Nil) {
+		return res, errors.New(types.ErrNoData)
+	} else if err != nil {
+		return res, errors.New(err)
+	}
+
+	if err := json.Unmarshal([]byte(metadata), &res); err != nil {
+		return res, errors.New(err)
+	}
+
+	return res, nil
+}
+
+// RenewAndDelete replaces the old session with the new one and moves the metadata to the new key,
+// it reports false when the old session has already been renewed or revoked
+func (s *sessionImpl) RenewAndDelete(ctx context.Context, oldKey, newKey string, session types.Session, duration time.Duration) (bool, error) {
+	userID := session.UserID.String()
+
+	oldSessionID, err := types.GetSessionIDFromKey(oldKey)
+	if err != nil {
+		return false, errors.New(err)
+	}
+
+	metadata, err := json.Marshal(session)
+	if err != nil {
+		return false, errors.New(err)
+	}
+
+	keys := []string{
+		oldKey,
+		newKey,
+		types.GetUserSessionsKey(userID),
+		types.GetUserSessionMetadataKey(userID),
+		types.GetSessionFamilyKey(session.FamilyID.String()),
+	}
+
+	renewed, err := renewSessionScript.Run(ctx, s.redis, keys, userID, duration.Milliseconds(), metadata, oldSessionID.String()).Int()
+	if err != nil {
+		return false, errors.New(err)
+	}
+
+	return renewed == 1, nil
+}
+
+// IsRenewed reports whether the session has been renewed, the family is kept until the last refresh token of the family expires
+func (s *sessionImpl) IsRenewed(ctx context.Context, familyID, sessionID string) (bool, error) {
+	ok, err := s.redis.SIsMember(ctx, types.GetSessionFamilyKey(familyID), sessionID).Result()
+	if err != nil {
+		return false, errors.New(err)
+	}
+
+	return ok, nil
+}
+
+func (s *sessionImpl) Delete(ctx context.Context, key string) error {
+	userID, err := s.redis.Get(ctx, key).Result()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return errors.New(err)
+	}
+
+	pipe := s.redis.TxPipeline()
+
+	pipe.Del(ctx, key)
+	if userID != "" {
+		pipe.SRem(ctx, types.GetUserSessionsKey(userID), key)
+		pipe.HDel(ctx, types.GetUserSessionMetadataKey(userID), key)
+	}
 
 	if _, err := pipe.Exec(ctx); err != nil {
 		return errors.New(err)
@@ -56,8 +228,68 @@ func (s *sessionImpl) RenewAndDelete(ctx context.Context, oldKey, newKey, newVal
 	return nil
 }
 
-func (s *sessionImpl) Delete(ctx context.Context, key string) error {
-	if err := s.redis.Del(ctx, key).Err(); err != nil {
+// DeleteAllByUserID revokes every session of the user, used when an account is suspended or banned
+func (s *sessionImpl) DeleteAllByUserID(ctx context.Context, userID string) error {
+	userSessionsKey := types.GetUserSessionsKey(userID)
+
+	keys, err := s.redis.SMembers(ctx, userSessionsKey).Result()
+	if err != nil {
+		return errors.New(err)
+	}
+
+	pipe := s.redis.TxPipeline()
+
+	if len(keys) > 0 {
+		pipe.Del(ctx, keys...)
+	}
+	pipe.Del(ctx, userSessionsKey, types.GetUserSessionMetadataKey(userID))
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return errors.New(err)
+	}
+
+	return nil
+}
+
+// DeleteOthersByUserID revokes every session of the user except the session of currentKey, used when the password is changed
+func (s *sessionImpl) DeleteOthersByUserID(ctx context.Context, userID, currentKey string) error {
+	keys, err := s.redis.SMembers(ctx, types.GetUserSessionsKey(userID)).Result()
+	if err != nil {
+		return errors.New(err)
+	}
+
+	otherKeys := slices.DeleteFunc(keys, func(key string) bool {
+		return key == currentKey
+	})
+
+	if len(otherKeys) == 0 {
+		return nil
+	}
+
+	if err := s.redis.Del(ctx, otherKeys...).Err(); err != nil {
+		return errors.New(err)
+	}
+
+	return s.deleteIndexes(ctx, userID, otherKeys)
+}
+
+// deleteIndexes removes the session keys from the set and the metadata hash of the user
+func (s *sessionImpl) deleteIndexes(ctx context.Context, userID string, keys []string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	members := make([]any, 0, len(keys))
+	for _, key := range keys {
+		members = append(members, key)
+	}
+
+	pipe := s.redis.TxPipeline()
+
+	pipe.SRem(ctx, types.GetUserSessionsKey(userID), members...)
+	pipe.HDel(ctx, types.GetUserSessionMetadataKey(userID), keys...)
+
+	if _, err := pipe.Exec(ctx); err != nil {
 		return errors.New(err)
 	}
 
@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"kelarin/internal/types"
 	dbUtil "kelarin/internal/utils/dbutil"
+	"time"
 
 	"github.com/go-errors/errors"
 	"github.com/google/uuid"
@@ -18,6 +19,14 @@ type User interface {
 	Create(ctx context.Context, user types.User) error
 	CreateTx(ctx context.Context, _tx dbUtil.Tx, user types.User) error
 	FindByIDs(ctx context.Context, IDs uuid.UUIDs) ([]types.User, error)
+	FindAllByFilter(ctx context.Context, filter types.UserAdminFilter) ([]types.User, int64, error)
+	FindForUpdateByID(ctx context.Context, _tx dbUtil.Tx, ID uuid.UUID) (types.User, error)
+	UpdateSuspensionTx(ctx context.Context, _tx dbUtil.Tx, user types.User) error
+	UpdateBanTx(ctx context.Context, _tx dbUtil.Tx, user types.User) error
+	UpdateLanguage(ctx context.Context, ID uuid.UUID, language types.Language) error
+	UpdatePassword(ctx context.Context, ID uuid.UUID, password string) error
+	MarkEmailAsVerified(ctx context.Context, ID uuid.UUID, verifiedAt time.Time) error
+	UnsuspendExpiredTx(ctx context.Context, _tx dbUtil.Tx, now time.Time, limit int) ([]types.User, error)
 }
 
 type userImpl struct {
@@ -37,7 +46,9 @@ func (r *userImpl) FindByID(ctx context.Context, ID uuid.UUID) (types.User, erro
 			name,
 			email,
 			password,
+			language,
 			role,
+			email_verified_at,
 			is_suspended,
 			suspended_count,
 			suspended_from,
@@ -69,6 +80,7 @@ func (r *userImpl) FindByEmail(ctx context.Context, email string) (types.User, e
 			email,
 			password,
 			role,
+			email_verified_at,
 			is_suspended,
 			suspended_count,
 			suspended_from,
@@ -77,7 +89,7 @@ func (r *userImpl) FindByEmail(ctx context.Context, email string) (types.User, e
 			banned_at,
 			created_at
 		FROM users
-		WHERE email = $1
+		WHERE LOWER(email) = LOWER($1)
 	`
 
 	err := r.db.GetContext(ctx, &res, statement, email)
@@ -98,7 +110,8 @@ func (r *userImpl) Create(ctx context.Context, user types.User) error {
 			name, 
 			email, 
 			password,
-			auth_provider
+			auth_provider,
+			email_verified_at
 		)
 		VALUES (
 			:id,
@@ -106,7 +119,8 @@ func (r *userImpl) Create(ctx context.Context, user types.User) error {
 			:name,
 			:email,
 			:password,
-			:auth_provider
+			:auth_provider,
+			:email_verified_at
 		)
 	`
 
@@ -131,7 +145,8 @@ func (r *userImpl) CreateTx(ctx context.Context, _tx dbUtil.Tx, user types.User)
 			name, 
 			email, 
 			password,
-			auth_provider
+			auth_provider,
+			email_verified_at
 		)
 		VALUES (
 			:id,
@@ -139,11 +154,15 @@ func (r *userImpl) CreateTx(ctx context.Context, _tx dbUtil.Tx, user types.User)
 			:name,
 			:email,
 			:password,
-			:auth_provider
+			:auth_provider,
+			:email_verified_at
 		)
 	`
 
-	if _, err := tx.NamedExecContext(ctx, statement, user); err != nil {
+	var pqErr *pq.Error
+	if _, err := tx.NamedExecContext(ctx, statement, user); errors.As(err, &pqErr) && pqErr.Code.Name() == "unique_violation" {
+		return errors.New(types.ErrDuplicateData)
+	} else if err != nil {
 		return errors.New(err)
 	}
 
@@ -160,6 +179,7 @@ func (r *userImpl) FindByIDs(ctx context.Context, IDs uuid.UUIDs) ([]types.User,
 			email,
 			password,
 			role,
+			email_verified_at,
 			is_suspended,
 			suspended_count,
 			suspended_from,
@@ -177,3 +197,219 @@ func (r *userImpl) FindByIDs(ctx context.Context, IDs uuid.UUIDs) ([]types.User,
 
 	return res, nil
 }
+
+func (r *userImpl) FindAllByFilter(ctx context.Context, filter types.UserAdminFilter) ([]types.User, int64, error) {
+	res := []types.User{}
+	var total int64
+
+	where := `
+		WHERE ($1 = '' OR name ILIKE '%' || $1 || '%' OR email ILIKE '%' || $1 || '%')
+			AND ($2 = 0 OR role = $2)
+			AND (
+				$3 = ''
+				OR ($3 = 'active' AND is_suspended = false AND is_banned = false)
+				OR ($3 = 'suspended' AND is_suspended = true AND is_banned = false)
+				OR ($3 = 'banned' AND is_banned = true)
+			)
+	`
+
+	statement := `
+		SELECT 
+			id,
+			name,
+			email,
+			password,
+			role,
+			email_verified_at,
+			is_suspended,
+			suspended_count,
+			suspended_from,
+			suspended_to,
+			is_banned,
+			banned_at,
+			created_at
+		FROM users
+	` + where + `
+		ORDER BY created_at DESC, id DESC
+		LIMIT $4 OFFSET $5
+	`
+
+	err := r.db.SelectContext(ctx, &res, statement, filter.Keyword, filter.Role, filter.Status, filter.Limit, filter.Offset)
+	if err != nil {
+		return res, total, errors.New(err)
+	}
+
+	statement = `SELECT COUNT(id) FROM users ` + where
+
+	if err = r.db.GetContext(ctx, &total, statement, filter.Keyword, filter.Role, filter.Status); err != nil {
+		return res, total, errors.New(err)
+	}
+
+	return res, total, nil
+}
+
+func (r *userImpl) FindForUpdateByID(ctx context.Context, _tx dbUtil.Tx, ID uuid.UUID) (types.User, error) {
+	res := types.User{}
+
+	tx, err := dbUtil.CastSqlxTx(_tx)
+	if err != nil {
+		return res, err
+	}
+
+	statement := ` 
+		SELECT 
+			id,
+			name,
+			email,
+			password,
+			role,
+			email_verified_at,
+			is_suspended,
+			suspended_count,
+			suspended_from,
+			suspended_to,
+			is_banned,
+			banned_at,
+			created_at
+		FROM users
+		WHERE id = $1
+		FOR UPDATE
+	`
+
+	err = tx.GetContext(ctx, &res, statement, ID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return res, types.ErrNoData
+	} else if err != nil {
+		return res, errors.New(err)
+	}
+
+	return res, nil
+}
+
+// UpdateSuspensionTx leaves suspended_count to increment_suspended_count_trigger, it counts every update that sets is_suspended to true
+func (r *userImpl) UpdateSuspensionTx(ctx context.Context, _tx dbUtil.Tx, user types.User) error {
+	tx, err := dbUtil.CastSqlxTx(_tx)
+	if err != nil {
+		return err
+	}
+
+	statement := `
+		UPDATE users
+		SET is_suspended = :is_suspended,
+			suspended_from = :suspended_from,
+			suspended_to = :suspended_to
+		WHERE id = :id
+	`
+
+	if _, err := tx.NamedExecContext(ctx, statement, user); err != nil {
+		return errors.New(err)
+	}
+
+	return nil
+}
+
+func (r *userImpl) UpdateBanTx(ctx context.Context, _tx dbUtil.Tx, user types.User) error {
+	tx, err := dbUtil.CastSqlxTx(_tx)
+	if err != nil {
+		return err
+	}
+
+	statement := `
+		UPDATE users
+		SET is_banned = :is_banned,
+			banned_at = :banned_at
+		WHERE id = :id
+	`
+
+	if _, err := tx.NamedExecContext(ctx, statement, user); err != nil {
+		return errors.New(err)
+	}
+
+	return nil
+}
+
+func (r *userImpl) UpdateLanguage(ctx context.Context, ID uuid.UUID, language types.Language) error {
+	statement := `
+		UPDATE users
+		SET language = $2
+		WHERE id = $1
+	`
+
+	if _, err := r.db.ExecContext(ctx, statement, ID, language); err != nil {
+		return errors.New(err)
+	}
+
+	return nil
+}
+
+func (r *userImpl) UpdatePassword(ctx context.Context, ID uuid.UUID, password string) error {
+	statement := `
+		UPDATE users
+		SET password = $2
+		WHERE id = $1
+	`
+
+	if _, err := r.db.ExecContext(ctx, statement, ID, password); err != nil {
+		return errors.New(err)
+	}
+
+	return nil
+}
+
+// MarkEmailAsVerified keeps the first verification time when the email is verified again
+func (r *userImpl) MarkEmailAsVerified(ctx context.Context, ID uuid.UUID, verifiedAt time.Time) error {
+	statement := `
+		UPDATE users
+		SET email_verified_at = $2
+		WHERE id = $1
+			AND email_verified_at IS NULL
+	`
+
+	if _, err := r.db.ExecContext(ctx, statement, ID, verifiedAt); err != nil {
+		return errors.New(err)
+	}
+
+	return nil
+}
+
+// UnsuspendExpiredTx lifts at most limit suspensions whose period has ended and returns the lifted users with their previous suspension period
+func (r *userImpl) UnsuspendExpiredTx(ctx context.Context, _tx dbUtil.Tx, now time.Time, limit int) ([]types.User, error) {
+	res := []types.User{}
+
+	tx, err := dbUtil.CastSqlxTx(_tx)
+	if err != nil {
+		return res, err
+	}
+
+	statement := `
+		WITH expired AS (
+			SELECT
+				id,
+				suspended_from,
+				suspended_to
+			FROM users
+			WHERE is_suspended = TRUE
+				AND suspended_to <= $1
+			ORDER BY suspended_to
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		)
+		UPDATE users
+		SET is_suspended = FALSE,
+			suspended_from = NULL,
+			suspended_to = NULL
+		FROM expired
+		WHERE users.id = expired.id
+		RETURNING
+			users.id,
+			users.is_banned,
+			expired.suspended_from,
+			expired.suspended_to
+	`
+
+	if err := tx.SelectContext(ctx, &res, statement, now, limit); err != nil {
+		return res, errors.New(err)
+	}
+
+	return res, nil
+}
@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"kelarin/internal/types"
+	"time"
+
+	"github.com/go-errors/errors"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// UserBlocklist holds suspended and banned users so the auth middleware can reject tokens issued before the block without hitting postgres
+type UserBlocklist interface {
+	Set(ctx context.Context, userID uuid.UUID, entry types.UserBlocklistEntry, expiration time.Duration) error
+	Find(ctx context.Context, userID uuid.UUID) (types.UserBlocklistEntry, error)
+	Delete(ctx context.Context, userIDs ...uuid.UUID) error
+}
+
+type userBlocklistImpl struct {
+	redisDB *redis.Client
+}
+
+func NewUserBlocklist(redisDB *redis.Client) UserBlocklist {
+	return &userBlocklistImpl{redisDB: redisDB}
+}
+
+func (r *userBlocklistImpl) Set(ctx context.Context, userID uuid.UUID, entry types.UserBlocklistEntry, expiration time.Duration) error {
+	val, err := json.Marshal(entry)
+	if err != nil {
+		return errors.New(err)
+	}
+
+	if err = r.redisDB.Set(ctx, types.GetUserBlocklistKey(userID.String()), val, expiration).Err(); err != nil {
+		return errors.New(err)
+	}
+
+	return nil
+}
+
+func (r *userBlocklistImpl) Find(ctx context.Context, userID uuid.UUID) (types.UserBlocklistEntry, error) {
+	res := types.UserBlocklistEntry{}
+
+	val, err := r.redisDB.Get(ctx, types.GetUserBlocklistKey(userID.String())).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return res, types.ErrNoData
+	} else if err != nil {
+		return res, errors.New(err)
+	}
+
+	if err = json.Unmarshal(val, &res); err != nil {
+		return res, errors.New(err)
+	}
+
+	return res, nil
+}
+
+func (r *userBlocklistImpl) Delete(ctx context.Context, userIDs ...uuid.UUID) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+
+	keys := make([]string, 0, len(userIDs))
+	for _, id := range userIDs {
+		keys = append(keys, types.GetUserBlocklistKey(id.String()))
+	}
+
+	if err := r.redisDB.Del(ctx, keys...).Err(); err != nil {
+		return errors.New(err)
+	}
+
+	return nil
+}
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"kelarin/internal/types"
+	dbUtil "kelarin/internal/utils/dbutil"
+
+	"github.com/go-errors/errors"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type UserModerationLog interface {
+	CreateTx(ctx context.Context, _tx dbUtil.Tx, req types.UserModerationLog) error
+	FindByUserIDWithAdmin(ctx context.Context, userID uuid.UUID) ([]types.UserModerationLogWithAdmin, error)
+}
+
+type userModerationLogImpl struct {
+	db *sqlx.DB
+}
+
+func NewUserModerationLog(db *sqlx.DB) UserModerationLog {
+	return &userModerationLogImpl{db: db}
+}
+
+func (r *userModerationLogImpl) CreateTx(ctx context.Context, _tx dbUtil.Tx, req types.UserModerationLog) error {
+	tx, err := dbUtil.CastSqlxTx(_tx)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO user_moderation_logs (
+			id,
+			user_id,
+			admin_user_id,
+			action,
+			reason,
+			suspended_from,
+			suspended_to,
+			created_at
+		)
+		VALUES (
+			:id,
+			:user_id,
+			:admin_user_id,
+			:action,
+			:reason,
+			:suspended_from,
+			:suspended_to,
+			:created_at
+		)
+	`
+
+	if _, err := tx.NamedExecContext(ctx, query, req); err != nil {
+		return errors.New(err)
+	}
+
+	return nil
+}
+
+func (r *userModerationLogImpl) FindByUserIDWithAdmin(ctx context.Context, userID uuid.UUID) ([]types.UserModerationLogWithAdmin, error) {
+	res := []types.UserModerationLogWithAdmin{}
+
+	query := `
+		SELECT
+			user_moderation_logs.id,
+			user_moderation_logs.user_id,
+			user_moderation_logs.admin_user_id,
+			user_moderation_logs.action,
+			user_moderation_logs.reason,
+			user_moderation_logs.suspended_from,
+			user_moderation_logs.suspended_to,
+			user_moderation_logs.created_at,
+			users.name AS admin_name
+		FROM user_moderation_logs
+		LEFT JOIN users
+			ON users.id = user_moderation_logs.admin_user_id
+		WHERE user_moderation_logs.user_id = $1
+		ORDER BY user_moderation_logs.created_at DESC
+	`
+
+	if err := r.db.SelectContext(ctx, &res, query, userID); err != nil {
+		return res, errors.New(err)
+	}
+
+	return res, nil
+}
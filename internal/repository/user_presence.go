@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"context"
+	"kelarin/internal/types"
+	"time"
+
+	"github.com/go-errors/errors"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// UserPresence holds the last seen time of the users, whether a user is online is known by the websocket hub
+type UserPresence interface {
+	SetLastSeen(ctx context.Context, userID uuid.UUID, lastSeenAt time.Time) error
+	FindLastSeen(ctx context.Context, userID uuid.UUID) (time.Time, error)
+}
+
+type userPresenceImpl struct {
+	redisDB *redis.Client
+}
+
+func NewUserPresence(redisDB *redis.Client) UserPresence {
+	return &userPresenceImpl{redisDB: redisDB}
+}
+
+func (r *userPresenceImpl) SetLastSeen(ctx context.Context, userID uuid.UUID, lastSeenAt time.Time) error {
+	if err := r.redisDB.Set(ctx, types.GetUserLastSeenKey(userID), lastSeenAt.Unix(), 0).Err(); err != nil {
+		return errors.New(err)
+	}
+
+	return nil
+}
+
+func (r *userPresenceImpl) FindLastSeen(ctx context.Context, userID uuid.UUID) (time.Time, error) {
+	res, err := r.redisDB.Get(ctx, types.GetUserLastSeenKey(userID)).Int64()
+	if errors.Is(err, redis.Nil) {
+		return time.Time{}, errors.New(types.ErrNoData)
+	} else if err != nil {
+		return time.Time{}, errors.New(err)
+	}
+
+	return time.Unix(res, 0), nil
+}
@@ -0,0 +1,231 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"kelarin/internal/types"
+	dbUtil "kelarin/internal/utils/dbutil"
+	"time"
+
+	"github.com/go-errors/errors"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type UserTOTP interface {
+	FindByUserID(ctx context.Context, userID uuid.UUID) (types.UserTOTP, error)
+	Upsert(ctx context.Context, req types.UserTOTP) (bool, error)
+	EnableTx(ctx context.Context, _tx dbUtil.Tx, userID uuid.UUID, enabledAt time.Time, step int64) (bool, error)
+	UpdateLastUsedStep(ctx context.Context, userID uuid.UUID, step int64) (bool, error)
+	DeleteTx(ctx context.Context, _tx dbUtil.Tx, userID uuid.UUID) error
+	CreateRecoveryCodesTx(ctx context.Context, _tx dbUtil.Tx, req []types.UserTOTPRecoveryCode) error
+	DeleteRecoveryCodesTx(ctx context.Context, _tx dbUtil.Tx, userID uuid.UUID) error
+	UseRecoveryCode(ctx context.Context, userID uuid.UUID, codeHash string, usedAt time.Time) (bool, error)
+}
+
+type userTOTPImpl struct {
+	db *sqlx.DB
+}
+
+func NewUserTOTP(db *sqlx.DB) UserTOTP {
+	return &userTOTPImpl{db: db}
+}
+
+func (r *userTOTPImpl) FindByUserID(ctx context.Context, userID uuid.UUID) (types.UserTOTP, error) {
+	res := types.UserTOTP{}
+
+	query := `
+		SELECT
+			user_id,
+			secret,
+			enabled_at,
+			last_used_step,
+			created_at
+		FROM user_totps
+		WHERE user_id = $1
+	`
+
+	err := r.db.GetContext(ctx, &res, query, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return res, errors.New(types.ErrNoData)
+	} else if err != nil {
+		return res, errors.New(err)
+	}
+
+	return res, nil
+}
+
+// Upsert replaces the secret of an enrollment that has not been confirmed, it reports false when two-factor authentication is already enabled
+func (r *userTOTPImpl) Upsert(ctx context.Context, req types.UserTOTP) (bool, error) {
+	query := `
+		INSERT INTO user_totps (
+			user_id,
+			secret,
+			created_at
+		)
+		VALUES (
+			:user_id,
+			:secret,
+			:created_at
+		)
+		ON CONFLICT (user_id) DO UPDATE SET
+			secret = EXCLUDED.secret,
+			last_used_step = 0,
+			created_at = EXCLUDED.created_at
+		WHERE user_totps.enabled_at IS NULL
+	`
+
+	result, err := r.db.NamedExecContext(ctx, query, req)
+	if err != nil {
+		return false, errors.New(err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, errors.New(err)
+	}
+
+	return affected == 1, nil
+}
+
+// EnableTx confirms the enrollment with the step of the confirmation code, it reports false when the enrollment has been confirmed before
+func (r *userTOTPImpl) EnableTx(ctx context.Context, _tx dbUtil.Tx, userID uuid.UUID, enabledAt time.Time, step int64) (bool, error) {
+	tx, err := dbUtil.CastSqlxTx(_tx)
+	if err != nil {
+		return false, err
+	}
+
+	query := `
+		UPDATE user_totps
+		SET enabled_at = $2,
+			last_used_step = $3
+		WHERE user_id = $1
+			AND enabled_at IS NULL
+	`
+
+	result, err := tx.ExecContext(ctx, query, userID, enabledAt, step)
+	if err != nil {
+		return false, errors.New(err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, errors.New(err)
+	}
+
+	return affected == 1, nil
+}
+
+// UpdateLastUsedStep reports false when a code of the same step or a newer one has been accepted, the code is a replay
+func (r *userTOTPImpl) UpdateLastUsedStep(ctx context.Context, userID uuid.UUID, step int64) (bool, error) {
+	query := `
+		UPDATE user_totps
+		SET last_used_step = $2
+		WHERE user_id = $1
+			AND enabled_at IS NOT NULL
+			AND last_used_step < $2
+	`
+
+	result, err := r.db.ExecContext(ctx, query, userID, step)
+	if err != nil {
+		return false, errors.New(err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, errors.New(err)
+	}
+
+	return affected == 1, nil
+}
+
+func (r *userTOTPImpl) DeleteTx(ctx context.Context, _tx dbUtil.Tx, userID uuid.UUID) error {
+	tx, err := dbUtil.CastSqlxTx(_tx)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		DELETE FROM user_totps
+		WHERE user_id = $1
+	`
+
+	if _, err = tx.ExecContext(ctx, query, userID); err != nil {
+		return errors.New(err)
+	}
+
+	return nil
+}
+
+func (r *userTOTPImpl) CreateRecoveryCodesTx(ctx context.Context, _tx dbUtil.Tx, req []types.UserTOTPRecoveryCode) error {
+	tx, err := dbUtil.CastSqlxTx(_tx)
+	if err != nil {
+		return err
+	}
+
+	if len(req) == 0 {
+		return nil
+	}
+
+	query := `
+		INSERT INTO user_totp_recovery_codes (
+			id,
+			user_id,
+			code_hash,
+			created_at
+		)
+		VALUES (
+			:id,
+			:user_id,
+			:code_hash,
+			:created_at
+		)
+	`
+
+	if _, err = tx.NamedExecContext(ctx, query, req); err != nil {
+		return errors.New(err)
+	}
+
+	return nil
+}
+
+func (r *userTOTPImpl) DeleteRecoveryCodesTx(ctx context.Context, _tx dbUtil.Tx, userID uuid.UUID) error {
+	tx, err := dbUtil.CastSqlxTx(_tx)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		DELETE FROM user_totp_recovery_codes
+		WHERE user_id = $1
+	`
+
+	if _, err = tx.ExecContext(ctx, query, userID); err != nil {
+		return errors.New(err)
+	}
+
+	return nil
+}
+
+// UseRecoveryCode reports false when the code does not exist or has been used, a recovery code can only be used once
+func (r *userTOTPImpl) UseRecoveryCode(ctx context.Context, userID uuid.UUID, codeHash string, usedAt time.Time) (bool, error) {
+	query := `
+		UPDATE user_totp_recovery_codes
+		SET used_at = $3
+		WHERE user_id = $1
+			AND code_hash = $2
+			AND used_at IS NULL
+	`
+
+	result, err := r.db.ExecContext(ctx, query, userID, codeHash, usedAt)
+	if err != nil {
+		return false, errors.New(err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, errors.New(err)
+	}
+
+	return affected == 1, nil
+}
@@ -0,0 +1,33 @@
+package routes
+
+import (
+	"kelarin/internal/handler"
+	"kelarin/internal/middleware"
+	"kelarin/internal/types"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AdminRole struct {
+	g                *gin.Engine
+	adminRoleHandler handler.AdminRole
+}
+
+func NewAdminRole(g *gin.Engine, adminRoleHandler handler.AdminRole) *AdminRole {
+	return &AdminRole{
+		g:                g,
+		adminRoleHandler: adminRoleHandler,
+	}
+}
+
+func (r *AdminRole) Register(authMw middleware.Auth) {
+	rolesManage := authMw.Permission(types.AdminPermissionRolesManage)
+
+	r.g.GET("/admin/v1/permissions", rolesManage, r.adminRoleHandler.GetAllPermissions)
+	r.g.GET("/admin/v1/roles", rolesManage, r.adminRoleHandler.GetAll)
+	r.g.POST("/admin/v1/roles", rolesManage, r.adminRoleHandler.Create)
+	r.g.PUT("/admin/v1/roles/:id", rolesManage, r.adminRoleHandler.Update)
+	r.g.DELETE("/admin/v1/roles/:id", rolesManage, r.adminRoleHandler.Delete)
+	r.g.GET("/admin/v1/users/:id/roles", rolesManage, r.adminRoleHandler.GetByUserID)
+	r.g.PUT("/admin/v1/users/:id/roles", rolesManage, r.adminRoleHandler.AssignToUser)
+}
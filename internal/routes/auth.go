@@ -25,4 +25,20 @@ func (r *Auth) Register(authMw middleware.Auth) {
 	r.g.POST("/provider/v1/auth/_google_login", r.authHandler.ProviderGoogleLogin)
 	r.g.POST("/v1/auth/_renew_session", r.authHandler.RenewSession)
 	r.g.DELETE("/v1/auth/revoke-session", authMw.Authenticated, r.authHandler.RevokeSession)
+	r.g.POST("/consumer/v1/auth/_register", r.authHandler.ConsumerRegister)
+	r.g.POST("/provider/v1/auth/_register", r.authHandler.ProviderRegister)
+	r.g.POST("/v1/auth/_verify_email", r.authHandler.VerifyEmail)
+	r.g.POST("/v1/auth/_resend_verification_email", r.authHandler.ResendVerificationEmail)
+	r.g.POST("/v1/auth/_forgot_password", r.authHandler.ForgotPassword)
+	r.g.POST("/v1/auth/_reset_password", r.authHandler.ResetPassword)
+	r.g.PUT("/v1/auth/_change_password", authMw.Session, r.authHandler.ChangePassword)
+	r.g.GET("/v1/auth/sessions", authMw.Session, r.authHandler.GetSessions)
+	r.g.DELETE("/v1/auth/sessions/:id", authMw.Session, r.authHandler.RevokeSessionByID)
+	r.g.POST("/v1/auth/sessions/_revoke_others", authMw.Session, r.authHandler.RevokeOtherSessions)
+	r.g.POST("/v1/auth/_verify_two_factor", r.authHandler.VerifyTwoFactor)
+	r.g.POST("/v1/auth/_enroll_two_factor", r.authHandler.EnrollTwoFactor)
+	r.g.POST("/v1/auth/two-factor/_setup", authMw.Session, r.authHandler.SetupTwoFactor)
+	r.g.POST("/v1/auth/two-factor/_enable", authMw.Session, r.authHandler.EnableTwoFactor)
+	r.g.POST("/v1/auth/two-factor/_disable", authMw.Session, r.authHandler.DisableTwoFactor)
+	r.g.POST("/v1/auth/two-factor/_regenerate_recovery_codes", authMw.Session, r.authHandler.RegenerateTwoFactorRecoveryCodes)
 }
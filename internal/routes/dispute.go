@@ -0,0 +1,35 @@
+package routes
+
+import (
+	"kelarin/internal/handler"
+	"kelarin/internal/middleware"
+	"kelarin/internal/types"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Dispute struct {
+	g              *gin.Engine
+	disputeHandler handler.Dispute
+}
+
+func NewDispute(g *gin.Engine, disputeHandler handler.Dispute) *Dispute {
+	return &Dispute{
+		g:              g,
+		disputeHandler: disputeHandler,
+	}
+}
+
+func (r *Dispute) Register(authMw middleware.Auth) {
+	r.g.POST("/consumer/v1/disputes", authMw.Consumer, r.disputeHandler.ConsumerCreate)
+	r.g.GET("/consumer/v1/disputes", authMw.Consumer, r.disputeHandler.ConsumerGetAll)
+	r.g.GET("/consumer/v1/disputes/:id", authMw.Consumer, r.disputeHandler.ConsumerGetByID)
+
+	r.g.GET("/provider/v1/disputes", authMw.ServiceProvider, r.disputeHandler.ProviderGetAll)
+	r.g.GET("/provider/v1/disputes/:id", authMw.ServiceProvider, r.disputeHandler.ProviderGetByID)
+	r.g.POST("/provider/v1/disputes/:id/_respond", authMw.ServiceProvider, r.disputeHandler.ProviderRespond)
+
+	r.g.GET("/admin/v1/disputes", authMw.Permission(types.AdminPermissionDisputesRead), r.disputeHandler.AdminGetAll)
+	r.g.GET("/admin/v1/disputes/:id", authMw.Permission(types.AdminPermissionDisputesRead), r.disputeHandler.AdminGetByID)
+	r.g.POST("/admin/v1/disputes/:id/_resolve", authMw.Permission(types.AdminPermissionDisputesResolve), r.disputeHandler.AdminResolve)
+}
@@ -25,4 +25,5 @@ func NewFile(g *gin.Engine, fileHandler handler.File) File {
 
 func (u *fileImpl) Register(m middleware.Auth) {
 	u.g.POST("/common/v1/files/_images", m.Authenticated, u.fileHandler.UploadImages)
+	u.g.POST("/common/v1/files/_chat_attachments", m.NonAdmin, u.fileHandler.UploadChatAttachment)
 }
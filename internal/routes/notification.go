@@ -3,6 +3,7 @@ package routes
 import (
 	"kelarin/internal/handler"
 	"kelarin/internal/middleware"
+	"kelarin/internal/types"
 
 	"github.com/gin-gonic/gin"
 )
@@ -21,8 +22,22 @@ func NewNotification(g *gin.Engine, notificationHandler handler.Notification) *N
 
 func (r *Notification) Register(authMw middleware.Auth) {
 	r.g.POST("/v1/notifications/_token", authMw.Authenticated, r.notificationHandler.SaveToken)
+	r.g.GET("/v1/notifications/preferences", authMw.NonAdmin, r.notificationHandler.GetPreferences)
+	r.g.PUT("/v1/notifications/preferences", authMw.NonAdmin, r.notificationHandler.UpdatePreferences)
+	r.g.PUT("/v1/notifications/quiet-hours", authMw.NonAdmin, r.notificationHandler.UpdateQuietHours)
+	r.g.PUT("/v1/notifications/language", authMw.NonAdmin, r.notificationHandler.UpdateLanguage)
 
 	r.g.GET("/consumer/v1/notifications", authMw.Consumer, r.notificationHandler.ConsumerGetAll)
+	r.g.GET("/consumer/v1/notifications/_unread_count", authMw.Consumer, r.notificationHandler.ConsumerGetUnreadCount)
+	r.g.PUT("/consumer/v1/notifications/_mark_as_read", authMw.Consumer, r.notificationHandler.ConsumerMarkManyAsRead)
+	r.g.PUT("/consumer/v1/notifications/_mark_all_as_read", authMw.Consumer, r.notificationHandler.ConsumerMarkAllAsRead)
+	r.g.PUT("/consumer/v1/notifications/:id/_mark_as_read", authMw.Consumer, r.notificationHandler.ConsumerMarkAsRead)
 
 	r.g.GET("/provider/v1/notifications", authMw.ServiceProvider, r.notificationHandler.ProviderGetAll)
+	r.g.GET("/provider/v1/notifications/_unread_count", authMw.ServiceProvider, r.notificationHandler.ProviderGetUnreadCount)
+	r.g.PUT("/provider/v1/notifications/_mark_as_read", authMw.ServiceProvider, r.notificationHandler.ProviderMarkManyAsRead)
+	r.g.PUT("/provider/v1/notifications/_mark_all_as_read", authMw.ServiceProvider, r.notificationHandler.ProviderMarkAllAsRead)
+	r.g.PUT("/provider/v1/notifications/:id/_mark_as_read", authMw.ServiceProvider, r.notificationHandler.ProviderMarkAsRead)
+
+	r.g.POST("/admin/v1/notifications/_broadcast", authMw.Permission(types.AdminPermissionNotificationsBroadcast), r.notificationHandler.AdminBroadcast)
 }
@@ -23,8 +23,10 @@ func (r *Order) Register(authMw middleware.Auth) {
 	r.g.GET("/consumer/v1/orders", authMw.Consumer, r.orderHandler.ConsumerGetAll)
 	r.g.GET("/consumer/v1/orders/:id", authMw.Consumer, r.orderHandler.ConsumerGetByID)
 	r.g.POST("/consumer/v1/orders/:id/_qr_code", authMw.Consumer, r.orderHandler.ConsumerGenerateQRCode)
+	r.g.POST("/consumer/v1/orders/:id/_cancel", authMw.Consumer, r.orderHandler.ConsumerCancel)
 
 	r.g.GET("/provider/v1/orders", authMw.ServiceProvider, r.orderHandler.ProviderGetAll)
 	r.g.GET("/provider/v1/orders/:id", authMw.ServiceProvider, r.orderHandler.ProviderGetByID)
 	r.g.POST("/provider/v1/orders/_finish", authMw.ServiceProvider, r.orderHandler.ProviderFinish)
+	r.g.POST("/provider/v1/orders/:id/_cancel", authMw.ServiceProvider, r.orderHandler.ProviderCancel)
 }
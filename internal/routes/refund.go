@@ -0,0 +1,27 @@
+package routes
+
+import (
+	"kelarin/internal/handler"
+	"kelarin/internal/middleware"
+	"kelarin/internal/types"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Refund struct {
+	g             *gin.Engine
+	refundHandler handler.Refund
+}
+
+func NewRefund(g *gin.Engine, refundHandler handler.Refund) *Refund {
+	return &Refund{
+		g:             g,
+		refundHandler: refundHandler,
+	}
+}
+
+func (r *Refund) Register(authMw middleware.Auth) {
+	r.g.GET("/admin/v1/refunds", authMw.Permission(types.AdminPermissionRefundsRead), r.refundHandler.AdminGetAll)
+	r.g.POST("/admin/v1/refunds/:id/_retry", authMw.Permission(types.AdminPermissionRefundsManage), r.refundHandler.AdminRetry)
+	r.g.POST("/admin/v1/refunds/:id/_mark_as_succeeded", authMw.Permission(types.AdminPermissionRefundsManage), r.refundHandler.AdminMarkAsSucceeded)
+}
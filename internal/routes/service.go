@@ -28,7 +28,7 @@ func (r *Service) Register(m middleware.Auth) {
 	r.g.POST("/provider/v1/services/:id/_images", m.ServiceProvider, r.serviceHandler.AddImages)
 	r.g.DELETE("/provider/v1/services/:id/_images", m.ServiceProvider, r.serviceHandler.RemoveImages)
 
-	r.g.GET("/v1/services", r.serviceHandler.ConsumerGetAll)
+	r.g.GET("/v1/services", m.Optional, r.serviceHandler.ConsumerGetAll)
 	r.g.GET("/v1/services/:id", r.serviceHandler.ConsumerGetByID)
 
 	r.g.GET("/v1/services/:id/feedbacks", r.serviceHandler.ConsumerGetAllFeedback)
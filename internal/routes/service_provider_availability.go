@@ -0,0 +1,31 @@
+package routes
+
+import (
+	"kelarin/internal/handler"
+	"kelarin/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ServiceProviderAvailability struct {
+	g                                  *gin.Engine
+	serviceProviderAvailabilityHandler handler.ServiceProviderAvailability
+}
+
+func NewServiceProviderAvailability(g *gin.Engine, serviceProviderAvailabilityHandler handler.ServiceProviderAvailability) *ServiceProviderAvailability {
+	return &ServiceProviderAvailability{
+		g:                                  g,
+		serviceProviderAvailabilityHandler: serviceProviderAvailabilityHandler,
+	}
+}
+
+func (r *ServiceProviderAvailability) Register(authMw middleware.Auth) {
+	r.g.GET("/v1/services/:id/slots", r.serviceProviderAvailabilityHandler.GetSlots)
+
+	r.g.GET("/provider/v1/working-hours", authMw.ServiceProvider, r.serviceProviderAvailabilityHandler.ProviderGetWorkingHours)
+	r.g.PUT("/provider/v1/working-hours", authMw.ServiceProvider, r.serviceProviderAvailabilityHandler.ProviderUpdateWorkingHours)
+	r.g.GET("/provider/v1/days-off", authMw.ServiceProvider, r.serviceProviderAvailabilityHandler.ProviderGetAllDaysOff)
+	r.g.POST("/provider/v1/days-off", authMw.ServiceProvider, r.serviceProviderAvailabilityHandler.ProviderCreateDayOff)
+	r.g.DELETE("/provider/v1/days-off/:id", authMw.ServiceProvider, r.serviceProviderAvailabilityHandler.ProviderDeleteDayOff)
+	r.g.PUT("/provider/v1/services/:id/_availability", authMw.ServiceProvider, r.serviceProviderAvailabilityHandler.ProviderUpdateService)
+}
@@ -0,0 +1,36 @@
+package routes
+
+import (
+	"kelarin/internal/handler"
+	"kelarin/internal/middleware"
+	"kelarin/internal/types"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ServiceProviderCredit struct {
+	g                            *gin.Engine
+	serviceProviderCreditHandler handler.ServiceProviderCredit
+}
+
+func NewServiceProviderCredit(g *gin.Engine, serviceProviderCreditHandler handler.ServiceProviderCredit) *ServiceProviderCredit {
+	return &ServiceProviderCredit{
+		g:                            g,
+		serviceProviderCreditHandler: serviceProviderCreditHandler,
+	}
+}
+
+func (r *ServiceProviderCredit) Register(authMw middleware.Auth) {
+	r.g.GET("/provider/v1/credits", authMw.ServiceProvider, r.serviceProviderCreditHandler.ProviderGet)
+	r.g.GET("/provider/v1/credits/ledgers", authMw.ServiceProvider, r.serviceProviderCreditHandler.ProviderGetLedgers)
+	r.g.GET("/provider/v1/bank-accounts", authMw.ServiceProvider, r.serviceProviderCreditHandler.ProviderGetAllBankAccounts)
+	r.g.POST("/provider/v1/bank-accounts", authMw.ServiceProvider, r.serviceProviderCreditHandler.ProviderCreateBankAccount)
+	r.g.DELETE("/provider/v1/bank-accounts/:id", authMw.ServiceProvider, r.serviceProviderCreditHandler.ProviderDeleteBankAccount)
+	r.g.GET("/provider/v1/payouts", authMw.ServiceProvider, r.serviceProviderCreditHandler.ProviderGetAllPayouts)
+	r.g.POST("/provider/v1/payouts", authMw.ServiceProvider, r.serviceProviderCreditHandler.ProviderCreatePayout)
+
+	r.g.GET("/admin/v1/payouts", authMw.Permission(types.AdminPermissionPayoutsRead), r.serviceProviderCreditHandler.AdminGetAllPayouts)
+	r.g.POST("/admin/v1/payouts/:id/_approve", authMw.Permission(types.AdminPermissionPayoutsApprove), r.serviceProviderCreditHandler.AdminApprovePayout)
+	r.g.POST("/admin/v1/payouts/:id/_reject", authMw.Permission(types.AdminPermissionPayoutsApprove), r.serviceProviderCreditHandler.AdminRejectPayout)
+	r.g.POST("/admin/v1/payouts/:id/_mark_as_paid", authMw.Permission(types.AdminPermissionPayoutsMarkAsPaid), r.serviceProviderCreditHandler.AdminMarkPayoutAsPaid)
+}
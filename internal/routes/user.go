@@ -2,12 +2,14 @@ package routes
 
 import (
 	"kelarin/internal/handler"
+	"kelarin/internal/middleware"
+	"kelarin/internal/types"
 
 	"github.com/gin-gonic/gin"
 )
 
 type User interface {
-	Register()
+	Register(authMw middleware.Auth)
 }
 
 type userImpl struct {
@@ -22,6 +24,14 @@ func NewUser(g *gin.Engine, userHandler handler.User) User {
 	}
 }
 
-func (u *userImpl) Register() {
+func (u *userImpl) Register(authMw middleware.Auth) {
 	u.g.POST("/", u.userHandler.GetOne)
+
+	u.g.GET("/admin/v1/users", authMw.Permission(types.AdminPermissionUsersRead), u.userHandler.AdminGetAll)
+	u.g.GET("/admin/v1/users/:id", authMw.Permission(types.AdminPermissionUsersRead), u.userHandler.AdminGetByID)
+	u.g.POST("/admin/v1/users/:id/_suspend", authMw.Permission(types.AdminPermissionUsersSuspend), u.userHandler.AdminSuspend)
+	u.g.POST("/admin/v1/users/:id/_unsuspend", authMw.Permission(types.AdminPermissionUsersSuspend), u.userHandler.AdminUnsuspend)
+	u.g.POST("/admin/v1/users/:id/_ban", authMw.Permission(types.AdminPermissionUsersBan), u.userHandler.AdminBan)
+	u.g.POST("/admin/v1/users/:id/_unban", authMw.Permission(types.AdminPermissionUsersBan), u.userHandler.AdminUnban)
+	u.g.POST("/admin/v1/users/:id/_revoke_sessions", authMw.Permission(types.AdminPermissionUsersRevokeSessions), u.userHandler.AdminRevokeSessions)
 }
@@ -0,0 +1,398 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"kelarin/internal/repository"
+	"kelarin/internal/types"
+	dbUtil "kelarin/internal/utils/dbutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-errors/errors"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"github.com/samber/lo"
+	"github.com/volatiletech/null/v9"
+)
+
+type AdminRole interface {
+	GetAllPermissions(ctx context.Context) []types.AdminPermissionRes
+	GetAll(ctx context.Context, req types.AdminRoleGetAllReq) ([]types.AdminRoleRes, error)
+	Create(ctx context.Context, req types.AdminRoleCreateReq) (types.AdminRoleRes, error)
+	Update(ctx context.Context, req types.AdminRoleUpdateReq) error
+	Delete(ctx context.Context, req types.AdminRoleDeleteReq) error
+	GetByUserID(ctx context.Context, req types.AdminRoleGetByUserIDReq) ([]types.AdminRoleRes, error)
+	AssignToUser(ctx context.Context, req types.AdminRoleAssignReq) error
+}
+
+type adminRoleImpl struct {
+	beginMainDBTx            dbUtil.SqlxTx
+	adminRoleRepo            repository.AdminRole
+	adminPermissionCacheRepo repository.AdminPermissionCache
+	userRepo                 repository.User
+}
+
+func NewAdminRole(beginMainDBTx dbUtil.SqlxTx, adminRoleRepo repository.AdminRole, adminPermissionCacheRepo repository.AdminPermissionCache, userRepo repository.User) AdminRole {
+	return &adminRoleImpl{
+		beginMainDBTx:            beginMainDBTx,
+		adminRoleRepo:            adminRoleRepo,
+		adminPermissionCacheRepo: adminPermissionCacheRepo,
+		userRepo:                 userRepo,
+	}
+}
+
+func (s *adminRoleImpl) GetAllPermissions(ctx context.Context) []types.AdminPermissionRes {
+	return types.AdminPermissions
+}
+
+func (s *adminRoleImpl) GetAll(ctx context.Context, req types.AdminRoleGetAllReq) ([]types.AdminRoleRes, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	roles, err := s.adminRoleRepo.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return lo.Map(roles, toAdminRoleRes), nil
+}
+
+func (s *adminRoleImpl) Create(ctx context.Context, req types.AdminRoleCreateReq) (types.AdminRoleRes, error) {
+	res := types.AdminRoleRes{}
+
+	if err := req.ValidateAndNormalize(); err != nil {
+		return res, err
+	}
+
+	permissions := toAdminPermissionNames(req.Permissions)
+	if err := s.checkGrantable(ctx, req.AuthUser, permissions); err != nil {
+		return res, err
+	}
+
+	if err := s.checkNameIsAvailable(ctx, req.Name, uuid.Nil); err != nil {
+		return res, err
+	}
+
+	id, err := uuid.NewV7()
+	if err != nil {
+		return res, errors.New(err)
+	}
+
+	role := types.AdminRole{
+		ID:          id,
+		Name:        req.Name,
+		Description: req.Description,
+		Permissions: permissions,
+		CreatedAt:   time.Now(),
+	}
+
+	tx, err := s.beginMainDBTx(ctx, nil)
+	if err != nil {
+		return res, err
+	}
+	defer tx.Rollback()
+
+	if err = s.adminRoleRepo.CreateTx(ctx, tx, role); err != nil {
+		return res, err
+	}
+
+	if err = s.adminRoleRepo.ReplacePermissionsTx(ctx, tx, role.ID, role.Permissions); err != nil {
+		return res, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return res, errors.New(err)
+	}
+
+	return toAdminRoleRes(role, 0), nil
+}
+
+// Update replaces the permissions of the role, the admins of the role get the new permissions on their next request
+func (s *adminRoleImpl) Update(ctx context.Context, req types.AdminRoleUpdateReq) error {
+	if err := req.ValidateAndNormalize(); err != nil {
+		return err
+	}
+
+	permissions := toAdminPermissionNames(req.Permissions)
+
+	tx, err := s.beginMainDBTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	role, err := s.findEditableForUpdate(ctx, tx, req.ID)
+	if err != nil {
+		return err
+	}
+
+	// the permissions taken away from the admins of the role also have to be held, like the permissions given to them
+	removedPermissions, _ := lo.Difference(role.Permissions, permissions)
+	if err = s.checkGrantable(ctx, req.AuthUser, append(removedPermissions, permissions...)); err != nil {
+		return err
+	}
+
+	if err = s.checkNameIsAvailable(ctx, req.Name, role.ID); err != nil {
+		return err
+	}
+
+	role.Name = req.Name
+	role.Description = req.Description
+	role.UpdatedAt = null.TimeFrom(time.Now())
+
+	if err = s.adminRoleRepo.UpdateTx(ctx, tx, role); err != nil {
+		return err
+	}
+
+	if err = s.adminRoleRepo.ReplacePermissionsTx(ctx, tx, role.ID, permissions); err != nil {
+		return err
+	}
+
+	userIDs, err := s.adminRoleRepo.FindUserIDsByRoleIDTx(ctx, tx, role.ID)
+	if err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return errors.New(err)
+	}
+
+	s.invalidatePermissionCache(ctx, userIDs...)
+
+	return nil
+}
+
+func (s *adminRoleImpl) Delete(ctx context.Context, req types.AdminRoleDeleteReq) error {
+	if err := req.Validate(); err != nil {
+		return err
+	}
+
+	tx, err := s.beginMainDBTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	role, err := s.findEditableForUpdate(ctx, tx, req.ID)
+	if err != nil {
+		return err
+	}
+
+	// deleting the role takes every permission of the role away from its admins
+	if err = s.checkGrantable(ctx, req.AuthUser, role.Permissions); err != nil {
+		return err
+	}
+
+	// the assignments are removed together with the role, the admins are looked up first
+	userIDs, err := s.adminRoleRepo.FindUserIDsByRoleIDTx(ctx, tx, role.ID)
+	if err != nil {
+		return err
+	}
+
+	if err = s.adminRoleRepo.DeleteTx(ctx, tx, role.ID); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return errors.New(err)
+	}
+
+	s.invalidatePermissionCache(ctx, userIDs...)
+
+	return nil
+}
+
+func (s *adminRoleImpl) GetByUserID(ctx context.Context, req types.AdminRoleGetByUserIDReq) ([]types.AdminRoleRes, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.findAdmin(ctx, req.UserID); err != nil {
+		return nil, err
+	}
+
+	roles, err := s.adminRoleRepo.FindAllByUserID(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	return lo.Map(roles, toAdminRoleRes), nil
+}
+
+// AssignToUser replaces the roles of another admin, an admin can not change their own roles to grant themselves a permission.
+// Only the roles whose permissions the admin has can be given or taken away, a system role can only be taken away while
+// another admin keeps it
+func (s *adminRoleImpl) AssignToUser(ctx context.Context, req types.AdminRoleAssignReq) error {
+	if err := req.Validate(); err != nil {
+		return err
+	}
+
+	if req.AuthUser.ID == req.UserID {
+		return errors.New(types.AppErr{Code: http.StatusForbidden, Message: "cannot change your own roles"})
+	}
+
+	if _, err := s.findAdmin(ctx, req.UserID); err != nil {
+		return err
+	}
+
+	roleIDs := lo.Uniq(req.RoleIDs)
+	roles, err := s.adminRoleRepo.FindByIDs(ctx, roleIDs)
+	if err != nil {
+		return err
+	}
+
+	if len(roles) != len(roleIDs) {
+		return errors.New(types.AppErr{Code: http.StatusBadRequest, Message: "role not found"})
+	}
+
+	currentRoles, err := s.adminRoleRepo.FindAllByUserID(ctx, req.UserID)
+	if err != nil {
+		return err
+	}
+
+	currentRoleIDs := lo.Map(currentRoles, func(role types.AdminRole, _ int) uuid.UUID { return role.ID })
+	addedRoles := lo.Filter(roles, func(role types.AdminRole, _ int) bool { return !lo.Contains(currentRoleIDs, role.ID) })
+	removedRoles := lo.Filter(currentRoles, func(role types.AdminRole, _ int) bool { return !lo.Contains(roleIDs, role.ID) })
+
+	if lo.SomeBy(addedRoles, func(role types.AdminRole) bool { return role.IsSystem }) {
+		return errors.New(types.AppErr{Code: http.StatusForbidden, Message: "a system role cannot be assigned"})
+	}
+
+	changedPermissions := lo.FlatMap(append(addedRoles, removedRoles...), func(role types.AdminRole, _ int) []string { return role.Permissions })
+	if err = s.checkGrantable(ctx, req.AuthUser, lo.Uniq(changedPermissions)); err != nil {
+		return err
+	}
+
+	removedSystemRoles := lo.Filter(removedRoles, func(role types.AdminRole, _ int) bool { return role.IsSystem })
+
+	tx, err := s.beginMainDBTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// the lock makes the admins taken off the same system role at the same time see each other's change
+	for _, role := range removedSystemRoles {
+		if _, err = s.adminRoleRepo.FindForUpdateByID(ctx, tx, role.ID); err != nil {
+			return err
+		}
+	}
+
+	if err = s.adminRoleRepo.ReplaceUserRolesTx(ctx, tx, req.UserID, roleIDs); err != nil {
+		return err
+	}
+
+	for _, role := range removedSystemRoles {
+		userIDs, err := s.adminRoleRepo.FindUserIDsByRoleIDTx(ctx, tx, role.ID)
+		if err != nil {
+			return err
+		}
+
+		if len(userIDs) == 0 {
+			return errors.New(types.AppErr{Code: http.StatusConflict, Message: fmt.Sprintf("at least one admin must keep the %s role", role.Name)})
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return errors.New(err)
+	}
+
+	s.invalidatePermissionCache(ctx, req.UserID)
+
+	return nil
+}
+
+// findEditableForUpdate locks the role when it is not a system role, the assignments made before the lock are committed
+func (s *adminRoleImpl) findEditableForUpdate(ctx context.Context, tx dbUtil.Tx, ID uuid.UUID) (types.AdminRole, error) {
+	role, err := s.adminRoleRepo.FindForUpdateByID(ctx, tx, ID)
+	if errors.Is(err, types.ErrNoData) {
+		return role, errors.New(types.AppErr{Code: http.StatusNotFound, Message: "role not found"})
+	} else if err != nil {
+		return role, err
+	}
+
+	if role.IsSystem {
+		return role, errors.New(types.AppErr{Code: http.StatusForbidden, Message: "a system role cannot be changed"})
+	}
+
+	return role, nil
+}
+
+func (s *adminRoleImpl) findAdmin(ctx context.Context, userID uuid.UUID) (types.User, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if errors.Is(err, types.ErrNoData) {
+		return user, errors.New(types.AppErr{Code: http.StatusNotFound, Message: "user not found"})
+	} else if err != nil {
+		return user, err
+	}
+
+	if user.Role != types.UserRoleAdmin {
+		return user, errors.New(types.AppErr{Code: http.StatusBadRequest, Message: "roles can only be assigned to an admin"})
+	}
+
+	return user, nil
+}
+
+// checkNameIsAvailable compares the names case-insensitively, excludeID is the role being renamed
+func (s *adminRoleImpl) checkNameIsAvailable(ctx context.Context, name string, excludeID uuid.UUID) error {
+	roles, err := s.adminRoleRepo.FindAll(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, role := range roles {
+		if role.ID != excludeID && strings.EqualFold(role.Name, name) {
+			return errors.New(types.AppErr{Code: http.StatusConflict, Message: "role name already exists"})
+		}
+	}
+
+	return nil
+}
+
+// checkGrantable rejects the permissions the admin does not have, an admin can not give anyone more access than their own
+func (s *adminRoleImpl) checkGrantable(ctx context.Context, authUser types.AuthUser, permissions []string) error {
+	granted, err := s.adminRoleRepo.FindPermissionsByUserID(ctx, authUser.ID)
+	if err != nil {
+		return err
+	}
+
+	for _, permission := range permissions {
+		if !types.HasAdminPermission(granted, types.AdminPermission(permission)) {
+			return errors.New(types.AppErr{Code: http.StatusForbidden, Message: "cannot grant a permission you do not have"})
+		}
+	}
+
+	return nil
+}
+
+// invalidatePermissionCache makes every session of the users load the permissions again, a failure is only logged
+// because the cache expires on its own
+func (s *adminRoleImpl) invalidatePermissionCache(ctx context.Context, userIDs ...uuid.UUID) {
+	if err := s.adminPermissionCacheRepo.Invalidate(ctx, types.AdminPermissionCacheExpiration, userIDs...); err != nil {
+		log.Error().Stack().Err(err).Send()
+	}
+}
+
+func toAdminPermissionNames(permissions []types.AdminPermission) []string {
+	return lo.Uniq(lo.Map(permissions, func(permission types.AdminPermission, _ int) string { return string(permission) }))
+}
+
+func toAdminRoleRes(role types.AdminRole, _ int) types.AdminRoleRes {
+	permissions := []string(role.Permissions)
+	if permissions == nil {
+		permissions = []string{}
+	}
+
+	return types.AdminRoleRes{
+		ID:          role.ID,
+		Name:        role.Name,
+		Description: role.Description,
+		IsSystem:    role.IsSystem,
+		Permissions: permissions,
+		CreatedAt:   role.CreatedAt,
+		UpdatedAt:   role.UpdatedAt,
+	}
+}
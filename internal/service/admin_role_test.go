@@ -0,0 +1,248 @@
+package service_test
+
+import (
+	"context"
+	repoMock "kelarin/internal/mocks/repository"
+	"kelarin/internal/service"
+	"kelarin/internal/types"
+	dbUtil "kelarin/internal/utils/dbutil"
+	"net/http"
+	"testing"
+
+	"github.com/go-errors/errors"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	sqlxmock "github.com/zhashkevych/go-sqlxmock"
+)
+
+func TestAdminRoleService(t *testing.T) {
+	ctx := context.Background()
+
+	authUser := types.AuthUser{ID: uuid.New(), SessionID: uuid.New(), Role: types.UserRoleAdmin}
+	targetUserID := uuid.New()
+
+	superAdminRole := types.AdminRole{ID: uuid.New(), Name: "super-admin", IsSystem: true, Permissions: pq.StringArray{string(types.AdminPermissionAll)}}
+	moderatorRole := types.AdminRole{ID: uuid.New(), Name: "moderator", Permissions: pq.StringArray{string(types.AdminPermissionUsersRead), string(types.AdminPermissionUsersBan)}}
+	supportRole := types.AdminRole{ID: uuid.New(), Name: "support", Permissions: pq.StringArray{string(types.AdminPermissionUsersRead)}}
+
+	type deps struct {
+		dbMock                   sqlxmock.Sqlmock
+		adminRoleRepo            *repoMock.AdminRole
+		adminPermissionCacheRepo *repoMock.AdminPermissionCache
+		userRepo                 *repoMock.User
+	}
+
+	newService := func(t *testing.T) (service.AdminRole, deps) {
+		db, dbMock, err := sqlxmock.Newx()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+
+		t.Cleanup(func() {
+			assert.NoError(t, dbMock.ExpectationsWereMet())
+			db.Close()
+		})
+
+		d := deps{
+			dbMock:                   dbMock,
+			adminRoleRepo:            repoMock.NewAdminRole(t),
+			adminPermissionCacheRepo: repoMock.NewAdminPermissionCache(t),
+			userRepo:                 repoMock.NewUser(t),
+		}
+
+		return service.NewAdminRole(dbUtil.NewSqlxTx(db), d.adminRoleRepo, d.adminPermissionCacheRepo, d.userRepo), d
+	}
+
+	assertAppErrCode := func(t *testing.T, err error, code int) {
+		appErr := types.AppErr{}
+		if assert.True(t, errors.As(err, &appErr), "expected an AppErr, got %v", err) {
+			assert.Equal(t, code, appErr.Code)
+		}
+	}
+
+	t.Run("Test AssignToUser rejects a system role", func(t *testing.T) {
+		adminRoleSvc, d := newService(t)
+
+		d.userRepo.Mock.On("FindByID", ctx, targetUserID).Return(types.User{ID: targetUserID, Role: types.UserRoleAdmin}, nil)
+		d.adminRoleRepo.Mock.On("FindByIDs", ctx, uuid.UUIDs{superAdminRole.ID}).Return([]types.AdminRole{superAdminRole}, nil)
+		d.adminRoleRepo.Mock.On("FindAllByUserID", ctx, targetUserID).Return([]types.AdminRole{}, nil)
+
+		err := adminRoleSvc.AssignToUser(ctx, types.AdminRoleAssignReq{AuthUser: authUser, UserID: targetUserID, RoleIDs: []uuid.UUID{superAdminRole.ID}})
+
+		assertAppErrCode(t, err, http.StatusForbidden)
+	})
+
+	t.Run("Test AssignToUser rejects a role with a permission the admin does not have", func(t *testing.T) {
+		adminRoleSvc, d := newService(t)
+
+		d.userRepo.Mock.On("FindByID", ctx, targetUserID).Return(types.User{ID: targetUserID, Role: types.UserRoleAdmin}, nil)
+		d.adminRoleRepo.Mock.On("FindByIDs", ctx, uuid.UUIDs{moderatorRole.ID}).Return([]types.AdminRole{moderatorRole}, nil)
+		d.adminRoleRepo.Mock.On("FindAllByUserID", ctx, targetUserID).Return([]types.AdminRole{}, nil)
+		d.adminRoleRepo.Mock.On("FindPermissionsByUserID", ctx, authUser.ID).Return([]string{string(types.AdminPermissionUsersRead), string(types.AdminPermissionRolesManage)}, nil)
+
+		err := adminRoleSvc.AssignToUser(ctx, types.AdminRoleAssignReq{AuthUser: authUser, UserID: targetUserID, RoleIDs: []uuid.UUID{moderatorRole.ID}})
+
+		assertAppErrCode(t, err, http.StatusForbidden)
+	})
+
+	t.Run("Test AssignToUser keeps the last admin of a system role", func(t *testing.T) {
+		adminRoleSvc, d := newService(t)
+
+		d.userRepo.Mock.On("FindByID", ctx, targetUserID).Return(types.User{ID: targetUserID, Role: types.UserRoleAdmin}, nil)
+		d.adminRoleRepo.Mock.On("FindByIDs", ctx, uuid.UUIDs{}).Return([]types.AdminRole{}, nil)
+		d.adminRoleRepo.Mock.On("FindAllByUserID", ctx, targetUserID).Return([]types.AdminRole{superAdminRole}, nil)
+		d.adminRoleRepo.Mock.On("FindPermissionsByUserID", ctx, authUser.ID).Return([]string{string(types.AdminPermissionAll)}, nil)
+
+		d.dbMock.ExpectBegin()
+		d.dbMock.ExpectRollback()
+
+		d.adminRoleRepo.Mock.On("FindForUpdateByID", ctx, mock.Anything, superAdminRole.ID).Return(superAdminRole, nil)
+		d.adminRoleRepo.Mock.On("ReplaceUserRolesTx", ctx, mock.Anything, targetUserID, uuid.UUIDs{}).Return(nil)
+		d.adminRoleRepo.Mock.On("FindUserIDsByRoleIDTx", ctx, mock.Anything, superAdminRole.ID).Return(uuid.UUIDs{}, nil)
+
+		err := adminRoleSvc.AssignToUser(ctx, types.AdminRoleAssignReq{AuthUser: authUser, UserID: targetUserID, RoleIDs: []uuid.UUID{}})
+
+		assertAppErrCode(t, err, http.StatusConflict)
+	})
+
+	t.Run("Test AssignToUser replaces the roles and invalidates the permissions of the admin", func(t *testing.T) {
+		adminRoleSvc, d := newService(t)
+
+		d.userRepo.Mock.On("FindByID", ctx, targetUserID).Return(types.User{ID: targetUserID, Role: types.UserRoleAdmin}, nil)
+		d.adminRoleRepo.Mock.On("FindByIDs", ctx, uuid.UUIDs{supportRole.ID}).Return([]types.AdminRole{supportRole}, nil)
+		d.adminRoleRepo.Mock.On("FindAllByUserID", ctx, targetUserID).Return([]types.AdminRole{moderatorRole}, nil)
+		d.adminRoleRepo.Mock.On("FindPermissionsByUserID", ctx, authUser.ID).Return([]string{string(types.AdminPermissionUsersRead), string(types.AdminPermissionUsersBan)}, nil)
+
+		d.dbMock.ExpectBegin()
+		d.dbMock.ExpectCommit()
+
+		d.adminRoleRepo.Mock.On("ReplaceUserRolesTx", ctx, mock.Anything, targetUserID, uuid.UUIDs{supportRole.ID}).Return(nil)
+		d.adminPermissionCacheRepo.Mock.On("Invalidate", ctx, types.AdminPermissionCacheExpiration, targetUserID).Return(nil)
+
+		err := adminRoleSvc.AssignToUser(ctx, types.AdminRoleAssignReq{AuthUser: authUser, UserID: targetUserID, RoleIDs: []uuid.UUID{supportRole.ID}})
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("Test Create rejects a permission the admin does not have", func(t *testing.T) {
+		adminRoleSvc, d := newService(t)
+
+		d.adminRoleRepo.Mock.On("FindPermissionsByUserID", ctx, authUser.ID).Return([]string{string(types.AdminPermissionRolesManage)}, nil)
+
+		_, err := adminRoleSvc.Create(ctx, types.AdminRoleCreateReq{
+			AuthUser:    authUser,
+			Name:        "payouts",
+			Permissions: []types.AdminPermission{types.AdminPermissionPayoutsApprove},
+		})
+
+		assertAppErrCode(t, err, http.StatusForbidden)
+	})
+
+	t.Run("Test Update rejects a permission the admin does not have", func(t *testing.T) {
+		adminRoleSvc, d := newService(t)
+
+		d.dbMock.ExpectBegin()
+		d.dbMock.ExpectRollback()
+
+		d.adminRoleRepo.Mock.On("FindForUpdateByID", ctx, mock.Anything, supportRole.ID).Return(supportRole, nil)
+		d.adminRoleRepo.Mock.On("FindPermissionsByUserID", ctx, authUser.ID).Return([]string{string(types.AdminPermissionUsersRead)}, nil)
+
+		err := adminRoleSvc.Update(ctx, types.AdminRoleUpdateReq{
+			AuthUser:    authUser,
+			ID:          supportRole.ID,
+			Name:        supportRole.Name,
+			Permissions: []types.AdminPermission{types.AdminPermissionUsersRead, types.AdminPermissionUsersBan},
+		})
+
+		assertAppErrCode(t, err, http.StatusForbidden)
+	})
+
+	t.Run("Test Update rejects removing a permission the admin does not have", func(t *testing.T) {
+		adminRoleSvc, d := newService(t)
+
+		d.dbMock.ExpectBegin()
+		d.dbMock.ExpectRollback()
+
+		d.adminRoleRepo.Mock.On("FindForUpdateByID", ctx, mock.Anything, moderatorRole.ID).Return(moderatorRole, nil)
+		d.adminRoleRepo.Mock.On("FindPermissionsByUserID", ctx, authUser.ID).Return([]string{string(types.AdminPermissionUsersRead), string(types.AdminPermissionRolesManage)}, nil)
+
+		err := adminRoleSvc.Update(ctx, types.AdminRoleUpdateReq{
+			AuthUser:    authUser,
+			ID:          moderatorRole.ID,
+			Name:        moderatorRole.Name,
+			Permissions: []types.AdminPermission{types.AdminPermissionUsersRead},
+		})
+
+		assertAppErrCode(t, err, http.StatusForbidden)
+	})
+
+	t.Run("Test Update replaces the permissions the admin has and invalidates the admins of the role", func(t *testing.T) {
+		adminRoleSvc, d := newService(t)
+
+		d.dbMock.ExpectBegin()
+		d.dbMock.ExpectCommit()
+
+		d.adminRoleRepo.Mock.On("FindForUpdateByID", ctx, mock.Anything, moderatorRole.ID).Return(moderatorRole, nil)
+		d.adminRoleRepo.Mock.On("FindPermissionsByUserID", ctx, authUser.ID).Return([]string{string(types.AdminPermissionUsersRead), string(types.AdminPermissionUsersBan)}, nil)
+		d.adminRoleRepo.Mock.On("FindAll", ctx).Return([]types.AdminRole{moderatorRole, supportRole}, nil)
+		d.adminRoleRepo.Mock.On("UpdateTx", ctx, mock.Anything, mock.MatchedBy(func(role types.AdminRole) bool { return role.ID == moderatorRole.ID })).Return(nil)
+		d.adminRoleRepo.Mock.On("ReplacePermissionsTx", ctx, mock.Anything, moderatorRole.ID, []string{string(types.AdminPermissionUsersRead)}).Return(nil)
+		d.adminRoleRepo.Mock.On("FindUserIDsByRoleIDTx", ctx, mock.Anything, moderatorRole.ID).Return(uuid.UUIDs{targetUserID}, nil)
+		d.adminPermissionCacheRepo.Mock.On("Invalidate", ctx, types.AdminPermissionCacheExpiration, targetUserID).Return(nil)
+
+		err := adminRoleSvc.Update(ctx, types.AdminRoleUpdateReq{
+			AuthUser:    authUser,
+			ID:          moderatorRole.ID,
+			Name:        moderatorRole.Name,
+			Permissions: []types.AdminPermission{types.AdminPermissionUsersRead},
+		})
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("Test Delete rejects a role with a permission the admin does not have", func(t *testing.T) {
+		adminRoleSvc, d := newService(t)
+
+		d.dbMock.ExpectBegin()
+		d.dbMock.ExpectRollback()
+
+		d.adminRoleRepo.Mock.On("FindForUpdateByID", ctx, mock.Anything, moderatorRole.ID).Return(moderatorRole, nil)
+		d.adminRoleRepo.Mock.On("FindPermissionsByUserID", ctx, authUser.ID).Return([]string{string(types.AdminPermissionRolesManage)}, nil)
+
+		err := adminRoleSvc.Delete(ctx, types.AdminRoleDeleteReq{AuthUser: authUser, ID: moderatorRole.ID})
+
+		assertAppErrCode(t, err, http.StatusForbidden)
+	})
+
+	t.Run("Test Delete finds the admins of the role in its transaction", func(t *testing.T) {
+		adminRoleSvc, d := newService(t)
+
+		d.dbMock.ExpectBegin()
+		d.dbMock.ExpectCommit()
+
+		d.adminRoleRepo.Mock.On("FindForUpdateByID", ctx, mock.Anything, supportRole.ID).Return(supportRole, nil)
+		d.adminRoleRepo.Mock.On("FindPermissionsByUserID", ctx, authUser.ID).Return([]string{string(types.AdminPermissionUsersRead), string(types.AdminPermissionRolesManage)}, nil)
+		d.adminRoleRepo.Mock.On("FindUserIDsByRoleIDTx", ctx, mock.Anything, supportRole.ID).Return(uuid.UUIDs{targetUserID}, nil)
+		d.adminRoleRepo.Mock.On("DeleteTx", ctx, mock.Anything, supportRole.ID).Return(nil)
+		d.adminPermissionCacheRepo.Mock.On("Invalidate", ctx, types.AdminPermissionCacheExpiration, targetUserID).Return(nil)
+
+		err := adminRoleSvc.Delete(ctx, types.AdminRoleDeleteReq{AuthUser: authUser, ID: supportRole.ID})
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("Test Delete rejects a system role", func(t *testing.T) {
+		adminRoleSvc, d := newService(t)
+
+		d.dbMock.ExpectBegin()
+		d.dbMock.ExpectRollback()
+
+		d.adminRoleRepo.Mock.On("FindForUpdateByID", ctx, mock.Anything, superAdminRole.ID).Return(superAdminRole, nil)
+
+		err := adminRoleSvc.Delete(ctx, types.AdminRoleDeleteReq{AuthUser: authUser, ID: superAdminRole.ID})
+
+		assertAppErrCode(t, err, http.StatusForbidden)
+	})
+}
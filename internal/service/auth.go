@@ -13,6 +13,8 @@ import (
 	"github.com/go-errors/errors"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"github.com/volatiletech/null/v9"
 	"golang.org/x/crypto/bcrypt"
 	"google.golang.org/api/idtoken"
 )
@@ -23,6 +25,18 @@ type Auth interface {
 	ProviderCreateSession(ctx context.Context, req types.AuthCreateSessionForGoogleReq) (types.AuthCreateSessionForGoogleLoginRes, error)
 	RenewSession(ctx context.Context, req types.AuthRenewSessionReq) (types.AuthRenewSessionRes, error)
 	RevokeSession(ctx context.Context, req types.AuthRevokeSessionReq) error
+	GetSessions(ctx context.Context, req types.SessionGetAllReq) ([]types.SessionGetAllRes, error)
+	RevokeSessionByID(ctx context.Context, req types.SessionRevokeReq) error
+	RevokeOtherSessions(ctx context.Context, req types.SessionRevokeOthersReq) error
+	ConsumerRegister(ctx context.Context, req types.AuthRegisterReq) error
+	ProviderRegister(ctx context.Context, req types.AuthRegisterReq) error
+	VerifyEmail(ctx context.Context, req types.AuthVerifyEmailReq) error
+	ResendVerificationEmail(ctx context.Context, req types.AuthEmailReq) error
+	ForgotPassword(ctx context.Context, req types.AuthEmailReq) error
+	ResetPassword(ctx context.Context, req types.AuthResetPasswordReq) error
+	ChangePassword(ctx context.Context, req types.AuthChangePasswordReq) error
+	VerifyTwoFactor(ctx context.Context, req types.AuthVerifyTwoFactorReq) (types.AuthVerifyTwoFactorRes, error)
+	EnrollTwoFactor(ctx context.Context, req types.AuthEnrollTwoFactorReq) (types.TwoFactorSetupRes, error)
 }
 
 type authImpl struct {
@@ -31,22 +45,28 @@ type authImpl struct {
 	sessionRepo             repository.Session
 	userRepo                repository.User
 	pendingRegistrationRepo repository.PendingRegistration
+	authTokenRepo           repository.AuthToken
+	notificationSvc         Notification
+	twoFactorSvc            TwoFactor
 }
 
-func NewAuth(cfg *config.Config, beginMainDBTx dbUtil.SqlxTx, sessionRepo repository.Session, userRepo repository.User, pendingRegistrationRepo repository.PendingRegistration) Auth {
+func NewAuth(cfg *config.Config, beginMainDBTx dbUtil.SqlxTx, sessionRepo repository.Session, userRepo repository.User, pendingRegistrationRepo repository.PendingRegistration, authTokenRepo repository.AuthToken, notificationSvc Notification, twoFactorSvc TwoFactor) Auth {
 	return &authImpl{
 		config:                  cfg,
 		beginMainDBTx:           beginMainDBTx,
 		sessionRepo:             sessionRepo,
 		userRepo:                userRepo,
 		pendingRegistrationRepo: pendingRegistrationRepo,
+		authTokenRepo:           authTokenRepo,
+		notificationSvc:         notificationSvc,
+		twoFactorSvc:            twoFactorSvc,
 	}
 }
 
 func (s *authImpl) LocalCreateSession(ctx context.Context, req types.AuthCreateSessionReq) (types.AuthCreateSessionRes, error) {
 	res := types.AuthCreateSessionRes{}
 
-	if err := req.Validate(); err != nil {
+	if err := req.ValidateAndNormalize(); err != nil {
 		return res, err
 	}
 
@@ -57,7 +77,7 @@ func (s *authImpl) LocalCreateSession(ctx context.Context, req types.AuthCreateS
 		return res, err
 	}
 
-	if user.IsSuspended {
+	if user.IsSuspensionActive(time.Now()) {
 		return res, errors.New(types.AppErr{Code: http.StatusUnauthorized, Message: "your account is suspended"})
 	} else if user.IsBanned {
 		return res, errors.New(types.AppErr{Code: http.StatusUnauthorized, Message: "your account is banned"})
@@ -70,13 +90,25 @@ func (s *authImpl) LocalCreateSession(ctx context.Context, req types.AuthCreateS
 		})
 	}
 
+	if !user.EmailVerifiedAt.Valid {
+		return res, errors.New(types.AppErr{Code: http.StatusForbidden, Message: "your email has not been verified"})
+	}
+
+	res.TwoFactorChallenge, err = s.twoFactorChallenge(ctx, user)
+	if err != nil {
+		return res, err
+	} else if res.TwoFactorChallenge != nil {
+		return res, nil
+	}
+
 	sessionId, err := uuid.NewRandom()
 	if err != nil {
 		return res, errors.New(err)
 	}
 
 	sessionKey := types.GetSessionKey(sessionId.String())
-	err = s.sessionRepo.Set(ctx, sessionKey, user.ID.String(), s.config.JWT.RefreshTokenExpiration)
+	session := newSession(sessionId, user.ID, req.SessionMetadataReq)
+	err = s.sessionRepo.Set(ctx, sessionKey, session, s.config.JWT.RefreshTokenExpiration)
 	if err != nil {
 		return res, err
 	}
@@ -88,7 +120,7 @@ func (s *authImpl) LocalCreateSession(ctx context.Context, req types.AuthCreateS
 		Name:      user.Name,
 	}
 
-	t, err := s.GenerateToken(authUser)
+	t, err := s.GenerateToken(authUser, session.FamilyID)
 	if err != nil {
 		return res, errors.New(err)
 	}
@@ -115,11 +147,12 @@ func (s *authImpl) ConsumerCreateSession(ctx context.Context, req types.AuthCrea
 		}
 
 		user = types.User{
-			ID:           id,
-			Role:         types.UserRoleConsumer,
-			Name:         payload.Name,
-			Email:        payload.Email,
-			AuthProvider: types.AuthProviderGoogle,
+			ID:              id,
+			Role:            types.UserRoleConsumer,
+			Name:            payload.Name,
+			Email:           payload.Email,
+			AuthProvider:    types.AuthProviderGoogle,
+			EmailVerifiedAt: null.TimeFrom(time.Now()),
 		}
 
 		err = s.userRepo.Create(ctx, user)
@@ -134,7 +167,7 @@ func (s *authImpl) ConsumerCreateSession(ctx context.Context, req types.AuthCrea
 		return res, errors.New(types.AppErr{Code: http.StatusUnauthorized, Message: "this account has been registered as a service provider, use another account"})
 	}
 
-	if user.IsSuspended {
+	if user.IsSuspensionActive(time.Now()) {
 		return res, errors.New(types.AppErr{Code: http.StatusUnauthorized, Message: "your account is suspended"})
 	} else if user.IsBanned {
 		return res, errors.New(types.AppErr{Code: http.StatusUnauthorized, Message: "your account is banned"})
@@ -146,7 +179,8 @@ func (s *authImpl) ConsumerCreateSession(ctx context.Context, req types.AuthCrea
 	}
 
 	sessionKey := types.GetSessionKey(sessionId.String())
-	err = s.sessionRepo.Set(ctx, sessionKey, user.ID.String(), s.config.JWT.RefreshTokenExpiration)
+	session := newSession(sessionId, user.ID, req.SessionMetadataReq)
+	err = s.sessionRepo.Set(ctx, sessionKey, session, s.config.JWT.RefreshTokenExpiration)
 	if err != nil {
 		return res, err
 	}
@@ -158,7 +192,7 @@ func (s *authImpl) ConsumerCreateSession(ctx context.Context, req types.AuthCrea
 		Name:      user.Name,
 	}
 
-	t, err := s.GenerateToken(authUser)
+	t, err := s.GenerateToken(authUser, session.FamilyID)
 	if err != nil {
 		return res, errors.New(err)
 	}
@@ -188,11 +222,12 @@ func (s *authImpl) ProviderCreateSession(ctx context.Context, req types.AuthCrea
 		}
 
 		user = types.User{
-			ID:           id,
-			Role:         types.UserRoleServiceProvider,
-			Name:         payload.Name,
-			Email:        payload.Email,
-			AuthProvider: types.AuthProviderGoogle,
+			ID:              id,
+			Role:            types.UserRoleServiceProvider,
+			Name:            payload.Name,
+			Email:           payload.Email,
+			AuthProvider:    types.AuthProviderGoogle,
+			EmailVerifiedAt: null.TimeFrom(time.Now()),
 		}
 
 		tx, err := s.beginMainDBTx(ctx, nil)
@@ -221,19 +256,30 @@ func (s *authImpl) ProviderCreateSession(ctx context.Context, req types.AuthCrea
 		return res, errors.New(types.AppErr{Code: http.StatusUnauthorized, Message: "this account not registered as a provider, use another account"})
 	}
 
-	if user.IsSuspended {
+	if user.IsSuspensionActive(time.Now()) {
 		return res, errors.New(types.AppErr{Code: http.StatusUnauthorized, Message: "your account is suspended"})
 	} else if user.IsBanned {
 		return res, errors.New(types.AppErr{Code: http.StatusUnauthorized, Message: "your account is banned"})
 	}
 
+	// a google account does not skip the second step of a provider that enabled two-factor authentication
+	challenge, err := s.twoFactorChallenge(ctx, user)
+	if err != nil {
+		return res, err
+	} else if challenge != nil {
+		res.Role = user.Role
+		res.TwoFactorChallenge = challenge
+		return res, nil
+	}
+
 	sessionId, err := uuid.NewRandom()
 	if err != nil {
 		return res, errors.New(err)
 	}
 
 	sessionKey := types.GetSessionKey(sessionId.String())
-	err = s.sessionRepo.Set(ctx, sessionKey, user.ID.String(), s.config.JWT.RefreshTokenExpiration)
+	session := newSession(sessionId, user.ID, req.SessionMetadataReq)
+	err = s.sessionRepo.Set(ctx, sessionKey, session, s.config.JWT.RefreshTokenExpiration)
 	if err != nil {
 		return res, err
 	}
@@ -251,7 +297,7 @@ func (s *authImpl) ProviderCreateSession(ctx context.Context, req types.AuthCrea
 		IncompleteRegistration: &incompleteRegistration,
 	}
 
-	t, err := s.GenerateToken(authUser)
+	t, err := s.GenerateToken(authUser, session.FamilyID)
 	if err != nil {
 		return res, errors.New(err)
 	}
@@ -265,7 +311,8 @@ func (s *authImpl) ProviderCreateSession(ctx context.Context, req types.AuthCrea
 	return res, nil
 }
 
-func (s *authImpl) GenerateToken(authUser types.AuthUser) (types.AuthGenerateToken, error) {
+// GenerateToken binds the refresh token to the session family, the family is revoked when a renewed refresh token is used again
+func (s *authImpl) GenerateToken(authUser types.AuthUser, familyID uuid.UUID) (types.AuthGenerateToken, error) {
 	res := types.AuthGenerateToken{}
 
 	accToken := jwt.NewWithClaims(jwt.SigningMethodHS256, types.AuthJwtCustomClaims{
@@ -294,10 +341,11 @@ func (s *authImpl) GenerateToken(authUser types.AuthUser) (types.AuthGenerateTok
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(s.config.JWT.RefreshTokenExpiration)),
 		},
-		ID:      authUser.SessionID,
-		Subject: authUser.ID,
-		Role:    authUser.Role,
-		Name:    authUser.Name,
+		ID:       authUser.SessionID,
+		Subject:  authUser.ID,
+		Role:     authUser.Role,
+		Name:     authUser.Name,
+		FamilyID: &familyID,
 	})
 
 	signedRefreshToken, err := refreshToken.SignedString([]byte(s.config.JWT.RefreshTokenSecretKey))
@@ -353,7 +401,7 @@ func (s *authImpl) RenewSession(ctx context.Context, req types.AuthRenewSessionR
 	sessionKey := types.GetSessionKey(claims.ID.String())
 	userId, err := s.sessionRepo.Find(ctx, sessionKey)
 	if errors.Is(err, types.ErrNoData) {
-		return res, types.AuthErrSessionRevoked
+		return res, s.revokeReusedFamily(ctx, claims, req.SessionMetadataReq)
 	} else if err != nil {
 		return res, err
 	}
@@ -370,14 +418,63 @@ func (s *authImpl) RenewSession(ctx context.Context, req types.AuthRenewSessionR
 		return res, err
 	}
 
+	if user.IsBanned || user.IsSuspensionActive(time.Now()) {
+		if err = s.sessionRepo.Delete(ctx, sessionKey); err != nil {
+			return res, err
+		}
+
+		if user.IsBanned {
+			return res, errors.New(types.AppErr{Code: http.StatusUnauthorized, Message: "your account is banned"})
+		}
+
+		return res, errors.New(types.AppErr{Code: http.StatusUnauthorized, Message: "your account is suspended"})
+	}
+
 	newSessionID, err := uuid.NewV7()
 	if err != nil {
 		return res, errors.New(err)
 	}
 
+	// the session keeps its creation time and device name, a session created before the metadata was stored starts now
+	session, err := s.sessionRepo.FindMetadata(ctx, userId, sessionKey)
+	if err != nil && !errors.Is(err, types.ErrNoData) {
+		return res, err
+	}
+
+	timeNow := time.Now()
+	if session.CreatedAt.IsZero() {
+		session.CreatedAt = timeNow
+	}
+
+	if req.DeviceName != "" {
+		session.DeviceName = req.DeviceName
+	}
+
+	// a refresh token issued before the families were introduced starts a new family
+	if claims.FamilyID != nil {
+		session.FamilyID = *claims.FamilyID
+	} else if session.FamilyID == uuid.Nil {
+		session.FamilyID = claims.ID
+	}
+
+	session.ID = newSessionID
+	session.UserID = user.ID
+	session.IPAddress = req.IPAddress
+	session.UserAgent = req.UserAgent
+	session.LastUsedAt = timeNow
+
 	newSessionKey := types.GetSessionKey(newSessionID.String())
-	if err = s.sessionRepo.RenewAndDelete(ctx, sessionKey, newSessionKey, user.ID.String(), s.config.JWT.RefreshTokenExpiration); err != nil {
+	renewed, err := s.sessionRepo.RenewAndDelete(ctx, sessionKey, newSessionKey, session, s.config.JWT.RefreshTokenExpiration)
+	if err != nil {
 		return res, err
+	} else if !renewed {
+		// another request renewed the session with the same refresh token first
+		return res, s.revokeReusedFamily(ctx, claims, req.SessionMetadataReq)
+	}
+
+	// the device keeps receiving pushes without registering its token again
+	if err = s.notificationSvc.MoveToken(ctx, user.ID, claims.ID, newSessionID); err != nil {
+		log.Error().Stack().Err(err).Send()
 	}
 
 	incompleteRegistration, err := s.pendingRegistrationRepo.IsExists(ctx, types.GetPendingRegistrationKey(userId))
@@ -396,7 +493,7 @@ func (s *authImpl) RenewSession(ctx context.Context, req types.AuthRenewSessionR
 		authUser.IncompleteRegistration = &incompleteRegistration
 	}
 
-	t, err := s.GenerateToken(authUser)
+	t, err := s.GenerateToken(authUser, session.FamilyID)
 	if err != nil {
 		return res, errors.New(err)
 	}
@@ -407,6 +504,54 @@ func (s *authImpl) RenewSession(ctx context.Context, req types.AuthRenewSessionR
 	return res, nil
 }
 
+// revokeReusedFamily is called when the session of the refresh token no longer exists. A refresh token of a renewed session
+// has been copied, every session of the family is revoked because the current one may belong to whoever copied it
+func (s *authImpl) revokeReusedFamily(ctx context.Context, claims *types.AuthJwtCustomClaims, metadata types.SessionMetadataReq) error {
+	if claims.FamilyID == nil {
+		return types.AuthErrSessionRevoked
+	}
+
+	renewed, err := s.sessionRepo.IsRenewed(ctx, claims.FamilyID.String(), claims.ID.String())
+	if err != nil {
+		return err
+	} else if !renewed {
+		return types.AuthErrSessionRevoked
+	}
+
+	sessions, err := s.sessionRepo.FindAllByUserID(ctx, claims.Subject.String())
+	if err != nil {
+		return err
+	}
+
+	revokedSessionIDs := []string{}
+	for _, session := range sessions {
+		if session.FamilyID != *claims.FamilyID {
+			continue
+		}
+
+		if err = s.sessionRepo.Delete(ctx, types.GetSessionKey(session.ID.String())); err != nil {
+			return err
+		}
+
+		if err = s.notificationSvc.RemoveToken(ctx, claims.Subject, session.ID); err != nil {
+			log.Error().Stack().Err(err).Send()
+		}
+
+		revokedSessionIDs = append(revokedSessionIDs, session.ID.String())
+	}
+
+	log.Warn().
+		Str("user_id", claims.Subject.String()).
+		Str("family_id", claims.FamilyID.String()).
+		Str("reused_session_id", claims.ID.String()).
+		Strs("revoked_session_ids", revokedSessionIDs).
+		Str("ip_address", metadata.IPAddress).
+		Str("user_agent", metadata.UserAgent).
+		Msg("refresh token reuse detected, the session family has been revoked")
+
+	return types.AuthErrRefreshTokenReused
+}
+
 func (s *authImpl) RevokeSession(ctx context.Context, req types.AuthRevokeSessionReq) error {
 	err := req.Validate()
 	if err != nil {
@@ -425,5 +570,563 @@ func (s *authImpl) RevokeSession(ctx context.Context, req types.AuthRevokeSessio
 		return err
 	}
 
+	err = s.notificationSvc.RemoveToken(ctx, req.AuthUser.ID, req.AuthUser.SessionID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *authImpl) ConsumerRegister(ctx context.Context, req types.AuthRegisterReq) error {
+	return s.register(ctx, req, types.UserRoleConsumer)
+}
+
+func (s *authImpl) ProviderRegister(ctx context.Context, req types.AuthRegisterReq) error {
+	return s.register(ctx, req, types.UserRoleServiceProvider)
+}
+
+// register creates an unverified local account, the user logs in once the email sent here is verified
+func (s *authImpl) register(ctx context.Context, req types.AuthRegisterReq, role types.UserRole) error {
+	if err := req.ValidateAndNormalize(); err != nil {
+		return err
+	}
+
+	_, err := s.userRepo.FindByEmail(ctx, req.Email)
+	if err == nil {
+		return types.AuthErrEmailRegistered
+	} else if !errors.Is(err, types.ErrNoData) {
+		return err
+	}
+
+	password, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return errors.New(err)
+	}
+
+	id, err := uuid.NewV7()
+	if err != nil {
+		return errors.New(err)
+	}
+
+	user := types.User{
+		ID:           id,
+		Role:         role,
+		Name:         req.Name,
+		Email:        req.Email,
+		Password:     null.StringFrom(string(password)),
+		AuthProvider: types.AuthProviderLocal,
+	}
+
+	tx, err := s.beginMainDBTx(ctx, nil)
+	if err != nil {
+		return errors.New(err)
+	}
+
+	defer tx.Rollback()
+
+	// the email can be registered by a concurrent request after it was checked above
+	if err = s.userRepo.CreateTx(ctx, tx, user); errors.Is(err, types.ErrDuplicateData) {
+		return types.AuthErrEmailRegistered
+	} else if err != nil {
+		return err
+	}
+
+	if role == types.UserRoleServiceProvider {
+		key := types.GetPendingRegistrationKey(user.ID.String())
+		if err = s.pendingRegistrationRepo.Set(ctx, key, user.ID); err != nil {
+			return err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return errors.New(err)
+	}
+
+	// the user can request the email again once the cooldown ends when it fails to be sent
+	cooldownKey := types.GetAuthEmailCooldownKey(types.AuthTokenPurposeEmailVerification, user.Email)
+	if _, err = s.authTokenRepo.AcquireCooldown(ctx, cooldownKey, s.config.Auth.EmailResendInterval); err != nil {
+		log.Error().Stack().Err(err).Send()
+	}
+
+	if err = s.sendEmailToken(ctx, user.ID, types.AuthTokenPurposeEmailVerification); err != nil {
+		log.Error().Stack().Err(err).Send()
+	}
+
+	return nil
+}
+
+func (s *authImpl) VerifyEmail(ctx context.Context, req types.AuthVerifyEmailReq) error {
+	if err := req.Validate(); err != nil {
+		return err
+	}
+
+	userID, err := s.consumeEmailToken(ctx, req.Token, types.AuthTokenPurposeEmailVerification)
+	if err != nil {
+		return err
+	}
+
+	return s.userRepo.MarkEmailAsVerified(ctx, userID, time.Now())
+}
+
+// ResendVerificationEmail does not tell whether the email is registered, nothing is sent to an unknown or a verified email
+func (s *authImpl) ResendVerificationEmail(ctx context.Context, req types.AuthEmailReq) error {
+	if err := req.ValidateAndNormalize(); err != nil {
+		return err
+	}
+
+	user, err := s.findByEmailAfterCooldown(ctx, req.Email, types.AuthTokenPurposeEmailVerification)
+	if errors.Is(err, types.ErrNoData) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	if user.EmailVerifiedAt.Valid {
+		return nil
+	}
+
+	return s.sendEmailToken(ctx, user.ID, types.AuthTokenPurposeEmailVerification)
+}
+
+// ForgotPassword does not tell whether the email is registered, nothing is sent to an unknown email
+func (s *authImpl) ForgotPassword(ctx context.Context, req types.AuthEmailReq) error {
+	if err := req.ValidateAndNormalize(); err != nil {
+		return err
+	}
+
+	user, err := s.findByEmailAfterCooldown(ctx, req.Email, types.AuthTokenPurposePasswordReset)
+	if errors.Is(err, types.ErrNoData) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	return s.sendEmailToken(ctx, user.ID, types.AuthTokenPurposePasswordReset)
+}
+
+// ResetPassword logs the user out of every device, the reset link also proves the user owns the email
+func (s *authImpl) ResetPassword(ctx context.Context, req types.AuthResetPasswordReq) error {
+	if err := req.Validate(); err != nil {
+		return err
+	}
+
+	userID, err := s.consumeEmailToken(ctx, req.Token, types.AuthTokenPurposePasswordReset)
+	if err != nil {
+		return err
+	}
+
+	password, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return errors.New(err)
+	}
+
+	if err = s.userRepo.UpdatePassword(ctx, userID, string(password)); err != nil {
+		return err
+	}
+
+	if err = s.userRepo.MarkEmailAsVerified(ctx, userID, time.Now()); err != nil {
+		return err
+	}
+
+	return s.sessionRepo.DeleteAllByUserID(ctx, userID.String())
+}
+
+// ChangePassword keeps the current session and revokes the other sessions of the user
+func (s *authImpl) ChangePassword(ctx context.Context, req types.AuthChangePasswordReq) error {
+	if err := req.Validate(); err != nil {
+		return err
+	}
+
+	session, err := s.sessionRepo.FindMetadata(ctx, req.AuthUser.ID.String(), types.GetSessionKey(req.AuthUser.SessionID.String()))
+	if errors.Is(err, types.ErrNoData) {
+		return types.AuthErrSessionRevoked
+	} else if err != nil {
+		return err
+	}
+
+	user, err := s.userRepo.FindByID(ctx, req.AuthUser.ID)
+	if err != nil {
+		return err
+	}
+
+	if user.Password.Valid {
+		if err = bcrypt.CompareHashAndPassword([]byte(user.Password.String), []byte(req.CurrentPassword)); err != nil {
+			return errors.New(types.AppErr{Code: http.StatusBadRequest, Message: "current password is incorrect"})
+		}
+	} else if time.Since(session.CreatedAt) > types.AuthRecentLoginDuration {
+		// a stolen access token must not be enough to take over a google account by setting its first password,
+		// the created at of a session is kept when it is renewed so it is the time of the login
+		return errors.New(types.AppErr{Code: http.StatusForbidden, Message: "please log in again before setting a password"})
+	}
+
+	password, err := bcrypt.GenerateFromPassword([]byte(req.NewPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return errors.New(err)
+	}
+
+	if err = s.userRepo.UpdatePassword(ctx, user.ID, string(password)); err != nil {
+		return err
+	}
+
+	return s.revokeOtherSessions(ctx, req.AuthUser)
+}
+
+func (s *authImpl) GetSessions(ctx context.Context, req types.SessionGetAllReq) ([]types.SessionGetAllRes, error) {
+	res := []types.SessionGetAllRes{}
+
+	if err := req.Validate(); err != nil {
+		return res, err
+	}
+
+	sessions, err := s.sessionRepo.FindAllByUserID(ctx, req.AuthUser.ID.String())
+	if err != nil {
+		return res, err
+	}
+
+	for _, session := range sessions {
+		res = append(res, types.SessionGetAllRes{
+			ID:         session.ID,
+			DeviceName: session.DeviceName,
+			IPAddress:  session.IPAddress,
+			UserAgent:  session.UserAgent,
+			IsCurrent:  session.ID == req.AuthUser.SessionID,
+			CreatedAt:  session.CreatedAt,
+			LastUsedAt: session.LastUsedAt,
+		})
+	}
+
+	return res, nil
+}
+
+// RevokeSessionByID logs out one of the devices of the user, the push token of the device is removed as well
+func (s *authImpl) RevokeSessionByID(ctx context.Context, req types.SessionRevokeReq) error {
+	if err := req.Validate(); err != nil {
+		return err
+	}
+
+	sessionKey := types.GetSessionKey(req.ID.String())
+	userID, err := s.sessionRepo.Find(ctx, sessionKey)
+	if errors.Is(err, types.ErrNoData) || (err == nil && userID != req.AuthUser.ID.String()) {
+		return errors.New(types.AppErr{Code: http.StatusNotFound, Message: "session not found"})
+	} else if err != nil {
+		return err
+	}
+
+	if err = s.sessionRepo.Delete(ctx, sessionKey); err != nil {
+		return err
+	}
+
+	return s.notificationSvc.RemoveToken(ctx, req.AuthUser.ID, req.ID)
+}
+
+func (s *authImpl) RevokeOtherSessions(ctx context.Context, req types.SessionRevokeOthersReq) error {
+	if err := req.Validate(); err != nil {
+		return err
+	}
+
+	return s.revokeOtherSessions(ctx, req.AuthUser)
+}
+
+// revokeOtherSessions keeps the current session, the revoked devices stop receiving pushes
+func (s *authImpl) revokeOtherSessions(ctx context.Context, authUser types.AuthUser) error {
+	sessions, err := s.sessionRepo.FindAllByUserID(ctx, authUser.ID.String())
+	if err != nil {
+		return err
+	}
+
+	sessionKey := types.GetSessionKey(authUser.SessionID.String())
+	if err = s.sessionRepo.DeleteOthersByUserID(ctx, authUser.ID.String(), sessionKey); err != nil {
+		return err
+	}
+
+	for _, session := range sessions {
+		if session.ID == authUser.SessionID {
+			continue
+		}
+
+		if err := s.notificationSvc.RemoveToken(ctx, authUser.ID, session.ID); err != nil {
+			log.Error().Stack().Err(err).Send()
+		}
+	}
+
 	return nil
 }
+
+// newSession starts a new family, the family id is the id of the first session
+func newSession(ID, userID uuid.UUID, metadata types.SessionMetadataReq) types.Session {
+	timeNow := time.Now()
+
+	return types.Session{
+		ID:         ID,
+		UserID:     userID,
+		FamilyID:   ID,
+		DeviceName: metadata.DeviceName,
+		IPAddress:  metadata.IPAddress,
+		UserAgent:  metadata.UserAgent,
+		CreatedAt:  timeNow,
+		LastUsedAt: timeNow,
+	}
+}
+
+// findByEmailAfterCooldown starts the cooldown before looking up the user, an unknown email is rate limited the same way
+func (s *authImpl) findByEmailAfterCooldown(ctx context.Context, email string, purpose types.AuthTokenPurpose) (types.User, error) {
+	ok, err := s.authTokenRepo.AcquireCooldown(ctx, types.GetAuthEmailCooldownKey(purpose, email), s.config.Auth.EmailResendInterval)
+	if err != nil {
+		return types.User{}, err
+	} else if !ok {
+		return types.User{}, types.AuthErrEmailCooldown
+	}
+
+	return s.userRepo.FindByEmail(ctx, email)
+}
+
+// sendEmailToken replaces the previous token of the purpose, only the token in the last email can be used
+func (s *authImpl) sendEmailToken(ctx context.Context, userID uuid.UUID, purpose types.AuthTokenPurpose) error {
+	expiration := s.config.Auth.EmailVerificationExpiration
+	if purpose == types.AuthTokenPurposePasswordReset {
+		expiration = s.config.Auth.PasswordResetExpiration
+	}
+
+	token, _, err := s.signAuthToken(ctx, userID, purpose, expiration)
+	if err != nil {
+		return err
+	}
+
+	return s.notificationSvc.QueueAuthEmail(ctx, types.NotificationSendAuthEmailReq{
+		UserID:  userID,
+		Purpose: purpose,
+		Token:   token,
+	})
+}
+
+// consumeEmailToken returns the user of the token, the token can not be used again afterwards
+func (s *authImpl) consumeEmailToken(ctx context.Context, signedToken string, purpose types.AuthTokenPurpose) (uuid.UUID, error) {
+	claims, ok := s.parseAuthToken(signedToken, purpose)
+	if !ok {
+		return uuid.Nil, types.AuthErrInvalidEmailToken
+	}
+
+	ok, err := s.authTokenRepo.Consume(ctx, types.GetAuthTokenKey(purpose, claims.Subject.String()), claims.ID)
+	if err != nil {
+		return uuid.Nil, err
+	} else if !ok {
+		return uuid.Nil, types.AuthErrInvalidEmailToken
+	}
+
+	return claims.Subject, nil
+}
+
+// signAuthToken replaces the previous token of the purpose and the user, only the last token can be consumed
+func (s *authImpl) signAuthToken(ctx context.Context, userID uuid.UUID, purpose types.AuthTokenPurpose, expiration time.Duration) (string, time.Time, error) {
+	tokenID, err := uuid.NewRandom()
+	if err != nil {
+		return "", time.Time{}, errors.New(err)
+	}
+
+	timeNow := time.Now()
+	expiresAt := timeNow.Add(expiration)
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, types.AuthTokenClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    s.config.JWT.Issuer,
+			IssuedAt:  jwt.NewNumericDate(timeNow),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+		ID:      tokenID,
+		Subject: userID,
+		Purpose: purpose,
+	}).SignedString([]byte(s.config.Auth.TokenSigningKey))
+	if err != nil {
+		return "", time.Time{}, errors.New(err)
+	}
+
+	if err = s.authTokenRepo.Set(ctx, types.GetAuthTokenKey(purpose, userID.String()), tokenID, expiration); err != nil {
+		return "", time.Time{}, err
+	}
+
+	return token, expiresAt, nil
+}
+
+// parseAuthToken only checks the signature, the expiration and the purpose, the token may have been consumed or replaced
+func (s *authImpl) parseAuthToken(signedToken string, purpose types.AuthTokenPurpose) (*types.AuthTokenClaims, bool) {
+	claims := &types.AuthTokenClaims{}
+	token, err := jwt.ParseWithClaims(signedToken, claims, func(token *jwt.Token) (interface{}, error) {
+		return []byte(s.config.Auth.TokenSigningKey), nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Name}))
+	if err != nil || !token.Valid || claims.Purpose != purpose {
+		return nil, false
+	}
+
+	return claims, true
+}
+
+// twoFactorChallenge returns nil when the login does not need a second step,
+// a role that requires two-factor authentication gets a challenge to enroll with when it has not been enabled
+func (s *authImpl) twoFactorChallenge(ctx context.Context, user types.User) (*types.AuthTwoFactorChallengeRes, error) {
+	policy := types.GetAuthTwoFactorPolicy(user.Role)
+	if policy == types.AuthTwoFactorPolicyDisabled {
+		return nil, nil
+	}
+
+	enabled, err := s.twoFactorSvc.IsEnabled(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	} else if !enabled && policy != types.AuthTwoFactorPolicyRequired {
+		return nil, nil
+	}
+
+	token, expiresAt, err := s.signAuthToken(ctx, user.ID, types.AuthTokenPurposeTwoFactor, s.config.Auth.TwoFactorChallengeExpiration)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.AuthTwoFactorChallengeRes{
+		Token:              token,
+		ExpiresAt:          expiresAt,
+		EnrollmentRequired: !enabled,
+	}, nil
+}
+
+// VerifyTwoFactor is the second step of the login, the challenge is consumed only after the code is accepted so a typo does not end the login
+func (s *authImpl) VerifyTwoFactor(ctx context.Context, req types.AuthVerifyTwoFactorReq) (types.AuthVerifyTwoFactorRes, error) {
+	res := types.AuthVerifyTwoFactorRes{}
+
+	if err := req.Validate(); err != nil {
+		return res, err
+	}
+
+	claims, ok := s.parseAuthToken(req.ChallengeToken, types.AuthTokenPurposeTwoFactor)
+	if !ok {
+		return res, types.AuthErrInvalidTwoFactorToken
+	}
+
+	challengeKey := types.GetAuthTokenKey(types.AuthTokenPurposeTwoFactor, claims.Subject.String())
+	attempts, err := s.authTokenRepo.IncrAttempts(ctx, types.GetAuthTwoFactorAttemptsKey(claims.ID.String()), s.config.Auth.TwoFactorChallengeExpiration)
+	if err != nil {
+		return res, err
+	} else if attempts > types.AuthTwoFactorMaxAttempts {
+		if _, err = s.authTokenRepo.Consume(ctx, challengeKey, claims.ID); err != nil {
+			log.Error().Stack().Err(err).Send()
+		}
+
+		return res, types.AuthErrTwoFactorAttempts
+	}
+
+	user, err := s.userRepo.FindByID(ctx, claims.Subject)
+	if errors.Is(err, types.ErrNoData) {
+		return res, types.AuthErrInvalidTwoFactorToken
+	} else if err != nil {
+		return res, err
+	}
+
+	if user.IsSuspensionActive(time.Now()) {
+		return res, errors.New(types.AppErr{Code: http.StatusUnauthorized, Message: "your account is suspended"})
+	} else if user.IsBanned {
+		return res, errors.New(types.AppErr{Code: http.StatusUnauthorized, Message: "your account is banned"})
+	}
+
+	// the challenge is consumed before the code is checked, a superseded challenge can not enable the pending secret
+	// or use up the code. A wrong code puts the challenge back for the next attempt
+	ok, err = s.authTokenRepo.Consume(ctx, challengeKey, claims.ID)
+	if err != nil {
+		return res, err
+	} else if !ok {
+		return res, types.AuthErrInvalidTwoFactorToken
+	}
+
+	recoveryCodes, err := s.twoFactorSvc.Verify(ctx, user.ID, req.Code)
+	if errors.Is(err, types.TwoFactorErrInvalidCode) {
+		s.restoreTwoFactorChallenge(ctx, challengeKey, claims)
+		return res, err
+	} else if err != nil {
+		return res, err
+	}
+
+	t, err := s.createSession(ctx, user, req.SessionMetadataReq)
+	if err != nil {
+		return res, err
+	}
+
+	res.AccessToken = t.AccessToken
+	res.RefreshToken = t.RefreshToken
+	res.RecoveryCodes = recoveryCodes
+
+	return res, nil
+}
+
+// restoreTwoFactorChallenge puts the challenge back for the time its token has left, a failure only makes the user log in again
+func (s *authImpl) restoreTwoFactorChallenge(ctx context.Context, key string, claims *types.AuthTokenClaims) {
+	if claims.ExpiresAt == nil {
+		return
+	}
+
+	expiration := time.Until(claims.ExpiresAt.Time)
+	if expiration <= 0 {
+		return
+	}
+
+	if _, err := s.authTokenRepo.Restore(ctx, key, claims.ID, expiration); err != nil {
+		log.Error().Stack().Err(err).Send()
+	}
+}
+
+// EnrollTwoFactor sets up the authenticator app of a user whose role requires two-factor authentication before the login completes,
+// the enrollment is confirmed by VerifyTwoFactor with the same challenge
+func (s *authImpl) EnrollTwoFactor(ctx context.Context, req types.AuthEnrollTwoFactorReq) (types.TwoFactorSetupRes, error) {
+	if err := req.Validate(); err != nil {
+		return types.TwoFactorSetupRes{}, err
+	}
+
+	claims, ok := s.parseAuthToken(req.ChallengeToken, types.AuthTokenPurposeTwoFactor)
+	if !ok {
+		return types.TwoFactorSetupRes{}, types.AuthErrInvalidTwoFactorToken
+	}
+
+	// a signed challenge stays valid until it expires, only the last one sent that has not been consumed can enroll
+	challengeKey := types.GetAuthTokenKey(types.AuthTokenPurposeTwoFactor, claims.Subject.String())
+	ok, err := s.authTokenRepo.IsCurrent(ctx, challengeKey, claims.ID)
+	if err != nil {
+		return types.TwoFactorSetupRes{}, err
+	} else if !ok {
+		return types.TwoFactorSetupRes{}, types.AuthErrInvalidTwoFactorToken
+	}
+
+	return s.twoFactorSvc.SetupForUser(ctx, claims.Subject)
+}
+
+// createSession issues the tokens of a login that passed every step
+func (s *authImpl) createSession(ctx context.Context, user types.User, metadata types.SessionMetadataReq) (types.AuthGenerateToken, error) {
+	sessionId, err := uuid.NewRandom()
+	if err != nil {
+		return types.AuthGenerateToken{}, errors.New(err)
+	}
+
+	session := newSession(sessionId, user.ID, metadata)
+	err = s.sessionRepo.Set(ctx, types.GetSessionKey(sessionId.String()), session, s.config.JWT.RefreshTokenExpiration)
+	if err != nil {
+		return types.AuthGenerateToken{}, err
+	}
+
+	authUser := types.AuthUser{
+		ID:        user.ID,
+		SessionID: sessionId,
+		Role:      user.Role,
+		Name:      user.Name,
+	}
+
+	if user.Role == types.UserRoleServiceProvider {
+		incompleteRegistration, err := s.pendingRegistrationRepo.IsExists(ctx, types.GetPendingRegistrationKey(user.ID.String()))
+		if err != nil {
+			return types.AuthGenerateToken{}, err
+		}
+
+		authUser.IncompleteRegistration = &incompleteRegistration
+	}
+
+	t, err := s.GenerateToken(authUser, session.FamilyID)
+	if err != nil {
+		return types.AuthGenerateToken{}, errors.New(err)
+	}
+
+	return t, nil
+}
@@ -0,0 +1,84 @@
+package service_test
+
+import (
+	"context"
+	"kelarin/internal/config"
+	repoMock "kelarin/internal/mocks/repository"
+	serviceMock "kelarin/internal/mocks/service"
+	"kelarin/internal/service"
+	"kelarin/internal/types"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAuthServiceVerifyTwoFactor(t *testing.T) {
+	ctx := context.Background()
+
+	cfg := &config.Config{Auth: config.AuthConfig{
+		TokenSigningKey:              "signing-key",
+		TwoFactorChallengeExpiration: 5 * time.Minute,
+	}}
+
+	user := types.User{ID: uuid.New(), Role: types.UserRoleAdmin}
+	challengeKey := types.GetAuthTokenKey(types.AuthTokenPurposeTwoFactor, user.ID.String())
+
+	newChallenge := func(t *testing.T) (string, uuid.UUID) {
+		tokenID := uuid.New()
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, types.AuthTokenClaims{
+			RegisteredClaims: jwt.RegisteredClaims{
+				ExpiresAt: jwt.NewNumericDate(time.Now().Add(cfg.Auth.TwoFactorChallengeExpiration)),
+			},
+			ID:      tokenID,
+			Subject: user.ID,
+			Purpose: types.AuthTokenPurposeTwoFactor,
+		}).SignedString([]byte(cfg.Auth.TokenSigningKey))
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when signing the challenge", err)
+		}
+
+		return token, tokenID
+	}
+
+	newService := func(t *testing.T) (service.Auth, *repoMock.AuthToken, *repoMock.User, *serviceMock.TwoFactor) {
+		authTokenRepo := repoMock.NewAuthToken(t)
+		userRepo := repoMock.NewUser(t)
+		twoFactorSvc := serviceMock.NewTwoFactor(t)
+
+		return service.NewAuth(cfg, nil, repoMock.NewSession(t), userRepo, nil, authTokenRepo, serviceMock.NewNotification(t), twoFactorSvc), authTokenRepo, userRepo, twoFactorSvc
+	}
+
+	t.Run("Test VerifyTwoFactor rejects a superseded challenge before checking the code", func(t *testing.T) {
+		authSvc, authTokenRepo, userRepo, _ := newService(t)
+		token, tokenID := newChallenge(t)
+
+		authTokenRepo.Mock.On("IncrAttempts", ctx, types.GetAuthTwoFactorAttemptsKey(tokenID.String()), cfg.Auth.TwoFactorChallengeExpiration).Return(int64(1), nil)
+		userRepo.Mock.On("FindByID", ctx, user.ID).Return(user, nil)
+		authTokenRepo.Mock.On("Consume", ctx, challengeKey, tokenID).Return(false, nil)
+
+		_, err := authSvc.VerifyTwoFactor(ctx, types.AuthVerifyTwoFactorReq{ChallengeToken: token, Code: "123456"})
+
+		assert.ErrorIs(t, err, types.AuthErrInvalidTwoFactorToken)
+	})
+
+	t.Run("Test VerifyTwoFactor puts the challenge back after a wrong code", func(t *testing.T) {
+		authSvc, authTokenRepo, userRepo, twoFactorSvc := newService(t)
+		token, tokenID := newChallenge(t)
+
+		authTokenRepo.Mock.On("IncrAttempts", ctx, types.GetAuthTwoFactorAttemptsKey(tokenID.String()), cfg.Auth.TwoFactorChallengeExpiration).Return(int64(1), nil)
+		userRepo.Mock.On("FindByID", ctx, user.ID).Return(user, nil)
+		authTokenRepo.Mock.On("Consume", ctx, challengeKey, tokenID).Return(true, nil)
+		twoFactorSvc.Mock.On("Verify", ctx, user.ID, "123456").Return(nil, types.TwoFactorErrInvalidCode)
+		authTokenRepo.Mock.On("Restore", ctx, challengeKey, tokenID, mock.MatchedBy(func(expiration time.Duration) bool {
+			return expiration > 0 && expiration <= cfg.Auth.TwoFactorChallengeExpiration
+		})).Return(true, nil)
+
+		_, err := authSvc.VerifyTwoFactor(ctx, types.AuthVerifyTwoFactorReq{ChallengeToken: token, Code: "123456"})
+
+		assert.ErrorIs(t, err, types.TwoFactorErrInvalidCode)
+	})
+}
@@ -7,7 +7,9 @@ import (
 	"kelarin/internal/repository"
 	"kelarin/internal/types"
 	dbUtil "kelarin/internal/utils/dbutil"
+	ws "kelarin/internal/utils/websocket"
 	"net/http"
+	"slices"
 	"time"
 
 	"github.com/go-errors/errors"
@@ -15,18 +17,21 @@ import (
 	"github.com/gorilla/websocket"
 	"github.com/rs/zerolog/log"
 	"github.com/samber/lo"
+	"github.com/volatiletech/null/v9"
 )
 
 type Chat interface {
 	HandleInboundMessage(client *types.WsClient)
 	CreateChatRoom(ctx context.Context, req types.ChatChatRoomCreateReq) (types.ChatChatRoomCreateRes, error)
 	MarkReceivedAsSeen(ctx context.Context, req types.ChatMarkReceivedAsSeenReq) error
+	PublishPresence(ctx context.Context, userID uuid.UUID, online bool) error
 
 	ConsumerGetAll(ctx context.Context, req types.ChatGetAllReq) ([]types.ChatConsumerGetAllRes, error)
-	ConsumerGetByRoomID(ctx context.Context, req types.ChatGetByRoomIDReq) (types.ChatConsumerGetByRoomIDRes, error)
+	ConsumerGetByRoomID(ctx context.Context, req types.ChatGetByRoomIDReq) (types.ChatConsumerGetByRoomIDRes, types.ChatMessageCursorPaginationRes, error)
 
 	ProviderGetAll(ctx context.Context, req types.ChatGetAllReq) ([]types.ChatProviderGetAllRes, error)
-	ProviderGetByRoomID(ctx context.Context, req types.ChatGetByRoomIDReq) (types.ChatProviderGetByRoomIDRes, error)
+	ProviderGetByRoomID(ctx context.Context, req types.ChatGetByRoomIDReq) (types.ChatProviderGetByRoomIDRes, types.ChatMessageCursorPaginationRes, error)
+	Search(ctx context.Context, req types.ChatSearchReq) ([]types.ChatSearchRes, types.CursorPaginationRes, error)
 }
 
 type chatImpl struct {
@@ -36,7 +41,8 @@ type chatImpl struct {
 	chatRoomRepo        repository.ChatRoom
 	chatRoomUserRepo    repository.ChatRoomUser
 	chatMessageRepo     repository.ChatMessage
-	hub                 *types.WsHub
+	userPresenceRepo    repository.UserPresence
+	hub                 ws.Hub
 	offerRepo           repository.Offer
 	serviceProviderRepo repository.ServiceProvider
 	fileSvc             File
@@ -51,7 +57,8 @@ func NewChat(
 	chatRoomRepo repository.ChatRoom,
 	chatRoomUserRepo repository.ChatRoomUser,
 	chatMessageRepo repository.ChatMessage,
-	hub *types.WsHub,
+	userPresenceRepo repository.UserPresence,
+	hub ws.Hub,
 	offerRepo repository.Offer,
 	serviceProviderRepo repository.ServiceProvider,
 	fileSvc File,
@@ -65,6 +72,7 @@ func NewChat(
 		chatRoomRepo:        chatRoomRepo,
 		chatRoomUserRepo:    chatRoomUserRepo,
 		chatMessageRepo:     chatMessageRepo,
+		userPresenceRepo:    userPresenceRepo,
 		hub:                 hub,
 		offerRepo:           offerRepo,
 		serviceProviderRepo: serviceProviderRepo,
@@ -75,9 +83,18 @@ func NewChat(
 }
 
 func (s *chatImpl) HandleInboundMessage(client *types.WsClient) {
-	for {
-		client.Lock()
+	// messages are sent one by one outside of the read loop, so uploading an attachment does not stop the client from
+	// reading its pongs and the messages keep the order they were sent in
+	outgoing := make(chan types.ChatSendMessageReq, types.ChatSendQueueSize)
+	defer close(outgoing)
+
+	go func() {
+		for m := range outgoing {
+			s.sendMessage(client, m)
+		}
+	}()
 
+	for {
 		wsRes := types.WsResponse{
 			Success: false,
 			Type:    types.WsResponseTypeServer,
@@ -91,27 +108,28 @@ func (s *chatImpl) HandleInboundMessage(client *types.WsClient) {
 
 		_, msg, err := client.Con.ReadMessage()
 		if err != nil {
-			log.Error().Stack().Err(err).Send()
+			// a missed pong fails the read with a timeout, the client reconnects and resumes from its last seen message
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+				log.Error().Stack().Err(err).Send()
+			}
+
 			wsRes.Message = "error reading message"
 
 			res, err := wsRes.Parse()
 			if err != nil {
 				log.Error().Stack().Err(err).Send()
-				client.Con.Close()
-				client.Unlock()
+				client.Close()
 				return
 			}
 
-			client.Con.WriteMessage(websocket.BinaryMessage, res)
-			client.Con.Close()
-			client.Unlock()
+			client.Send(res)
+			client.Close()
 
 			return
 		}
 
-		client.Unlock()
-
-		if err := json.Unmarshal(msg, &m); err != nil {
+		var wsReq types.WsRequest
+		if err := json.Unmarshal(msg, &wsReq); err != nil {
 			log.Error().Stack().Err(err).Send()
 
 			wsRes.Code = types.WsResponseCodeClientError
@@ -120,187 +138,212 @@ func (s *chatImpl) HandleInboundMessage(client *types.WsClient) {
 			res, err := wsRes.Parse()
 			if err != nil {
 				log.Error().Stack().Err(err).Send()
-				client.Con.Close()
+				client.Close()
 				return
 			}
 
-			client.Con.WriteMessage(websocket.BinaryMessage, res)
+			client.Send(res)
 			continue
 		}
 
-		if err := m.Validate(); err != nil {
-			wsRes.Code = types.WsResponseCodeClientError
-			wsRes.Message = "error validating message"
-			wsRes.Errors = err
+		var handle func(client *types.WsClient, msg []byte) types.WsResponse
+		switch wsReq.Type {
+		case types.WsRequestTypeChatResume:
+			handle = s.resume
+		case types.WsRequestTypeChatTypingStarted:
+			handle = s.typingStarted
+		case types.WsRequestTypeChatTypingStopped:
+			handle = s.typingStopped
+		case types.WsRequestTypeChatPresence:
+			handle = s.presence
+		}
 
-			res, err := wsRes.Parse()
+		if handle != nil {
+			res, err := handle(client, msg).Parse()
 			if err != nil {
 				log.Error().Stack().Err(err).Send()
-				client.Con.Close()
+				client.Close()
 				return
 			}
 
-			client.Con.WriteMessage(websocket.BinaryMessage, res)
+			client.Send(res)
 			continue
 		}
 
-		wsRes.Metadata = types.ChatSendMessageResMetadata{
-			ID: m.ID,
-		}
+		if err := json.Unmarshal(msg, &m); err != nil {
+			log.Error().Stack().Err(err).Send()
 
-		recipientUserID := m.ServiceProviderID
+			wsRes.Code = types.WsResponseCodeClientError
+			wsRes.Message = "error parsing message"
 
-		if m.RoomID.Valid {
-			chatRoomUser, err := s.chatRoomUserRepo.FindByChatRoomIDAndUserID(client.Ctx, m.RoomID.UUID, client.AuthUser.ID)
-			if errors.Is(err, types.ErrNoData) {
-				wsRes.Code = types.WsResponseCodeChatRoomNotFound
-				wsRes.Message = "chat room not found"
-
-				res, err := wsRes.Parse()
-				if err != nil {
-					log.Error().Stack().Err(err).Send()
-					client.Con.Close()
-					return
-				}
-
-				client.Con.WriteMessage(websocket.BinaryMessage, res)
-				continue
-			} else if err != nil {
+			res, err := wsRes.Parse()
+			if err != nil {
 				log.Error().Stack().Err(err).Send()
-				client.Con.Close()
+				client.Close()
 				return
 			}
 
-			recipients, err := s.chatRoomUserRepo.FindRecipientByChatRoomIDs(client.Ctx, client.AuthUser.ID, uuid.UUIDs{chatRoomUser.ChatRoomID})
-			if err != nil {
-				log.Error().Stack().Err(err).Send()
+			client.Send(res)
+			continue
+		}
 
-				res, err := wsRes.Parse()
-				if err != nil {
-					log.Error().Stack().Err(err).Send()
-					client.Con.Close()
-					return
-				}
+		if err := m.Validate(); err != nil {
+			wsRes.Code = types.WsResponseCodeClientError
+			wsRes.Message = "error validating message"
+			wsRes.Errors = err
 
-				client.Con.WriteMessage(websocket.BinaryMessage, res)
+			res, err := wsRes.Parse()
+			if err != nil {
+				log.Error().Stack().Err(err).Send()
+				client.Close()
 				return
 			}
 
-			if len(recipients) == 0 {
-				wsRes.Code = types.WsResponseCodeInternalServerError
-				wsRes.Message = "recipient not found"
-
-				res, err := wsRes.Parse()
-				if err != nil {
-					log.Error().Stack().Err(err).Send()
-					client.Con.Close()
-					return
-				}
+			client.Send(res)
+			continue
+		}
 
-				client.Con.WriteMessage(websocket.BinaryMessage, res)
-				continue
+		select {
+		case outgoing <- m:
+		default:
+			wsRes.Code = types.WsResponseCodeClientError
+			wsRes.Message = "too many messages waiting to be sent"
+			wsRes.Metadata = types.ChatSendMessageResMetadata{
+				ID: m.ID,
 			}
 
-			recipientUserID = uuid.NullUUID{UUID: recipients[0].UserID, Valid: true}
-		} else if m.ServiceProviderID.Valid {
-			serviceProvider, err := s.serviceProviderRepo.FindByID(client.Ctx, m.ServiceProviderID.UUID)
-			if errors.Is(err, types.ErrNoData) {
-				wsRes.Code = types.WsResponseCodeChatRecipientNotFound
-				wsRes.Message = "service provider not found"
-
-				res, err := wsRes.Parse()
-				if err != nil {
-					log.Error().Stack().Err(err).Send()
-					client.Con.Close()
-					return
-				}
-
-				client.Con.WriteMessage(websocket.BinaryMessage, res)
-				continue
-			} else if err != nil {
+			res, err := wsRes.Parse()
+			if err != nil {
 				log.Error().Stack().Err(err).Send()
-				client.Con.Close()
+				client.Close()
 				return
 			}
 
-			recipientUserID = uuid.NullUUID{UUID: serviceProvider.UserID, Valid: true}
+			client.Send(res)
 		}
+	}
+}
 
-		if recipientUserID.UUID == client.AuthUser.ID {
-			wsRes.Code = types.WsResponseCodeClientError
-			wsRes.Message = "cannot send message to yourself"
+// sendMessage saves the message and delivers it to the recipient, an attachment is moved to s3 before the message is saved
+func (s *chatImpl) sendMessage(client *types.WsClient, m types.ChatSendMessageReq) {
+	wsRes := types.WsResponse{
+		Success: false,
+		Type:    types.WsResponseTypeServer,
+		Code:    types.WsResponseCodeInternalServerError,
+		Message: "internal server error",
+		Metadata: types.ChatSendMessageResMetadata{
+			ID: m.ID,
+		},
+	}
+
+	recipientUserID := m.ServiceProviderID
+
+	if m.RoomID.Valid {
+		chatRoomUser, err := s.chatRoomUserRepo.FindByChatRoomIDAndUserID(client.Ctx, m.RoomID.UUID, client.AuthUser.ID)
+		if errors.Is(err, types.ErrNoData) {
+			wsRes.Code = types.WsResponseCodeChatRoomNotFound
+			wsRes.Message = "chat room not found"
 
 			res, err := wsRes.Parse()
 			if err != nil {
 				log.Error().Stack().Err(err).Send()
-				client.Con.Close()
+				client.Close()
 				return
 			}
 
-			client.Con.WriteMessage(websocket.BinaryMessage, res)
-			continue
-		}
-
-		req := types.ChatSaveSentMessageReq{
-			AuthUser:        client.AuthUser,
-			RoomID:          m.RoomID,
-			RecipientUserID: recipientUserID,
-			Content:         m.Content,
-			ContentType:     m.ContentType,
+			client.Send(res)
+			return
+		} else if err != nil {
+			log.Error().Stack().Err(err).Send()
+			client.Close()
+			return
 		}
 
-		saveSentMsgRes, err := s.SaveSentMessage(client.Ctx, req)
+		recipients, err := s.chatRoomUserRepo.FindRecipientByChatRoomIDs(client.Ctx, client.AuthUser.ID, uuid.UUIDs{chatRoomUser.ChatRoomID})
 		if err != nil {
 			log.Error().Stack().Err(err).Send()
 
 			res, err := wsRes.Parse()
 			if err != nil {
 				log.Error().Stack().Err(err).Send()
-				client.Con.Close()
+				client.Close()
 				return
 			}
 
-			client.Con.WriteMessage(websocket.BinaryMessage, res)
-			continue
+			client.Send(res)
+			return
 		}
 
-		targetClient, ok := s.hub.Clients[recipientUserID.UUID.String()]
-		if !ok {
-			wsRes.Success = true
-			wsRes.Code = types.WsResponseCodeChatRecipientOffline
-			wsRes.Message = "recipient is offline"
+		if len(recipients) == 0 {
+			wsRes.Code = types.WsResponseCodeInternalServerError
+			wsRes.Message = "recipient not found"
 
 			res, err := wsRes.Parse()
 			if err != nil {
 				log.Error().Stack().Err(err).Send()
-				client.Con.Close()
+				client.Close()
 				return
 			}
 
-			client.Con.WriteMessage(websocket.BinaryMessage, res)
-			continue
+			client.Send(res)
+			return
 		}
 
-		if targetClient.Con == nil {
-			log.Error().Stack().Err(errors.New("target client connection is nil")).Send()
+		recipientUserID = uuid.NullUUID{UUID: recipients[0].UserID, Valid: true}
+	} else if m.ServiceProviderID.Valid {
+		serviceProvider, err := s.serviceProviderRepo.FindByID(client.Ctx, m.ServiceProviderID.UUID)
+		if errors.Is(err, types.ErrNoData) {
+			wsRes.Code = types.WsResponseCodeChatRecipientNotFound
+			wsRes.Message = "service provider not found"
 
 			res, err := wsRes.Parse()
 			if err != nil {
 				log.Error().Stack().Err(err).Send()
-				client.Con.Close()
+				client.Close()
 				return
 			}
 
-			client.Con.WriteMessage(websocket.BinaryMessage, res)
-			continue
+			client.Send(res)
+			return
+		} else if err != nil {
+			log.Error().Stack().Err(err).Send()
+			client.Close()
+			return
+		}
+
+		recipientUserID = uuid.NullUUID{UUID: serviceProvider.UserID, Valid: true}
+	}
+
+	if recipientUserID.UUID == client.AuthUser.ID {
+		wsRes.Code = types.WsResponseCodeClientError
+		wsRes.Message = "cannot send message to yourself"
+
+		res, err := wsRes.Parse()
+		if err != nil {
+			log.Error().Stack().Err(err).Send()
+			client.Close()
+			return
 		}
 
-		// ping target client
-		if err := targetClient.Con.WriteMessage(websocket.PingMessage, nil); err != nil {
-			if websocket.IsCloseError(err, websocket.CloseNormalClosure) || websocket.IsCloseError(err, websocket.CloseGoingAway) {
-				wsRes.Success = true
-				wsRes.Message = "recipient is offline"
-				wsRes.Code = types.WsResponseCodeChatRecipientOffline
+		client.Send(res)
+		return
+	}
+
+	req := types.ChatSaveSentMessageReq{
+		AuthUser:        client.AuthUser,
+		RoomID:          m.RoomID,
+		RecipientUserID: recipientUserID,
+		Content:         m.Content,
+		ContentType:     m.ContentType,
+	}
+
+	if m.ContentType != types.ChatMessageContentTypeText {
+		attachment, err := s.fileSvc.UploadChatAttachment(client.Ctx, m.Content, m.ContentType)
+		if err != nil {
+			var appErr types.AppErr
+			if errors.As(err, &appErr) {
+				wsRes.Code = types.WsResponseCodeClientError
+				wsRes.Message = appErr.Message
 			} else {
 				log.Error().Stack().Err(err).Send()
 			}
@@ -308,69 +351,556 @@ func (s *chatImpl) HandleInboundMessage(client *types.WsClient) {
 			res, err := wsRes.Parse()
 			if err != nil {
 				log.Error().Stack().Err(err).Send()
-				client.Con.Close()
+				client.Close()
 				return
 			}
 
-			client.Con.WriteMessage(websocket.BinaryMessage, res)
-			continue
+			client.Send(res)
+			return
 		}
 
+		req.Content = attachment.ObjectKey
+		req.Thumbnail = attachment.ThumbnailKey
+	}
+
+	saveSentMsgRes, err := s.SaveSentMessage(client.Ctx, req)
+	if err != nil {
+		log.Error().Stack().Err(err).Send()
+
+		if req.ContentType != types.ChatMessageContentTypeText {
+			s.deleteAttachment(client.Ctx, req.Content, req.Thumbnail)
+		}
+
+		res, err := wsRes.Parse()
+		if err != nil {
+			log.Error().Stack().Err(err).Send()
+			client.Close()
+			return
+		}
+
+		client.Send(res)
+		return
+	}
+
+	incomingMsg := types.ChatIncomingMessageRes{
+		RoomID:      saveSentMsgRes.RoomID,
+		MessageID:   saveSentMsgRes.MessageID,
+		Content:     req.Content,
+		ContentType: req.ContentType,
+		CreatedAt:   saveSentMsgRes.CreatedAt,
+	}
+
+	incomingMsg.AttachmentURL, incomingMsg.ThumbnailURL, err = s.attachmentURLs(client.Ctx, req.ContentType, req.Content, req.Thumbnail)
+	if err != nil {
+		log.Error().Stack().Err(err).Send()
+
+		res, err := wsRes.Parse()
+		if err != nil {
+			log.Error().Stack().Err(err).Send()
+			client.Close()
+			return
+		}
+
+		client.Send(res)
+		return
+	}
+
+	wsIncomingMsgRes := types.WsResponse{
+		Success: true,
+		Type:    types.WsResponseTypeChatIncomingMessage,
+		Code:    types.WsResponseCodeSuccess,
+		Message: "success",
+		Data:    incomingMsg,
+	}
+
+	res, err := wsIncomingMsgRes.Parse()
+	if err != nil {
+		log.Error().Stack().Err(err).Send()
+		client.Close()
+		return
+	}
+
+	// the recipient can be connected to another server, the hub of that server writes the message to the recipient
+	delivered, err := s.hub.Publish(client.Ctx, recipientUserID.UUID, res)
+	if err != nil {
+		log.Error().Stack().Err(err).Send()
+
+		errRes, err := wsRes.Parse()
+		if err != nil {
+			log.Error().Stack().Err(err).Send()
+			client.Close()
+			return
+		}
+
+		client.Send(errRes)
+		return
+	}
+
+	if !delivered {
+		wsRes.Success = true
+		wsRes.Code = types.WsResponseCodeChatRecipientOffline
+		wsRes.Message = "recipient is offline"
+
+		res, err := wsRes.Parse()
+		if err != nil {
+			log.Error().Stack().Err(err).Send()
+			client.Close()
+			return
+		}
+
+		client.Send(res)
+		return
+	}
+
+	s.markAsDelivered(client.Ctx, uuid.UUIDs{saveSentMsgRes.MessageID})
+
+	wsRes = types.WsResponse{
+		Success: true,
+		Type:    types.WsResponseTypeServer,
+		Code:    types.WsResponseCodeSuccess,
+		Message: "success",
+		Metadata: types.ChatSendMessageResMetadata{
+			ID: m.ID,
+		},
+	}
+
+	res, err = wsRes.Parse()
+	if err != nil {
+		log.Error().Stack().Err(err).Send()
+		client.Close()
+		return
+	}
+
+	client.Send(res)
+}
+
+// resume replays the messages received after the last message the client has seen as if they were sent while it was
+// connected, the returned response tells the client the replay is done
+func (s *chatImpl) resume(client *types.WsClient, msg []byte) types.WsResponse {
+	wsRes := types.WsResponse{
+		Success: false,
+		Type:    types.WsResponseTypeServer,
+		Code:    types.WsResponseCodeInternalServerError,
+		Message: "internal server error",
+	}
+
+	var req types.ChatResumeReq
+	if err := json.Unmarshal(msg, &req); err != nil {
+		wsRes.Code = types.WsResponseCodeClientError
+		wsRes.Message = "error parsing message"
+		return wsRes
+	}
+
+	if err := req.Validate(); err != nil {
+		wsRes.Code = types.WsResponseCodeClientError
+		wsRes.Message = "error validating message"
+		wsRes.Errors = err
+		return wsRes
+	}
+
+	// the replay must fit in the send buffer, otherwise the client is closed before it reads the queued messages
+	limit := min(types.ChatResumeLimit, client.FreeSendSpace()-types.WsSendBufferReserve)
+	if limit <= 0 {
+		return types.WsResponse{
+			Success: true,
+			Type:    types.WsResponseTypeChatResumed,
+			Code:    types.WsResponseCodeSuccess,
+			Message: "success",
+			Data: types.ChatResumeRes{
+				Count:   0,
+				HasMore: true,
+			},
+		}
+	}
+
+	messages, err := s.chatMessageRepo.FindReceivedAfterID(client.Ctx, client.AuthUser.ID, req.LastMessageID, limit+1)
+	if err != nil {
+		log.Error().Stack().Err(err).Send()
+		return wsRes
+	}
+
+	hasMore := len(messages) > limit
+	if hasMore {
+		messages = messages[:limit]
+	}
+
+	for _, message := range messages {
 		incomingMsg := types.ChatIncomingMessageRes{
-			RoomID:      saveSentMsgRes.RoomID,
-			MessageID:   saveSentMsgRes.MessageID,
-			Content:     m.Content,
-			ContentType: m.ContentType,
-			CreatedAt:   saveSentMsgRes.CreatedAt,
+			RoomID:      message.ChatRoomID,
+			MessageID:   message.ID,
+			Content:     message.Content,
+			ContentType: message.ContentType,
+			CreatedAt:   message.CreatedAt,
+		}
+
+		incomingMsg.AttachmentURL, incomingMsg.ThumbnailURL, err = s.attachmentURLs(client.Ctx, message.ContentType, message.Content, message.Thumbnail)
+		if err != nil {
+			log.Error().Stack().Err(err).Send()
+			return wsRes
 		}
 
-		wsIncomingMsgRes := types.WsResponse{
+		res, err := types.WsResponse{
 			Success: true,
 			Type:    types.WsResponseTypeChatIncomingMessage,
 			Code:    types.WsResponseCodeSuccess,
 			Message: "success",
 			Data:    incomingMsg,
-		}
-
-		res, err := wsIncomingMsgRes.Parse()
+		}.Parse()
 		if err != nil {
 			log.Error().Stack().Err(err).Send()
-			client.Con.Close()
-			return
+			return wsRes
 		}
 
-		if err := targetClient.Con.WriteMessage(websocket.BinaryMessage, res); err != nil {
+		client.Send(res)
+	}
+
+	if len(messages) > 0 {
+		s.markAsDelivered(client.Ctx, lo.Map(messages, func(message types.ChatMessage, _ int) uuid.UUID {
+			return message.ID
+		}))
+	}
+
+	return types.WsResponse{
+		Success: true,
+		Type:    types.WsResponseTypeChatResumed,
+		Code:    types.WsResponseCodeSuccess,
+		Message: "success",
+		Data: types.ChatResumeRes{
+			Count:   len(messages),
+			HasMore: hasMore,
+		},
+	}
+}
+
+func (s *chatImpl) typingStarted(client *types.WsClient, msg []byte) types.WsResponse {
+	return s.typing(client, msg, types.WsResponseTypeChatTypingStarted)
+}
+
+func (s *chatImpl) typingStopped(client *types.WsClient, msg []byte) types.WsResponse {
+	return s.typing(client, msg, types.WsResponseTypeChatTypingStopped)
+}
+
+// typing forwards the typing event to the other party of the room, the event is not stored so an offline recipient misses it
+func (s *chatImpl) typing(client *types.WsClient, msg []byte, resType types.WsResponseType) types.WsResponse {
+	wsRes := types.WsResponse{
+		Success: false,
+		Type:    types.WsResponseTypeServer,
+		Code:    types.WsResponseCodeInternalServerError,
+		Message: "internal server error",
+	}
+
+	var req types.ChatRoomEventReq
+	if err := json.Unmarshal(msg, &req); err != nil {
+		wsRes.Code = types.WsResponseCodeClientError
+		wsRes.Message = "error parsing message"
+		return wsRes
+	}
+
+	if err := req.Validate(); err != nil {
+		wsRes.Code = types.WsResponseCodeClientError
+		wsRes.Message = "error validating message"
+		wsRes.Errors = err
+		return wsRes
+	}
+
+	recipient, err := s.findRoomRecipient(client.Ctx, req.RoomID, client.AuthUser.ID)
+	if errors.Is(err, types.ErrNoData) {
+		wsRes.Code = types.WsResponseCodeChatRoomNotFound
+		wsRes.Message = "chat room not found"
+		return wsRes
+	} else if err != nil {
+		log.Error().Stack().Err(err).Send()
+		return wsRes
+	}
+
+	res, err := types.WsResponse{
+		Success: true,
+		Type:    resType,
+		Code:    types.WsResponseCodeSuccess,
+		Message: "success",
+		Data: types.ChatTypingRes{
+			RoomID: req.RoomID,
+			UserID: client.AuthUser.ID,
+		},
+	}.Parse()
+	if err != nil {
+		log.Error().Stack().Err(err).Send()
+		return wsRes
+	}
+
+	if _, err := s.hub.Publish(client.Ctx, recipient.UserID, res); err != nil {
+		log.Error().Stack().Err(err).Send()
+		return wsRes
+	}
+
+	return types.WsResponse{
+		Success: true,
+		Type:    types.WsResponseTypeServer,
+		Code:    types.WsResponseCodeSuccess,
+		Message: "success",
+	}
+}
+
+// presence returns whether the other party of the room is online, or when it was last seen
+func (s *chatImpl) presence(client *types.WsClient, msg []byte) types.WsResponse {
+	wsRes := types.WsResponse{
+		Success: false,
+		Type:    types.WsResponseTypeServer,
+		Code:    types.WsResponseCodeInternalServerError,
+		Message: "internal server error",
+	}
+
+	var req types.ChatRoomEventReq
+	if err := json.Unmarshal(msg, &req); err != nil {
+		wsRes.Code = types.WsResponseCodeClientError
+		wsRes.Message = "error parsing message"
+		return wsRes
+	}
+
+	if err := req.Validate(); err != nil {
+		wsRes.Code = types.WsResponseCodeClientError
+		wsRes.Message = "error validating message"
+		wsRes.Errors = err
+		return wsRes
+	}
+
+	recipient, err := s.findRoomRecipient(client.Ctx, req.RoomID, client.AuthUser.ID)
+	if errors.Is(err, types.ErrNoData) {
+		wsRes.Code = types.WsResponseCodeChatRoomNotFound
+		wsRes.Message = "chat room not found"
+		return wsRes
+	} else if err != nil {
+		log.Error().Stack().Err(err).Send()
+		return wsRes
+	}
+
+	presence := types.ChatPresenceRes{UserID: recipient.UserID}
+
+	presence.Online, err = s.hub.IsOnline(client.Ctx, recipient.UserID)
+	if err != nil {
+		log.Error().Stack().Err(err).Send()
+		return wsRes
+	}
+
+	if !presence.Online {
+		lastSeenAt, err := s.userPresenceRepo.FindLastSeen(client.Ctx, recipient.UserID)
+		if err != nil && !errors.Is(err, types.ErrNoData) {
 			log.Error().Stack().Err(err).Send()
+			return wsRes
+		} else if err == nil {
+			presence.LastSeenAt = null.TimeFrom(lastSeenAt)
+		}
+	}
 
-			errRes, err := wsRes.Parse()
-			if err != nil {
-				log.Error().Stack().Err(err).Send()
-				client.Con.Close()
-				return
-			}
+	return types.WsResponse{
+		Success: true,
+		Type:    types.WsResponseTypeChatPresence,
+		Code:    types.WsResponseCodeSuccess,
+		Message: "success",
+		Data:    presence,
+	}
+}
 
-			client.Con.WriteMessage(websocket.BinaryMessage, errRes)
-			continue
+// findRoomRecipient returns ErrNoData when the user is not a member of the room
+func (s *chatImpl) findRoomRecipient(ctx context.Context, roomID, userID uuid.UUID) (types.ChatRoomUser, error) {
+	if _, err := s.chatRoomUserRepo.FindByChatRoomIDAndUserID(ctx, roomID, userID); err != nil {
+		return types.ChatRoomUser{}, err
+	}
+
+	return s.chatRoomUserRepo.FindRecipientByChatRoomID(ctx, userID, roomID)
+}
+
+// PublishPresence tells the other party of every room of the user that the user connected or disconnected, a user who
+// is still connected with another device is not reported offline
+func (s *chatImpl) PublishPresence(ctx context.Context, userID uuid.UUID, online bool) error {
+	presence := types.ChatPresenceRes{
+		UserID: userID,
+		Online: online,
+	}
+
+	if !online {
+		stillOnline, err := s.hub.IsOnline(ctx, userID)
+		if err != nil {
+			return err
+		} else if stillOnline {
+			return nil
 		}
 
-		wsRes = types.WsResponse{
+		lastSeenAt := time.Now()
+		if err := s.userPresenceRepo.SetLastSeen(ctx, userID, lastSeenAt); err != nil {
+			return err
+		}
+
+		presence.LastSeenAt = null.TimeFrom(lastSeenAt)
+	}
+
+	rooms, err := s.chatRoomUserRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if len(rooms) == 0 {
+		return nil
+	}
+
+	roomIDs := lo.Map(rooms, func(room types.ChatRoomUserWithServiceIDAndOfferID, _ int) uuid.UUID {
+		return room.ChatRoomID
+	})
+
+	recipients, err := s.chatRoomUserRepo.FindRecipientByChatRoomIDs(ctx, userID, roomIDs)
+	if err != nil {
+		return err
+	}
+
+	res, err := types.WsResponse{
+		Success: true,
+		Type:    types.WsResponseTypeChatPresence,
+		Code:    types.WsResponseCodeSuccess,
+		Message: "success",
+		Data:    presence,
+	}.Parse()
+	if err != nil {
+		return errors.New(err)
+	}
+
+	recipientUserIDs := lo.Uniq(lo.Map(recipients, func(recipient types.ChatRoomUser, _ int) uuid.UUID {
+		return recipient.UserID
+	}))
+
+	for _, recipientUserID := range recipientUserIDs {
+		if _, err := s.hub.Publish(ctx, recipientUserID, res); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// markAsDelivered sends a delivered receipt for the messages that were not delivered yet, a failure only loses the receipt
+func (s *chatImpl) markAsDelivered(ctx context.Context, IDs uuid.UUIDs) {
+	deliveredAt := time.Now()
+
+	messages, err := s.chatMessageRepo.MarkAsDelivered(ctx, IDs, deliveredAt)
+	if err != nil {
+		log.Error().Stack().Err(err).Send()
+		return
+	}
+
+	s.publishReceipts(ctx, types.WsResponseTypeChatDelivered, messages, deliveredAt)
+}
+
+// publishReceipts sends one receipt for every sender and room of the messages
+func (s *chatImpl) publishReceipts(ctx context.Context, resType types.WsResponseType, messages []types.ChatMessage, at time.Time) {
+	type receiptKey struct {
+		senderUserID uuid.UUID
+		roomID       uuid.UUID
+	}
+
+	receipts := map[receiptKey]uuid.UUIDs{}
+	for _, message := range messages {
+		key := receiptKey{senderUserID: message.UserID, roomID: message.ChatRoomID}
+		receipts[key] = append(receipts[key], message.ID)
+	}
+
+	for key, messageIDs := range receipts {
+		res, err := types.WsResponse{
 			Success: true,
-			Type:    types.WsResponseTypeServer,
+			Type:    resType,
 			Code:    types.WsResponseCodeSuccess,
 			Message: "success",
-			Metadata: types.ChatSendMessageResMetadata{
-				ID: m.ID,
+			Data: types.ChatReceiptRes{
+				RoomID:     key.roomID,
+				MessageIDs: messageIDs,
+				At:         at,
 			},
+		}.Parse()
+		if err != nil {
+			log.Error().Stack().Err(err).Send()
+			continue
 		}
 
-		res, err = wsRes.Parse()
-		if err != nil {
+		if _, err := s.hub.Publish(ctx, key.senderUserID, res); err != nil {
 			log.Error().Stack().Err(err).Send()
-			client.Con.Close()
-			return
 		}
+	}
+}
+
+// findMessagesPage returns the page of messages from the oldest to the newest whatever the direction of the cursor is
+func (s *chatImpl) findMessagesPage(ctx context.Context, roomID uuid.UUID, req types.ChatMessageCursorPaginationReq) ([]types.ChatMessage, types.ChatMessageCursorPaginationRes, error) {
+	paginationRes := types.ChatMessageCursorPaginationRes{}
+
+	before, err := req.GetBeforeID()
+	if err != nil {
+		return nil, paginationRes, err
+	}
+
+	after, err := req.GetAfterID()
+	if err != nil {
+		return nil, paginationRes, err
+	}
+
+	// one more message is fetched to know whether there is a next page
+	size := req.GetSize()
+	messages, err := s.chatMessageRepo.FindByChatRoomID(ctx, roomID, types.ChatMessageCursorFilter{
+		Before: before,
+		After:  after,
+		Limit:  size + 1,
+	})
+	if err != nil {
+		return nil, paginationRes, err
+	}
 
-		client.Con.WriteMessage(websocket.BinaryMessage, res)
+	paginationRes.Size = int32(size)
+	if len(messages) > size {
+		messages = messages[:size]
+		paginationRes.HasMore = true
+	}
+
+	if !after.Valid {
+		slices.Reverse(messages)
+	}
+
+	if len(messages) > 0 {
+		paginationRes.Before = messages[0].ID.String()
+		paginationRes.After = messages[len(messages)-1].ID.String()
+	}
+
+	return messages, paginationRes, nil
+}
+
+// attachmentURLs presigns the attachment and the thumbnail of a message, a text message has neither
+func (s *chatImpl) attachmentURLs(ctx context.Context, contentType types.ChatMessageContentType, content string, thumbnail null.String) (string, string, error) {
+	if contentType == types.ChatMessageContentTypeText {
+		return "", "", nil
+	}
+
+	attachmentURL, err := s.fileSvc.GetS3PresignedURL(ctx, content)
+	if err != nil {
+		return "", "", err
+	}
+
+	if !thumbnail.Valid {
+		return attachmentURL, "", nil
+	}
+
+	thumbnailURL, err := s.fileSvc.GetS3PresignedURL(ctx, thumbnail.String)
+	if err != nil {
+		return "", "", err
+	}
+
+	return attachmentURL, thumbnailURL, nil
+}
+
+// deleteAttachment removes the objects of a message that could not be saved
+func (s *chatImpl) deleteAttachment(ctx context.Context, objectKey string, thumbnail null.String) {
+	if err := s.fileSvc.DeleteS3Object(ctx, objectKey); err != nil {
+		log.Error().Stack().Err(err).Send()
+	}
+
+	if thumbnail.Valid {
+		if err := s.fileSvc.DeleteS3Object(ctx, thumbnail.String); err != nil {
+			log.Error().Stack().Err(err).Send()
+		}
 	}
 }
 
@@ -441,6 +971,7 @@ func (s *chatImpl) SaveSentMessage(ctx context.Context, req types.ChatSaveSentMe
 		UserID:      userSender.ID,
 		Content:     req.Content,
 		ContentType: req.ContentType,
+		Thumbnail:   req.Thumbnail,
 		CreatedAt:   timeNow,
 	}
 
@@ -729,42 +1260,43 @@ func (s *chatImpl) ConsumerGetAll(ctx context.Context, req types.ChatGetAllReq)
 	return res, nil
 }
 
-func (s *chatImpl) ConsumerGetByRoomID(ctx context.Context, req types.ChatGetByRoomIDReq) (types.ChatConsumerGetByRoomIDRes, error) {
+func (s *chatImpl) ConsumerGetByRoomID(ctx context.Context, req types.ChatGetByRoomIDReq) (types.ChatConsumerGetByRoomIDRes, types.ChatMessageCursorPaginationRes, error) {
 	res := types.ChatConsumerGetByRoomIDRes{}
+	paginationRes := types.ChatMessageCursorPaginationRes{}
 
-	if err := req.Validate(); err != nil {
-		return res, err
+	if err := req.ValidateAndNormalize(); err != nil {
+		return res, paginationRes, err
 	}
 
 	chatRoom, err := s.chatRoomRepo.FindByID(ctx, req.RoomID)
 	if errors.Is(err, types.ErrNoData) {
-		return res, errors.New(types.AppErr{Code: http.StatusNotFound, Message: "chat room not found"})
+		return res, paginationRes, errors.New(types.AppErr{Code: http.StatusNotFound, Message: "chat room not found"})
 	} else if err != nil {
-		return res, err
+		return res, paginationRes, err
 	}
 
-	messages, err := s.chatMessageRepo.FindByChatRoomID(ctx, chatRoom.ID)
+	messages, paginationRes, err := s.findMessagesPage(ctx, chatRoom.ID, req.ChatMessageCursorPaginationReq)
 	if err != nil {
-		return res, err
+		return res, paginationRes, err
 	}
 
 	recipient, err := s.chatRoomUserRepo.FindRecipientByChatRoomID(ctx, req.AuthUser.ID, chatRoom.ID)
 	if errors.Is(err, types.ErrNoData) {
-		return res, errors.Errorf("recipient not found: user_id %s", req.AuthUser.ID)
+		return res, paginationRes, errors.Errorf("recipient not found: user_id %s", req.AuthUser.ID)
 	} else if err != nil {
-		return res, err
+		return res, paginationRes, err
 	}
 
 	provider, err := s.serviceProviderRepo.FindByUserID(ctx, recipient.UserID)
 	if errors.Is(err, types.ErrNoData) {
-		return res, errors.Errorf("service provider not found: user_id %s", recipient.UserID)
+		return res, paginationRes, errors.Errorf("service provider not found: user_id %s", recipient.UserID)
 	} else if err != nil {
-		return res, err
+		return res, paginationRes, err
 	}
 
 	logoURL, err := s.fileSvc.GetS3PresignedURL(ctx, provider.LogoImage)
 	if err != nil {
-		return res, err
+		return res, paginationRes, err
 	}
 
 	res = types.ChatConsumerGetByRoomIDRes{
@@ -780,15 +1312,15 @@ func (s *chatImpl) ConsumerGetByRoomID(ctx context.Context, req types.ChatGetByR
 
 	reqTz, err := s.utilSvc.ParseUserTimeZone(req.TimeZone)
 	if err != nil {
-		return res, err
+		return res, paginationRes, err
 	}
 
 	if chatRoom.OfferID.Valid {
 		order, err := s.orderRepo.FindByOfferID(ctx, chatRoom.OfferID.UUID)
 		if errors.Is(err, types.ErrNoData) {
-			return res, errors.Errorf("order not found: offer_id %s", chatRoom.OfferID.UUID)
+			return res, paginationRes, errors.Errorf("order not found: offer_id %s", chatRoom.OfferID.UUID)
 		} else if err != nil {
-			return res, err
+			return res, paginationRes, err
 		}
 
 		res.Context = types.ChatContextOrder
@@ -807,9 +1339,9 @@ func (s *chatImpl) ConsumerGetByRoomID(ctx context.Context, req types.ChatGetByR
 	} else if chatRoom.ServiceID.Valid {
 		service, err := s.serviceRepo.FindByID(ctx, chatRoom.ServiceID.UUID)
 		if errors.Is(err, types.ErrNoData) {
-			return res, errors.Errorf("service not found: id %s", chatRoom.ServiceID.UUID)
+			return res, paginationRes, errors.Errorf("service not found: id %s", chatRoom.ServiceID.UUID)
 		} else if err != nil {
-			return res, err
+			return res, paginationRes, err
 		}
 
 		res.Context = types.ChatContextService
@@ -820,17 +1352,25 @@ func (s *chatImpl) ConsumerGetByRoomID(ctx context.Context, req types.ChatGetByR
 	}
 
 	for _, message := range messages {
+		attachmentURL, thumbnailURL, err := s.attachmentURLs(ctx, message.ContentType, message.Content, message.Thumbnail)
+		if err != nil {
+			return res, paginationRes, err
+		}
+
 		res.Messages = append(res.Messages, types.ChatGetByRoomIDResMessage{
-			ID:          message.ID,
-			IsSender:    message.UserID == req.AuthUser.ID,
-			Content:     message.Content,
-			ContentType: message.ContentType,
-			Read:        message.Read,
-			CreatedAt:   message.CreatedAt.In(reqTz),
+			ID:            message.ID,
+			IsSender:      message.UserID == req.AuthUser.ID,
+			Content:       message.Content,
+			ContentType:   message.ContentType,
+			AttachmentURL: attachmentURL,
+			ThumbnailURL:  thumbnailURL,
+			Delivered:     message.DeliveredAt.Valid,
+			Read:          message.Read,
+			CreatedAt:     message.CreatedAt.In(reqTz),
 		})
 	}
 
-	return res, nil
+	return res, paginationRes, nil
 }
 
 func (s *chatImpl) ProviderGetAll(ctx context.Context, req types.ChatGetAllReq) ([]types.ChatProviderGetAllRes, error) {
@@ -949,37 +1489,38 @@ func (s *chatImpl) ProviderGetAll(ctx context.Context, req types.ChatGetAllReq)
 	return res, nil
 }
 
-func (s *chatImpl) ProviderGetByRoomID(ctx context.Context, req types.ChatGetByRoomIDReq) (types.ChatProviderGetByRoomIDRes, error) {
+func (s *chatImpl) ProviderGetByRoomID(ctx context.Context, req types.ChatGetByRoomIDReq) (types.ChatProviderGetByRoomIDRes, types.ChatMessageCursorPaginationRes, error) {
 	res := types.ChatProviderGetByRoomIDRes{}
+	paginationRes := types.ChatMessageCursorPaginationRes{}
 
-	if err := req.Validate(); err != nil {
-		return res, err
+	if err := req.ValidateAndNormalize(); err != nil {
+		return res, paginationRes, err
 	}
 
 	chatRoom, err := s.chatRoomRepo.FindByID(ctx, req.RoomID)
 	if errors.Is(err, types.ErrNoData) {
-		return res, errors.New(types.AppErr{Code: http.StatusNotFound, Message: "chat room not found"})
+		return res, paginationRes, errors.New(types.AppErr{Code: http.StatusNotFound, Message: "chat room not found"})
 	} else if err != nil {
-		return res, err
+		return res, paginationRes, err
 	}
 
-	messages, err := s.chatMessageRepo.FindByChatRoomID(ctx, chatRoom.ID)
+	messages, paginationRes, err := s.findMessagesPage(ctx, chatRoom.ID, req.ChatMessageCursorPaginationReq)
 	if err != nil {
-		return res, err
+		return res, paginationRes, err
 	}
 
 	recipient, err := s.chatRoomUserRepo.FindRecipientByChatRoomID(ctx, req.AuthUser.ID, chatRoom.ID)
 	if errors.Is(err, types.ErrNoData) {
-		return res, errors.Errorf("recipient not found: user_id %s", req.AuthUser.ID)
+		return res, paginationRes, errors.Errorf("recipient not found: user_id %s", req.AuthUser.ID)
 	} else if err != nil {
-		return res, err
+		return res, paginationRes, err
 	}
 
 	consumer, err := s.userRepo.FindByID(ctx, recipient.UserID)
 	if errors.Is(err, types.ErrNoData) {
-		return res, errors.New(fmt.Sprintf("service provider not found: user_id %s", recipient.UserID))
+		return res, paginationRes, errors.New(fmt.Sprintf("service provider not found: user_id %s", recipient.UserID))
 	} else if err != nil {
-		return res, err
+		return res, paginationRes, err
 	}
 
 	res = types.ChatProviderGetByRoomIDRes{
@@ -994,15 +1535,15 @@ func (s *chatImpl) ProviderGetByRoomID(ctx context.Context, req types.ChatGetByR
 
 	reqTz, err := s.utilSvc.ParseUserTimeZone(req.TimeZone)
 	if err != nil {
-		return res, err
+		return res, paginationRes, err
 	}
 
 	if chatRoom.OfferID.Valid {
 		order, err := s.orderRepo.FindByOfferID(ctx, chatRoom.OfferID.UUID)
 		if errors.Is(err, types.ErrNoData) {
-			return res, errors.Errorf("order not found: offer_id %s", chatRoom.OfferID.UUID)
+			return res, paginationRes, errors.Errorf("order not found: offer_id %s", chatRoom.OfferID.UUID)
 		} else if err != nil {
-			return res, err
+			return res, paginationRes, err
 		}
 
 		res.Context = types.ChatContextOrder
@@ -1021,9 +1562,9 @@ func (s *chatImpl) ProviderGetByRoomID(ctx context.Context, req types.ChatGetByR
 	} else if chatRoom.ServiceID.Valid {
 		service, err := s.serviceRepo.FindByID(ctx, chatRoom.ServiceID.UUID)
 		if errors.Is(err, types.ErrNoData) {
-			return res, errors.Errorf("service not found: id %s", chatRoom.ServiceID.UUID)
+			return res, paginationRes, errors.Errorf("service not found: id %s", chatRoom.ServiceID.UUID)
 		} else if err != nil {
-			return res, err
+			return res, paginationRes, err
 		}
 
 		res.Context = types.ChatContextService
@@ -1034,17 +1575,82 @@ func (s *chatImpl) ProviderGetByRoomID(ctx context.Context, req types.ChatGetByR
 	}
 
 	for _, message := range messages {
+		attachmentURL, thumbnailURL, err := s.attachmentURLs(ctx, message.ContentType, message.Content, message.Thumbnail)
+		if err != nil {
+			return res, paginationRes, err
+		}
+
 		res.Messages = append(res.Messages, types.ChatGetByRoomIDResMessage{
-			ID:          message.ID,
-			IsSender:    message.UserID == req.AuthUser.ID,
-			Content:     message.Content,
-			ContentType: message.ContentType,
-			Read:        message.Read,
-			CreatedAt:   message.CreatedAt.In(reqTz),
+			ID:            message.ID,
+			IsSender:      message.UserID == req.AuthUser.ID,
+			Content:       message.Content,
+			ContentType:   message.ContentType,
+			AttachmentURL: attachmentURL,
+			ThumbnailURL:  thumbnailURL,
+			Delivered:     message.DeliveredAt.Valid,
+			Read:          message.Read,
+			CreatedAt:     message.CreatedAt.In(reqTz),
 		})
 	}
 
-	return res, nil
+	return res, paginationRes, nil
+}
+
+// Search only matches text messages, the pagination follows the results from the newest message
+func (s *chatImpl) Search(ctx context.Context, req types.ChatSearchReq) ([]types.ChatSearchRes, types.CursorPaginationRes, error) {
+	res := []types.ChatSearchRes{}
+	paginationRes := types.CursorPaginationRes{}
+
+	if err := req.ValidateAndNormalize(); err != nil {
+		return res, paginationRes, err
+	}
+
+	// the after cursor is the last message of the previous page, the next page has the messages older than it
+	lastMessageID, err := req.GetAfterID()
+	if err != nil {
+		return res, paginationRes, err
+	}
+
+	reqTz, err := s.utilSvc.ParseUserTimeZone(req.TimeZone)
+	if err != nil {
+		return res, paginationRes, err
+	}
+
+	// one more message is fetched to know whether there is a next page
+	size := req.GetSize()
+	messages, err := s.chatMessageRepo.Search(ctx, req.AuthUser.ID, req.Query, types.ChatMessageCursorFilter{
+		Before: lastMessageID,
+		Limit:  size + 1,
+	})
+	if err != nil {
+		return res, paginationRes, err
+	}
+
+	paginationRes.Size = int32(size)
+	if len(messages) > size {
+		messages = messages[:size]
+		paginationRes.HasMore = true
+		paginationRes.After = messages[size-1].ID.String()
+	}
+
+	for _, message := range messages {
+		res = append(res, types.ChatSearchRes{
+			RoomID: message.ChatRoomID,
+			Recipient: types.ChatSearchResRecipient{
+				UserID: message.RecipientUserID,
+				Name:   message.RecipientName,
+			},
+			Message: types.ChatSearchResMessage{
+				ID:        message.ID,
+				IsSender:  message.UserID == req.AuthUser.ID,
+				Content:   message.Content,
+				Headline:  message.HeadlineHTML(),
+				CreatedAt: message.CreatedAt.In(reqTz),
+			},
+		})
+	}
+
+	return res, paginationRes, nil
 }
 
 func (s *chatImpl) MarkReceivedAsSeen(ctx context.Context, req types.ChatMarkReceivedAsSeenReq) error {
@@ -1093,5 +1699,11 @@ func (s *chatImpl) MarkReceivedAsSeen(ctx context.Context, req types.ChatMarkRec
 		return err
 	}
 
+	unreadChats := lo.Filter(chats, func(chat types.ChatMessage, _ int) bool {
+		return !chat.Read
+	})
+
+	s.publishReceipts(ctx, types.WsResponseTypeChatRead, unreadChats, time.Now())
+
 	return nil
 }
@@ -7,15 +7,20 @@ import (
 	"kelarin/internal/types"
 	"kelarin/internal/utils"
 	dbUtil "kelarin/internal/utils/dbutil"
+	"net/http"
 
 	"github.com/go-errors/errors"
+	"github.com/rs/zerolog/log"
 	"github.com/shopspring/decimal"
 	"golang.org/x/text/currency"
 )
 
 type ConsumerNotification interface {
 	Create(ctx context.Context, tx dbUtil.Tx, req types.ConsumerNotification) error
-	GetAll(ctx context.Context, req types.ConsumerNotificationGetAllReq) ([]types.ConsumerNotificationGetAllRes, error)
+	GetAll(ctx context.Context, req types.ConsumerNotificationGetAllReq) ([]types.ConsumerNotificationGetAllRes, types.CursorPaginationRes, error)
+	MarkAsRead(ctx context.Context, req types.NotificationMarkAsReadReq) error
+	MarkAllAsRead(ctx context.Context, req types.NotificationMarkAllAsReadReq) error
+	GetUnreadCount(ctx context.Context, req types.NotificationUnreadCountReq) (types.NotificationUnreadCountRes, error)
 }
 
 type consumerNotificationImpl struct {
@@ -24,15 +29,17 @@ type consumerNotificationImpl struct {
 	consumerNotificationRepo repository.ConsumerNotification
 	utilSvc                  Util
 	fileSvc                  File
+	notificationSvc          Notification
 }
 
-func NewConsumerNotification(beginMainDBTx dbUtil.SqlxTx, userRepo repository.User, consumerNotificationRepo repository.ConsumerNotification, utilSvc Util, fileSvc File) ConsumerNotification {
+func NewConsumerNotification(beginMainDBTx dbUtil.SqlxTx, userRepo repository.User, consumerNotificationRepo repository.ConsumerNotification, utilSvc Util, fileSvc File, notificationSvc Notification) ConsumerNotification {
 	return &consumerNotificationImpl{
 		beginMainDBTx:            beginMainDBTx,
 		userRepo:                 userRepo,
 		consumerNotificationRepo: consumerNotificationRepo,
 		utilSvc:                  utilSvc,
 		fileSvc:                  fileSvc,
+		notificationSvc:          notificationSvc,
 	}
 }
 
@@ -63,21 +70,39 @@ func (s *consumerNotificationImpl) Create(ctx context.Context, _tx dbUtil.Tx, re
 	return nil
 }
 
-func (s *consumerNotificationImpl) GetAll(ctx context.Context, req types.ConsumerNotificationGetAllReq) ([]types.ConsumerNotificationGetAllRes, error) {
+func (s *consumerNotificationImpl) GetAll(ctx context.Context, req types.ConsumerNotificationGetAllReq) ([]types.ConsumerNotificationGetAllRes, types.CursorPaginationRes, error) {
 	res := []types.ConsumerNotificationGetAllRes{}
+	paginationRes := types.CursorPaginationRes{}
 
-	if err := req.Validate(); err != nil {
-		return res, err
+	if err := req.ValidateAndNormalize(); err != nil {
+		return res, paginationRes, err
 	}
 
-	notification, err := s.consumerNotificationRepo.FindAllByUserID(ctx, req.AuthUser.ID)
+	after, err := req.GetAfterID()
 	if err != nil {
-		return res, err
+		return res, paginationRes, err
+	}
+
+	// one more notification is fetched to know whether there is a next page
+	size := req.GetSize()
+	notification, err := s.consumerNotificationRepo.FindAllByUserID(ctx, req.AuthUser.ID, types.NotificationCursorFilter{
+		After: after,
+		Limit: size + 1,
+	})
+	if err != nil {
+		return res, paginationRes, err
+	}
+
+	paginationRes.Size = int32(size)
+	if len(notification) > size {
+		notification = notification[:size]
+		paginationRes.HasMore = true
+		paginationRes.After = notification[size-1].ID.String()
 	}
 
 	reqTz, err := s.utilSvc.ParseUserTimeZone(req.TimeZone)
 	if err != nil {
-		return res, err
+		return res, paginationRes, err
 	}
 
 	for _, n := range notification {
@@ -87,7 +112,7 @@ func (s *consumerNotificationImpl) GetAll(ctx context.Context, req types.Consume
 		if n.ServiceProviderLogoImage.Valid {
 			providerLogoURL, err = s.fileSvc.GetS3PresignedURL(ctx, n.ServiceProviderLogoImage.String)
 			if err != nil {
-				return res, err
+				return res, paginationRes, err
 			}
 		}
 
@@ -102,6 +127,60 @@ func (s *consumerNotificationImpl) GetAll(ctx context.Context, req types.Consume
 		})
 	}
 
+	return res, paginationRes, nil
+}
+
+func (s *consumerNotificationImpl) MarkAsRead(ctx context.Context, req types.NotificationMarkAsReadReq) error {
+	if err := req.Validate(); err != nil {
+		return err
+	}
+
+	affected, err := s.consumerNotificationRepo.UpdateAsReadByIDs(ctx, req.AuthUser.ID, req.IDs)
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return errors.New(types.AppErr{Code: http.StatusNotFound, Message: "notification not found"})
+	}
+
+	if err := s.notificationSvc.PublishUnreadCount(ctx, req.AuthUser.ID); err != nil {
+		log.Error().Stack().Err(err).Send()
+	}
+
+	return nil
+}
+
+func (s *consumerNotificationImpl) MarkAllAsRead(ctx context.Context, req types.NotificationMarkAllAsReadReq) error {
+	if err := req.Validate(); err != nil {
+		return err
+	}
+
+	if err := s.consumerNotificationRepo.UpdateAllAsReadByUserID(ctx, req.AuthUser.ID); err != nil {
+		return err
+	}
+
+	if err := s.notificationSvc.PublishUnreadCount(ctx, req.AuthUser.ID); err != nil {
+		log.Error().Stack().Err(err).Send()
+	}
+
+	return nil
+}
+
+func (s *consumerNotificationImpl) GetUnreadCount(ctx context.Context, req types.NotificationUnreadCountReq) (types.NotificationUnreadCountRes, error) {
+	res := types.NotificationUnreadCountRes{}
+
+	if err := req.Validate(); err != nil {
+		return res, err
+	}
+
+	count, err := s.consumerNotificationRepo.CountUnreadByUserID(ctx, req.AuthUser.ID)
+	if err != nil {
+		return res, err
+	}
+
+	res.Count = count
+
 	return res, nil
 }
 
@@ -119,14 +198,23 @@ func (s *consumerNotificationImpl) GenerateDetails(notification types.ConsumerNo
 			OfferID: notification.OfferID.UUID,
 		}
 	case types.ConsumerNotificationTypePaymentSuccess,
-		types.ConsumerNotificationTypePaymentExpired:
+		types.ConsumerNotificationTypePaymentExpired,
+		types.ConsumerNotificationTypePaymentRefunded:
 		details.Metadata = types.ConsumerNotificationMetadataPayment{
 			PaymentID: notification.PaymentID.UUID,
 		}
-	case types.ConsumerNotificationTypeOrderFinished:
+	case types.ConsumerNotificationTypeOrderFinished,
+		types.ConsumerNotificationTypeOrderCanceled,
+		types.ConsumerNotificationTypeOrderSessionFinished:
 		details.Metadata = types.ConsumerNotificationMetadataOrder{
 			OrderID: notification.OrderID.UUID,
 		}
+	case types.ConsumerNotificationTypeDisputeResponded,
+		types.ConsumerNotificationTypeDisputeResolved:
+		details.Metadata = types.ConsumerNotificationMetadataDispute{
+			OrderID:   notification.OrderID.UUID,
+			DisputeID: notification.DisputeID.UUID,
+		}
 	}
 
 	switch notification.Type {
@@ -147,9 +235,24 @@ func (s *consumerNotificationImpl) GenerateDetails(notification types.ConsumerNo
 	case types.ConsumerNotificationTypePaymentExpired:
 		details.Title = "Your payment is expired"
 		details.Message = fmt.Sprintf("Your payment with order id %s is expire, create a new one!", notification.OrderID.UUID.String())
+	case types.ConsumerNotificationTypePaymentRefunded:
+		details.Title = "Payment refunded"
+		details.Message = fmt.Sprintf("Your payment with order id %s has been refunded", notification.OrderID.UUID.String())
 	case types.ConsumerNotificationTypeOrderFinished:
 		details.Title = fmt.Sprintf("%s's order finished", notification.ServiceProviderName.String)
 		details.Message = "Your order has been finished. Rate service provider now!"
+	case types.ConsumerNotificationTypeOrderCanceled:
+		details.Title = fmt.Sprintf("%s's order canceled", notification.ServiceProviderName.String)
+		details.Message = "Your order has been canceled. Any refund will be sent to your payment method"
+	case types.ConsumerNotificationTypeOrderSessionFinished:
+		details.Title = fmt.Sprintf("%s's order session finished", notification.ServiceProviderName.String)
+		details.Message = "A session of your order has been finished. Check the progress of your order"
+	case types.ConsumerNotificationTypeDisputeResponded:
+		details.Title = fmt.Sprintf("%s responded to your dispute", notification.ServiceProviderName.String)
+		details.Message = "Check the response of the service provider"
+	case types.ConsumerNotificationTypeDisputeResolved:
+		details.Title = "Your dispute has been resolved"
+		details.Message = "Check the outcome of your dispute"
 	}
 
 	return details
@@ -16,7 +16,7 @@ import (
 )
 
 type ConsumerService interface {
-	GetAll(ctx context.Context, req types.ConsumerServiceGetAllReq) ([]types.ConsumerServiceGetAllRes, types.PaginationRes, error)
+	GetAll(ctx context.Context, req types.ConsumerServiceGetAllReq) ([]types.ConsumerServiceGetAllRes, types.PaginationRes, types.ConsumerServiceGetAllMetadata, error)
 	GetByID(ctx context.Context, ID uuid.UUID) (types.ConsumerServiceGetByIDRes, error)
 	CreateFeedback(ctx context.Context, req types.ConsumerServiceFeedbackCreateReq) error
 }
@@ -30,6 +30,8 @@ type consumerServiceImpl struct {
 	fileSvc                 File
 	orderRepo               repository.Order
 	serviceFeedbackRepo     repository.ServiceFeedback
+	userAddressRepo         repository.UserAddress
+	outboxSvc               Outbox
 }
 
 func NewConsumerService(
@@ -41,6 +43,8 @@ func NewConsumerService(
 	fileSvc File,
 	orderRepo repository.Order,
 	serviceFeedbackRepo repository.ServiceFeedback,
+	userAddressRepo repository.UserAddress,
+	outboxSvc Outbox,
 ) ConsumerService {
 	return &consumerServiceImpl{
 		beginMainDBTx:           beginMainDBTx,
@@ -51,44 +55,64 @@ func NewConsumerService(
 		fileSvc:                 fileSvc,
 		orderRepo:               orderRepo,
 		serviceFeedbackRepo:     serviceFeedbackRepo,
+		userAddressRepo:         userAddressRepo,
+		outboxSvc:               outboxSvc,
 	}
 }
 
-func (s *consumerServiceImpl) GetAll(ctx context.Context, req types.ConsumerServiceGetAllReq) ([]types.ConsumerServiceGetAllRes, types.PaginationRes, error) {
+func (s *consumerServiceImpl) GetAll(ctx context.Context, req types.ConsumerServiceGetAllReq) ([]types.ConsumerServiceGetAllRes, types.PaginationRes, types.ConsumerServiceGetAllMetadata, error) {
 	res := []types.ConsumerServiceGetAllRes{}
 	paginationRes := types.PaginationRes{}
+	metadata := types.ConsumerServiceGetAllMetadata{}
 
 	if err := req.ValidateAndNormalize(); err != nil {
-		return res, paginationRes, err
+		return res, paginationRes, metadata, err
 	}
 
 	sizeInt, err := strconv.Atoi(req.Size)
 	if err != nil {
-		return res, paginationRes, errors.New(err)
+		return res, paginationRes, metadata, errors.New(err)
 	}
 
 	after, err := utils.DecodeESAfter(req.After)
 	if err != nil {
-		return res, paginationRes, err
+		return res, paginationRes, metadata, err
 	}
 
 	filter := types.ServiceIndexFilter{
-		Limit:      sizeInt,
-		After:      after,
-		Province:   req.Province,
-		City:       req.City,
-		Categories: req.Categories,
-		Keyword:    req.Keyword,
+		Limit:           sizeInt,
+		After:           after,
+		Province:        req.Province,
+		City:            req.City,
+		Categories:      req.Categories,
+		Keyword:         req.Keyword,
+		FeeMin:          req.FeeMin,
+		FeeMax:          req.FeeMax,
+		MinRating:       req.MinRating,
+		DeliveryMethods: req.DeliveryMethods,
+		IsAvailable:     req.IsAvailable,
+		Sort:            req.Sort,
+	}
+
+	if req.IsGeoSearch() {
+		filter.Location, filter.AreaCity, err = s.getSearchLocation(ctx, req)
+		if err != nil {
+			return res, paginationRes, metadata, err
+		}
+
+		filter.RadiusKm = req.Radius.Float64
 	}
 
-	services, totalItem, after, err := s.serviceIndexRepo.FindAllByFilter(ctx, filter)
+	services, totalItem, after, facets, err := s.serviceIndexRepo.FindAllByFilter(ctx, filter)
 	if err != nil {
-		return res, paginationRes, err
+		return res, paginationRes, metadata, err
 	}
 
+	metadata.Facets = facets
+
 	afterRes, err := utils.EncodeEsAfter(after)
 	if err != nil {
-		return res, paginationRes, err
+		return res, paginationRes, metadata, err
 	}
 
 	paginationRes = req.GeneratePaginationResponse(totalItem)
@@ -101,7 +125,7 @@ func (s *consumerServiceImpl) GetAll(ctx context.Context, req types.ConsumerServ
 
 	serviceProviders, err := s.serviceProviderRepo.FindByIDs(ctx, serviceProviderIDs)
 	if err != nil {
-		return res, paginationRes, err
+		return res, paginationRes, metadata, err
 	}
 
 	for _, service := range services {
@@ -110,12 +134,12 @@ func (s *consumerServiceImpl) GetAll(ctx context.Context, req types.ConsumerServ
 		})
 
 		if !exs {
-			return res, paginationRes, errors.Errorf("service provider not found: id %s", service.ServiceProviderID)
+			return res, paginationRes, metadata, errors.Errorf("service provider not found: id %s", service.ServiceProviderID)
 		}
 
 		imgURL, err := s.fileSvc.GetS3PresignedURL(ctx, service.Images[0])
 		if err != nil {
-			return res, paginationRes, err
+			return res, paginationRes, metadata, err
 		}
 
 		res = append(res, types.ConsumerServiceGetAllRes{
@@ -129,10 +153,39 @@ func (s *consumerServiceImpl) GetAll(ctx context.Context, req types.ConsumerServ
 			City:                  service.City.String,
 			ReceivedRatingCount:   service.ReceivedRatingCount,
 			ReceivedRatingAverage: service.ReceivedRatingAverage,
+			Distance:              service.Distance,
 		})
 	}
 
-	return res, paginationRes, nil
+	return res, paginationRes, metadata, nil
+}
+
+// getSearchLocation returns the location to search around and the city used to match services by their service area
+func (s *consumerServiceImpl) getSearchLocation(ctx context.Context, req types.ConsumerServiceGetAllReq) (*types.ServiceIndexLocation, string, error) {
+	if !req.AddressID.Valid {
+		return &types.ServiceIndexLocation{Lat: req.Lat.Float64, Lon: req.Lng.Float64}, req.City, nil
+	}
+
+	address, err := s.userAddressRepo.FindByIDAndUserID(ctx, req.AddressID.UUID, req.AuthUser.ID)
+	if errors.Is(err, types.ErrNoData) {
+		return nil, "", errors.New(types.AppErr{Code: http.StatusNotFound, Message: "address not found"})
+	} else if err != nil {
+		return nil, "", err
+	}
+
+	if !address.Coordinates.Valid {
+		return nil, "", errors.New(types.AppErr{
+			Code:    http.StatusUnprocessableEntity,
+			Message: "address has no coordinates",
+		})
+	}
+
+	lat, lng, err := utils.ParseLatLngFromHexStr(address.Coordinates.String)
+	if err != nil {
+		return nil, "", errors.New(err)
+	}
+
+	return &types.ServiceIndexLocation{Lat: lat, Lon: lng}, address.City, nil
 }
 
 func (s *consumerServiceImpl) GetByID(ctx context.Context, ID uuid.UUID) (types.ConsumerServiceGetByIDRes, error) {
@@ -251,13 +304,6 @@ func (s *consumerServiceImpl) CreateFeedback(ctx context.Context, req types.Cons
 		CreatedAt: timeNow,
 	}
 
-	idxService, seqNo, primaryTerm, err := s.serviceIndexRepo.FindByID(ctx, order.ServiceID.String())
-	if errors.Is(err, types.ErrNoData) {
-		return errors.Errorf("service index not found: id %s", order.ServiceID)
-	} else if err != nil {
-		return err
-	}
-
 	tx, err := s.beginMainDBTx(ctx, nil)
 	if err != nil {
 		return err
@@ -311,10 +357,12 @@ func (s *consumerServiceImpl) CreateFeedback(ctx context.Context, req types.Cons
 		return err
 	}
 
-	idxService.ReceivedRatingCount = service.ReceivedRatingCount
-	idxService.ReceivedRatingAverage = service.ReceivedRatingAverage
+	indexSyncEvent, err := types.NewOutboxServiceIndexSync(service.ID)
+	if err != nil {
+		return err
+	}
 
-	err = s.serviceIndexRepo.Update(ctx, idxService, seqNo, primaryTerm)
+	err = s.outboxSvc.CreateTx(ctx, tx, indexSyncEvent)
 	if err != nil {
 		return err
 	}
@@ -324,5 +372,7 @@ func (s *consumerServiceImpl) CreateFeedback(ctx context.Context, req types.Cons
 		return errors.New(err)
 	}
 
+	s.outboxSvc.Dispatch(ctx, indexSyncEvent)
+
 	return nil
 }
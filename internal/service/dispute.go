@@ -0,0 +1,601 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"kelarin/internal/repository"
+	"kelarin/internal/types"
+	dbUtil "kelarin/internal/utils/dbutil"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-errors/errors"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"github.com/shopspring/decimal"
+	"github.com/volatiletech/null/v9"
+)
+
+type Dispute interface {
+	ConsumerCreate(ctx context.Context, req types.DisputeConsumerCreateReq) error
+	ConsumerGetAll(ctx context.Context, req types.DisputeConsumerGetAllReq) ([]types.DisputeGetRes, types.PaginationRes, error)
+	ConsumerGetByID(ctx context.Context, req types.DisputeGetByIDReq) (types.DisputeGetRes, error)
+
+	ProviderGetAll(ctx context.Context, req types.DisputeProviderGetAllReq) ([]types.DisputeGetRes, types.PaginationRes, error)
+	ProviderGetByID(ctx context.Context, req types.DisputeGetByIDReq) (types.DisputeGetRes, error)
+	ProviderRespond(ctx context.Context, req types.DisputeProviderRespondReq) error
+
+	AdminGetAll(ctx context.Context, req types.DisputeAdminGetAllReq) ([]types.DisputeGetRes, types.PaginationRes, error)
+	AdminGetByID(ctx context.Context, req types.DisputeGetByIDReq) (types.DisputeGetRes, error)
+	AdminResolve(ctx context.Context, req types.DisputeAdminResolveReq) error
+}
+
+type disputeImpl struct {
+	beginMainDBTx                   dbUtil.SqlxTx
+	disputeRepo                     repository.Dispute
+	orderRepo                       repository.Order
+	serviceProviderRepo             repository.ServiceProvider
+	refundRepo                      repository.Refund
+	refundSvc                       Refund
+	serviceProviderCreditSvc        ServiceProviderCredit
+	fileSvc                         File
+	consumerNotificationRepo        repository.ConsumerNotification
+	serviceProviderNotificationRepo repository.ServiceProviderNotification
+	outboxSvc                       Outbox
+}
+
+func NewDispute(
+	beginMainDBTx dbUtil.SqlxTx,
+	disputeRepo repository.Dispute,
+	orderRepo repository.Order,
+	serviceProviderRepo repository.ServiceProvider,
+	refundRepo repository.Refund,
+	refundSvc Refund,
+	serviceProviderCreditSvc ServiceProviderCredit,
+	fileSvc File,
+	consumerNotificationRepo repository.ConsumerNotification,
+	serviceProviderNotificationRepo repository.ServiceProviderNotification,
+	outboxSvc Outbox,
+) Dispute {
+	return &disputeImpl{
+		beginMainDBTx:                   beginMainDBTx,
+		disputeRepo:                     disputeRepo,
+		orderRepo:                       orderRepo,
+		serviceProviderRepo:             serviceProviderRepo,
+		refundRepo:                      refundRepo,
+		refundSvc:                       refundSvc,
+		serviceProviderCreditSvc:        serviceProviderCreditSvc,
+		fileSvc:                         fileSvc,
+		consumerNotificationRepo:        consumerNotificationRepo,
+		serviceProviderNotificationRepo: serviceProviderNotificationRepo,
+		outboxSvc:                       outboxSvc,
+	}
+}
+
+func (s *disputeImpl) ConsumerCreate(ctx context.Context, req types.DisputeConsumerCreateReq) error {
+	if err := req.Validate(); err != nil {
+		return err
+	}
+
+	order, err := s.orderRepo.FindByIDAndUserID(ctx, req.OrderID, req.AuthUser.ID)
+	if errors.Is(err, types.ErrNoData) {
+		return errors.New(types.AppErr{Code: http.StatusNotFound, Message: "order not found"})
+	} else if err != nil {
+		return err
+	}
+
+	if order.Status != types.OrderStatusFinished {
+		return errors.New(types.AppErr{Code: http.StatusForbidden, Message: "only finished orders can be disputed"})
+	}
+
+	// updated_at is set when the order is finished
+	if !order.UpdatedAt.Valid || time.Since(order.UpdatedAt.Time) > types.DisputeOpenWindow {
+		return errors.New(types.AppErr{Code: http.StatusForbidden, Message: "the dispute window of this order has passed"})
+	}
+
+	_, err = s.disputeRepo.FindByOrderID(ctx, order.ID)
+	if err == nil {
+		return errors.New(types.AppErr{Code: http.StatusConflict, Message: "order already disputed"})
+	} else if !errors.Is(err, types.ErrNoData) {
+		return err
+	}
+
+	provider, err := s.serviceProviderRepo.FindByID(ctx, order.ServiceProviderID)
+	if errors.Is(err, types.ErrNoData) {
+		return errors.Errorf("service provider not found: id %s", order.ServiceProviderID)
+	} else if err != nil {
+		return err
+	}
+
+	tempFiles := []types.TempFile{}
+	for _, img := range req.EvidenceImages {
+		file, err := s.fileSvc.GetTemp(ctx, img)
+		if err != nil {
+			return err
+		}
+
+		tempFiles = append(tempFiles, types.TempFile(file))
+	}
+
+	evidenceImages := []string{}
+	if len(tempFiles) > 0 {
+		evidenceImages, err = s.fileSvc.BulkUploadToS3(ctx, tempFiles, types.DisputeEvidenceImageDir)
+		if err != nil {
+			return err
+		}
+	}
+
+	timeNow := time.Now()
+
+	id, err := uuid.NewV7()
+	if err != nil {
+		return errors.New(err)
+	}
+	dispute := types.Dispute{
+		ID:                id,
+		OrderID:           order.ID,
+		UserID:            order.UserID,
+		ServiceProviderID: order.ServiceProviderID,
+		Reason:            req.Reason,
+		EvidenceImages:    evidenceImages,
+		Status:            types.DisputeStatusOpen,
+		CreatedAt:         timeNow,
+	}
+
+	id, err = uuid.NewV7()
+	if err != nil {
+		return errors.New(err)
+	}
+	providerNotif := types.ServiceProviderNotification{
+		ID:                id,
+		ServiceProviderID: order.ServiceProviderID,
+		OrderID:           uuid.NullUUID{UUID: order.ID, Valid: true},
+		DisputeID:         uuid.NullUUID{UUID: dispute.ID, Valid: true},
+		Type:              types.ServiceProviderNotificationTypeDisputeOpened,
+		CreatedAt:         timeNow,
+	}
+
+	tx, err := s.beginMainDBTx(ctx, nil)
+	if err != nil {
+		return errors.New(err)
+	}
+
+	defer tx.Rollback()
+
+	if err = s.disputeRepo.CreateTx(ctx, tx, dispute); err != nil {
+		return err
+	}
+
+	if err = s.serviceProviderNotificationRepo.CreateTx(ctx, tx, providerNotif); err != nil {
+		return err
+	}
+
+	pushEvent, err := types.NewOutboxPushNotification(provider.UserID, int16(providerNotif.Type), fmt.Sprintf("%s opened a dispute", order.UserName), req.Reason)
+	if err != nil {
+		return err
+	}
+
+	if err = s.outboxSvc.CreateTx(ctx, tx, pushEvent); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return errors.New(err)
+	}
+
+	s.outboxSvc.Dispatch(ctx, pushEvent)
+
+	return nil
+}
+
+func (s *disputeImpl) ConsumerGetAll(ctx context.Context, req types.DisputeConsumerGetAllReq) ([]types.DisputeGetRes, types.PaginationRes, error) {
+	if err := req.Validate(); err != nil {
+		return []types.DisputeGetRes{}, types.PaginationRes{}, err
+	}
+
+	return s.getAll(ctx, &req.PaginationReq, types.DisputeFilter{
+		UserID: uuid.NullUUID{UUID: req.AuthUser.ID, Valid: true},
+		Status: req.Status,
+	})
+}
+
+func (s *disputeImpl) ConsumerGetByID(ctx context.Context, req types.DisputeGetByIDReq) (types.DisputeGetRes, error) {
+	if err := req.Validate(); err != nil {
+		return types.DisputeGetRes{}, err
+	}
+
+	return s.getByID(ctx, req.ID, func(dispute types.DisputeWithRelations) bool {
+		return dispute.UserID == req.AuthUser.ID
+	})
+}
+
+func (s *disputeImpl) ProviderGetAll(ctx context.Context, req types.DisputeProviderGetAllReq) ([]types.DisputeGetRes, types.PaginationRes, error) {
+	if err := req.Validate(); err != nil {
+		return []types.DisputeGetRes{}, types.PaginationRes{}, err
+	}
+
+	provider, err := s.findProvider(ctx, req.AuthUser.ID)
+	if err != nil {
+		return []types.DisputeGetRes{}, types.PaginationRes{}, err
+	}
+
+	return s.getAll(ctx, &req.PaginationReq, types.DisputeFilter{
+		ServiceProviderID: uuid.NullUUID{UUID: provider.ID, Valid: true},
+		Status:            req.Status,
+	})
+}
+
+func (s *disputeImpl) ProviderGetByID(ctx context.Context, req types.DisputeGetByIDReq) (types.DisputeGetRes, error) {
+	if err := req.Validate(); err != nil {
+		return types.DisputeGetRes{}, err
+	}
+
+	return s.getByID(ctx, req.ID, func(dispute types.DisputeWithRelations) bool {
+		return dispute.ServiceProviderUserID == req.AuthUser.ID
+	})
+}
+
+func (s *disputeImpl) ProviderRespond(ctx context.Context, req types.DisputeProviderRespondReq) error {
+	if err := req.Validate(); err != nil {
+		return err
+	}
+
+	provider, err := s.findProvider(ctx, req.AuthUser.ID)
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.beginMainDBTx(ctx, nil)
+	if err != nil {
+		return errors.New(err)
+	}
+
+	defer tx.Rollback()
+
+	dispute, err := s.disputeRepo.FindForUpdateByID(ctx, tx, req.ID)
+	if errors.Is(err, types.ErrNoData) {
+		return errors.New(types.AppErr{Code: http.StatusNotFound, Message: "dispute not found"})
+	} else if err != nil {
+		return err
+	}
+
+	if dispute.ServiceProviderID != provider.ID {
+		return errors.New(types.AppErr{Code: http.StatusNotFound, Message: "dispute not found"})
+	}
+
+	if dispute.Status != types.DisputeStatusOpen {
+		return errors.New(types.AppErr{Code: http.StatusConflict, Message: "dispute already responded"})
+	}
+
+	timeNow := time.Now()
+
+	dispute.Status = types.DisputeStatusResponded
+	dispute.ProviderResponse = null.StringFrom(req.Response)
+	dispute.ProviderRespondedAt = null.TimeFrom(timeNow)
+	dispute.UpdatedAt = null.TimeFrom(timeNow)
+	if err = s.disputeRepo.UpdateResponseTx(ctx, tx, dispute); err != nil {
+		return err
+	}
+
+	id, err := uuid.NewV7()
+	if err != nil {
+		return errors.New(err)
+	}
+	consumerNotif := types.ConsumerNotification{
+		ID:        id,
+		UserID:    dispute.UserID,
+		OrderID:   uuid.NullUUID{UUID: dispute.OrderID, Valid: true},
+		DisputeID: uuid.NullUUID{UUID: dispute.ID, Valid: true},
+		Type:      types.ConsumerNotificationTypeDisputeResponded,
+		CreatedAt: timeNow,
+	}
+
+	if err = s.consumerNotificationRepo.CreateTx(ctx, tx, consumerNotif); err != nil {
+		return err
+	}
+
+	pushEvent, err := types.NewOutboxPushNotification(dispute.UserID, int16(consumerNotif.Type), fmt.Sprintf("%s responded to your dispute", provider.Name), req.Response)
+	if err != nil {
+		return err
+	}
+
+	if err = s.outboxSvc.CreateTx(ctx, tx, pushEvent); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return errors.New(err)
+	}
+
+	s.outboxSvc.Dispatch(ctx, pushEvent)
+
+	return nil
+}
+
+func (s *disputeImpl) AdminGetAll(ctx context.Context, req types.DisputeAdminGetAllReq) ([]types.DisputeGetRes, types.PaginationRes, error) {
+	if err := req.Validate(); err != nil {
+		return []types.DisputeGetRes{}, types.PaginationRes{}, err
+	}
+
+	return s.getAll(ctx, &req.PaginationReq, types.DisputeFilter{
+		Status: req.Status,
+	})
+}
+
+func (s *disputeImpl) AdminGetByID(ctx context.Context, req types.DisputeGetByIDReq) (types.DisputeGetRes, error) {
+	if err := req.Validate(); err != nil {
+		return types.DisputeGetRes{}, err
+	}
+
+	return s.getByID(ctx, req.ID, func(dispute types.DisputeWithRelations) bool {
+		return true
+	})
+}
+
+// AdminResolve closes the dispute. A refund outcome takes the refunded amount back from the service provider credit
+// and sends it to the consumer through a refund of the order payment, the credit can not go negative so a refund
+// larger than the credit left after a payout is rejected
+func (s *disputeImpl) AdminResolve(ctx context.Context, req types.DisputeAdminResolveReq) error {
+	if err := req.Validate(); err != nil {
+		return err
+	}
+
+	tx, err := s.beginMainDBTx(ctx, nil)
+	if err != nil {
+		return errors.New(err)
+	}
+
+	defer tx.Rollback()
+
+	dispute, err := s.disputeRepo.FindForUpdateByID(ctx, tx, req.ID)
+	if errors.Is(err, types.ErrNoData) {
+		return errors.New(types.AppErr{Code: http.StatusNotFound, Message: "dispute not found"})
+	} else if err != nil {
+		return err
+	}
+
+	if dispute.Status == types.DisputeStatusResolved {
+		return errors.New(types.AppErr{Code: http.StatusConflict, Message: "dispute already resolved"})
+	}
+
+	order, err := s.orderRepo.FindForUpdateByID(ctx, tx, dispute.OrderID)
+	if errors.Is(err, types.ErrNoData) {
+		return errors.Errorf("order not found: id %s", dispute.OrderID)
+	} else if err != nil {
+		return err
+	}
+
+	var refundAmount decimal.Decimal
+	switch req.Outcome {
+	case types.DisputeOutcomeRefund:
+		refundAmount = order.ServiceFee
+	case types.DisputeOutcomePartialRefund:
+		if !req.RefundAmount.LessThan(order.ServiceFee) {
+			return errors.New(types.AppErr{Code: http.StatusBadRequest, Message: "refund_amount must be less than the service fee, use the refund outcome instead"})
+		}
+
+		refundAmount = req.RefundAmount.RoundFloor(0)
+	}
+
+	timeNow := time.Now()
+
+	var refund types.Refund
+	if refundAmount.IsPositive() {
+		err = s.serviceProviderCreditSvc.AddTx(ctx, types.ServiceProviderCreditAddReq{
+			Tx:                tx,
+			ServiceProviderID: dispute.ServiceProviderID,
+			Amount:            refundAmount.Neg(),
+			Type:              types.ServiceProviderCreditLedgerTypeAdjustment,
+			OrderID:           uuid.NullUUID{UUID: order.ID, Valid: true},
+			Note:              null.StringFrom("dispute refund"),
+		})
+		if errors.Is(err, types.ErrServiceProviderInsufficientCredit) {
+			return errors.New(types.AppErr{Code: http.StatusConflict, Message: "the service provider credit is too low to take back the refund amount"})
+		} else if err != nil {
+			return err
+		}
+
+		id, err := uuid.NewV7()
+		if err != nil {
+			return errors.New(err)
+		}
+		refund = types.Refund{
+			ID:        id,
+			OrderID:   order.ID,
+			PaymentID: order.PaymentID.UUID,
+			Amount:    refundAmount,
+			Reason:    req.Note,
+			Status:    types.RefundStatusPending,
+			CreatedAt: timeNow,
+		}
+
+		if err = s.refundRepo.CreateTx(ctx, tx, refund); err != nil {
+			return err
+		}
+
+		dispute.RefundAmount = decimal.NewNullDecimal(refundAmount)
+	}
+
+	dispute.Status = types.DisputeStatusResolved
+	dispute.Outcome = null.StringFrom(string(req.Outcome))
+	dispute.AdminUserID = uuid.NullUUID{UUID: req.AuthUser.ID, Valid: true}
+	dispute.AdminNote = null.StringFrom(req.Note)
+	dispute.ResolvedAt = null.TimeFrom(timeNow)
+	dispute.UpdatedAt = null.TimeFrom(timeNow)
+	if err = s.disputeRepo.UpdateResolutionTx(ctx, tx, dispute); err != nil {
+		return err
+	}
+
+	id, err := uuid.NewV7()
+	if err != nil {
+		return errors.New(err)
+	}
+	consumerNotif := types.ConsumerNotification{
+		ID:        id,
+		UserID:    dispute.UserID,
+		OrderID:   uuid.NullUUID{UUID: dispute.OrderID, Valid: true},
+		DisputeID: uuid.NullUUID{UUID: dispute.ID, Valid: true},
+		Type:      types.ConsumerNotificationTypeDisputeResolved,
+		CreatedAt: timeNow,
+	}
+
+	id, err = uuid.NewV7()
+	if err != nil {
+		return errors.New(err)
+	}
+	providerNotif := types.ServiceProviderNotification{
+		ID:                id,
+		ServiceProviderID: dispute.ServiceProviderID,
+		OrderID:           uuid.NullUUID{UUID: dispute.OrderID, Valid: true},
+		DisputeID:         uuid.NullUUID{UUID: dispute.ID, Valid: true},
+		Type:              types.ServiceProviderNotificationTypeDisputeResolved,
+		CreatedAt:         timeNow,
+	}
+
+	if err = s.consumerNotificationRepo.CreateTx(ctx, tx, consumerNotif); err != nil {
+		return err
+	}
+
+	if err = s.serviceProviderNotificationRepo.CreateTx(ctx, tx, providerNotif); err != nil {
+		return err
+	}
+
+	provider, err := s.serviceProviderRepo.FindByID(ctx, dispute.ServiceProviderID)
+	if err != nil {
+		return err
+	}
+
+	consumerPushEvent, err := types.NewOutboxPushNotification(dispute.UserID, int16(consumerNotif.Type), "Your dispute has been resolved", req.Note)
+	if err != nil {
+		return err
+	}
+
+	providerPushEvent, err := types.NewOutboxPushNotification(provider.UserID, int16(providerNotif.Type), "A dispute on your order has been resolved", req.Note)
+	if err != nil {
+		return err
+	}
+
+	if err = s.outboxSvc.CreateTx(ctx, tx, consumerPushEvent, providerPushEvent); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return errors.New(err)
+	}
+
+	s.outboxSvc.Dispatch(ctx, consumerPushEvent, providerPushEvent)
+
+	// the dispute stays resolved when midtrans fails, the refund is kept as failed so an admin can retry it
+	if refund.ID != uuid.Nil {
+		if err = s.refundSvc.Process(ctx, refund.ID); err != nil {
+			log.Error().Stack().Err(err).Str("refund_id", refund.ID.String()).Send()
+		}
+	}
+
+	return nil
+}
+
+func (s *disputeImpl) getAll(ctx context.Context, req *types.PaginationReq, filter types.DisputeFilter) ([]types.DisputeGetRes, types.PaginationRes, error) {
+	res := []types.DisputeGetRes{}
+	paginationRes := types.PaginationRes{}
+
+	if err := req.ValidateAndNormalize(); err != nil {
+		return res, paginationRes, err
+	}
+
+	page, err := strconv.Atoi(req.Page)
+	if err != nil {
+		return res, paginationRes, errors.New(err)
+	}
+
+	size, err := strconv.Atoi(req.Size)
+	if err != nil {
+		return res, paginationRes, errors.New(err)
+	}
+
+	filter.Limit = size
+	filter.Offset = (page - 1) * size
+
+	disputes, totalItem, err := s.disputeRepo.FindAllByFilter(ctx, filter)
+	if err != nil {
+		return res, paginationRes, err
+	}
+
+	for _, dispute := range disputes {
+		item, err := s.toGetRes(ctx, dispute)
+		if err != nil {
+			return res, paginationRes, err
+		}
+
+		res = append(res, item)
+	}
+
+	paginationRes = req.GeneratePaginationResponse(totalItem)
+
+	return res, paginationRes, nil
+}
+
+// getByID returns not found when the dispute does not belong to the requester, checked by allowed
+func (s *disputeImpl) getByID(ctx context.Context, ID uuid.UUID, allowed func(dispute types.DisputeWithRelations) bool) (types.DisputeGetRes, error) {
+	dispute, err := s.disputeRepo.FindByID(ctx, ID)
+	if errors.Is(err, types.ErrNoData) {
+		return types.DisputeGetRes{}, errors.New(types.AppErr{Code: http.StatusNotFound, Message: "dispute not found"})
+	} else if err != nil {
+		return types.DisputeGetRes{}, err
+	}
+
+	if !allowed(dispute) {
+		return types.DisputeGetRes{}, errors.New(types.AppErr{Code: http.StatusNotFound, Message: "dispute not found"})
+	}
+
+	return s.toGetRes(ctx, dispute)
+}
+
+func (s *disputeImpl) toGetRes(ctx context.Context, dispute types.DisputeWithRelations) (types.DisputeGetRes, error) {
+	evidenceImageURLs := []string{}
+	for _, img := range dispute.EvidenceImages {
+		url, err := s.fileSvc.GetS3PresignedURL(ctx, img)
+		if err != nil {
+			return types.DisputeGetRes{}, err
+		}
+
+		evidenceImageURLs = append(evidenceImageURLs, url)
+	}
+
+	return types.DisputeGetRes{
+		ID:              dispute.ID,
+		OrderID:         dispute.OrderID,
+		OrderServiceFee: dispute.OrderServiceFee,
+		User: types.DisputeGetResUser{
+			ID:   dispute.UserID,
+			Name: dispute.UserName,
+		},
+		ServiceProvider: types.DisputeGetResServiceProvider{
+			ID:   dispute.ServiceProviderID,
+			Name: dispute.ServiceProviderName,
+		},
+		Reason:              dispute.Reason,
+		EvidenceImageURLs:   evidenceImageURLs,
+		Status:              dispute.Status,
+		ProviderResponse:    dispute.ProviderResponse,
+		ProviderRespondedAt: dispute.ProviderRespondedAt,
+		Outcome:             dispute.Outcome,
+		RefundAmount:        dispute.RefundAmount,
+		AdminNote:           dispute.AdminNote,
+		ResolvedAt:          dispute.ResolvedAt,
+		CreatedAt:           dispute.CreatedAt,
+		UpdatedAt:           dispute.UpdatedAt,
+	}, nil
+}
+
+func (s *disputeImpl) findProvider(ctx context.Context, userID uuid.UUID) (types.ServiceProvider, error) {
+	provider, err := s.serviceProviderRepo.FindByUserID(ctx, userID)
+	if errors.Is(err, types.ErrNoData) {
+		return provider, errors.Errorf("service provider not found: user_id %s", userID)
+	} else if err != nil {
+		return provider, err
+	}
+
+	return provider, nil
+}
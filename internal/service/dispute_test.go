@@ -0,0 +1,86 @@
+package service_test
+
+import (
+	"context"
+	repoMock "kelarin/internal/mocks/repository"
+	serviceMock "kelarin/internal/mocks/service"
+	"kelarin/internal/service"
+	"kelarin/internal/types"
+	dbUtil "kelarin/internal/utils/dbutil"
+	"net/http"
+	"testing"
+
+	"github.com/go-errors/errors"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	sqlxmock "github.com/zhashkevych/go-sqlxmock"
+)
+
+func TestDisputeService(t *testing.T) {
+	db, dbMock, err := sqlxmock.Newx()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	defer db.Close()
+
+	ctx := context.Background()
+
+	disputeRepo := repoMock.NewDispute(t)
+	orderRepo := repoMock.NewOrder(t)
+	serviceProviderCreditSvc := serviceMock.NewServiceProviderCredit(t)
+
+	disputeService := service.NewDispute(
+		dbUtil.NewSqlxTx(db),
+		disputeRepo,
+		orderRepo,
+		repoMock.NewServiceProvider(t),
+		repoMock.NewRefund(t),
+		serviceMock.NewRefund(t),
+		serviceProviderCreditSvc,
+		serviceMock.NewFile(t),
+		repoMock.NewConsumerNotification(t),
+		repoMock.NewServiceProviderNotification(t),
+		serviceMock.NewOutbox(t),
+	)
+
+	t.Run("Test AdminResolve rejects a refund larger than the service provider credit", func(t *testing.T) {
+		dispute := types.Dispute{
+			ID:                uuid.New(),
+			OrderID:           uuid.New(),
+			ServiceProviderID: uuid.New(),
+			Status:            types.DisputeStatusOpen,
+		}
+		order := types.Order{
+			ID:         dispute.OrderID,
+			PaymentID:  uuid.NullUUID{UUID: uuid.New(), Valid: true},
+			ServiceFee: decimal.NewFromInt(200000),
+		}
+
+		dbMock.ExpectBegin()
+		dbMock.ExpectRollback()
+
+		disputeRepo.Mock.On("FindForUpdateByID", ctx, mock.Anything, dispute.ID).Return(dispute, nil)
+		orderRepo.Mock.On("FindForUpdateByID", ctx, mock.Anything, order.ID).Return(order, nil)
+		serviceProviderCreditSvc.Mock.On("AddTx", ctx, mock.MatchedBy(func(req types.ServiceProviderCreditAddReq) bool {
+			return req.ServiceProviderID == dispute.ServiceProviderID && req.Amount.Equal(order.ServiceFee.Neg())
+		})).Return(types.ErrServiceProviderInsufficientCredit)
+
+		err := disputeService.AdminResolve(ctx, types.DisputeAdminResolveReq{
+			AuthUser: types.AuthUser{ID: uuid.New(), Role: types.UserRoleAdmin},
+			ID:       dispute.ID,
+			Outcome:  types.DisputeOutcomeRefund,
+			Note:     "the service was not delivered",
+		})
+
+		appErr := types.AppErr{}
+		if assert.True(t, errors.As(err, &appErr), "expected an AppErr, got %v", err) {
+			assert.Equal(t, http.StatusConflict, appErr.Code)
+			assert.Contains(t, appErr.Message, "credit is too low")
+		}
+
+		assert.NoError(t, dbMock.ExpectationsWereMet())
+	})
+}
@@ -4,15 +4,22 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"image"
+	"image/jpeg"
+	_ "image/png"
 	"io"
 	"kelarin/internal/config"
 	"kelarin/internal/queue/task"
 	"kelarin/internal/repository"
 	"kelarin/internal/types"
+	imageUtil "kelarin/internal/utils/image_util"
 	pkg "kelarin/pkg/utils"
+	"mime/multipart"
 	"net/http"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -22,13 +29,16 @@ import (
 	"github.com/go-errors/errors"
 	"github.com/redis/go-redis/v9"
 	"github.com/rs/zerolog/log"
+	"github.com/volatiletech/null/v9"
 )
 
 type File interface {
 	StoreTemp(ctx context.Context, req types.FileUploadImagesReq) (types.FileUploadFilesRes, error)
+	StoreChatAttachmentTemp(ctx context.Context, req types.FileUploadChatAttachmentReq) (types.FileUploadChatAttachmentRes, error)
 	GetTemp(ctx context.Context, fileName string) (types.FileGetTempRes, error)
 	DeleteTemp(ctx context.Context, fileName string) error
 	BulkUploadToS3(ctx context.Context, req []types.TempFile, dir string) ([]string, error)
+	UploadChatAttachment(ctx context.Context, fileKey string, contentType types.ChatMessageContentType) (types.FileChatAttachment, error)
 	GetS3PresignedURL(ctx context.Context, objectKey string) (string, error)
 	DeleteS3Object(ctx context.Context, objectKey string) error
 }
@@ -86,21 +96,9 @@ func (r *fileImpl) StoreTemp(ctx context.Context, req types.FileUploadImagesReq)
 			})
 		}
 
-		src, err := file.Open()
+		srcBinary, mimeType, err := readUploadedFile(file)
 		if err != nil {
-			return res, errors.New(err)
-		}
-		defer src.Close()
-
-		srcBinary, err := io.ReadAll(src)
-		if err != nil {
-			return res, errors.New(err)
-		}
-
-		reader := bytes.NewReader(srcBinary)
-		mimeType, err := mimetype.DetectReader(reader)
-		if err != nil {
-			return res, errors.New(err)
+			return res, err
 		}
 
 		if _, ok := allowedMimeTypesMap[mimeType.String()]; !ok {
@@ -110,27 +108,13 @@ func (r *fileImpl) StoreTemp(ctx context.Context, req types.FileUploadImagesReq)
 			})
 		}
 
-		fileName := pkg.GenerateUniqueFileName(file.Filename)
-		filePath := filepath.Join(types.TempFileDir, fileName)
-		err = os.WriteFile(filePath, srcBinary, 0644)
-		if err != nil {
-			return res, errors.New(err)
-		}
-
-		fileMeta := types.FileTemp{
-			Name:       fileName,
-			Expiration: expiration,
-		}
-
-		// add to queue to delete temp file after expiration
-		defaultQueueName := types.GetQueueName(types.QueuePriorityDefault, r.cfg.Environment)
-		err = r.tempFileTask.Delete(ctx, defaultQueueName, fileMeta, expiration)
+		fileMeta, err := r.writeTemp(ctx, file.Filename, srcBinary, expiration)
 		if err != nil {
-			return res, errors.New(err)
+			return res, err
 		}
 
 		storedFiles = append(storedFiles, fileMeta)
-		res.FileKeys = append(res.FileKeys, fileName)
+		res.FileKeys = append(res.FileKeys, fileMeta.Name)
 	}
 
 	err = r.fileRepo.SetTemp(ctx, storedFiles)
@@ -141,6 +125,100 @@ func (r *fileImpl) StoreTemp(ctx context.Context, req types.FileUploadImagesReq)
 	return res, nil
 }
 
+// StoreChatAttachmentTemp stores an image or a video for a chat message, the content type of the message is detected
+// from the content of the file and the size limit depends on it
+func (r *fileImpl) StoreChatAttachmentTemp(ctx context.Context, req types.FileUploadChatAttachmentReq) (types.FileUploadChatAttachmentRes, error) {
+	res := types.FileUploadChatAttachmentRes{}
+
+	// the file is read before its type is known, so a file larger than every limit is rejected right away
+	if req.File.Size > max(r.cfg.File.UploadedImageFileSizeLimit, r.cfg.File.UploadedVideoFileSizeLimit) {
+		return res, errors.New(types.AppErr{
+			Code:    http.StatusRequestEntityTooLarge,
+			Message: fmt.Sprintf("file size is too large, allowed max file size %s for images and %s for videos", r.cfg.File.MaxUploadedImageFileSize, r.cfg.File.MaxUploadedVideoFileSize),
+		})
+	}
+
+	srcBinary, mimeType, err := readUploadedFile(req.File)
+	if err != nil {
+		return res, err
+	}
+
+	var sizeLimit int64
+	var maxSize string
+	switch {
+	case slices.Contains(r.cfg.File.AllowedImageTypes, mimeType.String()):
+		res.ContentType = types.ChatMessageContentTypeImage
+		sizeLimit, maxSize = r.cfg.File.UploadedImageFileSizeLimit, r.cfg.File.MaxUploadedImageFileSize
+	case slices.Contains(r.cfg.File.AllowedVideoTypes, mimeType.String()):
+		res.ContentType = types.ChatMessageContentTypeVideo
+		sizeLimit, maxSize = r.cfg.File.UploadedVideoFileSizeLimit, r.cfg.File.MaxUploadedVideoFileSize
+	default:
+		return res, errors.New(types.AppErr{
+			Code:    http.StatusUnsupportedMediaType,
+			Message: fmt.Sprintf("file type %s is not allowed", mimeType.Extension()),
+		})
+	}
+
+	if req.File.Size > sizeLimit {
+		return res, errors.New(types.AppErr{
+			Code:    http.StatusRequestEntityTooLarge,
+			Message: fmt.Sprintf("file size is too large, allowed max %s file size %s", res.ContentType, maxSize),
+		})
+	}
+
+	expiration := time.Until(time.Now().Add(r.cfg.File.TempFileExpiration))
+	fileMeta, err := r.writeTemp(ctx, req.File.Filename, srcBinary, expiration)
+	if err != nil {
+		return res, err
+	}
+
+	if err = r.fileRepo.SetTemp(ctx, []types.FileTemp{fileMeta}); err != nil {
+		os.Remove(filepath.Join(types.TempFileDir, fileMeta.Name))
+		return res, err
+	}
+
+	res.FileKey = fileMeta.Name
+
+	return res, nil
+}
+
+// readUploadedFile detects the mime type from the content of the file, the extension sent by the client is not trusted
+func readUploadedFile(file *multipart.FileHeader) ([]byte, *mimetype.MIME, error) {
+	src, err := file.Open()
+	if err != nil {
+		return nil, nil, errors.New(err)
+	}
+	defer src.Close()
+
+	srcBinary, err := io.ReadAll(src)
+	if err != nil {
+		return nil, nil, errors.New(err)
+	}
+
+	return srcBinary, mimetype.Detect(srcBinary), nil
+}
+
+// writeTemp writes the file to the temp dir and queues its deletion once it expires
+func (r *fileImpl) writeTemp(ctx context.Context, originalName string, srcBinary []byte, expiration time.Duration) (types.FileTemp, error) {
+	fileName := pkg.GenerateUniqueFileName(originalName)
+	if err := os.WriteFile(filepath.Join(types.TempFileDir, fileName), srcBinary, 0644); err != nil {
+		return types.FileTemp{}, errors.New(err)
+	}
+
+	fileMeta := types.FileTemp{
+		Name:       fileName,
+		Expiration: expiration,
+	}
+
+	// add to queue to delete temp file after expiration
+	defaultQueueName := types.GetQueueName(types.QueuePriorityDefault, r.cfg.Environment)
+	if err := r.tempFileTask.Delete(ctx, defaultQueueName, fileMeta, expiration); err != nil {
+		return fileMeta, errors.New(err)
+	}
+
+	return fileMeta, nil
+}
+
 func (r *fileImpl) GetTemp(ctx context.Context, fileName string) (types.FileGetTempRes, error) {
 	res := types.FileGetTempRes{}
 
@@ -220,6 +298,116 @@ func (r *fileImpl) BulkUploadToS3(ctx context.Context, req []types.TempFile, dir
 	return res, nil
 }
 
+// UploadChatAttachment moves the temp file of a chat message to s3 after checking it matches the content type of the
+// message, an image also gets a jpeg thumbnail
+func (r *fileImpl) UploadChatAttachment(ctx context.Context, fileKey string, contentType types.ChatMessageContentType) (types.FileChatAttachment, error) {
+	res := types.FileChatAttachment{}
+
+	tempFile, err := r.GetTemp(ctx, fileKey)
+	if err != nil {
+		return res, err
+	}
+
+	tempFilePath := filepath.Join(types.TempFileDir, tempFile.Name)
+	mimeType, err := mimetype.DetectFile(tempFilePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return res, errors.New(types.AppErr{
+			Code:    http.StatusNotFound,
+			Message: fmt.Sprintf("file %s not found", fileKey),
+		})
+	} else if err != nil {
+		return res, errors.New(err)
+	}
+
+	allowedMimeTypes, dir := r.cfg.File.AllowedImageTypes, types.ChatImageDir
+	if contentType == types.ChatMessageContentTypeVideo {
+		allowedMimeTypes, dir = r.cfg.File.AllowedVideoTypes, types.ChatVideoDir
+	}
+
+	if !slices.Contains(allowedMimeTypes, mimeType.String()) {
+		return res, errors.New(types.AppErr{
+			Code:    http.StatusUnsupportedMediaType,
+			Message: fmt.Sprintf("file %s is not a %s", fileKey, contentType),
+		})
+	}
+
+	objectKeys, err := r.BulkUploadToS3(ctx, []types.TempFile{{Name: tempFile.Name}}, dir)
+	if err != nil {
+		return res, err
+	}
+
+	res.ObjectKey = objectKeys[0]
+
+	if contentType == types.ChatMessageContentTypeImage {
+		thumbnailKey, err := r.uploadThumbnail(ctx, tempFilePath, types.ChatImageThumbnailDir)
+		if err != nil {
+			if err := r.DeleteS3Object(ctx, res.ObjectKey); err != nil {
+				log.Error().Stack().Err(err).Send()
+			}
+
+			return res, err
+		}
+
+		res.ThumbnailKey = null.StringFrom(thumbnailKey)
+	}
+
+	if err := r.DeleteTemp(ctx, fileKey); err != nil {
+		log.Error().Stack().Err(err).Send()
+	}
+
+	return res, nil
+}
+
+// uploadThumbnail always encodes the thumbnail as jpeg, whatever the type of the image is
+func (r *fileImpl) uploadThumbnail(ctx context.Context, imagePath, dir string) (string, error) {
+	src, err := os.Open(imagePath)
+	if err != nil {
+		return "", errors.New(err)
+	}
+	defer src.Close()
+
+	imgCfg, _, err := image.DecodeConfig(src)
+	if err != nil {
+		return "", errors.New(err)
+	}
+
+	if int64(imgCfg.Width)*int64(imgCfg.Height) > types.ChatImageMaxPixels {
+		return "", errors.New(types.AppErr{
+			Code:    http.StatusRequestEntityTooLarge,
+			Message: fmt.Sprintf("image dimensions are too large, allowed max %d pixels", types.ChatImageMaxPixels),
+		})
+	}
+
+	if _, err := src.Seek(0, io.SeekStart); err != nil {
+		return "", errors.New(err)
+	}
+
+	img, _, err := image.Decode(src)
+	if err != nil {
+		return "", errors.New(err)
+	}
+
+	buf := &bytes.Buffer{}
+	if err := jpeg.Encode(buf, imageUtil.Thumbnail(img, types.ChatThumbnailMaxSize), &jpeg.Options{Quality: 80}); err != nil {
+		return "", errors.New(err)
+	}
+
+	name := filepath.Base(imagePath)
+	dest := filepath.Join(dir, strings.TrimSuffix(name, filepath.Ext(name))+".jpg")
+
+	uploadRes, err := r.s3Uploader.Upload(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(r.cfg.File.AwsS3Bucket),
+		Key:         aws.String(dest),
+		ContentType: aws.String("image/jpeg"),
+		Body:        buf,
+	})
+	if err != nil {
+		return "", errors.New(err)
+	}
+
+	return *uploadRes.Key, nil
+}
+
 func (r *fileImpl) GetS3PresignedURL(ctx context.Context, objectKey string) (string, error) {
 	req := &s3.GetObjectInput{
 		Bucket: &r.cfg.File.AwsS3Bucket,
@@ -4,19 +4,30 @@ import (
 	"context"
 
 	"github.com/go-errors/errors"
+	"github.com/midtrans/midtrans-go/coreapi"
 	"github.com/midtrans/midtrans-go/snap"
 )
 
 type Midtrans interface {
 	CreateTransaction(ctx context.Context, req *snap.Request) (*snap.Response, error)
+	Refund(ctx context.Context, orderID string, req *coreapi.RefundReq) (*coreapi.RefundResponse, error)
 }
 
 type midtransImpl struct {
-	client *snap.Client
+	client     *snap.Client
+	coreClient *coreapi.Client
 }
 
 func NewMidtrans(client *snap.Client) Midtrans {
-	return &midtransImpl{client}
+	// refunds are only available on the core api, it shares the same credentials with snap
+	coreClient := &coreapi.Client{
+		ServerKey:  client.ServerKey,
+		Env:        client.Env,
+		HttpClient: client.HttpClient,
+		Options:    client.Options,
+	}
+
+	return &midtransImpl{client: client, coreClient: coreClient}
 }
 
 func (s *midtransImpl) CreateTransaction(ctx context.Context, req *snap.Request) (*snap.Response, error) {
@@ -27,3 +38,13 @@ func (s *midtransImpl) CreateTransaction(ctx context.Context, req *snap.Request)
 
 	return res, nil
 }
+
+// Refund refunds a settled transaction, orderID is the order id sent to midtrans when creating the transaction
+func (s *midtransImpl) Refund(ctx context.Context, orderID string, req *coreapi.RefundReq) (*coreapi.RefundResponse, error) {
+	res, err := s.coreClient.RefundTransaction(orderID, req)
+	if err != nil {
+		return res, errors.New(err)
+	}
+
+	return res, nil
+}
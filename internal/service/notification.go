@@ -2,50 +2,90 @@ package service
 
 import (
 	"context"
+	"fmt"
+	"kelarin/internal/config"
+	"kelarin/internal/queue/task"
 	"kelarin/internal/repository"
 	"kelarin/internal/types"
+	emailUtil "kelarin/internal/utils/email_util"
+	ws "kelarin/internal/utils/websocket"
+	"net/url"
 	"time"
 
 	"firebase.google.com/go/messaging"
 	"github.com/go-errors/errors"
+	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
 	"github.com/rs/zerolog/log"
 )
 
 type Notification interface {
 	SendPush(ctx context.Context, req types.NotificationSendReq) error
+	SendPushToUser(ctx context.Context, userID uuid.UUID, req types.NotificationSendReq) error
+	QueueEmailToUser(ctx context.Context, ID uuid.UUID, req types.NotificationSendEmailReq) error
+	SendEmailToUser(ctx context.Context, req types.NotificationSendEmailReq) error
+	QueueAuthEmail(ctx context.Context, req types.NotificationSendAuthEmailReq) error
+	SendAuthEmail(ctx context.Context, req types.NotificationSendAuthEmailReq) error
 	SaveToken(ctx context.Context, req types.NotificationSaveTokenReq) error
+	MoveToken(ctx context.Context, userID, oldSessionID, newSessionID uuid.UUID) error
+	RemoveToken(ctx context.Context, userID, sessionID uuid.UUID) error
+	RemoveAllTokens(ctx context.Context, userID uuid.UUID) error
+	AdminBroadcast(ctx context.Context, req types.NotificationAdminBroadcastReq) error
+	PublishUnreadCount(ctx context.Context, userID uuid.UUID) error
 }
 
 type notificationImpl struct {
-	db              *sqlx.DB
-	messagingClient *messaging.Client
-	fcmTokenRepo    repository.FCMToken
+	cfg                             *config.Config
+	db                              *sqlx.DB
+	messagingClient                 *messaging.Client
+	emailSender                     emailUtil.Sender
+	userRepo                        repository.User
+	fcmTokenRepo                    repository.FCMToken
+	serviceProviderRepo             repository.ServiceProvider
+	serviceProviderAreaRepo         repository.ServiceProviderArea
+	consumerNotificationRepo        repository.ConsumerNotification
+	serviceProviderNotificationRepo repository.ServiceProviderNotification
+	notificationPreferenceRepo      repository.NotificationPreference
+	notificationTask                task.Notification
+	hub                             ws.Hub
 }
 
-func NewNotification(db *sqlx.DB, messagingClient *messaging.Client, fcmTokenRepo repository.FCMToken) Notification {
+func NewNotification(
+	cfg *config.Config,
+	db *sqlx.DB,
+	messagingClient *messaging.Client,
+	emailSender emailUtil.Sender,
+	userRepo repository.User,
+	fcmTokenRepo repository.FCMToken,
+	serviceProviderRepo repository.ServiceProvider,
+	serviceProviderAreaRepo repository.ServiceProviderArea,
+	consumerNotificationRepo repository.ConsumerNotification,
+	serviceProviderNotificationRepo repository.ServiceProviderNotification,
+	notificationPreferenceRepo repository.NotificationPreference,
+	notificationTask task.Notification,
+	hub ws.Hub,
+) Notification {
 	return &notificationImpl{
-		db:              db,
-		messagingClient: messagingClient,
-		fcmTokenRepo:    fcmTokenRepo,
+		cfg:                             cfg,
+		db:                              db,
+		messagingClient:                 messagingClient,
+		emailSender:                     emailSender,
+		userRepo:                        userRepo,
+		fcmTokenRepo:                    fcmTokenRepo,
+		serviceProviderRepo:             serviceProviderRepo,
+		serviceProviderAreaRepo:         serviceProviderAreaRepo,
+		consumerNotificationRepo:        consumerNotificationRepo,
+		serviceProviderNotificationRepo: serviceProviderNotificationRepo,
+		notificationPreferenceRepo:      notificationPreferenceRepo,
+		notificationTask:                notificationTask,
+		hub:                             hub,
 	}
 }
 
 func (s *notificationImpl) SendPush(ctx context.Context, req types.NotificationSendReq) error {
-	_, err := s.messagingClient.Send(ctx, &messaging.Message{
-		Webpush: &messaging.WebpushConfig{
-			Notification: &messaging.WebpushNotification{
-				Title: req.Title,
-				Body:  req.Message,
-				Icon:  req.IconURL,
-				Badge: req.BadgeURL,
-				Image: req.ImageURL,
-			},
-		},
-		Token: req.Token,
-	})
+	_, err := s.messagingClient.Send(ctx, s.buildMessage(req))
 	if messaging.IsInvalidArgument(err) || messaging.IsRegistrationTokenNotRegistered(err) {
-		log.Error().Stack().Err(errors.Errorf("invalid fcm token %s", req.Token))
+		log.Error().Stack().Err(errors.Errorf("invalid fcm token %s", req.Token)).Send()
 		return nil
 	} else if err != nil {
 		return errors.New(err)
@@ -54,25 +94,450 @@ func (s *notificationImpl) SendPush(ctx context.Context, req types.NotificationS
 	return nil
 }
 
+// SendPushToUser sends the push to every device of the user, the token of an unregistered device is removed.
+// An error is returned only when no device received the push so a retry does not notify the same device twice.
+// The push is dropped when the user turned off its type and held back in the queue until the quiet hours of the user end
+func (s *notificationImpl) SendPushToUser(ctx context.Context, userID uuid.UUID, req types.NotificationSendReq) error {
+	enabled, err := s.isEnabled(ctx, userID, req.NotificationType, types.NotificationChannelPush)
+	if err != nil || !enabled {
+		return err
+	}
+
+	quietHours, err := s.notificationPreferenceRepo.FindQuietHoursByUserID(ctx, userID)
+	if !errors.Is(err, types.ErrNoData) && err != nil {
+		return err
+	}
+
+	if endAt, ok := quietHours.EndAfter(time.Now()); ok {
+		queueName := types.GetQueueName(types.QueuePriorityDefault, s.cfg.Environment)
+
+		return s.notificationTask.SendPush(ctx, queueName, types.QueueSendPushNotificationPayload{
+			UserID:           userID,
+			NotificationType: req.NotificationType,
+			Title:            req.Title,
+			Message:          req.Message,
+			ImageURL:         req.ImageURL,
+		}, endAt)
+	}
+
+	tokens, err := s.fcmTokenRepo.FindAllByUserID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	sentCount := 0
+	staleSessionIDs := []uuid.UUID{}
+	var sendErr error
+	for _, token := range tokens {
+		req.Token = token.Token
+		req.Platform = token.Platform
+
+		_, err := s.messagingClient.Send(ctx, s.buildMessage(req))
+		if messaging.IsRegistrationTokenNotRegistered(err) {
+			staleSessionIDs = append(staleSessionIDs, token.SessionID)
+		} else if messaging.IsInvalidArgument(err) {
+			log.Error().Stack().Err(errors.Errorf("invalid fcm token %s", token.Token)).Send()
+		} else if err != nil {
+			sendErr = errors.New(err)
+		} else {
+			sentCount++
+		}
+	}
+
+	if err := s.fcmTokenRepo.DeleteBySessionIDs(ctx, userID, staleSessionIDs...); err != nil {
+		log.Error().Stack().Err(err).Send()
+	}
+
+	if sendErr != nil && sentCount == 0 {
+		return sendErr
+	} else if sendErr != nil {
+		log.Error().Stack().Err(sendErr).Send()
+	}
+
+	return nil
+}
+
+// QueueEmailToUser sends the email through the queue, ID is the id of the event that caused it so a retried event does not send the email twice
+func (s *notificationImpl) QueueEmailToUser(ctx context.Context, ID uuid.UUID, req types.NotificationSendEmailReq) error {
+	queueName := types.GetQueueName(types.QueuePriorityDefault, s.cfg.Environment)
+
+	return s.notificationTask.SendEmail(ctx, queueName, types.EmailNotificationTaskID(ID), types.QueueSendEmailNotificationPayload{
+		UserID:           req.UserID,
+		NotificationType: req.NotificationType,
+		Message:          req.Message,
+	})
+}
+
+// SendEmailToUser renders the template of the notification type in the language of the user, nothing is sent when the user turned off the type
+func (s *notificationImpl) SendEmailToUser(ctx context.Context, req types.NotificationSendEmailReq) error {
+	enabled, err := s.isEnabled(ctx, req.UserID, req.NotificationType, types.NotificationChannelEmail)
+	if err != nil || !enabled {
+		return err
+	}
+
+	user, err := s.userRepo.FindByID(ctx, req.UserID)
+	if errors.Is(err, types.ErrNoData) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	emailCopy, ok := types.NotificationEmailCopy(user.Role, req.NotificationType, user.Language)
+	if !ok {
+		return errors.Errorf("no email template for notification type %d of role %d", req.NotificationType, user.Role)
+	}
+
+	layoutCopy, ok := types.EmailLayoutCopies[user.Language]
+	if !ok {
+		layoutCopy = types.EmailLayoutCopies[types.LanguageID]
+	}
+
+	data := types.EmailNotificationTemplateData{
+		Language: user.Language,
+		Greeting: fmt.Sprintf(layoutCopy.Greeting, user.Name),
+		Heading:  emailCopy.Heading,
+		Body:     emailCopy.Body,
+		Button:   layoutCopy.Button,
+		URL:      s.cfg.Email.AppURL,
+		Footer:   layoutCopy.Footer,
+	}
+
+	if emailCopy.WithMessage {
+		data.Message = req.Message
+	}
+
+	html, text, err := emailUtil.RenderNotification(data)
+	if err != nil {
+		return err
+	}
+
+	return s.emailSender.Send(ctx, types.EmailMessage{
+		To:      user.Email,
+		Subject: emailCopy.Subject,
+		HTML:    html,
+		Text:    text,
+	})
+}
+
+// QueueAuthEmail uses the critical queue, the user is waiting for the email to continue
+func (s *notificationImpl) QueueAuthEmail(ctx context.Context, req types.NotificationSendAuthEmailReq) error {
+	queueName := types.GetQueueName(types.QueuePriorityCritical, s.cfg.Environment)
+
+	return s.notificationTask.SendAuthEmail(ctx, queueName, types.QueueSendAuthEmailPayload{
+		UserID:  req.UserID,
+		Purpose: req.Purpose,
+		Token:   req.Token,
+	})
+}
+
+// SendAuthEmail is sent regardless of the notification preferences, the button opens the page of the purpose with the token
+func (s *notificationImpl) SendAuthEmail(ctx context.Context, req types.NotificationSendAuthEmailReq) error {
+	user, err := s.userRepo.FindByID(ctx, req.UserID)
+	if errors.Is(err, types.ErrNoData) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	emailCopies, ok := types.EmailAuthCopies[req.Purpose]
+	if !ok {
+		return errors.Errorf("no email template for auth token purpose %s", req.Purpose)
+	}
+
+	emailCopy, ok := emailCopies[user.Language]
+	if !ok {
+		emailCopy = emailCopies[types.LanguageID]
+	}
+
+	layoutCopy, ok := types.EmailLayoutCopies[user.Language]
+	if !ok {
+		layoutCopy = types.EmailLayoutCopies[types.LanguageID]
+	}
+
+	html, text, err := emailUtil.RenderNotification(types.EmailNotificationTemplateData{
+		Language: user.Language,
+		Greeting: fmt.Sprintf(layoutCopy.Greeting, user.Name),
+		Heading:  emailCopy.Heading,
+		Body:     emailCopy.Body,
+		Button:   emailCopy.Button,
+		URL:      fmt.Sprintf("%s%s?token=%s", s.cfg.Email.AppURL, types.EmailAuthPaths[req.Purpose], url.QueryEscape(req.Token)),
+		Footer:   emailCopy.Footer,
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.emailSender.Send(ctx, types.EmailMessage{
+		To:      user.Email,
+		Subject: emailCopy.Subject,
+		HTML:    html,
+		Text:    text,
+	})
+}
+
 func (s *notificationImpl) SaveToken(ctx context.Context, req types.NotificationSaveTokenReq) error {
 	if err := req.Validate(); err != nil {
 		return err
 	}
 
-	key := types.FCMTokenKey(req.AuthUser.ID)
-	token, err := s.fcmTokenRepo.Find(ctx, key)
+	// clients registered before the platform was introduced are web clients
+	if req.Platform == "" {
+		req.Platform = types.FCMTokenPlatformWeb
+	}
+
+	existing, err := s.fcmTokenRepo.FindBySessionID(ctx, req.AuthUser.ID, req.AuthUser.SessionID)
 	if !errors.Is(err, types.ErrNoData) && err != nil {
 		return err
 	}
 
-	if token == req.Token {
+	if existing.Token == req.Token && existing.Platform == req.Platform {
+		return nil
+	}
+
+	topics, err := s.getTopics(ctx, req.AuthUser)
+	if err != nil {
+		return err
+	}
+
+	for _, topic := range topics {
+		if _, err := s.messagingClient.SubscribeToTopic(ctx, []string{req.Token}, topic); err != nil {
+			return errors.New(err)
+		}
+	}
+
+	// the device refreshed its token, the old one no longer receives anything
+	if existing.Token != "" && existing.Token != req.Token {
+		s.unsubscribe(ctx, existing)
+	}
+
+	token := types.FCMToken{
+		SessionID: req.AuthUser.SessionID,
+		Token:     req.Token,
+		Platform:  req.Platform,
+		Topics:    topics,
+		UpdatedAt: time.Now(),
+	}
+
+	if err := s.fcmTokenRepo.Save(ctx, req.AuthUser.ID, token, types.FCMTokenExpiration); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// MoveToken keeps the device token of a session that is renewed under a new session id
+func (s *notificationImpl) MoveToken(ctx context.Context, userID, oldSessionID, newSessionID uuid.UUID) error {
+	token, err := s.fcmTokenRepo.FindBySessionID(ctx, userID, oldSessionID)
+	if errors.Is(err, types.ErrNoData) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	token.SessionID = newSessionID
+	if err := s.fcmTokenRepo.Save(ctx, userID, token, types.FCMTokenExpiration); err != nil {
+		return err
+	}
+
+	if err := s.fcmTokenRepo.DeleteBySessionIDs(ctx, userID, oldSessionID); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *notificationImpl) RemoveToken(ctx context.Context, userID, sessionID uuid.UUID) error {
+	token, err := s.fcmTokenRepo.FindBySessionID(ctx, userID, sessionID)
+	if errors.Is(err, types.ErrNoData) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	s.unsubscribe(ctx, token)
+
+	if err := s.fcmTokenRepo.DeleteBySessionIDs(ctx, userID, sessionID); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// RemoveAllTokens stops the pushes to every device of the user, used once every session of the user is revoked
+func (s *notificationImpl) RemoveAllTokens(ctx context.Context, userID uuid.UUID) error {
+	tokens, err := s.fcmTokenRepo.FindAllByUserID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if len(tokens) == 0 {
+		return nil
+	}
+
+	sessionIDs := make(uuid.UUIDs, 0, len(tokens))
+	for _, token := range tokens {
+		s.unsubscribe(ctx, token)
+		sessionIDs = append(sessionIDs, token.SessionID)
+	}
+
+	return s.fcmTokenRepo.DeleteBySessionIDs(ctx, userID, sessionIDs...)
+}
+
+func (s *notificationImpl) AdminBroadcast(ctx context.Context, req types.NotificationAdminBroadcastReq) error {
+	if err := req.Validate(); err != nil {
+		return err
+	}
+
+	_, err := s.messagingClient.Send(ctx, &messaging.Message{
+		Notification: &messaging.Notification{
+			Title:    req.Title,
+			Body:     req.Message,
+			ImageURL: req.ImageURL,
+		},
+		Android: &messaging.AndroidConfig{
+			Priority: "high",
+		},
+		Topic: req.TopicName(),
+	})
+	if err != nil {
+		return errors.New(err)
+	}
+
+	return nil
+}
+
+// PublishUnreadCount sends the unread notification count to every websocket connection of the user, nothing is sent when the user is offline
+func (s *notificationImpl) PublishUnreadCount(ctx context.Context, userID uuid.UUID) error {
+	online, err := s.hub.IsOnline(ctx, userID)
+	if err != nil {
+		return err
+	} else if !online {
 		return nil
 	}
 
-	// 6480 hours = 9 months
-	if err := s.fcmTokenRepo.Save(ctx, key, req.Token, time.Duration(time.Hour*6480)); err != nil {
+	consumerCount, err := s.consumerNotificationRepo.CountUnreadByUserID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	providerCount, err := s.serviceProviderNotificationRepo.CountUnreadByUserID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	res, err := types.WsResponse{
+		Success: true,
+		Type:    types.WsResponseTypeNotificationUnreadCount,
+		Code:    types.WsResponseCodeSuccess,
+		Message: "success",
+		Data:    types.NotificationUnreadCountRes{Count: consumerCount + providerCount},
+	}.Parse()
+	if err != nil {
+		return errors.New(err)
+	}
+
+	if _, err := s.hub.Publish(ctx, userID, res); err != nil {
 		return err
 	}
 
 	return nil
 }
+
+// isEnabled reports whether the user wants the type on the channel, a notification without a type is always sent
+func (s *notificationImpl) isEnabled(ctx context.Context, userID uuid.UUID, notificationType int16, channel types.NotificationChannel) (bool, error) {
+	if notificationType == 0 {
+		return true, nil
+	}
+
+	preference, err := s.notificationPreferenceRepo.FindByUserIDAndTypeAndChannel(ctx, userID, notificationType, channel)
+	if errors.Is(err, types.ErrNoData) {
+		return true, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	return preference.Enabled, nil
+}
+
+// buildMessage builds the payload the platform of the token expects
+func (s *notificationImpl) buildMessage(req types.NotificationSendReq) *messaging.Message {
+	msg := &messaging.Message{Token: req.Token}
+
+	switch req.Platform {
+	case types.FCMTokenPlatformAndroid:
+		msg.Android = &messaging.AndroidConfig{
+			Priority: "high",
+			Notification: &messaging.AndroidNotification{
+				Title:    req.Title,
+				Body:     req.Message,
+				ImageURL: req.ImageURL,
+			},
+		}
+	case types.FCMTokenPlatformIOS:
+		msg.APNS = &messaging.APNSConfig{
+			Payload: &messaging.APNSPayload{
+				Aps: &messaging.Aps{
+					Alert: &messaging.ApsAlert{
+						Title: req.Title,
+						Body:  req.Message,
+					},
+					Sound:          "default",
+					MutableContent: req.ImageURL != "",
+				},
+			},
+			FCMOptions: &messaging.APNSFCMOptions{
+				ImageURL: req.ImageURL,
+			},
+		}
+	default:
+		msg.Webpush = &messaging.WebpushConfig{
+			Notification: &messaging.WebpushNotification{
+				Title: req.Title,
+				Body:  req.Message,
+				Icon:  req.IconURL,
+				Badge: req.BadgeURL,
+				Image: req.ImageURL,
+			},
+		}
+	}
+
+	return msg
+}
+
+// getTopics returns the broadcast topics of the user role, a service provider also receives the broadcasts of its service area city
+func (s *notificationImpl) getTopics(ctx context.Context, authUser types.AuthUser) ([]string, error) {
+	switch authUser.Role {
+	case types.UserRoleConsumer:
+		return []string{string(types.NotificationTopicConsumers)}, nil
+	case types.UserRoleServiceProvider:
+		topics := []string{string(types.NotificationTopicProviders)}
+
+		provider, err := s.serviceProviderRepo.FindByUserID(ctx, authUser.ID)
+		if errors.Is(err, types.ErrNoData) {
+			// the registration is not completed yet
+			return topics, nil
+		} else if err != nil {
+			return topics, err
+		}
+
+		area, err := s.serviceProviderAreaRepo.FindByServiceProviderID(ctx, provider.ID)
+		if errors.Is(err, types.ErrNoData) {
+			return topics, nil
+		} else if err != nil {
+			return topics, err
+		}
+
+		return append(topics, types.NotificationCityTopic(area.CityID)), nil
+	default:
+		return []string{}, nil
+	}
+}
+
+// unsubscribe is best effort, firebase drops the subscriptions of an unregistered token by itself
+func (s *notificationImpl) unsubscribe(ctx context.Context, token types.FCMToken) {
+	for _, topic := range token.Topics {
+		if _, err := s.messagingClient.UnsubscribeFromTopic(ctx, []string{token.Token}, topic); err != nil {
+			log.Error().Stack().Err(err).Send()
+		}
+	}
+}
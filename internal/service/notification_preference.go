@@ -0,0 +1,176 @@
+package service
+
+import (
+	"context"
+	"kelarin/internal/repository"
+	"kelarin/internal/types"
+	dbUtil "kelarin/internal/utils/dbutil"
+	"time"
+
+	"github.com/go-errors/errors"
+)
+
+type NotificationPreference interface {
+	Get(ctx context.Context, req types.NotificationPreferenceGetReq) (types.NotificationPreferenceRes, error)
+	Update(ctx context.Context, req types.NotificationPreferenceUpdateReq) error
+	UpdateQuietHours(ctx context.Context, req types.NotificationQuietHoursUpdateReq) error
+	UpdateLanguage(ctx context.Context, req types.NotificationLanguageUpdateReq) error
+}
+
+type notificationPreferenceImpl struct {
+	beginMainDBTx              dbUtil.SqlxTx
+	notificationPreferenceRepo repository.NotificationPreference
+	userRepo                   repository.User
+	utilSvc                    Util
+}
+
+func NewNotificationPreference(beginMainDBTx dbUtil.SqlxTx, notificationPreferenceRepo repository.NotificationPreference, userRepo repository.User, utilSvc Util) NotificationPreference {
+	return &notificationPreferenceImpl{
+		beginMainDBTx:              beginMainDBTx,
+		notificationPreferenceRepo: notificationPreferenceRepo,
+		userRepo:                   userRepo,
+		utilSvc:                    utilSvc,
+	}
+}
+
+// Get returns every type of the user role on every channel, a preference the user never changed is enabled
+func (s *notificationPreferenceImpl) Get(ctx context.Context, req types.NotificationPreferenceGetReq) (types.NotificationPreferenceRes, error) {
+	res := types.NotificationPreferenceRes{Preferences: []types.NotificationPreferenceItem{}}
+
+	if err := req.Validate(); err != nil {
+		return res, err
+	}
+
+	user, err := s.userRepo.FindByID(ctx, req.AuthUser.ID)
+	if err != nil {
+		return res, err
+	}
+
+	res.Language = user.Language
+
+	preferences, err := s.notificationPreferenceRepo.FindAllByUserID(ctx, req.AuthUser.ID)
+	if err != nil {
+		return res, err
+	}
+
+	disabled := map[types.NotificationPreferenceItem]bool{}
+	for _, preference := range preferences {
+		if !preference.Enabled {
+			disabled[types.NotificationPreferenceItem{Type: preference.Type, Channel: preference.Channel}] = true
+		}
+	}
+
+	for _, notificationType := range types.NotificationTypesByRole(req.AuthUser.Role) {
+		for _, channel := range types.NotificationChannels {
+			item := types.NotificationPreferenceItem{Type: notificationType, Channel: channel}
+			item.Enabled = !disabled[item]
+
+			res.Preferences = append(res.Preferences, item)
+		}
+	}
+
+	quietHours, err := s.notificationPreferenceRepo.FindQuietHoursByUserID(ctx, req.AuthUser.ID)
+	if errors.Is(err, types.ErrNoData) {
+		return res, nil
+	} else if err != nil {
+		return res, err
+	}
+
+	res.QuietHours = types.NotificationQuietHoursRes{
+		Enabled:   quietHours.Enabled,
+		StartTime: quietHours.StartTime.Format(time.TimeOnly),
+		EndTime:   quietHours.EndTime.Format(time.TimeOnly),
+		TimeZone:  quietHours.TimeZone,
+	}
+
+	return res, nil
+}
+
+// Update only changes the preferences in the request, the other preferences are left as they are
+func (s *notificationPreferenceImpl) Update(ctx context.Context, req types.NotificationPreferenceUpdateReq) error {
+	if err := req.Validate(); err != nil {
+		return err
+	}
+
+	timeNow := time.Now()
+	preferences := []types.NotificationPreference{}
+	for _, item := range req.Preferences {
+		preferences = append(preferences, types.NotificationPreference{
+			UserID:    req.AuthUser.ID,
+			Type:      item.Type,
+			Channel:   item.Channel,
+			Enabled:   item.Enabled,
+			UpdatedAt: timeNow,
+		})
+	}
+
+	tx, err := s.beginMainDBTx(ctx, nil)
+	if err != nil {
+		return errors.New(err)
+	}
+
+	defer tx.Rollback()
+
+	if err = s.notificationPreferenceRepo.UpsertManyTx(ctx, tx, preferences); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return errors.New(err)
+	}
+
+	return nil
+}
+
+// UpdateQuietHours keeps the time zone of the request, the quiet hours follow the user when the offset of the time zone changes
+func (s *notificationPreferenceImpl) UpdateQuietHours(ctx context.Context, req types.NotificationQuietHoursUpdateReq) error {
+	if err := req.Validate(); err != nil {
+		return err
+	}
+
+	if _, err := s.utilSvc.ParseUserTimeZone(req.TimeZone); err != nil {
+		return err
+	}
+
+	timeZone := req.TimeZone
+	if timeZone == "" {
+		timeZone = types.AppTimeZone
+	}
+
+	startTime, err := time.Parse(time.TimeOnly, req.StartTime)
+	if err != nil {
+		return errors.New(err)
+	}
+
+	endTime, err := time.Parse(time.TimeOnly, req.EndTime)
+	if err != nil {
+		return errors.New(err)
+	}
+
+	quietHours := types.NotificationQuietHours{
+		UserID:    req.AuthUser.ID,
+		Enabled:   req.Enabled,
+		StartTime: startTime,
+		EndTime:   endTime,
+		TimeZone:  timeZone,
+		UpdatedAt: time.Now(),
+	}
+
+	if err := s.notificationPreferenceRepo.UpsertQuietHours(ctx, quietHours); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *notificationPreferenceImpl) UpdateLanguage(ctx context.Context, req types.NotificationLanguageUpdateReq) error {
+	if err := req.Validate(); err != nil {
+		return err
+	}
+
+	if err := s.userRepo.UpdateLanguage(ctx, req.AuthUser.ID, req.Language); err != nil {
+		return err
+	}
+
+	return nil
+}
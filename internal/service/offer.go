@@ -38,13 +38,13 @@ type offerImpl struct {
 	serviceProviderRepo             repository.ServiceProvider
 	offerNegotiationRepo            repository.OfferNegotiation
 	serviceProviderNotificationRepo repository.ServiceProviderNotification
-	fcmTokenRepo                    repository.FCMToken
-	notificationSvc                 Notification
 	userRepo                        repository.User
 	consumerNotificationRepo        repository.ConsumerNotification
 	chatSvc                         Chat
 	orderSvc                        Order
 	utilSvc                         Util
+	serviceProviderAvailabilitySvc  ServiceProviderAvailability
+	outboxSvc                       Outbox
 }
 
 func NewOffer(
@@ -56,13 +56,13 @@ func NewOffer(
 	serviceProviderRepo repository.ServiceProvider,
 	offerNegotiationRepo repository.OfferNegotiation,
 	serviceProviderNotificationRepo repository.ServiceProviderNotification,
-	fcmTokenRepo repository.FCMToken,
-	notificationSvc Notification,
 	userRepo repository.User,
 	consumerNotificationRepo repository.ConsumerNotification,
 	chatSvc Chat,
 	orderSvc Order,
 	utilSvc Util,
+	serviceProviderAvailabilitySvc ServiceProviderAvailability,
+	outboxSvc Outbox,
 ) Offer {
 	return &offerImpl{
 		beginMainDBTx:                   beginMainDBTx,
@@ -73,13 +73,13 @@ func NewOffer(
 		serviceProviderRepo:             serviceProviderRepo,
 		offerNegotiationRepo:            offerNegotiationRepo,
 		serviceProviderNotificationRepo: serviceProviderNotificationRepo,
-		fcmTokenRepo:                    fcmTokenRepo,
-		notificationSvc:                 notificationSvc,
 		userRepo:                        userRepo,
 		consumerNotificationRepo:        consumerNotificationRepo,
 		chatSvc:                         chatSvc,
 		orderSvc:                        orderSvc,
 		utilSvc:                         utilSvc,
+		serviceProviderAvailabilitySvc:  serviceProviderAvailabilitySvc,
+		outboxSvc:                       outboxSvc,
 	}
 }
 
@@ -100,7 +100,27 @@ func (s *offerImpl) ConsumerCreate(ctx context.Context, req types.OfferConsumerC
 		return err
 	}
 
-	if err := req.ValidateDateTimeAndServiceFee(userTz, service.FeeStartAt); err != nil {
+	startDate, err := time.Parse(time.DateOnly, req.ServiceStartDate)
+	if err != nil {
+		return errors.New(err)
+	}
+
+	endDate, err := time.Parse(time.DateOnly, req.ServiceEndDate)
+	if err != nil {
+		return errors.New(err)
+	}
+
+	availability, err := s.serviceProviderAvailabilitySvc.Find(ctx, types.ServiceAvailabilityFindReq{
+		Service:   service,
+		Location:  userTz,
+		StartDate: startDate,
+		EndDate:   endDate,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := req.ValidateDateTimeAndServiceFee(userTz, service.FeeStartAt, availability); err != nil {
 		return err
 	}
 
@@ -134,16 +154,6 @@ func (s *offerImpl) ConsumerCreate(ctx context.Context, req types.OfferConsumerC
 		return err
 	}
 
-	startDate, err := time.Parse(time.DateOnly, req.ServiceStartDate)
-	if err != nil {
-		return errors.New(err)
-	}
-
-	endDate, err := time.Parse(time.DateOnly, req.ServiceEndDate)
-	if err != nil {
-		return errors.New(err)
-	}
-
 	localTz := time.FixedZone("GMT+8", 8*60*60)
 
 	startTime, err := time.ParseInLocation(time.TimeOnly, req.ServiceStartTime, localTz)
@@ -173,6 +183,7 @@ func (s *offerImpl) ConsumerCreate(ctx context.Context, req types.OfferConsumerC
 		ServiceEndDate:   endDate,
 		ServiceStartTime: startTime,
 		ServiceEndTime:   endTime,
+		ServiceSchedule:  req.Schedule(),
 		Status:           types.OfferStatusPending,
 		CreatedAt:        timeNow,
 	}
@@ -204,27 +215,21 @@ func (s *offerImpl) ConsumerCreate(ctx context.Context, req types.OfferConsumerC
 		return err
 	}
 
-	key := types.FCMTokenKey(provider.UserID)
-	token, err := s.fcmTokenRepo.Find(ctx, key)
-	if !errors.Is(err, types.ErrNoData) && err != nil {
+	pushEvent, err := types.NewOutboxPushNotification(provider.UserID, int16(providerNotification.Type), fmt.Sprintf("%s sent you an offer!", user.Name), "Check it now")
+	if err != nil {
 		return err
 	}
 
-	if token != "" {
-		err = s.notificationSvc.SendPush(ctx, types.NotificationSendReq{
-			Title:   fmt.Sprintf("%s sent you an offer!", user.Name),
-			Message: "Check it now",
-			Token:   token,
-		})
-		if err != nil {
-			return err
-		}
+	if err = s.outboxSvc.CreateTx(ctx, tx, pushEvent); err != nil {
+		return err
 	}
 
 	if err = tx.Commit(); err != nil {
 		return err
 	}
 
+	s.outboxSvc.Dispatch(ctx, pushEvent)
+
 	return nil
 }
 
@@ -280,6 +285,7 @@ func (s *offerImpl) ConsumerGetAll(ctx context.Context, req types.OfferConsumerG
 			ServiceEndDate:        o.ServiceEndDate.Format(time.DateOnly),
 			ServiceStartTime:      o.ServiceStartTime.In(reqTimeZone).Format(time.TimeOnly),
 			ServiceEndTime:        o.ServiceEndTime.In(reqTimeZone).Format(time.TimeOnly),
+			ServiceSchedule:       o.ServiceSchedule,
 			ServiceTimeTimeZone:   reqTimeZone.String(),
 			Status:                o.Status,
 			HasPendingNegotiation: slices.ContainsFunc(negotiations, func(n types.OfferNegotiation) bool { return n.OfferID == o.ID }),
@@ -388,6 +394,7 @@ func (s *offerImpl) ConsumerGetByID(ctx context.Context, req types.OfferConsumer
 		ServiceEndDate:        offer.ServiceEndDate.Format(time.DateOnly),
 		ServiceStartTime:      offer.ServiceStartTime.In(timeZone).Format(time.TimeOnly),
 		ServiceEndTime:        offer.ServiceEndTime.In(timeZone).Format(time.TimeOnly),
+		ServiceSchedule:       offer.ServiceSchedule,
 		ServiceTimeTimeZone:   timeZone.String(),
 		Status:                offer.Status,
 		HasPendingNegotiation: slices.ContainsFunc(negotiations, func(n types.OfferNegotiation) bool { return n.Status == types.OfferNegotiationStatusPending }),
@@ -455,18 +462,13 @@ func (s *offerImpl) ProviderAction(ctx context.Context, req types.OfferProviderA
 		return err
 	}
 
-	token, err := s.fcmTokenRepo.Find(ctx, types.FCMTokenKey(offer.UserID))
-	if !errors.Is(err, types.ErrNoData) && err != nil {
-		return err
-	}
-
 	id, err := uuid.NewV7()
 	if err != nil {
 		return errors.New(err)
 	}
 
 	now := time.Now()
-	pushNotifReq := types.NotificationSendReq{}
+	pushNotif := types.OutboxPushNotificationPayload{UserID: offer.UserID}
 	consumerNotification := types.ConsumerNotification{
 		ID:        id,
 		UserID:    offer.UserID,
@@ -484,6 +486,7 @@ func (s *offerImpl) ProviderAction(ctx context.Context, req types.OfferProviderA
 	switch req.Action {
 	case types.OfferProviderActionReqActionAccept:
 		err = req.ValidateDateAndTime(
+			offer.ServiceSchedule,
 			offer.ServiceStartDate,
 			offer.ServiceEndDate,
 			offer.ServiceStartTime.Format(time.TimeOnly),
@@ -495,9 +498,12 @@ func (s *offerImpl) ProviderAction(ctx context.Context, req types.OfferProviderA
 
 		offer.Status = types.OfferStatusAccepted
 
-		serviceDate, err := time.Parse(time.DateOnly, req.Date)
-		if err != nil {
-			return errors.New(err)
+		serviceDate := offer.ServiceStartDate
+		if !offer.ServiceSchedule.IsRecurring() {
+			serviceDate, err = time.Parse(time.DateOnly, req.Date)
+			if err != nil {
+				return errors.New(err)
+			}
 		}
 
 		reqTimeZone, err := time.LoadLocation(req.TimeZone)
@@ -511,6 +517,31 @@ func (s *offerImpl) ProviderAction(ctx context.Context, req types.OfferProviderA
 			return errors.New(err)
 		}
 
+		sessionDates := offer.SessionDates()
+		if len(sessionDates) == 0 {
+			sessionDates = []time.Time{serviceDate}
+		}
+
+		availability, err := s.serviceProviderAvailabilitySvc.Find(ctx, types.ServiceAvailabilityFindReq{
+			Tx:        tx,
+			Service:   service,
+			Location:  reqTimeZone,
+			StartDate: sessionDates[0],
+			EndDate:   sessionDates[len(sessionDates)-1],
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, date := range sessionDates {
+			if !availability.IsAvailable(date, serviceTime) {
+				return errors.New(types.AppErr{
+					Code:    http.StatusConflict,
+					Message: fmt.Sprintf("the schedule on %s at %s is outside of working hours or already booked", date.Format(time.DateOnly), req.Time),
+				})
+			}
+		}
+
 		err = s.orderSvc.Create(ctx, types.OrderCreateReq{
 			AuthUser:               req.AuthUser,
 			Offer:                  offer,
@@ -541,34 +572,36 @@ func (s *offerImpl) ProviderAction(ctx context.Context, req types.OfferProviderA
 		}
 
 		consumerNotification.Type = types.ConsumerNotificationTypeOfferAccepted
-		pushNotifReq = types.NotificationSendReq{
-			Title:   fmt.Sprintf("%s accept your offer", provider.Name),
-			Message: "confirm your payment now",
-			Token:   token,
-		}
+		pushNotif.NotificationType = int16(consumerNotification.Type)
+		pushNotif.Title = fmt.Sprintf("%s accept your offer", provider.Name)
+		pushNotif.Message = "confirm your payment now"
 	case types.OfferProviderActionReqActionReject:
 		offer.Status = types.OfferStatusRejected
 
 		consumerNotification.Type = types.ConsumerNotificationTypeOfferRejected
-		pushNotifReq = types.NotificationSendReq{
-			Title:   fmt.Sprintf("%s reject your offer", provider.Name),
-			Message: "your offer has been rejected, you still can sent a new offer :)",
-			Token:   token,
-		}
+		pushNotif.NotificationType = int16(consumerNotification.Type)
+		pushNotif.Title = fmt.Sprintf("%s reject your offer", provider.Name)
+		pushNotif.Message = "your offer has been rejected, you still can sent a new offer :)"
 	}
 
 	if err := s.consumerNotificationRepo.CreateTx(ctx, tx, consumerNotification); err != nil {
 		return err
 	}
 
-	if token != "" {
-		providerLogoURL, err := s.fileSvc.GetS3PresignedURL(ctx, provider.LogoImage)
+	if provider.LogoImage != "" {
+		pushNotif.ImageURL, err = s.fileSvc.GetS3PresignedURL(ctx, provider.LogoImage)
 		if err != nil {
 			return err
 		}
+	}
 
-		pushNotifReq.ImageURL = providerLogoURL
-		go s.notificationSvc.SendPush(ctx, pushNotifReq)
+	pushEvent, err := types.NewOutboxEvent(types.OutboxEventTypePushNotification, pushNotif)
+	if err != nil {
+		return err
+	}
+
+	if err = s.outboxSvc.CreateTx(ctx, tx, pushEvent); err != nil {
+		return err
 	}
 
 	if err = s.offerRepo.UpdateTx(ctx, tx, offer); err != nil {
@@ -579,6 +612,8 @@ func (s *offerImpl) ProviderAction(ctx context.Context, req types.OfferProviderA
 		return err
 	}
 
+	s.outboxSvc.Dispatch(ctx, pushEvent)
+
 	return nil
 }
 
@@ -610,6 +645,7 @@ func (s *offerImpl) ProviderGetAll(ctx context.Context, req types.OfferProviderG
 			ServiceEndDate:   o.ServiceEndDate.Format(time.DateOnly),
 			ServiceStartTime: o.ServiceStartTime.Format(time.TimeOnly),
 			ServiceEndTime:   o.ServiceEndTime.Format(time.TimeOnly),
+			ServiceSchedule:  o.ServiceSchedule,
 			Status:           o.Status,
 			CreatedAt:        o.CreatedAt,
 		})
@@ -698,6 +734,7 @@ func (s *offerImpl) ProviderGetByID(ctx context.Context, req types.OfferProvider
 			ServiceEndDate:   offer.ServiceEndDate.Format(time.DateOnly),
 			ServiceStartTime: offer.ServiceStartTime.Format(time.TimeOnly),
 			ServiceEndTime:   offer.ServiceEndTime.Format(time.TimeOnly),
+			ServiceSchedule:  offer.ServiceSchedule,
 			Status:           offer.Status,
 			CreatedAt:        offer.CreatedAt,
 		},
@@ -25,8 +25,7 @@ type offerNegotiationImpl struct {
 	offerNegotiationRepo            repository.OfferNegotiation
 	offerRepo                       repository.Offer
 	serviceRepo                     repository.Service
-	notificationSvc                 Notification
-	fcmTokenRepo                    repository.FCMToken
+	outboxSvc                       Outbox
 	fileSvc                         File
 	consumerNotificationRepo        repository.ConsumerNotification
 	serviceProviderNotificationRepo repository.ServiceProviderNotification
@@ -39,8 +38,7 @@ func NewOfferNegotiation(
 	offerNegotiationRepo repository.OfferNegotiation,
 	offerRepo repository.Offer,
 	serviceRepo repository.Service,
-	notificationSvc Notification,
-	fcmTokenRepo repository.FCMToken,
+	outboxSvc Outbox,
 	fileSvc File,
 	consumerNotificationRepo repository.ConsumerNotification,
 	serviceProviderNotificationRepo repository.ServiceProviderNotification,
@@ -52,8 +50,7 @@ func NewOfferNegotiation(
 		offerNegotiationRepo:            offerNegotiationRepo,
 		offerRepo:                       offerRepo,
 		serviceRepo:                     serviceRepo,
-		notificationSvc:                 notificationSvc,
-		fcmTokenRepo:                    fcmTokenRepo,
+		outboxSvc:                       outboxSvc,
 		fileSvc:                         fileSvc,
 		consumerNotificationRepo:        consumerNotificationRepo,
 		serviceProviderNotificationRepo: serviceProviderNotificationRepo,
@@ -141,33 +138,35 @@ func (s *offerNegotiationImpl) ProviderCreate(ctx context.Context, req types.Off
 		return err
 	}
 
-	key := types.FCMTokenKey(offer.UserID)
-	fcmToken, err := s.fcmTokenRepo.Find(ctx, key)
-	if !errors.Is(err, types.ErrNoData) && err != nil {
-		return err
+	pushNotif := types.OutboxPushNotificationPayload{
+		UserID:           offer.UserID,
+		NotificationType: int16(consumerNotification.Type),
+		Title:            fmt.Sprintf("%s want to negotiate", provider.Name),
+		Message:          req.Message,
 	}
 
-	if fcmToken != "" {
-		providerLogoURL, err := s.fileSvc.GetS3PresignedURL(ctx, provider.LogoImage)
+	if provider.LogoImage != "" {
+		pushNotif.ImageURL, err = s.fileSvc.GetS3PresignedURL(ctx, provider.LogoImage)
 		if err != nil {
 			return err
 		}
+	}
 
-		err = s.notificationSvc.SendPush(ctx, types.NotificationSendReq{
-			Title:    fmt.Sprintf("%s want to negotiate", provider.Name),
-			Message:  req.Message,
-			Token:    fcmToken,
-			ImageURL: providerLogoURL,
-		})
-		if err != nil {
-			return err
-		}
+	pushEvent, err := types.NewOutboxEvent(types.OutboxEventTypePushNotification, pushNotif)
+	if err != nil {
+		return err
+	}
+
+	if err = s.outboxSvc.CreateTx(ctx, tx, pushEvent); err != nil {
+		return err
 	}
 
 	if err = tx.Commit(); err != nil {
 		return err
 	}
 
+	s.outboxSvc.Dispatch(ctx, pushEvent)
+
 	return nil
 }
 
@@ -222,14 +221,9 @@ func (s *offerNegotiationImpl) ConsumerAction(ctx context.Context, req types.Off
 		CreatedAt:          time.Now(),
 	}
 
-	fcmToken, err := s.fcmTokenRepo.Find(ctx, types.FCMTokenKey(provider.UserID))
-	if !errors.Is(err, types.ErrNoData) && err != nil {
-		return err
-	}
-
-	pushNotif := types.NotificationSendReq{
+	pushNotif := types.OutboxPushNotificationPayload{
+		UserID:  provider.UserID,
 		Message: "Please check your offer",
-		Token:   fcmToken,
 	}
 
 	switch req.Action {
@@ -238,11 +232,13 @@ func (s *offerNegotiationImpl) ConsumerAction(ctx context.Context, req types.Off
 		offer.ServiceCost = negotiation.RequestedServiceCost
 		providerNotification.Type = types.ServiceProviderNotificationTypeOfferNegotiationAccepted
 
+		pushNotif.NotificationType = int16(providerNotification.Type)
 		pushNotif.Message = fmt.Sprintf("%s accepted your offer negotiation", user.Name)
 	case types.OfferNegotiationConsumerActionReject:
 		negotiation.Status = types.OfferNegotiationStatusRejected
 		providerNotification.Type = types.ServiceProviderNotificationTypeOfferNegotiationRejected
 
+		pushNotif.NotificationType = int16(providerNotification.Type)
 		pushNotif.Message = fmt.Sprintf("%s rejected your offer negotiation", user.Name)
 	}
 
@@ -265,16 +261,20 @@ func (s *offerNegotiationImpl) ConsumerAction(ctx context.Context, req types.Off
 		return err
 	}
 
-	if fcmToken != "" {
-		err = s.notificationSvc.SendPush(ctx, pushNotif)
-		if err != nil {
-			return err
-		}
+	pushEvent, err := types.NewOutboxEvent(types.OutboxEventTypePushNotification, pushNotif)
+	if err != nil {
+		return err
+	}
+
+	if err = s.outboxSvc.CreateTx(ctx, tx, pushEvent); err != nil {
+		return err
 	}
 
 	if err := tx.Commit(); err != nil {
 		return err
 	}
 
+	s.outboxSvc.Dispatch(ctx, pushEvent)
+
 	return nil
 }
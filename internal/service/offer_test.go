@@ -36,14 +36,14 @@ func TestOfferService(t *testing.T) {
 	serviceProviderRepo := repoMocks.NewServiceProvider(t)
 	offerNegotiationRepo := repoMocks.NewOfferNegotiation(t)
 	serviceProviderNotificationRepo := repoMocks.NewServiceProviderNotification(t)
-	fcmTokenRepo := repoMocks.NewFCMToken(t)
-	notificationSvc := svcMocks.NewNotification(t)
 	userRepo := repoMocks.NewUser(t)
 	consumerNotificationRepo := repoMocks.NewConsumerNotification(t)
 	chatSvc := svcMocks.NewChat(t)
 	utilSvc := svcMocks.NewUtil(t)
 	fileSvc := svcMocks.NewFile(t)
 	orderSvc := svcMocks.NewOrder(t)
+	serviceProviderAvailabilitySvc := svcMocks.NewServiceProviderAvailability(t)
+	outboxSvc := svcMocks.NewOutbox(t)
 
 	timeNow := time.Now()
 	serviceStartDate := timeNow.Format(time.DateOnly)
@@ -60,6 +60,7 @@ func TestOfferService(t *testing.T) {
 		ID:                serviceID,
 		ServiceProviderID: serviceProviderID,
 	}, nil)
+	serviceProviderAvailabilitySvc.Mock.On("Find", ctx, mock.Anything).Return(types.ServiceAvailability{MaxConcurrentJobs: 1}, nil)
 	offerRepo.Mock.On("IsPendingOfferExists", ctx, authUserID, serviceID).Return(false, nil)
 	utilSvc.Mock.On("ParseUserTimeZone", "").Return(time.Local, nil)
 	userRepo.Mock.On("FindByID", ctx, authUserID).Return(types.User{ID: authUserID}, nil)
@@ -104,7 +105,10 @@ func TestOfferService(t *testing.T) {
 		return n.OfferID.Valid && n.Type == types.ServiceProviderNotificationTypeOfferReceived
 	})).Return(nil)
 
-	fcmTokenRepo.Mock.On("Find", ctx, types.FCMTokenKey(uuid.UUID{})).Return("", nil)
+	outboxSvc.Mock.On("CreateTx", ctx, mock.Anything, mock.MatchedBy(func(e types.OutboxEvent) bool {
+		return e.Type == types.OutboxEventTypePushNotification
+	})).Return(nil)
+	outboxSvc.Mock.On("Dispatch", ctx, mock.Anything).Return()
 
 	dbMock.ExpectBegin()
 
@@ -119,13 +123,13 @@ func TestOfferService(t *testing.T) {
 		serviceProviderRepo,
 		offerNegotiationRepo,
 		serviceProviderNotificationRepo,
-		fcmTokenRepo,
-		notificationSvc,
 		userRepo,
 		consumerNotificationRepo,
 		chatSvc,
 		orderSvc,
 		utilSvc,
+		serviceProviderAvailabilitySvc,
+		outboxSvc,
 	)
 
 	dbMock.ExpectCommit()
@@ -14,6 +14,9 @@ import (
 	"github.com/go-errors/errors"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"github.com/samber/lo"
+	"github.com/shopspring/decimal"
 	"github.com/volatiletech/null/v9"
 )
 
@@ -23,10 +26,12 @@ type Order interface {
 	ConsumerGetAll(ctx context.Context, req types.OrderConsumerGetAllReq) ([]types.OrderConsumerGetAllRes, error)
 	ConsumerGetByID(ctx context.Context, req types.OrderConsumerGetByIDReq) (types.ConsumerOrderGetByIDRes, error)
 	ConsumerGenerateQRCode(ctx context.Context, req types.OrderConsumerGenerateQRCodeReq) (types.OrderConsumerGenerateQRCodeRes, error)
+	ConsumerCancel(ctx context.Context, req types.OrderCancelReq) (types.OrderCancelRes, error)
 
 	ProviderGetAll(ctx context.Context, req types.OrderProviderGetAllReq) ([]types.OrderProviderGetAllRes, error)
 	ProviderGetByID(ctx context.Context, req types.OrderProviderGetByIDReq) (types.OrderProviderGetByIDRes, error)
 	ProviderFinish(ctx context.Context, req types.OrderProviderValidateQRCodeReq) error
+	ProviderCancel(ctx context.Context, req types.OrderCancelReq) (types.OrderCancelRes, error)
 
 	TaskUpdateOrderStatus(ctx context.Context) error
 }
@@ -45,10 +50,13 @@ type orderImpl struct {
 	serviceProviderRepo             repository.ServiceProvider
 	consumerNotificationRepo        repository.ConsumerNotification
 	serviceProviderNotificationRepo repository.ServiceProviderNotification
-	fcmRepo                         repository.FCMToken
-	notificationSvc                 Notification
+	outboxSvc                       Outbox
 	serviceRepo                     repository.Service
 	serviceFeedback                 repository.ServiceFeedback
+	serviceProviderCreditSvc        ServiceProviderCredit
+	refundRepo                      repository.Refund
+	refundSvc                       Refund
+	orderSessionRepo                repository.OrderSession
 }
 
 func NewOrder(
@@ -64,10 +72,13 @@ func NewOrder(
 	serviceProviderRepo repository.ServiceProvider,
 	consumerNotificationRepo repository.ConsumerNotification,
 	serviceProviderNotificationRepo repository.ServiceProviderNotification,
-	fcmRepo repository.FCMToken,
-	notificationSvc Notification,
+	outboxSvc Outbox,
 	serviceRepo repository.Service,
 	serviceFeedback repository.ServiceFeedback,
+	serviceProviderCreditSvc ServiceProviderCredit,
+	refundRepo repository.Refund,
+	refundSvc Refund,
+	orderSessionRepo repository.OrderSession,
 ) Order {
 	return &orderImpl{
 		beginMainDBTx:                   beginMainDBTx,
@@ -83,10 +94,13 @@ func NewOrder(
 		serviceProviderRepo:             serviceProviderRepo,
 		consumerNotificationRepo:        consumerNotificationRepo,
 		serviceProviderNotificationRepo: serviceProviderNotificationRepo,
-		fcmRepo:                         fcmRepo,
-		notificationSvc:                 notificationSvc,
+		outboxSvc:                       outboxSvc,
 		serviceRepo:                     serviceRepo,
 		serviceFeedback:                 serviceFeedback,
+		serviceProviderCreditSvc:        serviceProviderCreditSvc,
+		refundRepo:                      refundRepo,
+		refundSvc:                       refundSvc,
+		orderSessionRepo:                orderSessionRepo,
 	}
 }
 
@@ -98,18 +112,24 @@ func (s *orderImpl) Create(ctx context.Context, req types.OrderCreateReq) error
 
 	now := time.Now()
 
+	serviceDates := req.Offer.SessionDates()
+	if len(serviceDates) == 0 {
+		serviceDates = []time.Time{req.ServiceDate}
+	}
+
 	id, err := uuid.NewV7()
 	if err != nil {
 		return errors.New(err)
 	}
 
+	// the order keeps the first session schedule, the whole series shares the order payment
 	oder := types.Order{
 		ID:                id,
 		UserID:            req.Offer.UserID,
 		ServiceProviderID: req.ServiceProviderID,
 		OfferID:           req.Offer.ID,
 		ServiceFee:        req.Offer.ServiceCost,
-		ServiceDate:       req.ServiceDate,
+		ServiceDate:       serviceDates[0],
 		ServiceTime:       req.ServiceTime,
 		CreatedAt:         now,
 	}
@@ -119,6 +139,29 @@ func (s *orderImpl) Create(ctx context.Context, req types.OrderCreateReq) error
 		return err
 	}
 
+	sessions := []types.OrderSession{}
+	for i, serviceDate := range serviceDates {
+		id, err := uuid.NewV7()
+		if err != nil {
+			return errors.New(err)
+		}
+
+		sessions = append(sessions, types.OrderSession{
+			ID:          id,
+			OrderID:     oder.ID,
+			Sequence:    int16(i + 1),
+			ServiceDate: serviceDate,
+			ServiceTime: req.ServiceTime,
+			Status:      types.OrderSessionStatusPending,
+			CreatedAt:   now,
+		})
+	}
+
+	err = s.orderSessionRepo.BulkCreateTx(ctx, req.Tx, sessions)
+	if err != nil {
+		return err
+	}
+
 	offerSnapshot := types.OrderOfferSnapshot{
 		OrderID: oder.ID,
 		UserAddress: types.OrderOfferSnapshotUserAddress{
@@ -307,6 +350,13 @@ func (s *orderImpl) ConsumerGetByID(ctx context.Context, req types.OrderConsumer
 		return res, err
 	}
 
+	sessions, err := s.orderSessionRepo.FindAllByOrderID(ctx, order.ID)
+	if err != nil {
+		return res, err
+	}
+
+	progress, sessionsRes := s.toSessionsRes(sessions, reqTz)
+
 	res = types.ConsumerOrderGetByIDRes{
 		ID:               order.ID,
 		OfferID:          order.OfferID,
@@ -344,7 +394,9 @@ func (s *orderImpl) ConsumerGetByID(ctx context.Context, req types.OrderConsumer
 			Lng:      lng,
 			Detail:   orderOfferSnapshot.UserAddress.Detail,
 		},
-		Payment: paymentRes,
+		Payment:  paymentRes,
+		Progress: progress,
+		Sessions: sessionsRes,
 	}
 
 	return res, nil
@@ -372,6 +424,19 @@ func (s *orderImpl) ConsumerGenerateQRCode(ctx context.Context, req types.OrderC
 		return res, errors.New(types.AppErr{Code: http.StatusForbidden, Message: "order not ongoing"})
 	}
 
+	sessions, err := s.orderSessionRepo.FindAllByOrderID(ctx, order.ID)
+	if err != nil {
+		return res, err
+	}
+
+	// the qr-code finishes the earliest ongoing session, a missed session stays ongoing until it is finished
+	session, ok := lo.Find(sessions, func(session types.OrderSession) bool {
+		return session.Status == types.OrderSessionStatusOngoing
+	})
+	if !ok {
+		return res, errors.New(types.AppErr{Code: http.StatusForbidden, Message: "no ongoing session for today"})
+	}
+
 	payment, err := s.paymentRepo.FindByID(ctx, order.PaymentID.UUID)
 	if errors.Is(err, types.ErrNoData) {
 		return res, errors.Errorf("payment not found: id %s", order.PaymentID.UUID)
@@ -382,6 +447,7 @@ func (s *orderImpl) ConsumerGenerateQRCode(ctx context.Context, req types.OrderC
 	duration := time.Minute * 1
 	qrCodeContent, err := s.GenerateQRCodeContent(types.OrderConsumerGenerateQRCodePayload{
 		OrderID:     order.ID,
+		SessionID:   session.ID,
 		Amount:      payment.Amount,
 		AdminFee:    payment.AdminFee,
 		PlatformFee: payment.PlatformFee,
@@ -406,6 +472,7 @@ func (s *orderImpl) GenerateQRCodeContent(payload types.OrderConsumerGenerateQRC
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(expiration)),
 		},
 		OrderID:     payload.OrderID,
+		SessionID:   payload.SessionID,
 		Amount:      payload.Amount,
 		AdminFee:    payload.AdminFee,
 		PlatformFee: payload.PlatformFee,
@@ -545,6 +612,13 @@ func (s *orderImpl) ProviderGetByID(ctx context.Context, req types.OrderProvider
 		lng = null.Float64From(longitude)
 	}
 
+	sessions, err := s.orderSessionRepo.FindAllByOrderID(ctx, order.ID)
+	if err != nil {
+		return res, err
+	}
+
+	progress, sessionsRes := s.toSessionsRes(sessions, order.ServiceTime.Location())
+
 	res = types.OrderProviderGetByIDRes{
 		ID:               order.ID,
 		OfferID:          order.OfferID,
@@ -570,6 +644,8 @@ func (s *orderImpl) ProviderGetByID(ctx context.Context, req types.OrderProvider
 			Lng:      lng,
 			Detail:   orderOfferSnapshot.UserAddress.Detail,
 		},
+		Progress: progress,
+		Sessions: sessionsRes,
 	}
 
 	if order.PaymentID.Valid {
@@ -655,8 +731,95 @@ func (s *orderImpl) ProviderFinish(ctx context.Context, req types.OrderProviderV
 		return errors.New(types.AppErr{Code: http.StatusForbidden, Message: "invalid qr-code"})
 	}
 
+	tx, err := s.beginMainDBTx(ctx, nil)
+	if err != nil {
+		return errors.New(err)
+	}
+
+	defer tx.Rollback()
+
+	// lock the order so two sessions of the same order can not both be finished as the last session,
+	// the order may have been canceled since it was read above so the status is checked again on the locked row
+	order, err = s.orderRepo.FindForUpdateByID(ctx, tx, order.ID)
+	if err != nil {
+		return err
+	}
+
+	if order.Status != types.OrderStatusOngoing {
+		return errors.New(types.AppErr{Code: http.StatusForbidden, Message: "invalid order"})
+	}
+
+	if !order.PaymentFulfilled {
+		return errors.New(types.AppErr{Code: http.StatusForbidden, Message: "payment not fulfilled"})
+	}
+
+	session, err := s.orderSessionRepo.FindForUpdateByID(ctx, tx, claims.SessionID)
+	if errors.Is(err, types.ErrNoData) {
+		return errors.New(types.AppErr{Code: http.StatusForbidden, Message: "invalid qr-code"})
+	} else if err != nil {
+		return err
+	}
+
+	if session.OrderID != order.ID {
+		return errors.New(types.AppErr{Code: http.StatusForbidden, Message: "invalid qr-code"})
+	}
+
+	if session.Status != types.OrderSessionStatusOngoing {
+		return errors.New(types.AppErr{Code: http.StatusForbidden, Message: "order session not ongoing"})
+	}
+
+	sessions, err := s.orderSessionRepo.FindAllByOrderID(ctx, order.ID)
+	if err != nil {
+		return err
+	}
+
+	isLastSession := lo.CountBy(sessions, func(x types.OrderSession) bool {
+		return x.ID != session.ID && !x.IsDone()
+	}) == 0
+
 	timeNow := time.Now()
 
+	session.Status = types.OrderSessionStatusFinished
+	session.FinishedAt = null.TimeFrom(timeNow)
+	session.UpdatedAt = null.TimeFrom(timeNow)
+	if err = s.orderSessionRepo.UpdateAsFinishedTx(ctx, tx, session); err != nil {
+		return err
+	}
+
+	consumerNotifType := types.ConsumerNotificationTypeOrderSessionFinished
+	providerNotifType := types.ServiceProviderNotificationTypeOrderSessionFinished
+	consumerPushTitle := fmt.Sprintf("%s session %d of %d finished", provider.Name, session.Sequence, len(sessions))
+	consumerPushMessage := "check the progress of your order"
+	providerPushTitle := "Order session finished"
+	providerPushMessage := "the service fee will be added to your credit once every session is finished"
+
+	// the order is finished and paid out to the service provider once its last session is finished
+	if isLastSession {
+		order.Status = types.OrderStatusFinished
+		order.UpdatedAt = null.TimeFrom(timeNow)
+		if err = s.orderRepo.UpdateStatusTx(ctx, tx, order); err != nil {
+			return err
+		}
+
+		err = s.serviceProviderCreditSvc.AddTx(ctx, types.ServiceProviderCreditAddReq{
+			Tx:                tx,
+			ServiceProviderID: provider.ID,
+			Amount:            order.ServiceFee,
+			Type:              types.ServiceProviderCreditLedgerTypeOrderFinished,
+			OrderID:           uuid.NullUUID{UUID: order.ID, Valid: true},
+		})
+		if err != nil {
+			return err
+		}
+
+		consumerNotifType = types.ConsumerNotificationTypeOrderFinished
+		providerNotifType = types.ServiceProviderNotificationTypeOrderFinished
+		consumerPushTitle = fmt.Sprintf("%s order finished", provider.Name)
+		consumerPushMessage = "rate provider now"
+		providerPushTitle = "Order finished"
+		providerPushMessage = "the service fee has been added to your credit"
+	}
+
 	id, err := uuid.NewV7()
 	if err != nil {
 		return errors.New(err)
@@ -665,7 +828,7 @@ func (s *orderImpl) ProviderFinish(ctx context.Context, req types.OrderProviderV
 		ID:        id,
 		UserID:    order.UserID,
 		OrderID:   uuid.NullUUID{UUID: order.ID, Valid: true},
-		Type:      types.ConsumerNotificationTypeOrderFinished,
+		Type:      consumerNotifType,
 		CreatedAt: timeNow,
 	}
 
@@ -677,73 +840,296 @@ func (s *orderImpl) ProviderFinish(ctx context.Context, req types.OrderProviderV
 		ID:                id,
 		ServiceProviderID: provider.ID,
 		OrderID:           uuid.NullUUID{UUID: order.ID, Valid: true},
-		Type:              types.ServiceProviderNotificationTypeOrderFinished,
+		Type:              providerNotifType,
 		CreatedAt:         timeNow,
 	}
 
+	if err = s.consumerNotificationRepo.CreateTx(ctx, tx, consumerNotif); err != nil {
+		return err
+	}
+
+	if err = s.serviceProviderNotificationRepo.CreateTx(ctx, tx, providerNotif); err != nil {
+		return err
+	}
+
+	consumerPushEvent, err := types.NewOutboxPushNotification(order.UserID, int16(consumerNotifType), consumerPushTitle, consumerPushMessage)
+	if err != nil {
+		return err
+	}
+
+	providerPushEvent, err := types.NewOutboxPushNotification(provider.UserID, int16(providerNotifType), providerPushTitle, providerPushMessage)
+	if err != nil {
+		return err
+	}
+
+	if err = s.outboxSvc.CreateTx(ctx, tx, consumerPushEvent, providerPushEvent); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return err
+	}
+
+	s.outboxSvc.Dispatch(ctx, consumerPushEvent, providerPushEvent)
+
+	return nil
+}
+
+func (s *orderImpl) ConsumerCancel(ctx context.Context, req types.OrderCancelReq) (types.OrderCancelRes, error) {
+	res := types.OrderCancelRes{}
+
+	if err := req.Validate(); err != nil {
+		return res, err
+	}
+
+	order, err := s.orderRepo.FindByIDAndUserID(ctx, req.ID, req.AuthUser.ID)
+	if errors.Is(err, types.ErrNoData) {
+		return res, errors.New(types.AppErr{Code: http.StatusNotFound, Message: "order not found"})
+	} else if err != nil {
+		return res, err
+	}
+
+	return s.cancel(ctx, order.ID, types.UserRoleConsumer, req.Reason)
+}
+
+func (s *orderImpl) ProviderCancel(ctx context.Context, req types.OrderCancelReq) (types.OrderCancelRes, error) {
+	res := types.OrderCancelRes{}
+
+	if err := req.Validate(); err != nil {
+		return res, err
+	}
+
+	provider, err := s.serviceProviderRepo.FindByUserID(ctx, req.AuthUser.ID)
+	if errors.Is(err, types.ErrNoData) {
+		return res, errors.Errorf("service provider not found: user_id %s", req.AuthUser.ID)
+	} else if err != nil {
+		return res, err
+	}
+
+	order, err := s.orderRepo.FindByIDAndServiceProviderID(ctx, req.ID, provider.ID)
+	if errors.Is(err, types.ErrNoData) {
+		return res, errors.New(types.AppErr{Code: http.StatusNotFound, Message: "order not found"})
+	} else if err != nil {
+		return res, err
+	}
+
+	return s.cancel(ctx, order.ID, types.UserRoleServiceProvider, req.Reason)
+}
+
+// cancel marks the order as canceled and refunds a paid order based on who cancels it:
+//   - the service provider: the whole payment including the admin and platform fee
+//   - the consumer before OrderCancellationFullRefundWindow: the service fee
+//   - the consumer after that: OrderCancellationLateRefundPercentage of the service fee, the rest goes to the service provider credit
+//
+// The window is counted from the next unfinished session. The share of the finished sessions is earned by the service provider and never refunded
+func (s *orderImpl) cancel(ctx context.Context, orderID uuid.UUID, canceledBy types.UserRole, reason string) (types.OrderCancelRes, error) {
+	res := types.OrderCancelRes{}
+
 	tx, err := s.beginMainDBTx(ctx, nil)
 	if err != nil {
-		return errors.New(err)
+		return res, errors.New(err)
 	}
 
 	defer tx.Rollback()
 
-	order.Status = types.OrderStatusFinished
+	order, err := s.orderRepo.FindForUpdateByID(ctx, tx, orderID)
+	if errors.Is(err, types.ErrNoData) {
+		return res, errors.New(types.AppErr{Code: http.StatusNotFound, Message: "order not found"})
+	} else if err != nil {
+		return res, err
+	}
+
+	if !order.IsCancelable() {
+		return res, errors.New(types.AppErr{Code: http.StatusConflict, Message: "order can no longer be canceled"})
+	}
+
+	provider, err := s.serviceProviderRepo.FindByID(ctx, order.ServiceProviderID)
+	if errors.Is(err, types.ErrNoData) {
+		return res, errors.Errorf("service provider not found: id %s", order.ServiceProviderID)
+	} else if err != nil {
+		return res, err
+	}
+
+	sessions, err := s.orderSessionRepo.FindAllByOrderID(ctx, order.ID)
+	if err != nil {
+		return res, err
+	}
+
+	serviceStartAt := order.ServiceStartAt()
+	if nextSession, ok := sessions.NextUndone(); ok {
+		serviceStartAt = nextSession.ServiceStartAt()
+	}
+
+	timeNow := time.Now()
+
+	order.Status = types.OrderStatusCanceled
+	order.CanceledBy = null.Int16From(int16(canceledBy))
+	order.CancellationReason = null.StringFrom(reason)
+	order.CanceledAt = null.TimeFrom(timeNow)
 	order.UpdatedAt = null.TimeFrom(timeNow)
-	if err = s.orderRepo.UpdateStatusTx(ctx, tx, order); err != nil {
-		return err
+	if err = s.orderRepo.UpdateAsCanceledTx(ctx, tx, order); err != nil {
+		return res, err
 	}
 
-	provider.Credit = provider.Credit.Add(order.ServiceFee)
-	if err = s.serviceProviderRepo.UpdateCreditTx(ctx, provider); err != nil {
-		return err
+	if err = s.orderSessionRepo.UpdateAsCanceledByOrderIDsTx(ctx, tx, uuid.UUIDs{order.ID}); err != nil {
+		return res, err
+	}
+
+	var refund types.Refund
+	if order.PaymentFulfilled {
+		payment, err := s.paymentRepo.FindByID(ctx, order.PaymentID.UUID)
+		if errors.Is(err, types.ErrNoData) {
+			return res, errors.Errorf("payment not found: id %s", order.PaymentID.UUID)
+		} else if err != nil {
+			return res, err
+		}
+
+		refundableAmount := payment.Amount
+		if finishedSessions := sessions.CountByStatus(types.OrderSessionStatusFinished); finishedSessions > 0 {
+			earnedAmount := payment.Amount.Mul(decimal.NewFromInt(int64(finishedSessions))).Div(decimal.NewFromInt(int64(len(sessions)))).RoundFloor(0)
+			refundableAmount = payment.Amount.Sub(earnedAmount)
+
+			err = s.serviceProviderCreditSvc.AddTx(ctx, types.ServiceProviderCreditAddReq{
+				Tx:                tx,
+				ServiceProviderID: order.ServiceProviderID,
+				Amount:            earnedAmount,
+				Type:              types.ServiceProviderCreditLedgerTypeOrderFinished,
+				OrderID:           uuid.NullUUID{UUID: order.ID, Valid: true},
+				Note:              null.StringFrom("finished sessions of a canceled order"),
+			})
+			if err != nil {
+				return res, err
+			}
+		}
+
+		refundAmount := refundableAmount
+		if canceledBy == types.UserRoleServiceProvider {
+			refundAmount = refundableAmount.Add(decimal.NewFromInt32(payment.AdminFee)).Add(decimal.NewFromInt32(payment.PlatformFee))
+		} else if serviceStartAt.Sub(timeNow) < types.OrderCancellationFullRefundWindow {
+			refundAmount = refundableAmount.Mul(decimal.NewFromInt(types.OrderCancellationLateRefundPercentage)).Div(decimal.NewFromInt(100)).RoundFloor(0)
+
+			err = s.serviceProviderCreditSvc.AddTx(ctx, types.ServiceProviderCreditAddReq{
+				Tx:                tx,
+				ServiceProviderID: order.ServiceProviderID,
+				Amount:            refundableAmount.Sub(refundAmount),
+				Type:              types.ServiceProviderCreditLedgerTypeAdjustment,
+				OrderID:           uuid.NullUUID{UUID: order.ID, Valid: true},
+				Note:              null.StringFrom("late cancellation fee"),
+			})
+			if err != nil {
+				return res, err
+			}
+		}
+
+		id, err := uuid.NewV7()
+		if err != nil {
+			return res, errors.New(err)
+		}
+		refund = types.Refund{
+			ID:        id,
+			OrderID:   order.ID,
+			PaymentID: payment.ID,
+			Amount:    refundAmount,
+			Reason:    reason,
+			Status:    types.RefundStatusPending,
+			CreatedAt: timeNow,
+		}
+
+		if err = s.refundRepo.CreateTx(ctx, tx, refund); err != nil {
+			return res, err
+		}
+	}
+
+	id, err := uuid.NewV7()
+	if err != nil {
+		return res, errors.New(err)
+	}
+	consumerNotif := types.ConsumerNotification{
+		ID:        id,
+		UserID:    order.UserID,
+		OrderID:   uuid.NullUUID{UUID: order.ID, Valid: true},
+		Type:      types.ConsumerNotificationTypeOrderCanceled,
+		CreatedAt: timeNow,
+	}
+
+	id, err = uuid.NewV7()
+	if err != nil {
+		return res, errors.New(err)
+	}
+	providerNotif := types.ServiceProviderNotification{
+		ID:                id,
+		ServiceProviderID: order.ServiceProviderID,
+		OrderID:           uuid.NullUUID{UUID: order.ID, Valid: true},
+		Type:              types.ServiceProviderNotificationTypeOrderCanceled,
+		CreatedAt:         timeNow,
 	}
 
 	if err = s.consumerNotificationRepo.CreateTx(ctx, tx, consumerNotif); err != nil {
-		return err
+		return res, err
 	}
 
 	if err = s.serviceProviderNotificationRepo.CreateTx(ctx, tx, providerNotif); err != nil {
-		return err
+		return res, err
 	}
 
-	consumerFCMToken, err := s.fcmRepo.Find(ctx, types.FCMTokenKey(order.UserID))
-	if !errors.Is(err, types.ErrNoData) && err != nil {
-		return err
+	// the receiver is the side that did not cancel the order
+	receiverUserID := order.UserID
+	receiverNotifType := int16(consumerNotif.Type)
+	pushTitle := fmt.Sprintf("%s canceled the order", provider.Name)
+	if canceledBy == types.UserRoleConsumer {
+		receiverUserID = provider.UserID
+		receiverNotifType = int16(providerNotif.Type)
+		pushTitle = "The consumer canceled the order"
 	}
 
-	providerFCMToken, err := s.fcmRepo.Find(ctx, types.FCMTokenKey(provider.UserID))
-	if !errors.Is(err, types.ErrNoData) && err != nil {
-		return err
+	pushEvent, err := types.NewOutboxPushNotification(receiverUserID, receiverNotifType, pushTitle, reason)
+	if err != nil {
+		return res, err
 	}
 
-	if consumerFCMToken != "" {
-		err = s.notificationSvc.SendPush(ctx, types.NotificationSendReq{
-			Title:   fmt.Sprintf("%s order finished", provider.Name),
-			Message: "rate provider now",
-			Token:   consumerFCMToken,
-		})
-		if err != nil {
-			return err
+	if err = s.outboxSvc.CreateTx(ctx, tx, pushEvent); err != nil {
+		return res, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return res, errors.New(err)
+	}
+
+	s.outboxSvc.Dispatch(ctx, pushEvent)
+
+	// the order stays canceled when midtrans fails, the refund is kept as failed so an admin can retry it
+	if refund.ID != uuid.Nil {
+		res.RefundAmount = refund.Amount
+		res.RefundStatus = types.RefundStatusSucceeded
+
+		if err = s.refundSvc.Process(ctx, refund.ID); err != nil {
+			log.Error().Stack().Err(err).Str("refund_id", refund.ID.String()).Send()
+			res.RefundStatus = types.RefundStatusFailed
 		}
 	}
 
-	if providerFCMToken != "" {
-		err = s.notificationSvc.SendPush(ctx, types.NotificationSendReq{
-			Title:   "Order finished",
-			Message: "the service fee has been added to your credit",
-			Token:   providerFCMToken,
+	return res, nil
+}
+
+func (s *orderImpl) toSessionsRes(sessions types.OrderSessions, reqTz *time.Location) (types.OrderProgressRes, []types.OrderSessionRes) {
+	sessionsRes := []types.OrderSessionRes{}
+	for _, session := range sessions {
+		sessionsRes = append(sessionsRes, types.OrderSessionRes{
+			ID:          session.ID,
+			Sequence:    session.Sequence,
+			ServiceDate: session.ServiceDate.Format(time.DateOnly),
+			ServiceTime: s.utilSvc.NormalizeTimeOnlyTz(session.ServiceTime).In(reqTz).Format(time.TimeOnly),
+			Status:      session.Status,
+			FinishedAt:  session.FinishedAt,
 		})
-		if err != nil {
-			return err
-		}
 	}
 
-	if err = tx.Commit(); err != nil {
-		return err
+	progress := types.OrderProgressRes{
+		FinishedSessions: sessions.CountByStatus(types.OrderSessionStatusFinished),
+		TotalSessions:    len(sessions),
 	}
 
-	return nil
+	return progress, sessionsRes
 }
 
 func (s *orderImpl) TaskUpdateOrderStatus(ctx context.Context) error {
@@ -760,7 +1146,13 @@ func (s *orderImpl) TaskUpdateOrderStatus(ctx context.Context) error {
 			return err
 		}
 
-		if len(expiredOrderIDs) == 0 && len(onGoingOrderIDs) == 0 {
+		// every session of a recurring order starts on its own date, the order itself is ongoing since its first session
+		onGoingSessionIDs, err := s.orderSessionRepo.FindIDsWhereOngoingToday(ctx, now)
+		if err != nil {
+			return err
+		}
+
+		if len(expiredOrderIDs) == 0 && len(onGoingOrderIDs) == 0 && len(onGoingSessionIDs) == 0 {
 			break
 		}
 
@@ -776,6 +1168,11 @@ func (s *orderImpl) TaskUpdateOrderStatus(ctx context.Context) error {
 			if err != nil {
 				return err
 			}
+
+			err = s.orderSessionRepo.UpdateAsCanceledByOrderIDsTx(ctx, tx, expiredOrderIDs)
+			if err != nil {
+				return err
+			}
 		}
 
 		if len(onGoingOrderIDs) > 0 {
@@ -785,6 +1182,13 @@ func (s *orderImpl) TaskUpdateOrderStatus(ctx context.Context) error {
 			}
 		}
 
+		if len(onGoingSessionIDs) > 0 {
+			err = s.orderSessionRepo.UpdateStatusByIDs(ctx, tx, onGoingSessionIDs, types.OrderSessionStatusOngoing)
+			if err != nil {
+				return err
+			}
+		}
+
 		err = tx.Commit()
 		if err != nil {
 			return errors.New(err)
@@ -0,0 +1,276 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"kelarin/internal/config"
+	"kelarin/internal/queue/task"
+	"kelarin/internal/repository"
+	"kelarin/internal/types"
+	"kelarin/internal/utils"
+	dbUtil "kelarin/internal/utils/dbutil"
+	"time"
+
+	"github.com/go-errors/errors"
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+	"github.com/rs/zerolog/log"
+	"github.com/volatiletech/null/v9"
+)
+
+// Outbox defers side effects outside of the database, events are written in the same transaction as the change
+// that caused them and processed by the worker once the transaction is committed
+type Outbox interface {
+	CreateTx(ctx context.Context, tx dbUtil.Tx, events ...types.OutboxEvent) error
+	Dispatch(ctx context.Context, events ...types.OutboxEvent)
+	Process(ctx context.Context, ID uuid.UUID) error
+	TaskSweepPending(ctx context.Context) error
+}
+
+type outboxImpl struct {
+	cfg                     *config.Config
+	beginMainDBTx           dbUtil.SqlxTx
+	outboxEventRepo         repository.OutboxEvent
+	outboxTask              task.Outbox
+	notificationSvc         Notification
+	serviceIndexRepo        repository.ServiceIndex
+	serviceRepo             repository.Service
+	serviceCategoryRepo     repository.ServiceCategory
+	serviceProviderRepo     repository.ServiceProvider
+	serviceProviderAreaRepo repository.ServiceProviderArea
+}
+
+func NewOutbox(
+	cfg *config.Config,
+	beginMainDBTx dbUtil.SqlxTx,
+	outboxEventRepo repository.OutboxEvent,
+	outboxTask task.Outbox,
+	notificationSvc Notification,
+	serviceIndexRepo repository.ServiceIndex,
+	serviceRepo repository.Service,
+	serviceCategoryRepo repository.ServiceCategory,
+	serviceProviderRepo repository.ServiceProvider,
+	serviceProviderAreaRepo repository.ServiceProviderArea,
+) Outbox {
+	return &outboxImpl{
+		cfg:                     cfg,
+		beginMainDBTx:           beginMainDBTx,
+		outboxEventRepo:         outboxEventRepo,
+		outboxTask:              outboxTask,
+		notificationSvc:         notificationSvc,
+		serviceIndexRepo:        serviceIndexRepo,
+		serviceRepo:             serviceRepo,
+		serviceCategoryRepo:     serviceCategoryRepo,
+		serviceProviderRepo:     serviceProviderRepo,
+		serviceProviderAreaRepo: serviceProviderAreaRepo,
+	}
+}
+
+func (s *outboxImpl) CreateTx(ctx context.Context, tx dbUtil.Tx, events ...types.OutboxEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	return s.outboxEventRepo.BulkCreateTx(ctx, tx, events)
+}
+
+// Dispatch must be called after the transaction is committed, an event that fails to be enqueued is picked up by the sweeper
+func (s *outboxImpl) Dispatch(ctx context.Context, events ...types.OutboxEvent) {
+	queueName := types.GetQueueName(types.QueuePriorityCritical, s.cfg.Environment)
+
+	for _, event := range events {
+		if err := s.outboxTask.Process(ctx, queueName, event.ID); err != nil {
+			log.Error().Stack().Err(err).Str("outbox_event_id", event.ID.String()).Send()
+		}
+
+		// a push is sent along with a new notification, the unread count of the recipient is refreshed right away
+		if event.Type != types.OutboxEventTypePushNotification {
+			continue
+		}
+
+		payload := types.OutboxPushNotificationPayload{}
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			log.Error().Stack().Err(err).Send()
+			continue
+		}
+
+		if err := s.notificationSvc.PublishUnreadCount(ctx, payload.UserID); err != nil {
+			log.Error().Stack().Err(err).Send()
+		}
+	}
+}
+
+// Process runs the side effect of a pending event, a failed event is retried by the queue until it runs out of attempts and is marked as dead
+func (s *outboxImpl) Process(ctx context.Context, ID uuid.UUID) error {
+	tx, err := s.beginMainDBTx(ctx, nil)
+	if err != nil {
+		return errors.New(err)
+	}
+
+	defer tx.Rollback()
+
+	event, err := s.outboxEventRepo.FindForUpdateByID(ctx, tx, ID)
+	if errors.Is(err, types.ErrNoData) {
+		// processed by another worker or the transaction that created it was rolled back
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	if event.Status != types.OutboxEventStatusPending {
+		return nil
+	}
+
+	processErr := s.process(ctx, event)
+
+	event.Attempts += 1
+	if processErr == nil {
+		event.Status = types.OutboxEventStatusProcessed
+		event.ProcessedAt = null.TimeFrom(time.Now())
+	} else {
+		event.LastError = null.StringFrom(processErr.Error())
+		if event.Attempts >= types.OutboxMaxAttempts {
+			event.Status = types.OutboxEventStatusDead
+		}
+	}
+
+	if err := s.outboxEventRepo.UpdateTx(ctx, tx, event); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.New(err)
+	}
+
+	if event.Status == types.OutboxEventStatusDead {
+		log.Error().Stack().Err(processErr).Str("outbox_event_id", event.ID.String()).Msg("outbox event is dead")
+		return errors.Errorf("%w: %w", processErr, asynq.SkipRetry)
+	}
+
+	return processErr
+}
+
+// TaskSweepPending enqueues the events that are still pending after the grace period
+func (s *outboxImpl) TaskSweepPending(ctx context.Context) error {
+	IDs, err := s.outboxEventRepo.FindPendingIDs(ctx, time.Now().Add(-types.OutboxPendingGracePeriod), types.OutboxSweepLimit)
+	if err != nil {
+		return err
+	}
+
+	queueName := types.GetQueueName(types.QueuePriorityDefault, s.cfg.Environment)
+	for _, ID := range IDs {
+		if err := s.outboxTask.Process(ctx, queueName, ID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *outboxImpl) process(ctx context.Context, event types.OutboxEvent) error {
+	switch event.Type {
+	case types.OutboxEventTypePushNotification:
+		payload := types.OutboxPushNotificationPayload{}
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return errors.New(err)
+		}
+
+		// the email is queued first, a push that fails retries the event and the email is not queued again
+		if err := s.queueEmail(ctx, event.ID, payload); err != nil {
+			return err
+		}
+
+		return s.sendPush(ctx, payload)
+	case types.OutboxEventTypeServiceIndexSync:
+		payload := types.OutboxServiceIndexSyncPayload{}
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return errors.New(err)
+		}
+
+		return s.syncServiceIndex(ctx, payload.ServiceID)
+	default:
+		return errors.Errorf("unknown outbox event type: %s", event.Type)
+	}
+}
+
+func (s *outboxImpl) sendPush(ctx context.Context, payload types.OutboxPushNotificationPayload) error {
+	return s.notificationSvc.SendPushToUser(ctx, payload.UserID, types.NotificationSendReq{
+		NotificationType: payload.NotificationType,
+		Title:            payload.Title,
+		Message:          payload.Message,
+		ImageURL:         payload.ImageURL,
+	})
+}
+
+// queueEmail only queues a notification with a type, the type picks the template of the email
+func (s *outboxImpl) queueEmail(ctx context.Context, eventID uuid.UUID, payload types.OutboxPushNotificationPayload) error {
+	if payload.NotificationType == 0 {
+		return nil
+	}
+
+	return s.notificationSvc.QueueEmailToUser(ctx, eventID, types.NotificationSendEmailReq{
+		UserID:           payload.UserID,
+		NotificationType: payload.NotificationType,
+		Message:          payload.Message,
+	})
+}
+
+func (s *outboxImpl) syncServiceIndex(ctx context.Context, serviceID uuid.UUID) error {
+	service, err := s.serviceRepo.FindByID(ctx, serviceID)
+	if errors.Is(err, types.ErrNoData) || (err == nil && service.IsDeleted) {
+		return s.serviceIndexRepo.Delete(ctx, types.ServiceIndex{ID: serviceID})
+	} else if err != nil {
+		return err
+	}
+
+	provider, err := s.serviceProviderRepo.FindByID(ctx, service.ServiceProviderID)
+	if err != nil {
+		return err
+	}
+
+	categories, err := s.serviceCategoryRepo.FindByServiceIDs(ctx, []uuid.UUID{service.ID})
+	if err != nil {
+		return err
+	}
+
+	categoriesName := []string{}
+	for _, category := range categories {
+		categoriesName = append(categoriesName, category.Name)
+	}
+
+	doc := types.ServiceIndex{
+		ID:                    service.ID,
+		ServiceProviderID:     service.ServiceProviderID,
+		Name:                  service.Name,
+		Description:           service.Description,
+		DeliveryMethods:       service.DeliveryMethods,
+		Categories:            categoriesName,
+		Rules:                 service.Rules,
+		FeeStartAt:            service.FeeStartAt,
+		FeeEndAt:              service.FeeEndAt,
+		IsAvailable:           service.IsAvailable,
+		Images:                service.Images,
+		ReceivedRatingCount:   service.ReceivedRatingCount,
+		ReceivedRatingAverage: service.ReceivedRatingAverage,
+		CreatedAt:             service.CreatedAt,
+	}
+
+	area, err := s.serviceProviderAreaRepo.FindByServiceProviderID(ctx, provider.ID)
+	if err == nil {
+		doc.Province = area.ProvinceName
+		doc.City = area.CityName
+	} else if !errors.Is(err, types.ErrNoData) {
+		return err
+	}
+
+	if provider.OfficeCoordinates.Valid {
+		lat, lng, err := utils.ParseLatLngFromHexStr(provider.OfficeCoordinates.String)
+		if err != nil {
+			return errors.New(err)
+		}
+
+		doc.Location = &types.ServiceIndexLocation{Lat: lat, Lon: lng}
+	}
+
+	return s.serviceIndexRepo.Create(ctx, doc)
+}
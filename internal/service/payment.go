@@ -37,13 +37,14 @@ type paymentImpl struct {
 	paymentMethodRepo               repository.PaymentMethod
 	orderRepo                       repository.Order
 	midtransSvc                     Midtrans
-	notificationSvc                 Notification
-	fcmTokenRepo                    repository.FCMToken
 	consumerNotificationRepo        repository.ConsumerNotification
 	serviceProviderNotificationRepo repository.ServiceProviderNotification
+	refundRepo                      repository.Refund
+	refundSvc                       Refund
+	outboxSvc                       Outbox
 }
 
-func NewPayment(cfg *config.Config, beginMainDBTx dbUtil.SqlxTx, paymentRepo repository.Payment, paymentMethodRepo repository.PaymentMethod, orderRepo repository.Order, midtransSvc Midtrans, notificationSvc Notification, fcmTokenRepo repository.FCMToken, consumerNotificationRepo repository.ConsumerNotification, serviceProviderNotificationRepo repository.ServiceProviderNotification) Payment {
+func NewPayment(cfg *config.Config, beginMainDBTx dbUtil.SqlxTx, paymentRepo repository.Payment, paymentMethodRepo repository.PaymentMethod, orderRepo repository.Order, midtransSvc Midtrans, consumerNotificationRepo repository.ConsumerNotification, serviceProviderNotificationRepo repository.ServiceProviderNotification, refundRepo repository.Refund, refundSvc Refund, outboxSvc Outbox) Payment {
 	return &paymentImpl{
 		cfg:                             &cfg.Midtrans,
 		beginMainDBTx:                   beginMainDBTx,
@@ -51,10 +52,11 @@ func NewPayment(cfg *config.Config, beginMainDBTx dbUtil.SqlxTx, paymentRepo rep
 		paymentMethodRepo:               paymentMethodRepo,
 		orderRepo:                       orderRepo,
 		midtransSvc:                     midtransSvc,
-		notificationSvc:                 notificationSvc,
-		fcmTokenRepo:                    fcmTokenRepo,
 		consumerNotificationRepo:        consumerNotificationRepo,
 		serviceProviderNotificationRepo: serviceProviderNotificationRepo,
+		refundRepo:                      refundRepo,
+		refundSvc:                       refundSvc,
+		outboxSvc:                       outboxSvc,
 	}
 }
 
@@ -91,6 +93,10 @@ func (s *paymentImpl) Create(ctx context.Context, req types.PaymentCreateReq) (t
 		return res, errors.New(types.AppErr{Code: http.StatusForbidden, Message: "order already paid"})
 	}
 
+	if order.Status == types.OrderStatusCanceled {
+		return res, errors.New(types.AppErr{Code: http.StatusForbidden, Message: "order canceled"})
+	}
+
 	adminFee := s.CalculateAdminFee(order.ServiceFee, paymentMethod.AdminFee, paymentMethod.AdminFeeUnit)
 	totalFee := order.ServiceFee.Add(adminFee).Add(decimal.NewFromInt(PlatformFee))
 
@@ -218,6 +224,9 @@ func (s *paymentImpl) MidtransNotification(ctx context.Context, req types.Paymen
 		return err
 	}
 
+	// midtrans may send the same notification more than once
+	alreadyPaid := payment.Status == types.PaymentStatusPaid
+
 	updatedAt := null.TimeFrom(time.Now())
 	switch req.TransactionStatus {
 	case types.MidtransTransactionStatusPending:
@@ -250,7 +259,32 @@ func (s *paymentImpl) MidtransNotification(ctx context.Context, req types.Paymen
 		return err
 	}
 
-	if payment.Status == types.PaymentStatusPaid {
+	// the order was canceled while the payment was still pending, the whole payment is sent back instead of fulfilling the order
+	var refund types.Refund
+	pushEvents := []types.OutboxEvent{}
+	if payment.Status == types.PaymentStatusPaid && order.Status == types.OrderStatusCanceled {
+		if alreadyPaid {
+			return nil
+		}
+
+		id, err = uuid.NewV7()
+		if err != nil {
+			return errors.New(err)
+		}
+		refund = types.Refund{
+			ID:        id,
+			OrderID:   order.ID,
+			PaymentID: payment.ID,
+			Amount:    payment.Amount.Add(decimal.NewFromInt32(payment.AdminFee)).Add(decimal.NewFromInt32(payment.PlatformFee)),
+			Reason:    "order canceled before the payment settled",
+			Status:    types.RefundStatusPending,
+			CreatedAt: timeNow,
+		}
+
+		if err = s.refundRepo.CreateTx(ctx, tx, refund); err != nil {
+			return err
+		}
+	} else if payment.Status == types.PaymentStatusPaid {
 		if err = s.orderRepo.UpdateAsPaymentFulfilledTx(ctx, tx, order.Order); err != nil {
 			return err
 		}
@@ -272,22 +306,6 @@ func (s *paymentImpl) MidtransNotification(ctx context.Context, req types.Paymen
 			return err
 		}
 
-		userFCMToken, err := s.fcmTokenRepo.Find(ctx, types.FCMTokenKey(order.UserID))
-		if !errors.Is(err, types.ErrNoData) && err != nil {
-			return err
-		}
-
-		if userFCMToken != "" {
-			err = s.notificationSvc.SendPush(ctx, types.NotificationSendReq{
-				Title:   "Payment Success",
-				Message: "Your order has been paid",
-				Token:   userFCMToken,
-			})
-			if err != nil {
-				return err
-			}
-		}
-
 		id, err = uuid.NewV7()
 		if err != nil {
 			return errors.New(err)
@@ -304,20 +322,24 @@ func (s *paymentImpl) MidtransNotification(ctx context.Context, req types.Paymen
 			return err
 		}
 
-		providerFCMToken, err := s.fcmTokenRepo.Find(ctx, types.FCMTokenKey(order.ServiceProviderUserID))
-		if !errors.Is(err, types.ErrNoData) && err != nil {
+		consumerPushEvent, err := types.NewOutboxPushNotification(order.UserID, int16(consumerNotif.Type), "Payment Success", "Your order has been paid")
+		if err != nil {
 			return err
 		}
 
-		if providerFCMToken != "" {
-			err = s.notificationSvc.SendPush(ctx, types.NotificationSendReq{
-				Title:   fmt.Sprintf("%s has fulfilled the payment", order.UserName),
-				Message: "Remember to check the service schedule!",
-				Token:   providerFCMToken,
-			})
-			if err != nil {
-				return err
-			}
+		providerPushEvent, err := types.NewOutboxPushNotification(
+			order.ServiceProviderUserID,
+			int16(providerNotif.Type),
+			fmt.Sprintf("%s has fulfilled the payment", order.UserName),
+			"Remember to check the service schedule!",
+		)
+		if err != nil {
+			return err
+		}
+
+		pushEvents = append(pushEvents, consumerPushEvent, providerPushEvent)
+		if err = s.outboxSvc.CreateTx(ctx, tx, pushEvents...); err != nil {
+			return err
 		}
 	}
 
@@ -325,6 +347,14 @@ func (s *paymentImpl) MidtransNotification(ctx context.Context, req types.Paymen
 		return errors.New(err)
 	}
 
+	s.outboxSvc.Dispatch(ctx, pushEvents...)
+
+	if refund.ID != uuid.Nil {
+		if err = s.refundSvc.Process(ctx, refund.ID); err != nil {
+			log.Error().Stack().Err(err).Str("refund_id", refund.ID.String()).Send()
+		}
+	}
+
 	return nil
 }
 
@@ -35,12 +35,13 @@ func TestPaymentService(t *testing.T) {
 	paymentMethodRepo := repoMock.NewPaymentMethod(t)
 	orderRepo := repoMock.NewOrder(t)
 	midtransSvc := serviceMock.NewMidtrans(t)
-	notificationSvc := serviceMock.NewNotification(t)
-	fcmTokenRepo := repoMock.NewFCMToken(t)
 	consumerNotificationRepo := repoMock.NewConsumerNotification(t)
 	serviceProviderNotificationRepo := repoMock.NewServiceProviderNotification(t)
+	refundRepo := repoMock.NewRefund(t)
+	refundSvc := serviceMock.NewRefund(t)
+	outboxSvc := serviceMock.NewOutbox(t)
 
-	paymentService := service.NewPayment(&config.Config{}, beginMainDBTx, paymentRepo, paymentMethodRepo, orderRepo, midtransSvc, notificationSvc, fcmTokenRepo, consumerNotificationRepo, serviceProviderNotificationRepo)
+	paymentService := service.NewPayment(&config.Config{}, beginMainDBTx, paymentRepo, paymentMethodRepo, orderRepo, midtransSvc, consumerNotificationRepo, serviceProviderNotificationRepo, refundRepo, refundSvc, outboxSvc)
 
 	amount := decimal.NewFromInt(328000)
 
@@ -0,0 +1,283 @@
+package service
+
+import (
+	"context"
+	"kelarin/internal/repository"
+	"kelarin/internal/types"
+	dbUtil "kelarin/internal/utils/dbutil"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-errors/errors"
+	"github.com/google/uuid"
+	"github.com/midtrans/midtrans-go/coreapi"
+	"github.com/volatiletech/null/v9"
+)
+
+type Refund interface {
+	Process(ctx context.Context, ID uuid.UUID) error
+
+	AdminGetAll(ctx context.Context, req types.RefundAdminGetAllReq) ([]types.RefundAdminGetAllRes, types.PaginationRes, error)
+	AdminRetry(ctx context.Context, req types.RefundAdminActionReq) error
+	AdminMarkAsSucceeded(ctx context.Context, req types.RefundAdminActionReq) error
+}
+
+type refundImpl struct {
+	beginMainDBTx            dbUtil.SqlxTx
+	refundRepo               repository.Refund
+	orderRepo                repository.Order
+	midtransSvc              Midtrans
+	consumerNotificationRepo repository.ConsumerNotification
+	outboxSvc                Outbox
+}
+
+func NewRefund(
+	beginMainDBTx dbUtil.SqlxTx,
+	refundRepo repository.Refund,
+	orderRepo repository.Order,
+	midtransSvc Midtrans,
+	consumerNotificationRepo repository.ConsumerNotification,
+	outboxSvc Outbox,
+) Refund {
+	return &refundImpl{
+		beginMainDBTx:            beginMainDBTx,
+		refundRepo:               refundRepo,
+		orderRepo:                orderRepo,
+		midtransSvc:              midtransSvc,
+		consumerNotificationRepo: consumerNotificationRepo,
+		outboxSvc:                outboxSvc,
+	}
+}
+
+// Process sends a pending or failed refund to midtrans. The refund id is used as the refund key
+// so retrying a refund that already reached midtrans does not refund the consumer twice, a refund left processing
+// because its result could not be stored is sent again the same way
+func (s *refundImpl) Process(ctx context.Context, ID uuid.UUID) error {
+	refund, err := s.claim(ctx, ID)
+	if err != nil {
+		return err
+	}
+
+	if refund.Status == types.RefundStatusSucceeded {
+		return nil
+	}
+
+	_, sendErr := s.midtransSvc.Refund(ctx, refund.PaymentID.String(), &coreapi.RefundReq{
+		RefundKey: refund.ID.String(),
+		Amount:    refund.Amount.IntPart(),
+		Reason:    refund.Reason,
+	})
+
+	if err = s.complete(ctx, ID, sendErr); err != nil {
+		return err
+	}
+
+	return sendErr
+}
+
+func (s *refundImpl) AdminGetAll(ctx context.Context, req types.RefundAdminGetAllReq) ([]types.RefundAdminGetAllRes, types.PaginationRes, error) {
+	res := []types.RefundAdminGetAllRes{}
+	paginationRes := types.PaginationRes{}
+
+	if err := req.Validate(); err != nil {
+		return res, paginationRes, err
+	}
+
+	if err := req.ValidateAndNormalize(); err != nil {
+		return res, paginationRes, err
+	}
+
+	page, err := strconv.Atoi(req.Page)
+	if err != nil {
+		return res, paginationRes, errors.New(err)
+	}
+
+	size, err := strconv.Atoi(req.Size)
+	if err != nil {
+		return res, paginationRes, errors.New(err)
+	}
+
+	refunds, totalItem, err := s.refundRepo.FindAllByFilter(ctx, types.RefundFilter{
+		Status: req.Status,
+		Limit:  size,
+		Offset: (page - 1) * size,
+	})
+	if err != nil {
+		return res, paginationRes, err
+	}
+
+	for _, refund := range refunds {
+		res = append(res, types.RefundAdminGetAllRes{
+			ID:                  refund.ID,
+			OrderID:             refund.OrderID,
+			PaymentID:           refund.PaymentID,
+			PaymentReference:    refund.PaymentReference,
+			UserID:              refund.UserID,
+			UserName:            refund.UserName,
+			ServiceProviderID:   refund.ServiceProviderID,
+			ServiceProviderName: refund.ServiceProviderName,
+			Amount:              refund.Amount,
+			Reason:              refund.Reason,
+			Status:              refund.Status,
+			FailureMessage:      refund.FailureMessage,
+			CreatedAt:           refund.CreatedAt,
+			UpdatedAt:           refund.UpdatedAt,
+		})
+	}
+
+	paginationRes = req.GeneratePaginationResponse(totalItem)
+
+	return res, paginationRes, nil
+}
+
+func (s *refundImpl) AdminRetry(ctx context.Context, req types.RefundAdminActionReq) error {
+	if err := req.Validate(); err != nil {
+		return err
+	}
+
+	err := s.Process(ctx, req.ID)
+	if errors.Is(err, types.ErrNoData) {
+		return errors.New(types.AppErr{Code: http.StatusNotFound, Message: "refund not found"})
+	} else if err != nil {
+		return errors.New(types.AppErr{Code: http.StatusBadGateway, Message: "refund failed, check the failure message"})
+	}
+
+	return nil
+}
+
+// AdminMarkAsSucceeded is used when the refund has been transferred outside midtrans,
+// e.g. the payment method does not support refunds
+func (s *refundImpl) AdminMarkAsSucceeded(ctx context.Context, req types.RefundAdminActionReq) error {
+	if err := req.Validate(); err != nil {
+		return err
+	}
+
+	err := s.complete(ctx, req.ID, nil)
+	if errors.Is(err, types.ErrNoData) {
+		return errors.New(types.AppErr{Code: http.StatusNotFound, Message: "refund not found"})
+	} else if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// claim marks the refund as processing before it is sent, midtrans is called after the lock is released
+// so a slow refund does not block the order. A refund that already succeeded is returned unchanged
+func (s *refundImpl) claim(ctx context.Context, ID uuid.UUID) (types.Refund, error) {
+	tx, err := s.beginMainDBTx(ctx, nil)
+	if err != nil {
+		return types.Refund{}, errors.New(err)
+	}
+
+	defer tx.Rollback()
+
+	refund, err := s.refundRepo.FindForUpdateByID(ctx, tx, ID)
+	if err != nil {
+		return refund, err
+	}
+
+	if refund.Status == types.RefundStatusSucceeded {
+		return refund, nil
+	}
+
+	refund.Status = types.RefundStatusProcessing
+	refund.UpdatedAt = null.TimeFrom(time.Now())
+
+	if err = s.refundRepo.UpdateStatusTx(ctx, tx, refund); err != nil {
+		return refund, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return refund, errors.New(err)
+	}
+
+	return refund, nil
+}
+
+// complete locks the refund and stores the result of sending it, sendErr is nil when the refund succeeded.
+// A refund that already succeeded is skipped
+func (s *refundImpl) complete(ctx context.Context, ID uuid.UUID, sendErr error) error {
+	tx, err := s.beginMainDBTx(ctx, nil)
+	if err != nil {
+		return errors.New(err)
+	}
+
+	defer tx.Rollback()
+
+	refund, err := s.refundRepo.FindForUpdateByID(ctx, tx, ID)
+	if err != nil {
+		return err
+	}
+
+	if refund.Status == types.RefundStatusSucceeded {
+		return nil
+	}
+
+	timeNow := time.Now()
+	refund.UpdatedAt = null.TimeFrom(timeNow)
+
+	if sendErr != nil {
+		refund.Status = types.RefundStatusFailed
+		refund.FailureMessage = null.StringFrom(sendErr.Error())
+
+		if err = s.refundRepo.UpdateStatusTx(ctx, tx, refund); err != nil {
+			return err
+		}
+
+		if err = tx.Commit(); err != nil {
+			return errors.New(err)
+		}
+
+		return nil
+	}
+
+	refund.Status = types.RefundStatusSucceeded
+	refund.FailureMessage = null.String{}
+
+	if err = s.refundRepo.UpdateStatusTx(ctx, tx, refund); err != nil {
+		return err
+	}
+
+	order, err := s.orderRepo.FindForUpdateByID(ctx, tx, refund.OrderID)
+	if errors.Is(err, types.ErrNoData) {
+		return errors.Errorf("order not found: id %s", refund.OrderID)
+	} else if err != nil {
+		return err
+	}
+
+	id, err := uuid.NewV7()
+	if err != nil {
+		return errors.New(err)
+	}
+	consumerNotif := types.ConsumerNotification{
+		ID:        id,
+		UserID:    order.UserID,
+		OrderID:   uuid.NullUUID{UUID: order.ID, Valid: true},
+		PaymentID: uuid.NullUUID{UUID: refund.PaymentID, Valid: true},
+		Type:      types.ConsumerNotificationTypePaymentRefunded,
+		CreatedAt: timeNow,
+	}
+
+	if err = s.consumerNotificationRepo.CreateTx(ctx, tx, consumerNotif); err != nil {
+		return err
+	}
+
+	pushEvent, err := types.NewOutboxPushNotification(order.UserID, int16(consumerNotif.Type), "Payment refunded", "Your refund has been sent to your payment method")
+	if err != nil {
+		return err
+	}
+
+	if err = s.outboxSvc.CreateTx(ctx, tx, pushEvent); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return errors.New(err)
+	}
+
+	s.outboxSvc.Dispatch(ctx, pushEvent)
+
+	return nil
+}
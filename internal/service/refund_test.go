@@ -0,0 +1,138 @@
+package service_test
+
+import (
+	"context"
+	repoMock "kelarin/internal/mocks/repository"
+	serviceMock "kelarin/internal/mocks/service"
+	"kelarin/internal/service"
+	"kelarin/internal/types"
+	dbUtil "kelarin/internal/utils/dbutil"
+	"testing"
+
+	"github.com/go-errors/errors"
+	"github.com/google/uuid"
+	"github.com/midtrans/midtrans-go/coreapi"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	sqlxmock "github.com/zhashkevych/go-sqlxmock"
+)
+
+func TestRefundService(t *testing.T) {
+	ctx := context.Background()
+
+	type deps struct {
+		dbMock                   sqlxmock.Sqlmock
+		refundRepo               *repoMock.Refund
+		orderRepo                *repoMock.Order
+		midtransSvc              *serviceMock.Midtrans
+		consumerNotificationRepo *repoMock.ConsumerNotification
+		outboxSvc                *serviceMock.Outbox
+	}
+
+	newService := func(t *testing.T) (service.Refund, deps) {
+		db, dbMock, err := sqlxmock.Newx()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+
+		t.Cleanup(func() {
+			assert.NoError(t, dbMock.ExpectationsWereMet())
+			db.Close()
+		})
+
+		d := deps{
+			dbMock:                   dbMock,
+			refundRepo:               repoMock.NewRefund(t),
+			orderRepo:                repoMock.NewOrder(t),
+			midtransSvc:              serviceMock.NewMidtrans(t),
+			consumerNotificationRepo: repoMock.NewConsumerNotification(t),
+			outboxSvc:                serviceMock.NewOutbox(t),
+		}
+
+		return service.NewRefund(dbUtil.NewSqlxTx(db), d.refundRepo, d.orderRepo, d.midtransSvc, d.consumerNotificationRepo, d.outboxSvc), d
+	}
+
+	newRefund := func() types.Refund {
+		return types.Refund{
+			ID:        uuid.New(),
+			OrderID:   uuid.New(),
+			PaymentID: uuid.New(),
+			Amount:    decimal.NewFromInt(150000),
+			Reason:    "order canceled",
+			Status:    types.RefundStatusPending,
+		}
+	}
+
+	hasStatus := func(status types.RefundStatus) interface{} {
+		return mock.MatchedBy(func(refund types.Refund) bool { return refund.Status == status })
+	}
+
+	t.Run("Test Process calls midtrans after the processing status is committed", func(t *testing.T) {
+		refundSvc, d := newService(t)
+		refund := newRefund()
+		processing := refund
+		processing.Status = types.RefundStatusProcessing
+
+		d.dbMock.ExpectBegin()
+		d.dbMock.ExpectCommit()
+		d.dbMock.ExpectBegin()
+		d.dbMock.ExpectCommit()
+
+		d.refundRepo.Mock.On("FindForUpdateByID", ctx, mock.Anything, refund.ID).Return(refund, nil).Once()
+		d.refundRepo.Mock.On("UpdateStatusTx", ctx, mock.Anything, hasStatus(types.RefundStatusProcessing)).Return(nil).Once()
+		d.midtransSvc.Mock.On("Refund", ctx, refund.PaymentID.String(), mock.MatchedBy(func(req *coreapi.RefundReq) bool {
+			return req.RefundKey == refund.ID.String() && req.Amount == refund.Amount.IntPart()
+		})).Return(&coreapi.RefundResponse{}, nil)
+		d.refundRepo.Mock.On("FindForUpdateByID", ctx, mock.Anything, refund.ID).Return(processing, nil).Once()
+		d.refundRepo.Mock.On("UpdateStatusTx", ctx, mock.Anything, hasStatus(types.RefundStatusSucceeded)).Return(nil).Once()
+		d.orderRepo.Mock.On("FindForUpdateByID", ctx, mock.Anything, refund.OrderID).Return(types.Order{ID: refund.OrderID, UserID: uuid.New()}, nil)
+		d.consumerNotificationRepo.Mock.On("CreateTx", ctx, mock.Anything, mock.Anything).Return(nil)
+		d.outboxSvc.Mock.On("CreateTx", ctx, mock.Anything, mock.Anything).Return(nil)
+		d.outboxSvc.Mock.On("Dispatch", ctx, mock.Anything).Return()
+
+		err := refundSvc.Process(ctx, refund.ID)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("Test Process stores the midtrans failure", func(t *testing.T) {
+		refundSvc, d := newService(t)
+		refund := newRefund()
+		processing := refund
+		processing.Status = types.RefundStatusProcessing
+		midtransErr := errors.New("midtrans is down")
+
+		d.dbMock.ExpectBegin()
+		d.dbMock.ExpectCommit()
+		d.dbMock.ExpectBegin()
+		d.dbMock.ExpectCommit()
+
+		d.refundRepo.Mock.On("FindForUpdateByID", ctx, mock.Anything, refund.ID).Return(refund, nil).Once()
+		d.refundRepo.Mock.On("UpdateStatusTx", ctx, mock.Anything, hasStatus(types.RefundStatusProcessing)).Return(nil).Once()
+		d.midtransSvc.Mock.On("Refund", ctx, refund.PaymentID.String(), mock.Anything).Return(nil, midtransErr)
+		d.refundRepo.Mock.On("FindForUpdateByID", ctx, mock.Anything, refund.ID).Return(processing, nil).Once()
+		d.refundRepo.Mock.On("UpdateStatusTx", ctx, mock.Anything, mock.MatchedBy(func(refund types.Refund) bool {
+			return refund.Status == types.RefundStatusFailed && refund.FailureMessage.String == midtransErr.Error()
+		})).Return(nil).Once()
+
+		err := refundSvc.Process(ctx, refund.ID)
+
+		assert.ErrorIs(t, err, midtransErr)
+	})
+
+	t.Run("Test Process skips a refund that already succeeded", func(t *testing.T) {
+		refundSvc, d := newService(t)
+		refund := newRefund()
+		refund.Status = types.RefundStatusSucceeded
+
+		d.dbMock.ExpectBegin()
+		d.dbMock.ExpectRollback()
+
+		d.refundRepo.Mock.On("FindForUpdateByID", ctx, mock.Anything, refund.ID).Return(refund, nil)
+
+		err := refundSvc.Process(ctx, refund.ID)
+
+		assert.NoError(t, err)
+	})
+}
@@ -28,25 +28,23 @@ type Service interface {
 
 type serviceImpl struct {
 	beginMainDBTx              dbUtil.SqlxTx
-	serviceIndexRepo           repository.ServiceIndex
 	serviceProviderRepo        repository.ServiceProvider
 	serviceRepo                repository.Service
 	serviceCategoryRepo        repository.ServiceCategory
 	serviceServiceCategoryRepo repository.ServiceServiceCategory
-	serviceProviderAreaRepo    repository.ServiceProviderArea
 	fileSvc                    File
+	outboxSvc                  Outbox
 }
 
-func NewService(beginMainDBTx dbUtil.SqlxTx, serviceIndexRepo repository.ServiceIndex, serviceProviderRepo repository.ServiceProvider, serviceRepo repository.Service, serviceCategoryRepo repository.ServiceCategory, serviceServiceCategoryRepo repository.ServiceServiceCategory, serviceProviderAreaRepo repository.ServiceProviderArea, fileSvc File) Service {
+func NewService(beginMainDBTx dbUtil.SqlxTx, serviceProviderRepo repository.ServiceProvider, serviceRepo repository.Service, serviceCategoryRepo repository.ServiceCategory, serviceServiceCategoryRepo repository.ServiceServiceCategory, fileSvc File, outboxSvc Outbox) Service {
 	return &serviceImpl{
 		beginMainDBTx:              beginMainDBTx,
-		serviceIndexRepo:           serviceIndexRepo,
 		serviceProviderRepo:        serviceProviderRepo,
 		serviceRepo:                serviceRepo,
 		serviceCategoryRepo:        serviceCategoryRepo,
 		serviceServiceCategoryRepo: serviceServiceCategoryRepo,
-		serviceProviderAreaRepo:    serviceProviderAreaRepo,
 		fileSvc:                    fileSvc,
+		outboxSvc:                  outboxSvc,
 	}
 }
 
@@ -128,14 +126,6 @@ func (s *serviceImpl) Create(ctx context.Context, req types.ServiceCreateReq) er
 		}
 	}
 
-	areaExists := true
-	area, err := s.serviceProviderAreaRepo.FindByServiceProviderID(ctx, provider.ID)
-	if errors.Is(err, types.ErrNoData) {
-		areaExists = false
-	} else if err != nil {
-		return err
-	}
-
 	timeNow := time.Now()
 
 	id, err := uuid.NewV7()
@@ -196,32 +186,8 @@ func (s *serviceImpl) Create(ctx context.Context, req types.ServiceCreateReq) er
 		return err
 	}
 
-	categoriesName := []string{}
-	for _, category := range categories {
-		categoriesName = append(categoriesName, category.Name)
-	}
-
-	indexReq := types.ServiceIndex{
-		ID:                service.ID,
-		ServiceProviderID: service.ServiceProviderID,
-		Name:              service.Name,
-		Description:       service.Description,
-		DeliveryMethods:   service.DeliveryMethods,
-		Categories:        categoriesName,
-		Rules:             service.Rules,
-		FeeStartAt:        service.FeeStartAt,
-		FeeEndAt:          service.FeeEndAt,
-		IsAvailable:       service.IsAvailable,
-		Images:            service.Images,
-		CreatedAt:         timeNow,
-	}
-
-	if areaExists {
-		indexReq.Province = area.ProvinceName
-		indexReq.City = area.CityName
-	}
-
-	if err := s.serviceIndexRepo.Create(ctx, indexReq); err != nil {
+	indexSyncEvent, err := s.createIndexSyncEventTx(ctx, tx, service.ID)
+	if err != nil {
 		return err
 	}
 
@@ -229,6 +195,8 @@ func (s *serviceImpl) Create(ctx context.Context, req types.ServiceCreateReq) er
 		return errors.New(err)
 	}
 
+	s.outboxSvc.Dispatch(ctx, indexSyncEvent)
+
 	return nil
 }
 
@@ -310,20 +278,6 @@ func (s *serviceImpl) Update(ctx context.Context, req types.ServiceUpdateReq) er
 		return err
 	}
 
-	area, err := s.serviceProviderAreaRepo.FindByServiceProviderID(ctx, provider.ID)
-	if errors.Is(err, types.ErrNoData) {
-		// do noting
-	} else if err != nil {
-		return err
-	}
-
-	idxService, seqNo, primaryTerm, err := s.serviceIndexRepo.FindByID(ctx, service.ID.String())
-	if errors.Is(err, types.ErrNoData) {
-		return errors.New(fmt.Sprintf("service index not found for service_id: %s", service.ID))
-	} else if err != nil {
-		return err
-	}
-
 	categories, err := s.serviceCategoryRepo.FindByIDs(ctx, req.CategoryIDs)
 	if err != nil {
 		return err
@@ -343,13 +297,11 @@ func (s *serviceImpl) Update(ctx context.Context, req types.ServiceUpdateReq) er
 	}
 
 	serviceCategories := []types.ServiceServiceCategory{}
-	idxCategories := []string{}
 	for _, category := range categories {
 		serviceCategories = append(serviceCategories, types.ServiceServiceCategory{
 			ServiceID:         service.ID,
 			ServiceCategoryID: category.ID,
 		})
-		idxCategories = append(idxCategories, category.Name)
 	}
 
 	service.Name = req.Name
@@ -360,20 +312,6 @@ func (s *serviceImpl) Update(ctx context.Context, req types.ServiceUpdateReq) er
 	service.Rules = req.Rules
 	service.IsAvailable = req.IsAvailable
 
-	idxService.Name = service.Name
-	idxService.Description = service.Description
-	idxService.DeliveryMethods = service.DeliveryMethods
-	idxService.Categories = idxCategories
-	idxService.Rules = service.Rules
-	idxService.FeeStartAt = service.FeeStartAt
-	idxService.FeeEndAt = service.FeeEndAt
-	idxService.IsAvailable = service.IsAvailable
-
-	if area.ID != 0 {
-		idxService.Province = area.ProvinceName
-		idxService.City = area.CityName
-	}
-
 	tx, err := s.beginMainDBTx(ctx, nil)
 	if err != nil {
 		return errors.New(err)
@@ -394,7 +332,8 @@ func (s *serviceImpl) Update(ctx context.Context, req types.ServiceUpdateReq) er
 		return err
 	}
 
-	if err := s.serviceIndexRepo.Update(ctx, idxService, seqNo, primaryTerm); err != nil {
+	indexSyncEvent, err := s.createIndexSyncEventTx(ctx, tx, service.ID)
+	if err != nil {
 		return err
 	}
 
@@ -402,6 +341,8 @@ func (s *serviceImpl) Update(ctx context.Context, req types.ServiceUpdateReq) er
 		return errors.New(err)
 	}
 
+	s.outboxSvc.Dispatch(ctx, indexSyncEvent)
+
 	return nil
 }
 
@@ -424,13 +365,6 @@ func (s *serviceImpl) Delete(ctx context.Context, req types.ServiceDeleteReq) er
 		return err
 	}
 
-	idxService, _, _, err := s.serviceIndexRepo.FindByID(ctx, service.ID.String())
-	if errors.Is(err, types.ErrNoData) {
-		return errors.New(fmt.Sprintf("service index not found for service_id: %s", service.ID))
-	} else if err != nil {
-		return err
-	}
-
 	tx, err := s.beginMainDBTx(ctx, nil)
 	if err != nil {
 		return errors.New(err)
@@ -443,7 +377,8 @@ func (s *serviceImpl) Delete(ctx context.Context, req types.ServiceDeleteReq) er
 		return err
 	}
 
-	if err = s.serviceIndexRepo.Delete(ctx, idxService); err != nil {
+	indexSyncEvent, err := s.createIndexSyncEventTx(ctx, tx, service.ID)
+	if err != nil {
 		return err
 	}
 
@@ -451,6 +386,8 @@ func (s *serviceImpl) Delete(ctx context.Context, req types.ServiceDeleteReq) er
 		return errors.New(err)
 	}
 
+	s.outboxSvc.Dispatch(ctx, indexSyncEvent)
+
 	return nil
 }
 
@@ -473,13 +410,6 @@ func (s *serviceImpl) AddImages(ctx context.Context, req types.ServiceImageActio
 		return err
 	}
 
-	idxService, seqNo, primaryTerm, err := s.serviceIndexRepo.FindByID(ctx, service.ID.String())
-	if errors.Is(err, types.ErrNoData) {
-		return errors.New(fmt.Sprintf("service index not found for service_id: %s", service.ID))
-	} else if err != nil {
-		return err
-	}
-
 	tempFiles := []types.TempFile{}
 	for _, img := range req.ImageKeys {
 		file, err := s.fileSvc.GetTemp(ctx, img)
@@ -496,7 +426,6 @@ func (s *serviceImpl) AddImages(ctx context.Context, req types.ServiceImageActio
 	}
 
 	service.Images = append(service.Images, imgKeys...)
-	idxService.Images = append(idxService.Images, imgKeys...)
 
 	tx, err := s.beginMainDBTx(ctx, nil)
 	if err != nil {
@@ -509,7 +438,8 @@ func (s *serviceImpl) AddImages(ctx context.Context, req types.ServiceImageActio
 		return err
 	}
 
-	if err := s.serviceIndexRepo.Update(ctx, idxService, seqNo, primaryTerm); err != nil {
+	indexSyncEvent, err := s.createIndexSyncEventTx(ctx, tx, service.ID)
+	if err != nil {
 		return err
 	}
 
@@ -517,6 +447,8 @@ func (s *serviceImpl) AddImages(ctx context.Context, req types.ServiceImageActio
 		return errors.New(err)
 	}
 
+	s.outboxSvc.Dispatch(ctx, indexSyncEvent)
+
 	return nil
 }
 
@@ -539,13 +471,6 @@ func (s *serviceImpl) RemoveImages(ctx context.Context, req types.ServiceImageAc
 		return err
 	}
 
-	idxService, seqNo, primaryTerm, err := s.serviceIndexRepo.FindByID(ctx, service.ID.String())
-	if errors.Is(err, types.ErrNoData) {
-		return errors.New(fmt.Sprintf("service index not found for service_id: %s", service.ID))
-	} else if err != nil {
-		return err
-	}
-
 	for _, k := range req.ImageKeys {
 		if exs := slices.Contains(service.Images, k); !exs {
 			return errors.New(types.AppErr{Code: http.StatusNotFound, Message: fmt.Sprintf("image key not found: %s", k)})
@@ -560,7 +485,6 @@ func (s *serviceImpl) RemoveImages(ctx context.Context, req types.ServiceImageAc
 	}
 
 	service.Images = images
-	idxService.Images = images
 
 	tx, err := s.beginMainDBTx(ctx, nil)
 	if err != nil {
@@ -573,7 +497,8 @@ func (s *serviceImpl) RemoveImages(ctx context.Context, req types.ServiceImageAc
 		return err
 	}
 
-	if err := s.serviceIndexRepo.Update(ctx, idxService, seqNo, primaryTerm); err != nil {
+	indexSyncEvent, err := s.createIndexSyncEventTx(ctx, tx, service.ID)
+	if err != nil {
 		return err
 	}
 
@@ -581,5 +506,21 @@ func (s *serviceImpl) RemoveImages(ctx context.Context, req types.ServiceImageAc
 		return errors.New(err)
 	}
 
+	s.outboxSvc.Dispatch(ctx, indexSyncEvent)
+
 	return nil
 }
+
+// createIndexSyncEventTx rebuilds the service document from the database once the transaction is committed
+func (s *serviceImpl) createIndexSyncEventTx(ctx context.Context, tx dbUtil.Tx, serviceID uuid.UUID) (types.OutboxEvent, error) {
+	event, err := types.NewOutboxServiceIndexSync(serviceID)
+	if err != nil {
+		return event, err
+	}
+
+	if err = s.outboxSvc.CreateTx(ctx, tx, event); err != nil {
+		return event, err
+	}
+
+	return event, nil
+}
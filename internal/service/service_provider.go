@@ -101,7 +101,7 @@ func (s *serviceProviderImpl) Register(ctx context.Context, req types.ServicePro
 		long := req.OfficeCoordinates[1].InexactFloat64()
 
 		coordinates := s2.LatLngFromDegrees(lat, long)
-		serviceProvider.OfficeCoordinates = null.StringFrom(fmt.Sprintf("POINT(%f %f)", lat, long))
+		serviceProvider.OfficeCoordinates = null.StringFrom(fmt.Sprintf("POINT(%f %f)", long, lat))
 
 		geocodingResChan := make(chan types.GeocodingReverseRes)
 		errChan := make(chan error)
@@ -0,0 +1,370 @@
+package service
+
+import (
+	"context"
+	"kelarin/internal/repository"
+	"kelarin/internal/types"
+	"kelarin/internal/utils"
+	dbUtil "kelarin/internal/utils/dbutil"
+	"net/http"
+	"time"
+
+	"github.com/go-errors/errors"
+	"github.com/google/uuid"
+)
+
+type ServiceProviderAvailability interface {
+	Find(ctx context.Context, req types.ServiceAvailabilityFindReq) (types.ServiceAvailability, error)
+	GetSlots(ctx context.Context, req types.ServiceAvailabilityGetSlotsReq) (types.ServiceAvailabilityGetSlotsRes, error)
+
+	ProviderGetWorkingHours(ctx context.Context, req types.ServiceProviderWorkingHourProviderGetAllReq) ([]types.ServiceProviderWorkingHourRes, error)
+	ProviderUpdateWorkingHours(ctx context.Context, req types.ServiceProviderWorkingHourProviderUpdateReq) error
+	ProviderGetAllDaysOff(ctx context.Context, req types.ServiceProviderDayOffProviderGetAllReq) ([]types.ServiceProviderDayOffRes, error)
+	ProviderCreateDayOff(ctx context.Context, req types.ServiceProviderDayOffProviderCreateReq) error
+	ProviderDeleteDayOff(ctx context.Context, req types.ServiceProviderDayOffProviderDeleteReq) error
+	ProviderUpdateService(ctx context.Context, req types.ServiceAvailabilityProviderUpdateReq) error
+}
+
+type serviceProviderAvailabilityImpl struct {
+	beginMainDBTx                   dbUtil.SqlxTx
+	serviceProviderAvailabilityRepo repository.ServiceProviderAvailability
+	serviceProviderRepo             repository.ServiceProvider
+	serviceRepo                     repository.Service
+	utilSvc                         Util
+}
+
+func NewServiceProviderAvailability(
+	beginMainDBTx dbUtil.SqlxTx,
+	serviceProviderAvailabilityRepo repository.ServiceProviderAvailability,
+	serviceProviderRepo repository.ServiceProvider,
+	serviceRepo repository.Service,
+	utilSvc Util,
+) ServiceProviderAvailability {
+	return &serviceProviderAvailabilityImpl{
+		beginMainDBTx:                   beginMainDBTx,
+		serviceProviderAvailabilityRepo: serviceProviderAvailabilityRepo,
+		serviceProviderRepo:             serviceProviderRepo,
+		serviceRepo:                     serviceRepo,
+		utilSvc:                         utilSvc,
+	}
+}
+
+// Find loads the schedule of a service between two dates. When req.Tx is set the service is locked first,
+// so a booking made with the result can not race with another booking of the same service
+func (s *serviceProviderAvailabilityImpl) Find(ctx context.Context, req types.ServiceAvailabilityFindReq) (types.ServiceAvailability, error) {
+	res := types.ServiceAvailability{Location: req.Location}
+
+	if req.Tx != nil {
+		if err := s.serviceProviderAvailabilityRepo.LockServiceTx(ctx, req.Tx, req.Service.ID); err != nil {
+			return res, err
+		}
+	}
+
+	workingHours, err := s.serviceProviderAvailabilityRepo.FindWorkingHoursByServiceProviderID(ctx, req.Service.ServiceProviderID)
+	if err != nil {
+		return res, err
+	}
+
+	daysOff, err := s.serviceProviderAvailabilityRepo.FindDaysOffByServiceProviderIDAndDateRange(ctx, req.Service.ServiceProviderID, req.StartDate, req.EndDate)
+	if err != nil {
+		return res, err
+	}
+
+	maxConcurrentJobs, err := s.serviceProviderAvailabilityRepo.FindMaxConcurrentJobsByServiceID(ctx, req.Service.ID)
+	if errors.Is(err, types.ErrNoData) {
+		return res, errors.Errorf("service not found: id %s", req.Service.ID)
+	} else if err != nil {
+		return res, err
+	}
+
+	bookedSessions, err := s.serviceProviderAvailabilityRepo.FindBookedSessionsByServiceIDAndDateRange(ctx, req.Service.ID, req.StartDate, req.EndDate)
+	if err != nil {
+		return res, err
+	}
+
+	res.WorkingHours = workingHours
+	res.DaysOff = daysOff
+	res.MaxConcurrentJobs = maxConcurrentJobs
+	res.BookedSessions = bookedSessions
+
+	return res, nil
+}
+
+func (s *serviceProviderAvailabilityImpl) GetSlots(ctx context.Context, req types.ServiceAvailabilityGetSlotsReq) (types.ServiceAvailabilityGetSlotsRes, error) {
+	res := types.ServiceAvailabilityGetSlotsRes{}
+
+	if err := req.Validate(); err != nil {
+		return res, err
+	}
+
+	service, err := s.serviceRepo.FindByID(ctx, req.ID)
+	if errors.Is(err, types.ErrNoData) {
+		return res, errors.New(types.AppErr{Code: http.StatusNotFound, Message: "service not found"})
+	} else if err != nil {
+		return res, err
+	}
+
+	if service.IsDeleted {
+		return res, errors.New(types.AppErr{Code: http.StatusNotFound, Message: "service not found"})
+	}
+
+	reqTz, err := s.utilSvc.ParseUserTimeZone(req.TimeZone)
+	if err != nil {
+		return res, err
+	}
+
+	startDate, err := time.Parse(time.DateOnly, req.StartDate)
+	if err != nil {
+		return res, errors.New(err)
+	}
+
+	endDate, err := time.Parse(time.DateOnly, req.EndDate)
+	if err != nil {
+		return res, errors.New(err)
+	}
+
+	availability, err := s.Find(ctx, types.ServiceAvailabilityFindReq{
+		Service:   service,
+		Location:  reqTz,
+		StartDate: startDate,
+		EndDate:   endDate,
+	})
+	if err != nil {
+		return res, err
+	}
+
+	res.MaxConcurrentJobs = availability.MaxConcurrentJobs
+	res.Dates = []types.ServiceAvailabilityGetSlotsResDate{}
+
+	today := utils.DateNowInUTC()
+	for date := startDate; !date.After(endDate); date = date.AddDate(0, 0, 1) {
+		item := types.ServiceAvailabilityGetSlotsResDate{
+			Date:      date.Format(time.DateOnly),
+			FreeSlots: []string{},
+		}
+
+		for _, dayOff := range availability.DaysOff {
+			if dayOff.Date.Format(time.DateOnly) == item.Date {
+				item.DayOff = true
+			}
+		}
+
+		if !date.Before(today) {
+			for _, slot := range availability.FreeSlots(date) {
+				item.FreeSlots = append(item.FreeSlots, types.FormatClock(slot))
+			}
+		}
+
+		res.Dates = append(res.Dates, item)
+	}
+
+	return res, nil
+}
+
+func (s *serviceProviderAvailabilityImpl) ProviderGetWorkingHours(ctx context.Context, req types.ServiceProviderWorkingHourProviderGetAllReq) ([]types.ServiceProviderWorkingHourRes, error) {
+	res := []types.ServiceProviderWorkingHourRes{}
+
+	if err := req.Validate(); err != nil {
+		return res, err
+	}
+
+	provider, err := s.findProvider(ctx, req.AuthUser.ID)
+	if err != nil {
+		return res, err
+	}
+
+	reqTz, err := s.utilSvc.ParseUserTimeZone(req.TimeZone)
+	if err != nil {
+		return res, err
+	}
+
+	workingHours, err := s.serviceProviderAvailabilityRepo.FindWorkingHoursByServiceProviderID(ctx, provider.ID)
+	if err != nil {
+		return res, err
+	}
+
+	for _, wh := range workingHours {
+		res = append(res, types.ServiceProviderWorkingHourRes{
+			DayOfWeek: wh.DayOfWeek,
+			StartTime: s.utilSvc.NormalizeTimeOnlyTz(wh.StartTime).In(reqTz).Format(time.TimeOnly),
+			EndTime:   s.utilSvc.NormalizeTimeOnlyTz(wh.EndTime).In(reqTz).Format(time.TimeOnly),
+		})
+	}
+
+	return res, nil
+}
+
+// ProviderUpdateWorkingHours replaces the whole week, a day without a working hour is a day off every week
+func (s *serviceProviderAvailabilityImpl) ProviderUpdateWorkingHours(ctx context.Context, req types.ServiceProviderWorkingHourProviderUpdateReq) error {
+	if err := req.Validate(); err != nil {
+		return err
+	}
+
+	provider, err := s.findProvider(ctx, req.AuthUser.ID)
+	if err != nil {
+		return err
+	}
+
+	reqTz, err := s.utilSvc.ParseUserTimeZone(req.TimeZone)
+	if err != nil {
+		return err
+	}
+
+	workingHours := []types.ServiceProviderWorkingHour{}
+	for _, wh := range req.WorkingHours {
+		startTime, err := time.ParseInLocation(time.TimeOnly, wh.StartTime, reqTz)
+		if err != nil {
+			return errors.New(err)
+		}
+
+		endTime, err := time.ParseInLocation(time.TimeOnly, wh.EndTime, reqTz)
+		if err != nil {
+			return errors.New(err)
+		}
+
+		workingHours = append(workingHours, types.ServiceProviderWorkingHour{
+			ServiceProviderID: provider.ID,
+			DayOfWeek:         wh.DayOfWeek,
+			StartTime:         startTime,
+			EndTime:           endTime,
+		})
+	}
+
+	tx, err := s.beginMainDBTx(ctx, nil)
+	if err != nil {
+		return errors.New(err)
+	}
+
+	defer tx.Rollback()
+
+	if err = s.serviceProviderAvailabilityRepo.ReplaceWorkingHoursTx(ctx, tx, provider.ID, workingHours); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return errors.New(err)
+	}
+
+	return nil
+}
+
+func (s *serviceProviderAvailabilityImpl) ProviderGetAllDaysOff(ctx context.Context, req types.ServiceProviderDayOffProviderGetAllReq) ([]types.ServiceProviderDayOffRes, error) {
+	res := []types.ServiceProviderDayOffRes{}
+
+	if err := req.Validate(); err != nil {
+		return res, err
+	}
+
+	provider, err := s.findProvider(ctx, req.AuthUser.ID)
+	if err != nil {
+		return res, err
+	}
+
+	// only the upcoming days off are listed
+	today := utils.DateNowInUTC()
+	daysOff, err := s.serviceProviderAvailabilityRepo.FindDaysOffByServiceProviderIDAndDateRange(ctx, provider.ID, today, today.AddDate(1, 0, 0))
+	if err != nil {
+		return res, err
+	}
+
+	for _, dayOff := range daysOff {
+		res = append(res, types.ServiceProviderDayOffRes{
+			ID:        dayOff.ID,
+			Date:      dayOff.Date.Format(time.DateOnly),
+			Reason:    dayOff.Reason,
+			CreatedAt: dayOff.CreatedAt,
+		})
+	}
+
+	return res, nil
+}
+
+// ProviderCreateDayOff does not cancel the orders already booked on the date, the service provider has to cancel them
+func (s *serviceProviderAvailabilityImpl) ProviderCreateDayOff(ctx context.Context, req types.ServiceProviderDayOffProviderCreateReq) error {
+	if err := req.Validate(); err != nil {
+		return err
+	}
+
+	provider, err := s.findProvider(ctx, req.AuthUser.ID)
+	if err != nil {
+		return err
+	}
+
+	date, err := time.Parse(time.DateOnly, req.Date)
+	if err != nil {
+		return errors.New(err)
+	}
+
+	id, err := uuid.NewV7()
+	if err != nil {
+		return errors.New(err)
+	}
+
+	err = s.serviceProviderAvailabilityRepo.CreateDayOff(ctx, types.ServiceProviderDayOff{
+		ID:                id,
+		ServiceProviderID: provider.ID,
+		Date:              date,
+		Reason:            req.Reason,
+		CreatedAt:         time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *serviceProviderAvailabilityImpl) ProviderDeleteDayOff(ctx context.Context, req types.ServiceProviderDayOffProviderDeleteReq) error {
+	if err := req.Validate(); err != nil {
+		return err
+	}
+
+	provider, err := s.findProvider(ctx, req.AuthUser.ID)
+	if err != nil {
+		return err
+	}
+
+	err = s.serviceProviderAvailabilityRepo.DeleteDayOffByIDAndServiceProviderID(ctx, req.ID, provider.ID)
+	if errors.Is(err, types.ErrNoData) {
+		return errors.New(types.AppErr{Code: http.StatusNotFound, Message: "day off not found"})
+	} else if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *serviceProviderAvailabilityImpl) ProviderUpdateService(ctx context.Context, req types.ServiceAvailabilityProviderUpdateReq) error {
+	if err := req.Validate(); err != nil {
+		return err
+	}
+
+	provider, err := s.findProvider(ctx, req.AuthUser.ID)
+	if err != nil {
+		return err
+	}
+
+	service, err := s.serviceRepo.FindByID(ctx, req.ID)
+	if errors.Is(err, types.ErrNoData) {
+		return errors.New(types.AppErr{Code: http.StatusNotFound, Message: "service not found"})
+	} else if err != nil {
+		return err
+	}
+
+	if service.ServiceProviderID != provider.ID || service.IsDeleted {
+		return errors.New(types.AppErr{Code: http.StatusNotFound, Message: "service not found"})
+	}
+
+	return s.serviceProviderAvailabilityRepo.UpdateMaxConcurrentJobsByServiceID(ctx, service.ID, req.MaxConcurrentJobs)
+}
+
+func (s *serviceProviderAvailabilityImpl) findProvider(ctx context.Context, userID uuid.UUID) (types.ServiceProvider, error) {
+	provider, err := s.serviceProviderRepo.FindByUserID(ctx, userID)
+	if errors.Is(err, types.ErrNoData) {
+		return provider, errors.Errorf("service provider not found: user_id %s", userID)
+	} else if err != nil {
+		return provider, err
+	}
+
+	return provider, nil
+}
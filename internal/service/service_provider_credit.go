@@ -0,0 +1,521 @@
+package service
+
+import (
+	"context"
+	"kelarin/internal/repository"
+	"kelarin/internal/types"
+	dbUtil "kelarin/internal/utils/dbutil"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-errors/errors"
+	"github.com/google/uuid"
+	"github.com/volatiletech/null/v9"
+)
+
+type ServiceProviderCredit interface {
+	AddTx(ctx context.Context, req types.ServiceProviderCreditAddReq) error
+
+	ProviderGet(ctx context.Context, req types.ServiceProviderCreditProviderGetReq) (types.ServiceProviderCreditProviderGetRes, error)
+	ProviderGetLedgers(ctx context.Context, req types.ServiceProviderCreditProviderGetLedgersReq) ([]types.ServiceProviderCreditProviderGetLedgersRes, types.PaginationRes, error)
+	ProviderGetAllBankAccounts(ctx context.Context, req types.ServiceProviderBankAccountProviderGetAllReq) ([]types.ServiceProviderBankAccountProviderGetAllRes, error)
+	ProviderCreateBankAccount(ctx context.Context, req types.ServiceProviderBankAccountProviderCreateReq) error
+	ProviderDeleteBankAccount(ctx context.Context, req types.ServiceProviderBankAccountProviderDeleteReq) error
+	ProviderGetAllPayouts(ctx context.Context, req types.PayoutProviderGetAllReq) ([]types.PayoutGetAllRes, types.PaginationRes, error)
+	ProviderCreatePayout(ctx context.Context, req types.PayoutProviderCreateReq) error
+
+	AdminGetAllPayouts(ctx context.Context, req types.PayoutAdminGetAllReq) ([]types.PayoutGetAllRes, types.PaginationRes, error)
+	AdminApprovePayout(ctx context.Context, req types.PayoutAdminActionReq) error
+	AdminRejectPayout(ctx context.Context, req types.PayoutAdminActionReq) error
+	AdminMarkPayoutAsPaid(ctx context.Context, req types.PayoutAdminActionReq) error
+}
+
+type serviceProviderCreditImpl struct {
+	beginMainDBTx                   dbUtil.SqlxTx
+	serviceProviderRepo             repository.ServiceProvider
+	serviceProviderCreditLedgerRepo repository.ServiceProviderCreditLedger
+	serviceProviderBankAccountRepo  repository.ServiceProviderBankAccount
+	payoutRepo                      repository.Payout
+}
+
+func NewServiceProviderCredit(
+	beginMainDBTx dbUtil.SqlxTx,
+	serviceProviderRepo repository.ServiceProvider,
+	serviceProviderCreditLedgerRepo repository.ServiceProviderCreditLedger,
+	serviceProviderBankAccountRepo repository.ServiceProviderBankAccount,
+	payoutRepo repository.Payout,
+) ServiceProviderCredit {
+	return &serviceProviderCreditImpl{
+		beginMainDBTx:                   beginMainDBTx,
+		serviceProviderRepo:             serviceProviderRepo,
+		serviceProviderCreditLedgerRepo: serviceProviderCreditLedgerRepo,
+		serviceProviderBankAccountRepo:  serviceProviderBankAccountRepo,
+		payoutRepo:                      payoutRepo,
+	}
+}
+
+// AddTx locks the service provider row, moves its credit and records the movement in the ledger.
+// It must be called inside the transaction that owns the reason of the movement so both are committed together
+func (s *serviceProviderCreditImpl) AddTx(ctx context.Context, req types.ServiceProviderCreditAddReq) error {
+	if err := req.Validate(); err != nil {
+		return err
+	}
+
+	provider, err := s.serviceProviderRepo.FindForUpdateByID(ctx, req.Tx, req.ServiceProviderID)
+	if errors.Is(err, types.ErrNoData) {
+		return errors.Errorf("service provider not found: id %s", req.ServiceProviderID)
+	} else if err != nil {
+		return err
+	}
+
+	provider.Credit = provider.Credit.Add(req.Amount)
+	if provider.Credit.IsNegative() {
+		return types.ErrServiceProviderInsufficientCredit
+	}
+
+	if err = s.serviceProviderRepo.UpdateCreditTx(ctx, req.Tx, provider); err != nil {
+		return err
+	}
+
+	id, err := uuid.NewV7()
+	if err != nil {
+		return errors.New(err)
+	}
+
+	ledger := types.ServiceProviderCreditLedger{
+		ID:                id,
+		ServiceProviderID: provider.ID,
+		Type:              req.Type,
+		Amount:            req.Amount,
+		Balance:           provider.Credit,
+		OrderID:           req.OrderID,
+		PayoutID:          req.PayoutID,
+		Note:              req.Note,
+		CreatedAt:         time.Now(),
+	}
+
+	return s.serviceProviderCreditLedgerRepo.CreateTx(ctx, req.Tx, ledger)
+}
+
+func (s *serviceProviderCreditImpl) ProviderGet(ctx context.Context, req types.ServiceProviderCreditProviderGetReq) (types.ServiceProviderCreditProviderGetRes, error) {
+	res := types.ServiceProviderCreditProviderGetRes{}
+
+	if err := req.Validate(); err != nil {
+		return res, err
+	}
+
+	provider, err := s.findProvider(ctx, req.AuthUser.ID)
+	if err != nil {
+		return res, err
+	}
+
+	pendingPayoutAmount, err := s.payoutRepo.SumUnpaidAmountByServiceProviderID(ctx, provider.ID)
+	if err != nil {
+		return res, err
+	}
+
+	res = types.ServiceProviderCreditProviderGetRes{
+		Credit:              provider.Credit,
+		PendingPayoutAmount: pendingPayoutAmount,
+	}
+
+	return res, nil
+}
+
+func (s *serviceProviderCreditImpl) ProviderGetLedgers(ctx context.Context, req types.ServiceProviderCreditProviderGetLedgersReq) ([]types.ServiceProviderCreditProviderGetLedgersRes, types.PaginationRes, error) {
+	res := []types.ServiceProviderCreditProviderGetLedgersRes{}
+	paginationRes := types.PaginationRes{}
+
+	if err := req.Validate(); err != nil {
+		return res, paginationRes, err
+	}
+
+	limit, offset, err := s.parsePagination(&req.PaginationReq)
+	if err != nil {
+		return res, paginationRes, err
+	}
+
+	provider, err := s.findProvider(ctx, req.AuthUser.ID)
+	if err != nil {
+		return res, paginationRes, err
+	}
+
+	ledgers, totalItem, err := s.serviceProviderCreditLedgerRepo.FindAllByServiceProviderID(ctx, provider.ID, limit, offset)
+	if err != nil {
+		return res, paginationRes, err
+	}
+
+	for _, ledger := range ledgers {
+		res = append(res, types.ServiceProviderCreditProviderGetLedgersRes{
+			ID:        ledger.ID,
+			Type:      ledger.Type,
+			Amount:    ledger.Amount,
+			Balance:   ledger.Balance,
+			OrderID:   ledger.OrderID,
+			PayoutID:  ledger.PayoutID,
+			Note:      ledger.Note,
+			CreatedAt: ledger.CreatedAt,
+		})
+	}
+
+	paginationRes = req.GeneratePaginationResponse(totalItem)
+
+	return res, paginationRes, nil
+}
+
+func (s *serviceProviderCreditImpl) ProviderGetAllBankAccounts(ctx context.Context, req types.ServiceProviderBankAccountProviderGetAllReq) ([]types.ServiceProviderBankAccountProviderGetAllRes, error) {
+	res := []types.ServiceProviderBankAccountProviderGetAllRes{}
+
+	if err := req.Validate(); err != nil {
+		return res, err
+	}
+
+	provider, err := s.findProvider(ctx, req.AuthUser.ID)
+	if err != nil {
+		return res, err
+	}
+
+	bankAccounts, err := s.serviceProviderBankAccountRepo.FindAllByServiceProviderID(ctx, provider.ID)
+	if err != nil {
+		return res, err
+	}
+
+	for _, bankAccount := range bankAccounts {
+		res = append(res, types.ServiceProviderBankAccountProviderGetAllRes{
+			ID:                bankAccount.ID,
+			BankName:          bankAccount.BankName,
+			AccountNumber:     bankAccount.AccountNumber,
+			AccountHolderName: bankAccount.AccountHolderName,
+			CreatedAt:         bankAccount.CreatedAt,
+		})
+	}
+
+	return res, nil
+}
+
+func (s *serviceProviderCreditImpl) ProviderCreateBankAccount(ctx context.Context, req types.ServiceProviderBankAccountProviderCreateReq) error {
+	if err := req.Validate(); err != nil {
+		return err
+	}
+
+	provider, err := s.findProvider(ctx, req.AuthUser.ID)
+	if err != nil {
+		return err
+	}
+
+	id, err := uuid.NewV7()
+	if err != nil {
+		return errors.New(err)
+	}
+
+	bankAccount := types.ServiceProviderBankAccount{
+		ID:                id,
+		ServiceProviderID: provider.ID,
+		BankName:          req.BankName,
+		AccountNumber:     req.AccountNumber,
+		AccountHolderName: req.AccountHolderName,
+		CreatedAt:         time.Now(),
+	}
+
+	return s.serviceProviderBankAccountRepo.Create(ctx, bankAccount)
+}
+
+func (s *serviceProviderCreditImpl) ProviderDeleteBankAccount(ctx context.Context, req types.ServiceProviderBankAccountProviderDeleteReq) error {
+	if err := req.Validate(); err != nil {
+		return err
+	}
+
+	provider, err := s.findProvider(ctx, req.AuthUser.ID)
+	if err != nil {
+		return err
+	}
+
+	bankAccount, err := s.serviceProviderBankAccountRepo.FindByIDAndServiceProviderID(ctx, req.ID, provider.ID)
+	if errors.Is(err, types.ErrNoData) {
+		return errors.New(types.AppErr{Code: http.StatusNotFound, Message: "bank account not found"})
+	} else if err != nil {
+		return err
+	}
+
+	bankAccount.IsDeleted = true
+	bankAccount.DeletedAt = null.TimeFrom(time.Now())
+
+	return s.serviceProviderBankAccountRepo.Delete(ctx, bankAccount)
+}
+
+func (s *serviceProviderCreditImpl) ProviderGetAllPayouts(ctx context.Context, req types.PayoutProviderGetAllReq) ([]types.PayoutGetAllRes, types.PaginationRes, error) {
+	res := []types.PayoutGetAllRes{}
+	paginationRes := types.PaginationRes{}
+
+	if err := req.Validate(); err != nil {
+		return res, paginationRes, err
+	}
+
+	limit, offset, err := s.parsePagination(&req.PaginationReq)
+	if err != nil {
+		return res, paginationRes, err
+	}
+
+	provider, err := s.findProvider(ctx, req.AuthUser.ID)
+	if err != nil {
+		return res, paginationRes, err
+	}
+
+	payouts, totalItem, err := s.payoutRepo.FindAllByFilter(ctx, types.PayoutFilter{
+		ServiceProviderID: uuid.NullUUID{UUID: provider.ID, Valid: true},
+		Status:            req.Status,
+		Limit:             limit,
+		Offset:            offset,
+	})
+	if err != nil {
+		return res, paginationRes, err
+	}
+
+	for _, payout := range payouts {
+		res = append(res, s.toPayoutGetAllRes(payout))
+	}
+
+	paginationRes = req.GeneratePaginationResponse(totalItem)
+
+	return res, paginationRes, nil
+}
+
+// ProviderCreatePayout deducts the requested amount from the credit right away so the same credit cannot be withdrawn twice,
+// the amount is given back when an admin rejects the payout
+func (s *serviceProviderCreditImpl) ProviderCreatePayout(ctx context.Context, req types.PayoutProviderCreateReq) error {
+	if err := req.Validate(); err != nil {
+		return err
+	}
+
+	provider, err := s.findProvider(ctx, req.AuthUser.ID)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.serviceProviderBankAccountRepo.FindByIDAndServiceProviderID(ctx, req.ServiceProviderBankAccountID, provider.ID)
+	if errors.Is(err, types.ErrNoData) {
+		return errors.New(types.AppErr{Code: http.StatusNotFound, Message: "bank account not found"})
+	} else if err != nil {
+		return err
+	}
+
+	id, err := uuid.NewV7()
+	if err != nil {
+		return errors.New(err)
+	}
+
+	payout := types.Payout{
+		ID:                           id,
+		ServiceProviderID:            provider.ID,
+		ServiceProviderBankAccountID: req.ServiceProviderBankAccountID,
+		Amount:                       req.Amount,
+		Status:                       types.PayoutStatusPending,
+		CreatedAt:                    time.Now(),
+	}
+
+	tx, err := s.beginMainDBTx(ctx, nil)
+	if err != nil {
+		return errors.New(err)
+	}
+
+	defer tx.Rollback()
+
+	if err = s.payoutRepo.CreateTx(ctx, tx, payout); err != nil {
+		return err
+	}
+
+	err = s.AddTx(ctx, types.ServiceProviderCreditAddReq{
+		Tx:                tx,
+		ServiceProviderID: provider.ID,
+		Amount:            payout.Amount.Neg(),
+		Type:              types.ServiceProviderCreditLedgerTypePayout,
+		PayoutID:          uuid.NullUUID{UUID: payout.ID, Valid: true},
+	})
+	if err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return errors.New(err)
+	}
+
+	return nil
+}
+
+func (s *serviceProviderCreditImpl) AdminGetAllPayouts(ctx context.Context, req types.PayoutAdminGetAllReq) ([]types.PayoutGetAllRes, types.PaginationRes, error) {
+	res := []types.PayoutGetAllRes{}
+	paginationRes := types.PaginationRes{}
+
+	if err := req.Validate(); err != nil {
+		return res, paginationRes, err
+	}
+
+	limit, offset, err := s.parsePagination(&req.PaginationReq)
+	if err != nil {
+		return res, paginationRes, err
+	}
+
+	payouts, totalItem, err := s.payoutRepo.FindAllByFilter(ctx, types.PayoutFilter{
+		Status: req.Status,
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		return res, paginationRes, err
+	}
+
+	for _, payout := range payouts {
+		res = append(res, s.toPayoutGetAllRes(payout))
+	}
+
+	paginationRes = req.GeneratePaginationResponse(totalItem)
+
+	return res, paginationRes, nil
+}
+
+func (s *serviceProviderCreditImpl) AdminApprovePayout(ctx context.Context, req types.PayoutAdminActionReq) error {
+	if err := req.Validate(); err != nil {
+		return err
+	}
+
+	return s.updatePayoutStatus(ctx, req, func(tx dbUtil.Tx, payout *types.Payout) error {
+		if payout.Status != types.PayoutStatusPending {
+			return errors.New(types.AppErr{Code: http.StatusConflict, Message: "payout is not pending"})
+		}
+
+		payout.Status = types.PayoutStatusApproved
+
+		return nil
+	})
+}
+
+func (s *serviceProviderCreditImpl) AdminRejectPayout(ctx context.Context, req types.PayoutAdminActionReq) error {
+	if err := req.Validate(); err != nil {
+		return err
+	}
+
+	if req.Note == "" {
+		return errors.New(types.AppErr{Code: http.StatusBadRequest, Message: "note is required when rejecting a payout"})
+	}
+
+	return s.updatePayoutStatus(ctx, req, func(tx dbUtil.Tx, payout *types.Payout) error {
+		if payout.Status != types.PayoutStatusPending && payout.Status != types.PayoutStatusApproved {
+			return errors.New(types.AppErr{Code: http.StatusConflict, Message: "payout can no longer be rejected"})
+		}
+
+		payout.Status = types.PayoutStatusRejected
+
+		return s.AddTx(ctx, types.ServiceProviderCreditAddReq{
+			Tx:                tx,
+			ServiceProviderID: payout.ServiceProviderID,
+			Amount:            payout.Amount,
+			Type:              types.ServiceProviderCreditLedgerTypePayoutRejected,
+			PayoutID:          uuid.NullUUID{UUID: payout.ID, Valid: true},
+			Note:              null.StringFrom(req.Note),
+		})
+	})
+}
+
+func (s *serviceProviderCreditImpl) AdminMarkPayoutAsPaid(ctx context.Context, req types.PayoutAdminActionReq) error {
+	if err := req.Validate(); err != nil {
+		return err
+	}
+
+	return s.updatePayoutStatus(ctx, req, func(tx dbUtil.Tx, payout *types.Payout) error {
+		if payout.Status != types.PayoutStatusApproved {
+			return errors.New(types.AppErr{Code: http.StatusConflict, Message: "payout is not approved"})
+		}
+
+		payout.Status = types.PayoutStatusPaid
+		payout.PaidAt = null.TimeFrom(time.Now())
+
+		return nil
+	})
+}
+
+// updatePayoutStatus locks the payout, applies the transition through fn and persists it in one transaction
+func (s *serviceProviderCreditImpl) updatePayoutStatus(ctx context.Context, req types.PayoutAdminActionReq, fn func(tx dbUtil.Tx, payout *types.Payout) error) error {
+	tx, err := s.beginMainDBTx(ctx, nil)
+	if err != nil {
+		return errors.New(err)
+	}
+
+	defer tx.Rollback()
+
+	payout, err := s.payoutRepo.FindForUpdateByID(ctx, tx, req.ID)
+	if errors.Is(err, types.ErrNoData) {
+		return errors.New(types.AppErr{Code: http.StatusNotFound, Message: "payout not found"})
+	} else if err != nil {
+		return err
+	}
+
+	if err = fn(tx, &payout); err != nil {
+		return err
+	}
+
+	payout.AdminUserID = uuid.NullUUID{UUID: req.AuthUser.ID, Valid: true}
+	payout.UpdatedAt = null.TimeFrom(time.Now())
+	if req.Note != "" {
+		payout.Note = null.StringFrom(req.Note)
+	}
+
+	if err = s.payoutRepo.UpdateStatusTx(ctx, tx, payout); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return errors.New(err)
+	}
+
+	return nil
+}
+
+func (s *serviceProviderCreditImpl) findProvider(ctx context.Context, userID uuid.UUID) (types.ServiceProvider, error) {
+	provider, err := s.serviceProviderRepo.FindByUserID(ctx, userID)
+	if errors.Is(err, types.ErrNoData) {
+		return provider, errors.Errorf("service provider not found: user_id %s", userID)
+	} else if err != nil {
+		return provider, err
+	}
+
+	return provider, nil
+}
+
+func (s *serviceProviderCreditImpl) parsePagination(req *types.PaginationReq) (int, int, error) {
+	if err := req.ValidateAndNormalize(); err != nil {
+		return 0, 0, err
+	}
+
+	page, err := strconv.Atoi(req.Page)
+	if err != nil {
+		return 0, 0, errors.New(err)
+	}
+
+	size, err := strconv.Atoi(req.Size)
+	if err != nil {
+		return 0, 0, errors.New(err)
+	}
+
+	return size, (page - 1) * size, nil
+}
+
+func (s *serviceProviderCreditImpl) toPayoutGetAllRes(payout types.PayoutWithBankAccount) types.PayoutGetAllRes {
+	return types.PayoutGetAllRes{
+		ID:                  payout.ID,
+		ServiceProviderID:   payout.ServiceProviderID,
+		ServiceProviderName: payout.ServiceProviderName,
+		Amount:              payout.Amount,
+		Status:              payout.Status,
+		BankAccount: types.PayoutGetAllResBankAccount{
+			ID:                payout.ServiceProviderBankAccountID,
+			BankName:          payout.BankName,
+			AccountNumber:     payout.AccountNumber,
+			AccountHolderName: payout.AccountHolderName,
+		},
+		Note:      payout.Note,
+		CreatedAt: payout.CreatedAt,
+		UpdatedAt: payout.UpdatedAt,
+		PaidAt:    payout.PaidAt,
+	}
+}
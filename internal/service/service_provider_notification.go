@@ -5,50 +5,74 @@ import (
 	"fmt"
 	"kelarin/internal/repository"
 	"kelarin/internal/types"
+	"net/http"
 
 	"github.com/go-errors/errors"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
 )
 
 type ServiceProviderNotification interface {
-	GetAll(ctx context.Context, req types.ServiceProviderNotificationGetAllReq) ([]types.ServiceProviderNotificationGetAllRes, error)
+	GetAll(ctx context.Context, req types.ServiceProviderNotificationGetAllReq) ([]types.ServiceProviderNotificationGetAllRes, types.CursorPaginationRes, error)
+	MarkAsRead(ctx context.Context, req types.NotificationMarkAsReadReq) error
+	MarkAllAsRead(ctx context.Context, req types.NotificationMarkAllAsReadReq) error
+	GetUnreadCount(ctx context.Context, req types.NotificationUnreadCountReq) (types.NotificationUnreadCountRes, error)
 }
 
 type serviceProviderNotificationImpl struct {
 	serviceProviderRepo             repository.ServiceProvider
 	serviceProviderNotificationRepo repository.ServiceProviderNotification
 	utilSvc                         Util
+	notificationSvc                 Notification
 }
 
-func NewServiceProviderNotification(serviceProviderRepo repository.ServiceProvider, serviceProviderNotificationRepo repository.ServiceProviderNotification, utilSvc Util) ServiceProviderNotification {
+func NewServiceProviderNotification(serviceProviderRepo repository.ServiceProvider, serviceProviderNotificationRepo repository.ServiceProviderNotification, utilSvc Util, notificationSvc Notification) ServiceProviderNotification {
 	return &serviceProviderNotificationImpl{
 		serviceProviderRepo,
 		serviceProviderNotificationRepo,
 		utilSvc,
+		notificationSvc,
 	}
 }
 
-func (s *serviceProviderNotificationImpl) GetAll(ctx context.Context, req types.ServiceProviderNotificationGetAllReq) ([]types.ServiceProviderNotificationGetAllRes, error) {
+func (s *serviceProviderNotificationImpl) GetAll(ctx context.Context, req types.ServiceProviderNotificationGetAllReq) ([]types.ServiceProviderNotificationGetAllRes, types.CursorPaginationRes, error) {
 	res := []types.ServiceProviderNotificationGetAllRes{}
+	paginationRes := types.CursorPaginationRes{}
 
-	if err := req.Validate(); err != nil {
-		return res, err
+	if err := req.ValidateAndNormalize(); err != nil {
+		return res, paginationRes, err
 	}
 
-	provider, err := s.serviceProviderRepo.FindByUserID(ctx, req.AuthUser.ID)
-	if errors.Is(err, types.ErrNoData) {
-		return res, errors.New(fmt.Sprintf("service provider not found: user_id %s", req.AuthUser.ID))
-	} else if err != nil {
-		return res, err
+	after, err := req.GetAfterID()
+	if err != nil {
+		return res, paginationRes, err
 	}
 
-	notifications, err := s.serviceProviderNotificationRepo.FindAllByServiceProviderID(ctx, provider.ID)
+	provider, err := s.findProvider(ctx, req.AuthUser.ID)
 	if err != nil {
-		return res, err
+		return res, paginationRes, err
+	}
+
+	// one more notification is fetched to know whether there is a next page
+	size := req.GetSize()
+	notifications, err := s.serviceProviderNotificationRepo.FindAllByServiceProviderID(ctx, provider.ID, types.NotificationCursorFilter{
+		After: after,
+		Limit: size + 1,
+	})
+	if err != nil {
+		return res, paginationRes, err
+	}
+
+	paginationRes.Size = int32(size)
+	if len(notifications) > size {
+		notifications = notifications[:size]
+		paginationRes.HasMore = true
+		paginationRes.After = notifications[size-1].ID.String()
 	}
 
 	reqTz, err := s.utilSvc.ParseUserTimeZone(req.TimeZone)
 	if err != nil {
-		return res, err
+		return res, paginationRes, err
 	}
 
 	for _, n := range notifications {
@@ -64,9 +88,84 @@ func (s *serviceProviderNotificationImpl) GetAll(ctx context.Context, req types.
 		})
 	}
 
+	return res, paginationRes, nil
+}
+
+func (s *serviceProviderNotificationImpl) MarkAsRead(ctx context.Context, req types.NotificationMarkAsReadReq) error {
+	if err := req.Validate(); err != nil {
+		return err
+	}
+
+	provider, err := s.findProvider(ctx, req.AuthUser.ID)
+	if err != nil {
+		return err
+	}
+
+	affected, err := s.serviceProviderNotificationRepo.UpdateAsReadByIDs(ctx, provider.ID, req.IDs)
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return errors.New(types.AppErr{Code: http.StatusNotFound, Message: "notification not found"})
+	}
+
+	if err := s.notificationSvc.PublishUnreadCount(ctx, req.AuthUser.ID); err != nil {
+		log.Error().Stack().Err(err).Send()
+	}
+
+	return nil
+}
+
+func (s *serviceProviderNotificationImpl) MarkAllAsRead(ctx context.Context, req types.NotificationMarkAllAsReadReq) error {
+	if err := req.Validate(); err != nil {
+		return err
+	}
+
+	provider, err := s.findProvider(ctx, req.AuthUser.ID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.serviceProviderNotificationRepo.UpdateAllAsReadByServiceProviderID(ctx, provider.ID); err != nil {
+		return err
+	}
+
+	if err := s.notificationSvc.PublishUnreadCount(ctx, req.AuthUser.ID); err != nil {
+		log.Error().Stack().Err(err).Send()
+	}
+
+	return nil
+}
+
+func (s *serviceProviderNotificationImpl) GetUnreadCount(ctx context.Context, req types.NotificationUnreadCountReq) (types.NotificationUnreadCountRes, error) {
+	res := types.NotificationUnreadCountRes{}
+
+	if err := req.Validate(); err != nil {
+		return res, err
+	}
+
+	count, err := s.serviceProviderNotificationRepo.CountUnreadByUserID(ctx, req.AuthUser.ID)
+	if err != nil {
+		return res, err
+	}
+
+	res.Count = count
+
 	return res, nil
 }
 
+func (s *serviceProviderNotificationImpl) findProvider(ctx context.Context, userID uuid.UUID) (types.ServiceProvider, error) {
+	provider, err := s.serviceProviderRepo.FindByUserID(ctx, userID)
+	if errors.Is(err, types.ErrNoData) {
+		return provider, errors.New(fmt.Sprintf("service provider not found: user_id %s", userID))
+	} else if err != nil {
+		return provider, err
+	}
+
+	return provider, nil
+}
+
 func (s *serviceProviderNotificationImpl) GenerateDetails(notification types.ServiceProviderNotificationWithUser) types.ServiceProviderNotificationGeneratedDetails {
 	details := types.ServiceProviderNotificationGeneratedDetails{}
 
@@ -85,10 +184,19 @@ func (s *serviceProviderNotificationImpl) GenerateDetails(notification types.Ser
 		}
 	case
 		types.ServiceProviderNotificationTypeConsumerSettledPayment,
-		types.ServiceProviderNotificationTypeOrderFinished:
+		types.ServiceProviderNotificationTypeOrderFinished,
+		types.ServiceProviderNotificationTypeOrderCanceled,
+		types.ServiceProviderNotificationTypeOrderSessionFinished:
 		details.Metadata = types.ServiceProviderNotificationMetadataOrder{
 			OrderID: notification.OrderID.UUID,
 		}
+	case
+		types.ServiceProviderNotificationTypeDisputeOpened,
+		types.ServiceProviderNotificationTypeDisputeResolved:
+		details.Metadata = types.ServiceProviderNotificationMetadataDispute{
+			OrderID:   notification.OrderID.UUID,
+			DisputeID: notification.DisputeID.UUID,
+		}
 	}
 
 	switch notification.Type {
@@ -107,6 +215,18 @@ func (s *serviceProviderNotificationImpl) GenerateDetails(notification types.Ser
 	case types.ServiceProviderNotificationTypeOrderFinished:
 		details.Title = fmt.Sprintf("%s's order finished", notification.UserName.String)
 		details.Message = "Order finished, the service fee automatically added to your credit"
+	case types.ServiceProviderNotificationTypeOrderCanceled:
+		details.Title = fmt.Sprintf("%s's order canceled", notification.UserName.String)
+		details.Message = "Order canceled. Check your schedule and credit for the details"
+	case types.ServiceProviderNotificationTypeOrderSessionFinished:
+		details.Title = fmt.Sprintf("%s's order session finished", notification.UserName.String)
+		details.Message = "Session finished, the service fee will be added to your credit once every session is finished"
+	case types.ServiceProviderNotificationTypeDisputeOpened:
+		details.Title = fmt.Sprintf("%s opened a dispute", notification.UserName.String)
+		details.Message = "A consumer is not satisfied with your service. Respond to the dispute now"
+	case types.ServiceProviderNotificationTypeDisputeResolved:
+		details.Title = fmt.Sprintf("%s's dispute resolved", notification.UserName.String)
+		details.Message = "Check the outcome of the dispute and your credit"
 	case types.ServiceProviderNotificationTypeConsumerSettledPayment:
 		details.Title = fmt.Sprintf("%s finished their payment for your service fee", notification.UserName.String)
 		details.Message = "The service fee is currently on hold!"
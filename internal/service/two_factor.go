@@ -0,0 +1,339 @@
+package service
+
+import (
+	"context"
+	"kelarin/internal/config"
+	"kelarin/internal/repository"
+	"kelarin/internal/types"
+	dbUtil "kelarin/internal/utils/dbutil"
+	totpUtil "kelarin/internal/utils/totp_util"
+	"net/http"
+	"time"
+
+	"github.com/go-errors/errors"
+	"github.com/google/uuid"
+)
+
+type TwoFactor interface {
+	Setup(ctx context.Context, req types.TwoFactorSetupReq) (types.TwoFactorSetupRes, error)
+	SetupForUser(ctx context.Context, userID uuid.UUID) (types.TwoFactorSetupRes, error)
+	Enable(ctx context.Context, req types.TwoFactorCodeReq) (types.TwoFactorRecoveryCodesRes, error)
+	Disable(ctx context.Context, req types.TwoFactorCodeReq) error
+	RegenerateRecoveryCodes(ctx context.Context, req types.TwoFactorCodeReq) (types.TwoFactorRecoveryCodesRes, error)
+	IsEnabled(ctx context.Context, userID uuid.UUID) (bool, error)
+	Verify(ctx context.Context, userID uuid.UUID, code string) ([]string, error)
+}
+
+type twoFactorImpl struct {
+	config        *config.Config
+	beginMainDBTx dbUtil.SqlxTx
+	userRepo      repository.User
+	userTOTPRepo  repository.UserTOTP
+}
+
+func NewTwoFactor(cfg *config.Config, beginMainDBTx dbUtil.SqlxTx, userRepo repository.User, userTOTPRepo repository.UserTOTP) TwoFactor {
+	return &twoFactorImpl{
+		config:        cfg,
+		beginMainDBTx: beginMainDBTx,
+		userRepo:      userRepo,
+		userTOTPRepo:  userTOTPRepo,
+	}
+}
+
+func (s *twoFactorImpl) Setup(ctx context.Context, req types.TwoFactorSetupReq) (types.TwoFactorSetupRes, error) {
+	if err := req.Validate(); err != nil {
+		return types.TwoFactorSetupRes{}, err
+	}
+
+	return s.SetupForUser(ctx, req.AuthUser.ID)
+}
+
+// SetupForUser starts an enrollment, the secret is not used for login until Enable or Verify confirms it with a code.
+// Calling it again replaces the secret of an enrollment that has not been confirmed
+func (s *twoFactorImpl) SetupForUser(ctx context.Context, userID uuid.UUID) (types.TwoFactorSetupRes, error) {
+	res := types.TwoFactorSetupRes{}
+
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return res, err
+	}
+
+	if types.GetAuthTwoFactorPolicy(user.Role) == types.AuthTwoFactorPolicyDisabled {
+		return res, types.TwoFactorErrNotAvailable
+	}
+
+	secret, err := totpUtil.GenerateSecret()
+	if err != nil {
+		return res, err
+	}
+
+	encryptedSecret, err := totpUtil.Encrypt(s.config.Auth.TOTPEncryptionKeyBytes, secret)
+	if err != nil {
+		return res, err
+	}
+
+	ok, err := s.userTOTPRepo.Upsert(ctx, types.UserTOTP{
+		UserID:    user.ID,
+		Secret:    encryptedSecret,
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		return res, err
+	} else if !ok {
+		return res, types.TwoFactorErrAlreadyEnabled
+	}
+
+	res.Secret = secret
+	res.URI = totpUtil.URI(types.TOTPIssuer, user.Email, secret)
+
+	return res, nil
+}
+
+func (s *twoFactorImpl) Enable(ctx context.Context, req types.TwoFactorCodeReq) (types.TwoFactorRecoveryCodesRes, error) {
+	res := types.TwoFactorRecoveryCodesRes{}
+
+	if err := req.Validate(); err != nil {
+		return res, err
+	}
+
+	totp, err := s.userTOTPRepo.FindByUserID(ctx, req.AuthUser.ID)
+	if errors.Is(err, types.ErrNoData) {
+		return res, errors.New(types.AppErr{Code: http.StatusBadRequest, Message: "two-factor authentication has not been set up"})
+	} else if err != nil {
+		return res, err
+	}
+
+	if totp.EnabledAt.Valid {
+		return res, types.TwoFactorErrAlreadyEnabled
+	}
+
+	res.RecoveryCodes, err = s.enable(ctx, totp, req.Code)
+	if err != nil {
+		return res, err
+	}
+
+	return res, nil
+}
+
+// Disable is not allowed for a role that requires two-factor authentication
+func (s *twoFactorImpl) Disable(ctx context.Context, req types.TwoFactorCodeReq) error {
+	if err := req.Validate(); err != nil {
+		return err
+	}
+
+	if types.GetAuthTwoFactorPolicy(req.AuthUser.Role) == types.AuthTwoFactorPolicyRequired {
+		return types.TwoFactorErrRequired
+	}
+
+	totp, err := s.findEnabled(ctx, req.AuthUser.ID)
+	if err != nil {
+		return err
+	}
+
+	if err = s.verifyCode(ctx, totp, req.Code); err != nil {
+		return err
+	}
+
+	tx, err := s.beginMainDBTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err = s.userTOTPRepo.DeleteRecoveryCodesTx(ctx, tx, req.AuthUser.ID); err != nil {
+		return err
+	}
+
+	if err = s.userTOTPRepo.DeleteTx(ctx, tx, req.AuthUser.ID); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return errors.New(err)
+	}
+
+	return nil
+}
+
+// RegenerateRecoveryCodes replaces every recovery code, the codes that have not been used are no longer valid
+func (s *twoFactorImpl) RegenerateRecoveryCodes(ctx context.Context, req types.TwoFactorCodeReq) (types.TwoFactorRecoveryCodesRes, error) {
+	res := types.TwoFactorRecoveryCodesRes{}
+
+	if err := req.Validate(); err != nil {
+		return res, err
+	}
+
+	totp, err := s.findEnabled(ctx, req.AuthUser.ID)
+	if err != nil {
+		return res, err
+	}
+
+	if err = s.verifyCode(ctx, totp, req.Code); err != nil {
+		return res, err
+	}
+
+	tx, err := s.beginMainDBTx(ctx, nil)
+	if err != nil {
+		return res, err
+	}
+	defer tx.Rollback()
+
+	if err = s.userTOTPRepo.DeleteRecoveryCodesTx(ctx, tx, req.AuthUser.ID); err != nil {
+		return res, err
+	}
+
+	res.RecoveryCodes, err = s.createRecoveryCodesTx(ctx, tx, req.AuthUser.ID)
+	if err != nil {
+		return res, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return res, errors.New(err)
+	}
+
+	return res, nil
+}
+
+func (s *twoFactorImpl) IsEnabled(ctx context.Context, userID uuid.UUID) (bool, error) {
+	totp, err := s.userTOTPRepo.FindByUserID(ctx, userID)
+	if errors.Is(err, types.ErrNoData) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	return totp.EnabledAt.Valid, nil
+}
+
+// Verify is the second step of the login, it accepts a code from the authenticator app or a recovery code.
+// A login that requires enrollment confirms the pending secret instead, the recovery codes are returned in that case.
+// Nothing is stored when an error is returned
+func (s *twoFactorImpl) Verify(ctx context.Context, userID uuid.UUID, code string) ([]string, error) {
+	totp, err := s.userTOTPRepo.FindByUserID(ctx, userID)
+	if errors.Is(err, types.ErrNoData) {
+		return nil, types.TwoFactorErrNotEnabled
+	} else if err != nil {
+		return nil, err
+	}
+
+	if !totp.EnabledAt.Valid {
+		return s.enable(ctx, totp, code)
+	}
+
+	return nil, s.verifyCode(ctx, totp, code)
+}
+
+func (s *twoFactorImpl) findEnabled(ctx context.Context, userID uuid.UUID) (types.UserTOTP, error) {
+	totp, err := s.userTOTPRepo.FindByUserID(ctx, userID)
+	if errors.Is(err, types.ErrNoData) {
+		return totp, types.TwoFactorErrNotEnabled
+	} else if err != nil {
+		return totp, err
+	}
+
+	if !totp.EnabledAt.Valid {
+		return totp, types.TwoFactorErrNotEnabled
+	}
+
+	return totp, nil
+}
+
+// enable confirms the pending secret, only a code from the authenticator app is accepted because there is no recovery code yet
+func (s *twoFactorImpl) enable(ctx context.Context, totp types.UserTOTP, code string) ([]string, error) {
+	secret, err := totpUtil.Decrypt(s.config.Auth.TOTPEncryptionKeyBytes, totp.Secret)
+	if err != nil {
+		return nil, err
+	}
+
+	timeNow := time.Now()
+	step, ok, err := totpUtil.Validate(code, secret, timeNow)
+	if err != nil {
+		return nil, err
+	} else if !ok {
+		return nil, types.TwoFactorErrInvalidCode
+	}
+
+	tx, err := s.beginMainDBTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	ok, err = s.userTOTPRepo.EnableTx(ctx, tx, totp.UserID, timeNow, step)
+	if err != nil {
+		return nil, err
+	} else if !ok {
+		return nil, types.TwoFactorErrAlreadyEnabled
+	}
+
+	recoveryCodes, err := s.createRecoveryCodesTx(ctx, tx, totp.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, errors.New(err)
+	}
+
+	return recoveryCodes, nil
+}
+
+// verifyCode rejects a code that has been accepted before, a code from the authenticator app is valid for the whole time step
+func (s *twoFactorImpl) verifyCode(ctx context.Context, totp types.UserTOTP, code string) error {
+	secret, err := totpUtil.Decrypt(s.config.Auth.TOTPEncryptionKeyBytes, totp.Secret)
+	if err != nil {
+		return err
+	}
+
+	timeNow := time.Now()
+	step, ok, err := totpUtil.Validate(code, secret, timeNow)
+	if err != nil {
+		return err
+	}
+
+	if ok {
+		ok, err = s.userTOTPRepo.UpdateLastUsedStep(ctx, totp.UserID, step)
+	} else {
+		ok, err = s.userTOTPRepo.UseRecoveryCode(ctx, totp.UserID, totpUtil.HashRecoveryCode(code), timeNow)
+	}
+
+	if err != nil {
+		return err
+	} else if !ok {
+		return types.TwoFactorErrInvalidCode
+	}
+
+	return nil
+}
+
+func (s *twoFactorImpl) createRecoveryCodesTx(ctx context.Context, tx dbUtil.Tx, userID uuid.UUID) ([]string, error) {
+	timeNow := time.Now()
+	recoveryCodes := make([]string, 0, types.TOTPRecoveryCodeCount)
+	rows := make([]types.UserTOTPRecoveryCode, 0, types.TOTPRecoveryCodeCount)
+
+	for range types.TOTPRecoveryCodeCount {
+		code, err := totpUtil.GenerateRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+
+		id, err := uuid.NewV7()
+		if err != nil {
+			return nil, errors.New(err)
+		}
+
+		recoveryCodes = append(recoveryCodes, code)
+		rows = append(rows, types.UserTOTPRecoveryCode{
+			ID:        id,
+			UserID:    userID,
+			CodeHash:  totpUtil.HashRecoveryCode(code),
+			CreatedAt: timeNow,
+		})
+	}
+
+	if err := s.userTOTPRepo.CreateRecoveryCodesTx(ctx, tx, rows); err != nil {
+		return nil, err
+	}
+
+	return recoveryCodes, nil
+}
@@ -4,25 +4,430 @@ import (
 	"context"
 	"kelarin/internal/repository"
 	"kelarin/internal/types"
+	dbUtil "kelarin/internal/utils/dbutil"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/go-errors/errors"
+	"github.com/google/uuid"
+	"github.com/volatiletech/null/v9"
 )
 
 type User interface {
 	FindOne(c context.Context) error
+
+	AdminGetAll(ctx context.Context, req types.UserAdminGetAllReq) ([]types.UserAdminGetAllRes, types.PaginationRes, error)
+	AdminGetByID(ctx context.Context, req types.UserAdminGetByIDReq) (types.UserAdminGetByIDRes, error)
+	AdminSuspend(ctx context.Context, req types.UserAdminSuspendReq) error
+	AdminUnsuspend(ctx context.Context, req types.UserAdminModerateReq) error
+	AdminBan(ctx context.Context, req types.UserAdminModerateReq) error
+	AdminUnban(ctx context.Context, req types.UserAdminModerateReq) error
+	AdminRevokeSessions(ctx context.Context, req types.UserAdminModerateReq) error
+
+	TaskLiftExpiredSuspensions(ctx context.Context) error
 }
 
 type userImpl struct {
-	userRepo repository.User
+	beginMainDBTx         dbUtil.SqlxTx
+	userRepo              repository.User
+	userModerationLogRepo repository.UserModerationLog
+	sessionRepo           repository.Session
+	userBlocklistRepo     repository.UserBlocklist
+	notificationSvc       Notification
 }
 
-func NewUser(userRepo repository.User) User {
+func NewUser(
+	beginMainDBTx dbUtil.SqlxTx,
+	userRepo repository.User,
+	userModerationLogRepo repository.UserModerationLog,
+	sessionRepo repository.Session,
+	userBlocklistRepo repository.UserBlocklist,
+	notificationSvc Notification,
+) User {
 	return &userImpl{
-		userRepo: userRepo,
+		beginMainDBTx:         beginMainDBTx,
+		userRepo:              userRepo,
+		userModerationLogRepo: userModerationLogRepo,
+		sessionRepo:           sessionRepo,
+		userBlocklistRepo:     userBlocklistRepo,
+		notificationSvc:       notificationSvc,
 	}
 }
 
 func (u *userImpl) FindOne(c context.Context) error {
 	return errors.New(types.AppErr{Code: http.StatusNotFound})
 }
+
+func (u *userImpl) AdminGetAll(ctx context.Context, req types.UserAdminGetAllReq) ([]types.UserAdminGetAllRes, types.PaginationRes, error) {
+	res := []types.UserAdminGetAllRes{}
+	paginationRes := types.PaginationRes{}
+
+	if err := req.Validate(); err != nil {
+		return res, paginationRes, err
+	}
+
+	if err := req.ValidateAndNormalize(); err != nil {
+		return res, paginationRes, err
+	}
+
+	page, err := strconv.Atoi(req.Page)
+	if err != nil {
+		return res, paginationRes, errors.New(err)
+	}
+
+	size, err := strconv.Atoi(req.Size)
+	if err != nil {
+		return res, paginationRes, errors.New(err)
+	}
+
+	users, totalItem, err := u.userRepo.FindAllByFilter(ctx, types.UserAdminFilter{
+		Keyword: req.Keyword,
+		Role:    req.Role,
+		Status:  req.Status,
+		Limit:   size,
+		Offset:  (page - 1) * size,
+	})
+	if err != nil {
+		return res, paginationRes, err
+	}
+
+	for _, user := range users {
+		res = append(res, u.toAdminGetAllRes(user))
+	}
+
+	paginationRes = req.GeneratePaginationResponse(totalItem)
+
+	return res, paginationRes, nil
+}
+
+func (u *userImpl) AdminGetByID(ctx context.Context, req types.UserAdminGetByIDReq) (types.UserAdminGetByIDRes, error) {
+	res := types.UserAdminGetByIDRes{}
+
+	if err := req.Validate(); err != nil {
+		return res, err
+	}
+
+	user, err := u.userRepo.FindByID(ctx, req.ID)
+	if errors.Is(err, types.ErrNoData) {
+		return res, errors.New(types.AppErr{Code: http.StatusNotFound, Message: "user not found"})
+	} else if err != nil {
+		return res, err
+	}
+
+	logs, err := u.userModerationLogRepo.FindByUserIDWithAdmin(ctx, user.ID)
+	if err != nil {
+		return res, err
+	}
+
+	res = types.UserAdminGetByIDRes{
+		UserAdminGetAllRes: u.toAdminGetAllRes(user),
+		ModerationLogs:     []types.UserAdminGetByIDResModerationLog{},
+	}
+
+	for _, log := range logs {
+		moderationLog := types.UserAdminGetByIDResModerationLog{
+			ID:            log.ID,
+			Action:        log.Action,
+			Reason:        log.Reason,
+			SuspendedFrom: log.SuspendedFrom,
+			SuspendedTo:   log.SuspendedTo,
+			CreatedAt:     log.CreatedAt,
+		}
+
+		if log.AdminUserID.Valid {
+			moderationLog.Admin = &types.UserAdminGetByIDResModerationLogAdmin{
+				ID:   log.AdminUserID.UUID,
+				Name: log.AdminName.String,
+			}
+		}
+
+		res.ModerationLogs = append(res.ModerationLogs, moderationLog)
+	}
+
+	return res, nil
+}
+
+func (u *userImpl) AdminSuspend(ctx context.Context, req types.UserAdminSuspendReq) error {
+	if err := req.Validate(); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	suspendedTo := now.AddDate(0, 0, req.DurationInDays)
+
+	return u.moderate(ctx, req.AuthUser, req.ID, func(user *types.User) (types.UserModerationLog, error) {
+		if user.IsBanned {
+			return types.UserModerationLog{}, errors.New(types.AppErr{Code: http.StatusConflict, Message: "user is banned"})
+		} else if user.IsSuspensionActive(now) {
+			return types.UserModerationLog{}, errors.New(types.AppErr{Code: http.StatusConflict, Message: "user is already suspended"})
+		}
+
+		// suspended_count is incremented by increment_suspended_count_trigger
+		user.IsSuspended = true
+		user.SuspendedFrom = null.TimeFrom(now)
+		user.SuspendedTo = null.TimeFrom(suspendedTo)
+
+		return types.UserModerationLog{
+			Action:        types.UserModerationActionSuspend,
+			Reason:        req.Reason,
+			SuspendedFrom: user.SuspendedFrom,
+			SuspendedTo:   user.SuspendedTo,
+		}, nil
+	})
+}
+
+func (u *userImpl) AdminUnsuspend(ctx context.Context, req types.UserAdminModerateReq) error {
+	if err := req.Validate(); err != nil {
+		return err
+	}
+
+	return u.moderate(ctx, req.AuthUser, req.ID, func(user *types.User) (types.UserModerationLog, error) {
+		if !user.IsSuspended {
+			return types.UserModerationLog{}, errors.New(types.AppErr{Code: http.StatusConflict, Message: "user is not suspended"})
+		}
+
+		log := types.UserModerationLog{
+			Action:        types.UserModerationActionUnsuspend,
+			Reason:        req.Reason,
+			SuspendedFrom: user.SuspendedFrom,
+			SuspendedTo:   user.SuspendedTo,
+		}
+
+		user.IsSuspended = false
+		user.SuspendedFrom = null.Time{}
+		user.SuspendedTo = null.Time{}
+
+		return log, nil
+	})
+}
+
+func (u *userImpl) AdminBan(ctx context.Context, req types.UserAdminModerateReq) error {
+	if err := req.Validate(); err != nil {
+		return err
+	}
+
+	return u.moderate(ctx, req.AuthUser, req.ID, func(user *types.User) (types.UserModerationLog, error) {
+		if user.IsBanned {
+			return types.UserModerationLog{}, errors.New(types.AppErr{Code: http.StatusConflict, Message: "user is already banned"})
+		}
+
+		user.IsBanned = true
+		user.BannedAt = null.TimeFrom(time.Now())
+
+		return types.UserModerationLog{
+			Action: types.UserModerationActionBan,
+			Reason: req.Reason,
+		}, nil
+	})
+}
+
+func (u *userImpl) AdminUnban(ctx context.Context, req types.UserAdminModerateReq) error {
+	if err := req.Validate(); err != nil {
+		return err
+	}
+
+	return u.moderate(ctx, req.AuthUser, req.ID, func(user *types.User) (types.UserModerationLog, error) {
+		if !user.IsBanned {
+			return types.UserModerationLog{}, errors.New(types.AppErr{Code: http.StatusConflict, Message: "user is not banned"})
+		}
+
+		user.IsBanned = false
+		user.BannedAt = null.Time{}
+
+		return types.UserModerationLog{
+			Action: types.UserModerationActionUnban,
+			Reason: req.Reason,
+		}, nil
+	})
+}
+
+// AdminRevokeSessions logs the user out of every device without blocking the account, used when the account is compromised
+func (u *userImpl) AdminRevokeSessions(ctx context.Context, req types.UserAdminModerateReq) error {
+	if err := req.Validate(); err != nil {
+		return err
+	}
+
+	return u.moderate(ctx, req.AuthUser, req.ID, func(user *types.User) (types.UserModerationLog, error) {
+		return types.UserModerationLog{
+			Action: types.UserModerationActionRevokeSessions,
+			Reason: req.Reason,
+		}, nil
+	})
+}
+
+// moderate locks the target user, applies the action through fn, persists the user and the audit log in one transaction,
+// then revokes every session and push token of the user when the action blocks the account or logs the user out
+func (u *userImpl) moderate(ctx context.Context, admin types.AuthUser, userID uuid.UUID, fn func(user *types.User) (types.UserModerationLog, error)) error {
+	if admin.ID == userID {
+		return errors.New(types.AppErr{Code: http.StatusForbidden, Message: "cannot moderate your own account"})
+	}
+
+	tx, err := u.beginMainDBTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	user, err := u.userRepo.FindForUpdateByID(ctx, tx, userID)
+	if errors.Is(err, types.ErrNoData) {
+		return errors.New(types.AppErr{Code: http.StatusNotFound, Message: "user not found"})
+	} else if err != nil {
+		return err
+	}
+
+	if user.Role == types.UserRoleAdmin {
+		return errors.New(types.AppErr{Code: http.StatusForbidden, Message: "cannot moderate an admin account"})
+	}
+
+	log, err := fn(&user)
+	if err != nil {
+		return err
+	}
+
+	id, err := uuid.NewV7()
+	if err != nil {
+		return errors.New(err)
+	}
+
+	log.ID = id
+	log.UserID = user.ID
+	log.AdminUserID = uuid.NullUUID{UUID: admin.ID, Valid: true}
+	log.CreatedAt = time.Now()
+
+	switch log.Action {
+	case types.UserModerationActionSuspend, types.UserModerationActionUnsuspend:
+		err = u.userRepo.UpdateSuspensionTx(ctx, tx, user)
+	case types.UserModerationActionBan, types.UserModerationActionUnban:
+		err = u.userRepo.UpdateBanTx(ctx, tx, user)
+	}
+	if err != nil {
+		return err
+	}
+
+	if err = u.userModerationLogRepo.CreateTx(ctx, tx, log); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return errors.New(err)
+	}
+
+	if err = u.syncBlocklist(ctx, user, log.CreatedAt); err != nil {
+		return err
+	}
+
+	switch log.Action {
+	case types.UserModerationActionSuspend, types.UserModerationActionBan, types.UserModerationActionRevokeSessions:
+		if err = u.sessionRepo.DeleteAllByUserID(ctx, user.ID.String()); err != nil {
+			return err
+		}
+
+		if err = u.notificationSvc.RemoveAllTokens(ctx, user.ID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// syncBlocklist mirrors the moderation state of the user into the blocklist read by the auth middleware,
+// a suspension entry expires together with the suspension period. The tokens issued before moderatedAt are rejected
+func (u *userImpl) syncBlocklist(ctx context.Context, user types.User, moderatedAt time.Time) error {
+	now := time.Now()
+
+	if user.IsBanned {
+		return u.userBlocklistRepo.Set(ctx, user.ID, types.UserBlocklistEntry{Status: types.UserStatusBanned, BlockedAt: moderatedAt}, 0)
+	} else if user.IsSuspensionActive(now) {
+		expiration := time.Duration(0)
+		if user.SuspendedTo.Valid {
+			expiration = user.SuspendedTo.Time.Sub(now)
+		}
+
+		return u.userBlocklistRepo.Set(ctx, user.ID, types.UserBlocklistEntry{Status: types.UserStatusSuspended, BlockedAt: moderatedAt}, expiration)
+	}
+
+	return u.userBlocklistRepo.Delete(ctx, user.ID)
+}
+
+const liftExpiredSuspensionsBatchSize = 500
+
+func (u *userImpl) TaskLiftExpiredSuspensions(ctx context.Context) error {
+	for {
+		lifted, err := u.liftExpiredSuspensions(ctx)
+		if err != nil {
+			return err
+		}
+
+		if lifted < liftExpiredSuspensionsBatchSize {
+			return nil
+		}
+	}
+}
+
+func (u *userImpl) liftExpiredSuspensions(ctx context.Context) (int, error) {
+	tx, err := u.beginMainDBTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	defer tx.Rollback()
+
+	now := time.Now()
+
+	users, err := u.userRepo.UnsuspendExpiredTx(ctx, tx, now, liftExpiredSuspensionsBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	userIDs := uuid.UUIDs{}
+	for _, user := range users {
+		id, err := uuid.NewV7()
+		if err != nil {
+			return 0, errors.New(err)
+		}
+
+		err = u.userModerationLogRepo.CreateTx(ctx, tx, types.UserModerationLog{
+			ID:            id,
+			UserID:        user.ID,
+			Action:        types.UserModerationActionUnsuspend,
+			Reason:        types.UserModerationReasonSuspensionEnded,
+			SuspendedFrom: user.SuspendedFrom,
+			SuspendedTo:   user.SuspendedTo,
+			CreatedAt:     now,
+		})
+		if err != nil {
+			return 0, err
+		}
+
+		if !user.IsBanned {
+			userIDs = append(userIDs, user.ID)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return 0, errors.New(err)
+	}
+
+	if err = u.userBlocklistRepo.Delete(ctx, userIDs...); err != nil {
+		return 0, err
+	}
+
+	return len(users), nil
+}
+
+func (u *userImpl) toAdminGetAllRes(user types.User) types.UserAdminGetAllRes {
+	return types.UserAdminGetAllRes{
+		ID:             user.ID,
+		Name:           user.Name,
+		Email:          user.Email,
+		Role:           user.Role,
+		Status:         user.Status(),
+		SuspendedCount: user.SuspendedCount,
+		SuspendedFrom:  user.SuspendedFrom,
+		SuspendedTo:    user.SuspendedTo,
+		BannedAt:       user.BannedAt,
+		CreatedAt:      user.CreatedAt,
+	}
+}
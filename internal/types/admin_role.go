@@ -0,0 +1,250 @@
+package types
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-errors/errors"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/volatiletech/null/v9"
+)
+
+const (
+	AdminPermissionCacheKey        = "admin-permissions"
+	AdminPermissionCacheVersionKey = "admin-permissions-version"
+)
+
+// AdminPermissionCacheExpiration bounds how long a session keeps a permission that has been revoked when the invalidation fails
+const AdminPermissionCacheExpiration = 10 * time.Minute
+
+// GetAdminPermissionCacheKey returns the key holding the permissions of the session, the roles are read once per session until they change
+func GetAdminPermissionCacheKey(sessionID string) string {
+	return fmt.Sprintf("%s:%s", AdminPermissionCacheKey, sessionID)
+}
+
+// GetAdminPermissionCacheVersionKey returns the key counting the changes to the roles of the admin, the permissions cached
+// with an older version are ignored
+func GetAdminPermissionCacheVersionKey(userID string) string {
+	return fmt.Sprintf("%s:%s", AdminPermissionCacheVersionKey, userID)
+}
+
+var ErrAdminPermissionDenied = errors.New(AppErr{Code: http.StatusForbidden, Message: "you do not have the permission to perform this action"})
+
+type AdminPermission string
+
+// AdminPermissionAll is only granted to the system role, it includes the permissions added later
+const AdminPermissionAll AdminPermission = "*"
+
+const (
+	AdminPermissionUsersRead              AdminPermission = "users.read"
+	AdminPermissionUsersSuspend           AdminPermission = "users.suspend"
+	AdminPermissionUsersBan               AdminPermission = "users.ban"
+	AdminPermissionUsersRevokeSessions    AdminPermission = "users.revoke_sessions"
+	AdminPermissionDisputesRead           AdminPermission = "disputes.read"
+	AdminPermissionDisputesResolve        AdminPermission = "disputes.resolve"
+	AdminPermissionPayoutsRead            AdminPermission = "payouts.read"
+	AdminPermissionPayoutsApprove         AdminPermission = "payouts.approve"
+	AdminPermissionPayoutsMarkAsPaid      AdminPermission = "payouts.mark_as_paid"
+	AdminPermissionRefundsRead            AdminPermission = "refunds.read"
+	AdminPermissionRefundsManage          AdminPermission = "refunds.manage"
+	AdminPermissionNotificationsBroadcast AdminPermission = "notifications.broadcast"
+	AdminPermissionRolesManage            AdminPermission = "roles.manage"
+)
+
+// AdminPermissions is the list shown to the admin that manages the roles, a role can only grant these permissions
+var AdminPermissions = []AdminPermissionRes{
+	{Name: AdminPermissionUsersRead, Description: "View users and their moderation history"},
+	{Name: AdminPermissionUsersSuspend, Description: "Suspend and unsuspend users"},
+	{Name: AdminPermissionUsersBan, Description: "Ban and unban users"},
+	{Name: AdminPermissionUsersRevokeSessions, Description: "Log users out of every device"},
+	{Name: AdminPermissionDisputesRead, Description: "View disputes"},
+	{Name: AdminPermissionDisputesResolve, Description: "Resolve disputes and decide the refund"},
+	{Name: AdminPermissionPayoutsRead, Description: "View payout requests"},
+	{Name: AdminPermissionPayoutsApprove, Description: "Approve and reject payout requests"},
+	{Name: AdminPermissionPayoutsMarkAsPaid, Description: "Mark approved payouts as paid"},
+	{Name: AdminPermissionRefundsRead, Description: "View refunds"},
+	{Name: AdminPermissionRefundsManage, Description: "Retry refunds and mark them as succeeded"},
+	{Name: AdminPermissionNotificationsBroadcast, Description: "Broadcast notifications to users"},
+	{Name: AdminPermissionRolesManage, Description: "Manage the roles and assign them to admins"},
+}
+
+// HasAdminPermission reports whether the permissions granted to an admin include the permission
+func HasAdminPermission(granted []string, permission AdminPermission) bool {
+	return slices.Contains(granted, string(AdminPermissionAll)) || slices.Contains(granted, string(permission))
+}
+
+// region repo types
+
+// AdminRole is a named set of permissions, a system role is created by the migrations and can not be changed
+type AdminRole struct {
+	ID          uuid.UUID      `db:"id"`
+	Name        string         `db:"name"`
+	Description string         `db:"description"`
+	IsSystem    bool           `db:"is_system"`
+	Permissions pq.StringArray `db:"permissions"`
+	CreatedAt   time.Time      `db:"created_at"`
+	UpdatedAt   null.Time      `db:"updated_at"`
+}
+
+// AdminPermissionCache is the value cached for a session, Version is the version of the admin when the permissions were loaded
+type AdminPermissionCache struct {
+	Version     int64    `json:"version"`
+	Permissions []string `json:"permissions"`
+}
+
+// end of region repo types
+
+// region service types
+
+type AdminPermissionRes struct {
+	Name        AdminPermission `json:"name"`
+	Description string          `json:"description"`
+}
+
+type AdminRoleRes struct {
+	ID          uuid.UUID `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	IsSystem    bool      `json:"is_system"`
+	Permissions []string  `json:"permissions"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   null.Time `json:"updated_at"`
+}
+
+type AdminRoleGetAllReq struct {
+	AuthUser AuthUser `middleware:"user"`
+}
+
+func (r AdminRoleGetAllReq) Validate() error {
+	if r.AuthUser.IsZero() {
+		return errors.New("AuthUser is required")
+	}
+
+	return nil
+}
+
+type AdminRoleCreateReq struct {
+	AuthUser    AuthUser          `middleware:"user"`
+	Name        string            `json:"name"`
+	Description string            `json:"description"`
+	Permissions []AdminPermission `json:"permissions"`
+}
+
+func (r *AdminRoleCreateReq) ValidateAndNormalize() error {
+	if r.AuthUser.IsZero() {
+		return errors.New("AuthUser is required")
+	}
+
+	r.Name = strings.TrimSpace(r.Name)
+	r.Description = strings.TrimSpace(r.Description)
+
+	return validation.ValidateStruct(r,
+		validation.Field(&r.Name, validation.Required, validation.Length(1, 100)),
+		validation.Field(&r.Description, validation.Length(0, 255)),
+		validation.Field(&r.Permissions, validation.Required, validation.Each(validation.In(adminPermissionNames()...))),
+	)
+}
+
+type AdminRoleUpdateReq struct {
+	AuthUser    AuthUser          `middleware:"user"`
+	ID          uuid.UUID         `param:"id"`
+	Name        string            `json:"name"`
+	Description string            `json:"description"`
+	Permissions []AdminPermission `json:"permissions"`
+}
+
+func (r *AdminRoleUpdateReq) ValidateAndNormalize() error {
+	if r.AuthUser.IsZero() {
+		return errors.New("AuthUser is required")
+	}
+
+	if r.ID == uuid.Nil {
+		return ErrIDRouteParamRequired
+	}
+
+	r.Name = strings.TrimSpace(r.Name)
+	r.Description = strings.TrimSpace(r.Description)
+
+	return validation.ValidateStruct(r,
+		validation.Field(&r.Name, validation.Required, validation.Length(1, 100)),
+		validation.Field(&r.Description, validation.Length(0, 255)),
+		validation.Field(&r.Permissions, validation.Required, validation.Each(validation.In(adminPermissionNames()...))),
+	)
+}
+
+type AdminRoleDeleteReq struct {
+	AuthUser AuthUser  `middleware:"user"`
+	ID       uuid.UUID `param:"id"`
+}
+
+func (r AdminRoleDeleteReq) Validate() error {
+	if r.AuthUser.IsZero() {
+		return errors.New("AuthUser is required")
+	}
+
+	if r.ID == uuid.Nil {
+		return ErrIDRouteParamRequired
+	}
+
+	return nil
+}
+
+type AdminRoleGetByUserIDReq struct {
+	AuthUser AuthUser  `middleware:"user"`
+	UserID   uuid.UUID `param:"id"`
+}
+
+func (r AdminRoleGetByUserIDReq) Validate() error {
+	if r.AuthUser.IsZero() {
+		return errors.New("AuthUser is required")
+	}
+
+	if r.UserID == uuid.Nil {
+		return ErrIDRouteParamRequired
+	}
+
+	return nil
+}
+
+// AdminRoleAssignReq replaces every role of the admin, an empty RoleIDs leaves the admin without permissions
+type AdminRoleAssignReq struct {
+	AuthUser AuthUser    `middleware:"user"`
+	UserID   uuid.UUID   `param:"id"`
+	RoleIDs  []uuid.UUID `json:"role_ids"`
+}
+
+func (r AdminRoleAssignReq) Validate() error {
+	if r.AuthUser.IsZero() {
+		return errors.New("AuthUser is required")
+	}
+
+	if r.UserID == uuid.Nil {
+		return ErrIDRouteParamRequired
+	}
+
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.RoleIDs, validation.NotNil, validation.Each(validation.By(func(value interface{}) error {
+			if value.(uuid.UUID) == uuid.Nil {
+				return errors.New("must be a valid uuid")
+			}
+
+			return nil
+		}))),
+	)
+}
+
+func adminPermissionNames() []interface{} {
+	res := make([]interface{}, 0, len(AdminPermissions))
+	for _, permission := range AdminPermissions {
+		res = append(res, permission.Name)
+	}
+
+	return res
+}
+
+// end of region service types
@@ -6,6 +6,7 @@ import (
 	"strconv"
 
 	"github.com/go-errors/errors"
+	"github.com/google/uuid"
 )
 
 type ApiResponse struct {
@@ -27,8 +28,8 @@ func (r ApiResponse) MarshalJSON() ([]byte, error) {
 }
 
 type PaginationReq struct {
-	Page string
-	Size string
+	Page string `form:"page"`
+	Size string `form:"size"`
 }
 
 type PaginationRes struct {
@@ -89,3 +90,60 @@ func (r PaginationReq) GeneratePaginationResponse(totalItem int64) PaginationRes
 		TotalPage: totalPage,
 	}
 }
+
+// CursorPaginationReq pages through a list ordered by a unique and monotonic key, After is the key of the last item of the previous page
+type CursorPaginationReq struct {
+	After string `form:"after"`
+	Size  string `form:"size"`
+}
+
+type CursorPaginationRes struct {
+	Size    int32  `json:"size"`
+	After   string `json:"after"`
+	HasMore bool   `json:"has_more"`
+}
+
+const CursorPaginationMaxSize = 100
+
+func (r *CursorPaginationReq) ValidateAndNormalize() error {
+	if r.Size == "" {
+		r.Size = "30"
+	}
+
+	sizeReq, err := strconv.Atoi(r.Size)
+	if err != nil {
+		return errors.New(AppErr{
+			Code:    http.StatusBadRequest,
+			Message: "invalid size query",
+		})
+	}
+
+	if sizeReq < 1 {
+		r.Size = "10"
+	} else if sizeReq > CursorPaginationMaxSize {
+		r.Size = strconv.Itoa(CursorPaginationMaxSize)
+	}
+
+	return nil
+}
+
+func (r CursorPaginationReq) GetSize() int {
+	size, _ := strconv.Atoi(r.Size)
+	return size
+}
+
+func (r CursorPaginationReq) GetAfterID() (uuid.NullUUID, error) {
+	if r.After == "" {
+		return uuid.NullUUID{}, nil
+	}
+
+	ID, err := uuid.Parse(r.After)
+	if err != nil {
+		return uuid.NullUUID{}, errors.New(AppErr{
+			Code:    http.StatusBadRequest,
+			Message: "invalid after query",
+		})
+	}
+
+	return uuid.NullUUID{UUID: ID, Valid: true}, nil
+}
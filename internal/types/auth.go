@@ -2,6 +2,8 @@ package types
 
 import (
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/go-errors/errors"
 	validation "github.com/go-ozzo/ozzo-validation/v4"
@@ -19,8 +21,18 @@ var (
 	AuthErrInvalidToken       = errors.New(AppErr{Code: http.StatusUnauthorized, Message: "invalid token"})
 	AuthErrInvalidTokenClaims = errors.New(AppErr{Code: http.StatusUnauthorized, Message: "invalid token claims"})
 	AuthErrSessionRevoked     = errors.New(AppErr{Code: http.StatusUnauthorized, Message: "session revoked"})
+	AuthErrRefreshTokenReused = errors.New(AppErr{Code: http.StatusUnauthorized, Message: "refresh token has been used, please log in again"})
+	AuthErrInvalidEmailToken  = errors.New(AppErr{Code: http.StatusBadRequest, Message: "invalid or expired token"})
+	AuthErrEmailCooldown      = errors.New(AppErr{Code: http.StatusTooManyRequests, Message: "an email has just been sent, please wait before requesting another one"})
+	AuthErrEmailRegistered    = errors.New(AppErr{Code: http.StatusConflict, Message: "this email has been registered, use another email"})
 )
 
+// AuthRecentLoginDuration is how long after logging in a google account without a password can set one
+const AuthRecentLoginDuration = 5 * time.Minute
+
+// AuthPasswordRules follows the bcrypt limit, a longer password would be truncated silently
+var AuthPasswordRules = []validation.Rule{validation.Required, validation.Length(8, 72)}
+
 // region repo types
 
 type AuthProvider int16
@@ -36,34 +48,48 @@ const (
 
 type AuthJwtCustomClaims struct {
 	jwt.RegisteredClaims
-	ID                     uuid.UUID `json:"jti"`
-	Subject                uuid.UUID `json:"sub"`
-	Role                   UserRole  `json:"role"`
-	Name                   string    `json:"name"`
-	IncompleteRegistration *bool     `json:"incomplete_registration,omitempty"`
+	ID                     uuid.UUID  `json:"jti"`
+	Subject                uuid.UUID  `json:"sub"`
+	Role                   UserRole   `json:"role"`
+	Name                   string     `json:"name"`
+	IncompleteRegistration *bool      `json:"incomplete_registration,omitempty"`
+	FamilyID               *uuid.UUID `json:"fam,omitempty"` // only in the refresh token
 }
 
 type AuthCreateSessionReq struct {
 	Email    string `json:"email"`
 	Password string `json:"password"`
+	SessionMetadataReq
 }
 
-func (r AuthCreateSessionReq) Validate() error {
-	return validation.ValidateStruct(&r,
+func (r *AuthCreateSessionReq) ValidateAndNormalize() error {
+	r.Email = strings.ToLower(strings.TrimSpace(r.Email))
+
+	return validation.ValidateStruct(r,
 		validation.Field(&r.Email, validation.Required, is.Email),
 		validation.Field(&r.Password, validation.Required),
 	)
 }
 
+// AuthCreateSessionRes only has TwoFactorChallenge when the login needs a two-factor code, the tokens are issued by the verification
 type AuthCreateSessionRes struct {
-	AccessToken  string `json:"access_token"`
-	RefreshToken string `json:"refresh_token"`
+	AccessToken        string                     `json:"access_token,omitempty"`
+	RefreshToken       string                     `json:"refresh_token,omitempty"`
+	TwoFactorChallenge *AuthTwoFactorChallengeRes `json:"two_factor_challenge,omitempty"`
 }
 
 type AuthCreateSessionForGoogleLoginRes struct {
-	AccessToken  string   `json:"access_token"`
-	RefreshToken string   `json:"refresh_token"`
-	Role         UserRole `json:"role"`
+	AccessToken        string                     `json:"access_token,omitempty"`
+	RefreshToken       string                     `json:"refresh_token,omitempty"`
+	Role               UserRole                   `json:"role"`
+	TwoFactorChallenge *AuthTwoFactorChallengeRes `json:"two_factor_challenge,omitempty"`
+}
+
+// AuthTwoFactorChallengeRes is returned instead of the tokens, EnrollmentRequired tells the client to set up an authenticator app first
+type AuthTwoFactorChallengeRes struct {
+	Token              string    `json:"token"`
+	ExpiresAt          time.Time `json:"expires_at"`
+	EnrollmentRequired bool      `json:"enrollment_required"`
 }
 
 type AuthUser struct {
@@ -71,7 +97,8 @@ type AuthUser struct {
 	SessionID              uuid.UUID
 	Role                   UserRole
 	Name                   string
-	IncompleteRegistration *bool // for service provider role
+	IncompleteRegistration *bool     // for service provider role
+	IssuedAt               time.Time // of the access token, zero when the token has no issue time
 }
 
 func (r AuthUser) IsZero() bool {
@@ -85,6 +112,7 @@ type AuthGenerateToken struct {
 
 type AuthCreateSessionForGoogleReq struct {
 	IDToken string `json:"id_token"`
+	SessionMetadataReq
 }
 
 type AuthValidateGoogleIDToken struct {
@@ -98,6 +126,7 @@ type AuthHeaderReq struct {
 
 type AuthRenewSessionReq struct {
 	RefreshToken string `json:"refresh_token"`
+	SessionMetadataReq
 }
 
 func (r AuthRenewSessionReq) Validate() error {
@@ -120,4 +149,105 @@ func (r AuthRevokeSessionReq) Validate() error {
 	return nil
 }
 
+type AuthRegisterReq struct {
+	Name     string `json:"name"`
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+func (r *AuthRegisterReq) ValidateAndNormalize() error {
+	r.Name = strings.TrimSpace(r.Name)
+	r.Email = strings.ToLower(strings.TrimSpace(r.Email))
+
+	return validation.ValidateStruct(r,
+		validation.Field(&r.Name, validation.Required, validation.Length(1, 255)),
+		validation.Field(&r.Email, validation.Required, validation.Length(1, 255), is.Email),
+		validation.Field(&r.Password, AuthPasswordRules...),
+	)
+}
+
+type AuthVerifyEmailReq struct {
+	Token string `json:"token"`
+}
+
+func (r AuthVerifyEmailReq) Validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.Token, validation.Required),
+	)
+}
+
+// AuthEmailReq is used to request the verification email again and to request a password reset
+type AuthEmailReq struct {
+	Email string `json:"email"`
+}
+
+func (r *AuthEmailReq) ValidateAndNormalize() error {
+	r.Email = strings.ToLower(strings.TrimSpace(r.Email))
+
+	return validation.ValidateStruct(r,
+		validation.Field(&r.Email, validation.Required, is.Email),
+	)
+}
+
+type AuthResetPasswordReq struct {
+	Token    string `json:"token"`
+	Password string `json:"password"`
+}
+
+func (r AuthResetPasswordReq) Validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.Token, validation.Required),
+		validation.Field(&r.Password, AuthPasswordRules...),
+	)
+}
+
+// AuthChangePasswordReq does not require CurrentPassword from a google account that has never set a password,
+// such an account has to log in again right before instead
+type AuthChangePasswordReq struct {
+	AuthUser        AuthUser `middleware:"user"`
+	CurrentPassword string   `json:"current_password"`
+	NewPassword     string   `json:"new_password"`
+}
+
+func (r AuthChangePasswordReq) Validate() error {
+	if r.AuthUser.IsZero() {
+		return errors.New("AuthUser is required")
+	}
+
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.NewPassword, append(AuthPasswordRules, validation.NotIn(r.CurrentPassword).Error("must be different from current_password"))...),
+	)
+}
+
+type AuthVerifyTwoFactorReq struct {
+	ChallengeToken string `json:"challenge_token"`
+	Code           string `json:"code"`
+	SessionMetadataReq
+}
+
+func (r AuthVerifyTwoFactorReq) Validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.ChallengeToken, validation.Required),
+		validation.Field(&r.Code, validation.Required, validation.Length(1, 20)),
+	)
+}
+
+// AuthVerifyTwoFactorRes has RecoveryCodes when the verification completes an enrollment
+type AuthVerifyTwoFactorRes struct {
+	AccessToken   string   `json:"access_token"`
+	RefreshToken  string   `json:"refresh_token"`
+	RecoveryCodes []string `json:"recovery_codes,omitempty"`
+}
+
+// AuthEnrollTwoFactorReq is used by a user that has to enroll before the login completes
+type AuthEnrollTwoFactorReq struct {
+	ChallengeToken string `json:"challenge_token"`
+}
+
+func (r AuthEnrollTwoFactorReq) Validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.ChallengeToken, validation.Required),
+	)
+}
+
 // end of region service types
@@ -0,0 +1,42 @@
+package types
+
+import (
+	"fmt"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+const (
+	AuthTokenKey         = "auth-token"
+	AuthEmailCooldownKey = "auth-email-cooldown"
+)
+
+// region repo types
+
+type AuthTokenPurpose string
+
+const (
+	AuthTokenPurposeEmailVerification AuthTokenPurpose = "email-verification"
+	AuthTokenPurposePasswordReset     AuthTokenPurpose = "password-reset"
+	AuthTokenPurposeTwoFactor         AuthTokenPurpose = "two-factor"
+)
+
+// GetAuthTokenKey returns the key holding the id of the last token sent to the user, an older token of the same purpose is no longer valid
+func GetAuthTokenKey(purpose AuthTokenPurpose, userID string) string {
+	return fmt.Sprintf("%s:%s:%s", AuthTokenKey, purpose, userID)
+}
+
+// GetAuthEmailCooldownKey is set by email so the cooldown can not tell whether the email is registered
+func GetAuthEmailCooldownKey(purpose AuthTokenPurpose, email string) string {
+	return fmt.Sprintf("%s:%s:%s", AuthEmailCooldownKey, purpose, email)
+}
+
+type AuthTokenClaims struct {
+	jwt.RegisteredClaims
+	ID      uuid.UUID        `json:"jti"`
+	Subject uuid.UUID        `json:"sub"`
+	Purpose AuthTokenPurpose `json:"purpose"`
+}
+
+// end of region repo types
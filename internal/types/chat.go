@@ -2,11 +2,13 @@ package types
 
 import (
 	dbUtil "kelarin/internal/utils/dbutil"
+	"net/http"
 	"time"
 
 	"github.com/go-errors/errors"
 	validation "github.com/go-ozzo/ozzo-validation/v4"
 	"github.com/google/uuid"
+	"github.com/volatiletech/null/v9"
 )
 
 // region service types
@@ -39,7 +41,7 @@ type ChatSendMessageReq struct {
 	RoomID            uuid.NullUUID          `json:"room_id"`
 	SenderUserID      uuid.UUID              `json:"-"`
 	ServiceProviderID uuid.NullUUID          `json:"service_provider_id"`
-	Content           string                 `json:"content"`
+	Content           string                 `json:"content"` // file key of the uploaded attachment when the content type is not text
 	ContentType       ChatMessageContentType `json:"content_type"`
 }
 
@@ -52,7 +54,7 @@ func (r ChatSendMessageReq) Validate() error {
 		validation.Field(&r.ID, validation.Required),
 		validation.Field(&r.RoomID, validation.Required.When(!r.ServiceProviderID.Valid)),
 		validation.Field(&r.Content, validation.Required),
-		validation.Field(&r.ContentType, validation.Required),
+		validation.Field(&r.ContentType, validation.Required, validation.In(ChatMessageContentTypeText, ChatMessageContentTypeImage, ChatMessageContentTypeVideo)),
 		validation.Field(&r.ServiceProviderID, validation.Required.When(!r.RoomID.Valid)),
 	)
 }
@@ -62,11 +64,66 @@ type ChatSendMessageResMetadata struct {
 }
 
 type ChatIncomingMessageRes struct {
-	RoomID      uuid.UUID              `json:"room_id"`
-	MessageID   uuid.UUID              `json:"message_id"`
-	Content     string                 `json:"content"`
-	ContentType ChatMessageContentType `json:"content_type"`
-	CreatedAt   time.Time              `json:"created_at"`
+	RoomID        uuid.UUID              `json:"room_id"`
+	MessageID     uuid.UUID              `json:"message_id"`
+	Content       string                 `json:"content"`
+	ContentType   ChatMessageContentType `json:"content_type"`
+	AttachmentURL string                 `json:"attachment_url,omitempty"`
+	ThumbnailURL  string                 `json:"thumbnail_url,omitempty"`
+	CreatedAt     time.Time              `json:"created_at"`
+}
+
+// ChatSendQueueSize is how many messages of a client can wait for the previous ones to be sent
+const ChatSendQueueSize = 16
+
+// ChatResumeLimit is the most messages replayed by a resume. A replay is also capped to the free space of the send
+// buffer of the client, when there are more the client resumes again from the last replayed message
+const ChatResumeLimit = 200
+
+// ChatResumeReq is sent by a reconnecting client to receive the messages it missed while it was disconnected
+type ChatResumeReq struct {
+	LastMessageID uuid.UUID `json:"last_message_id"`
+}
+
+func (r ChatResumeReq) Validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.LastMessageID, validation.Required),
+	)
+}
+
+type ChatResumeRes struct {
+	Count   int  `json:"count"`
+	HasMore bool `json:"has_more"` // resume again from the last replayed message to receive the rest
+}
+
+// ChatRoomEventReq is sent by the client to tell the other party of the room it is typing or to ask for its presence
+type ChatRoomEventReq struct {
+	RoomID uuid.UUID `json:"room_id"`
+}
+
+func (r ChatRoomEventReq) Validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.RoomID, validation.Required),
+	)
+}
+
+type ChatTypingRes struct {
+	RoomID uuid.UUID `json:"room_id"`
+	UserID uuid.UUID `json:"user_id"`
+}
+
+// ChatPresenceRes has the last seen time only when the user is offline and has connected before
+type ChatPresenceRes struct {
+	UserID     uuid.UUID `json:"user_id"`
+	Online     bool      `json:"online"`
+	LastSeenAt null.Time `json:"last_seen_at"`
+}
+
+// ChatReceiptRes is sent to the sender of the messages once they are delivered to or read by the recipient
+type ChatReceiptRes struct {
+	RoomID     uuid.UUID  `json:"room_id"`
+	MessageIDs uuid.UUIDs `json:"message_ids"`
+	At         time.Time  `json:"at"`
 }
 
 type ChatSaveSentMessageReq struct {
@@ -75,6 +132,7 @@ type ChatSaveSentMessageReq struct {
 	RecipientUserID uuid.NullUUID          `json:"recipient_user_id"`
 	Content         string                 `json:"content"`
 	ContentType     ChatMessageContentType `json:"content_type"`
+	Thumbnail       null.String            `json:"thumbnail"`
 }
 
 func (r ChatSaveSentMessageReq) Validate() error {
@@ -142,9 +200,10 @@ type ChatGetByRoomIDReq struct {
 	AuthUser AuthUser  `middleware:"user"`
 	TimeZone string    `header:"Time-Zone"`
 	RoomID   uuid.UUID `param:"room_id"`
+	ChatMessageCursorPaginationReq
 }
 
-func (r ChatGetByRoomIDReq) Validate() error {
+func (r *ChatGetByRoomIDReq) ValidateAndNormalize() error {
 	if r.AuthUser.IsZero() {
 		return errors.New("AuthUser is required")
 	}
@@ -153,7 +212,94 @@ func (r ChatGetByRoomIDReq) Validate() error {
 		return ErrIDRouteParamRequired
 	}
 
-	return nil
+	return r.ChatMessageCursorPaginationReq.ValidateAndNormalize()
+}
+
+// ChatMessageCursorPaginationReq pages through the messages of a room from the newest one. Before is the id of the oldest
+// message the client has to load older messages, After is the id of the newest message the client has to load newer ones
+type ChatMessageCursorPaginationReq struct {
+	CursorPaginationReq
+	Before string `form:"before"`
+}
+
+func (r *ChatMessageCursorPaginationReq) ValidateAndNormalize() error {
+	if r.Before != "" && r.After != "" {
+		return errors.New(AppErr{
+			Code:    http.StatusBadRequest,
+			Message: "before and after query can not be used together",
+		})
+	}
+
+	return r.CursorPaginationReq.ValidateAndNormalize()
+}
+
+func (r ChatMessageCursorPaginationReq) GetBeforeID() (uuid.NullUUID, error) {
+	if r.Before == "" {
+		return uuid.NullUUID{}, nil
+	}
+
+	ID, err := uuid.Parse(r.Before)
+	if err != nil {
+		return uuid.NullUUID{}, errors.New(AppErr{
+			Code:    http.StatusBadRequest,
+			Message: "invalid before query",
+		})
+	}
+
+	return uuid.NullUUID{UUID: ID, Valid: true}, nil
+}
+
+// ChatMessageCursorPaginationRes has the ids of the oldest and the newest message of the page, HasMore tells whether
+// there are more messages in the direction of the request, older ones unless the request has After
+type ChatMessageCursorPaginationRes struct {
+	Size    int32  `json:"size"`
+	Before  string `json:"before"`
+	After   string `json:"after"`
+	HasMore bool   `json:"has_more"`
+}
+
+type ChatSearchReq struct {
+	AuthUser AuthUser `middleware:"user"`
+	TimeZone string   `header:"Time-Zone"`
+	Query    string   `form:"q"`
+	CursorPaginationReq
+}
+
+func (r *ChatSearchReq) ValidateAndNormalize() error {
+	if r.AuthUser.IsZero() {
+		return errors.New("AuthUser is required")
+	}
+
+	err := validation.ValidateStruct(r,
+		validation.Field(&r.Query, validation.Required, validation.Length(2, 100)),
+	)
+	if err != nil {
+		return err
+	}
+
+	return r.CursorPaginationReq.ValidateAndNormalize()
+}
+
+type ChatSearchRes struct {
+	RoomID    uuid.UUID              `json:"room_id"`
+	Recipient ChatSearchResRecipient `json:"recipient"`
+	Message   ChatSearchResMessage   `json:"message"`
+}
+
+// ChatSearchResRecipient is the other party of the room, the name of a service provider is the name of its business
+type ChatSearchResRecipient struct {
+	UserID uuid.UUID `json:"user_id"`
+	Name   string    `json:"name"`
+}
+
+// ChatSearchResMessage has the html escaped content with its matched words wrapped in <b> tags in Headline, the room
+// can be opened around the message with the before and after query of the message id
+type ChatSearchResMessage struct {
+	ID        uuid.UUID `json:"id"`
+	IsSender  bool      `json:"is_sender"`
+	Content   string    `json:"content"`
+	Headline  string    `json:"headline"`
+	CreatedAt time.Time `json:"created_at"`
 }
 
 type ChatConsumerGetByRoomIDRes struct {
@@ -185,12 +331,15 @@ type ChatGetByRoomIDResOrder struct {
 }
 
 type ChatGetByRoomIDResMessage struct {
-	ID          uuid.UUID              `json:"id"`
-	IsSender    bool                   `json:"is_sender"`
-	Content     string                 `json:"content"`
-	ContentType ChatMessageContentType `json:"content_type"`
-	Read        bool                   `json:"read"`
-	CreatedAt   time.Time              `json:"created_at"`
+	ID            uuid.UUID              `json:"id"`
+	IsSender      bool                   `json:"is_sender"`
+	Content       string                 `json:"content"`
+	ContentType   ChatMessageContentType `json:"content_type"`
+	AttachmentURL string                 `json:"attachment_url,omitempty"`
+	ThumbnailURL  string                 `json:"thumbnail_url,omitempty"`
+	Delivered     bool                   `json:"delivered"`
+	Read          bool                   `json:"read"`
+	CreatedAt     time.Time              `json:"created_at"`
 }
 
 type ChatProviderGetAllRes struct {
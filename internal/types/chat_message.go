@@ -1,9 +1,12 @@
 package types
 
 import (
+	"html"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/volatiletech/null/v9"
 )
 
 // region repo types
@@ -12,9 +15,11 @@ type ChatMessage struct {
 	ID          uuid.UUID              `db:"id"`
 	ChatRoomID  uuid.UUID              `db:"chat_room_id"`
 	UserID      uuid.UUID              `db:"user_id"`
-	Content     string                 `db:"content"`
+	Content     string                 `db:"content"` // object key of the attachment when the content type is not text
 	ContentType ChatMessageContentType `db:"content_type"`
+	Thumbnail   null.String            `db:"thumbnail_image"`
 	Read        bool                   `db:"read"`
+	DeliveredAt null.Time              `db:"delivered_at"` // set once the message reaches a connection of the recipient
 	CreatedAt   time.Time              `db:"created_at"`
 }
 
@@ -26,6 +31,48 @@ const (
 	ChatMessageContentTypeVideo ChatMessageContentType = "video"
 )
 
+const (
+	ChatImageDir          = "images/chat"
+	ChatImageThumbnailDir = "images/chat/thumbnails"
+	ChatVideoDir          = "videos/chat"
+
+	ChatThumbnailMaxSize = 320
+	// ChatImageMaxPixels bounds the width times the height of an image decoded for its thumbnail, a small file can
+	// declare huge dimensions and decoding it allocates the whole bitmap
+	ChatImageMaxPixels = 40_000_000
+)
+
+// ChatMessageCursorFilter returns the messages nearest to the cursor first, the newest ones when there is no cursor.
+// Only one of Before and After is set
+type ChatMessageCursorFilter struct {
+	Before uuid.NullUUID
+	After  uuid.NullUUID
+	Limit  int
+}
+
+// ChatSearchHeadlineStartSel and ChatSearchHeadlineStopSel wrap the matched words of a search headline until the
+// content is html escaped
+const (
+	ChatSearchHeadlineStartSel = "\x02"
+	ChatSearchHeadlineStopSel  = "\x03"
+)
+
+// ChatMessageSearchResult is a message of a room of the user matching the search query
+type ChatMessageSearchResult struct {
+	ChatMessage
+	Headline        string    `db:"headline"`
+	RecipientUserID uuid.UUID `db:"recipient_user_id"`
+	RecipientName   string    `db:"recipient_name"`
+}
+
+// HeadlineHTML escapes the headline and wraps its matched words in <b> tags
+func (r ChatMessageSearchResult) HeadlineHTML() string {
+	return strings.NewReplacer(
+		ChatSearchHeadlineStartSel, "<b>",
+		ChatSearchHeadlineStopSel, "</b>",
+	).Replace(html.EscapeString(r.Headline))
+}
+
 type ChatMessageCountUnread struct {
 	ChatRoomID uuid.UUID `db:"chat_room_id"`
 	Count      int       `db:"count"`
@@ -18,6 +18,7 @@ type ConsumerNotification struct {
 	OfferNegotiationID uuid.NullUUID            `db:"offer_negotiation_id"`
 	PaymentID          uuid.NullUUID            `db:"payment_id"`
 	OrderID            uuid.NullUUID            `db:"order_id"`
+	DisputeID          uuid.NullUUID            `db:"dispute_id"`
 	Type               ConsumerNotificationType `db:"type"`
 	Read               bool                     `db:"read"`
 	CreatedAt          time.Time                `db:"created_at"`
@@ -34,12 +35,35 @@ const (
 const (
 	ConsumerNotificationTypePaymentSuccess ConsumerNotificationType = iota + 101
 	ConsumerNotificationTypePaymentExpired
+	ConsumerNotificationTypePaymentRefunded
 )
 
 const (
 	ConsumerNotificationTypeOrderFinished ConsumerNotificationType = iota + 201
+	ConsumerNotificationTypeOrderCanceled
+	ConsumerNotificationTypeOrderSessionFinished
 )
 
+const (
+	ConsumerNotificationTypeDisputeResponded ConsumerNotificationType = iota + 301
+	ConsumerNotificationTypeDisputeResolved
+)
+
+// ConsumerNotificationTypes is every type a consumer can set a notification preference for
+var ConsumerNotificationTypes = []ConsumerNotificationType{
+	ConsumerNotificationTypeOfferNegotiationReceived,
+	ConsumerNotificationTypeOfferAccepted,
+	ConsumerNotificationTypeOfferRejected,
+	ConsumerNotificationTypePaymentSuccess,
+	ConsumerNotificationTypePaymentExpired,
+	ConsumerNotificationTypePaymentRefunded,
+	ConsumerNotificationTypeOrderFinished,
+	ConsumerNotificationTypeOrderCanceled,
+	ConsumerNotificationTypeOrderSessionFinished,
+	ConsumerNotificationTypeDisputeResponded,
+	ConsumerNotificationTypeDisputeResolved,
+}
+
 type ConsumerNotificationWithServiceProviderAndPayment struct {
 	ConsumerNotification
 	ServiceProviderName      null.String         `db:"service_provider_name"`
@@ -57,14 +81,15 @@ type ConsumerNotificationWithServiceProviderAndPayment struct {
 type ConsumerNotificationGetAllReq struct {
 	AuthUser AuthUser `middleware:"user"`
 	TimeZone string   `header:"Time-Zone"`
+	CursorPaginationReq
 }
 
-func (r ConsumerNotificationGetAllReq) Validate() error {
-	if r.AuthUser == (AuthUser{}) {
+func (r *ConsumerNotificationGetAllReq) ValidateAndNormalize() error {
+	if r.AuthUser.IsZero() {
 		return errors.New("AuthUser is required")
 	}
 
-	return nil
+	return r.CursorPaginationReq.ValidateAndNormalize()
 }
 
 type ConsumerNotificationGetAllRes struct {
@@ -93,6 +118,11 @@ type ConsumerNotificationMetadataOrder struct {
 	OrderID uuid.UUID `json:"order_id"`
 }
 
+type ConsumerNotificationMetadataDispute struct {
+	OrderID   uuid.UUID `json:"order_id"`
+	DisputeID uuid.UUID `json:"dispute_id"`
+}
+
 type ConsumerNotificationGeneratedDetails struct {
 	Title    string
 	Message  string
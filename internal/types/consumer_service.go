@@ -1,25 +1,152 @@
 package types
 
 import (
+	"fmt"
 	"net/http"
 
 	"github.com/go-errors/errors"
 	validation "github.com/go-ozzo/ozzo-validation/v4"
 	"github.com/google/uuid"
 	"github.com/shopspring/decimal"
+	"github.com/volatiletech/null/v9"
 )
 
 // region service types
 
+const (
+	ConsumerServiceDefaultRadiusKm = 10
+	ConsumerServiceMaxRadiusKm     = 100
+)
+
 type ConsumerServiceGetAllReq struct {
+	// AuthUser is optional, only required when searching around one of the user addresses
+	AuthUser   AuthUser
 	Province   string
 	City       string
 	Categories []string
 	Keyword    string
 	After      string
+	Lat        null.Float64
+	Lng        null.Float64
+	AddressID  uuid.NullUUID
+	// Radius in kilometers
+	Radius          null.Float64
+	FeeMin          decimal.NullDecimal
+	FeeMax          decimal.NullDecimal
+	MinRating       null.Float64
+	DeliveryMethods []ServiceDeliveryMethod
+	IsAvailable     null.Bool
+	Sort            ServiceIndexSort
 	PaginationReq
 }
 
+func (r *ConsumerServiceGetAllReq) ValidateAndNormalize() error {
+	if err := r.PaginationReq.ValidateAndNormalize(); err != nil {
+		return err
+	}
+
+	if r.Lat.Valid != r.Lng.Valid {
+		return errors.New(AppErr{
+			Code:    http.StatusBadRequest,
+			Message: "lat and lng must be sent together",
+		})
+	}
+
+	if r.Lat.Valid && r.AddressID.Valid {
+		return errors.New(AppErr{
+			Code:    http.StatusBadRequest,
+			Message: "either lat and lng or address_id can be sent",
+		})
+	}
+
+	if r.Lat.Valid && (r.Lat.Float64 < -90 || r.Lat.Float64 > 90) {
+		return errors.New(AppErr{
+			Code:    http.StatusBadRequest,
+			Message: "lat must be between -90 to 90",
+		})
+	}
+
+	if r.Lng.Valid && (r.Lng.Float64 < -180 || r.Lng.Float64 > 180) {
+		return errors.New(AppErr{
+			Code:    http.StatusBadRequest,
+			Message: "lng must be between -180 to 180",
+		})
+	}
+
+	if r.AddressID.Valid && r.AuthUser.IsZero() {
+		return errors.New(AppErr{Code: http.StatusUnauthorized})
+	}
+
+	if r.Radius.Valid && (r.Radius.Float64 <= 0 || r.Radius.Float64 > ConsumerServiceMaxRadiusKm) {
+		return errors.New(AppErr{
+			Code:    http.StatusBadRequest,
+			Message: fmt.Sprintf("radius must be greater than 0 and at most %d km", ConsumerServiceMaxRadiusKm),
+		})
+	}
+
+	if !r.Radius.Valid {
+		r.Radius = null.Float64From(ConsumerServiceDefaultRadiusKm)
+	}
+
+	if r.FeeMin.Valid && r.FeeMin.Decimal.IsNegative() {
+		return errors.New(AppErr{
+			Code:    http.StatusBadRequest,
+			Message: "fee_min must be greater than or equal to 0",
+		})
+	}
+
+	if r.FeeMin.Valid && r.FeeMax.Valid && r.FeeMin.Decimal.GreaterThan(r.FeeMax.Decimal) {
+		return errors.New(AppErr{
+			Code:    http.StatusBadRequest,
+			Message: "fee_min must be less than or equal to fee_max",
+		})
+	}
+
+	if r.MinRating.Valid && (r.MinRating.Float64 < 0 || r.MinRating.Float64 > 5) {
+		return errors.New(AppErr{
+			Code:    http.StatusBadRequest,
+			Message: "min_rating must be between 0 to 5",
+		})
+	}
+
+	for _, deliveryMethod := range r.DeliveryMethods {
+		if deliveryMethod != ServiceDeliveryMethodOnsite && deliveryMethod != ServiceDeliveryMethodOnline {
+			return errors.New(AppErr{
+				Code:    http.StatusBadRequest,
+				Message: fmt.Sprintf("invalid delivery method: %s", deliveryMethod),
+			})
+		}
+	}
+
+	switch r.Sort {
+	case "":
+		r.Sort = ServiceIndexSortRelevance
+		if r.IsGeoSearch() {
+			r.Sort = ServiceIndexSortDistance
+		}
+	case ServiceIndexSortDistance:
+		if !r.IsGeoSearch() {
+			return errors.New(AppErr{
+				Code:    http.StatusBadRequest,
+				Message: "sort by distance requires lat and lng or address_id",
+			})
+		}
+	case ServiceIndexSortRelevance, ServiceIndexSortNewest, ServiceIndexSortPriceAsc, ServiceIndexSortPriceDesc, ServiceIndexSortRating:
+	default:
+		return errors.New(AppErr{
+			Code:    http.StatusBadRequest,
+			Message: "invalid sort query",
+		})
+	}
+
+	return nil
+}
+
+// IsGeoSearch reports whether the services are searched around a location
+func (r ConsumerServiceGetAllReq) IsGeoSearch() bool {
+	return r.Lat.Valid || r.AddressID.Valid
+}
+
 type ConsumerServiceGetAllRes struct {
 	ID                    uuid.UUID       `json:"id"`
 	Name                  string          `json:"name"`
@@ -31,6 +158,12 @@ type ConsumerServiceGetAllRes struct {
 	City                  string          `json:"city"`
 	ReceivedRatingCount   int32           `json:"received_rating_count"`
 	ReceivedRatingAverage float32         `json:"received_rating_average"`
+	// Distance in kilometers, only filled when searching around a location and the provider has an office
+	Distance null.Float64 `json:"distance"`
+}
+
+type ConsumerServiceGetAllMetadata struct {
+	Facets ServiceIndexFacets `json:"facets"`
 }
 
 type ConsumerServiceGetByIDRes struct {
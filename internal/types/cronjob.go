@@ -1,6 +1,8 @@
 package types
 
 const (
-	CronjobMarkOfferAsExpired = "mark-offer-as-expired"
-	CronjobUpdateOrderStatus  = "update-order-status"
+	CronjobMarkOfferAsExpired    = "mark-offer-as-expired"
+	CronjobUpdateOrderStatus     = "update-order-status"
+	CronjobLiftExpiredSuspension = "lift-expired-suspension"
+	CronjobSweepOutboxEvents     = "sweep-outbox-events"
 )
@@ -0,0 +1,246 @@
+package types
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/go-errors/errors"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/shopspring/decimal"
+	"github.com/volatiletech/null/v9"
+)
+
+// region repo types
+
+type Dispute struct {
+	ID                  uuid.UUID           `db:"id"`
+	OrderID             uuid.UUID           `db:"order_id"`
+	UserID              uuid.UUID           `db:"user_id"`
+	ServiceProviderID   uuid.UUID           `db:"service_provider_id"`
+	Reason              string              `db:"reason"`
+	EvidenceImages      pq.StringArray      `db:"evidence_images"`
+	Status              DisputeStatus       `db:"status"`
+	ProviderResponse    null.String         `db:"provider_response"`
+	ProviderRespondedAt null.Time           `db:"provider_responded_at"`
+	Outcome             null.String         `db:"outcome"`
+	RefundAmount        decimal.NullDecimal `db:"refund_amount"`
+	AdminUserID         uuid.NullUUID       `db:"admin_user_id"`
+	AdminNote           null.String         `db:"admin_note"`
+	ResolvedAt          null.Time           `db:"resolved_at"`
+	CreatedAt           time.Time           `db:"created_at"`
+	UpdatedAt           null.Time           `db:"updated_at"`
+}
+
+type DisputeStatus string
+
+const (
+	DisputeStatusOpen      DisputeStatus = "open"
+	DisputeStatusResponded DisputeStatus = "responded"
+	DisputeStatusResolved  DisputeStatus = "resolved"
+)
+
+type DisputeOutcome string
+
+const (
+	DisputeOutcomeRefund        DisputeOutcome = "refund"
+	DisputeOutcomePartialRefund DisputeOutcome = "partial_refund"
+	DisputeOutcomeDismiss       DisputeOutcome = "dismiss"
+)
+
+type DisputeWithRelations struct {
+	Dispute
+	UserName              string          `db:"user_name"`
+	ServiceProviderName   string          `db:"service_provider_name"`
+	ServiceProviderUserID uuid.UUID       `db:"service_provider_user_id"`
+	OrderServiceFee       decimal.Decimal `db:"order_service_fee"`
+}
+
+type DisputeFilter struct {
+	UserID            uuid.NullUUID
+	ServiceProviderID uuid.NullUUID
+	Status            DisputeStatus
+	Limit             int
+	Offset            int
+}
+
+// endregion repo types
+
+// region service types
+
+const (
+	// DisputeOpenWindow is how long after an order is finished the consumer can still open a dispute
+	DisputeOpenWindow = 7 * 24 * time.Hour
+	// DisputeMaxEvidenceImages is the maximum number of evidence images attached to a dispute
+	DisputeMaxEvidenceImages = 5
+)
+
+const DisputeEvidenceImageDir = "images/dispute"
+
+type DisputeConsumerCreateReq struct {
+	AuthUser       AuthUser  `middleware:"user"`
+	OrderID        uuid.UUID `json:"order_id"`
+	Reason         string    `json:"reason"`
+	EvidenceImages []string  `json:"evidence_images"`
+}
+
+func (r DisputeConsumerCreateReq) Validate() error {
+	if r.AuthUser.IsZero() {
+		return errors.New("AuthUser is required")
+	}
+
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.OrderID, validation.Required),
+		validation.Field(&r.Reason, validation.Required, validation.Length(10, 2000)),
+		validation.Field(&r.EvidenceImages, validation.Length(0, DisputeMaxEvidenceImages)),
+	)
+}
+
+type DisputeConsumerGetAllReq struct {
+	AuthUser AuthUser      `middleware:"user"`
+	Status   DisputeStatus `form:"status"`
+	PaginationReq
+}
+
+func (r DisputeConsumerGetAllReq) Validate() error {
+	if r.AuthUser.IsZero() {
+		return errors.New("AuthUser is required")
+	}
+
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.Status, validation.In(DisputeStatusOpen, DisputeStatusResponded, DisputeStatusResolved)),
+	)
+}
+
+type DisputeProviderGetAllReq struct {
+	AuthUser AuthUser      `middleware:"user"`
+	Status   DisputeStatus `form:"status"`
+	PaginationReq
+}
+
+func (r DisputeProviderGetAllReq) Validate() error {
+	if r.AuthUser.IsZero() {
+		return errors.New("AuthUser is required")
+	}
+
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.Status, validation.In(DisputeStatusOpen, DisputeStatusResponded, DisputeStatusResolved)),
+	)
+}
+
+type DisputeAdminGetAllReq struct {
+	AuthUser AuthUser      `middleware:"user"`
+	Status   DisputeStatus `form:"status"`
+	PaginationReq
+}
+
+func (r DisputeAdminGetAllReq) Validate() error {
+	if r.AuthUser.IsZero() {
+		return errors.New("AuthUser is required")
+	}
+
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.Status, validation.In(DisputeStatusOpen, DisputeStatusResponded, DisputeStatusResolved)),
+	)
+}
+
+// DisputeGetByIDReq is used by the consumer, the service provider and the admin
+type DisputeGetByIDReq struct {
+	AuthUser AuthUser  `middleware:"user"`
+	ID       uuid.UUID `param:"id"`
+}
+
+func (r DisputeGetByIDReq) Validate() error {
+	if r.AuthUser.IsZero() {
+		return errors.New("AuthUser is required")
+	}
+
+	if r.ID == uuid.Nil {
+		return ErrIDRouteParamRequired
+	}
+
+	return nil
+}
+
+type DisputeGetRes struct {
+	ID                  uuid.UUID                    `json:"id"`
+	OrderID             uuid.UUID                    `json:"order_id"`
+	OrderServiceFee     decimal.Decimal              `json:"order_service_fee"`
+	User                DisputeGetResUser            `json:"user"`
+	ServiceProvider     DisputeGetResServiceProvider `json:"service_provider"`
+	Reason              string                       `json:"reason"`
+	EvidenceImageURLs   []string                     `json:"evidence_image_urls"`
+	Status              DisputeStatus                `json:"status"`
+	ProviderResponse    null.String                  `json:"provider_response"`
+	ProviderRespondedAt null.Time                    `json:"provider_responded_at"`
+	Outcome             null.String                  `json:"outcome"`
+	RefundAmount        decimal.NullDecimal          `json:"refund_amount"`
+	AdminNote           null.String                  `json:"admin_note"`
+	ResolvedAt          null.Time                    `json:"resolved_at"`
+	CreatedAt           time.Time                    `json:"created_at"`
+	UpdatedAt           null.Time                    `json:"updated_at"`
+}
+
+type DisputeGetResUser struct {
+	ID   uuid.UUID `json:"id"`
+	Name string    `json:"name"`
+}
+
+type DisputeGetResServiceProvider struct {
+	ID   uuid.UUID `json:"id"`
+	Name string    `json:"name"`
+}
+
+type DisputeProviderRespondReq struct {
+	AuthUser AuthUser  `middleware:"user"`
+	ID       uuid.UUID `param:"id"`
+	Response string    `json:"response"`
+}
+
+func (r DisputeProviderRespondReq) Validate() error {
+	if r.AuthUser.IsZero() {
+		return errors.New("AuthUser is required")
+	}
+
+	if r.ID == uuid.Nil {
+		return ErrIDRouteParamRequired
+	}
+
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.Response, validation.Required, validation.Length(1, 2000)),
+	)
+}
+
+type DisputeAdminResolveReq struct {
+	AuthUser     AuthUser        `middleware:"user"`
+	ID           uuid.UUID       `param:"id"`
+	Outcome      DisputeOutcome  `json:"outcome"`
+	RefundAmount decimal.Decimal `json:"refund_amount"` // only used by partial_refund
+	Note         string          `json:"note"`
+}
+
+func (r DisputeAdminResolveReq) Validate() error {
+	if r.AuthUser.IsZero() {
+		return errors.New("AuthUser is required")
+	}
+
+	if r.ID == uuid.Nil {
+		return ErrIDRouteParamRequired
+	}
+
+	if err := validation.ValidateStruct(&r,
+		validation.Field(&r.Outcome, validation.Required, validation.In(DisputeOutcomeRefund, DisputeOutcomePartialRefund, DisputeOutcomeDismiss)),
+		validation.Field(&r.Note, validation.Required, validation.Length(1, 1000)),
+	); err != nil {
+		return err
+	}
+
+	if r.Outcome == DisputeOutcomePartialRefund && !r.RefundAmount.IsPositive() {
+		return errors.New(AppErr{Code: http.StatusBadRequest, Message: "refund_amount must be greater than 0"})
+	}
+
+	return nil
+}
+
+// endregion service types
@@ -0,0 +1,250 @@
+package types
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// region repo types
+
+type Language string
+
+const (
+	LanguageID Language = "id"
+	LanguageEN Language = "en"
+)
+
+type EmailMessage struct {
+	To      string
+	Subject string
+	HTML    string
+	Text    string
+}
+
+// EmailNotificationTaskRetention keeps a sent notification email in the queue so the outbox event that is retried does not send it again
+const EmailNotificationTaskRetention = 24 * time.Hour
+
+func EmailNotificationTaskID(outboxEventID uuid.UUID) string {
+	return fmt.Sprintf("email-notification:%s", outboxEventID)
+}
+
+// EmailNotificationCopy is the localized content of a notification type, the message of the notification
+// is shown below the body when WithMessage is set because it is written by the other party
+type EmailNotificationCopy struct {
+	Subject     string
+	Heading     string
+	Body        string
+	WithMessage bool
+}
+
+// EmailLayoutCopy is the localized content shared by every notification email
+type EmailLayoutCopy struct {
+	Greeting string // formatted with the name of the recipient
+	Button   string
+	Footer   string
+}
+
+var EmailLayoutCopies = map[Language]EmailLayoutCopy{
+	LanguageID: {
+		Greeting: "Halo %s,",
+		Button:   "Buka Kelarin",
+		Footer:   "Anda menerima email ini karena notifikasi email aktif di akun Kelarin Anda. Anda dapat mengubahnya melalui pengaturan notifikasi.",
+	},
+	LanguageEN: {
+		Greeting: "Hi %s,",
+		Button:   "Open Kelarin",
+		Footer:   "You received this email because email notifications are turned on for your Kelarin account. You can change it in the notification settings.",
+	},
+}
+
+var consumerNotificationEmailCopies = map[ConsumerNotificationType]map[Language]EmailNotificationCopy{
+	ConsumerNotificationTypeOfferNegotiationReceived: {
+		LanguageID: {Subject: "Penyedia jasa mengajukan negosiasi", Heading: "Ada negosiasi baru untuk penawaran Anda", Body: "Penyedia jasa mengajukan harga baru untuk penawaran Anda. Buka Kelarin untuk menerima atau menolaknya.", WithMessage: true},
+		LanguageEN: {Subject: "A service provider wants to negotiate", Heading: "New negotiation on your offer", Body: "A service provider has proposed a new price for your offer. Open Kelarin to accept or reject it.", WithMessage: true},
+	},
+	ConsumerNotificationTypeOfferAccepted: {
+		LanguageID: {Subject: "Penawaran Anda diterima", Heading: "Penawaran Anda diterima", Body: "Penyedia jasa menerima penawaran Anda. Selesaikan pembayaran agar pesanan Anda dapat segera diproses."},
+		LanguageEN: {Subject: "Your offer has been accepted", Heading: "Your offer has been accepted", Body: "The service provider accepted your offer. Complete the payment so your order can be processed."},
+	},
+	ConsumerNotificationTypeOfferRejected: {
+		LanguageID: {Subject: "Penawaran Anda ditolak", Heading: "Penawaran Anda ditolak", Body: "Penyedia jasa menolak penawaran Anda. Anda masih dapat mengirim penawaran baru."},
+		LanguageEN: {Subject: "Your offer has been rejected", Heading: "Your offer has been rejected", Body: "The service provider rejected your offer. You can still send a new offer."},
+	},
+	ConsumerNotificationTypePaymentSuccess: {
+		LanguageID: {Subject: "Pembayaran berhasil", Heading: "Pembayaran berhasil", Body: "Pembayaran pesanan Anda telah kami terima."},
+		LanguageEN: {Subject: "Payment successful", Heading: "Payment successful", Body: "We have received the payment for your order."},
+	},
+	ConsumerNotificationTypePaymentExpired: {
+		LanguageID: {Subject: "Pembayaran kedaluwarsa", Heading: "Pembayaran kedaluwarsa", Body: "Batas waktu pembayaran pesanan Anda telah berakhir."},
+		LanguageEN: {Subject: "Payment expired", Heading: "Payment expired", Body: "The payment deadline of your order has passed."},
+	},
+	ConsumerNotificationTypePaymentRefunded: {
+		LanguageID: {Subject: "Dana Anda telah dikembalikan", Heading: "Dana Anda telah dikembalikan", Body: "Pengembalian dana Anda telah dikirim ke metode pembayaran yang Anda gunakan."},
+		LanguageEN: {Subject: "Your payment has been refunded", Heading: "Your payment has been refunded", Body: "Your refund has been sent to the payment method you used."},
+	},
+	ConsumerNotificationTypeOrderFinished: {
+		LanguageID: {Subject: "Pesanan selesai", Heading: "Pesanan Anda telah selesai", Body: "Terima kasih telah menggunakan Kelarin. Bagikan pengalaman Anda dengan memberikan ulasan."},
+		LanguageEN: {Subject: "Order finished", Heading: "Your order has been completed", Body: "Thank you for using Kelarin. Share your experience by leaving a review."},
+	},
+	ConsumerNotificationTypeOrderCanceled: {
+		LanguageID: {Subject: "Pesanan dibatalkan", Heading: "Pesanan Anda dibatalkan", Body: "Pesanan Anda telah dibatalkan.", WithMessage: true},
+		LanguageEN: {Subject: "Order canceled", Heading: "Your order has been canceled", Body: "Your order has been canceled.", WithMessage: true},
+	},
+	ConsumerNotificationTypeOrderSessionFinished: {
+		LanguageID: {Subject: "Sesi layanan selesai", Heading: "Sesi layanan selesai", Body: "Salah satu sesi layanan pesanan Anda telah selesai."},
+		LanguageEN: {Subject: "Service session finished", Heading: "Service session finished", Body: "One of the service sessions of your order has been completed."},
+	},
+	ConsumerNotificationTypeDisputeResponded: {
+		LanguageID: {Subject: "Sengketa Anda mendapat tanggapan", Heading: "Sengketa Anda mendapat tanggapan", Body: "Penyedia jasa telah menanggapi sengketa yang Anda ajukan.", WithMessage: true},
+		LanguageEN: {Subject: "Your dispute has a response", Heading: "Your dispute has a response", Body: "The service provider has responded to your dispute.", WithMessage: true},
+	},
+	ConsumerNotificationTypeDisputeResolved: {
+		LanguageID: {Subject: "Sengketa telah diselesaikan", Heading: "Sengketa telah diselesaikan", Body: "Admin telah menyelesaikan sengketa pada pesanan Anda.", WithMessage: true},
+		LanguageEN: {Subject: "Dispute resolved", Heading: "Dispute resolved", Body: "An admin has resolved the dispute on your order.", WithMessage: true},
+	},
+}
+
+var serviceProviderNotificationEmailCopies = map[ServiceProviderNotificationType]map[Language]EmailNotificationCopy{
+	ServiceProviderNotificationTypeOfferReceived: {
+		LanguageID: {Subject: "Penawaran baru", Heading: "Anda menerima penawaran baru", Body: "Seorang pelanggan mengirim penawaran untuk layanan Anda. Tanggapi sebelum penawaran tersebut kedaluwarsa."},
+		LanguageEN: {Subject: "New offer", Heading: "You have received a new offer", Body: "A customer sent an offer for your service. Respond before the offer expires."},
+	},
+	ServiceProviderNotificationTypeOfferCanceled: {
+		LanguageID: {Subject: "Penawaran dibatalkan", Heading: "Penawaran dibatalkan", Body: "Pelanggan telah membatalkan penawarannya."},
+		LanguageEN: {Subject: "Offer canceled", Heading: "Offer canceled", Body: "The customer has canceled their offer."},
+	},
+	ServiceProviderNotificationTypeOfferNegotiationAccepted: {
+		LanguageID: {Subject: "Negosiasi diterima", Heading: "Negosiasi Anda diterima", Body: "Pelanggan menerima harga yang Anda ajukan."},
+		LanguageEN: {Subject: "Negotiation accepted", Heading: "Your negotiation has been accepted", Body: "The customer accepted the price you proposed."},
+	},
+	ServiceProviderNotificationTypeOfferNegotiationRejected: {
+		LanguageID: {Subject: "Negosiasi ditolak", Heading: "Negosiasi Anda ditolak", Body: "Pelanggan menolak harga yang Anda ajukan."},
+		LanguageEN: {Subject: "Negotiation rejected", Heading: "Your negotiation has been rejected", Body: "The customer rejected the price you proposed."},
+	},
+	ServiceProviderNotificationTypeConsumerSettledPayment: {
+		LanguageID: {Subject: "Pesanan telah dibayar", Heading: "Pelanggan telah membayar pesanan", Body: "Pelanggan telah menyelesaikan pembayaran. Jangan lupa periksa jadwal layanan Anda."},
+		LanguageEN: {Subject: "Order paid", Heading: "The customer has paid the order", Body: "The customer has completed the payment. Remember to check your service schedule."},
+	},
+	ServiceProviderNotificationTypeOrderFinished: {
+		LanguageID: {Subject: "Pesanan selesai", Heading: "Pesanan telah selesai", Body: "Pesanan pelanggan Anda telah selesai."},
+		LanguageEN: {Subject: "Order finished", Heading: "The order has been completed", Body: "The order of your customer has been completed."},
+	},
+	ServiceProviderNotificationTypeOrderCanceled: {
+		LanguageID: {Subject: "Pesanan dibatalkan", Heading: "Pesanan dibatalkan", Body: "Pesanan pelanggan Anda telah dibatalkan.", WithMessage: true},
+		LanguageEN: {Subject: "Order canceled", Heading: "Order canceled", Body: "The order of your customer has been canceled.", WithMessage: true},
+	},
+	ServiceProviderNotificationTypeOrderSessionFinished: {
+		LanguageID: {Subject: "Sesi layanan selesai", Heading: "Sesi layanan selesai", Body: "Salah satu sesi layanan pesanan pelanggan Anda telah selesai."},
+		LanguageEN: {Subject: "Service session finished", Heading: "Service session finished", Body: "One of the service sessions of your customer order has been completed."},
+	},
+	ServiceProviderNotificationTypeDisputeOpened: {
+		LanguageID: {Subject: "Sengketa baru", Heading: "Pelanggan mengajukan sengketa", Body: "Pelanggan mengajukan sengketa atas pesanan Anda. Tanggapi sengketa tersebut secepatnya.", WithMessage: true},
+		LanguageEN: {Subject: "New dispute", Heading: "A customer opened a dispute", Body: "A customer opened a dispute on your order. Please respond as soon as possible.", WithMessage: true},
+	},
+	ServiceProviderNotificationTypeDisputeResolved: {
+		LanguageID: {Subject: "Sengketa telah diselesaikan", Heading: "Sengketa telah diselesaikan", Body: "Admin telah menyelesaikan sengketa pada pesanan Anda.", WithMessage: true},
+		LanguageEN: {Subject: "Dispute resolved", Heading: "Dispute resolved", Body: "An admin has resolved the dispute on your order.", WithMessage: true},
+	},
+}
+
+// NotificationEmailCopy returns the content of the notification type in the language, the type is a ConsumerNotificationType
+// or a ServiceProviderNotificationType depending on the role of the recipient. Indonesian is used for an unknown language
+func NotificationEmailCopy(role UserRole, notificationType int16, language Language) (EmailNotificationCopy, bool) {
+	var copies map[Language]EmailNotificationCopy
+	switch role {
+	case UserRoleConsumer:
+		copies = consumerNotificationEmailCopies[ConsumerNotificationType(notificationType)]
+	case UserRoleServiceProvider:
+		copies = serviceProviderNotificationEmailCopies[ServiceProviderNotificationType(notificationType)]
+	}
+
+	if res, ok := copies[language]; ok {
+		return res, true
+	}
+
+	res, ok := copies[LanguageID]
+
+	return res, ok
+}
+
+// EmailAuthCopy is the localized content of an email sent by the auth flow, Button links to the page that takes the token
+type EmailAuthCopy struct {
+	Subject string
+	Heading string
+	Body    string
+	Button  string
+	Footer  string
+}
+
+var EmailAuthCopies = map[AuthTokenPurpose]map[Language]EmailAuthCopy{
+	AuthTokenPurposeEmailVerification: {
+		LanguageID: {
+			Subject: "Verifikasi email Anda",
+			Heading: "Verifikasi email Anda",
+			Body:    "Terima kasih telah mendaftar di Kelarin. Verifikasi email Anda untuk mulai menggunakan akun Anda.",
+			Button:  "Verifikasi Email",
+			Footer:  "Jika Anda tidak mendaftar di Kelarin, abaikan email ini.",
+		},
+		LanguageEN: {
+			Subject: "Verify your email",
+			Heading: "Verify your email",
+			Body:    "Thank you for signing up to Kelarin. Verify your email to start using your account.",
+			Button:  "Verify Email",
+			Footer:  "If you did not sign up to Kelarin, you can ignore this email.",
+		},
+	},
+	AuthTokenPurposePasswordReset: {
+		LanguageID: {
+			Subject: "Atur ulang kata sandi Anda",
+			Heading: "Atur ulang kata sandi Anda",
+			Body:    "Kami menerima permintaan untuk mengatur ulang kata sandi akun Kelarin Anda. Anda akan keluar dari semua perangkat setelah kata sandi diubah.",
+			Button:  "Atur Ulang Kata Sandi",
+			Footer:  "Jika Anda tidak meminta pengaturan ulang kata sandi, abaikan email ini. Kata sandi Anda tidak akan berubah.",
+		},
+		LanguageEN: {
+			Subject: "Reset your password",
+			Heading: "Reset your password",
+			Body:    "We received a request to reset the password of your Kelarin account. You will be logged out of every device once the password is changed.",
+			Button:  "Reset Password",
+			Footer:  "If you did not request a password reset, you can ignore this email. Your password will not change.",
+		},
+	},
+}
+
+// EmailAuthPaths are the pages of the app that take the token from the query string
+var EmailAuthPaths = map[AuthTokenPurpose]string{
+	AuthTokenPurposeEmailVerification: "/verify-email",
+	AuthTokenPurposePasswordReset:     "/reset-password",
+}
+
+// EmailNotificationTemplateData is rendered by the html and the text layout of the notification email
+type EmailNotificationTemplateData struct {
+	Language Language
+	Greeting string
+	Heading  string
+	Body     string
+	Message  string
+	Button   string
+	URL      string
+	Footer   string
+}
+
+// endregion repo types
+
+// region service types
+
+type NotificationSendEmailReq struct {
+	UserID           uuid.UUID
+	NotificationType int16
+	Message          string
+}
+
+type NotificationSendAuthEmailReq struct {
+	UserID  uuid.UUID
+	Purpose AuthTokenPurpose
+	Token   string
+}
+
+// endregion service types
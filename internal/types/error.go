@@ -7,6 +7,7 @@ import (
 )
 
 var ErrNoData = errors.New("no data")
+var ErrDuplicateData = errors.New("duplicate data")
 var ErrIDRouteParamRequired = errors.New(AppErr{Code: http.StatusBadRequest, Message: "id param is required"})
 
 var ErrMustBeSlice = errors.New("must be slice")
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/volatiletech/null/v9"
 )
 
 const TempFileDir = "temp"
@@ -34,6 +35,22 @@ type FileUploadFilesRes struct {
 
 type FileGetTempRes TempFile
 
+type FileUploadChatAttachmentReq struct {
+	File *multipart.FileHeader
+}
+
+// FileUploadChatAttachmentRes has the content type of the chat message the file key can be sent with
+type FileUploadChatAttachmentRes struct {
+	FileKey     string                 `json:"file_key"`
+	ContentType ChatMessageContentType `json:"content_type"`
+}
+
+// FileChatAttachment has the object keys of a chat attachment moved to s3, only an image has a thumbnail
+type FileChatAttachment struct {
+	ObjectKey    string
+	ThumbnailKey null.String
+}
+
 type FilePresignedURLClaims struct {
 	jwt.RegisteredClaims
 	FileName string `json:"file_name"`
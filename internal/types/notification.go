@@ -2,16 +2,58 @@ package types
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/go-errors/errors"
 	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
 	"github.com/google/uuid"
+	"github.com/volatiletech/null/v9"
 )
 
 // region repo types
 
-func FCMTokenKey(userID uuid.UUID) string {
-	return fmt.Sprintf("fcm-token:%s", userID)
+// 6480 hours = 9 months
+const FCMTokenExpiration = time.Hour * 6480
+
+// FCMTokensKey returns the key of the hash holding the device token of every session of the user
+func FCMTokensKey(userID uuid.UUID) string {
+	return fmt.Sprintf("fcm-tokens:%s", userID)
+}
+
+type FCMToken struct {
+	SessionID uuid.UUID        `json:"session_id"`
+	Token     string           `json:"token"`
+	Platform  FCMTokenPlatform `json:"platform"`
+	Topics    []string         `json:"topics"`
+	UpdatedAt time.Time        `json:"updated_at"`
+}
+
+type FCMTokenPlatform string
+
+const (
+	FCMTokenPlatformWeb     FCMTokenPlatform = "web"
+	FCMTokenPlatformAndroid FCMTokenPlatform = "android"
+	FCMTokenPlatformIOS     FCMTokenPlatform = "ios"
+)
+
+type NotificationTopic string
+
+const (
+	NotificationTopicConsumers NotificationTopic = "consumers"
+	NotificationTopicProviders NotificationTopic = "providers"
+	NotificationTopicCity      NotificationTopic = "city"
+)
+
+// NotificationCityTopic is subscribed by the service providers whose service area is in the city
+func NotificationCityTopic(cityID int64) string {
+	return fmt.Sprintf("%s-%d", NotificationTopicCity, cityID)
+}
+
+// NotificationCursorFilter pages through the notifications from the newest one, After is the id of the last notification of the previous page
+type NotificationCursorFilter struct {
+	After uuid.NullUUID
+	Limit int
 }
 
 // endregion repo types
@@ -19,17 +61,20 @@ func FCMTokenKey(userID uuid.UUID) string {
 // region service types
 
 type NotificationSendReq struct {
-	Title    string
-	Message  string
-	IconURL  string
-	BadgeURL string
-	ImageURL string
-	Token    string
+	NotificationType int16 // ConsumerNotificationType or ServiceProviderNotificationType of the recipient, optional
+	Title            string
+	Message          string
+	IconURL          string
+	BadgeURL         string
+	ImageURL         string
+	Token            string
+	Platform         FCMTokenPlatform
 }
 
 type NotificationSaveTokenReq struct {
-	AuthUser AuthUser `middleware:"user"`
-	Token    string   `json:"token"`
+	AuthUser AuthUser         `middleware:"user"`
+	Token    string           `json:"token"`
+	Platform FCMTokenPlatform `json:"platform"`
 }
 
 func (r NotificationSaveTokenReq) Validate() error {
@@ -37,7 +82,91 @@ func (r NotificationSaveTokenReq) Validate() error {
 		return errors.New("AuthUser is required")
 	}
 
-	return validation.ValidateStruct(&r, validation.Field(&r.Token, validation.Required))
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.Token, validation.Required),
+		validation.Field(&r.Platform, validation.In(FCMTokenPlatformWeb, FCMTokenPlatformAndroid, FCMTokenPlatformIOS)),
+	)
+}
+
+type NotificationAdminBroadcastReq struct {
+	AuthUser AuthUser          `middleware:"user"`
+	Topic    NotificationTopic `json:"topic"`
+	CityID   null.Int64        `json:"city_id"`
+	Title    string            `json:"title"`
+	Message  string            `json:"message"`
+	ImageURL string            `json:"image_url"`
+}
+
+func (r NotificationAdminBroadcastReq) Validate() error {
+	if r.AuthUser.IsZero() {
+		return errors.New("AuthUser is required")
+	}
+
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.Topic, validation.Required, validation.In(NotificationTopicConsumers, NotificationTopicProviders, NotificationTopicCity)),
+		validation.Field(&r.CityID, validation.When(r.Topic == NotificationTopicCity, validation.By(func(value interface{}) error {
+			if !r.CityID.Valid {
+				return validation.NewError("city_id_required", "city_id is required for the city topic")
+			}
+
+			return nil
+		}))),
+		validation.Field(&r.Title, validation.Required, validation.Length(1, 100)),
+		validation.Field(&r.Message, validation.Required, validation.Length(1, 500)),
+		validation.Field(&r.ImageURL, is.URL),
+	)
+}
+
+// TopicName returns the firebase topic the broadcast is sent to
+func (r NotificationAdminBroadcastReq) TopicName() string {
+	if r.Topic == NotificationTopicCity {
+		return NotificationCityTopic(r.CityID.Int64)
+	}
+
+	return string(r.Topic)
+}
+
+type NotificationMarkAsReadReq struct {
+	AuthUser AuthUser   `middleware:"user"`
+	IDs      uuid.UUIDs `json:"ids"`
+}
+
+func (r NotificationMarkAsReadReq) Validate() error {
+	if r.AuthUser.IsZero() {
+		return errors.New("AuthUser is required")
+	}
+
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.IDs, validation.Required, validation.Length(1, CursorPaginationMaxSize)),
+	)
+}
+
+type NotificationMarkAllAsReadReq struct {
+	AuthUser AuthUser `middleware:"user"`
+}
+
+func (r NotificationMarkAllAsReadReq) Validate() error {
+	if r.AuthUser.IsZero() {
+		return errors.New("AuthUser is required")
+	}
+
+	return nil
+}
+
+type NotificationUnreadCountReq struct {
+	AuthUser AuthUser `middleware:"user"`
+}
+
+func (r NotificationUnreadCountReq) Validate() error {
+	if r.AuthUser.IsZero() {
+		return errors.New("AuthUser is required")
+	}
+
+	return nil
+}
+
+type NotificationUnreadCountRes struct {
+	Count int64 `json:"count"`
 }
 
 // endregion service types
@@ -0,0 +1,205 @@
+package types
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/go-errors/errors"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/google/uuid"
+)
+
+// region repo types
+
+type NotificationChannel string
+
+const (
+	NotificationChannelPush  NotificationChannel = "push"
+	NotificationChannelInApp NotificationChannel = "in_app"
+	NotificationChannelEmail NotificationChannel = "email"
+)
+
+var NotificationChannels = []NotificationChannel{
+	NotificationChannelPush,
+	NotificationChannelInApp,
+	NotificationChannelEmail,
+}
+
+// NotificationPreference is only stored once the user changes it, every type is enabled on every channel by default.
+// Type is a ConsumerNotificationType or a ServiceProviderNotificationType depending on the role of the user
+type NotificationPreference struct {
+	UserID    uuid.UUID           `db:"user_id"`
+	Type      int16               `db:"type"`
+	Channel   NotificationChannel `db:"channel"`
+	Enabled   bool                `db:"enabled"`
+	UpdatedAt time.Time           `db:"updated_at"`
+}
+
+type NotificationQuietHours struct {
+	UserID    uuid.UUID `db:"user_id"`
+	Enabled   bool      `db:"enabled"`
+	StartTime time.Time `db:"start_time"` // time of day in TimeZone
+	EndTime   time.Time `db:"end_time"`   // before StartTime when the quiet hours end after midnight
+	TimeZone  string    `db:"time_zone"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+// EndAfter reports whether t is inside the quiet hours and returns when they end, the time of day is compared in the time zone of the user
+func (q NotificationQuietHours) EndAfter(t time.Time) (time.Time, bool) {
+	if !q.Enabled {
+		return time.Time{}, false
+	}
+
+	loc, err := time.LoadLocation(q.TimeZone)
+	if err != nil {
+		loc, _ = time.LoadLocation(AppTimeZone)
+	}
+
+	t = t.In(loc)
+	year, month, day := t.Date()
+	start := time.Date(year, month, day, q.StartTime.Hour(), q.StartTime.Minute(), q.StartTime.Second(), 0, loc)
+	end := time.Date(year, month, day, q.EndTime.Hour(), q.EndTime.Minute(), q.EndTime.Second(), 0, loc)
+
+	if end.After(start) {
+		return end, !t.Before(start) && t.Before(end)
+	}
+
+	// the quiet hours started yesterday or end tomorrow
+	if t.Before(end) {
+		return end, true
+	} else if !t.Before(start) {
+		return end.AddDate(0, 0, 1), true
+	}
+
+	return time.Time{}, false
+}
+
+// NotificationTypesByRole returns the notification types the role can set a preference for
+func NotificationTypesByRole(role UserRole) []int16 {
+	res := []int16{}
+
+	switch role {
+	case UserRoleConsumer:
+		for _, t := range ConsumerNotificationTypes {
+			res = append(res, int16(t))
+		}
+	case UserRoleServiceProvider:
+		for _, t := range ServiceProviderNotificationTypes {
+			res = append(res, int16(t))
+		}
+	}
+
+	return res
+}
+
+// endregion repo types
+
+// region service types
+
+type NotificationPreferenceGetReq struct {
+	AuthUser AuthUser `middleware:"user"`
+}
+
+func (r NotificationPreferenceGetReq) Validate() error {
+	if r.AuthUser.IsZero() {
+		return errors.New("AuthUser is required")
+	}
+
+	return nil
+}
+
+type NotificationPreferenceRes struct {
+	Language    Language                     `json:"language"`
+	Preferences []NotificationPreferenceItem `json:"preferences"`
+	QuietHours  NotificationQuietHoursRes    `json:"quiet_hours"`
+}
+
+type NotificationPreferenceItem struct {
+	Type    int16               `json:"type"`
+	Channel NotificationChannel `json:"channel"`
+	Enabled bool                `json:"enabled"`
+}
+
+type NotificationQuietHoursRes struct {
+	Enabled   bool   `json:"enabled"`
+	StartTime string `json:"start_time"`
+	EndTime   string `json:"end_time"`
+	TimeZone  string `json:"time_zone"`
+}
+
+type NotificationPreferenceUpdateReq struct {
+	AuthUser    AuthUser                     `middleware:"user"`
+	Preferences []NotificationPreferenceItem `json:"preferences"`
+}
+
+func (r NotificationPreferenceUpdateReq) Validate() error {
+	if r.AuthUser.IsZero() {
+		return errors.New("AuthUser is required")
+	}
+
+	notificationTypes := []any{}
+	for _, t := range NotificationTypesByRole(r.AuthUser.Role) {
+		notificationTypes = append(notificationTypes, t)
+	}
+
+	err := validation.ValidateStruct(&r,
+		validation.Field(&r.Preferences, validation.Required, validation.Length(1, len(notificationTypes)*len(NotificationChannels))),
+	)
+	if err != nil {
+		return err
+	}
+
+	ve := validation.Errors{}
+	for i, item := range r.Preferences {
+		err := validation.ValidateStruct(&item,
+			validation.Field(&item.Type, validation.Required, validation.In(notificationTypes...)),
+			validation.Field(&item.Channel, validation.Required, validation.In(NotificationChannelPush, NotificationChannelInApp, NotificationChannelEmail)),
+		)
+		if err != nil {
+			ve[fmt.Sprintf("preferences.%d", i)] = err
+		}
+	}
+
+	if len(ve) > 0 {
+		return ve
+	}
+
+	return nil
+}
+
+type NotificationQuietHoursUpdateReq struct {
+	AuthUser  AuthUser `middleware:"user"`
+	TimeZone  string   `header:"Time-Zone"`
+	Enabled   bool     `json:"enabled"`
+	StartTime string   `json:"start_time"`
+	EndTime   string   `json:"end_time"`
+}
+
+func (r NotificationQuietHoursUpdateReq) Validate() error {
+	if r.AuthUser.IsZero() {
+		return errors.New("AuthUser is required")
+	}
+
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.StartTime, validation.Required, validation.Date(time.TimeOnly)),
+		validation.Field(&r.EndTime, validation.Required, validation.Date(time.TimeOnly), validation.NotIn(r.StartTime).Error("must be different from start_time")),
+	)
+}
+
+// NotificationLanguageUpdateReq sets the language of the notification emails
+type NotificationLanguageUpdateReq struct {
+	AuthUser AuthUser `middleware:"user"`
+	Language Language `json:"language"`
+}
+
+func (r NotificationLanguageUpdateReq) Validate() error {
+	if r.AuthUser.IsZero() {
+		return errors.New("AuthUser is required")
+	}
+
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.Language, validation.Required, validation.In(LanguageID, LanguageEN)),
+	)
+}
+
+// endregion service types
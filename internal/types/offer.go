@@ -16,18 +16,61 @@ import (
 // region repo types
 
 type Offer struct {
-	ID               uuid.UUID       `db:"id"`
-	UserID           uuid.UUID       `db:"user_id"`
-	UserAddressID    uuid.UUID       `db:"user_address_id"`
-	ServiceID        uuid.UUID       `db:"service_id"`
-	Detail           string          `db:"detail"`
-	ServiceCost      decimal.Decimal `db:"service_cost"`
-	ServiceStartDate time.Time       `db:"service_start_date"`
-	ServiceEndDate   time.Time       `db:"service_end_date"`
-	ServiceStartTime time.Time       `db:"service_start_time"`
-	ServiceEndTime   time.Time       `db:"service_end_time"`
-	Status           OfferStatus     `db:"status"`
-	CreatedAt        time.Time       `db:"created_at"`
+	ID               uuid.UUID            `db:"id"`
+	UserID           uuid.UUID            `db:"user_id"`
+	UserAddressID    uuid.UUID            `db:"user_address_id"`
+	ServiceID        uuid.UUID            `db:"service_id"`
+	Detail           string               `db:"detail"`
+	ServiceCost      decimal.Decimal      `db:"service_cost"` // price of the whole schedule, a recurring offer is paid once for all of its sessions
+	ServiceStartDate time.Time            `db:"service_start_date"`
+	ServiceEndDate   time.Time            `db:"service_end_date"`
+	ServiceStartTime time.Time            `db:"service_start_time"`
+	ServiceEndTime   time.Time            `db:"service_end_time"`
+	ServiceSchedule  OfferServiceSchedule `db:"service_schedule"`
+	Status           OfferStatus          `db:"status"`
+	CreatedAt        time.Time            `db:"created_at"`
+}
+
+// SessionDates returns the date of every order session the offer produces. A single schedule has no fixed date,
+// the service provider picks one between the start and end date when accepting the offer
+func (o Offer) SessionDates() []time.Time {
+	return o.ServiceSchedule.SessionDates(o.ServiceStartDate, o.ServiceEndDate)
+}
+
+type OfferServiceSchedule string
+
+const (
+	OfferServiceScheduleSingle OfferServiceSchedule = "single"
+	OfferServiceScheduleDaily  OfferServiceSchedule = "daily"
+	OfferServiceScheduleWeekly OfferServiceSchedule = "weekly"
+)
+
+// OfferMaxSessions limits how many order sessions a recurring offer may produce
+const OfferMaxSessions = 60
+
+func (s OfferServiceSchedule) IsRecurring() bool {
+	return s == OfferServiceScheduleDaily || s == OfferServiceScheduleWeekly
+}
+
+// SessionDates lists the session dates between startDate and endDate, a weekly schedule repeats on the weekday of startDate
+func (s OfferServiceSchedule) SessionDates(startDate, endDate time.Time) []time.Time {
+	res := []time.Time{}
+
+	step := 0
+	switch s {
+	case OfferServiceScheduleDaily:
+		step = 1
+	case OfferServiceScheduleWeekly:
+		step = 7
+	default:
+		return res
+	}
+
+	for date := startDate; !date.After(endDate); date = date.AddDate(0, 0, step) {
+		res = append(res, date)
+	}
+
+	return res
 }
 
 type OfferStatus string
@@ -69,6 +112,7 @@ type OfferConsumerCreateReq struct {
 	ServiceEndDate   string    `json:"service_end_date"`
 	ServiceStartTime string    `json:"service_start_time"`
 	ServiceEndTime   string    `json:"service_end_time"`
+	ServiceSchedule  string    `json:"service_schedule"`
 }
 
 func (r OfferConsumerCreateReq) Validate() error {
@@ -85,6 +129,11 @@ func (r OfferConsumerCreateReq) Validate() error {
 		validation.Field(&r.ServiceEndDate, validation.Required, validation.Date(time.DateOnly)),
 		validation.Field(&r.ServiceStartTime, validation.Required, validation.Date(time.TimeOnly)),
 		validation.Field(&r.ServiceEndTime, validation.Required, validation.Date(time.TimeOnly)),
+		validation.Field(&r.ServiceSchedule, validation.In(
+			string(OfferServiceScheduleSingle),
+			string(OfferServiceScheduleDaily),
+			string(OfferServiceScheduleWeekly),
+		)),
 	)
 
 	if err != nil {
@@ -110,6 +159,8 @@ func (r OfferConsumerCreateReq) Validate() error {
 
 	if endDate.Before(startDate) {
 		ve["service_end_date"] = validation.NewError("service_end_date_min", "service_end_date must be equal or greater than service_start_date")
+	} else if len(r.Schedule().SessionDates(startDate, endDate)) > OfferMaxSessions {
+		ve["service_end_date"] = validation.NewError("service_end_date_max", fmt.Sprintf("service schedule must not exceed %d sessions", OfferMaxSessions))
 	}
 
 	if len(ve) > 0 {
@@ -119,7 +170,32 @@ func (r OfferConsumerCreateReq) Validate() error {
 	return nil
 }
 
-func (r OfferConsumerCreateReq) ValidateDateTimeAndServiceFee(userTz *time.Location, serviceFeeStartAt decimal.Decimal) error {
+func (r OfferConsumerCreateReq) isScheduleAvailable(availability ServiceAvailability, startDate, endDate, startTime, endTime time.Time) bool {
+	if r.Schedule().IsRecurring() {
+		return availability.HasCommonFreeSlot(r.Schedule().SessionDates(startDate, endDate), startTime, endTime)
+	}
+
+	for date := startDate; !date.After(endDate); date = date.AddDate(0, 0, 1) {
+		if availability.HasCommonFreeSlot([]time.Time{date}, startTime, endTime) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Schedule defaults an empty service_schedule to a single session
+func (r OfferConsumerCreateReq) Schedule() OfferServiceSchedule {
+	if r.ServiceSchedule == "" {
+		return OfferServiceScheduleSingle
+	}
+
+	return OfferServiceSchedule(r.ServiceSchedule)
+}
+
+// ValidateDateTimeAndServiceFee also rejects a schedule the service can not take: a single offer needs a free slot
+// on one of its dates, a recurring offer needs one time of day that is free on every session date
+func (r OfferConsumerCreateReq) ValidateDateTimeAndServiceFee(userTz *time.Location, serviceFeeStartAt decimal.Decimal, availability ServiceAvailability) error {
 	ve := validation.Errors{}
 
 	nowUTC := utils.DateNowInUTC()
@@ -169,6 +245,10 @@ func (r OfferConsumerCreateReq) ValidateDateTimeAndServiceFee(userTz *time.Locat
 		ve["service_cost"] = validation.NewError("service_cost_min", fmt.Sprintf("service_cost must be greater or equal than %s", serviceFeeStartAt))
 	}
 
+	if len(ve) == 0 && !r.isScheduleAvailable(availability, startDate, endDate, startTime, endTime) {
+		ve["service_start_date"] = validation.NewError("service_schedule_unavailable", "the service provider is not available or fully booked on the requested schedule")
+	}
+
 	if len(ve) > 0 {
 		return ve
 	}
@@ -197,6 +277,7 @@ type OfferConsumerGetAllRes struct {
 	ServiceStartTime      string                                `json:"service_start_time"`
 	ServiceEndTime        string                                `json:"service_end_time"`
 	ServiceTimeTimeZone   string                                `json:"service_time_time_zone"`
+	ServiceSchedule       OfferServiceSchedule                  `json:"service_schedule"`
 	Status                OfferStatus                           `json:"status"`
 	HasPendingNegotiation bool                                  `json:"has_pending_negotiation"`
 	CreatedAt             time.Time                             `json:"created_at"`
@@ -243,6 +324,7 @@ type OfferConsumerGetByIDRes struct {
 	ServiceStartTime      string                                 `json:"service_start_time"`
 	ServiceEndTime        string                                 `json:"service_end_time"`
 	ServiceTimeTimeZone   string                                 `json:"service_time_time_zone"`
+	ServiceSchedule       OfferServiceSchedule                   `json:"service_schedule"`
 	Status                OfferStatus                            `json:"status"`
 	HasPendingNegotiation bool                                   `json:"has_pending_negotiation"`
 	CreatedAt             time.Time                              `json:"created_at"`
@@ -310,10 +392,7 @@ func (r OfferProviderActionReq) Validate() error {
 
 	return validation.ValidateStruct(&r,
 		validation.Field(&r.Action, validation.Required, validation.In(OfferProviderActionReqActionAccept, OfferProviderActionReqActionReject)),
-		validation.Field(&r.Date,
-			validation.Required.When(r.Action == OfferProviderActionReqActionAccept),
-			validation.Date(time.DateOnly),
-		),
+		validation.Field(&r.Date, validation.Date(time.DateOnly)),
 		validation.Field(&r.Time,
 			validation.Required.When(r.Action == OfferProviderActionReqActionAccept),
 			validation.Date(time.TimeOnly),
@@ -321,10 +400,19 @@ func (r OfferProviderActionReq) Validate() error {
 	)
 }
 
-func (r OfferProviderActionReq) ValidateDateAndTime(startDate, endDate time.Time, startTime, endTime string) error {
+// ValidateDateAndTime checks the date and time picked by the service provider against the offer.
+// A recurring schedule has its dates fixed by the offer, so only the time is picked and the date is ignored
+func (r OfferProviderActionReq) ValidateDateAndTime(schedule OfferServiceSchedule, startDate, endDate time.Time, startTime, endTime string) error {
 	ve := validation.Errors{}
 
 	if r.Action == OfferProviderActionReqActionAccept {
+		if schedule.IsRecurring() {
+			r.Date = startDate.Format(time.DateOnly)
+		} else if r.Date == "" {
+			ve["date"] = validation.ErrRequired
+			return ve
+		}
+
 		t, err := utils.IsDateBetween(r.Date, startDate, endDate, time.DateOnly)
 		if err != nil {
 			return err
@@ -404,15 +492,16 @@ func (r OfferProviderGetAllReq) Validate() error {
 }
 
 type OfferProviderGetAllRes struct {
-	ID               uuid.UUID       `json:"id"`
-	Detail           string          `json:"detail"`
-	ServiceCost      decimal.Decimal `json:"service_cost"`
-	ServiceStartDate string          `json:"service_start_date"`
-	ServiceEndDate   string          `json:"service_end_date"`
-	ServiceStartTime string          `json:"service_start_time"`
-	ServiceEndTime   string          `json:"service_end_time"`
-	Status           OfferStatus     `json:"status"`
-	CreatedAt        time.Time       `json:"created_at"`
+	ID               uuid.UUID            `json:"id"`
+	Detail           string               `json:"detail"`
+	ServiceCost      decimal.Decimal      `json:"service_cost"`
+	ServiceStartDate string               `json:"service_start_date"`
+	ServiceEndDate   string               `json:"service_end_date"`
+	ServiceStartTime string               `json:"service_start_time"`
+	ServiceEndTime   string               `json:"service_end_time"`
+	ServiceSchedule  OfferServiceSchedule `json:"service_schedule"`
+	Status           OfferStatus          `json:"status"`
+	CreatedAt        time.Time            `json:"created_at"`
 }
 
 type OfferProviderGetByIDReq struct {
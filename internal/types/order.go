@@ -15,18 +15,35 @@ import (
 // region repo types
 
 type Order struct {
-	ID                uuid.UUID       `db:"id"`
-	UserID            uuid.UUID       `db:"user_id"`
-	ServiceProviderID uuid.UUID       `db:"service_provider_id"`
-	OfferID           uuid.UUID       `db:"offer_id"`
-	PaymentID         uuid.NullUUID   `db:"payment_id"`
-	PaymentFulfilled  bool            `db:"payment_fulfilled"`
-	ServiceFee        decimal.Decimal `db:"service_fee"`
-	ServiceDate       time.Time       `db:"service_date"`
-	ServiceTime       time.Time       `db:"service_time"`
-	Status            OrderStatus     `db:"status"`
-	CreatedAt         time.Time       `db:"created_at"`
-	UpdatedAt         null.Time       `db:"updated_at"`
+	ID                 uuid.UUID       `db:"id"`
+	UserID             uuid.UUID       `db:"user_id"`
+	ServiceProviderID  uuid.UUID       `db:"service_provider_id"`
+	OfferID            uuid.UUID       `db:"offer_id"`
+	PaymentID          uuid.NullUUID   `db:"payment_id"`
+	PaymentFulfilled   bool            `db:"payment_fulfilled"`
+	ServiceFee         decimal.Decimal `db:"service_fee"`
+	ServiceDate        time.Time       `db:"service_date"`
+	ServiceTime        time.Time       `db:"service_time"`
+	Status             OrderStatus     `db:"status"`
+	CanceledBy         null.Int16      `db:"canceled_by"` // role of the user who canceled the order
+	CancellationReason null.String     `db:"cancellation_reason"`
+	CanceledAt         null.Time       `db:"canceled_at"`
+	CreatedAt          time.Time       `db:"created_at"`
+	UpdatedAt          null.Time       `db:"updated_at"`
+}
+
+// ServiceStartAt combines the service date and the service time into the moment the service starts
+func (o Order) ServiceStartAt() time.Time {
+	return time.Date(
+		o.ServiceDate.Year(), o.ServiceDate.Month(), o.ServiceDate.Day(),
+		o.ServiceTime.Hour(), o.ServiceTime.Minute(), o.ServiceTime.Second(), 0,
+		o.ServiceTime.Location(),
+	)
+}
+
+// IsCancelable reports whether the order has not reached a final status yet
+func (o Order) IsCancelable() bool {
+	return o.Status == OrderStatusPending || o.Status == OrderStatusOngoing
 }
 
 type OrderStatus string
@@ -36,6 +53,7 @@ const (
 	OrderStatusOngoing  OrderStatus = "ongoing"
 	OrderStatusFinished OrderStatus = "finished"
 	OrderStatusExpired  OrderStatus = "expired"
+	OrderStatusCanceled OrderStatus = "canceled"
 )
 
 type OrderWithRelations struct {
@@ -103,7 +121,7 @@ type OrderCreateReq struct {
 	ServiceDeliveryMethods DeliveryMethods
 	ServiceRules           []ServiceRule
 	ServiceDescription     string
-	ServiceDate            time.Time
+	ServiceDate            time.Time // only used by a single schedule offer, a recurring offer takes its session dates from the offer
 	ServiceTime            time.Time
 	Tx                     dbUtil.Tx
 }
@@ -222,6 +240,8 @@ type ConsumerOrderGetByIDRes struct {
 	Service          ConsumerOrderGetByIDResOfferService `json:"service"`
 	Address          ConsumerOrderGetByIDResOfferAddress `json:"address"`
 	Payment          *OrderConsumerGetByIDResPayment     `json:"payment"`
+	Progress         OrderProgressRes                    `json:"progress"`
+	Sessions         []OrderSessionRes                   `json:"sessions"`
 }
 
 type ConsumerOrderGetByIDResOffer struct {
@@ -296,6 +316,7 @@ type OrderConsumerGenerateQRCodeRes struct {
 type OrderConsumerGenerateQRCodePayload struct {
 	jwt.RegisteredClaims
 	OrderID     uuid.UUID       `json:"order_id"`
+	SessionID   uuid.UUID       `json:"session_id"`
 	Amount      decimal.Decimal `json:"amount"`
 	AdminFee    int32           `json:"admin_fee"`
 	PlatformFee int32           `json:"platform_fee"`
@@ -366,6 +387,8 @@ type OrderProviderGetByIDRes struct {
 	Offer            OrderProviderGetByIDResOffer   `json:"offer"`
 	Address          OrderProviderGetByIDResAddress `json:"address"`
 	Payment          *OrderProviderGetAllResPayment `json:"payment"`
+	Progress         OrderProgressRes               `json:"progress"`
+	Sessions         []OrderSessionRes              `json:"sessions"`
 }
 
 type OrderProviderGetByIDResOffer struct {
@@ -401,4 +424,37 @@ func (r OrderProviderValidateQRCodeReq) Validate() error {
 	)
 }
 
+const (
+	// OrderCancellationFullRefundWindow is how long before the service starts a consumer can still cancel with a full refund of the service fee
+	OrderCancellationFullRefundWindow = 24 * time.Hour
+	// OrderCancellationLateRefundPercentage is the part of the service fee refunded when a consumer cancels inside the full refund window,
+	// the rest is given to the service provider as credit
+	OrderCancellationLateRefundPercentage = 50
+)
+
+type OrderCancelReq struct {
+	AuthUser AuthUser  `middleware:"user"`
+	ID       uuid.UUID `param:"id"`
+	Reason   string    `json:"reason"`
+}
+
+func (r OrderCancelReq) Validate() error {
+	if r.AuthUser.IsZero() {
+		return errors.New("AuthUser is required")
+	}
+
+	if r.ID == uuid.Nil {
+		return errors.New(ErrIDRouteParamRequired)
+	}
+
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.Reason, validation.Required, validation.Length(1, 1000)),
+	)
+}
+
+type OrderCancelRes struct {
+	RefundAmount decimal.Decimal `json:"refund_amount"`
+	RefundStatus RefundStatus    `json:"refund_status,omitempty"`
+}
+
 // endregion service types
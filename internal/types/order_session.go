@@ -0,0 +1,90 @@
+package types
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/volatiletech/null/v9"
+)
+
+// region repo types
+
+type OrderSession struct {
+	ID          uuid.UUID          `db:"id"`
+	OrderID     uuid.UUID          `db:"order_id"`
+	Sequence    int16              `db:"sequence"`
+	ServiceDate time.Time          `db:"service_date"`
+	ServiceTime time.Time          `db:"service_time"`
+	Status      OrderSessionStatus `db:"status"`
+	FinishedAt  null.Time          `db:"finished_at"`
+	CreatedAt   time.Time          `db:"created_at"`
+	UpdatedAt   null.Time          `db:"updated_at"`
+}
+
+// ServiceStartAt combines the service date and the service time into the moment the session starts
+func (s OrderSession) ServiceStartAt() time.Time {
+	return time.Date(
+		s.ServiceDate.Year(), s.ServiceDate.Month(), s.ServiceDate.Day(),
+		s.ServiceTime.Hour(), s.ServiceTime.Minute(), s.ServiceTime.Second(), 0,
+		s.ServiceTime.Location(),
+	)
+}
+
+// IsDone reports whether the session has reached a final status
+func (s OrderSession) IsDone() bool {
+	return s.Status == OrderSessionStatusFinished || s.Status == OrderSessionStatusCanceled
+}
+
+type OrderSessionStatus string
+
+const (
+	OrderSessionStatusPending  OrderSessionStatus = "pending"
+	OrderSessionStatusOngoing  OrderSessionStatus = "ongoing"
+	OrderSessionStatusFinished OrderSessionStatus = "finished"
+	OrderSessionStatusCanceled OrderSessionStatus = "canceled"
+)
+
+type OrderSessions []OrderSession
+
+// CountByStatus counts the sessions with the given status
+func (s OrderSessions) CountByStatus(status OrderSessionStatus) int {
+	count := 0
+	for _, session := range s {
+		if session.Status == status {
+			count++
+		}
+	}
+
+	return count
+}
+
+// NextUndone returns the earliest session that has not been finished or canceled
+func (s OrderSessions) NextUndone() (OrderSession, bool) {
+	for _, session := range s {
+		if !session.IsDone() {
+			return session, true
+		}
+	}
+
+	return OrderSession{}, false
+}
+
+// endregion repo types
+
+// region service types
+
+type OrderSessionRes struct {
+	ID          uuid.UUID          `json:"id"`
+	Sequence    int16              `json:"sequence"`
+	ServiceDate string             `json:"service_date"`
+	ServiceTime string             `json:"service_time"`
+	Status      OrderSessionStatus `json:"status"`
+	FinishedAt  null.Time          `json:"finished_at"`
+}
+
+type OrderProgressRes struct {
+	FinishedSessions int `json:"finished_sessions"`
+	TotalSessions    int `json:"total_sessions"`
+}
+
+// endregion service types
@@ -0,0 +1,101 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/go-errors/errors"
+	"github.com/google/uuid"
+	"github.com/volatiletech/null/v9"
+)
+
+// region repo types
+
+// OutboxMaxAttempts is how many times an event is processed before it is marked as dead
+const OutboxMaxAttempts = 5
+
+// OutboxPendingGracePeriod is how long an event stays pending before the sweeper enqueues it again,
+// it covers events whose task failed to be enqueued after the transaction was committed
+const OutboxPendingGracePeriod = time.Minute
+
+const OutboxSweepLimit = 500
+
+type OutboxEvent struct {
+	ID          uuid.UUID         `db:"id"`
+	Type        OutboxEventType   `db:"type"`
+	Payload     []byte            `db:"payload"`
+	Status      OutboxEventStatus `db:"status"`
+	Attempts    int16             `db:"attempts"`
+	LastError   null.String       `db:"last_error"`
+	CreatedAt   time.Time         `db:"created_at"`
+	ProcessedAt null.Time         `db:"processed_at"`
+}
+
+type OutboxEventType string
+
+const (
+	OutboxEventTypePushNotification OutboxEventType = "push_notification"
+	OutboxEventTypeServiceIndexSync OutboxEventType = "service_index_sync"
+)
+
+type OutboxEventStatus string
+
+const (
+	OutboxEventStatusPending   OutboxEventStatus = "pending"
+	OutboxEventStatusProcessed OutboxEventStatus = "processed"
+	OutboxEventStatusDead      OutboxEventStatus = "dead"
+)
+
+// OutboxPushNotificationPayload is sent to the device token the user has when the event is processed
+type OutboxPushNotificationPayload struct {
+	UserID           uuid.UUID `json:"user_id"`
+	NotificationType int16     `json:"notification_type,omitempty"` // the preferences of the user are not checked when it is empty
+	Title            string    `json:"title"`
+	Message          string    `json:"message"`
+	ImageURL         string    `json:"image_url,omitempty"`
+}
+
+// OutboxServiceIndexSyncPayload rebuilds the service document from the database, a deleted service is removed from the index
+type OutboxServiceIndexSyncPayload struct {
+	ServiceID uuid.UUID `json:"service_id"`
+}
+
+func NewOutboxEvent(eventType OutboxEventType, payload any) (OutboxEvent, error) {
+	id, err := uuid.NewV7()
+	if err != nil {
+		return OutboxEvent{}, errors.New(err)
+	}
+
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		return OutboxEvent{}, errors.New(err)
+	}
+
+	return OutboxEvent{
+		ID:        id,
+		Type:      eventType,
+		Payload:   payloadBytes,
+		Status:    OutboxEventStatusPending,
+		CreatedAt: time.Now(),
+	}, nil
+}
+
+func NewOutboxPushNotification(userID uuid.UUID, notificationType int16, title, message string) (OutboxEvent, error) {
+	return NewOutboxEvent(OutboxEventTypePushNotification, OutboxPushNotificationPayload{
+		UserID:           userID,
+		NotificationType: notificationType,
+		Title:            title,
+		Message:          message,
+	})
+}
+
+func NewOutboxServiceIndexSync(serviceID uuid.UUID) (OutboxEvent, error) {
+	return NewOutboxEvent(OutboxEventTypeServiceIndexSync, OutboxServiceIndexSyncPayload{ServiceID: serviceID})
+}
+
+func OutboxTaskID(eventID uuid.UUID) string {
+	return fmt.Sprintf("outbox-event:%s", eventID)
+}
+
+// endregion repo types
@@ -2,10 +2,16 @@ package types
 
 import (
 	"fmt"
+
+	"github.com/google/uuid"
 )
 
 const (
-	TaskDeleteTempFile = "delete-temp-file"
+	TaskDeleteTempFile        = "delete-temp-file"
+	TaskProcessOutboxEvent    = "process-outbox-event"
+	TaskSendPushNotification  = "send-push-notification"
+	TaskSendEmailNotification = "send-email-notification"
+	TaskSendAuthEmail         = "send-auth-email"
 )
 
 type QueueDeleteTempFilePayload struct {
@@ -13,6 +19,31 @@ type QueueDeleteTempFilePayload struct {
 	FilePath string `json:"path"`
 }
 
+type QueueProcessOutboxEventPayload struct {
+	ID uuid.UUID `json:"id"`
+}
+
+// QueueSendPushNotificationPayload is a push held back until the quiet hours of the user end
+type QueueSendPushNotificationPayload struct {
+	UserID           uuid.UUID `json:"user_id"`
+	NotificationType int16     `json:"notification_type"`
+	Title            string    `json:"title"`
+	Message          string    `json:"message"`
+	ImageURL         string    `json:"image_url,omitempty"`
+}
+
+type QueueSendEmailNotificationPayload struct {
+	UserID           uuid.UUID `json:"user_id"`
+	NotificationType int16     `json:"notification_type"`
+	Message          string    `json:"message,omitempty"`
+}
+
+type QueueSendAuthEmailPayload struct {
+	UserID  uuid.UUID        `json:"user_id"`
+	Purpose AuthTokenPurpose `json:"purpose"`
+	Token   string           `json:"token"`
+}
+
 type QueuePriority string
 
 const (
@@ -0,0 +1,106 @@
+package types
+
+import (
+	"time"
+
+	"github.com/go-errors/errors"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/volatiletech/null/v9"
+)
+
+// region repo types
+
+type Refund struct {
+	ID             uuid.UUID       `db:"id"`
+	OrderID        uuid.UUID       `db:"order_id"`
+	PaymentID      uuid.UUID       `db:"payment_id"`
+	Amount         decimal.Decimal `db:"amount"`
+	Reason         string          `db:"reason"`
+	Status         RefundStatus    `db:"status"`
+	FailureMessage null.String     `db:"failure_message"`
+	CreatedAt      time.Time       `db:"created_at"`
+	UpdatedAt      null.Time       `db:"updated_at"`
+}
+
+type RefundStatus string
+
+const (
+	RefundStatusPending    RefundStatus = "pending"
+	RefundStatusProcessing RefundStatus = "processing" // sent to midtrans, the result has not been stored yet
+	RefundStatusSucceeded  RefundStatus = "succeeded"
+	RefundStatusFailed     RefundStatus = "failed"
+)
+
+type RefundWithOrder struct {
+	Refund
+	UserID              uuid.UUID `db:"user_id"`
+	UserName            string    `db:"user_name"`
+	ServiceProviderID   uuid.UUID `db:"service_provider_id"`
+	ServiceProviderName string    `db:"service_provider_name"`
+	PaymentReference    string    `db:"payment_reference"`
+}
+
+type RefundFilter struct {
+	Status RefundStatus
+	Limit  int
+	Offset int
+}
+
+// endregion repo types
+
+// region service types
+
+type RefundAdminGetAllReq struct {
+	AuthUser AuthUser     `middleware:"user"`
+	Status   RefundStatus `form:"status"`
+	PaginationReq
+}
+
+func (r RefundAdminGetAllReq) Validate() error {
+	if r.AuthUser.IsZero() {
+		return errors.New("AuthUser is required")
+	}
+
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.Status, validation.In(RefundStatusPending, RefundStatusProcessing, RefundStatusSucceeded, RefundStatusFailed)),
+	)
+}
+
+type RefundAdminGetAllRes struct {
+	ID                  uuid.UUID       `json:"id"`
+	OrderID             uuid.UUID       `json:"order_id"`
+	PaymentID           uuid.UUID       `json:"payment_id"`
+	PaymentReference    string          `json:"payment_reference"`
+	UserID              uuid.UUID       `json:"user_id"`
+	UserName            string          `json:"user_name"`
+	ServiceProviderID   uuid.UUID       `json:"service_provider_id"`
+	ServiceProviderName string          `json:"service_provider_name"`
+	Amount              decimal.Decimal `json:"amount"`
+	Reason              string          `json:"reason"`
+	Status              RefundStatus    `json:"status"`
+	FailureMessage      null.String     `json:"failure_message"`
+	CreatedAt           time.Time       `json:"created_at"`
+	UpdatedAt           null.Time       `json:"updated_at"`
+}
+
+// RefundAdminActionReq is used by retry and mark as succeeded
+type RefundAdminActionReq struct {
+	AuthUser AuthUser  `middleware:"user"`
+	ID       uuid.UUID `param:"id"`
+}
+
+func (r RefundAdminActionReq) Validate() error {
+	if r.AuthUser.IsZero() {
+		return errors.New("AuthUser is required")
+	}
+
+	if r.ID == uuid.Nil {
+		return ErrIDRouteParamRequired
+	}
+
+	return nil
+}
+
+// endregion service types
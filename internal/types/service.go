@@ -14,7 +14,12 @@ import (
 	"github.com/volatiletech/null/v9"
 )
 
-const ServiceElasticSearchIndexName = "services"
+const (
+	// ServiceElasticSearchIndexName is an alias pointing to the versioned index, reads and writes must go through it
+	ServiceElasticSearchIndexName = "services"
+	// ServiceElasticSearchVersionedIndexName must be bumped whenever a field mapping changes
+	ServiceElasticSearchVersionedIndexName = "services_v2"
+)
 
 // region repo types
 
@@ -181,12 +186,20 @@ type ServiceIndex struct {
 	Rules                 ServiceRules            `json:"rules"`
 	FeeStartAt            decimal.Decimal         `json:"fee_start_at"`
 	FeeEndAt              decimal.Decimal         `json:"fee_end_at"`
-	Coordinates           null.String             `json:"coordinates"`
+	Location              *ServiceIndexLocation   `json:"location,omitempty"`
 	IsAvailable           bool                    `json:"is_available"`
 	Images                []string                `json:"images"`
 	ReceivedRatingCount   int32                   `json:"received_rating_count"`
 	ReceivedRatingAverage float32                 `json:"received_rating_average"`
 	CreatedAt             time.Time               `json:"created_at"`
+	// Distance in kilometers from the searched location, only filled on a geo-distance search
+	Distance null.Float64 `json:"-"`
+}
+
+// ServiceIndexLocation is mapped as geo_point, it holds the service provider office coordinates
+type ServiceIndexLocation struct {
+	Lat float64 `json:"lat"`
+	Lon float64 `json:"lon"`
 }
 
 type ServiceGetByIDReq struct {
@@ -320,6 +333,39 @@ type ServiceIndexFilter struct {
 	Province   string
 	City       string
 	Categories []string
+	// Location and RadiusKm filter the services by distance and sort them from the nearest
+	Location *ServiceIndexLocation
+	RadiusKm float64
+	// AreaCity matches services without office coordinates by their service area instead
+	AreaCity        string
+	FeeMin          decimal.NullDecimal
+	FeeMax          decimal.NullDecimal
+	MinRating       null.Float64
+	DeliveryMethods []ServiceDeliveryMethod
+	IsAvailable     null.Bool
+	Sort            ServiceIndexSort
+}
+
+type ServiceIndexSort string
+
+const (
+	ServiceIndexSortRelevance ServiceIndexSort = "relevance"
+	ServiceIndexSortNewest    ServiceIndexSort = "newest"
+	ServiceIndexSortPriceAsc  ServiceIndexSort = "price_asc"
+	ServiceIndexSortPriceDesc ServiceIndexSort = "price_desc"
+	ServiceIndexSortRating    ServiceIndexSort = "rating"
+	ServiceIndexSortDistance  ServiceIndexSort = "distance"
+)
+
+type ServiceIndexFacetBucket struct {
+	Value string `json:"value"`
+	Count int64  `json:"count"`
+}
+
+type ServiceIndexFacets struct {
+	Categories      []ServiceIndexFacetBucket `json:"categories"`
+	Cities          []ServiceIndexFacetBucket `json:"cities"`
+	DeliveryMethods []ServiceIndexFacetBucket `json:"delivery_methods"`
 }
 
 // end of region service types
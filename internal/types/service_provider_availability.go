@@ -0,0 +1,417 @@
+package types
+
+import (
+	"fmt"
+	"kelarin/internal/utils"
+	dbUtil "kelarin/internal/utils/dbutil"
+	"net/http"
+	"time"
+
+	"github.com/go-errors/errors"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/google/uuid"
+	"github.com/volatiletech/null/v9"
+)
+
+// region repo types
+
+type ServiceProviderWorkingHour struct {
+	ServiceProviderID uuid.UUID `db:"service_provider_id"`
+	DayOfWeek         int16     `db:"day_of_week"` // same as time.Weekday, 0 is sunday
+	StartTime         time.Time `db:"start_time"`
+	EndTime           time.Time `db:"end_time"` // before StartTime when the working hour ends after midnight
+}
+
+type ServiceProviderDayOff struct {
+	ID                uuid.UUID   `db:"id"`
+	ServiceProviderID uuid.UUID   `db:"service_provider_id"`
+	Date              time.Time   `db:"date"`
+	Reason            null.String `db:"reason"`
+	CreatedAt         time.Time   `db:"created_at"`
+}
+
+// ServiceBookedSession is an order session that still occupies the schedule of a service
+type ServiceBookedSession struct {
+	ServiceDate time.Time `db:"service_date"`
+	ServiceTime time.Time `db:"service_time"`
+}
+
+// endregion repo types
+
+// region service types
+
+// ServiceAvailabilitySlotDuration is how long a booked session occupies the schedule of a service
+const ServiceAvailabilitySlotDuration = time.Hour
+
+// ServiceAvailability is the schedule of a service between two dates. A service provider without any working hour
+// has not published the calendar yet and is treated as available every day
+type ServiceAvailability struct {
+	Location          *time.Location // every time of day is compared in this location
+	WorkingHours      []ServiceProviderWorkingHour
+	DaysOff           []ServiceProviderDayOff
+	MaxConcurrentJobs int16
+	BookedSessions    []ServiceBookedSession
+}
+
+type ServiceAvailabilityFindReq struct {
+	Tx        dbUtil.Tx // optional, locks the service until the tx ends so the schedule can not be booked concurrently
+	Service   Service
+	Location  *time.Location
+	StartDate time.Time
+	EndDate   time.Time
+}
+
+// IsAvailable reports whether a session can be booked on the date at the given time of day
+func (a ServiceAvailability) IsAvailable(date, at time.Time) bool {
+	return a.isAvailableAt(date, a.clock(at))
+}
+
+// FreeSlots returns the start of every free slot on the date as a time of day
+func (a ServiceAvailability) FreeSlots(date time.Time) []time.Duration {
+	res := []time.Duration{}
+
+	start, end, ok := a.workingWindow(date)
+	if !ok {
+		return res
+	}
+
+	for at := start; at+ServiceAvailabilitySlotDuration <= end; at += ServiceAvailabilitySlotDuration {
+		if a.isAvailableAt(date, at) {
+			res = append(res, at)
+		}
+	}
+
+	return res
+}
+
+// HasCommonFreeSlot reports whether one time of day between from and to is free on every date
+func (a ServiceAvailability) HasCommonFreeSlot(dates []time.Time, from, to time.Time) bool {
+	start := a.clock(from)
+	end := a.clock(to)
+	if end < start {
+		end += 24 * time.Hour
+	}
+
+	for at := start; at <= end; at += ServiceAvailabilitySlotDuration {
+		available := true
+		for _, date := range dates {
+			if !a.isAvailableAt(date, at%(24*time.Hour)) {
+				available = false
+				break
+			}
+		}
+
+		if available {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (a ServiceAvailability) isAvailableAt(date time.Time, at time.Duration) bool {
+	if a.isDayOff(date) {
+		return false
+	}
+
+	start, end, ok := a.workingWindow(date)
+	if !ok {
+		return false
+	}
+
+	if at < start {
+		at += 24 * time.Hour
+	}
+
+	if at < start || at+ServiceAvailabilitySlotDuration > end {
+		return false
+	}
+
+	return a.bookedCount(date, at%(24*time.Hour)) < int(a.MaxConcurrentJobs)
+}
+
+func (a ServiceAvailability) workingWindow(date time.Time) (time.Duration, time.Duration, bool) {
+	if len(a.WorkingHours) == 0 {
+		return 0, 24 * time.Hour, true
+	}
+
+	for _, wh := range a.WorkingHours {
+		if wh.DayOfWeek != int16(date.Weekday()) {
+			continue
+		}
+
+		start := a.clock(wh.StartTime)
+		end := a.clock(wh.EndTime)
+		if end <= start {
+			end += 24 * time.Hour
+		}
+
+		return start, end, true
+	}
+
+	return 0, 0, false
+}
+
+func (a ServiceAvailability) isDayOff(date time.Time) bool {
+	for _, dayOff := range a.DaysOff {
+		if dayOff.Date.Format(time.DateOnly) == date.Format(time.DateOnly) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (a ServiceAvailability) bookedCount(date time.Time, at time.Duration) int {
+	count := 0
+	for _, session := range a.BookedSessions {
+		if session.ServiceDate.Format(time.DateOnly) != date.Format(time.DateOnly) {
+			continue
+		}
+
+		diff := (a.clock(session.ServiceTime) - at).Abs()
+		if diff > 12*time.Hour {
+			diff = 24*time.Hour - diff
+		}
+
+		if diff < ServiceAvailabilitySlotDuration {
+			count++
+		}
+	}
+
+	return count
+}
+
+// clock returns the time of day of t in the availability location
+func (a ServiceAvailability) clock(t time.Time) time.Duration {
+	loc := a.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	t = time.Date(2000, 1, 1, t.Hour(), t.Minute(), t.Second(), 0, t.Location()).In(loc)
+
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute + time.Duration(t.Second())*time.Second
+}
+
+// FormatClock formats a time of day returned by FreeSlots
+func FormatClock(d time.Duration) string {
+	return time.Time{}.Add(d % (24 * time.Hour)).Format(time.TimeOnly)
+}
+
+type ServiceProviderWorkingHourProviderGetAllReq struct {
+	AuthUser AuthUser `middleware:"user"`
+	TimeZone string   `header:"Time-Zone"`
+}
+
+func (r ServiceProviderWorkingHourProviderGetAllReq) Validate() error {
+	if r.AuthUser.IsZero() {
+		return errors.New("AuthUser is required")
+	}
+
+	return nil
+}
+
+type ServiceProviderWorkingHourRes struct {
+	DayOfWeek int16  `json:"day_of_week"`
+	StartTime string `json:"start_time"`
+	EndTime   string `json:"end_time"`
+}
+
+type ServiceProviderWorkingHourProviderUpdateReq struct {
+	AuthUser     AuthUser                                          `middleware:"user"`
+	TimeZone     string                                            `header:"Time-Zone"`
+	WorkingHours []ServiceProviderWorkingHourProviderUpdateReqItem `json:"working_hours"`
+}
+
+type ServiceProviderWorkingHourProviderUpdateReqItem struct {
+	DayOfWeek int16  `json:"day_of_week"`
+	StartTime string `json:"start_time"`
+	EndTime   string `json:"end_time"`
+}
+
+func (r ServiceProviderWorkingHourProviderUpdateReqItem) Validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.DayOfWeek, validation.Min(int16(time.Sunday)), validation.Max(int16(time.Saturday))),
+		validation.Field(&r.StartTime, validation.Required, validation.Date(time.TimeOnly)),
+		validation.Field(&r.EndTime, validation.Required, validation.Date(time.TimeOnly), validation.NotIn(r.StartTime).Error("must be different from start_time")),
+	)
+}
+
+func (r ServiceProviderWorkingHourProviderUpdateReq) Validate() error {
+	if r.AuthUser.IsZero() {
+		return errors.New("AuthUser is required")
+	}
+
+	err := validation.ValidateStruct(&r,
+		validation.Field(&r.WorkingHours, validation.Length(0, 7)),
+	)
+	if err != nil {
+		return err
+	}
+
+	ve := validation.Errors{}
+	days := map[int16]bool{}
+	for i, wh := range r.WorkingHours {
+		if err := wh.Validate(); err != nil {
+			ve[fmt.Sprintf("working_hours.%d", i)] = err
+			continue
+		}
+
+		if days[wh.DayOfWeek] {
+			ve[fmt.Sprintf("working_hours.%d", i)] = validation.NewError("day_of_week_duplicate", "day_of_week must be unique")
+		}
+
+		days[wh.DayOfWeek] = true
+	}
+
+	if len(ve) > 0 {
+		return ve
+	}
+
+	return nil
+}
+
+type ServiceProviderDayOffProviderGetAllReq struct {
+	AuthUser AuthUser `middleware:"user"`
+}
+
+func (r ServiceProviderDayOffProviderGetAllReq) Validate() error {
+	if r.AuthUser.IsZero() {
+		return errors.New("AuthUser is required")
+	}
+
+	return nil
+}
+
+type ServiceProviderDayOffRes struct {
+	ID        uuid.UUID   `json:"id"`
+	Date      string      `json:"date"`
+	Reason    null.String `json:"reason"`
+	CreatedAt time.Time   `json:"created_at"`
+}
+
+type ServiceProviderDayOffProviderCreateReq struct {
+	AuthUser AuthUser    `middleware:"user"`
+	Date     string      `json:"date"`
+	Reason   null.String `json:"reason"`
+}
+
+func (r ServiceProviderDayOffProviderCreateReq) Validate() error {
+	if r.AuthUser.IsZero() {
+		return errors.New("AuthUser is required")
+	}
+
+	err := validation.ValidateStruct(&r,
+		validation.Field(&r.Date, validation.Required, validation.Date(time.DateOnly)),
+		validation.Field(&r.Reason, validation.Length(0, 255)),
+	)
+	if err != nil {
+		return err
+	}
+
+	date, err := time.Parse(time.DateOnly, r.Date)
+	if err != nil {
+		return errors.New(err)
+	}
+
+	if date.Before(utils.DateNowInUTC()) {
+		return validation.Errors{"date": validation.NewError("date_min", "date must be equal or greater than today")}
+	}
+
+	return nil
+}
+
+type ServiceProviderDayOffProviderDeleteReq struct {
+	AuthUser AuthUser  `middleware:"user"`
+	ID       uuid.UUID `param:"id"`
+}
+
+func (r ServiceProviderDayOffProviderDeleteReq) Validate() error {
+	if r.AuthUser.IsZero() {
+		return errors.New("AuthUser is required")
+	}
+
+	if r.ID == uuid.Nil {
+		return ErrIDRouteParamRequired
+	}
+
+	return nil
+}
+
+type ServiceAvailabilityProviderUpdateReq struct {
+	AuthUser          AuthUser  `middleware:"user"`
+	ID                uuid.UUID `param:"id"`
+	MaxConcurrentJobs int16     `json:"max_concurrent_jobs"`
+}
+
+func (r ServiceAvailabilityProviderUpdateReq) Validate() error {
+	if r.AuthUser.IsZero() {
+		return errors.New("AuthUser is required")
+	}
+
+	if r.ID == uuid.Nil {
+		return errors.New(AppErr{Code: http.StatusBadRequest, Message: ErrIDRouteParamRequired.Error()})
+	}
+
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.MaxConcurrentJobs, validation.Required, validation.Min(int16(1)), validation.Max(int16(100))),
+	)
+}
+
+// ServiceAvailabilityMaxDays limits the date range of the free slots query
+const ServiceAvailabilityMaxDays = 31
+
+type ServiceAvailabilityGetSlotsReq struct {
+	ID        uuid.UUID `param:"id"`
+	TimeZone  string    `header:"Time-Zone"`
+	StartDate string    `form:"start_date"`
+	EndDate   string    `form:"end_date"`
+}
+
+func (r ServiceAvailabilityGetSlotsReq) Validate() error {
+	if r.ID == uuid.Nil {
+		return errors.New(AppErr{Code: http.StatusBadRequest, Message: ErrIDRouteParamRequired.Error()})
+	}
+
+	err := validation.ValidateStruct(&r,
+		validation.Field(&r.StartDate, validation.Required, validation.Date(time.DateOnly)),
+		validation.Field(&r.EndDate, validation.Required, validation.Date(time.DateOnly)),
+	)
+	if err != nil {
+		return err
+	}
+
+	startDate, err := time.Parse(time.DateOnly, r.StartDate)
+	if err != nil {
+		return errors.New(err)
+	}
+
+	endDate, err := time.Parse(time.DateOnly, r.EndDate)
+	if err != nil {
+		return errors.New(err)
+	}
+
+	if endDate.Before(startDate) {
+		return validation.Errors{"end_date": validation.NewError("end_date_min", "end_date must be equal or greater than start_date")}
+	}
+
+	if endDate.Sub(startDate) >= ServiceAvailabilityMaxDays*24*time.Hour {
+		return validation.Errors{"end_date": validation.NewError("end_date_max", fmt.Sprintf("date range must not exceed %d days", ServiceAvailabilityMaxDays))}
+	}
+
+	return nil
+}
+
+type ServiceAvailabilityGetSlotsRes struct {
+	MaxConcurrentJobs int16                                `json:"max_concurrent_jobs"`
+	Dates             []ServiceAvailabilityGetSlotsResDate `json:"dates"`
+}
+
+type ServiceAvailabilityGetSlotsResDate struct {
+	Date      string   `json:"date"`
+	DayOff    bool     `json:"day_off"`
+	FreeSlots []string `json:"free_slots"` // start time of every free ServiceAvailabilitySlotDuration slot
+}
+
+// endregion service types
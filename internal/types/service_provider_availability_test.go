@@ -0,0 +1,317 @@
+package types_test
+
+import (
+	"kelarin/internal/types"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServiceAvailability(t *testing.T) {
+	date := func(s string) time.Time {
+		d, err := time.Parse(time.DateOnly, s)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return d
+	}
+
+	clock := func(s string) time.Time {
+		c, err := time.Parse(time.TimeOnly, s)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return c
+	}
+
+	workingHour := func(day time.Weekday, start, end string) types.ServiceProviderWorkingHour {
+		return types.ServiceProviderWorkingHour{DayOfWeek: int16(day), StartTime: clock(start), EndTime: clock(end)}
+	}
+
+	booked := func(d, at string) types.ServiceBookedSession {
+		return types.ServiceBookedSession{ServiceDate: date(d), ServiceTime: clock(at)}
+	}
+
+	// 2025-05-05 is a monday
+	monday := "2025-05-05"
+	tuesday := "2025-05-06"
+
+	t.Run("IsAvailable", func(t *testing.T) {
+		tests := []struct {
+			name         string
+			availability types.ServiceAvailability
+			date         string
+			at           string
+			expected     bool
+		}{
+			{
+				name:         "available every day without working hours",
+				availability: types.ServiceAvailability{MaxConcurrentJobs: 1},
+				date:         monday,
+				at:           "03:00:00",
+				expected:     true,
+			},
+			{
+				name:         "inside the working hour",
+				availability: types.ServiceAvailability{WorkingHours: []types.ServiceProviderWorkingHour{workingHour(time.Monday, "08:00:00", "17:00:00")}, MaxConcurrentJobs: 1},
+				date:         monday,
+				at:           "16:00:00",
+				expected:     true,
+			},
+			{
+				name:         "before the working hour",
+				availability: types.ServiceAvailability{WorkingHours: []types.ServiceProviderWorkingHour{workingHour(time.Monday, "08:00:00", "17:00:00")}, MaxConcurrentJobs: 1},
+				date:         monday,
+				at:           "07:00:00",
+				expected:     false,
+			},
+			{
+				name:         "slot ends after the working hour",
+				availability: types.ServiceAvailability{WorkingHours: []types.ServiceProviderWorkingHour{workingHour(time.Monday, "08:00:00", "17:00:00")}, MaxConcurrentJobs: 1},
+				date:         monday,
+				at:           "16:30:00",
+				expected:     false,
+			},
+			{
+				name:         "no working hour on the day",
+				availability: types.ServiceAvailability{WorkingHours: []types.ServiceProviderWorkingHour{workingHour(time.Monday, "08:00:00", "17:00:00")}, MaxConcurrentJobs: 1},
+				date:         tuesday,
+				at:           "09:00:00",
+				expected:     false,
+			},
+			{
+				name:         "midnight wrapping window before midnight",
+				availability: types.ServiceAvailability{WorkingHours: []types.ServiceProviderWorkingHour{workingHour(time.Monday, "22:00:00", "02:00:00")}, MaxConcurrentJobs: 1},
+				date:         monday,
+				at:           "23:00:00",
+				expected:     true,
+			},
+			{
+				name:         "midnight wrapping window after midnight",
+				availability: types.ServiceAvailability{WorkingHours: []types.ServiceProviderWorkingHour{workingHour(time.Monday, "22:00:00", "02:00:00")}, MaxConcurrentJobs: 1},
+				date:         monday,
+				at:           "01:00:00",
+				expected:     true,
+			},
+			{
+				name:         "midnight wrapping window slot ends after the working hour",
+				availability: types.ServiceAvailability{WorkingHours: []types.ServiceProviderWorkingHour{workingHour(time.Monday, "22:00:00", "02:00:00")}, MaxConcurrentJobs: 1},
+				date:         monday,
+				at:           "01:30:00",
+				expected:     false,
+			},
+			{
+				name:         "midnight wrapping window outside the working hour",
+				availability: types.ServiceAvailability{WorkingHours: []types.ServiceProviderWorkingHour{workingHour(time.Monday, "22:00:00", "02:00:00")}, MaxConcurrentJobs: 1},
+				date:         monday,
+				at:           "21:00:00",
+				expected:     false,
+			},
+			{
+				name:         "day off",
+				availability: types.ServiceAvailability{DaysOff: []types.ServiceProviderDayOff{{Date: date(monday)}}, MaxConcurrentJobs: 1},
+				date:         monday,
+				at:           "09:00:00",
+				expected:     false,
+			},
+			{
+				name:         "day off on another date",
+				availability: types.ServiceAvailability{DaysOff: []types.ServiceProviderDayOff{{Date: date(tuesday)}}, MaxConcurrentJobs: 1},
+				date:         monday,
+				at:           "09:00:00",
+				expected:     true,
+			},
+			{
+				name:         "booked below max concurrent jobs",
+				availability: types.ServiceAvailability{MaxConcurrentJobs: 2, BookedSessions: []types.ServiceBookedSession{booked(monday, "09:00:00")}},
+				date:         monday,
+				at:           "09:00:00",
+				expected:     true,
+			},
+			{
+				name:         "booked up to max concurrent jobs",
+				availability: types.ServiceAvailability{MaxConcurrentJobs: 2, BookedSessions: []types.ServiceBookedSession{booked(monday, "09:00:00"), booked(monday, "09:00:00")}},
+				date:         monday,
+				at:           "09:00:00",
+				expected:     false,
+			},
+			{
+				name:         "overlapping booked sessions",
+				availability: types.ServiceAvailability{MaxConcurrentJobs: 2, BookedSessions: []types.ServiceBookedSession{booked(monday, "09:30:00"), booked(monday, "08:30:00")}},
+				date:         monday,
+				at:           "09:00:00",
+				expected:     false,
+			},
+			{
+				name:         "booked sessions one slot apart",
+				availability: types.ServiceAvailability{MaxConcurrentJobs: 1, BookedSessions: []types.ServiceBookedSession{booked(monday, "08:00:00"), booked(monday, "10:00:00")}},
+				date:         monday,
+				at:           "09:00:00",
+				expected:     true,
+			},
+			{
+				name:         "booked on another date",
+				availability: types.ServiceAvailability{MaxConcurrentJobs: 1, BookedSessions: []types.ServiceBookedSession{booked(tuesday, "09:00:00")}},
+				date:         monday,
+				at:           "09:00:00",
+				expected:     true,
+			},
+			{
+				name:         "booked session overlapping midnight",
+				availability: types.ServiceAvailability{MaxConcurrentJobs: 1, BookedSessions: []types.ServiceBookedSession{booked(monday, "23:30:00")}},
+				date:         monday,
+				at:           "00:00:00",
+				expected:     false,
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				assert.Equal(t, tt.expected, tt.availability.IsAvailable(date(tt.date), clock(tt.at)))
+			})
+		}
+	})
+
+	t.Run("FreeSlots", func(t *testing.T) {
+		tests := []struct {
+			name         string
+			availability types.ServiceAvailability
+			date         string
+			expected     []time.Duration
+		}{
+			{
+				name: "skips booked slots",
+				availability: types.ServiceAvailability{
+					WorkingHours:      []types.ServiceProviderWorkingHour{workingHour(time.Monday, "08:00:00", "12:00:00")},
+					MaxConcurrentJobs: 1,
+					BookedSessions:    []types.ServiceBookedSession{booked(monday, "09:00:00")},
+				},
+				date:     monday,
+				expected: []time.Duration{8 * time.Hour, 10 * time.Hour, 11 * time.Hour},
+			},
+			{
+				name: "midnight wrapping window",
+				availability: types.ServiceAvailability{
+					WorkingHours:      []types.ServiceProviderWorkingHour{workingHour(time.Monday, "22:00:00", "01:00:00")},
+					MaxConcurrentJobs: 1,
+				},
+				date:     monday,
+				expected: []time.Duration{22 * time.Hour, 23 * time.Hour, 24 * time.Hour},
+			},
+			{
+				name: "day off",
+				availability: types.ServiceAvailability{
+					WorkingHours:      []types.ServiceProviderWorkingHour{workingHour(time.Monday, "08:00:00", "12:00:00")},
+					DaysOff:           []types.ServiceProviderDayOff{{Date: date(monday)}},
+					MaxConcurrentJobs: 1,
+				},
+				date:     monday,
+				expected: []time.Duration{},
+			},
+			{
+				name: "no working hour on the day",
+				availability: types.ServiceAvailability{
+					WorkingHours:      []types.ServiceProviderWorkingHour{workingHour(time.Monday, "08:00:00", "12:00:00")},
+					MaxConcurrentJobs: 1,
+				},
+				date:     tuesday,
+				expected: []time.Duration{},
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				assert.Equal(t, tt.expected, tt.availability.FreeSlots(date(tt.date)))
+			})
+		}
+	})
+
+	t.Run("HasCommonFreeSlot", func(t *testing.T) {
+		workingHours := []types.ServiceProviderWorkingHour{
+			workingHour(time.Monday, "08:00:00", "12:00:00"),
+			workingHour(time.Tuesday, "08:00:00", "12:00:00"),
+		}
+
+		tests := []struct {
+			name         string
+			availability types.ServiceAvailability
+			dates        []string
+			from         string
+			to           string
+			expected     bool
+		}{
+			{
+				name: "common free slot on every date",
+				availability: types.ServiceAvailability{
+					WorkingHours:      workingHours,
+					MaxConcurrentJobs: 1,
+					BookedSessions:    []types.ServiceBookedSession{booked(monday, "08:00:00"), booked(tuesday, "09:00:00")},
+				},
+				dates:    []string{monday, tuesday},
+				from:     "08:00:00",
+				to:       "10:00:00",
+				expected: true,
+			},
+			{
+				name: "free slots differ between dates",
+				availability: types.ServiceAvailability{
+					WorkingHours:      workingHours,
+					MaxConcurrentJobs: 1,
+					BookedSessions:    []types.ServiceBookedSession{booked(monday, "08:00:00"), booked(tuesday, "09:00:00")},
+				},
+				dates:    []string{monday, tuesday},
+				from:     "08:00:00",
+				to:       "09:00:00",
+				expected: false,
+			},
+			{
+				name: "one of the dates is a day off",
+				availability: types.ServiceAvailability{
+					WorkingHours:      workingHours,
+					DaysOff:           []types.ServiceProviderDayOff{{Date: date(tuesday)}},
+					MaxConcurrentJobs: 1,
+				},
+				dates:    []string{monday, tuesday},
+				from:     "08:00:00",
+				to:       "11:00:00",
+				expected: false,
+			},
+			{
+				name: "range wrapping midnight",
+				availability: types.ServiceAvailability{
+					MaxConcurrentJobs: 1,
+					BookedSessions:    []types.ServiceBookedSession{booked(monday, "23:00:00"), booked(tuesday, "00:00:00")},
+				},
+				dates:    []string{monday, tuesday},
+				from:     "23:00:00",
+				to:       "01:00:00",
+				expected: true,
+			},
+			{
+				name: "range wrapping midnight fully booked",
+				availability: types.ServiceAvailability{
+					MaxConcurrentJobs: 1,
+					BookedSessions:    []types.ServiceBookedSession{booked(monday, "23:00:00"), booked(tuesday, "00:00:00"), booked(tuesday, "01:00:00")},
+				},
+				dates:    []string{monday, tuesday},
+				from:     "23:00:00",
+				to:       "01:00:00",
+				expected: false,
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				dates := make([]time.Time, len(tt.dates))
+				for i, d := range tt.dates {
+					dates[i] = date(d)
+				}
+
+				assert.Equal(t, tt.expected, tt.availability.HasCommonFreeSlot(dates, clock(tt.from), clock(tt.to)))
+			})
+		}
+	})
+}
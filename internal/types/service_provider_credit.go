@@ -0,0 +1,327 @@
+package types
+
+import (
+	dbUtil "kelarin/internal/utils/dbutil"
+	"net/http"
+	"time"
+
+	"github.com/go-errors/errors"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/volatiletech/null/v9"
+)
+
+// region repo types
+
+type ServiceProviderCreditLedger struct {
+	ID                uuid.UUID                       `db:"id"`
+	ServiceProviderID uuid.UUID                       `db:"service_provider_id"`
+	Type              ServiceProviderCreditLedgerType `db:"type"`
+	Amount            decimal.Decimal                 `db:"amount"`  // signed, negative when the credit is deducted
+	Balance           decimal.Decimal                 `db:"balance"` // credit of the service provider after the movement
+	OrderID           uuid.NullUUID                   `db:"order_id"`
+	PayoutID          uuid.NullUUID                   `db:"payout_id"`
+	Note              null.String                     `db:"note"`
+	CreatedAt         time.Time                       `db:"created_at"`
+}
+
+type ServiceProviderCreditLedgerType string
+
+const (
+	ServiceProviderCreditLedgerTypeOrderFinished  ServiceProviderCreditLedgerType = "order_finished"
+	ServiceProviderCreditLedgerTypePayout         ServiceProviderCreditLedgerType = "payout"
+	ServiceProviderCreditLedgerTypePayoutRejected ServiceProviderCreditLedgerType = "payout_rejected"
+	ServiceProviderCreditLedgerTypeAdjustment     ServiceProviderCreditLedgerType = "adjustment"
+)
+
+type ServiceProviderBankAccount struct {
+	ID                uuid.UUID `db:"id"`
+	ServiceProviderID uuid.UUID `db:"service_provider_id"`
+	BankName          string    `db:"bank_name"`
+	AccountNumber     string    `db:"account_number"`
+	AccountHolderName string    `db:"account_holder_name"`
+	IsDeleted         bool      `db:"is_deleted"`
+	CreatedAt         time.Time `db:"created_at"`
+	DeletedAt         null.Time `db:"deleted_at"`
+}
+
+type Payout struct {
+	ID                           uuid.UUID       `db:"id"`
+	ServiceProviderID            uuid.UUID       `db:"service_provider_id"`
+	ServiceProviderBankAccountID uuid.UUID       `db:"service_provider_bank_account_id"`
+	Amount                       decimal.Decimal `db:"amount"`
+	Status                       PayoutStatus    `db:"status"`
+	AdminUserID                  uuid.NullUUID   `db:"admin_user_id"`
+	Note                         null.String     `db:"note"`
+	CreatedAt                    time.Time       `db:"created_at"`
+	UpdatedAt                    null.Time       `db:"updated_at"`
+	PaidAt                       null.Time       `db:"paid_at"`
+}
+
+type PayoutStatus string
+
+const (
+	PayoutStatusPending  PayoutStatus = "pending"
+	PayoutStatusApproved PayoutStatus = "approved"
+	PayoutStatusRejected PayoutStatus = "rejected"
+	PayoutStatusPaid     PayoutStatus = "paid"
+)
+
+type PayoutWithBankAccount struct {
+	Payout
+	ServiceProviderName string `db:"service_provider_name"`
+	BankName            string `db:"bank_name"`
+	AccountNumber       string `db:"account_number"`
+	AccountHolderName   string `db:"account_holder_name"`
+}
+
+type PayoutFilter struct {
+	ServiceProviderID uuid.NullUUID
+	Status            PayoutStatus
+	Limit             int
+	Offset            int
+}
+
+// end of region repo types
+
+// region service types
+
+// ErrServiceProviderInsufficientCredit is returned when a deduction would make the credit negative, the credit of a
+// service provider never goes below zero
+var ErrServiceProviderInsufficientCredit = errors.New(AppErr{Code: http.StatusConflict, Message: "insufficient credit"})
+
+// PayoutMinimumAmount is the smallest amount of credit a service provider can withdraw at once
+var PayoutMinimumAmount = decimal.NewFromInt(50000)
+
+// ServiceProviderCreditAddReq moves the credit of a service provider by Amount, a negative Amount deducts the credit
+type ServiceProviderCreditAddReq struct {
+	Tx                dbUtil.Tx
+	ServiceProviderID uuid.UUID
+	Amount            decimal.Decimal
+	Type              ServiceProviderCreditLedgerType
+	OrderID           uuid.NullUUID
+	PayoutID          uuid.NullUUID
+	Note              null.String
+}
+
+func (r ServiceProviderCreditAddReq) Validate() error {
+	if r.Tx == nil {
+		return errors.New("Tx is required")
+	}
+
+	if r.ServiceProviderID == uuid.Nil {
+		return errors.New("ServiceProviderID is required")
+	}
+
+	if r.Amount.IsZero() {
+		return errors.New("Amount must not be zero")
+	}
+
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.Type, validation.Required, validation.In(
+			ServiceProviderCreditLedgerTypeOrderFinished,
+			ServiceProviderCreditLedgerTypePayout,
+			ServiceProviderCreditLedgerTypePayoutRejected,
+			ServiceProviderCreditLedgerTypeAdjustment,
+		)),
+	)
+}
+
+type ServiceProviderCreditProviderGetReq struct {
+	AuthUser AuthUser `middleware:"user"`
+}
+
+func (r ServiceProviderCreditProviderGetReq) Validate() error {
+	if r.AuthUser.IsZero() {
+		return errors.New("AuthUser is required")
+	}
+
+	return nil
+}
+
+type ServiceProviderCreditProviderGetRes struct {
+	Credit              decimal.Decimal `json:"credit"`
+	PendingPayoutAmount decimal.Decimal `json:"pending_payout_amount"`
+}
+
+type ServiceProviderCreditProviderGetLedgersReq struct {
+	AuthUser AuthUser `middleware:"user"`
+	PaginationReq
+}
+
+func (r ServiceProviderCreditProviderGetLedgersReq) Validate() error {
+	if r.AuthUser.IsZero() {
+		return errors.New("AuthUser is required")
+	}
+
+	return nil
+}
+
+type ServiceProviderCreditProviderGetLedgersRes struct {
+	ID        uuid.UUID                       `json:"id"`
+	Type      ServiceProviderCreditLedgerType `json:"type"`
+	Amount    decimal.Decimal                 `json:"amount"`
+	Balance   decimal.Decimal                 `json:"balance"`
+	OrderID   uuid.NullUUID                   `json:"order_id"`
+	PayoutID  uuid.NullUUID                   `json:"payout_id"`
+	Note      null.String                     `json:"note"`
+	CreatedAt time.Time                       `json:"created_at"`
+}
+
+type ServiceProviderBankAccountProviderGetAllReq struct {
+	AuthUser AuthUser `middleware:"user"`
+}
+
+func (r ServiceProviderBankAccountProviderGetAllReq) Validate() error {
+	if r.AuthUser.IsZero() {
+		return errors.New("AuthUser is required")
+	}
+
+	return nil
+}
+
+type ServiceProviderBankAccountProviderGetAllRes struct {
+	ID                uuid.UUID `json:"id"`
+	BankName          string    `json:"bank_name"`
+	AccountNumber     string    `json:"account_number"`
+	AccountHolderName string    `json:"account_holder_name"`
+	CreatedAt         time.Time `json:"created_at"`
+}
+
+type ServiceProviderBankAccountProviderCreateReq struct {
+	AuthUser          AuthUser `middleware:"user"`
+	BankName          string   `json:"bank_name"`
+	AccountNumber     string   `json:"account_number"`
+	AccountHolderName string   `json:"account_holder_name"`
+}
+
+func (r ServiceProviderBankAccountProviderCreateReq) Validate() error {
+	if r.AuthUser.IsZero() {
+		return errors.New("AuthUser is required")
+	}
+
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.BankName, validation.Required, validation.Length(1, 100)),
+		validation.Field(&r.AccountNumber, validation.Required, is.Digit, validation.Length(5, 50)),
+		validation.Field(&r.AccountHolderName, validation.Required, validation.Length(1, 255)),
+	)
+}
+
+type ServiceProviderBankAccountProviderDeleteReq struct {
+	AuthUser AuthUser  `middleware:"user"`
+	ID       uuid.UUID `param:"id"`
+}
+
+func (r ServiceProviderBankAccountProviderDeleteReq) Validate() error {
+	if r.AuthUser.IsZero() {
+		return errors.New("AuthUser is required")
+	}
+
+	if r.ID == uuid.Nil {
+		return ErrIDRouteParamRequired
+	}
+
+	return nil
+}
+
+type PayoutProviderGetAllReq struct {
+	AuthUser AuthUser     `middleware:"user"`
+	Status   PayoutStatus `form:"status"`
+	PaginationReq
+}
+
+func (r PayoutProviderGetAllReq) Validate() error {
+	if r.AuthUser.IsZero() {
+		return errors.New("AuthUser is required")
+	}
+
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.Status, validation.In(PayoutStatusPending, PayoutStatusApproved, PayoutStatusRejected, PayoutStatusPaid)),
+	)
+}
+
+type PayoutProviderCreateReq struct {
+	AuthUser                     AuthUser        `middleware:"user"`
+	ServiceProviderBankAccountID uuid.UUID       `json:"service_provider_bank_account_id"`
+	Amount                       decimal.Decimal `json:"amount"`
+}
+
+func (r PayoutProviderCreateReq) Validate() error {
+	if r.AuthUser.IsZero() {
+		return errors.New("AuthUser is required")
+	}
+
+	if err := validation.ValidateStruct(&r,
+		validation.Field(&r.ServiceProviderBankAccountID, validation.Required),
+		validation.Field(&r.Amount, validation.Required),
+	); err != nil {
+		return err
+	}
+
+	if r.Amount.LessThan(PayoutMinimumAmount) {
+		return errors.New(AppErr{Code: http.StatusBadRequest, Message: "amount must be at least " + PayoutMinimumAmount.String()})
+	}
+
+	return nil
+}
+
+type PayoutGetAllRes struct {
+	ID                  uuid.UUID                  `json:"id"`
+	ServiceProviderID   uuid.UUID                  `json:"service_provider_id"`
+	ServiceProviderName string                     `json:"service_provider_name"`
+	Amount              decimal.Decimal            `json:"amount"`
+	Status              PayoutStatus               `json:"status"`
+	BankAccount         PayoutGetAllResBankAccount `json:"bank_account"`
+	Note                null.String                `json:"note"`
+	CreatedAt           time.Time                  `json:"created_at"`
+	UpdatedAt           null.Time                  `json:"updated_at"`
+	PaidAt              null.Time                  `json:"paid_at"`
+}
+
+type PayoutGetAllResBankAccount struct {
+	ID                uuid.UUID `json:"id"`
+	BankName          string    `json:"bank_name"`
+	AccountNumber     string    `json:"account_number"`
+	AccountHolderName string    `json:"account_holder_name"`
+}
+
+type PayoutAdminGetAllReq struct {
+	AuthUser AuthUser     `middleware:"user"`
+	Status   PayoutStatus `form:"status"`
+	PaginationReq
+}
+
+func (r PayoutAdminGetAllReq) Validate() error {
+	if r.AuthUser.IsZero() {
+		return errors.New("AuthUser is required")
+	}
+
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.Status, validation.In(PayoutStatusPending, PayoutStatusApproved, PayoutStatusRejected, PayoutStatusPaid)),
+	)
+}
+
+// PayoutAdminActionReq is used by approve, reject and mark as paid, note is required when rejecting
+type PayoutAdminActionReq struct {
+	AuthUser AuthUser  `middleware:"user"`
+	ID       uuid.UUID `param:"id"`
+	Note     string    `json:"note"`
+}
+
+func (r PayoutAdminActionReq) Validate() error {
+	if r.AuthUser.IsZero() {
+		return errors.New("AuthUser is required")
+	}
+
+	if r.ID == uuid.Nil {
+		return ErrIDRouteParamRequired
+	}
+
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.Note, validation.Length(0, 1000)),
+	)
+}
+
+// end of region service types
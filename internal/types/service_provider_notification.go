@@ -16,6 +16,7 @@ type ServiceProviderNotification struct {
 	OfferID            uuid.NullUUID                   `db:"offer_id"`
 	OfferNegotiationID uuid.NullUUID                   `db:"offer_negotiation_id"`
 	OrderID            uuid.NullUUID                   `db:"order_id"`
+	DisputeID          uuid.NullUUID                   `db:"dispute_id"`
 	Type               ServiceProviderNotificationType `db:"type"`
 	Read               bool                            `db:"read"`
 	CreatedAt          time.Time                       `db:"created_at"`
@@ -36,8 +37,29 @@ const (
 
 const (
 	ServiceProviderNotificationTypeOrderFinished ServiceProviderNotificationType = iota + 201
+	ServiceProviderNotificationTypeOrderCanceled
+	ServiceProviderNotificationTypeOrderSessionFinished
 )
 
+const (
+	ServiceProviderNotificationTypeDisputeOpened ServiceProviderNotificationType = iota + 301
+	ServiceProviderNotificationTypeDisputeResolved
+)
+
+// ServiceProviderNotificationTypes is every type a service provider can set a notification preference for
+var ServiceProviderNotificationTypes = []ServiceProviderNotificationType{
+	ServiceProviderNotificationTypeOfferReceived,
+	ServiceProviderNotificationTypeOfferCanceled,
+	ServiceProviderNotificationTypeOfferNegotiationAccepted,
+	ServiceProviderNotificationTypeOfferNegotiationRejected,
+	ServiceProviderNotificationTypeConsumerSettledPayment,
+	ServiceProviderNotificationTypeOrderFinished,
+	ServiceProviderNotificationTypeOrderCanceled,
+	ServiceProviderNotificationTypeOrderSessionFinished,
+	ServiceProviderNotificationTypeDisputeOpened,
+	ServiceProviderNotificationTypeDisputeResolved,
+}
+
 type ServiceProviderNotificationWithUser struct {
 	ServiceProviderNotification
 	UserName null.String `db:"user_name"`
@@ -50,14 +72,15 @@ type ServiceProviderNotificationWithUser struct {
 type ServiceProviderNotificationGetAllReq struct {
 	AuthUser AuthUser `middleware:"user"`
 	TimeZone string   `header:"Time-Zone"`
+	CursorPaginationReq
 }
 
-func (r ServiceProviderNotificationGetAllReq) Validate() error {
+func (r *ServiceProviderNotificationGetAllReq) ValidateAndNormalize() error {
 	if r.AuthUser.IsZero() {
 		return errors.New("AuthUser is required")
 	}
 
-	return nil
+	return r.CursorPaginationReq.ValidateAndNormalize()
 }
 
 type ServiceProviderNotificationGetAllRes struct {
@@ -81,6 +104,11 @@ type ServiceProviderNotificationMetadataOrder struct {
 	OrderID uuid.UUID `json:"order_id"`
 }
 
+type ServiceProviderNotificationMetadataDispute struct {
+	OrderID   uuid.UUID `json:"order_id"`
+	DisputeID uuid.UUID `json:"dispute_id"`
+}
+
 type ServiceProviderNotificationGeneratedDetails struct {
 	Title    string
 	Message  string
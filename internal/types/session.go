@@ -1,11 +1,121 @@
 package types
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-errors/errors"
+	"github.com/google/uuid"
+)
 
 const (
-	SessionKey = "session"
+	SessionKey             = "session"
+	UserSessionsKey        = "user-sessions"
+	UserSessionMetadataKey = "user-session-metadata"
+	SessionFamilyKey       = "session-family"
 )
 
 func GetSessionKey(id string) string {
 	return fmt.Sprintf("%s:%s", SessionKey, id)
 }
+
+// GetSessionIDFromKey is the reverse of GetSessionKey
+func GetSessionIDFromKey(key string) (uuid.UUID, error) {
+	return uuid.Parse(strings.TrimPrefix(key, SessionKey+":"))
+}
+
+// GetUserSessionsKey returns the key of the set holding every session key owned by the user
+func GetUserSessionsKey(userID string) string {
+	return fmt.Sprintf("%s:%s", UserSessionsKey, userID)
+}
+
+// GetUserSessionMetadataKey returns the key of the hash holding the metadata of every session owned by the user, the field is the session key
+func GetUserSessionMetadataKey(userID string) string {
+	return fmt.Sprintf("%s:%s", UserSessionMetadataKey, userID)
+}
+
+// GetSessionFamilyKey returns the key of the set holding the ids of the renewed sessions of the family,
+// a refresh token of a session in the set has been used before
+func GetSessionFamilyKey(familyID string) string {
+	return fmt.Sprintf("%s:%s", SessionFamilyKey, familyID)
+}
+
+// region repo types
+
+// Session is the metadata of a login, a session created before the metadata was stored only has ID and UserID.
+// Every session renewed from the same login shares FamilyID, it is the id of the first session
+type Session struct {
+	ID         uuid.UUID `json:"id"`
+	UserID     uuid.UUID `json:"user_id"`
+	FamilyID   uuid.UUID `json:"family_id"`
+	DeviceName string    `json:"device_name"`
+	IPAddress  string    `json:"ip_address"`
+	UserAgent  string    `json:"user_agent"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastUsedAt time.Time `json:"last_used_at"`
+}
+
+// end of region repo types
+
+// region service types
+
+// SessionMetadataReq is embedded by the requests that create or renew a session, IPAddress is set by the handler
+type SessionMetadataReq struct {
+	DeviceName string `header:"Device-Name" json:"-"`
+	UserAgent  string `header:"User-Agent" json:"-"`
+	IPAddress  string `json:"-"`
+}
+
+type SessionGetAllReq struct {
+	AuthUser AuthUser `middleware:"user"`
+}
+
+func (r SessionGetAllReq) Validate() error {
+	if r.AuthUser.IsZero() {
+		return errors.New("AuthUser is required")
+	}
+
+	return nil
+}
+
+type SessionGetAllRes struct {
+	ID         uuid.UUID `json:"id"`
+	DeviceName string    `json:"device_name"`
+	IPAddress  string    `json:"ip_address"`
+	UserAgent  string    `json:"user_agent"`
+	IsCurrent  bool      `json:"is_current"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastUsedAt time.Time `json:"last_used_at"`
+}
+
+type SessionRevokeReq struct {
+	AuthUser AuthUser  `middleware:"user"`
+	ID       uuid.UUID `param:"id"`
+}
+
+func (r SessionRevokeReq) Validate() error {
+	if r.AuthUser.IsZero() {
+		return errors.New("AuthUser is required")
+	}
+
+	if r.ID == uuid.Nil {
+		return ErrIDRouteParamRequired
+	}
+
+	return nil
+}
+
+type SessionRevokeOthersReq struct {
+	AuthUser AuthUser `middleware:"user"`
+}
+
+func (r SessionRevokeOthersReq) Validate() error {
+	if r.AuthUser.IsZero() {
+		return errors.New("AuthUser is required")
+	}
+
+	return nil
+}
+
+// end of region service types
@@ -3,6 +3,8 @@ package types
 import (
 	"time"
 
+	"github.com/go-errors/errors"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
 	"github.com/google/uuid"
 	"github.com/volatiletech/null/v9"
 )
@@ -18,19 +20,169 @@ const (
 )
 
 type User struct {
-	ID             uuid.UUID    `db:"id"`
-	AuthProvider   AuthProvider `db:"auth_provider"`
-	Role           UserRole     `db:"role"`
-	Name           string       `db:"name"`
-	Email          string       `db:"email"`
-	Password       null.String  `db:"password"`
-	IsSuspended    bool         `db:"is_suspended"`
-	SuspendedCount int16        `db:"suspended_count"`
-	SuspendedFrom  null.Time    `db:"suspended_from"`
-	SuspendedTo    null.Time    `db:"suspended_to"`
-	IsBanned       bool         `db:"is_banned"`
-	BannedAt       null.Time    `db:"banned_at"`
-	CreatedAt      time.Time    `db:"created_at"`
+	ID              uuid.UUID    `db:"id"`
+	AuthProvider    AuthProvider `db:"auth_provider"`
+	Role            UserRole     `db:"role"`
+	Name            string       `db:"name"`
+	Email           string       `db:"email"`
+	Password        null.String  `db:"password"`
+	Language        Language     `db:"language"`
+	EmailVerifiedAt null.Time    `db:"email_verified_at"`
+	IsSuspended     bool         `db:"is_suspended"`
+	SuspendedCount  int16        `db:"suspended_count"`
+	SuspendedFrom   null.Time    `db:"suspended_from"`
+	SuspendedTo     null.Time    `db:"suspended_to"`
+	IsBanned        bool         `db:"is_banned"`
+	BannedAt        null.Time    `db:"banned_at"`
+	CreatedAt       time.Time    `db:"created_at"`
+}
+
+// IsSuspensionActive reports whether the user is suspended and the suspension period has not ended yet,
+// a suspension whose suspended_to has passed is waiting to be lifted by the cronjob and no longer blocks the user
+func (u User) IsSuspensionActive(now time.Time) bool {
+	return u.IsSuspended && (!u.SuspendedTo.Valid || u.SuspendedTo.Time.After(now))
 }
 
 // end of region repo types
+
+// region service types
+
+type UserStatus string
+
+const (
+	UserStatusActive    UserStatus = "active"
+	UserStatusSuspended UserStatus = "suspended"
+	UserStatusBanned    UserStatus = "banned"
+)
+
+func (u User) Status() UserStatus {
+	if u.IsBanned {
+		return UserStatusBanned
+	} else if u.IsSuspensionActive(time.Now()) {
+		return UserStatusSuspended
+	}
+
+	return UserStatusActive
+}
+
+type UserAdminFilter struct {
+	Keyword string
+	Role    UserRole
+	Status  UserStatus
+	Limit   int
+	Offset  int
+}
+
+type UserAdminGetAllReq struct {
+	AuthUser AuthUser   `middleware:"user"`
+	Keyword  string     `form:"keyword"`
+	Role     UserRole   `form:"role"`
+	Status   UserStatus `form:"status"`
+	PaginationReq
+}
+
+func (r UserAdminGetAllReq) Validate() error {
+	if r.AuthUser.IsZero() {
+		return errors.New("AuthUser is required")
+	}
+
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.Role, validation.In(UserRoleAdmin, UserRoleConsumer, UserRoleServiceProvider)),
+		validation.Field(&r.Status, validation.In(UserStatusActive, UserStatusSuspended, UserStatusBanned)),
+	)
+}
+
+type UserAdminGetAllRes struct {
+	ID             uuid.UUID  `json:"id"`
+	Name           string     `json:"name"`
+	Email          string     `json:"email"`
+	Role           UserRole   `json:"role"`
+	Status         UserStatus `json:"status"`
+	SuspendedCount int16      `json:"suspended_count"`
+	SuspendedFrom  null.Time  `json:"suspended_from"`
+	SuspendedTo    null.Time  `json:"suspended_to"`
+	BannedAt       null.Time  `json:"banned_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+type UserAdminGetByIDReq struct {
+	AuthUser AuthUser  `middleware:"user"`
+	ID       uuid.UUID `param:"id"`
+}
+
+func (r UserAdminGetByIDReq) Validate() error {
+	if r.AuthUser.IsZero() {
+		return errors.New("AuthUser is required")
+	}
+
+	if r.ID == uuid.Nil {
+		return ErrIDRouteParamRequired
+	}
+
+	return nil
+}
+
+type UserAdminGetByIDRes struct {
+	UserAdminGetAllRes
+	ModerationLogs []UserAdminGetByIDResModerationLog `json:"moderation_logs"`
+}
+
+type UserAdminGetByIDResModerationLog struct {
+	ID            uuid.UUID                              `json:"id"`
+	Action        UserModerationAction                   `json:"action"`
+	Reason        string                                 `json:"reason"`
+	SuspendedFrom null.Time                              `json:"suspended_from"`
+	SuspendedTo   null.Time                              `json:"suspended_to"`
+	Admin         *UserAdminGetByIDResModerationLogAdmin `json:"admin"`
+	CreatedAt     time.Time                              `json:"created_at"`
+}
+
+type UserAdminGetByIDResModerationLogAdmin struct {
+	ID   uuid.UUID `json:"id"`
+	Name string    `json:"name"`
+}
+
+type UserAdminSuspendReq struct {
+	AuthUser       AuthUser  `middleware:"user"`
+	ID             uuid.UUID `param:"id"`
+	DurationInDays int       `json:"duration_in_days"`
+	Reason         string    `json:"reason"`
+}
+
+func (r UserAdminSuspendReq) Validate() error {
+	if r.AuthUser.IsZero() {
+		return errors.New("AuthUser is required")
+	}
+
+	if r.ID == uuid.Nil {
+		return ErrIDRouteParamRequired
+	}
+
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.DurationInDays, validation.Required, validation.Min(1), validation.Max(365)),
+		validation.Field(&r.Reason, validation.Required, validation.Length(1, 1000)),
+	)
+}
+
+// UserAdminModerateReq is used by moderation actions that only need a reason: unsuspend, ban, unban and revoke sessions
+type UserAdminModerateReq struct {
+	AuthUser AuthUser  `middleware:"user"`
+	ID       uuid.UUID `param:"id"`
+	Reason   string    `json:"reason"`
+}
+
+func (r UserAdminModerateReq) Validate() error {
+	if r.AuthUser.IsZero() {
+		return errors.New("AuthUser is required")
+	}
+
+	if r.ID == uuid.Nil {
+		return ErrIDRouteParamRequired
+	}
+
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.Reason, validation.Required, validation.Length(1, 1000)),
+	)
+}
+
+// end of region service types
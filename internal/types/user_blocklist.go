@@ -0,0 +1,20 @@
+package types
+
+import (
+	"fmt"
+	"time"
+)
+
+const UserBlocklistKey = "user-blocklist"
+
+// GetUserBlocklistKey returns the key holding the status of a suspended or banned user
+func GetUserBlocklistKey(userID string) string {
+	return fmt.Sprintf("%s:%s", UserBlocklistKey, userID)
+}
+
+// UserBlocklistEntry is the moderation state of a blocked user, BlockedAt is the time of the moderation action
+// that the issue time of an access token is compared with
+type UserBlocklistEntry struct {
+	Status    UserStatus `json:"status"`
+	BlockedAt time.Time  `json:"blocked_at"`
+}
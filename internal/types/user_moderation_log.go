@@ -0,0 +1,41 @@
+package types
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/volatiletech/null/v9"
+)
+
+// region repo types
+
+type UserModerationLog struct {
+	ID            uuid.UUID            `db:"id"`
+	UserID        uuid.UUID            `db:"user_id"`
+	AdminUserID   uuid.NullUUID        `db:"admin_user_id"` // null when the action is done by the system
+	Action        UserModerationAction `db:"action"`
+	Reason        string               `db:"reason"`
+	SuspendedFrom null.Time            `db:"suspended_from"`
+	SuspendedTo   null.Time            `db:"suspended_to"`
+	CreatedAt     time.Time            `db:"created_at"`
+}
+
+type UserModerationAction string
+
+const (
+	UserModerationActionSuspend   UserModerationAction = "suspend"
+	UserModerationActionUnsuspend UserModerationAction = "unsuspend"
+	UserModerationActionBan       UserModerationAction = "ban"
+	UserModerationActionUnban     UserModerationAction = "unban"
+	// UserModerationActionRevokeSessions logs the user out of every device without blocking the account
+	UserModerationActionRevokeSessions UserModerationAction = "revoke_sessions"
+)
+
+type UserModerationLogWithAdmin struct {
+	UserModerationLog
+	AdminName null.String `db:"admin_name"`
+}
+
+const UserModerationReasonSuspensionEnded = "suspension period ended"
+
+// end of region repo types
@@ -0,0 +1,14 @@
+package types
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+const UserLastSeenKey = "user-last-seen"
+
+// GetUserLastSeenKey returns the key holding when the user closed their last websocket connection
+func GetUserLastSeenKey(userID uuid.UUID) string {
+	return fmt.Sprintf("%s:%s", UserLastSeenKey, userID)
+}
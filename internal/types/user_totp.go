@@ -0,0 +1,125 @@
+package types
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-errors/errors"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/google/uuid"
+	"github.com/volatiletech/null/v9"
+)
+
+const (
+	TOTPIssuer            = "Kelarin"
+	TOTPRecoveryCodeCount = 10
+
+	AuthTwoFactorAttemptsKey = "auth-two-factor-attempts"
+	// AuthTwoFactorMaxAttempts is counted per challenge, the challenge is revoked after the last attempt
+	AuthTwoFactorMaxAttempts = 5
+)
+
+var (
+	TwoFactorErrInvalidCode      = errors.New(AppErr{Code: http.StatusBadRequest, Message: "invalid two-factor code"})
+	TwoFactorErrNotEnabled       = errors.New(AppErr{Code: http.StatusBadRequest, Message: "two-factor authentication is not enabled"})
+	TwoFactorErrAlreadyEnabled   = errors.New(AppErr{Code: http.StatusConflict, Message: "two-factor authentication is already enabled"})
+	TwoFactorErrNotAvailable     = errors.New(AppErr{Code: http.StatusForbidden, Message: "two-factor authentication is not available for your role"})
+	TwoFactorErrRequired         = errors.New(AppErr{Code: http.StatusForbidden, Message: "two-factor authentication is required for your role"})
+	AuthErrInvalidTwoFactorToken = errors.New(AppErr{Code: http.StatusUnauthorized, Message: "invalid or expired two-factor challenge, please log in again"})
+	AuthErrTwoFactorAttempts     = errors.New(AppErr{Code: http.StatusTooManyRequests, Message: "too many invalid two-factor codes, please log in again"})
+)
+
+// GetAuthTwoFactorAttemptsKey counts the invalid codes submitted for the challenge
+func GetAuthTwoFactorAttemptsKey(challengeID string) string {
+	return fmt.Sprintf("%s:%s", AuthTwoFactorAttemptsKey, challengeID)
+}
+
+type AuthTwoFactorPolicy int16
+
+const (
+	AuthTwoFactorPolicyDisabled AuthTwoFactorPolicy = iota + 1
+	AuthTwoFactorPolicyOptional
+	AuthTwoFactorPolicyRequired
+)
+
+// AuthTwoFactorPolicies is the enforcement of each role, a role that is not listed can not enable two-factor authentication.
+// An admin without two-factor authentication has to enroll before the login completes
+var AuthTwoFactorPolicies = map[UserRole]AuthTwoFactorPolicy{
+	UserRoleConsumer:        AuthTwoFactorPolicyDisabled,
+	UserRoleServiceProvider: AuthTwoFactorPolicyOptional,
+	UserRoleAdmin:           AuthTwoFactorPolicyRequired,
+}
+
+func GetAuthTwoFactorPolicy(role UserRole) AuthTwoFactorPolicy {
+	if policy, ok := AuthTwoFactorPolicies[role]; ok {
+		return policy
+	}
+
+	return AuthTwoFactorPolicyDisabled
+}
+
+// region repo types
+
+// UserTOTP holds the secret encrypted with the totp encryption key, EnabledAt is null until the secret is confirmed with a code.
+// LastUsedStep is the time step of the last accepted code, a code of the same step or older is rejected
+type UserTOTP struct {
+	UserID       uuid.UUID `db:"user_id"`
+	Secret       string    `db:"secret"`
+	EnabledAt    null.Time `db:"enabled_at"`
+	LastUsedStep int64     `db:"last_used_step"`
+	CreatedAt    time.Time `db:"created_at"`
+}
+
+type UserTOTPRecoveryCode struct {
+	ID        uuid.UUID `db:"id"`
+	UserID    uuid.UUID `db:"user_id"`
+	CodeHash  string    `db:"code_hash"`
+	UsedAt    null.Time `db:"used_at"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+// end of region repo types
+
+// region service types
+
+type TwoFactorSetupReq struct {
+	AuthUser AuthUser `middleware:"user"`
+}
+
+func (r TwoFactorSetupReq) Validate() error {
+	if r.AuthUser.IsZero() {
+		return errors.New("AuthUser is required")
+	}
+
+	return nil
+}
+
+// TwoFactorSetupRes is shown once, URI is rendered as a qr-code and Secret is for the apps that can not scan it
+type TwoFactorSetupRes struct {
+	Secret string `json:"secret"`
+	URI    string `json:"uri"`
+}
+
+// TwoFactorCodeReq confirms the action with a code from the authenticator app or a recovery code
+type TwoFactorCodeReq struct {
+	AuthUser AuthUser `middleware:"user"`
+	Code     string   `json:"code"`
+}
+
+func (r TwoFactorCodeReq) Validate() error {
+	if r.AuthUser.IsZero() {
+		return errors.New("AuthUser is required")
+	}
+
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.Code, validation.Required, validation.Length(1, 20)),
+	)
+}
+
+// TwoFactorRecoveryCodesRes is shown once, only the hashes of the codes are stored
+type TwoFactorRecoveryCodesRes struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// end of region service types
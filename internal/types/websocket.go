@@ -3,22 +3,98 @@ package types
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"sync"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/gorilla/websocket"
 )
 
+const (
+	WsWriteWait      = 10 * time.Second
+	WsPongWait       = 60 * time.Second
+	WsPingPeriod     = WsPongWait * 9 / 10 // must be less than WsPongWait so the pong arrives before the read deadline
+	WsMaxMessageSize = 32 * 1024
+	WsSendBufferSize = 256
+	// WsSendBufferReserve is the space of the send buffer a replay leaves for its response and the messages pushed meanwhile
+	WsSendBufferReserve = 32
+	// WsUnsubscribeWait bounds how long an unregister waits for redis to confirm the unsubscribe of the user channel
+	WsUnsubscribeWait = 5 * time.Second
+)
+
 type WsClient struct {
-	sync.Mutex
 	Ctx      context.Context
 	AuthUser AuthUser `middleware:"user"`
 	Con      *websocket.Conn
+
+	send      chan []byte
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+func NewWsClient(ctx context.Context, con *websocket.Conn) *WsClient {
+	return &WsClient{
+		Ctx:  ctx,
+		Con:  con,
+		send: make(chan []byte, WsSendBufferSize),
+		done: make(chan struct{}),
+	}
+}
+
+// Send queues the message for the write pump of the client, a client that does not keep up with its messages is closed
+// instead of blocking the sender
+func (c *WsClient) Send(msg []byte) {
+	select {
+	case <-c.done:
+	case c.send <- msg:
+	default:
+		c.Close()
+	}
+}
+
+// Close stops the write pump after it writes the queued messages, the write pump closes the connection
+func (c *WsClient) Close() {
+	c.closeOnce.Do(func() {
+		close(c.done)
+	})
+}
+
+// FreeSendSpace returns how many messages can be queued before the client is closed for not keeping up
+func (c *WsClient) FreeSendSpace() int {
+	return cap(c.send) - len(c.send)
 }
 
-type WsHub struct {
-	Clients map[string]*WsClient
+func (c *WsClient) Outbound() <-chan []byte {
+	return c.send
 }
 
+func (c *WsClient) Done() <-chan struct{} {
+	return c.done
+}
+
+const WsUserChannelKey = "ws-user"
+
+// GetWsUserChannelKey returns the pub/sub channel every server subscribes to while the user has a connection on it
+func GetWsUserChannelKey(userID uuid.UUID) string {
+	return fmt.Sprintf("%s:%s", WsUserChannelKey, userID)
+}
+
+// WsRequest is the envelope of every message sent by the client, a message without a type is a chat message
+type WsRequest struct {
+	Type WsRequestType `json:"type"`
+}
+
+type WsRequestType string
+
+const (
+	WsRequestTypeChatSendMessage   WsRequestType = "send_message"
+	WsRequestTypeChatResume        WsRequestType = "resume"
+	WsRequestTypeChatTypingStarted WsRequestType = "typing_started"
+	WsRequestTypeChatTypingStopped WsRequestType = "typing_stopped"
+	WsRequestTypeChatPresence      WsRequestType = "presence"
+)
+
 type WsResponse struct {
 	Success  bool           `json:"success"`
 	Type     WsResponseType `json:"type"`
@@ -38,6 +114,14 @@ type WsResponseType string
 const (
 	WsResponseTypeServer              WsResponseType = "server"
 	WsResponseTypeChatIncomingMessage WsResponseType = "incoming_message"
+	WsResponseTypeChatResumed         WsResponseType = "resumed"
+	WsResponseTypeChatTypingStarted   WsResponseType = "typing_started"
+	WsResponseTypeChatTypingStopped   WsResponseType = "typing_stopped"
+	WsResponseTypeChatPresence        WsResponseType = "presence"
+	WsResponseTypeChatDelivered       WsResponseType = "message_delivered"
+	WsResponseTypeChatRead            WsResponseType = "message_read"
+
+	WsResponseTypeNotificationUnreadCount WsResponseType = "notification_unread_count"
 )
 
 type WsResponseCode int16
@@ -0,0 +1,21 @@
+package emailUtil
+
+import (
+	"context"
+	"kelarin/internal/config"
+	"kelarin/internal/types"
+)
+
+// Sender delivers an email right away, it is called by the worker so a slow mail server does not block a request
+type Sender interface {
+	Send(ctx context.Context, msg types.EmailMessage) error
+}
+
+func NewSender(cfg *config.Config) Sender {
+	switch cfg.Email.Driver {
+	case config.EmailDriverSMTP:
+		return newSMTPSender(cfg.Email)
+	default:
+		return newLogSender(cfg.Email)
+	}
+}
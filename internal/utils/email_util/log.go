@@ -0,0 +1,51 @@
+package emailUtil
+
+import (
+	"context"
+	"fmt"
+	"kelarin/internal/config"
+	"kelarin/internal/types"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/go-errors/errors"
+	"github.com/rs/zerolog/log"
+)
+
+// logSender stands in for the smtp server on local development, the whole message is written as an .eml file
+// to the log directory so it can be opened with a mail client
+type logSender struct {
+	cfg config.EmailConfig
+}
+
+func newLogSender(cfg config.EmailConfig) Sender {
+	return &logSender{cfg: cfg}
+}
+
+func (s *logSender) Send(ctx context.Context, msg types.EmailMessage) error {
+	logEvent := log.Info().Str("to", msg.To).Str("subject", msg.Subject)
+
+	if s.cfg.LogDir == "" {
+		logEvent.Str("text", msg.Text).Msg("email sent")
+		return nil
+	}
+
+	body, err := buildMIMEMessage(s.cfg.From, msg)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(s.cfg.LogDir, 0o755); err != nil {
+		return errors.New(err)
+	}
+
+	fileName := filepath.Join(s.cfg.LogDir, fmt.Sprintf("%d-%s.eml", time.Now().UnixNano(), msg.To))
+	if err := os.WriteFile(fileName, body, 0o644); err != nil {
+		return errors.New(err)
+	}
+
+	logEvent.Str("file", fileName).Msg("email sent")
+
+	return nil
+}
@@ -0,0 +1,89 @@
+package emailUtil
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"kelarin/internal/config"
+	"kelarin/internal/types"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"time"
+
+	"github.com/go-errors/errors"
+)
+
+type smtpSender struct {
+	cfg config.EmailConfig
+}
+
+func newSMTPSender(cfg config.EmailConfig) Sender {
+	return &smtpSender{cfg: cfg}
+}
+
+func (s *smtpSender) Send(ctx context.Context, msg types.EmailMessage) error {
+	from, err := mail.ParseAddress(s.cfg.From)
+	if err != nil {
+		return errors.New(err)
+	}
+
+	body, err := buildMIMEMessage(s.cfg.From, msg)
+	if err != nil {
+		return err
+	}
+
+	var auth smtp.Auth
+	if s.cfg.SMTP.Username != "" {
+		auth = smtp.PlainAuth("", s.cfg.SMTP.Username, s.cfg.SMTP.Password, s.cfg.SMTP.Host)
+	}
+
+	if err := smtp.SendMail(s.cfg.SMTP.Address(), auth, from.Address, []string{msg.To}, body); err != nil {
+		return errors.New(err)
+	}
+
+	return nil
+}
+
+// buildMIMEMessage puts the text and the html body in a multipart/alternative message, the mail client picks the one it can show
+func buildMIMEMessage(from string, msg types.EmailMessage) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	writer := multipart.NewWriter(buf)
+
+	fmt.Fprintf(buf, "From: %s\r\n", from)
+	fmt.Fprintf(buf, "To: %s\r\n", msg.To)
+	fmt.Fprintf(buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	fmt.Fprintf(buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(buf, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(buf, "Content-Type: multipart/alternative; boundary=%s\r\n\r\n", writer.Boundary())
+
+	parts := []struct {
+		contentType string
+		body        string
+	}{
+		{"text/plain; charset=utf-8", msg.Text},
+		{"text/html; charset=utf-8", msg.HTML},
+	}
+
+	for _, part := range parts {
+		w, err := writer.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"8bit"},
+		})
+		if err != nil {
+			return nil, errors.New(err)
+		}
+
+		if _, err := w.Write([]byte(part.body)); err != nil {
+			return nil, errors.New(err)
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, errors.New(err)
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,34 @@
+package emailUtil
+
+import (
+	"bytes"
+	"embed"
+	htmlTemplate "html/template"
+	"kelarin/internal/types"
+	textTemplate "text/template"
+
+	"github.com/go-errors/errors"
+)
+
+//go:embed templates
+var templateFS embed.FS
+
+var (
+	notificationHTMLTemplate = htmlTemplate.Must(htmlTemplate.ParseFS(templateFS, "templates/notification.html"))
+	notificationTextTemplate = textTemplate.Must(textTemplate.ParseFS(templateFS, "templates/notification.txt"))
+)
+
+// RenderNotification renders the html and the text body of a notification email
+func RenderNotification(data types.EmailNotificationTemplateData) (string, string, error) {
+	html := &bytes.Buffer{}
+	if err := notificationHTMLTemplate.Execute(html, data); err != nil {
+		return "", "", errors.New(err)
+	}
+
+	text := &bytes.Buffer{}
+	if err := notificationTextTemplate.Execute(text, data); err != nil {
+		return "", "", errors.New(err)
+	}
+
+	return html.String(), text.String(), nil
+}
@@ -0,0 +1,52 @@
+package imageUtil
+
+import (
+	"image"
+	"image/color"
+)
+
+// Thumbnail scales the image down so its longest side is maxSize, every pixel of the thumbnail is the average of the
+// pixels it covers in the source. An image that already fits is returned as it is
+func Thumbnail(src image.Image, maxSize int) image.Image {
+	bounds := src.Bounds()
+	srcWidth, srcHeight := bounds.Dx(), bounds.Dy()
+	if srcWidth <= maxSize && srcHeight <= maxSize {
+		return src
+	}
+
+	width, height := maxSize, srcHeight*maxSize/srcWidth
+	if srcHeight > srcWidth {
+		width, height = srcWidth*maxSize/srcHeight, maxSize
+	}
+
+	width, height = max(width, 1), max(height, 1)
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := range height {
+		y0 := bounds.Min.Y + y*srcHeight/height
+		y1 := max(bounds.Min.Y+(y+1)*srcHeight/height, y0+1)
+
+		for x := range width {
+			x0 := bounds.Min.X + x*srcWidth/width
+			x1 := max(bounds.Min.X+(x+1)*srcWidth/width, x0+1)
+
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					pr, pg, pb, pa := src.At(sx, sy).RGBA()
+					r, g, b, a = r+uint64(pr), g+uint64(pg), b+uint64(pb), a+uint64(pa)
+					n++
+				}
+			}
+
+			dst.Set(x, y, color.RGBA64{
+				R: uint16(r / n),
+				G: uint16(g / n),
+				B: uint16(b / n),
+				A: uint16(a / n),
+			})
+		}
+	}
+
+	return dst
+}
@@ -0,0 +1,101 @@
+package totpUtil
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+
+	"github.com/go-errors/errors"
+)
+
+const (
+	recoveryCodeAlphabet = "abcdefghjkmnpqrstuvwxyz23456789"
+	recoveryCodeLength   = 10
+)
+
+// Encrypt seals the secret with AES-GCM, the secret is stored encrypted because it is enough to generate valid codes
+func Encrypt(key []byte, secret string) (string, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", errors.New(err)
+	}
+
+	sealed := gcm.Seal(nonce, nonce, []byte(secret), nil)
+
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+func Decrypt(key []byte, encrypted string) (string, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(encrypted)
+	if err != nil {
+		return "", errors.New(err)
+	}
+
+	if len(sealed) < gcm.NonceSize() {
+		return "", errors.New("encrypted secret is too short")
+	}
+
+	nonce, ciphertext := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+	secret, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", errors.New(err)
+	}
+
+	return string(secret), nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, errors.New(err)
+	}
+
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, errors.New(err)
+	}
+
+	return gcm, nil
+}
+
+// GenerateRecoveryCode returns a code formatted as xxxxx-xxxxx, the alphabet leaves out the characters that look alike
+func GenerateRecoveryCode() (string, error) {
+	// a byte above the largest multiple of the alphabet length is skipped so every character is equally likely
+	limit := byte(256 - 256%len(recoveryCodeAlphabet))
+	random := make([]byte, 1)
+
+	code := make([]byte, 0, recoveryCodeLength)
+	for len(code) < recoveryCodeLength {
+		if _, err := rand.Read(random); err != nil {
+			return "", errors.New(err)
+		}
+
+		if random[0] < limit {
+			code = append(code, recoveryCodeAlphabet[int(random[0])%len(recoveryCodeAlphabet)])
+		}
+	}
+
+	return string(code[:recoveryCodeLength/2]) + "-" + string(code[recoveryCodeLength/2:]), nil
+}
+
+// HashRecoveryCode ignores the case and the separator of the code, a recovery code has enough entropy to be hashed without a salt
+func HashRecoveryCode(code string) string {
+	normalized := strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	sum := sha256.Sum256([]byte(normalized))
+
+	return hex.EncodeToString(sum[:])
+}
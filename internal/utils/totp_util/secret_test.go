@@ -0,0 +1,78 @@
+package totpUtil
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncryptDecrypt(t *testing.T) {
+	key := []byte("0123456789abcdef0123456789abcdef")
+	secret := "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+	encrypted, err := Encrypt(key, secret)
+	assert.NoError(t, err)
+	assert.NotContains(t, encrypted, secret)
+
+	t.Run("round trip", func(t *testing.T) {
+		decrypted, err := Decrypt(key, encrypted)
+
+		assert.NoError(t, err)
+		assert.Equal(t, secret, decrypted)
+	})
+
+	t.Run("random nonce", func(t *testing.T) {
+		other, err := Encrypt(key, secret)
+
+		assert.NoError(t, err)
+		assert.NotEqual(t, encrypted, other)
+	})
+
+	t.Run("wrong key", func(t *testing.T) {
+		_, err := Decrypt([]byte("fedcba9876543210fedcba9876543210"), encrypted)
+
+		assert.Error(t, err)
+	})
+
+	t.Run("tampered ciphertext", func(t *testing.T) {
+		sealed, err := base64.StdEncoding.DecodeString(encrypted)
+		assert.NoError(t, err)
+
+		sealed[len(sealed)-1] ^= 0x01
+
+		_, err = Decrypt(key, base64.StdEncoding.EncodeToString(sealed))
+		assert.Error(t, err)
+	})
+
+	t.Run("tampered nonce", func(t *testing.T) {
+		sealed, err := base64.StdEncoding.DecodeString(encrypted)
+		assert.NoError(t, err)
+
+		sealed[0] ^= 0x01
+
+		_, err = Decrypt(key, base64.StdEncoding.EncodeToString(sealed))
+		assert.Error(t, err)
+	})
+
+	t.Run("too short", func(t *testing.T) {
+		_, err := Decrypt(key, base64.StdEncoding.EncodeToString([]byte("short")))
+
+		assert.Error(t, err)
+	})
+
+	t.Run("invalid key size", func(t *testing.T) {
+		_, err := Encrypt([]byte("short"), secret)
+
+		assert.Error(t, err)
+	})
+}
+
+func TestHashRecoveryCode(t *testing.T) {
+	code, err := GenerateRecoveryCode()
+	assert.NoError(t, err)
+	assert.Len(t, code, recoveryCodeLength+1)
+
+	assert.Equal(t, HashRecoveryCode(code), HashRecoveryCode("  "+code[:5]+code[6:]+" "))
+	assert.NotEqual(t, HashRecoveryCode("abcde-fghjk"), HashRecoveryCode("abcde-fghjm"))
+}
@@ -0,0 +1,93 @@
+package totpUtil
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/go-errors/errors"
+)
+
+// the parameters are the defaults of the authenticator apps, some apps ignore the parameters in the uri
+const (
+	Digits     = 6
+	Period     = 30 * time.Second
+	secretSize = 20
+	// skew accepts the code of the previous and the next step, the clock of the phone may drift
+	skew = 1
+)
+
+var secretEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// GenerateSecret returns a random base32 secret, the format is the one the authenticator apps expect
+func GenerateSecret() (string, error) {
+	secret := make([]byte, secretSize)
+	if _, err := rand.Read(secret); err != nil {
+		return "", errors.New(err)
+	}
+
+	return secretEncoding.EncodeToString(secret), nil
+}
+
+// URI returns the otpauth uri shown as a qr-code by the client
+func URI(issuer, accountName, secret string) string {
+	query := url.Values{}
+	query.Set("secret", secret)
+	query.Set("issuer", issuer)
+	query.Set("algorithm", "SHA1")
+	query.Set("digits", fmt.Sprint(Digits))
+	query.Set("period", fmt.Sprint(int(Period.Seconds())))
+
+	label := url.PathEscape(fmt.Sprintf("%s:%s", issuer, accountName))
+
+	return fmt.Sprintf("otpauth://totp/%s?%s", label, query.Encode())
+}
+
+// Validate returns the time step of the code, the caller rejects a step that has been used to prevent the code from being replayed
+func Validate(code, secret string, t time.Time) (int64, bool, error) {
+	key, err := secretEncoding.DecodeString(strings.ToUpper(secret))
+	if err != nil {
+		return 0, false, errors.New(err)
+	}
+
+	code = strings.TrimSpace(code)
+	if len(code) != Digits {
+		return 0, false, nil
+	}
+
+	step := t.Unix() / int64(Period.Seconds())
+	for i := -skew; i <= skew; i++ {
+		if subtle.ConstantTimeCompare([]byte(generateCode(key, step+int64(i))), []byte(code)) == 1 {
+			return step + int64(i), true, nil
+		}
+	}
+
+	return 0, false, nil
+}
+
+// generateCode follows RFC 4226, the counter of TOTP is the time step
+func generateCode(key []byte, step int64) string {
+	counter := make([]byte, 8)
+	binary.BigEndian.PutUint64(counter, uint64(step))
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(counter)
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	mod := uint32(1)
+	for range Digits {
+		mod *= 10
+	}
+
+	return fmt.Sprintf("%0*d", Digits, value%mod)
+}
@@ -0,0 +1,126 @@
+package totpUtil
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// rfc6238Secret is the SHA1 secret of the test vectors of RFC 6238 appendix B
+var rfc6238Secret = []byte("12345678901234567890")
+
+func TestGenerateCode(t *testing.T) {
+	// the RFC uses 8 digits, a 6 digits code is the last 6 digits of the same truncated value
+	tests := []struct {
+		unix     int64
+		expected string
+	}{
+		{unix: 59, expected: "287082"},
+		{unix: 1111111109, expected: "081804"},
+		{unix: 1111111111, expected: "050471"},
+		{unix: 1234567890, expected: "005924"},
+		{unix: 2000000000, expected: "279037"},
+		{unix: 20000000000, expected: "353130"},
+	}
+
+	for _, tt := range tests {
+		t.Run(time.Unix(tt.unix, 0).UTC().Format(time.RFC3339), func(t *testing.T) {
+			assert.Equal(t, tt.expected, generateCode(rfc6238Secret, tt.unix/int64(Period.Seconds())))
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	secret := secretEncoding.EncodeToString(rfc6238Secret)
+	now := time.Unix(1111111111, 0)
+	step := now.Unix() / int64(Period.Seconds())
+
+	tests := []struct {
+		name         string
+		code         string
+		secret       string
+		expectedStep int64
+		expectedOk   bool
+	}{
+		{
+			name:         "code of the current step",
+			code:         "050471",
+			secret:       secret,
+			expectedStep: step,
+			expectedOk:   true,
+		},
+		{
+			name:         "code of the previous step",
+			code:         generateCode(rfc6238Secret, step-1),
+			secret:       secret,
+			expectedStep: step - 1,
+			expectedOk:   true,
+		},
+		{
+			name:         "code of the next step",
+			code:         generateCode(rfc6238Secret, step+1),
+			secret:       secret,
+			expectedStep: step + 1,
+			expectedOk:   true,
+		},
+		{
+			name:       "code outside of the window",
+			code:       generateCode(rfc6238Secret, step-2),
+			secret:     secret,
+			expectedOk: false,
+		},
+		{
+			name:       "code outside of the window in the future",
+			code:       generateCode(rfc6238Secret, step+2),
+			secret:     secret,
+			expectedOk: false,
+		},
+		{
+			name:         "surrounding spaces and lowercase secret",
+			code:         " 050471 ",
+			secret:       strings.ToLower(secret),
+			expectedStep: step,
+			expectedOk:   true,
+		},
+		{
+			name:       "wrong length",
+			code:       "94287082",
+			secret:     secret,
+			expectedOk: false,
+		},
+		{
+			name:       "wrong code",
+			code:       "000000",
+			secret:     secret,
+			expectedOk: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resStep, ok, err := Validate(tt.code, tt.secret, now)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedOk, ok)
+			assert.Equal(t, tt.expectedStep, resStep)
+		})
+	}
+
+	t.Run("invalid secret", func(t *testing.T) {
+		_, ok, err := Validate("050471", "not base32!", now)
+
+		assert.Error(t, err)
+		assert.False(t, ok)
+	})
+}
+
+func TestGenerateSecret(t *testing.T) {
+	secret, err := GenerateSecret()
+	assert.NoError(t, err)
+
+	key, err := secretEncoding.DecodeString(secret)
+	assert.NoError(t, err)
+	assert.Len(t, key, secretSize)
+}
@@ -0,0 +1,73 @@
+package websocket
+
+import (
+	"kelarin/internal/types"
+	"time"
+
+	"github.com/go-errors/errors"
+	"github.com/gorilla/websocket"
+	"github.com/rs/zerolog/log"
+)
+
+// Start sets the read limit and the read deadline of the connection and starts the write pump of the client.
+// The read deadline is extended by every pong, so a connection that stops answering the pings fails its next read
+func Start(client *types.WsClient) {
+	client.Con.SetReadLimit(types.WsMaxMessageSize)
+	client.Con.SetReadDeadline(time.Now().Add(types.WsPongWait))
+	client.Con.SetPongHandler(func(string) error {
+		return client.Con.SetReadDeadline(time.Now().Add(types.WsPongWait))
+	})
+
+	go writePump(client)
+}
+
+// writePump is the only writer of the connection, it writes the queued messages and pings the client until the client is closed
+func writePump(client *types.WsClient) {
+	ticker := time.NewTicker(types.WsPingPeriod)
+
+	defer func() {
+		ticker.Stop()
+		client.Close()
+		client.Con.Close()
+	}()
+
+	for {
+		select {
+		case msg := <-client.Outbound():
+			if err := write(client, websocket.BinaryMessage, msg); err != nil {
+				return
+			}
+		case <-ticker.C:
+			if err := write(client, websocket.PingMessage, nil); err != nil {
+				return
+			}
+		case <-client.Done():
+			// the error response sent right before the client is closed must still reach the client
+			for {
+				select {
+				case msg := <-client.Outbound():
+					if err := write(client, websocket.BinaryMessage, msg); err != nil {
+						return
+					}
+				default:
+					write(client, websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+					return
+				}
+			}
+		}
+	}
+}
+
+func write(client *types.WsClient, messageType int, data []byte) error {
+	client.Con.SetWriteDeadline(time.Now().Add(types.WsWriteWait))
+
+	if err := client.Con.WriteMessage(messageType, data); err != nil {
+		if !errors.Is(err, websocket.ErrCloseSent) {
+			log.Error().Stack().Err(errors.New(err)).Send()
+		}
+
+		return err
+	}
+
+	return nil
+}
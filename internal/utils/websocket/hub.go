@@ -0,0 +1,204 @@
+package websocket
+
+import (
+	"context"
+	"kelarin/internal/types"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-errors/errors"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog/log"
+)
+
+// Hub keeps the websocket connections of this server and delivers the messages published by any server to them.
+// A server subscribes to the channel of a user while the user has at least one connection on it, so a user can be
+// connected to several servers with several devices at once
+type Hub interface {
+	Register(client *types.WsClient) error
+	Unregister(client *types.WsClient) error
+	// Publish returns false when the user is not connected to any server
+	Publish(ctx context.Context, userID uuid.UUID, msg []byte) (bool, error)
+	IsOnline(ctx context.Context, userID uuid.UUID) (bool, error)
+	Close() error
+}
+
+type hubImpl struct {
+	mu      sync.RWMutex
+	redis   *redis.Client
+	pubSub  *redis.PubSub
+	clients map[uuid.UUID]map[*types.WsClient]struct{}
+	// unsubscribing holds the unregisters waiting for the unsubscribe of a channel to be confirmed, in the order they were sent
+	unsubscribing map[string][]chan struct{}
+}
+
+func NewWsHub(redis *redis.Client) Hub {
+	return &hubImpl{
+		redis:         redis,
+		clients:       make(map[uuid.UUID]map[*types.WsClient]struct{}),
+		unsubscribing: make(map[string][]chan struct{}),
+	}
+}
+
+func (h *hubImpl) Register(client *types.WsClient) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	userID := client.AuthUser.ID
+	if clients, ok := h.clients[userID]; ok {
+		clients[client] = struct{}{}
+		return nil
+	}
+
+	// the subscription is changed while holding the lock so a register and an unregister of the same user keep their order
+	channel := types.GetWsUserChannelKey(userID)
+	if h.pubSub == nil {
+		// the pub/sub connection is only opened by the server accepting connections, the worker and the cronjob only publish
+		h.pubSub = h.redis.Subscribe(context.Background(), channel)
+		go h.listen(h.pubSub.ChannelWithSubscriptions())
+	} else if err := h.pubSub.Subscribe(context.Background(), channel); err != nil {
+		return errors.New(err)
+	}
+
+	h.clients[userID] = map[*types.WsClient]struct{}{client: {}}
+
+	return nil
+}
+
+// Unregister can be called more than once for the same client. When the last connection of the user on this server is
+// unregistered it returns once redis confirmed the unsubscribe, so IsOnline no longer counts this server afterwards
+func (h *hubImpl) Unregister(client *types.WsClient) error {
+	confirmed, err := h.unregister(client)
+	if err != nil || confirmed == nil {
+		return err
+	}
+
+	select {
+	case <-confirmed:
+	case <-time.After(types.WsUnsubscribeWait):
+		return errors.Errorf("unsubscribe of user %s was not confirmed", client.AuthUser.ID)
+	}
+
+	return nil
+}
+
+// unregister returns a channel closed once the unsubscribe is confirmed, it is nil when the user still has a connection
+func (h *hubImpl) unregister(client *types.WsClient) (<-chan struct{}, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	userID := client.AuthUser.ID
+	clients, ok := h.clients[userID]
+	if !ok {
+		return nil, nil
+	}
+
+	delete(clients, client)
+	if len(clients) > 0 {
+		return nil, nil
+	}
+
+	delete(h.clients, userID)
+
+	channel := types.GetWsUserChannelKey(userID)
+	if err := h.pubSub.Unsubscribe(context.Background(), channel); err != nil {
+		return nil, errors.New(err)
+	}
+
+	confirmed := make(chan struct{})
+	h.unsubscribing[channel] = append(h.unsubscribing[channel], confirmed)
+
+	return confirmed, nil
+}
+
+func (h *hubImpl) Publish(ctx context.Context, userID uuid.UUID, msg []byte) (bool, error) {
+	receivers, err := h.redis.Publish(ctx, types.GetWsUserChannelKey(userID), msg).Result()
+	if err != nil {
+		return false, errors.New(err)
+	}
+
+	return receivers > 0, nil
+}
+
+func (h *hubImpl) IsOnline(ctx context.Context, userID uuid.UUID) (bool, error) {
+	channel := types.GetWsUserChannelKey(userID)
+
+	res, err := h.redis.PubSubNumSub(ctx, channel).Result()
+	if err != nil {
+		return false, errors.New(err)
+	}
+
+	return res[channel] > 0, nil
+}
+
+func (h *hubImpl) Close() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.pubSub == nil {
+		return nil
+	}
+
+	if err := h.pubSub.Close(); err != nil {
+		return errors.New(err)
+	}
+
+	return nil
+}
+
+// listen writes every published message to the connections of the user on this server and releases the unregisters
+// waiting for their unsubscribe, it stops when the hub is closed
+func (h *hubImpl) listen(messages <-chan interface{}) {
+	for m := range messages {
+		if subscription, ok := m.(*redis.Subscription); ok {
+			if subscription.Kind == "unsubscribe" {
+				h.confirmUnsubscribe(subscription.Channel)
+			}
+
+			continue
+		}
+
+		msg, ok := m.(*redis.Message)
+		if !ok {
+			continue
+		}
+
+		userID, err := uuid.Parse(strings.TrimPrefix(msg.Channel, types.WsUserChannelKey+":"))
+		if err != nil {
+			log.Error().Stack().Err(errors.New(err)).Send()
+			continue
+		}
+
+		h.mu.RLock()
+		clients := make([]*types.WsClient, 0, len(h.clients[userID]))
+		for client := range h.clients[userID] {
+			clients = append(clients, client)
+		}
+		h.mu.RUnlock()
+
+		for _, client := range clients {
+			client.Send([]byte(msg.Payload))
+		}
+	}
+}
+
+func (h *hubImpl) confirmUnsubscribe(channel string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	waiting := h.unsubscribing[channel]
+	if len(waiting) == 0 {
+		return
+	}
+
+	close(waiting[0])
+
+	if len(waiting) == 1 {
+		delete(h.unsubscribing, channel)
+		return
+	}
+
+	h.unsubscribing[channel] = waiting[1:]
+}
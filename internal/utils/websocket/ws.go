@@ -2,7 +2,6 @@ package websocket
 
 import (
 	"kelarin/internal/config"
-	"kelarin/internal/types"
 	"net/http"
 	"time"
 
@@ -20,9 +19,3 @@ func NewWsUpgrader(cfg *config.Config) *websocket.Upgrader {
 		},
 	}
 }
-
-func NewWsHub() *types.WsHub {
-	return &types.WsHub{
-		Clients: make(map[string]*types.WsClient),
-	}
-}
@@ -43,7 +43,7 @@ func (w jsonIndentWriter) Write(p []byte) (int, error) {
 }
 
 func NewWorkerLogger() *WorkerLogger {
-	workerDict := zerolog.Dict().Str("app", "ms-group-backend")
+	workerDict := zerolog.Dict().Str("app", "kelarin-backend")
 	logger := zerolog.New(jsonIndentWriter{Out: os.Stderr}).
 		With().
 		Timestamp().